@@ -0,0 +1,202 @@
+package apierrors
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"one-client-view-2025tht/app/i18n"
+	"one-client-view-2025tht/app/jsonapi"
+	"one-client-view-2025tht/app/logging"
+	"one-client-view-2025tht/app/requestid"
+)
+
+// Error codes returned in API error responses
+const (
+	CodeBadRequest       = "bad_request"
+	CodeUnauthorized     = "unauthorized"
+	CodeForbidden        = "forbidden"
+	CodeNotFound         = "not_found"
+	CodeMethodNotAllowed = "method_not_allowed"
+	CodeNotAcceptable    = "not_acceptable"
+	CodeConflict         = "conflict"
+	CodePayloadTooLarge  = "payload_too_large"
+	CodeUnsupportedMedia = "unsupported_media_type"
+	CodePrecondition     = "precondition_required"
+	CodeUnprocessable    = "unprocessable_entity"
+	CodeValidation       = "validation_failed"
+	CodeTooManyRequests  = "too_many_requests"
+	CodeInternal         = "internal_error"
+	CodeBadGateway       = "bad_gateway"
+	CodeUnavailable      = "service_unavailable"
+
+	// Requests or responses that depart from the OpenAPI document, reported
+	// only in development
+	CodeContractViolation = "contract_violation"
+)
+
+// APIError is the JSON body returned for all failed API requests
+// @Description Error response
+type APIError struct {
+	Status    int         `json:"-"`
+	Code      string      `json:"code" example:"not_found"`
+	Message   string      `json:"message" example:"Applicant not found"`
+	Details   interface{} `json:"details,omitempty"` // A string, a list of strings or an object of field errors
+	RequestID string      `json:"request_id,omitempty"`
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// New creates an error with the given HTTP status, code and message
+func New(status int, code, message string) *APIError {
+	return &APIError{Status: status, Code: code, Message: message}
+}
+
+// WithDetails returns a copy of the error with additional details attached
+func (e *APIError) WithDetails(details interface{}) *APIError {
+	copied := *e
+	copied.Details = details
+	return &copied
+}
+
+// BadRequest creates a 400 error
+func BadRequest(message string) *APIError {
+	return New(http.StatusBadRequest, CodeBadRequest, message)
+}
+
+// Unauthorized creates a 401 error
+func Unauthorized(message string) *APIError {
+	return New(http.StatusUnauthorized, CodeUnauthorized, message)
+}
+
+// Forbidden creates a 403 error
+func Forbidden(message string) *APIError {
+	return New(http.StatusForbidden, CodeForbidden, message)
+}
+
+// NotFound creates a 404 error
+func NotFound(message string) *APIError {
+	return New(http.StatusNotFound, CodeNotFound, message)
+}
+
+// NotAcceptable creates a 406 error
+func NotAcceptable(message string) *APIError {
+	return New(http.StatusNotAcceptable, CodeNotAcceptable, message)
+}
+
+// Conflict creates a 409 error
+func Conflict(message string) *APIError {
+	return New(http.StatusConflict, CodeConflict, message)
+}
+
+// PayloadTooLarge creates a 413 error
+func PayloadTooLarge(message string) *APIError {
+	return New(http.StatusRequestEntityTooLarge, CodePayloadTooLarge, message)
+}
+
+// PreconditionRequired creates a 428 error
+func PreconditionRequired(message string) *APIError {
+	return New(http.StatusPreconditionRequired, CodePrecondition, message)
+}
+
+// UnsupportedMediaType creates a 415 error
+func UnsupportedMediaType(message string) *APIError {
+	return New(http.StatusUnsupportedMediaType, CodeUnsupportedMedia, message)
+}
+
+// Unprocessable creates a 422 error
+func Unprocessable(message string) *APIError {
+	return New(http.StatusUnprocessableEntity, CodeUnprocessable, message)
+}
+
+// Validation creates a 422 error listing the invalid fields, keyed by field
+// name
+func Validation(fields map[string]string) *APIError {
+	e := New(http.StatusUnprocessableEntity, CodeValidation, "Validation failed")
+	e.Details = fields
+	return e
+}
+
+// TooManyRequests creates a 429 error
+func TooManyRequests(message string) *APIError {
+	return New(http.StatusTooManyRequests, CodeTooManyRequests, message)
+}
+
+// Internal creates a 500 error, including the underlying error as details
+func Internal(message string, err error) *APIError {
+	e := New(http.StatusInternalServerError, CodeInternal, message)
+	if err != nil {
+		e.Details = err.Error()
+	}
+	return e
+}
+
+// BadGateway creates a 502 error for a failed call to an external service,
+// including the underlying error as details
+func BadGateway(message string, err error) *APIError {
+	e := New(http.StatusBadGateway, CodeBadGateway, message)
+	if err != nil {
+		e.Details = err.Error()
+	}
+	return e
+}
+
+// ServiceUnavailable creates a 503 error
+func ServiceUnavailable(message string) *APIError {
+	return New(http.StatusServiceUnavailable, CodeUnavailable, message)
+}
+
+// Write writes the error to the response as JSON, or as a JSON:API error
+// document to clients that ask for one, tagging it with the request's ID.
+// Server errors are also logged with the request's logger, which records the
+// ID so they can be correlated with the access log. The message and any field errors are translated into the
+// language negotiated with the Accept-Language header.
+func Write(w http.ResponseWriter, r *http.Request, err *APIError) {
+	body := *err
+	if body.RequestID == "" {
+		body.RequestID = requestid.FromContext(r.Context())
+	}
+
+	if body.Status >= http.StatusInternalServerError {
+		logging.FromContext(r.Context()).Error(body.Message, "method", r.Method, "path", r.URL.Path, "status", body.Status, "details", body.Details)
+	}
+
+	lang := i18n.FromRequest(r)
+	body.Message = i18n.Message(lang, body.Message)
+	if fields, ok := body.Details.(map[string]string); ok {
+		body.Details = i18n.Messages(lang, fields)
+	}
+	w.Header().Set("Content-Language", lang)
+	w.Header().Add("Vary", "Accept-Language")
+
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if jsonapi.Accepts(r) {
+		meta := map[string]interface{}{"request_id": body.RequestID}
+		if body.Details != nil {
+			meta["details"] = body.Details
+		}
+		w.Header().Set("Content-Type", jsonapi.MediaType)
+		w.WriteHeader(body.Status)
+		json.NewEncoder(w).Encode(jsonapi.Errors(jsonapi.Error{
+			Status: strconv.Itoa(body.Status),
+			Code:   body.Code,
+			Title:  body.Message,
+			Meta:   meta,
+		}))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(body.Status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// NotFoundHandler responds with a JSON 404 for unmatched routes
+func NotFoundHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Write(w, r, NotFound("Resource not found"))
+	})
+}
@@ -0,0 +1,135 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Roles that can be assigned to users
+const (
+	RoleAdmin      = "admin"
+	RoleCaseworker = "caseworker"
+	RoleViewer     = "viewer" // Read-only, with data-minimized responses
+
+	// RoleApplicant is held by applicants signed in to the portal, who can
+	// only read their own records and withdraw their own applications. The
+	// token's subject is the applicant ID.
+	RoleApplicant = "applicant"
+)
+
+// Claims are the JWT claims issued to authenticated users
+type Claims struct {
+	Username string `json:"username"`
+	Role     string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// UserID returns the ID of the authenticated user
+func (c *Claims) UserID() string {
+	return c.Subject
+}
+
+// TokenManager issues and validates signed bearer tokens
+type TokenManager struct {
+	signingKey []byte
+	ttl        time.Duration
+}
+
+// NewTokenManager creates a token manager signing tokens with the given key.
+// Issued tokens expire after ttl.
+func NewTokenManager(signingKey []byte, ttl time.Duration) *TokenManager {
+	return &TokenManager{signingKey: signingKey, ttl: ttl}
+}
+
+// Issue creates a signed token for the given user
+func (m *TokenManager) Issue(userID, username, role string) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(m.ttl)
+
+	claims := Claims{
+		Username: username,
+		Role:     role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.signingKey)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("error signing token: %v", err)
+	}
+	return token, expiresAt, nil
+}
+
+// Parse validates a signed token and returns its claims
+func (m *TokenManager) Parse(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		return m.signingKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, err
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("token has no subject")
+	}
+	return claims, nil
+}
+
+// HashPassword returns a bcrypt hash of the password
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("error hashing password: %v", err)
+	}
+	return string(hash), nil
+}
+
+// CheckPassword reports whether the password matches the bcrypt hash
+func CheckPassword(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// dummyHash is a bcrypt hash of a random password, generated when first
+// needed, that no password is expected to match
+var dummyHash = sync.OnceValue(func() []byte {
+	secret := make([]byte, 32)
+	_, _ = rand.Read(secret)
+	hash, err := bcrypt.GenerateFromPassword(secret, bcrypt.DefaultCost)
+	if err != nil {
+		panic(fmt.Sprintf("error hashing dummy password: %v", err))
+	}
+	return hash
+})
+
+// CheckNoPassword takes as long as CheckPassword with a wrong password, for
+// when there is no hash to check against, such as for an unknown username,
+// so that response times do not reveal which accounts exist. It always
+// reports false.
+func CheckNoPassword(password string) bool {
+	_ = bcrypt.CompareHashAndPassword(dummyHash(), []byte(password))
+	return false
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the authenticated user's claims
+func NewContext(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, contextKey{}, claims)
+}
+
+// FromContext returns the authenticated user's claims, or nil if the request
+// is unauthenticated
+func FromContext(ctx context.Context) *Claims {
+	claims, _ := ctx.Value(contextKey{}).(*Claims)
+	return claims
+}
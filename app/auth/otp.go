@@ -0,0 +1,23 @@
+package auth
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+)
+
+// otpDigits is the length of one-time codes
+const otpDigits = 6
+
+// NewOTP returns a random numeric one-time code, with leading zeros kept
+func NewOTP() (string, error) {
+	limit := big.NewInt(1)
+	for i := 0; i < otpDigits; i++ {
+		limit.Mul(limit, big.NewInt(10))
+	}
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", fmt.Errorf("error generating code: %v", err)
+	}
+	return fmt.Sprintf("%0*d", otpDigits, n), nil
+}
@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidTrackingToken is returned when a tracking token is malformed or
+// its signature does not match
+var ErrInvalidTrackingToken = errors.New("invalid tracking token")
+
+// trackingMACSize is the number of bytes of the HMAC kept in a tracking
+// token, enough that tokens cannot be guessed
+const trackingMACSize = 16
+
+// TrackingTokens issues and verifies the tokens applicants use to check the
+// status of an application without an account. A token is the application's
+// ID followed by an HMAC-SHA256 of it, so it cannot be guessed or altered and
+// needs no storage; tokens remain valid until the signing key changes.
+type TrackingTokens struct {
+	key []byte
+}
+
+// NewTrackingTokens creates a token issuer signing with a key derived from
+// secret, so that the secret can be shared with other uses
+func NewTrackingTokens(secret []byte) *TrackingTokens {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("application-tracking"))
+	return &TrackingTokens{key: mac.Sum(nil)}
+}
+
+// Issue returns the tracking token of an application
+func (t *TrackingTokens) Issue(applicationID string) (string, error) {
+	id, err := uuid.Parse(applicationID)
+	if err != nil {
+		return "", err
+	}
+	token := append(id[:], t.sign(id[:])...)
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+// Verify checks a tracking token's signature and returns the ID of the
+// application it was issued for
+func (t *TrackingTokens) Verify(token string) (string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(data) != len(uuid.UUID{})+trackingMACSize {
+		return "", ErrInvalidTrackingToken
+	}
+	id, mac := data[:len(uuid.UUID{})], data[len(uuid.UUID{}):]
+	if !hmac.Equal(mac, t.sign(id)) {
+		return "", ErrInvalidTrackingToken
+	}
+	return uuid.UUID(id).String(), nil
+}
+
+// sign returns the truncated HMAC of an application ID
+func (t *TrackingTokens) sign(id []byte) []byte {
+	mac := hmac.New(sha256.New, t.key)
+	mac.Write(id)
+	return mac.Sum(nil)[:trackingMACSize]
+}
@@ -0,0 +1,489 @@
+// Package backup takes logical dumps of the database and restores them, so
+// deployments without a database administrator can snapshot their data, for
+// example before a bulk import or retention run.
+//
+// A backup is a zip archive holding manifest.json, which describes it, and
+// tables/{name}.json for each table, a JSON array of the table's rows as
+// objects keyed by column. All tables are read in one read-only transaction,
+// so the backup is consistent even while the server is serving requests.
+// The job queue and the migration bookkeeping are left out: restoring a
+// queue would run its jobs again, and the schema is recorded in the
+// manifest instead. Values are written as the database holds them, so
+// encrypted columns stay encrypted, and a backup can only be read with the
+// encryption keys in use when it was taken. Documents and photos are kept
+// in storage, not the database, and are not included.
+package backup
+
+import (
+	"archive/zip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"one-client-view-2025tht/app/database"
+	"one-client-view-2025tht/app/database/migrations"
+)
+
+// ContentType is the media type of backups
+const ContentType = "application/zip"
+
+// Format is the version of the archive layout written by Create
+const Format = 1
+
+const manifestName = "manifest.json"
+
+// excluded are the tables left out of backups
+var excluded = map[string]bool{
+	"schema_migrations": true,
+	"jobs":              true,
+}
+
+// Table describes a table in a backup
+type Table struct {
+	Name        string   `json:"name"`
+	Rows        int      `json:"rows"`
+	TimeColumns []string `json:"time_columns,omitempty"` // Columns holding times, written in RFC 3339
+}
+
+// Manifest describes a backup
+type Manifest struct {
+	Format    int       `json:"format"`
+	CreatedAt time.Time `json:"created_at"`
+	Driver    string    `json:"driver"` // Database driver the backup was taken from
+	Schema    string    `json:"schema"` // Version of the last migration applied, which restores must match
+	Tables    []Table   `json:"tables"`
+}
+
+// Create writes a backup of the database to w as a zip archive
+func Create(ctx context.Context, db *database.Database, w io.Writer) (*Manifest, error) {
+	opts := &sql.TxOptions{ReadOnly: true}
+	if db.Driver == database.DriverMySQL {
+		// Every table is read from the snapshot taken at the first read
+		opts.Isolation = sql.LevelRepeatableRead
+	}
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	manifest := &Manifest{Format: Format, CreatedAt: time.Now().UTC(), Driver: db.Driver}
+	if manifest.Schema, err = schemaVersion(tx); err != nil {
+		return nil, err
+	}
+	names, err := tableNames(tx, db.Driver)
+	if err != nil {
+		return nil, err
+	}
+
+	archive := zip.NewWriter(w)
+	for _, name := range names {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		entry, err := archive.Create("tables/" + name + ".json")
+		if err != nil {
+			return nil, err
+		}
+		table, err := dumpTable(tx, name, entry)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Tables = append(manifest.Tables, table)
+	}
+
+	entry, err := archive.Create(manifestName)
+	if err != nil {
+		return nil, err
+	}
+	enc := json.NewEncoder(entry)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(manifest); err != nil {
+		return nil, err
+	}
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("error writing backup: %v", err)
+	}
+	return manifest, nil
+}
+
+// dumpTable writes the rows of a table to w as a JSON array of objects
+func dumpTable(tx *sql.Tx, name string, w io.Writer) (Table, error) {
+	table := Table{Name: name}
+	rows, err := tx.Query(`SELECT * FROM ` + name)
+	if err != nil {
+		return table, fmt.Errorf("error querying %s: %v", name, err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return table, fmt.Errorf("error reading columns of %s: %v", name, err)
+	}
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	times := make([]bool, len(columns))
+
+	if _, err := io.WriteString(w, "["); err != nil {
+		return table, err
+	}
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return table, fmt.Errorf("error scanning %s row: %v", name, err)
+		}
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			switch v := values[i].(type) {
+			case []byte:
+				row[column] = string(v)
+			case time.Time:
+				times[i] = true
+				row[column] = v.UTC().Format(time.RFC3339Nano)
+			default:
+				row[column] = v
+			}
+		}
+		data, err := json.Marshal(row)
+		if err != nil {
+			return table, fmt.Errorf("error encoding %s row: %v", name, err)
+		}
+		separator := ",\n"
+		if table.Rows == 0 {
+			separator = "\n"
+		}
+		if _, err := io.WriteString(w, separator); err != nil {
+			return table, err
+		}
+		if _, err := w.Write(data); err != nil {
+			return table, err
+		}
+		table.Rows++
+	}
+	if err := rows.Err(); err != nil {
+		return table, fmt.Errorf("error iterating %s rows: %v", name, err)
+	}
+	if _, err := io.WriteString(w, "\n]\n"); err != nil {
+		return table, err
+	}
+
+	for i, column := range columns {
+		if times[i] {
+			table.TimeColumns = append(table.TimeColumns, column)
+		}
+	}
+	return table, nil
+}
+
+// Restore replaces the contents of the database with a backup, in one
+// transaction, so a failed restore leaves the database as it was. The
+// database must be migrated to the schema the backup was taken with. The
+// server should be stopped while it runs, as writes made during the restore
+// are lost, and the job queue is emptied. It fails if the restored rows name
+// a column their table does not have, or break a foreign key.
+func Restore(ctx context.Context, db *database.Database, archive *zip.Reader) (*Manifest, error) {
+	manifest, err := readManifest(archive)
+	if err != nil {
+		return nil, err
+	}
+
+	// Foreign keys are checked once every table is restored, so the tables
+	// can be emptied and filled in any order. Both settings apply to a
+	// connection.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if db.Driver == database.DriverMySQL {
+		if _, err := conn.ExecContext(ctx, `SET FOREIGN_KEY_CHECKS = 0`); err != nil {
+			return nil, fmt.Errorf("error disabling foreign key checks: %v", err)
+		}
+		defer conn.ExecContext(context.Background(), `SET FOREIGN_KEY_CHECKS = 1`)
+	}
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback()
+	if db.Driver == database.DriverSQLite {
+		if _, err := tx.Exec(`PRAGMA defer_foreign_keys = ON`); err != nil {
+			return nil, fmt.Errorf("error deferring foreign key checks: %v", err)
+		}
+	}
+
+	schema, err := schemaVersion(tx)
+	if err != nil {
+		return nil, err
+	}
+	if schema != manifest.Schema {
+		return nil, fmt.Errorf("the backup has schema %s but the database has %s; migrate the database to %s first", manifest.Schema, schema, manifest.Schema)
+	}
+	names, err := tableNames(tx, db.Driver)
+	if err != nil {
+		return nil, err
+	}
+	for _, table := range manifest.Tables {
+		if !slices.Contains(names, table.Name) {
+			return nil, fmt.Errorf("the database has no table %s", table.Name)
+		}
+	}
+
+	// The job queue is emptied too, as its jobs refer to the data replaced.
+	// Rows referring to others are deleted first, as restricting keys are
+	// checked immediately even when checks are deferred.
+	if _, err := tx.Exec(`DELETE FROM jobs`); err != nil {
+		return nil, fmt.Errorf("error emptying jobs: %v", err)
+	}
+	for i := len(names) - 1; i >= 0; i-- {
+		if _, err := tx.Exec(`DELETE FROM ` + names[i]); err != nil {
+			return nil, fmt.Errorf("error emptying %s: %v", names[i], err)
+		}
+	}
+	for _, table := range manifest.Tables {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		columns, err := tableColumns(tx, db.Driver, table.Name)
+		if err != nil {
+			return nil, err
+		}
+		entry, err := archive.Open("tables/" + table.Name + ".json")
+		if err != nil {
+			return nil, fmt.Errorf("error opening %s: %v", table.Name, err)
+		}
+		restored, err := restoreTable(tx, table, columns, entry)
+		entry.Close()
+		if err != nil {
+			return nil, err
+		}
+		if restored != table.Rows {
+			return nil, fmt.Errorf("the backup of %s has %d row(s) but the manifest lists %d", table.Name, restored, table.Rows)
+		}
+	}
+
+	orphans, err := migrations.CheckIntegrity(tx)
+	if err != nil {
+		return nil, err
+	}
+	if len(orphans) > 0 {
+		problems := make([]string, len(orphans))
+		for i, o := range orphans {
+			problems[i] = o.String()
+		}
+		return nil, fmt.Errorf("the backup breaks foreign keys: %s", strings.Join(problems, "; "))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error committing restore: %v", err)
+	}
+	return manifest, nil
+}
+
+// readManifest reads and checks the manifest of a backup
+func readManifest(archive *zip.Reader) (*Manifest, error) {
+	f, err := archive.Open(manifestName)
+	if err != nil {
+		return nil, errors.New("not a backup: it has no manifest.json")
+	}
+	defer f.Close()
+
+	var manifest Manifest
+	if err := json.NewDecoder(f).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("error reading manifest: %v", err)
+	}
+	if manifest.Format != Format {
+		return nil, fmt.Errorf("unsupported backup format %d", manifest.Format)
+	}
+	return &manifest, nil
+}
+
+// restoreTable inserts the rows of a table read from r, a JSON array of
+// objects, returning how many it inserted. The objects' keys name the columns
+// inserted, so each must be one of the table's columns.
+func restoreTable(tx *sql.Tx, table Table, known []string, r io.Reader) (int, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	if _, err := dec.Token(); err != nil {
+		return 0, fmt.Errorf("error reading %s: %v", table.Name, err)
+	}
+
+	statements := make(map[string]*sql.Stmt)
+	defer func() {
+		for _, stmt := range statements {
+			stmt.Close()
+		}
+	}()
+
+	restored := 0
+	for dec.More() {
+		var row map[string]interface{}
+		if err := dec.Decode(&row); err != nil {
+			return restored, fmt.Errorf("error reading %s row %d: %v", table.Name, restored+1, err)
+		}
+
+		columns := make([]string, 0, len(row))
+		for column := range row {
+			if !slices.Contains(known, column) {
+				return restored, fmt.Errorf("the backup of %s has column %q in row %d, which the table does not have", table.Name, column, restored+1)
+			}
+			columns = append(columns, column)
+		}
+		sort.Strings(columns)
+		args := make([]interface{}, len(columns))
+		for i, column := range columns {
+			value, err := columnValue(row[column], slices.Contains(table.TimeColumns, column))
+			if err != nil {
+				return restored, fmt.Errorf("invalid %s.%s in row %d: %v", table.Name, column, restored+1, err)
+			}
+			args[i] = value
+		}
+
+		key := strings.Join(columns, ",")
+		stmt, ok := statements[key]
+		if !ok {
+			placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+			var err error
+			stmt, err = tx.Prepare(`INSERT INTO ` + table.Name + ` (` + strings.Join(columns, ", ") + `) VALUES (` + placeholders + `)`)
+			if err != nil {
+				return restored, fmt.Errorf("error preparing insert into %s: %v", table.Name, err)
+			}
+			statements[key] = stmt
+		}
+		if _, err := stmt.Exec(args...); err != nil {
+			return restored, fmt.Errorf("error restoring %s row %d: %v", table.Name, restored+1, err)
+		}
+		restored++
+	}
+	if _, err := dec.Token(); err != nil {
+		return restored, fmt.Errorf("error reading %s: %v", table.Name, err)
+	}
+	return restored, nil
+}
+
+// columnValue converts a value decoded from a backup to the argument it was
+// scanned from
+func columnValue(value interface{}, isTime bool) (interface{}, error) {
+	switch v := value.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n, nil
+		}
+		return v.Float64()
+	case string:
+		if isTime {
+			return time.Parse(time.RFC3339Nano, v)
+		}
+		return v, nil
+	default:
+		return v, nil
+	}
+}
+
+// schemaVersion returns the version of the last migration applied
+func schemaVersion(tx *sql.Tx) (string, error) {
+	var version sql.NullString
+	if err := tx.QueryRow(`SELECT MAX(version) FROM schema_migrations`).Scan(&version); err != nil {
+		return "", fmt.Errorf("error reading schema version: %v", err)
+	}
+	if !version.Valid {
+		return "", errors.New("the database has no migrations applied")
+	}
+	return version.String, nil
+}
+
+// tableColumns returns the names of the columns of a table
+func tableColumns(tx *sql.Tx, driver, table string) ([]string, error) {
+	query := `SELECT column_name FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = ?`
+	if driver == database.DriverSQLite {
+		query = `SELECT name FROM pragma_table_info(?)`
+	}
+	rows, err := tx.Query(query, table)
+	if err != nil {
+		return nil, fmt.Errorf("error listing columns of %s: %v", table, err)
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, fmt.Errorf("error scanning column name: %v", err)
+		}
+		columns = append(columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating column names: %v", err)
+	}
+	return columns, nil
+}
+
+// tableNames returns the tables of the database that backups hold, with each
+// table after those its foreign keys refer to
+func tableNames(tx *sql.Tx, driver string) ([]string, error) {
+	query := `SELECT table_name FROM information_schema.tables WHERE table_schema = DATABASE() AND table_type = 'BASE TABLE'`
+	if driver == database.DriverSQLite {
+		query = `SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'`
+	}
+	rows, err := tx.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("error listing tables: %v", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("error scanning table name: %v", err)
+		}
+		if !excluded[name] {
+			names = append(names, name)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating table names: %v", err)
+	}
+	sort.Strings(names)
+	return dependencyOrder(names), nil
+}
+
+// dependencyOrder orders tables so each comes after the tables its foreign
+// keys refer to, and otherwise by name
+func dependencyOrder(names []string) []string {
+	parents := make(map[string][]string)
+	for _, fk := range migrations.ForeignKeys {
+		if fk.References != fk.Table {
+			parents[fk.Table] = append(parents[fk.Table], fk.References)
+		}
+	}
+
+	ordered := make([]string, 0, len(names))
+	placed := make(map[string]bool)
+	var place func(name string)
+	place = func(name string) {
+		if placed[name] {
+			return
+		}
+		placed[name] = true
+		for _, parent := range parents[name] {
+			if slices.Contains(names, parent) {
+				place(parent)
+			}
+		}
+		ordered = append(ordered, name)
+	}
+	for _, name := range names {
+		place(name)
+	}
+	return ordered
+}
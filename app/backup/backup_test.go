@@ -0,0 +1,100 @@
+package backup
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"one-client-view-2025tht/app/database"
+)
+
+func TestRestoreRejectsUnknownColumns(t *testing.T) {
+	db, err := database.Initialize(&database.Config{
+		Driver: database.DriverSQLite,
+		Path:   filepath.Join(t.TempDir(), "test.sqlite"),
+	})
+	if err != nil {
+		t.Fatalf("initializing database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.Exec(`INSERT INTO users (id, username, password_hash, role, created_at, updated_at)
+			  VALUES ('u1', 'admin', 'hash', 'admin', ?, ?)`, time.Now(), time.Now())
+	if err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := Create(context.Background(), db, &buf); err != nil {
+		t.Fatalf("creating backup: %v", err)
+	}
+
+	// The backup as taken restores, columns and all
+	untouched, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("reading backup: %v", err)
+	}
+	if _, err := Restore(context.Background(), db, untouched); err != nil {
+		t.Fatalf("restoring backup: %v", err)
+	}
+
+	// Rewrite the backup with a key that would be spliced into the insert
+	tampered := `[{"id": "u2", "username": "x", "password_hash": "h", "role": "admin", ` +
+		`"role) VALUES ('a', 'b', 'c', 'd'); DROP TABLE users; --": 1}]`
+	archive := rewriteEntry(t, buf.Bytes(), "tables/users.json", tampered)
+
+	_, err = Restore(context.Background(), db, archive)
+	if err == nil || !strings.Contains(err.Error(), "which the table does not have") {
+		t.Fatalf("restoring tampered backup: got %v, want an unknown column error", err)
+	}
+
+	var username string
+	if err := db.QueryRow(`SELECT username FROM users WHERE id = 'u1'`).Scan(&username); err != nil {
+		t.Fatalf("reading user after failed restore: %v", err)
+	}
+}
+
+// rewriteEntry returns a copy of the zip archive data with the entry name
+// replaced by content
+func rewriteEntry(t *testing.T, data []byte, name, content string) *zip.Reader {
+	t.Helper()
+	original, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("reading backup: %v", err)
+	}
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, f := range original.File {
+		out, err := w.Create(f.Name)
+		if err != nil {
+			t.Fatalf("writing %s: %v", f.Name, err)
+		}
+		if f.Name == name {
+			_, err = io.WriteString(out, content)
+		} else {
+			var in io.ReadCloser
+			if in, err = f.Open(); err == nil {
+				_, err = io.Copy(out, in)
+				in.Close()
+			}
+		}
+		if err != nil {
+			t.Fatalf("copying %s: %v", f.Name, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("closing backup: %v", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("reading rewritten backup: %v", err)
+	}
+	return archive
+}
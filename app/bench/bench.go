@@ -0,0 +1,152 @@
+// Package bench holds benchmarks of the eligibility engine and of the
+// repositories' hot paths, run against a seeded in-memory SQLite database,
+// and the sample data they are seeded with, which the loadgen command also
+// seeds servers with:
+//
+//	go test -bench . -benchmem ./app/bench -args -applicants 1000
+package bench
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"one-client-view-2025tht/app/database"
+	"one-client-view-2025tht/app/date"
+	"one-client-view-2025tht/app/encryption"
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/money"
+)
+
+// Fixture is a database seeded with sample applicants, schemes and
+// applications, and the repositories over it
+type Fixture struct {
+	DB           *database.Database
+	Applicants   *models.ApplicantRepository
+	Schemes      *models.SchemeRepository
+	Applications *models.ApplicationRepository
+
+	ApplicantIDs []string
+	SchemeIDs    []string
+	AsOf         time.Time // When eligibility is assessed
+}
+
+// NewFixture seeds an in-memory database with the given numbers of applicants
+// and schemes. Each applicant applies for the first scheme they are eligible
+// for, if any. The data is the same for the same numbers.
+func NewFixture(applicants, schemes int) (*Fixture, error) {
+	db, err := database.Initialize(&database.Config{Driver: database.DriverSQLite, Path: ":memory:"})
+	if err != nil {
+		return nil, err
+	}
+	// Encryption is part of the cost of reading applicants, so is included
+	cipher, err := encryption.New([]byte(strings.Repeat("b", 32)))
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	f := &Fixture{DB: db, AsOf: time.Now()}
+	f.Applicants = models.NewApplicantRepository(db.DB, cipher)
+	f.Schemes = models.NewSchemeRepository(db.DB)
+	f.Applications = models.NewApplicationRepository(db.DB, f.Applicants, f.Schemes)
+
+	if err := f.seed(applicants, schemes); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// Close closes the fixture's database
+func (f *Fixture) Close() error {
+	return f.DB.Close()
+}
+
+func (f *Fixture) seed(applicants, schemes int) error {
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < schemes; i++ {
+		scheme := SampleScheme(rng, i)
+		if err := f.Schemes.Create(&scheme); err != nil {
+			return fmt.Errorf("error seeding scheme %d: %v", i, err)
+		}
+		f.SchemeIDs = append(f.SchemeIDs, scheme.ID)
+	}
+
+	for i := 0; i < applicants; i++ {
+		applicant := SampleApplicant(rng, i)
+		if err := f.Applicants.Create(&applicant); err != nil {
+			return fmt.Errorf("error seeding applicant %d: %v", i, err)
+		}
+		f.ApplicantIDs = append(f.ApplicantIDs, applicant.ID)
+
+		eligible, err := models.EligibleSchemes(f.Schemes, &applicant, f.AsOf)
+		if err != nil {
+			return err
+		}
+		if len(eligible) > 0 {
+			application := models.Application{ApplicantID: applicant.ID, SchemeID: eligible[0].ID}
+			if err := f.Applications.Create(&application); err != nil {
+				return fmt.Errorf("error seeding application %d: %v", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// SampleApplicant returns a valid applicant with random details and up to
+// three household members. i numbers the applicant's name.
+func SampleApplicant(rng *rand.Rand, i int) models.Applicant {
+	employment := []string{"employed", "unemployed"}
+	sexes := []string{"male", "female"}
+	marital := []string{"single", "married", "widowed", "divorced"}
+	schoolLevels := []string{"preschool", "primary", "secondary", "tertiary", "none"}
+
+	a := models.Applicant{
+		Name:             fmt.Sprintf("Applicant %d", i),
+		EmploymentStatus: employment[rng.Intn(len(employment))],
+		Sex:              sexes[rng.Intn(len(sexes))],
+		DateOfBirth:      date.New(1950+rng.Intn(50), time.Month(1+rng.Intn(12)), 1+rng.Intn(28)),
+		MaritalStatus:    marital[rng.Intn(len(marital))],
+		MonthlyIncome:    float64(rng.Intn(60)) * 100,
+	}
+	for j, n := 0, rng.Intn(4); j < n; j++ {
+		a.Household = append(a.Household, models.HouseholdMember{
+			Name:             fmt.Sprintf("Member %d of applicant %d", j, i),
+			EmploymentStatus: employment[rng.Intn(len(employment))],
+			Sex:              sexes[rng.Intn(len(sexes))],
+			DateOfBirth:      date.New(2005+rng.Intn(20), time.Month(1+rng.Intn(12)), 1+rng.Intn(28)),
+			Relation:         models.Relations[rng.Intn(len(models.Relations))],
+			MonthlyIncome:    float64(rng.Intn(20)) * 100,
+			SchoolLevel:      schoolLevels[rng.Intn(len(schoolLevels))],
+		})
+	}
+	return a
+}
+
+// SampleScheme returns a valid, published and active scheme with random
+// criteria and a benefit. i numbers the scheme's name.
+func SampleScheme(rng *rand.Rand, i int) models.Scheme {
+	s := models.Scheme{
+		Name:        fmt.Sprintf("Scheme %d", i),
+		Description: "Sample scheme",
+		IsActive:    true,
+		Status:      models.SchemePublished,
+		Benefits:    []models.Benefit{{Name: "Grant", Amount: money.Amount(10000 * (1 + rng.Intn(10)))}},
+	}
+	switch rng.Intn(4) {
+	case 0:
+		s.Criteria.EmploymentStatus = "unemployed"
+	case 1:
+		s.Criteria.HasChildren.SchoolLevel = "primary"
+	case 2:
+		income := float64(1000 + 500*rng.Intn(6))
+		s.Criteria.MaxHouseholdIncome = &income
+	default:
+		minAge := 55
+		s.Criteria.AnyOf = []models.Criteria{{MaritalStatus: "widowed"}, {MinAge: &minAge}}
+	}
+	return s
+}
@@ -0,0 +1,140 @@
+package bench
+
+import (
+	"flag"
+	"sync"
+	"testing"
+
+	"one-client-view-2025tht/app/models"
+)
+
+var (
+	applicantCount = flag.Int("applicants", 500, "applicants to seed")
+	schemeCount    = flag.Int("schemes", 20, "schemes to seed")
+)
+
+var (
+	fixtureOnce sync.Once
+	fixture     *Fixture
+	fixtureErr  error
+)
+
+// sharedFixture returns the fixture the benchmarks share, seeding it the
+// first time it is needed so that plain test runs do not pay for it
+func sharedFixture(b *testing.B) *Fixture {
+	fixtureOnce.Do(func() {
+		fixture, fixtureErr = NewFixture(*applicantCount, *schemeCount)
+	})
+	if fixtureErr != nil {
+		b.Fatal(fixtureErr)
+	}
+	b.ReportAllocs()
+	return fixture
+}
+
+// schemeSnapshot is a SchemeStore over schemes read once, as the batch
+// eligibility check uses, so benchmarks measure the rules rather than the
+// database
+type schemeSnapshot struct {
+	schemes  []models.Scheme
+	versions []models.SchemeVersion
+}
+
+func (s schemeSnapshot) GetAll() ([]models.Scheme, error)                { return s.schemes, nil }
+func (s schemeSnapshot) GetAllVersions() ([]models.SchemeVersion, error) { return s.versions, nil }
+
+func (f *Fixture) snapshot(b *testing.B) schemeSnapshot {
+	schemes, err := f.Schemes.GetAll()
+	if err != nil {
+		b.Fatal(err)
+	}
+	versions, err := f.Schemes.GetAllVersions()
+	if err != nil {
+		b.Fatal(err)
+	}
+	return schemeSnapshot{schemes: schemes, versions: versions}
+}
+
+func (f *Fixture) applicants(b *testing.B) []models.Applicant {
+	applicants, err := f.Applicants.GetAll()
+	if err != nil {
+		b.Fatal(err)
+	}
+	if len(applicants) == 0 {
+		b.Skip("no applicants seeded")
+	}
+	return applicants
+}
+
+// BenchmarkEligibilityApplicant assesses one applicant against every scheme
+func BenchmarkEligibilityApplicant(b *testing.B) {
+	f := sharedFixture(b)
+	snapshot := f.snapshot(b)
+	applicants := f.applicants(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := models.EligibleSchemes(snapshot, &applicants[i%len(applicants)], f.AsOf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkEligibilityAllApplicants assesses every applicant, as a batch run
+// does
+func BenchmarkEligibilityAllApplicants(b *testing.B) {
+	f := sharedFixture(b)
+	snapshot := f.snapshot(b)
+	applicants := f.applicants(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range applicants {
+			if _, err := models.EligibleSchemes(snapshot, &applicants[j], f.AsOf); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// BenchmarkApplicantsGetAll lists every applicant with their households,
+// decrypted
+func BenchmarkApplicantsGetAll(b *testing.B) {
+	f := sharedFixture(b)
+	for i := 0; i < b.N; i++ {
+		if _, err := f.Applicants.GetAll(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkApplicantsGetByID(b *testing.B) {
+	f := sharedFixture(b)
+	if len(f.ApplicantIDs) == 0 {
+		b.Skip("no applicants seeded")
+	}
+	for i := 0; i < b.N; i++ {
+		if _, err := f.Applicants.GetByID(f.ApplicantIDs[i%len(f.ApplicantIDs)]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkApplicationsFind lists every application with its applicant and
+// scheme
+func BenchmarkApplicationsFind(b *testing.B) {
+	f := sharedFixture(b)
+	for i := 0; i < b.N; i++ {
+		if _, err := f.Applications.Find(models.ApplicationFilter{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkSchemesGetAll lists every scheme with its benefits
+func BenchmarkSchemesGetAll(b *testing.B) {
+	f := sharedFixture(b)
+	for i := 0; i < b.N; i++ {
+		if _, err := f.Schemes.GetAll(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// Package cache stores short-lived values so repeated reads can skip the
+// database.
+//
+// A Cache holds byte values under string keys, each with a time to live, and
+// counters that can be incremented atomically. Memory keeps them in the
+// process, which suits a single server; Redis shares them between replicas,
+// so an invalidation or count on one is seen by all. Noop stores nothing.
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Backends selectable in configuration
+const (
+	BackendNone   = "none"   // Noop: nothing is cached
+	BackendMemory = "memory" // Memory: held in each process
+	BackendRedis  = "redis"  // Redis: shared between replicas
+)
+
+// Open creates the cache for a backend. An empty backend is BackendNone.
+// redisURL is only used by BackendRedis.
+func Open(ctx context.Context, backend, redisURL string) (Cache, error) {
+	switch backend {
+	case "", BackendNone:
+		return Noop{}, nil
+	case BackendMemory:
+		return NewMemory(), nil
+	case BackendRedis:
+		return NewRedis(ctx, redisURL, keyPrefix)
+	default:
+		return nil, fmt.Errorf("unsupported cache backend: %q", backend)
+	}
+}
+
+// keyPrefix namespaces this application's keys in a shared Redis server
+const keyPrefix = "ocv:"
+
+// Cache stores values with a time to live
+type Cache interface {
+	// Get returns the value stored under key, and false if there is none or
+	// it has expired
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores value under key until ttl has passed
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes the values stored under the keys, if any
+	Delete(ctx context.Context, keys ...string) error
+	// Add stores value under key until ttl has passed, unless a value is
+	// already stored there, and reports whether it was stored
+	Add(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+	// Increment adds one to the counter under key and returns the new count.
+	// A new counter starts at zero and expires after ttl; incrementing it
+	// does not extend its life.
+	Increment(ctx context.Context, key string, ttl time.Duration) (int64, error)
+}
+
+// Noop is a Cache that stores nothing: every Get misses, every Add succeeds
+// and counters never pass one
+type Noop struct{}
+
+// Get always misses
+func (Noop) Get(ctx context.Context, key string) ([]byte, bool, error) { return nil, false, nil }
+
+// Set discards the value
+func (Noop) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error { return nil }
+
+// Delete does nothing
+func (Noop) Delete(ctx context.Context, keys ...string) error { return nil }
+
+// Add discards the value and reports it stored
+func (Noop) Add(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	return true, nil
+}
+
+// Increment always returns one
+func (Noop) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return 1, nil
+}
+
+// sweepInterval is how often Memory removes expired entries when values are
+// written
+const sweepInterval = time.Minute
+
+// Memory is a Cache held in the process. It is safe for concurrent use.
+type Memory struct {
+	mu        sync.Mutex
+	entries   map[string]memoryEntry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type memoryEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+// NewMemory creates an empty in-process cache
+func NewMemory() *Memory {
+	return &Memory{entries: make(map[string]memoryEntry), now: time.Now}
+}
+
+// Get returns the value stored under key
+func (m *Memory) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if !m.now().Before(entry.expires) {
+		delete(m.entries, key)
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+// Set stores a copy of value under key until ttl has passed
+func (m *Memory) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.sweep()
+	m.entries[key] = memoryEntry{value: append([]byte(nil), value...), expires: now.Add(ttl)}
+	return nil
+}
+
+// Add stores a copy of value under key unless an unexpired value is stored
+func (m *Memory) Add(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.sweep()
+	if entry, ok := m.entries[key]; ok && now.Before(entry.expires) {
+		return false, nil
+	}
+	m.entries[key] = memoryEntry{value: append([]byte(nil), value...), expires: now.Add(ttl)}
+	return true, nil
+}
+
+// Increment adds one to the counter under key
+func (m *Memory) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.sweep()
+	entry, ok := m.entries[key]
+	if !ok || !now.Before(entry.expires) {
+		entry = memoryEntry{value: []byte("0"), expires: now.Add(ttl)}
+	}
+	n, err := strconv.ParseInt(string(entry.value), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("value under %s is not a counter", key)
+	}
+	n++
+	entry.value = []byte(strconv.FormatInt(n, 10))
+	m.entries[key] = entry
+	return n, nil
+}
+
+// sweep removes expired entries, at most once per sweepInterval, and returns
+// the current time. The caller must hold the lock.
+func (m *Memory) sweep() time.Time {
+	now := m.now()
+	if now.Sub(m.lastSweep) >= sweepInterval {
+		for k, entry := range m.entries {
+			if !now.Before(entry.expires) {
+				delete(m.entries, k)
+			}
+		}
+		m.lastSweep = now
+	}
+	return now
+}
+
+// Delete removes the values stored under the keys
+func (m *Memory) Delete(ctx context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range keys {
+		delete(m.entries, key)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis is a Cache stored in a Redis server, shared by every replica
+// connected to it. Keys are namespaced with a prefix so the server can be
+// shared with other applications.
+type Redis struct {
+	Client *redis.Client
+	Prefix string
+}
+
+// NewRedis connects to the Redis server at url, in the form
+// redis://[user:password@]host:port[/db], and checks it is reachable
+func NewRedis(ctx context.Context, url, prefix string) (*Redis, error) {
+	options, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Redis URL: %v", err)
+	}
+
+	client := redis.NewClient(options)
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("error connecting to Redis: %v", err)
+	}
+	return &Redis{Client: client, Prefix: prefix}, nil
+}
+
+// Get returns the value stored under key
+func (r *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := r.Client.Get(ctx, r.Prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("error reading from Redis: %v", err)
+	}
+	return value, true, nil
+}
+
+// Set stores value under key until ttl has passed
+func (r *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := r.Client.Set(ctx, r.Prefix+key, value, ttl).Err(); err != nil {
+		return fmt.Errorf("error writing to Redis: %v", err)
+	}
+	return nil
+}
+
+// Delete removes the values stored under the keys
+func (r *Redis) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = r.Prefix + key
+	}
+	if err := r.Client.Del(ctx, prefixed...).Err(); err != nil {
+		return fmt.Errorf("error deleting from Redis: %v", err)
+	}
+	return nil
+}
+
+// Add stores value under key unless a value is already stored there
+func (r *Redis) Add(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	stored, err := r.Client.SetNX(ctx, r.Prefix+key, value, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("error writing to Redis: %v", err)
+	}
+	return stored, nil
+}
+
+// Increment adds one to the counter under key, setting its expiry when it is
+// created
+func (r *Redis) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	n, err := r.Client.Incr(ctx, r.Prefix+key).Result()
+	if err != nil {
+		return 0, fmt.Errorf("error incrementing in Redis: %v", err)
+	}
+	if n == 1 {
+		if err := r.Client.PExpire(ctx, r.Prefix+key, ttl).Err(); err != nil {
+			return n, fmt.Errorf("error setting expiry in Redis: %v", err)
+		}
+	}
+	return n, nil
+}
+
+// Close closes the connection pool
+func (r *Redis) Close() error {
+	return r.Client.Close()
+}
@@ -0,0 +1,306 @@
+// Command loadgen measures the API's performance. By default it seeds a
+// running server with sample applicants and schemes through the API, then
+// fires concurrent requests at the list and eligibility endpoints for a while
+// and reports their latencies. The in-process benchmarks are in the bench
+// package, run with go test -bench.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"one-client-view-2025tht/app/bench"
+)
+
+func main() {
+	if err := runLoad(os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// runLoad seeds a running server and fires requests at it
+func runLoad(args []string) error {
+	flags := flag.NewFlagSet("loadgen", flag.ExitOnError)
+	baseURL := flags.String("url", "http://localhost:8080", "base URL of the server")
+	username := flags.String("username", "admin", "user to log in as; must be allowed to create applicants and schemes")
+	password := flags.String("password", "admin123", "password of the user")
+	applicants := flags.Int("applicants", 100, "applicants to seed; 0 uses those already on the server")
+	schemes := flags.Int("schemes", 10, "schemes to seed")
+	concurrency := flags.Int("concurrency", 10, "concurrent clients")
+	duration := flags.Duration("duration", 30*time.Second, "how long to fire requests for")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage:\n  loadgen [flags]   seed a server and measure its latencies\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	c := &client{baseURL: strings.TrimRight(*baseURL, "/"), http: &http.Client{Timeout: 30 * time.Second}}
+	if err := c.login(*username, *password); err != nil {
+		return err
+	}
+
+	log.Printf("Seeding %d applicants and %d schemes...", *applicants, *schemes)
+	applicantIDs, err := c.seed(*applicants, *schemes, *concurrency)
+	if err != nil {
+		return err
+	}
+	if len(applicantIDs) == 0 {
+		if applicantIDs, err = c.applicantIDs(); err != nil {
+			return err
+		}
+	}
+	if len(applicantIDs) == 0 {
+		return errors.New("no applicants to request; seed some with -applicants")
+	}
+
+	log.Printf("Firing requests from %d clients for %s...", *concurrency, *duration)
+	stats := c.fire(applicantIDs, *concurrency, *duration)
+	stats.write(os.Stdout, *duration)
+	return nil
+}
+
+// client makes authenticated requests to the API
+type client struct {
+	baseURL string
+	http    *http.Client
+	token   string
+}
+
+// do sends a request with an optional JSON body and decodes a 2xx response
+// into out, if it is not nil
+func (c *client) do(method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, bytes.TrimSpace(data))
+	}
+	if out == nil {
+		_, err = io.Copy(io.Discard, resp.Body)
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (c *client) login(username, password string) error {
+	var resp struct {
+		Token string `json:"token"`
+	}
+	if err := c.do("POST", "/api/v1/auth/login", map[string]string{"username": username, "password": password}, &resp); err != nil {
+		return fmt.Errorf("error logging in: %v", err)
+	}
+	c.token = resp.Token
+	return nil
+}
+
+// seed creates sample schemes and applicants, returning the applicants' IDs
+func (c *client) seed(applicants, schemes, concurrency int) ([]string, error) {
+	// Different data on every run, so identity numbers and names do not
+	// collide with earlier runs
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	for i := 0; i < schemes; i++ {
+		scheme := bench.SampleScheme(rng, i)
+		if err := c.do("POST", "/api/v1/schemes", scheme, nil); err != nil {
+			return nil, fmt.Errorf("error seeding scheme %d: %v", i, err)
+		}
+	}
+
+	samples := make(chan int)
+	var mu sync.Mutex
+	var ids []string
+	var firstErr error
+	var wg sync.WaitGroup
+	rngs := make([]*rand.Rand, concurrency)
+	for w := range rngs {
+		rngs[w] = rand.New(rand.NewSource(rng.Int63()))
+	}
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func(rng *rand.Rand) {
+			defer wg.Done()
+			for i := range samples {
+				applicant := bench.SampleApplicant(rng, i)
+				var created struct {
+					ID string `json:"id"`
+				}
+				err := c.do("POST", "/api/v1/applicants", applicant, &created)
+
+				mu.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = fmt.Errorf("error seeding applicant %d: %v", i, err)
+				} else if err == nil {
+					ids = append(ids, created.ID)
+				}
+				mu.Unlock()
+			}
+		}(rngs[w])
+	}
+	for i := 0; i < applicants; i++ {
+		mu.Lock()
+		failed := firstErr != nil
+		mu.Unlock()
+		if failed {
+			break
+		}
+		samples <- i
+	}
+	close(samples)
+	wg.Wait()
+	return ids, firstErr
+}
+
+// applicantIDs returns the IDs of the applicants already on the server
+func (c *client) applicantIDs() ([]string, error) {
+	var applicants []struct {
+		ID string `json:"id"`
+	}
+	if err := c.do("GET", "/api/v1/applicants", nil, &applicants); err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(applicants))
+	for i, a := range applicants {
+		ids[i] = a.ID
+	}
+	return ids, nil
+}
+
+// endpoint is a kind of request fired during the run. {id} in path is
+// replaced with a random applicant ID.
+type endpoint struct {
+	name string
+	path string
+}
+
+var endpoints = []endpoint{
+	{name: "list applicants", path: "/api/v1/applicants"},
+	{name: "list applications", path: "/api/v1/applications"},
+	{name: "list schemes", path: "/api/v1/schemes"},
+	{name: "get applicant", path: "/api/v1/applicants/{id}"},
+	{name: "eligible schemes", path: "/api/v1/schemes/eligible?applicant={id}"},
+	{name: "applicant profile", path: "/api/v1/applicants/{id}/profile"},
+}
+
+// fire sends requests to random endpoints from concurrency clients until the
+// duration has passed
+func (c *client) fire(applicantIDs []string, concurrency int, duration time.Duration) *loadStats {
+	ctx, cancel := context.WithTimeout(context.Background(), duration)
+	defer cancel()
+
+	stats := &loadStats{byEndpoint: make(map[string]*endpointStats)}
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(seed))
+			for ctx.Err() == nil {
+				e := endpoints[rng.Intn(len(endpoints))]
+				path := strings.ReplaceAll(e.path, "{id}", applicantIDs[rng.Intn(len(applicantIDs))])
+
+				start := time.Now()
+				err := c.do("GET", path, nil, nil)
+				if ctx.Err() != nil {
+					// Cut short by the end of the run
+					return
+				}
+				stats.record(e.name, time.Since(start), err)
+			}
+		}(int64(w))
+	}
+	wg.Wait()
+	return stats
+}
+
+// loadStats collects the outcomes of the requests of a run
+type loadStats struct {
+	mu         sync.Mutex
+	byEndpoint map[string]*endpointStats
+}
+
+type endpointStats struct {
+	latencies []time.Duration
+	errors    int
+	lastError error
+}
+
+func (s *loadStats) record(name string, latency time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := s.byEndpoint[name]
+	if e == nil {
+		e = &endpointStats{}
+		s.byEndpoint[name] = e
+	}
+	e.latencies = append(e.latencies, latency)
+	if err != nil {
+		e.errors++
+		e.lastError = err
+	}
+}
+
+// write reports each endpoint's throughput, errors and latency percentiles
+func (s *loadStats) write(w io.Writer, duration time.Duration) {
+	names := make([]string, 0, len(s.byEndpoint))
+	for name := range s.byEndpoint {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(w, "%-20s %8s %8s %8s %10s %10s %10s %10s\n", "endpoint", "requests", "req/s", "errors", "p50", "p95", "p99", "max")
+	for _, name := range names {
+		e := s.byEndpoint[name]
+		sort.Slice(e.latencies, func(i, j int) bool { return e.latencies[i] < e.latencies[j] })
+		fmt.Fprintf(w, "%-20s %8d %8.1f %8d %10s %10s %10s %10s\n", name, len(e.latencies),
+			float64(len(e.latencies))/duration.Seconds(), e.errors,
+			percentile(e.latencies, 0.50), percentile(e.latencies, 0.95), percentile(e.latencies, 0.99),
+			e.latencies[len(e.latencies)-1].Round(time.Microsecond))
+	}
+	for _, name := range names {
+		if e := s.byEndpoint[name]; e.lastError != nil {
+			fmt.Fprintf(w, "last error of %s: %v\n", name, e.lastError)
+		}
+	}
+}
+
+// percentile returns the latency below which the fraction p of the sorted
+// latencies fall
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	i := int(p * float64(len(sorted)-1))
+	return sorted[i].Round(time.Microsecond)
+}
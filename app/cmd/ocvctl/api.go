@@ -0,0 +1,207 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"one-client-view-2025tht/app/models"
+)
+
+// client makes authenticated requests to the API
+type client struct {
+	baseURL string
+	http    *http.Client
+	token   string
+}
+
+// apiFlags adds the flags API commands connect and sign in with, returning a
+// function creating the signed-in client once the flags are parsed
+func apiFlags(flags *flag.FlagSet) func() (*client, error) {
+	baseURL := flags.String("url", envOr("OCV_URL", "http://localhost:8080"), "base URL of the server")
+	token := flags.String("token", os.Getenv("OCV_TOKEN"), "bearer token to use instead of signing in")
+	username := flags.String("username", os.Getenv("OCV_USERNAME"), "user to sign in as")
+	password := flags.String("password", os.Getenv("OCV_PASSWORD"), "password of the user; prefer $OCV_PASSWORD")
+
+	return func() (*client, error) {
+		c := &client{
+			baseURL: strings.TrimRight(*baseURL, "/"),
+			http:    &http.Client{Timeout: 5 * time.Minute},
+			token:   *token,
+		}
+		if c.token != "" {
+			return c, nil
+		}
+		if *username == "" || *password == "" {
+			return nil, errors.New("sign in with -token, or -username and -password")
+		}
+		return c, c.login(*username, *password)
+	}
+}
+
+// envOr returns the environment variable, or fallback if it is unset
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// request sends a request with an optional JSON body, returning the response
+// if it is a 2xx and an error with the API's message otherwise
+func (c *client) request(method, path string, body interface{}) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Message != "" {
+			return nil, fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, apiErr.Message)
+		}
+		return nil, fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, bytes.TrimSpace(data))
+	}
+	return resp, nil
+}
+
+// do sends a request and decodes the response into out, if it is not nil
+func (c *client) do(method, path string, body, out interface{}) error {
+	resp, err := c.request(method, path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if out == nil {
+		_, err = io.Copy(io.Discard, resp.Body)
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (c *client) login(username, password string) error {
+	var resp struct {
+		Token string `json:"token"`
+	}
+	if err := c.do("POST", "/api/v1/auth/login", map[string]string{"username": username, "password": password}, &resp); err != nil {
+		return fmt.Errorf("error signing in: %v", err)
+	}
+	c.token = resp.Token
+	return nil
+}
+
+// eligibilityBatch queues a batch eligibility check of the given applicants,
+// or every applicant, assessed as of now
+func eligibilityBatch(args []string) error {
+	flags := newFlags("eligibility batch")
+	connect := apiFlags(flags)
+	applicants := flags.String("applicants", "", "comma-separated IDs of the applicants to check; every applicant if empty")
+	wait := flags.Bool("wait", false, "wait for the check to finish and print its result")
+	flags.Parse(args)
+
+	c, err := connect()
+	if err != nil {
+		return err
+	}
+
+	var req models.BatchEligibilityRequest
+	if *applicants != "" {
+		req.ApplicantIDs = strings.Split(*applicants, ",")
+	}
+	var job models.Job
+	if err := c.do("POST", "/api/v1/schemes/eligible/batch", req, &job); err != nil {
+		return err
+	}
+	fmt.Printf("Queued job %s\n", job.ID)
+	if !*wait {
+		return nil
+	}
+
+	return waitForJob(func() (*models.Job, error) {
+		var j models.Job
+		return &j, c.do("GET", "/api/v1/jobs/"+url.PathEscape(job.ID), nil, &j)
+	})
+}
+
+// exportApplications downloads applications matching the filters as CSV or
+// Excel, to a file or standard output
+func exportApplications(args []string) error {
+	flags := newFlags("export applications")
+	connect := apiFlags(flags)
+	format := flags.String("format", "csv", "csv or xlsx")
+	output := flags.String("o", "", "file to write; standard output if empty, for csv only")
+	status := flags.String("status", "", "only applications with this status: pending, approved or rejected")
+	schemeID := flags.String("scheme", "", "only applications for this scheme ID")
+	after := flags.String("after", "", "only applications made on or after this date (YYYY-MM-DD)")
+	before := flags.String("before", "", "only applications made before this date (YYYY-MM-DD)")
+	flags.Parse(args)
+
+	if *format == "xlsx" && *output == "" {
+		return errors.New("-o is required for xlsx")
+	}
+	c, err := connect()
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{"format": {*format}}
+	for key, value := range map[string]string{"status": *status, "scheme_id": *schemeID, "applied_after": *after, "applied_before": *before} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	resp, err := c.request("GET", "/api/v1/applications/export?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var out io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	written, err := io.Copy(out, resp.Body)
+	if err != nil {
+		return fmt.Errorf("error writing export: %v", err)
+	}
+	if *output != "" {
+		fmt.Fprintf(os.Stderr, "Wrote %d bytes to %s\n", written, *output)
+	}
+	return nil
+}
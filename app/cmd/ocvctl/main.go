@@ -0,0 +1,395 @@
+// Command ocvctl runs operational tasks against a deployment, so operators
+// need not hand-craft SQL or HTTP calls.
+//
+// Commands that manage the deployment itself, such as creating the first
+// admin user, applying migrations or rotating the encryption key, work on the
+// database directly, reading the same configuration as the server: the file
+// named by -config or CONFIG_FILE, .env and the environment. Commands that
+// work with its data, such as batch eligibility checks and exports, go
+// through the API at -url as a signed-in user, so they are authorized and
+// audited like any other client.
+package main
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"one-client-view-2025tht/app/auth"
+	"one-client-view-2025tht/app/backup"
+	"one-client-view-2025tht/app/config"
+	"one-client-view-2025tht/app/database"
+	"one-client-view-2025tht/app/database/migrations"
+	"one-client-view-2025tht/app/encryption"
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/storage"
+)
+
+const usage = `Usage:
+  ocvctl user create -username NAME [-role admin] [-email ADDRESS]   create a user, e.g. the first admin
+  ocvctl migrate [up|status]                                         apply or list database migrations
+  ocvctl keys rotate                                                 re-encrypt personal data with the current key
+  ocvctl backup restore (-key KEY | FILE) -yes                       replace the database's data with a backup
+  ocvctl eligibility review [-wait]                                  queue a review of active applications' eligibility
+  ocvctl eligibility batch [-applicants ID,...] [-wait]              check the schemes applicants are eligible for
+  ocvctl export applications [-format csv|xlsx] [-o FILE] [filters]  download applications
+
+Database commands take -config FILE (default $CONFIG_FILE). API commands take
+-url (default $OCV_URL or http://localhost:8080) and sign in with -token
+(default $OCV_TOKEN) or -username and -password (default $OCV_USERNAME and
+$OCV_PASSWORD). Run a command with -h for its flags.
+`
+
+// commands are the handlers of each command, keyed by its name and
+// subcommand
+var commands = map[string]func(args []string) error{
+	"user create":         userCreate,
+	"migrate":             migrate,
+	"keys rotate":         keysRotate,
+	"backup restore":      backupRestore,
+	"eligibility review":  eligibilityReview,
+	"eligibility batch":   eligibilityBatch,
+	"export applications": exportApplications,
+}
+
+func main() {
+	// A missing .env is fine; the environment may hold the settings
+	_ = godotenv.Load()
+
+	args := os.Args[1:]
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "help" {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	name, rest := args[0], args[1:]
+	if len(rest) > 0 {
+		if _, ok := commands[name+" "+rest[0]]; ok {
+			name, rest = name+" "+rest[0], rest[1:]
+		}
+	}
+	command, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "ocvctl: unknown command %q\n\n%s", name, usage)
+		os.Exit(2)
+	}
+	if err := command(rest); err != nil {
+		fmt.Fprintf(os.Stderr, "ocvctl %s: %v\n", name, err)
+		os.Exit(1)
+	}
+}
+
+// newFlags creates the flag set of a command
+func newFlags(name string) *flag.FlagSet {
+	return flag.NewFlagSet("ocvctl "+name, flag.ExitOnError)
+}
+
+// databaseFlags adds the flag naming the configuration file database
+// commands read
+func databaseFlags(flags *flag.FlagSet) *string {
+	return flags.String("config", os.Getenv("CONFIG_FILE"), "YAML configuration file; environment variables override its settings")
+}
+
+// loadConfig loads the server's configuration, checking only the database
+// settings unless full is set
+func loadConfig(path string, full bool) (*config.Config, error) {
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if full {
+		err = cfg.Validate()
+	} else {
+		err = cfg.Database.Validate()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// openDatabase loads the configuration and connects to its database
+func openDatabase(path string, full bool) (*config.Config, *database.Database, error) {
+	cfg, err := loadConfig(path, full)
+	if err != nil {
+		return nil, nil, err
+	}
+	db, err := database.Initialize(cfg.Database.Connection())
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, db, nil
+}
+
+// userCreate creates a user, reading the password from -password, then
+// $OCV_NEW_PASSWORD, then standard input
+func userCreate(args []string) error {
+	flags := newFlags("user create")
+	configPath := databaseFlags(flags)
+	username := flags.String("username", "", "username of the new user")
+	password := flags.String("password", "", "password of the new user; prefer $OCV_NEW_PASSWORD or standard input, which stay out of the shell history")
+	role := flags.String("role", auth.RoleAdmin, "role of the new user: admin, caseworker or viewer")
+	email := flags.String("email", "", "email address for notifications, optional")
+	flags.Parse(args)
+
+	if *username == "" {
+		return errors.New("-username is required")
+	}
+	if !slices.Contains([]string{auth.RoleAdmin, auth.RoleCaseworker, auth.RoleViewer}, *role) {
+		return fmt.Errorf("invalid role %q: must be admin, caseworker or viewer", *role)
+	}
+	if *password == "" {
+		*password = os.Getenv("OCV_NEW_PASSWORD")
+	}
+	if *password == "" {
+		fmt.Fprint(os.Stderr, "Password: ")
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return fmt.Errorf("error reading password: %v", err)
+		}
+		*password = strings.TrimRight(line, "\r\n")
+	}
+	if *password == "" {
+		return errors.New("a password is required")
+	}
+
+	_, db, err := openDatabase(*configPath, false)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	users := models.NewUserRepository(db.DB)
+	existing, err := users.GetByUsername(*username)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("user %s already exists", *username)
+	}
+
+	hash, err := auth.HashPassword(*password)
+	if err != nil {
+		return err
+	}
+	user := models.User{Username: *username, PasswordHash: hash, Role: *role, Email: *email}
+	if err := users.Create(&user); err != nil {
+		return err
+	}
+	fmt.Printf("Created %s %s (%s)\n", user.Role, user.Username, user.ID)
+	return nil
+}
+
+// migrate applies pending migrations, or lists every migration with status
+func migrate(args []string) error {
+	flags := newFlags("migrate")
+	configPath := databaseFlags(flags)
+	flags.Parse(args)
+
+	command := "up"
+	if flags.NArg() > 0 {
+		command = flags.Arg(0)
+	}
+	if command != "up" && command != "status" {
+		return fmt.Errorf("unknown migrate command %q (expected up or status)", command)
+	}
+
+	_, db, err := openDatabase(*configPath, false)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if command == "status" {
+		all, err := migrations.Status(db.DB, db.Driver)
+		if err != nil {
+			return err
+		}
+		for _, m := range all {
+			state := "pending"
+			if m.AppliedAt != nil {
+				state = "applied " + m.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%-40s %s\n", m.Name, state)
+		}
+		return nil
+	}
+
+	applied, err := migrations.Up(db.DB, db.Driver)
+	for _, m := range applied {
+		fmt.Printf("Applied %s\n", m.Name)
+	}
+	if err == nil && len(applied) == 0 {
+		fmt.Println("Database schema is up to date")
+	}
+	return err
+}
+
+// keysRotate re-encrypts personal data not yet sealed with the current
+// encryption key, after which the previous keys can be removed
+func keysRotate(args []string) error {
+	flags := newFlags("keys rotate")
+	configPath := databaseFlags(flags)
+	flags.Parse(args)
+
+	cfg, db, err := openDatabase(*configPath, true)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	cipher, err := encryption.NewFromProvider(context.Background(), cfg.Encryption.Keys())
+	if err != nil {
+		return err
+	}
+	applicants := models.NewApplicantRepository(db.DB, cipher)
+
+	fmt.Printf("Re-encrypting personal data with key %s\n", cipher.KeyID())
+	result, err := applicants.RotateKeys()
+	fmt.Printf("Re-encrypted %d applicant(s) and %d household member(s)\n", result.Applicants, result.HouseholdMembers)
+	return err
+}
+
+// backupRestore replaces the data in the database with a backup taken by
+// POST /api/v1/admin/backup, read from the configured storage with -key or
+// from a file. The server should be stopped first, as the jobs it has queued
+// are discarded and its caches would be stale.
+func backupRestore(args []string) error {
+	flags := newFlags("backup restore")
+	configPath := databaseFlags(flags)
+	key := flags.String("key", "", "key of the backup in storage, such as backups/20261014T130000Z.zip")
+	yes := flags.Bool("yes", false, "confirm that the database's data is to be replaced")
+	flags.Parse(args)
+
+	if (*key == "") == (flags.NArg() == 0) {
+		return errors.New("either -key or a backup file is required")
+	}
+	if !*yes {
+		return errors.New("restoring replaces every row in the database; pass -yes to confirm")
+	}
+
+	cfg, err := loadConfig(*configPath, false)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	path := flags.Arg(0)
+	if *key != "" {
+		store, err := storage.Open(ctx, cfg.Storage.Options())
+		if err != nil {
+			return err
+		}
+		if path, err = downloadBackup(ctx, store, *key); err != nil {
+			return err
+		}
+		defer os.Remove(path)
+	}
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Errorf("error opening backup: %v", err)
+	}
+	defer archive.Close()
+
+	db, err := database.Initialize(cfg.Database.Connection())
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	manifest, err := backup.Restore(ctx, db, &archive.Reader)
+	if err != nil {
+		return err
+	}
+	for _, table := range manifest.Tables {
+		fmt.Printf("Restored %d row(s) of %s\n", table.Rows, table.Name)
+	}
+	fmt.Printf("Restored the backup taken at %s\n", manifest.CreatedAt.Format(time.RFC3339))
+	return nil
+}
+
+// downloadBackup copies a backup from storage to a temporary file, as zip
+// archives are read from their end, and returns its path
+func downloadBackup(ctx context.Context, store storage.Store, key string) (string, error) {
+	r, err := store.Get(ctx, key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return "", fmt.Errorf("no backup is stored under %s", key)
+	}
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	f, err := os.CreateTemp("", "ocv-restore-*.zip")
+	if err != nil {
+		return "", fmt.Errorf("error creating temporary file: %v", err)
+	}
+	defer f.Close()
+	if _, err := io.Copy(f, r); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("error downloading backup: %v", err)
+	}
+	return f.Name(), nil
+}
+
+// eligibilityReview queues an eligibility review as of now, as the schedule
+// does, for the server's job workers to run
+func eligibilityReview(args []string) error {
+	flags := newFlags("eligibility review")
+	configPath := databaseFlags(flags)
+	wait := flags.Bool("wait", false, "wait for the review to finish and print its result")
+	flags.Parse(args)
+
+	_, db, err := openDatabase(*configPath, false)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	jobs := models.NewJobRepository(db.DB)
+	job, err := jobs.Enqueue(models.JobEligibilityReview, models.EligibilityReviewJob{AsOf: time.Now()}, "")
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Queued job %s\n", job.ID)
+	if !*wait {
+		return nil
+	}
+
+	return waitForJob(func() (*models.Job, error) {
+		j, err := jobs.GetByID(job.ID)
+		if err == nil && j == nil {
+			err = errors.New("the job has been removed")
+		}
+		return j, err
+	})
+}
+
+// waitForJob polls a job until it finishes, printing its result, and fails if
+// the job does
+func waitForJob(get func() (*models.Job, error)) error {
+	for {
+		job, err := get()
+		if err != nil {
+			return err
+		}
+		switch job.Status {
+		case models.JobSucceeded:
+			fmt.Println(string(job.Result))
+			return nil
+		case models.JobFailed:
+			return fmt.Errorf("job %s failed after %d attempt(s): %s", job.ID, job.Attempts, job.LastError)
+		}
+		time.Sleep(time.Second)
+	}
+}
@@ -0,0 +1,799 @@
+// Package config loads the application settings from an optional YAML file
+// and the environment.
+//
+// Settings start from the defaults in Default, are overlaid with the file
+// given to Load, and are then overridden by any environment variables that
+// are set. Each field's variable is named in its env tag. Validate reports
+// every missing or invalid setting at once, so a misconfigured server fails at
+// startup rather than on first use.
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"one-client-view-2025tht/app/cache"
+	"one-client-view-2025tht/app/database"
+	"one-client-view-2025tht/app/encryption"
+	"one-client-view-2025tht/app/features"
+	"one-client-view-2025tht/app/integration"
+	"one-client-view-2025tht/app/logging"
+	"one-client-view-2025tht/app/retention"
+	"one-client-view-2025tht/app/scheduler"
+	"one-client-view-2025tht/app/storage"
+)
+
+// Config holds every setting of the application, grouped by subsystem
+type Config struct {
+	Server     ServerConfig     `yaml:"server"`
+	TLS        TLSConfig        `yaml:"tls"`
+	Database   DatabaseConfig   `yaml:"database"`
+	Auth       AuthConfig       `yaml:"auth"`
+	Encryption EncryptionConfig `yaml:"encryption"`
+	CORS       CORSConfig       `yaml:"cors"`
+	Logging    LoggingConfig    `yaml:"logging"`
+	Jobs       JobsConfig       `yaml:"jobs"`
+	SMTP       SMTPConfig       `yaml:"smtp"`
+	SMS        SMSConfig        `yaml:"sms"`
+	Cache      CacheConfig      `yaml:"cache"`
+	RateLimit  RateLimitConfig  `yaml:"rate_limit"`
+	Scheduler  SchedulerConfig  `yaml:"scheduler"`
+	Storage    StorageConfig    `yaml:"storage"`
+	Documents  DocumentsConfig  `yaml:"documents"`
+	Photos     PhotosConfig     `yaml:"photos"`
+	MyInfo     MyInfoConfig     `yaml:"myinfo"`
+	Income     IncomeConfig     `yaml:"income"`
+	Export     ExportConfig     `yaml:"export"`
+	Events     EventsConfig     `yaml:"events"`
+	Retention  RetentionConfig  `yaml:"retention"`
+	Tasks      TasksConfig      `yaml:"tasks"`
+	Portal     PortalConfig     `yaml:"portal"`
+	Features   FeaturesConfig   `yaml:"features"`
+}
+
+// ServerConfig holds the HTTP server settings
+type ServerConfig struct {
+	Port            int           `yaml:"port" env:"PORT"`
+	ReadTimeout     time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT"`
+	WriteTimeout    time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT"`
+	IdleTimeout     time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"` // How long in-flight requests may take to finish on shutdown
+	Environment     string        `yaml:"environment" env:"APP_ENV"`               // production (the default) or development
+}
+
+// TLSConfig holds the settings for serving HTTPS directly, for deployments
+// without a proxy terminating TLS. The certificate is loaded from CertFile and
+// KeyFile, or obtained from Let's Encrypt for AutocertDomains; with neither,
+// the server speaks plain HTTP. Over TLS the server also speaks HTTP/2.
+type TLSConfig struct {
+	CertFile        string   `yaml:"cert_file" env:"TLS_CERT_FILE"` // PEM, with any intermediate certificates after the server's
+	KeyFile         string   `yaml:"key_file" env:"TLS_KEY_FILE"`
+	AutocertDomains []string `yaml:"autocert_domains" env:"TLS_AUTOCERT_DOMAINS"` // Comma-separated in the environment
+	AutocertEmail   string   `yaml:"autocert_email" env:"TLS_AUTOCERT_EMAIL"`     // Told by Let's Encrypt of problems with the certificates
+	AutocertCache   string   `yaml:"autocert_cache" env:"TLS_AUTOCERT_CACHE"`     // Directory the certificates and account key are kept in
+	RedirectPort    int      `yaml:"redirect_port" env:"TLS_REDIRECT_PORT"`       // Port redirecting plain HTTP to HTTPS; 0 for none
+}
+
+// Enabled reports whether the server serves HTTPS
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" || c.Autocert()
+}
+
+// Autocert reports whether certificates are obtained from Let's Encrypt
+func (c TLSConfig) Autocert() bool {
+	return len(c.AutocertDomains) > 0
+}
+
+// Environments the server runs in. In development, requests and responses are
+// checked against the OpenAPI document.
+const (
+	EnvironmentProduction  = "production"
+	EnvironmentDevelopment = "development"
+)
+
+// MinSecretLength is the fewest bytes a token signing secret may have, so
+// that it cannot be guessed and used to forge tokens
+const MinSecretLength = 32
+
+// DatabaseConfig holds the database connection settings
+type DatabaseConfig struct {
+	Driver      string `yaml:"driver" env:"DB_DRIVER"` // mysql or sqlite
+	Host        string `yaml:"host" env:"DB_HOST"`
+	Port        int    `yaml:"port" env:"DB_PORT"`
+	User        string `yaml:"user" env:"DB_USER"`
+	Password    string `yaml:"password" env:"DB_PASSWORD"`
+	Name        string `yaml:"name" env:"DB_NAME"`
+	SQLitePath  string `yaml:"sqlite_path" env:"SQLITE_PATH"`
+	AutoMigrate bool   `yaml:"auto_migrate" env:"AUTO_MIGRATE"` // Apply pending migrations at startup
+
+	// Resilience of MySQL connections
+	MaxRetries       int           `yaml:"max_retries" env:"DB_MAX_RETRIES"`             // Retries of a connection attempt or statement that failed transiently
+	BreakerThreshold int           `yaml:"breaker_threshold" env:"DB_BREAKER_THRESHOLD"` // Consecutive connection failures that open the circuit breaker
+	BreakerCooldown  time.Duration `yaml:"breaker_cooldown" env:"DB_BREAKER_COOLDOWN"`   // How long an open breaker fails requests before trying the database again
+}
+
+// AuthConfig holds the bearer token settings
+type AuthConfig struct {
+	JWTSecret      string        `yaml:"jwt_secret" env:"JWT_SECRET"`
+	TokenExpiry    time.Duration `yaml:"token_expiry" env:"JWT_EXPIRY"`
+	TrackingSecret string        `yaml:"tracking_secret" env:"TRACKING_SECRET"` // Signs applicants' tracking tokens; JWTSecret if empty
+}
+
+// TrackingKey returns the secret applicants' tracking tokens are signed with.
+// Changing it invalidates every token already issued.
+func (c AuthConfig) TrackingKey() []byte {
+	if c.TrackingSecret != "" {
+		return []byte(c.TrackingSecret)
+	}
+	return []byte(c.JWTSecret)
+}
+
+// EncryptionConfig holds the keys used to encrypt sensitive columns at rest
+type EncryptionConfig struct {
+	Key          string   `yaml:"key" env:"ENCRYPTION_KEY"`                     // Base64-encoded 32-byte key, e.g. from "openssl rand -base64 32"
+	PreviousKeys []string `yaml:"previous_keys" env:"ENCRYPTION_PREVIOUS_KEYS"` // Retired keys, kept to decrypt values until they are rotated
+}
+
+// Keys returns a provider for the configured keys
+func (c EncryptionConfig) Keys() encryption.KeyProvider {
+	return encryption.StaticKeys{Primary: c.Key, Previous: c.PreviousKeys}
+}
+
+// CORSConfig holds the cross-origin request settings
+type CORSConfig struct {
+	AllowedOrigins []string `yaml:"allowed_origins" env:"CORS_ALLOWED_ORIGINS"` // Comma-separated in the environment; "*" allows any origin
+}
+
+// LoggingConfig holds the settings of the server log and the access log
+type LoggingConfig struct {
+	Level     string `yaml:"level" env:"LOG_LEVEL"`   // debug, info, warn or error
+	Format    string `yaml:"format" env:"LOG_FORMAT"` // json or text
+	AccessLog bool   `yaml:"access_log" env:"ACCESS_LOG"`
+	Output    string `yaml:"output" env:"ACCESS_LOG_OUTPUT"` // stdout, stderr or a file path
+}
+
+// JobsConfig holds the settings of the background job workers, which also
+// deliver webhooks
+type JobsConfig struct {
+	Workers      int           `yaml:"workers" env:"JOB_WORKERS"`             // Jobs run at once by each server
+	PollInterval time.Duration `yaml:"poll_interval" env:"JOB_POLL_INTERVAL"` // How often idle workers check for due jobs
+}
+
+// SMTPConfig holds the mail server settings for applicant notifications.
+// Emails are logged instead of sent when Host is empty.
+type SMTPConfig struct {
+	Host     string `yaml:"host" env:"SMTP_HOST"`
+	Port     int    `yaml:"port" env:"SMTP_PORT"`
+	Username string `yaml:"username" env:"SMTP_USERNAME"`
+	Password string `yaml:"password" env:"SMTP_PASSWORD"`
+	From     string `yaml:"from" env:"SMTP_FROM"`
+}
+
+// SMSConfig holds the settings of the HTTP gateway text messages, such as
+// portal sign-in codes, are sent through. Text messages are logged instead of
+// sent when no URL is set.
+type SMSConfig struct {
+	GatewayURL string        `yaml:"gateway_url" env:"SMS_GATEWAY_URL"`
+	APIKey     string        `yaml:"api_key" env:"SMS_API_KEY"`
+	Timeout    time.Duration `yaml:"timeout" env:"SMS_TIMEOUT"`
+}
+
+// CacheConfig holds the settings of the cache, which also stores idempotency
+// keys and rate limit counters
+type CacheConfig struct {
+	Backend        string        `yaml:"backend" env:"CACHE_BACKEND"`               // none (the default), memory or redis
+	RedisURL       string        `yaml:"redis_url" env:"REDIS_URL"`                 // redis://[user:password@]host:port[/db], for the redis backend
+	SchemeTTL      time.Duration `yaml:"scheme_ttl" env:"SCHEME_CACHE_TTL"`         // How long scheme data may be served from the cache
+	ApplicantTTL   time.Duration `yaml:"applicant_ttl" env:"APPLICANT_CACHE_TTL"`   // How long applicants may be served from the cache
+	IdempotencyTTL time.Duration `yaml:"idempotency_ttl" env:"IDEMPOTENCY_KEY_TTL"` // How long responses are kept for replay
+}
+
+// RateLimitConfig holds the per-client request limit, enforced when a cache
+// backend is configured
+type RateLimitConfig struct {
+	Requests int           `yaml:"requests" env:"RATE_LIMIT_REQUESTS"` // Allowed per client per window; 0 disables the limit
+	Window   time.Duration `yaml:"window" env:"RATE_LIMIT_WINDOW"`
+}
+
+// ScheduleOff disables a scheduled job
+const ScheduleOff = "off"
+
+// SchedulerConfig holds the cron schedules of recurring jobs, each a
+// five-field cron expression or "off"
+type SchedulerConfig struct {
+	EligibilityReview string `yaml:"eligibility_review" env:"ELIGIBILITY_REVIEW_SCHEDULE"` // When active applications are re-evaluated and flagged
+	ChangeExport      string `yaml:"change_export" env:"CHANGE_EXPORT_SCHEDULE"`           // When changes are exported to the data warehouse, if a sink is set
+	Retention         string `yaml:"retention" env:"RETENTION_SCHEDULE"`                   // When the retention rules are run
+	TaskReminders     string `yaml:"task_reminders" env:"TASK_REMINDERS_SCHEDULE"`         // When the assignees of tasks due soon are emailed
+	TimeZone          string `yaml:"time_zone" env:"SCHEDULER_TIME_ZONE"`                  // IANA name schedules are evaluated in; the server's local time zone if empty
+}
+
+// Location returns the time zone schedules are evaluated in
+func (c SchedulerConfig) Location() (*time.Location, error) {
+	if c.TimeZone == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(c.TimeZone)
+}
+
+// StorageConfig holds the settings of the file store documents are kept in
+type StorageConfig struct {
+	Backend     string `yaml:"backend" env:"STORAGE_BACKEND"`     // local (the default) or s3
+	LocalDir    string `yaml:"local_dir" env:"STORAGE_LOCAL_DIR"` // Directory of the local backend
+	S3Endpoint  string `yaml:"s3_endpoint" env:"S3_ENDPOINT"`     // host[:port] of an S3-compatible service, e.g. s3.amazonaws.com
+	S3Bucket    string `yaml:"s3_bucket" env:"S3_BUCKET"`         // Must already exist
+	S3Region    string `yaml:"s3_region" env:"S3_REGION"`         // Detected from the bucket if empty
+	S3AccessKey string `yaml:"s3_access_key" env:"S3_ACCESS_KEY"`
+	S3SecretKey string `yaml:"s3_secret_key" env:"S3_SECRET_KEY"`
+	S3UseSSL    bool   `yaml:"s3_use_ssl" env:"S3_USE_SSL"`
+}
+
+// Options returns the settings in the form the storage package takes
+func (c StorageConfig) Options() storage.Config {
+	return storage.Config{
+		Backend:     c.Backend,
+		LocalDir:    c.LocalDir,
+		S3Endpoint:  c.S3Endpoint,
+		S3Bucket:    c.S3Bucket,
+		S3Region:    c.S3Region,
+		S3AccessKey: c.S3AccessKey,
+		S3SecretKey: c.S3SecretKey,
+		S3UseSSL:    c.S3UseSSL,
+	}
+}
+
+// DocumentsConfig holds the limits on uploaded documents
+type DocumentsConfig struct {
+	MaxSize      int      `yaml:"max_size" env:"DOCUMENT_MAX_SIZE"`           // In bytes
+	AllowedTypes []string `yaml:"allowed_types" env:"DOCUMENT_ALLOWED_TYPES"` // Media types, detected from the content; comma-separated in the environment
+}
+
+// PhotosConfig holds the limits on uploaded applicant photos
+type PhotosConfig struct {
+	MaxSize       int `yaml:"max_size" env:"PHOTO_MAX_SIZE"`             // In bytes
+	ThumbnailSize int `yaml:"thumbnail_size" env:"PHOTO_THUMBNAIL_SIZE"` // Longest side of thumbnails, in pixels
+}
+
+// MyInfoConfig holds the settings of the MyInfo Person API that new
+// applicants are prefilled from. Prefill is disabled when no URL is set.
+type MyInfoConfig struct {
+	URL      string        `yaml:"url" env:"MYINFO_URL"`
+	ClientID string        `yaml:"client_id" env:"MYINFO_CLIENT_ID"`
+	APIKey   string        `yaml:"api_key" env:"MYINFO_API_KEY"`
+	Timeout  time.Duration `yaml:"timeout" env:"MYINFO_TIMEOUT"`
+}
+
+// IncomeConfig holds the settings of the source applicants' assessed incomes
+// are retrieved from
+type IncomeConfig struct {
+	Backend string        `yaml:"backend" env:"INCOME_BACKEND"` // none (the default), stub or iras
+	URL     string        `yaml:"url" env:"INCOME_URL"`         // Base URL of the income assessment API, for the iras backend
+	APIKey  string        `yaml:"api_key" env:"INCOME_API_KEY"`
+	Timeout time.Duration `yaml:"timeout" env:"INCOME_TIMEOUT"`
+}
+
+// ExportConfig holds the settings of the export of changes to the data
+// warehouse, which is off when no sink is set
+type ExportConfig struct {
+	Sink      string `yaml:"sink" env:"EXPORT_SINK"`             // s3://bucket/prefix, file:///dir or http(s)://host/path
+	Token     string `yaml:"token" env:"EXPORT_TOKEN"`           // Bearer token sent to http(s) sinks
+	BatchSize int    `yaml:"batch_size" env:"EXPORT_BATCH_SIZE"` // Most events per file or request
+}
+
+// EventsConfig holds the settings of the publishing of domain events to a
+// message broker, which is off when no broker is set
+type EventsConfig struct {
+	Broker       string        `yaml:"broker" env:"EVENTS_BROKER"`               // nats://host:4222, tls://host:4222, kafka+http(s)://rest-proxy
+	Prefix       string        `yaml:"prefix" env:"EVENTS_PREFIX"`               // Prepended to event names to make subjects or topics
+	BatchSize    int           `yaml:"batch_size" env:"EVENTS_BATCH_SIZE"`       // Most events published at once
+	PollInterval time.Duration `yaml:"poll_interval" env:"EVENTS_POLL_INTERVAL"` // How often an empty outbox is checked
+}
+
+// RetentionConfig holds how long records are kept, each as a period such as
+// "2y" or "18m", or "off"
+type RetentionConfig struct {
+	RejectedApplications string `yaml:"rejected_applications" env:"RETENTION_REJECTED_APPLICATIONS"` // How long after their decision rejected applications are purged
+	InactiveApplicants   string `yaml:"inactive_applicants" env:"RETENTION_INACTIVE_APPLICANTS"`     // How long after their last activity applicants are anonymized
+	Enforce              bool   `yaml:"enforce" env:"RETENTION_ENFORCE"`                             // Whether scheduled runs apply the rules, rather than only report what they match
+}
+
+// Rules returns the retention rules that are not off
+func (c RetentionConfig) Rules() ([]retention.Rule, error) {
+	var rules []retention.Rule
+	for _, setting := range []struct{ name, value string }{
+		{retention.RuleRejectedApplications, c.RejectedApplications},
+		{retention.RuleInactiveApplicants, c.InactiveApplicants},
+	} {
+		if setting.value == retention.Off {
+			continue
+		}
+		period, err := retention.ParsePeriod(setting.value)
+		if err != nil {
+			return nil, fmt.Errorf("retention.%s: %v", setting.name, err)
+		}
+		rule, err := retention.NewRule(setting.name, period)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// TasksConfig holds the settings of task reminders
+type TasksConfig struct {
+	ReminderLead time.Duration `yaml:"reminder_lead" env:"TASK_REMINDER_LEAD"` // How long before a task is due its assignee is reminded
+}
+
+// PortalConfig holds the settings of applicants' sign-in to the portal with
+// one-time codes
+type PortalConfig struct {
+	CodeExpiry      time.Duration `yaml:"code_expiry" env:"PORTAL_CODE_EXPIRY"`           // How long a code can be used for
+	MaxAttempts     int           `yaml:"max_attempts" env:"PORTAL_CODE_MAX_ATTEMPTS"`    // Wrong codes entered before a code stops working
+	TokenExpiry     time.Duration `yaml:"token_expiry" env:"PORTAL_TOKEN_EXPIRY"`         // Lifetime of the bearer tokens issued to applicants
+	LockoutFailures int           `yaml:"lockout_failures" env:"PORTAL_LOCKOUT_FAILURES"` // Sign-in attempts without success, across codes, before sign-in is locked
+	LockoutWindow   time.Duration `yaml:"lockout_window" env:"PORTAL_LOCKOUT_WINDOW"`     // How long after the first of those attempts the count resets
+}
+
+// FeaturesConfig holds the defaults of feature flags, which admins can
+// override at runtime
+type FeaturesConfig struct {
+	Enabled         []string      `yaml:"enabled" env:"FEATURES_ENABLED"`                   // Flags on by default, whatever their built-in default; comma-separated in the environment
+	Disabled        []string      `yaml:"disabled" env:"FEATURES_DISABLED"`                 // Flags off by default; comma-separated in the environment
+	RefreshInterval time.Duration `yaml:"refresh_interval" env:"FEATURES_REFRESH_INTERVAL"` // How long each replica uses the overrides it has read before reading them again
+}
+
+// Default returns the settings used when neither the file nor the
+// environment sets a value
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:            8080,
+			ReadTimeout:     15 * time.Second,
+			WriteTimeout:    60 * time.Second,
+			IdleTimeout:     120 * time.Second,
+			ShutdownTimeout: 30 * time.Second,
+			Environment:     EnvironmentProduction,
+		},
+		TLS: TLSConfig{
+			AutocertCache: "autocert",
+		},
+		Database: DatabaseConfig{
+			Driver:     database.DriverMySQL,
+			Host:       "localhost",
+			Port:       3306,
+			User:       "root",
+			Name:       "one_client_view_2025tht",
+			SQLitePath: "one_client_view_2025tht.db",
+
+			MaxRetries:       database.DefaultRetryPolicy.MaxRetries,
+			BreakerThreshold: database.DefaultBreakerThreshold,
+			BreakerCooldown:  database.DefaultBreakerCooldown,
+		},
+		Auth: AuthConfig{
+			TokenExpiry: time.Hour,
+		},
+		CORS: CORSConfig{
+			AllowedOrigins: []string{"*"},
+		},
+		Logging: LoggingConfig{
+			Level:     "info",
+			Format:    logging.FormatJSON,
+			AccessLog: true,
+			Output:    "stdout",
+		},
+		Jobs: JobsConfig{
+			Workers:      4,
+			PollInterval: 5 * time.Second,
+		},
+		SMTP: SMTPConfig{
+			Port: 587,
+			From: "no-reply@example.com",
+		},
+		SMS: SMSConfig{
+			Timeout: 10 * time.Second,
+		},
+		Cache: CacheConfig{
+			SchemeTTL:      5 * time.Minute,
+			ApplicantTTL:   time.Minute,
+			IdempotencyTTL: 24 * time.Hour,
+		},
+		RateLimit: RateLimitConfig{
+			Requests: 600,
+			Window:   time.Minute,
+		},
+		Scheduler: SchedulerConfig{
+			EligibilityReview: "0 2 * * *",
+			ChangeExport:      "*/15 * * * *",
+			Retention:         "0 3 * * *",
+			TaskReminders:     "0 * * * *",
+		},
+		Storage: StorageConfig{
+			Backend:  storage.BackendLocal,
+			LocalDir: "documents",
+			S3UseSSL: true,
+		},
+		Documents: DocumentsConfig{
+			MaxSize:      10 << 20,
+			AllowedTypes: []string{"application/pdf", "image/jpeg", "image/png"},
+		},
+		Photos: PhotosConfig{
+			MaxSize:       5 << 20,
+			ThumbnailSize: 256,
+		},
+		MyInfo: MyInfoConfig{
+			Timeout: 10 * time.Second,
+		},
+		Income: IncomeConfig{
+			Backend: integration.IncomeBackendNone,
+			Timeout: 10 * time.Second,
+		},
+		Export: ExportConfig{
+			BatchSize: 5000,
+		},
+		Events: EventsConfig{
+			Prefix:       "ocv",
+			BatchSize:    100,
+			PollInterval: time.Second,
+		},
+		Retention: RetentionConfig{
+			RejectedApplications: "2y",
+			InactiveApplicants:   "7y",
+		},
+		Tasks: TasksConfig{
+			ReminderLead: 24 * time.Hour,
+		},
+		Portal: PortalConfig{
+			CodeExpiry:      10 * time.Minute,
+			MaxAttempts:     5,
+			TokenExpiry:     30 * time.Minute,
+			LockoutFailures: 10,
+			LockoutWindow:   time.Hour,
+		},
+		Features: FeaturesConfig{
+			RefreshInterval: 30 * time.Second,
+		},
+	}
+}
+
+// Load builds the configuration from the defaults, the YAML file at path (if
+// path is not empty) and the environment. Call Validate before using it.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading config file: %v", err)
+		}
+		if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+			return nil, fmt.Errorf("error parsing config file %s: %v", path, err)
+		}
+	}
+
+	if err := applyEnv(reflect.ValueOf(cfg).Elem()); err != nil {
+		return nil, err
+	}
+
+	// JWT_EXPIRY_MINUTES predates JWT_EXPIRY and is still honoured
+	if minutes := os.Getenv("JWT_EXPIRY_MINUTES"); minutes != "" && os.Getenv("JWT_EXPIRY") == "" {
+		n, err := strconv.Atoi(minutes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWT_EXPIRY_MINUTES: %v", err)
+		}
+		cfg.Auth.TokenExpiry = time.Duration(n) * time.Minute
+	}
+
+	return cfg, nil
+}
+
+// durationType is handled separately from other int64 fields
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// applyEnv sets each field with an env tag from its environment variable, if
+// the variable is set and not empty
+func applyEnv(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			if err := applyEnv(field); err != nil {
+				return err
+			}
+			continue
+		}
+
+		key := t.Field(i).Tag.Get("env")
+		value := os.Getenv(key)
+		if key == "" || value == "" {
+			continue
+		}
+		if err := setField(field, value); err != nil {
+			return fmt.Errorf("invalid %s: %v", key, err)
+		}
+	}
+	return nil
+}
+
+// setField parses value into a string, bool, int, duration or string slice
+// field
+func setField(field reflect.Value, value string) error {
+	switch {
+	case field.Type() == durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+	case field.Kind() == reflect.String:
+		field.SetString(value)
+	case field.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case field.Kind() == reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(n))
+	case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String:
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
+
+// Validate checks that required settings are present and values are in range,
+// reporting every problem at once
+func (c *Config) Validate() error {
+	var v validator
+	v.check(c.Server.Port > 0 && c.Server.Port <= 65535, "server.port must be between 1 and 65535")
+	v.check(c.Server.ReadTimeout > 0, "server.read_timeout must be positive")
+	v.check(c.Server.WriteTimeout > 0, "server.write_timeout must be positive")
+	v.check(c.Server.IdleTimeout > 0, "server.idle_timeout must be positive")
+	v.check(c.Server.ShutdownTimeout > 0, "server.shutdown_timeout must be positive")
+	v.check(c.Server.Environment == EnvironmentProduction || c.Server.Environment == EnvironmentDevelopment,
+		"server.environment (APP_ENV) must be production or development")
+
+	c.TLS.validate(&v, c.Server.Port)
+	c.Database.validate(&v)
+
+	if c.Auth.JWTSecret == "" {
+		v.check(false, "auth.jwt_secret (JWT_SECRET) is required")
+	} else {
+		v.check(len(c.Auth.JWTSecret) >= MinSecretLength,
+			fmt.Sprintf("auth.jwt_secret (JWT_SECRET) must be at least %d bytes", MinSecretLength))
+	}
+	v.check(c.Auth.TrackingSecret == "" || len(c.Auth.TrackingSecret) >= MinSecretLength,
+		fmt.Sprintf("auth.tracking_secret (TRACKING_SECRET) must be at least %d bytes if set", MinSecretLength))
+	v.check(c.Auth.TokenExpiry > 0, "auth.token_expiry must be positive")
+
+	if c.Encryption.Key == "" {
+		v.check(false, "encryption.key (ENCRYPTION_KEY) is required")
+	} else if _, err := encryption.ParseKey(c.Encryption.Key); err != nil {
+		v.check(false, "encryption.key: "+err.Error())
+	}
+	for i, key := range c.Encryption.PreviousKeys {
+		if _, err := encryption.ParseKey(key); err != nil {
+			v.check(false, fmt.Sprintf("encryption.previous_keys[%d]: %v", i, err))
+		}
+	}
+
+	v.check(len(c.CORS.AllowedOrigins) > 0, "cors.allowed_origins must not be empty")
+	if _, err := logging.ParseLevel(c.Logging.Level); err != nil {
+		v.check(false, "logging.level (LOG_LEVEL): "+err.Error())
+	}
+	v.check(c.Logging.Format == logging.FormatJSON || c.Logging.Format == logging.FormatText,
+		"logging.format (LOG_FORMAT) must be json or text")
+	v.check(!c.Logging.AccessLog || c.Logging.Output != "", "logging.output is required when logging.access_log is enabled")
+	v.check(c.Jobs.Workers > 0, "jobs.workers must be positive")
+	v.check(c.Jobs.PollInterval > 0, "jobs.poll_interval must be positive")
+
+	if c.SMTP.Host != "" {
+		v.check(c.SMTP.Port > 0 && c.SMTP.Port <= 65535, "smtp.port must be between 1 and 65535")
+		v.check(c.SMTP.From != "", "smtp.from is required when smtp.host is set")
+	}
+
+	switch c.Cache.Backend {
+	case "", cache.BackendNone, cache.BackendMemory:
+	case cache.BackendRedis:
+		v.check(c.Cache.RedisURL != "", "cache.redis_url (REDIS_URL) is required for the redis backend")
+	default:
+		v.check(false, "cache.backend must be "+cache.BackendNone+", "+cache.BackendMemory+" or "+cache.BackendRedis)
+	}
+	v.check(c.Cache.SchemeTTL > 0, "cache.scheme_ttl must be positive")
+	v.check(c.Cache.ApplicantTTL > 0, "cache.applicant_ttl must be positive")
+	v.check(c.Cache.IdempotencyTTL > 0, "cache.idempotency_ttl must be positive")
+	v.check(c.RateLimit.Requests >= 0, "rate_limit.requests must not be negative")
+	v.check(c.RateLimit.Window > 0, "rate_limit.window must be positive")
+
+	if c.Scheduler.EligibilityReview != ScheduleOff {
+		if _, err := scheduler.Parse(c.Scheduler.EligibilityReview); err != nil {
+			v.check(false, "scheduler.eligibility_review: "+err.Error())
+		}
+	}
+	if c.Scheduler.ChangeExport != ScheduleOff {
+		if _, err := scheduler.Parse(c.Scheduler.ChangeExport); err != nil {
+			v.check(false, "scheduler.change_export: "+err.Error())
+		}
+	}
+	if c.Scheduler.Retention != ScheduleOff {
+		if _, err := scheduler.Parse(c.Scheduler.Retention); err != nil {
+			v.check(false, "scheduler.retention: "+err.Error())
+		}
+	}
+	if c.Scheduler.TaskReminders != ScheduleOff {
+		if _, err := scheduler.Parse(c.Scheduler.TaskReminders); err != nil {
+			v.check(false, "scheduler.task_reminders: "+err.Error())
+		}
+	}
+	if _, err := c.Scheduler.Location(); err != nil {
+		v.check(false, "scheduler.time_zone: "+err.Error())
+	}
+
+	switch c.Storage.Backend {
+	case "", storage.BackendLocal:
+		v.check(c.Storage.LocalDir != "", "storage.local_dir (STORAGE_LOCAL_DIR) is required for the local backend")
+	case storage.BackendS3:
+		v.check(c.Storage.S3Endpoint != "", "storage.s3_endpoint (S3_ENDPOINT) is required for the s3 backend")
+		v.check(c.Storage.S3Bucket != "", "storage.s3_bucket (S3_BUCKET) is required for the s3 backend")
+	default:
+		v.check(false, "storage.backend must be "+storage.BackendLocal+" or "+storage.BackendS3)
+	}
+	v.check(c.Documents.MaxSize > 0, "documents.max_size must be positive")
+	v.check(len(c.Documents.AllowedTypes) > 0, "documents.allowed_types must not be empty")
+	v.check(c.Photos.MaxSize > 0, "photos.max_size must be positive")
+	v.check(c.Photos.ThumbnailSize > 0, "photos.thumbnail_size must be positive")
+	if c.SMS.GatewayURL != "" {
+		u, err := url.Parse(c.SMS.GatewayURL)
+		v.check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "", "sms.gateway_url (SMS_GATEWAY_URL) must be an http or https URL")
+		v.check(c.SMS.Timeout > 0, "sms.timeout must be positive")
+	}
+	if c.MyInfo.URL != "" {
+		u, err := url.Parse(c.MyInfo.URL)
+		v.check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "", "myinfo.url (MYINFO_URL) must be an http or https URL")
+		v.check(c.MyInfo.Timeout > 0, "myinfo.timeout must be positive")
+	}
+	switch c.Income.Backend {
+	case "", integration.IncomeBackendNone:
+	case integration.IncomeBackendStub:
+		v.check(c.Server.Environment == EnvironmentDevelopment, "income.backend (INCOME_BACKEND) must not be stub in production")
+	case integration.IncomeBackendIRAS:
+		u, err := url.Parse(c.Income.URL)
+		v.check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "", "income.url (INCOME_URL) must be an http or https URL for the iras backend")
+		v.check(c.Income.Timeout > 0, "income.timeout must be positive")
+	default:
+		v.check(false, "income.backend must be "+integration.IncomeBackendNone+", "+integration.IncomeBackendStub+" or "+integration.IncomeBackendIRAS)
+	}
+	if c.Export.Sink != "" {
+		u, err := url.Parse(c.Export.Sink)
+		v.check(err == nil && slices.Contains([]string{"s3", "file", "http", "https"}, u.Scheme),
+			"export.sink (EXPORT_SINK) must be an s3://, file:// or http(s):// URL")
+		if err == nil && u.Scheme == "s3" {
+			v.check(u.Host != "", "export.sink (EXPORT_SINK) must name a bucket")
+			v.check(c.Storage.S3Endpoint != "", "storage.s3_endpoint (S3_ENDPOINT) is required for an s3 export sink")
+		}
+		v.check(c.Export.BatchSize > 0, "export.batch_size must be positive")
+	}
+	if c.Events.Broker != "" {
+		u, err := url.Parse(c.Events.Broker)
+		v.check(err == nil && slices.Contains([]string{"nats", "tls", "kafka+http", "kafka+https"}, u.Scheme) && u.Host != "",
+			"events.broker (EVENTS_BROKER) must be a nats://, tls://, kafka+http:// or kafka+https:// URL")
+		v.check(c.Events.Prefix != "" && !strings.ContainsAny(c.Events.Prefix, " \t*>/"),
+			"events.prefix (EVENTS_PREFIX) must not be empty or contain spaces, wildcards or slashes")
+		v.check(c.Events.BatchSize > 0, "events.batch_size must be positive")
+		v.check(c.Events.PollInterval > 0, "events.poll_interval must be positive")
+	}
+	if _, err := c.Retention.Rules(); err != nil {
+		v.check(false, err.Error())
+	}
+	v.check(c.Tasks.ReminderLead >= 0, "tasks.reminder_lead must not be negative")
+	v.check(c.Portal.CodeExpiry >= time.Minute, "portal.code_expiry must be at least a minute")
+	v.check(c.Portal.MaxAttempts > 0, "portal.max_attempts must be positive")
+	v.check(c.Portal.TokenExpiry > 0, "portal.token_expiry must be positive")
+	v.check(c.Portal.LockoutFailures > 0, "portal.lockout_failures must be positive")
+	v.check(c.Portal.LockoutWindow > 0, "portal.lockout_window must be positive")
+	if _, err := features.New(nil, c.Features.Enabled, c.Features.Disabled, c.Features.RefreshInterval); err != nil {
+		v.check(false, "features: "+err.Error())
+	}
+	v.check(c.Features.RefreshInterval > 0, "features.refresh_interval must be positive")
+
+	return v.err()
+}
+
+// Validate checks the database settings alone, for commands that only need a
+// connection
+func (c DatabaseConfig) Validate() error {
+	var v validator
+	c.validate(&v)
+	return v.err()
+}
+
+func (c DatabaseConfig) validate(v *validator) {
+	switch c.Driver {
+	case database.DriverMySQL:
+		v.check(c.Host != "", "database.host is required")
+		v.check(c.Port > 0 && c.Port <= 65535, "database.port must be between 1 and 65535")
+		v.check(c.User != "", "database.user is required")
+		v.check(c.Name != "", "database.name is required")
+		v.check(c.MaxRetries >= 0, "database.max_retries must not be negative")
+		v.check(c.BreakerThreshold > 0, "database.breaker_threshold must be positive")
+		v.check(c.BreakerCooldown > 0, "database.breaker_cooldown must be positive")
+	case database.DriverSQLite:
+		v.check(c.SQLitePath != "", "database.sqlite_path is required")
+	default:
+		v.check(false, "database.driver must be "+database.DriverMySQL+" or "+database.DriverSQLite)
+	}
+}
+
+// validator collects configuration problems
+type validator struct {
+	problems []string
+}
+
+func (v *validator) check(ok bool, problem string) {
+	if !ok {
+		v.problems = append(v.problems, problem)
+	}
+}
+
+func (v *validator) err() error {
+	if len(v.problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid configuration: %s", strings.Join(v.problems, "; "))
+}
+
+// Connection returns the settings used to open the database
+func (c DatabaseConfig) Connection() *database.Config {
+	retry := database.DefaultRetryPolicy
+	retry.MaxRetries = c.MaxRetries
+	return &database.Config{
+		Driver:   c.Driver,
+		Host:     c.Host,
+		Port:     c.Port,
+		User:     c.User,
+		Password: c.Password,
+		DBName:   c.Name,
+		Path:     c.SQLitePath,
+
+		Retry:            &retry,
+		BreakerThreshold: c.BreakerThreshold,
+		BreakerCooldown:  c.BreakerCooldown,
+	}
+}
+
+// validate checks the TLS settings, given the port the server listens on
+func (c TLSConfig) validate(v *validator, port int) {
+	v.check((c.CertFile == "") == (c.KeyFile == ""), "tls.cert_file (TLS_CERT_FILE) and tls.key_file (TLS_KEY_FILE) must be set together")
+	v.check(c.CertFile == "" || !c.Autocert(), "tls.cert_file and tls.autocert_domains cannot both be set")
+	v.check(!c.Autocert() || c.AutocertCache != "", "tls.autocert_cache (TLS_AUTOCERT_CACHE) is required with tls.autocert_domains")
+	if c.RedirectPort != 0 {
+		v.check(c.Enabled(), "tls.redirect_port (TLS_REDIRECT_PORT) requires a certificate or tls.autocert_domains")
+		v.check(c.RedirectPort > 0 && c.RedirectPort <= 65535, "tls.redirect_port must be between 1 and 65535")
+		v.check(c.RedirectPort != port, "tls.redirect_port must differ from server.port")
+	}
+}
+
+// Addr returns the address the HTTP server listens on
+func (c ServerConfig) Addr() string {
+	return ":" + strconv.Itoa(c.Port)
+}
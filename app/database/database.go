@@ -3,57 +3,130 @@ package database
 import (
 	"database/sql"
 	"fmt"
-	"log"
+	"time"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
+
+	"one-client-view-2025tht/app/database/migrations"
+	"one-client-view-2025tht/app/logging"
 )
 
-var (
-	DB *sql.DB
+// Supported database drivers
+const (
+	DriverMySQL  = migrations.MySQL
+	DriverSQLite = migrations.SQLite
 )
 
+// Database is an open connection pool. It embeds *sql.DB, so it can be used
+// directly for queries and passed to repositories as db.DB.
+type Database struct {
+	*sql.DB
+	Driver string // The driver in use, which is also the migration dialect
+	// Breaker guards MySQL connections, failing fast while the server is
+	// unreachable; nil for SQLite, which is in-process
+	Breaker *Breaker
+}
+
 // Config represents the database configuration
 type Config struct {
+	Driver   string // DriverMySQL (default) or DriverSQLite
 	Host     string
 	Port     int
 	User     string
 	Password string
 	DBName   string
+	Path     string // SQLite database file, or ":memory:" for an in-process database
+
+	// Resilience of MySQL connections. Transient failures are retried under
+	// Retry, and after BreakerThreshold consecutive connection failures
+	// requests fail fast for BreakerCooldown. Zero values use the defaults.
+	Retry            *RetryPolicy
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
 }
 
-// Initialize sets up the database connection
-func Initialize(config *Config) error {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=true",
-		config.User, config.Password, config.Host, config.Port, config.DBName)
+// Defaults for the circuit breaker
+const (
+	DefaultBreakerThreshold = 5
+	DefaultBreakerCooldown  = 10 * time.Second
+)
+
+// Initialize opens a database connection. SQLite databases are brought up to
+// date with the embedded migrations, so they are ready to use immediately. The
+// caller owns the returned handle and must close it.
+func Initialize(config *Config) (*Database, error) {
+	name := config.Driver
+	if name == "" {
+		name = DriverMySQL
+	}
 
-	db, err := sql.Open("mysql", dsn)
+	var db *sql.DB
+	var breaker *Breaker
+	var err error
+	switch name {
+	case DriverMySQL:
+		db, breaker, err = openMySQL(config)
+	case DriverSQLite:
+		db, err = openSQLite(config.Path)
+	default:
+		return nil, fmt.Errorf("unsupported database driver: %q", config.Driver)
+	}
 	if err != nil {
-		return fmt.Errorf("error opening database connection: %v", err)
+		return nil, err
 	}
 
 	// Test the connection
 	if err := db.Ping(); err != nil {
-		return fmt.Errorf("error connecting to database: %v", err)
+		db.Close()
+		return nil, fmt.Errorf("error connecting to database: %v", err)
 	}
 
-	// Set connection pool configuration
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
+	if name == DriverSQLite {
+		if _, err := migrations.Up(db, DriverSQLite); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("error bootstrapping SQLite schema: %v", err)
+		}
+	}
 
-	DB = db
-	log.Println("Database connection established successfully")
-	return nil
+	logging.Component("database").Info("Database connection established", "driver", name)
+	return &Database{DB: db, Driver: name, Breaker: breaker}, nil
 }
 
-// GetDB returns the database connection
-func GetDB() *sql.DB {
-	return DB
-}
+// openMySQL opens a connection pool to a MySQL server, with its connections
+// retried and guarded by a circuit breaker
+func openMySQL(config *Config) (*sql.DB, *Breaker, error) {
+	mysqlConfig := mysql.NewConfig()
+	mysqlConfig.User = config.User
+	mysqlConfig.Passwd = config.Password
+	mysqlConfig.Net = "tcp"
+	mysqlConfig.Addr = fmt.Sprintf("%s:%d", config.Host, config.Port)
+	mysqlConfig.DBName = config.DBName
+	mysqlConfig.ParseTime = true
+
+	connector, err := mysql.NewConnector(mysqlConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error opening database connection: %v", err)
+	}
 
-// Close closes the database connection
-func Close() error {
-	if DB != nil {
-		return DB.Close()
+	retry := DefaultRetryPolicy
+	if config.Retry != nil {
+		retry = *config.Retry
+	}
+	threshold := config.BreakerThreshold
+	if threshold <= 0 {
+		threshold = DefaultBreakerThreshold
+	}
+	cooldown := config.BreakerCooldown
+	if cooldown <= 0 {
+		cooldown = DefaultBreakerCooldown
 	}
-	return nil
+	breaker := NewBreaker(threshold, cooldown)
+
+	db := sql.OpenDB(&resilientConnector{Connector: connector, breaker: breaker, retry: retry})
+
+	// Set connection pool configuration
+	db.SetMaxOpenConns(25)
+	db.SetMaxIdleConns(5)
+
+	return db, breaker, nil
 }
@@ -0,0 +1,101 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// ForeignKey is a column referring to the id of a row in another table, and
+// what happens to the referring row when that row is deleted
+type ForeignKey struct {
+	Table      string
+	Column     string
+	References string
+	OnDelete   string // CASCADE, RESTRICT or SET NULL
+}
+
+// ForeignKeys lists the foreign keys defined by the migrations, as checked by
+// CheckIntegrity
+var ForeignKeys = []ForeignKey{
+	{Table: "household_members", Column: "applicant_id", References: "applicants", OnDelete: "CASCADE"},
+	{Table: "benefits", Column: "scheme_id", References: "schemes", OnDelete: "CASCADE"},
+	{Table: "scheme_checklist_items", Column: "scheme_id", References: "schemes", OnDelete: "CASCADE"},
+	{Table: "scheme_versions", Column: "scheme_id", References: "schemes", OnDelete: "CASCADE"},
+	{Table: "scheme_translations", Column: "scheme_id", References: "schemes", OnDelete: "CASCADE"},
+	{Table: "applications", Column: "applicant_id", References: "applicants", OnDelete: "RESTRICT"},
+	{Table: "applications", Column: "scheme_id", References: "schemes", OnDelete: "RESTRICT"},
+	{Table: "applications", Column: "decided_by", References: "users", OnDelete: "SET NULL"},
+	{Table: "applications", Column: "assigned_to", References: "users", OnDelete: "SET NULL"},
+	{Table: "applications", Column: "withdrawn_by", References: "users", OnDelete: "SET NULL"},
+	{Table: "applications", Column: "previous_application_id", References: "applications", OnDelete: "SET NULL"},
+	{Table: "review_flags", Column: "application_id", References: "applications", OnDelete: "CASCADE"},
+	{Table: "documents", Column: "application_id", References: "applications", OnDelete: "CASCADE"},
+	{Table: "documents", Column: "uploaded_by", References: "users", OnDelete: "SET NULL"},
+	{Table: "application_checklist_items", Column: "application_id", References: "applications", OnDelete: "CASCADE"},
+	{Table: "application_checklist_items", Column: "checklist_item_id", References: "scheme_checklist_items", OnDelete: "SET NULL"},
+	{Table: "application_checklist_items", Column: "completed_by", References: "users", OnDelete: "SET NULL"},
+	{Table: "households", Column: "head_applicant_id", References: "applicants", OnDelete: "CASCADE"},
+	{Table: "households", Column: "created_by", References: "users", OnDelete: "SET NULL"},
+	{Table: "household_memberships", Column: "household_id", References: "households", OnDelete: "CASCADE"},
+	{Table: "household_memberships", Column: "applicant_id", References: "applicants", OnDelete: "CASCADE"},
+	{Table: "income_records", Column: "applicant_id", References: "applicants", OnDelete: "CASCADE"},
+	{Table: "income_records", Column: "retrieved_by", References: "users", OnDelete: "SET NULL"},
+	{Table: "case_notes", Column: "applicant_id", References: "applicants", OnDelete: "CASCADE"},
+	{Table: "case_notes", Column: "author_id", References: "users", OnDelete: "SET NULL"},
+	{Table: "applicant_photos", Column: "applicant_id", References: "applicants", OnDelete: "CASCADE"},
+	{Table: "applicant_photos", Column: "uploaded_by", References: "users", OnDelete: "SET NULL"},
+	{Table: "consents", Column: "applicant_id", References: "applicants", OnDelete: "CASCADE"},
+	{Table: "consents", Column: "recorded_by", References: "users", OnDelete: "SET NULL"},
+	{Table: "application_comments", Column: "application_id", References: "applications", OnDelete: "CASCADE"},
+	{Table: "application_comments", Column: "parent_id", References: "application_comments", OnDelete: "CASCADE"},
+	{Table: "application_comments", Column: "author_id", References: "users", OnDelete: "SET NULL"},
+	{Table: "tasks", Column: "applicant_id", References: "applicants", OnDelete: "CASCADE"},
+	{Table: "tasks", Column: "application_id", References: "applications", OnDelete: "CASCADE"},
+	{Table: "tasks", Column: "assigned_to", References: "users", OnDelete: "SET NULL"},
+	{Table: "tasks", Column: "created_by", References: "users", OnDelete: "SET NULL"},
+	{Table: "applicant_otps", Column: "applicant_id", References: "applicants", OnDelete: "CASCADE"},
+	{Table: "referrals", Column: "applicant_id", References: "applicants", OnDelete: "CASCADE"},
+	{Table: "referrals", Column: "created_by", References: "users", OnDelete: "SET NULL"},
+	{Table: "webhook_deliveries", Column: "webhook_id", References: "webhooks", OnDelete: "CASCADE"},
+	{Table: "jobs", Column: "created_by", References: "users", OnDelete: "SET NULL"},
+}
+
+// Querier runs queries in a database or a transaction
+type Querier interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// Orphans is the number of rows whose foreign key refers to a row that does
+// not exist
+type Orphans struct {
+	ForeignKey
+	Count int
+}
+
+func (o Orphans) String() string {
+	return fmt.Sprintf("%d row(s) in %s with %s not found in %s", o.Count, o.Table, o.Column, o.References)
+}
+
+// CheckIntegrity counts the orphaned rows of each of ForeignKeys, returning
+// only those with any. The constraints prevent orphans, but rows can be left
+// behind while they are not enforced, for example by a SQLite connection
+// without foreign_keys or a MySQL import with FOREIGN_KEY_CHECKS=0. Run in a
+// transaction, it checks the rows the transaction sees.
+func CheckIntegrity(db Querier) ([]Orphans, error) {
+	var orphans []Orphans
+	for _, fk := range ForeignKeys {
+		query := fmt.Sprintf(`SELECT COUNT(*) FROM %s c
+			WHERE c.%s IS NOT NULL
+			AND NOT EXISTS (SELECT 1 FROM %s p WHERE p.id = c.%s)`,
+			fk.Table, fk.Column, fk.References, fk.Column)
+
+		var count int
+		if err := db.QueryRow(query).Scan(&count); err != nil {
+			return nil, fmt.Errorf("error checking %s.%s: %v", fk.Table, fk.Column, err)
+		}
+		if count > 0 {
+			orphans = append(orphans, Orphans{ForeignKey: fk, Count: count})
+		}
+	}
+	return orphans, nil
+}
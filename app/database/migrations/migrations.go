@@ -0,0 +1,233 @@
+// Package migrations applies the versioned SQL files embedded in this
+// directory to the database, recording each applied version in the
+// schema_migrations table.
+//
+// Each supported dialect has its own directory of files named
+// <version>_<description>.sql, applied in version order. Every migration must
+// exist for all dialects under the same version. Applied migrations must never
+// be edited; schema changes go in a new file, and app/database/schema.sql is
+// kept in sync as a snapshot of the MySQL result.
+package migrations
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+//go:embed mysql/*.sql sqlite/*.sql
+var files embed.FS
+
+// Supported dialects, matching the directory names
+const (
+	MySQL  = "mysql"
+	SQLite = "sqlite"
+)
+
+// Migration is a single versioned schema change
+type Migration struct {
+	Version   string     // e.g. "0001"
+	Name      string     // File name, e.g. "0001_initial_schema.sql"
+	SQL       string     // Statements to run, separated by semicolons
+	AppliedAt *time.Time // Set when the migration has been applied
+}
+
+// createTable creates the table tracking applied migrations
+const createTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version VARCHAR(255) PRIMARY KEY,
+    applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+)`
+
+// Load returns the embedded migrations for a dialect in version order
+func Load(dialect string) ([]Migration, error) {
+	if dialect != MySQL && dialect != SQLite {
+		return nil, fmt.Errorf("unsupported migration dialect: %q", dialect)
+	}
+
+	names, err := fs.Glob(files, dialect+"/*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("error listing migrations: %v", err)
+	}
+	sort.Strings(names)
+
+	migrations := make([]Migration, 0, len(names))
+	seen := make(map[string]string)
+	for _, name := range names {
+		version, _, ok := strings.Cut(strings.TrimSuffix(path.Base(name), ".sql"), "_")
+		if !ok || version == "" {
+			return nil, fmt.Errorf("invalid migration file name: %s", name)
+		}
+		if other, dup := seen[version]; dup {
+			return nil, fmt.Errorf("duplicate migration version %s: %s and %s", version, other, name)
+		}
+		seen[version] = name
+
+		content, err := files.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("error reading migration %s: %v", name, err)
+		}
+		migrations = append(migrations, Migration{Version: version, Name: path.Base(name), SQL: string(content)})
+	}
+
+	return migrations, nil
+}
+
+// Status returns all migrations, with AppliedAt set on those already applied
+func Status(db *sql.DB, dialect string) ([]Migration, error) {
+	migrations, err := Load(dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := db.Exec(createTable); err != nil {
+		return nil, fmt.Errorf("error creating schema_migrations table: %v", err)
+	}
+
+	rows, err := db.Query(`SELECT version, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("error querying schema_migrations: %v", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[string]time.Time)
+	for rows.Next() {
+		var version string
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, fmt.Errorf("error scanning schema_migrations row: %v", err)
+		}
+		applied[version] = appliedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating schema_migrations rows: %v", err)
+	}
+
+	for i := range migrations {
+		if t, ok := applied[migrations[i].Version]; ok {
+			migrations[i].AppliedAt = &t
+		}
+	}
+	return migrations, nil
+}
+
+// Up applies all pending migrations in order and returns the ones applied.
+// Each migration and its schema_migrations row are committed together, but
+// note that MySQL commits DDL statements implicitly, so a migration that
+// fails partway may need manual cleanup before it is retried.
+func Up(db *sql.DB, dialect string) ([]Migration, error) {
+	migrations, err := Status(db, dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	for _, m := range migrations {
+		if m.AppliedAt != nil {
+			continue
+		}
+		if err := apply(db, m); err != nil {
+			return applied, err
+		}
+		now := time.Now()
+		m.AppliedAt = &now
+		applied = append(applied, m)
+	}
+	return applied, nil
+}
+
+// Pending returns the migrations that have not been applied yet
+func Pending(db *sql.DB, dialect string) ([]Migration, error) {
+	migrations, err := Status(db, dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, m := range migrations {
+		if m.AppliedAt == nil {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
+// apply runs a single migration and records it
+func apply(db *sql.DB, m Migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting migration %s: %v", m.Name, err)
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range splitStatements(m.SQL) {
+		if _, err := tx.Exec(stmt); err != nil {
+			return fmt.Errorf("error applying migration %s: %v", m.Name, err)
+		}
+	}
+
+	if _, err := tx.Exec(`INSERT INTO schema_migrations (version) VALUES (?)`, m.Version); err != nil {
+		return fmt.Errorf("error recording migration %s: %v", m.Name, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing migration %s: %v", m.Name, err)
+	}
+	return nil
+}
+
+// splitStatements splits a SQL script into individual statements on
+// semicolons, ignoring semicolons inside quoted strings and -- comments
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	var quote byte
+	inComment := false
+
+	flush := func() {
+		if stmt := strings.TrimSpace(current.String()); stmt != "" {
+			statements = append(statements, stmt)
+		}
+		current.Reset()
+	}
+
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+
+		switch {
+		case inComment:
+			if c == '\n' {
+				inComment = false
+				current.WriteByte(c)
+			}
+			continue
+		case quote != 0:
+			current.WriteByte(c)
+			if c == '\\' && i+1 < len(script) {
+				i++
+				current.WriteByte(script[i])
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		case c == '-' && i+1 < len(script) && script[i+1] == '-':
+			inComment = true
+			i++
+			continue
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == ';':
+			flush()
+			continue
+		}
+
+		current.WriteByte(c)
+	}
+	flush()
+
+	return statements
+}
@@ -0,0 +1,102 @@
+-- Initial schema: applicants, households, schemes, applications, users and audit logs
+
+-- Applicants table
+CREATE TABLE applicants (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    employment_status ENUM('employed', 'unemployed') NOT NULL,
+    sex ENUM('male', 'female', 'other') NOT NULL,
+    date_of_birth DATE NOT NULL,
+    marital_status ENUM('single', 'married', 'widowed', 'divorced') NOT NULL,
+    monthly_income DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL -- Set when soft-deleted
+);
+
+-- Household members table
+CREATE TABLE household_members (
+    id VARCHAR(36) PRIMARY KEY,
+    applicant_id VARCHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    employment_status ENUM('employed', 'unemployed') NOT NULL,
+    sex ENUM('male', 'female', 'other') NOT NULL,
+    date_of_birth DATE NOT NULL,
+    relation VARCHAR(50) NOT NULL, -- e.g., 'son', 'daughter', 'spouse', etc.
+    monthly_income DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (applicant_id) REFERENCES applicants(id) ON DELETE CASCADE
+);
+
+-- Schemes table
+CREATE TABLE schemes (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL,
+    criteria JSON NOT NULL, -- Store eligibility criteria as JSON
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+-- Benefits table
+CREATE TABLE benefits (
+    id VARCHAR(36) PRIMARY KEY,
+    scheme_id VARCHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    amount DECIMAL(10, 2),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (scheme_id) REFERENCES schemes(id) ON DELETE CASCADE
+);
+
+-- Applications table
+CREATE TABLE applications (
+    id VARCHAR(36) PRIMARY KEY,
+    applicant_id VARCHAR(36) NOT NULL,
+    scheme_id VARCHAR(36) NOT NULL,
+    status ENUM('pending', 'approved', 'rejected') NOT NULL DEFAULT 'pending',
+    application_date TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    decision_date TIMESTAMP NULL,
+    notes TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL, -- Set when soft-deleted
+    FOREIGN KEY (applicant_id) REFERENCES applicants(id),
+    FOREIGN KEY (scheme_id) REFERENCES schemes(id)
+);
+
+-- Users table (staff who can log in to the API)
+CREATE TABLE users (
+    id VARCHAR(36) PRIMARY KEY,
+    username VARCHAR(100) NOT NULL UNIQUE,
+    password_hash VARCHAR(255) NOT NULL, -- bcrypt hash
+    role ENUM('admin', 'caseworker') NOT NULL DEFAULT 'caseworker',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+-- Audit logs table (who changed what, for compliance reviews)
+CREATE TABLE audit_logs (
+    id VARCHAR(36) PRIMARY KEY,
+    entity_type VARCHAR(50) NOT NULL, -- e.g., 'applicant', 'scheme', 'application'
+    entity_id VARCHAR(36) NOT NULL,
+    action VARCHAR(20) NOT NULL, -- e.g., 'create', 'update', 'delete'
+    actor_id VARCHAR(36),
+    actor_username VARCHAR(100),
+    before_data JSON NULL,
+    after_data JSON NULL,
+    changes JSON NULL, -- Field-level changes: {"field": {"old": ..., "new": ...}}
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Indexes for performance
+CREATE INDEX idx_household_applicant ON household_members(applicant_id);
+CREATE INDEX idx_benefits_scheme ON benefits(scheme_id);
+CREATE INDEX idx_applications_applicant ON applications(applicant_id);
+CREATE INDEX idx_applications_scheme ON applications(scheme_id);
+CREATE INDEX idx_applicants_deleted ON applicants(deleted_at);
+CREATE INDEX idx_applications_deleted ON applications(deleted_at);
+CREATE INDEX idx_audit_entity ON audit_logs(entity_type, entity_id);
+CREATE INDEX idx_audit_created ON audit_logs(created_at);
@@ -0,0 +1,5 @@
+-- Version counters for optimistic locking, incremented on every update
+
+ALTER TABLE applicants ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE schemes ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE applications ADD COLUMN version INT NOT NULL DEFAULT 1;
@@ -0,0 +1,25 @@
+-- Effective-dated scheme versions, so policy changes apply from a given date
+-- without altering the terms past applications were assessed under
+
+CREATE TABLE scheme_versions (
+    scheme_id VARCHAR(36) NOT NULL,
+    version INT NOT NULL, -- Matches schemes.version when the terms were saved
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL,
+    criteria JSON NOT NULL,
+    effective_from TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    effective_to TIMESTAMP NULL, -- NULL while the version is the latest
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (scheme_id, version),
+    FOREIGN KEY (scheme_id) REFERENCES schemes(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_scheme_versions_effective ON scheme_versions(effective_from, effective_to);
+
+ALTER TABLE applications ADD COLUMN scheme_version INT NULL;
+
+-- Existing schemes start with a single version, effective since creation
+INSERT INTO scheme_versions (scheme_id, version, name, description, criteria, effective_from)
+SELECT id, version, name, description, criteria, created_at FROM schemes;
+
+UPDATE applications SET scheme_version = (SELECT version FROM schemes WHERE schemes.id = applications.scheme_id);
@@ -0,0 +1,7 @@
+-- Who decided an application, why, and the benefit amount recommended on
+-- approval
+
+ALTER TABLE applications ADD COLUMN decided_by VARCHAR(36) NULL;
+ALTER TABLE applications ADD COLUMN decision_reason TEXT NULL;
+ALTER TABLE applications ADD COLUMN recommended_benefit_amount DECIMAL(10, 2) NULL;
+ALTER TABLE applications ADD CONSTRAINT fk_applications_decided_by FOREIGN KEY (decided_by) REFERENCES users(id) ON DELETE SET NULL;
@@ -0,0 +1,6 @@
+-- National identity numbers (NRIC/FIN), encrypted at rest. The hash is a
+-- keyed blind index used for lookups and to keep each person unique.
+
+ALTER TABLE applicants ADD COLUMN identity_number_encrypted TEXT NULL;
+ALTER TABLE applicants ADD COLUMN identity_number_hash CHAR(64) NULL;
+CREATE UNIQUE INDEX idx_applicants_identity_number ON applicants(identity_number_hash);
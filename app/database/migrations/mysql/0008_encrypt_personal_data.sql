@@ -0,0 +1,12 @@
+-- Names and dates of birth of applicants and household members are now
+-- AES-GCM encrypted by the application, so the columns hold ciphertext.
+-- Existing plaintext values stay readable and are encrypted by the
+-- "encryption rotate" command.
+
+ALTER TABLE applicants
+    MODIFY name TEXT NOT NULL,
+    MODIFY date_of_birth VARCHAR(255) NOT NULL;
+
+ALTER TABLE household_members
+    MODIFY name TEXT NOT NULL,
+    MODIFY date_of_birth VARCHAR(255) NOT NULL;
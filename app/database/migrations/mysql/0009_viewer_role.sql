@@ -0,0 +1,3 @@
+-- Read-only users, who receive data-minimized responses
+
+ALTER TABLE users MODIFY role ENUM('admin', 'caseworker', 'viewer') NOT NULL DEFAULT 'caseworker';
@@ -0,0 +1,12 @@
+-- Searchable words of encrypted names. Each term is a keyed blind index of a
+-- lower-cased word, so names can be matched by whole word without being
+-- stored in plaintext.
+
+CREATE TABLE search_terms (
+    entity_type VARCHAR(20) NOT NULL, -- 'applicant' or 'household_member'
+    entity_id VARCHAR(36) NOT NULL,
+    term_hash CHAR(64) NOT NULL,
+    PRIMARY KEY (entity_type, entity_id, term_hash)
+);
+
+CREATE INDEX idx_search_terms_hash ON search_terms(term_hash, entity_type);
@@ -0,0 +1,4 @@
+-- The school level a household member is enrolled at, as declared by the
+-- applicant. Members without one are assessed by age instead.
+
+ALTER TABLE household_members ADD COLUMN school_level ENUM('preschool', 'primary', 'secondary', 'tertiary', 'none') NULL;
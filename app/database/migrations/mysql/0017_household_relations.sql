@@ -0,0 +1,13 @@
+-- Household member relations become an enumeration. Existing free-text
+-- relations are mapped to it, with anything unrecognised becoming 'other'.
+
+UPDATE household_members SET relation = CASE
+    WHEN LOWER(TRIM(relation)) IN ('spouse', 'husband', 'wife') THEN 'spouse'
+    WHEN LOWER(TRIM(relation)) IN ('son', 'stepson', 'step-son') THEN 'son'
+    WHEN LOWER(TRIM(relation)) IN ('daughter', 'stepdaughter', 'step-daughter') THEN 'daughter'
+    WHEN LOWER(TRIM(relation)) IN ('parent', 'father', 'mother') THEN 'parent'
+    WHEN LOWER(TRIM(relation)) IN ('sibling', 'brother', 'sister') THEN 'sibling'
+    ELSE 'other'
+END;
+
+ALTER TABLE household_members MODIFY relation ENUM('spouse', 'son', 'daughter', 'parent', 'sibling', 'other') NOT NULL;
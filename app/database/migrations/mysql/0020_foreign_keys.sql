@@ -0,0 +1,20 @@
+-- Name the foreign keys of household members, benefits and applications and
+-- state their ON DELETE rules: household members and benefits are removed with
+-- their applicant or scheme, while applicants and schemes cannot be deleted
+-- while applications refer to them, so case history is never lost. The
+-- constraints replace those MySQL named when the tables were created.
+
+ALTER TABLE household_members DROP FOREIGN KEY household_members_ibfk_1;
+ALTER TABLE household_members ADD CONSTRAINT fk_household_members_applicant
+    FOREIGN KEY (applicant_id) REFERENCES applicants(id) ON DELETE CASCADE;
+
+ALTER TABLE benefits DROP FOREIGN KEY benefits_ibfk_1;
+ALTER TABLE benefits ADD CONSTRAINT fk_benefits_scheme
+    FOREIGN KEY (scheme_id) REFERENCES schemes(id) ON DELETE CASCADE;
+
+ALTER TABLE applications DROP FOREIGN KEY applications_ibfk_1, DROP FOREIGN KEY applications_ibfk_2;
+ALTER TABLE applications
+    ADD CONSTRAINT fk_applications_applicant
+        FOREIGN KEY (applicant_id) REFERENCES applicants(id) ON DELETE RESTRICT,
+    ADD CONSTRAINT fk_applications_scheme
+        FOREIGN KEY (scheme_id) REFERENCES schemes(id) ON DELETE RESTRICT;
@@ -0,0 +1,18 @@
+-- Applicants' photos, shown on their client cards. The uploaded image and a
+-- thumbnail of it are kept in the configured storage backend; each upload
+-- replaces the applicant's previous photo.
+
+CREATE TABLE applicant_photos (
+    applicant_id VARCHAR(36) PRIMARY KEY,
+    content_type VARCHAR(100) NOT NULL, -- Of the uploaded image
+    size BIGINT NOT NULL, -- Of the uploaded image, in bytes
+    width INTEGER NOT NULL, -- In pixels
+    height INTEGER NOT NULL,
+    storage_key VARCHAR(255) NOT NULL,
+    thumbnail_key VARCHAR(255) NOT NULL,
+    thumbnail_content_type VARCHAR(100) NOT NULL,
+    uploaded_by VARCHAR(36) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_applicant_photos_applicant FOREIGN KEY (applicant_id) REFERENCES applicants(id) ON DELETE CASCADE,
+    CONSTRAINT fk_applicant_photos_uploaded_by FOREIGN KEY (uploaded_by) REFERENCES users(id) ON DELETE SET NULL
+);
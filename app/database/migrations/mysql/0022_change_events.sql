@@ -0,0 +1,13 @@
+-- Outbox of changes to export to the data warehouse. A row is added with
+-- every audit entry, in the same transaction, and deleted once shipped.
+
+CREATE TABLE change_events (
+    sequence BIGINT AUTO_INCREMENT PRIMARY KEY, -- Order the changes were made in
+    id VARCHAR(36) NOT NULL UNIQUE, -- The audit entry's ID
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id VARCHAR(36) NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    actor_id VARCHAR(36) NULL,
+    data JSON NULL, -- The entity after the change, as audited; null for deletes
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,4 @@
+-- Marks applicants whose personal data has been irreversibly replaced, on
+-- request or under the retention rules
+
+ALTER TABLE applicants ADD COLUMN anonymized_at TIMESTAMP NULL;
@@ -0,0 +1,13 @@
+-- Names and descriptions of schemes in the languages other than English that
+-- the API serves, chosen by the Accept-Language header. Schemes without a
+-- translation into a language are served in English.
+
+CREATE TABLE scheme_translations (
+    scheme_id VARCHAR(36) NOT NULL,
+    language VARCHAR(8) NOT NULL, -- zh, ms or ta
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (scheme_id, language),
+    CONSTRAINT fk_scheme_translations_scheme FOREIGN KEY (scheme_id) REFERENCES schemes(id) ON DELETE CASCADE
+);
@@ -0,0 +1,18 @@
+-- Amounts of money in whole cents instead of decimals, so that they and their
+-- totals are exact, and the ISO 4217 currency of each benefit. Existing
+-- amounts are converted and rounded to the cent.
+
+ALTER TABLE benefits ADD COLUMN amount_cents BIGINT NULL;
+ALTER TABLE benefits ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'SGD';
+UPDATE benefits SET amount_cents = ROUND(amount * 100);
+ALTER TABLE benefits DROP COLUMN amount;
+
+ALTER TABLE applications ADD COLUMN recommended_benefit_amount_cents BIGINT NULL;
+UPDATE applications SET recommended_benefit_amount_cents = ROUND(recommended_benefit_amount * 100);
+ALTER TABLE applications DROP COLUMN recommended_benefit_amount;
+
+ALTER TABLE schemes ADD COLUMN budget_cents BIGINT NULL;
+ALTER TABLE schemes ADD COLUMN approved_amount_cents BIGINT NOT NULL DEFAULT 0;
+UPDATE schemes SET budget_cents = ROUND(budget * 100), approved_amount_cents = ROUND(approved_amount * 100);
+ALTER TABLE schemes DROP COLUMN budget;
+ALTER TABLE schemes DROP COLUMN approved_amount;
@@ -0,0 +1,5 @@
+-- How often each benefit is paid and, for recurring benefits, over how many
+-- months. Existing benefits are one-off payments.
+
+ALTER TABLE benefits ADD COLUMN frequency VARCHAR(20) NOT NULL DEFAULT 'one_off';
+ALTER TABLE benefits ADD COLUMN duration_months INTEGER NULL;
@@ -0,0 +1,4 @@
+-- Formulas scaling a benefit's amount with the applicant's household, stored
+-- as JSON. Benefits without one pay their amount as before.
+
+ALTER TABLE benefits ADD COLUMN formula TEXT NULL;
@@ -0,0 +1,7 @@
+-- The caseworker, or admin, an application is assigned to for review, and
+-- when it was assigned
+
+ALTER TABLE applications ADD COLUMN assigned_to VARCHAR(36) NULL;
+ALTER TABLE applications ADD COLUMN assigned_at TIMESTAMP NULL;
+ALTER TABLE applications ADD CONSTRAINT fk_applications_assigned_to FOREIGN KEY (assigned_to) REFERENCES users(id) ON DELETE SET NULL;
+CREATE INDEX idx_applications_assigned_to ON applications(assigned_to, status);
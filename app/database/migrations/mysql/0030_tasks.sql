@@ -0,0 +1,29 @@
+-- Follow-ups to be done for an applicant or one of their applications, such
+-- as verifying a payslip, by a due date. Assignees are emailed a reminder
+-- once a task is due soon, so users now have an optional email address.
+
+ALTER TABLE users ADD COLUMN email VARCHAR(255) NULL;
+
+CREATE TABLE tasks (
+    id VARCHAR(36) PRIMARY KEY,
+    applicant_id VARCHAR(36) NOT NULL,
+    application_id VARCHAR(36) NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NULL,
+    due_date TIMESTAMP NULL,
+    assigned_to VARCHAR(36) NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open', -- open, done or cancelled
+    created_by VARCHAR(36) NULL,
+    completed_at TIMESTAMP NULL, -- When the task was done or cancelled
+    reminded_at TIMESTAMP NULL, -- When the assignee was emailed a reminder; cleared when the due date changes
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_tasks_applicant FOREIGN KEY (applicant_id) REFERENCES applicants(id) ON DELETE CASCADE,
+    CONSTRAINT fk_tasks_application FOREIGN KEY (application_id) REFERENCES applications(id) ON DELETE CASCADE,
+    CONSTRAINT fk_tasks_assigned_to FOREIGN KEY (assigned_to) REFERENCES users(id) ON DELETE SET NULL,
+    CONSTRAINT fk_tasks_created_by FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_tasks_due ON tasks(status, due_date);
+CREATE INDEX idx_tasks_assigned_to ON tasks(assigned_to, status);
+CREATE INDEX idx_tasks_applicant ON tasks(applicant_id, status);
@@ -0,0 +1,21 @@
+-- Referrals of applicants to help delivered outside this system, such as
+-- another agency's scheme, and what came of them.
+
+CREATE TABLE referrals (
+    id VARCHAR(36) PRIMARY KEY,
+    applicant_id VARCHAR(36) NOT NULL,
+    referred_by VARCHAR(255) NOT NULL, -- The agency or officer making the referral
+    referred_to VARCHAR(255) NOT NULL, -- The agency referred to
+    scheme VARCHAR(255) NULL, -- The agency's scheme or service, if any
+    reason TEXT NOT NULL,
+    outcome VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, accepted, declined or completed
+    outcome_notes TEXT NULL,
+    outcome_at TIMESTAMP NULL, -- When the outcome was last set to other than pending
+    created_by VARCHAR(36) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_referrals_applicant FOREIGN KEY (applicant_id) REFERENCES applicants(id) ON DELETE CASCADE,
+    CONSTRAINT fk_referrals_created_by FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_referrals_applicant ON referrals(applicant_id, created_at);
@@ -0,0 +1,6 @@
+-- Where a scheme is in its lifecycle: draft schemes are prepared without
+-- being offered to anyone, published ones are offered while active and open,
+-- and archived ones are kept for their applications only. Schemes created
+-- before the lifecycle are already in use, so they start published.
+
+ALTER TABLE schemes ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'published';
@@ -0,0 +1,18 @@
+-- Every transition of an application as an immutable event, added in the
+-- same transaction as its audit entry. Replaying an application's events in
+-- order rebuilds its current state. Rows are never updated, except to drop
+-- the data of purged and anonymized applications, and never deleted.
+
+CREATE TABLE application_events (
+    sequence BIGINT AUTO_INCREMENT PRIMARY KEY, -- Order events happened in, across applications
+    id VARCHAR(36) NOT NULL UNIQUE,
+    application_id VARCHAR(36) NOT NULL,
+    version INT NOT NULL, -- 1 for an application's first event, counting up
+    type VARCHAR(20) NOT NULL, -- The audited action, such as create or approve
+    actor_id VARCHAR(36) NULL,
+    actor_username VARCHAR(100) NULL,
+    data JSON NULL, -- The fields the event set, or the whole application for create and anonymize events
+    occurred_at TIMESTAMP NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_application_events_version ON application_events(application_id, version);
@@ -0,0 +1,7 @@
+-- NRIC/FIN of household members, optional and encrypted at rest like the
+-- applicant's. The hash is not unique, as the same person may be declared in
+-- more than one household; the household validation report flags it.
+
+ALTER TABLE household_members ADD COLUMN identity_number_encrypted TEXT NULL;
+ALTER TABLE household_members ADD COLUMN identity_number_hash CHAR(64) NULL;
+CREATE INDEX idx_household_members_identity_number ON household_members(identity_number_hash);
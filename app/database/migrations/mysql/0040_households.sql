@@ -0,0 +1,32 @@
+-- Households shared by several applicants of the same family, so the members
+-- they have in common are recorded once. The head's household members are
+-- the household's; other applicants join it for a period, with their relation
+-- to the head, and are assessed for eligibility against it meanwhile.
+
+CREATE TABLE households (
+    id VARCHAR(36) PRIMARY KEY,
+    head_applicant_id VARCHAR(36) NOT NULL, -- The applicant the members' relations are to
+    created_by VARCHAR(36) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_households_head FOREIGN KEY (head_applicant_id) REFERENCES applicants(id) ON DELETE CASCADE,
+    CONSTRAINT fk_households_created_by FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE UNIQUE INDEX idx_households_head ON households(head_applicant_id);
+
+CREATE TABLE household_memberships (
+    id VARCHAR(36) PRIMARY KEY,
+    household_id VARCHAR(36) NOT NULL,
+    applicant_id VARCHAR(36) NOT NULL,
+    relation VARCHAR(20) NOT NULL, -- The applicant's relation to the head, as for household members
+    start_date TIMESTAMP NOT NULL, -- A member from this day
+    end_date TIMESTAMP NULL, -- No longer a member from this day, if set
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_household_memberships_household FOREIGN KEY (household_id) REFERENCES households(id) ON DELETE CASCADE,
+    CONSTRAINT fk_household_memberships_applicant FOREIGN KEY (applicant_id) REFERENCES applicants(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_household_memberships_household ON household_memberships(household_id);
+CREATE INDEX idx_household_memberships_applicant ON household_memberships(applicant_id);
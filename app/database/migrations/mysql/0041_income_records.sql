@@ -0,0 +1,19 @@
+-- Incomes retrieved for applicants from external sources, such as IRAS
+-- notices of assessment, with the source and date of each retrieval. The
+-- latest is copied to the applicant's monthly_income.
+
+CREATE TABLE income_records (
+    id VARCHAR(36) PRIMARY KEY,
+    applicant_id VARCHAR(36) NOT NULL,
+    source VARCHAR(50) NOT NULL, -- The provider the income came from
+    monthly_income DECIMAL(10, 2) NOT NULL,
+    previous_monthly_income DECIMAL(10, 2) NOT NULL, -- The applicant's monthly_income before it was replaced
+    assessment_year INT NULL, -- The year the income was assessed for, if the source gives one
+    reference VARCHAR(100) NULL, -- The source's reference for the record, such as a notice number
+    retrieved_by VARCHAR(36) NULL,
+    retrieved_at TIMESTAMP NOT NULL,
+    CONSTRAINT fk_income_records_applicant FOREIGN KEY (applicant_id) REFERENCES applicants(id) ON DELETE CASCADE,
+    CONSTRAINT fk_income_records_retrieved_by FOREIGN KEY (retrieved_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_income_records_applicant ON income_records(applicant_id, retrieved_at);
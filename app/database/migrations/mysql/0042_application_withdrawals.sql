@@ -0,0 +1,8 @@
+-- Applications can be withdrawn by the applicant or a caseworker while
+-- pending or approved, recording when, by whom and why
+
+ALTER TABLE applications MODIFY status ENUM('pending', 'approved', 'rejected', 'withdrawn') NOT NULL DEFAULT 'pending';
+ALTER TABLE applications ADD COLUMN withdrawn_at TIMESTAMP NULL;
+ALTER TABLE applications ADD COLUMN withdrawn_by VARCHAR(36) NULL;
+ALTER TABLE applications ADD COLUMN withdrawal_reason TEXT NULL;
+ALTER TABLE applications ADD CONSTRAINT fk_applications_withdrawn_by FOREIGN KEY (withdrawn_by) REFERENCES users(id) ON DELETE SET NULL;
@@ -0,0 +1,8 @@
+-- Applications made after a rejection for the same scheme link to the
+-- rejected one, and schemes can set a cooling-off period after a rejection
+-- before the applicant can apply again
+
+ALTER TABLE schemes ADD COLUMN cooling_off_days INT NULL;
+ALTER TABLE applications ADD COLUMN previous_application_id VARCHAR(36) NULL;
+ALTER TABLE applications ADD CONSTRAINT fk_applications_previous FOREIGN KEY (previous_application_id) REFERENCES applications(id) ON DELETE SET NULL;
+CREATE INDEX idx_applications_previous ON applications(previous_application_id);
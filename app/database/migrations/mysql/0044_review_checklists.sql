@@ -0,0 +1,40 @@
+-- Review checklists: the items, such as documents to verify and interviews
+-- to conduct, that each scheme's applications are reviewed against, and the
+-- copies made on each application when it is created, which reviewers tick
+-- off. Approval waits for the mandatory items. Existing applications have no
+-- checklist.
+
+CREATE TABLE scheme_checklist_items (
+    id VARCHAR(36) PRIMARY KEY,
+    scheme_id VARCHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NULL,
+    kind VARCHAR(20) NOT NULL DEFAULT 'other', -- document, interview or other
+    mandatory BOOLEAN NOT NULL DEFAULT FALSE, -- Must be completed before the application is approved
+    position INT NOT NULL DEFAULT 0, -- Items are listed by position, then name
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_scheme_checklist_items_scheme FOREIGN KEY (scheme_id) REFERENCES schemes(id) ON DELETE CASCADE
+);
+
+CREATE TABLE application_checklist_items (
+    id VARCHAR(36) PRIMARY KEY,
+    application_id VARCHAR(36) NOT NULL,
+    checklist_item_id VARCHAR(36) NULL, -- The scheme's item this was copied from
+    name VARCHAR(255) NOT NULL,
+    description TEXT NULL,
+    kind VARCHAR(20) NOT NULL DEFAULT 'other',
+    mandatory BOOLEAN NOT NULL DEFAULT FALSE,
+    position INT NOT NULL DEFAULT 0,
+    completed_at TIMESTAMP NULL,
+    completed_by VARCHAR(36) NULL,
+    note TEXT NULL, -- The reviewer's note on completing the item
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_application_checklist_items_application FOREIGN KEY (application_id) REFERENCES applications(id) ON DELETE CASCADE,
+    CONSTRAINT fk_application_checklist_items_item FOREIGN KEY (checklist_item_id) REFERENCES scheme_checklist_items(id) ON DELETE SET NULL,
+    CONSTRAINT fk_application_checklist_items_completed_by FOREIGN KEY (completed_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_scheme_checklist_items_scheme ON scheme_checklist_items(scheme_id);
+CREATE INDEX idx_application_checklist_items_application ON application_checklist_items(application_id);
@@ -0,0 +1,7 @@
+-- Application dates are calendar dates, the day an application was made;
+-- created_at keeps the time. Existing dates keep their day in the session's
+-- time zone.
+
+UPDATE applications SET application_date = created_at WHERE application_date IS NULL;
+
+ALTER TABLE applications MODIFY application_date DATE NOT NULL;
@@ -0,0 +1,11 @@
+-- Deleting an approved application now releases the capacity it took up of
+-- its scheme, and restoring it takes it up again. Approvals deleted before
+-- then still count, so the totals are recounted from those not deleted.
+
+UPDATE schemes SET
+    approved_count = (SELECT COUNT(*) FROM applications a
+                      WHERE a.scheme_id = schemes.id AND a.status = 'approved'
+                        AND a.deleted_at IS NULL),
+    approved_amount_cents = (SELECT COALESCE(SUM(a.committed_amount_cents), 0) FROM applications a
+                             WHERE a.scheme_id = schemes.id AND a.status = 'approved'
+                               AND a.deleted_at IS NULL);
@@ -0,0 +1,103 @@
+-- Initial schema: applicants, households, schemes, applications, users and audit logs
+-- SQLite version of mysql/0001_initial_schema.sql
+
+-- Applicants table
+CREATE TABLE applicants (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    employment_status TEXT NOT NULL CHECK (employment_status IN ('employed', 'unemployed')),
+    sex TEXT NOT NULL CHECK (sex IN ('male', 'female', 'other')),
+    date_of_birth DATE NOT NULL,
+    marital_status TEXT NOT NULL CHECK (marital_status IN ('single', 'married', 'widowed', 'divorced')),
+    monthly_income REAL NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL -- Set when soft-deleted
+);
+
+-- Household members table
+CREATE TABLE household_members (
+    id VARCHAR(36) PRIMARY KEY,
+    applicant_id VARCHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    employment_status TEXT NOT NULL CHECK (employment_status IN ('employed', 'unemployed')),
+    sex TEXT NOT NULL CHECK (sex IN ('male', 'female', 'other')),
+    date_of_birth DATE NOT NULL,
+    relation VARCHAR(50) NOT NULL, -- e.g., 'son', 'daughter', 'spouse', etc.
+    monthly_income REAL NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (applicant_id) REFERENCES applicants(id) ON DELETE CASCADE
+);
+
+-- Schemes table
+CREATE TABLE schemes (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL,
+    criteria TEXT NOT NULL, -- Store eligibility criteria as JSON
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Benefits table
+CREATE TABLE benefits (
+    id VARCHAR(36) PRIMARY KEY,
+    scheme_id VARCHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    amount REAL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (scheme_id) REFERENCES schemes(id) ON DELETE CASCADE
+);
+
+-- Applications table
+CREATE TABLE applications (
+    id VARCHAR(36) PRIMARY KEY,
+    applicant_id VARCHAR(36) NOT NULL,
+    scheme_id VARCHAR(36) NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    application_date TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    decision_date TIMESTAMP NULL,
+    notes TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL, -- Set when soft-deleted
+    FOREIGN KEY (applicant_id) REFERENCES applicants(id),
+    FOREIGN KEY (scheme_id) REFERENCES schemes(id)
+);
+
+-- Users table (staff who can log in to the API)
+CREATE TABLE users (
+    id VARCHAR(36) PRIMARY KEY,
+    username VARCHAR(100) NOT NULL UNIQUE,
+    password_hash VARCHAR(255) NOT NULL, -- bcrypt hash
+    role TEXT NOT NULL DEFAULT 'caseworker' CHECK (role IN ('admin', 'caseworker')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Audit logs table (who changed what, for compliance reviews)
+CREATE TABLE audit_logs (
+    id VARCHAR(36) PRIMARY KEY,
+    entity_type VARCHAR(50) NOT NULL, -- e.g., 'applicant', 'scheme', 'application'
+    entity_id VARCHAR(36) NOT NULL,
+    action VARCHAR(20) NOT NULL, -- e.g., 'create', 'update', 'delete'
+    actor_id VARCHAR(36),
+    actor_username VARCHAR(100),
+    before_data TEXT NULL,
+    after_data TEXT NULL,
+    changes TEXT NULL, -- Field-level changes: {"field": {"old": ..., "new": ...}}
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Indexes for performance
+CREATE INDEX idx_household_applicant ON household_members(applicant_id);
+CREATE INDEX idx_benefits_scheme ON benefits(scheme_id);
+CREATE INDEX idx_applications_applicant ON applications(applicant_id);
+CREATE INDEX idx_applications_scheme ON applications(scheme_id);
+CREATE INDEX idx_applicants_deleted ON applicants(deleted_at);
+CREATE INDEX idx_applications_deleted ON applications(deleted_at);
+CREATE INDEX idx_audit_entity ON audit_logs(entity_type, entity_id);
+CREATE INDEX idx_audit_created ON audit_logs(created_at);
@@ -0,0 +1,6 @@
+-- Who decided an application, why, and the benefit amount recommended on
+-- approval
+
+ALTER TABLE applications ADD COLUMN decided_by VARCHAR(36) NULL REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE applications ADD COLUMN decision_reason TEXT NULL;
+ALTER TABLE applications ADD COLUMN recommended_benefit_amount REAL NULL;
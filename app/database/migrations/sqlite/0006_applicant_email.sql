@@ -0,0 +1,5 @@
+-- Applicant email addresses for status notifications, with a per-applicant
+-- opt-out
+
+ALTER TABLE applicants ADD COLUMN email TEXT NULL;
+ALTER TABLE applicants ADD COLUMN email_opt_out BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,7 @@
+-- Names and dates of birth of applicants and household members are now
+-- AES-GCM encrypted by the application, so the columns hold ciphertext.
+-- Existing plaintext values stay readable and are encrypted by the
+-- "encryption rotate" command.
+--
+-- SQLite stores text in columns of any declared type, so the schema is
+-- unchanged.
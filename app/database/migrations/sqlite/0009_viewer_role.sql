@@ -0,0 +1,29 @@
+-- Read-only users, who receive data-minimized responses.
+--
+-- SQLite cannot change a CHECK constraint, so the users table is rebuilt.
+-- Dropping it sets applications.decided_by to NULL through its foreign key,
+-- so the decisions are saved and restored around the rebuild.
+
+CREATE TEMP TABLE saved_decisions AS
+    SELECT id, decided_by FROM applications WHERE decided_by IS NOT NULL;
+
+CREATE TABLE users_new (
+    id VARCHAR(36) PRIMARY KEY,
+    username VARCHAR(100) NOT NULL UNIQUE,
+    password_hash VARCHAR(255) NOT NULL, -- bcrypt hash
+    role TEXT NOT NULL DEFAULT 'caseworker' CHECK (role IN ('admin', 'caseworker', 'viewer')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO users_new (id, username, password_hash, role, created_at, updated_at)
+    SELECT id, username, password_hash, role, created_at, updated_at FROM users;
+
+DROP TABLE users;
+ALTER TABLE users_new RENAME TO users;
+
+UPDATE applications
+    SET decided_by = (SELECT s.decided_by FROM saved_decisions s WHERE s.id = applications.id)
+    WHERE id IN (SELECT id FROM saved_decisions);
+
+DROP TABLE saved_decisions;
@@ -0,0 +1,26 @@
+-- Background jobs, claimed and run by worker goroutines. Pending webhook
+-- deliveries are moved onto the queue, keeping their attempt counts.
+
+CREATE TABLE jobs (
+    id VARCHAR(36) PRIMARY KEY,
+    type VARCHAR(100) NOT NULL,
+    status TEXT NOT NULL DEFAULT 'queued' CHECK (status IN ('queued', 'running', 'succeeded', 'failed')),
+    payload TEXT NOT NULL,
+    result TEXT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NULL,
+    run_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- When a queued job is due, or a running job's claim expires
+    created_by VARCHAR(36) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP NULL,
+    finished_at TIMESTAMP NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_jobs_due ON jobs(status, run_at);
+
+INSERT INTO jobs (id, type, status, payload, attempts, run_at, created_at, updated_at)
+    SELECT id, 'webhook.deliver', 'queued', '{"delivery_id":"' || id || '"}', attempts, next_attempt_at, created_at, CURRENT_TIMESTAMP
+    FROM webhook_deliveries
+    WHERE status = 'pending';
@@ -0,0 +1,14 @@
+-- Flags raised on active applications whose applicant no longer meets the
+-- scheme's criteria when eligibility is re-evaluated
+
+CREATE TABLE review_flags (
+    id VARCHAR(36) PRIMARY KEY,
+    application_id VARCHAR(36) NOT NULL,
+    reason TEXT NOT NULL,
+    scheme_version INTEGER NULL, -- The scheme terms assessed against, if any were in effect
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP NULL, -- Set once a later evaluation finds the applicant eligible again
+    FOREIGN KEY (application_id) REFERENCES applications(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_review_flags_application ON review_flags(application_id, resolved_at);
@@ -0,0 +1,19 @@
+-- Supporting documents attached to applications, such as payslips and
+-- letters. The files are kept in the configured storage backend under
+-- storage_key.
+
+CREATE TABLE documents (
+    id VARCHAR(36) PRIMARY KEY,
+    application_id VARCHAR(36) NOT NULL,
+    filename VARCHAR(255) NOT NULL, -- As uploaded
+    content_type VARCHAR(100) NOT NULL,
+    size INTEGER NOT NULL, -- In bytes
+    sha256 CHAR(64) NOT NULL, -- Hex-encoded checksum of the content
+    storage_key VARCHAR(255) NOT NULL,
+    uploaded_by VARCHAR(36) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (application_id) REFERENCES applications(id) ON DELETE CASCADE,
+    FOREIGN KEY (uploaded_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_documents_application ON documents(application_id, created_at);
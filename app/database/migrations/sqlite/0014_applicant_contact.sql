@@ -0,0 +1,13 @@
+-- Applicant phone numbers and structured postal addresses for outreach and
+-- correspondence. The postal district is derived from the postal code when
+-- an applicant is saved, so applicants can be filtered by it.
+
+ALTER TABLE applicants ADD COLUMN phone VARCHAR(20) NULL;
+ALTER TABLE applicants ADD COLUMN address_block VARCHAR(10) NULL;
+ALTER TABLE applicants ADD COLUMN address_street VARCHAR(255) NULL;
+ALTER TABLE applicants ADD COLUMN address_unit VARCHAR(20) NULL;
+ALTER TABLE applicants ADD COLUMN address_building VARCHAR(255) NULL;
+ALTER TABLE applicants ADD COLUMN postal_code CHAR(6) NULL;
+ALTER TABLE applicants ADD COLUMN postal_district CHAR(2) NULL;
+
+CREATE INDEX idx_applicants_postal_district ON applicants(postal_district);
@@ -0,0 +1,16 @@
+-- Case notes recording caseworkers' interactions with an applicant, such as
+-- calls and home visits, kept separately from the notes of any application
+
+CREATE TABLE case_notes (
+    id VARCHAR(36) PRIMARY KEY,
+    applicant_id VARCHAR(36) NOT NULL,
+    author_id VARCHAR(36) NULL,
+    author_username VARCHAR(255) NULL, -- Kept if the author's account is removed
+    body TEXT NOT NULL,
+    tags TEXT NOT NULL, -- JSON array of lowercase tags
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (applicant_id) REFERENCES applicants(id) ON DELETE CASCADE,
+    FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_case_notes_applicant ON case_notes(applicant_id, created_at);
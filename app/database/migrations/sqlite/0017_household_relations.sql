@@ -0,0 +1,39 @@
+-- Household member relations become an enumeration. Existing free-text
+-- relations are mapped to it, with anything unrecognised becoming 'other'.
+--
+-- SQLite cannot add a CHECK constraint to a column, so the table is rebuilt.
+-- No other table references household members.
+
+CREATE TABLE household_members_new (
+    id VARCHAR(36) PRIMARY KEY,
+    applicant_id VARCHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL, -- AES-GCM encrypted
+    employment_status TEXT NOT NULL CHECK (employment_status IN ('employed', 'unemployed')),
+    sex TEXT NOT NULL CHECK (sex IN ('male', 'female', 'other')),
+    date_of_birth DATE NOT NULL, -- YYYY-MM-DD, AES-GCM encrypted
+    relation TEXT NOT NULL CHECK (relation IN ('spouse', 'son', 'daughter', 'parent', 'sibling', 'other')),
+    monthly_income REAL NOT NULL DEFAULT 0,
+    school_level TEXT NULL CHECK (school_level IN ('preschool', 'primary', 'secondary', 'tertiary', 'none')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (applicant_id) REFERENCES applicants(id) ON DELETE CASCADE
+);
+
+INSERT INTO household_members_new (id, applicant_id, name, employment_status, sex, date_of_birth,
+                                   relation, monthly_income, school_level, created_at, updated_at)
+    SELECT id, applicant_id, name, employment_status, sex, date_of_birth,
+        CASE
+            WHEN LOWER(TRIM(relation)) IN ('spouse', 'husband', 'wife') THEN 'spouse'
+            WHEN LOWER(TRIM(relation)) IN ('son', 'stepson', 'step-son') THEN 'son'
+            WHEN LOWER(TRIM(relation)) IN ('daughter', 'stepdaughter', 'step-daughter') THEN 'daughter'
+            WHEN LOWER(TRIM(relation)) IN ('parent', 'father', 'mother') THEN 'parent'
+            WHEN LOWER(TRIM(relation)) IN ('sibling', 'brother', 'sister') THEN 'sibling'
+            ELSE 'other'
+        END,
+        monthly_income, school_level, created_at, updated_at
+    FROM household_members;
+
+DROP TABLE household_members;
+ALTER TABLE household_members_new RENAME TO household_members;
+
+CREATE INDEX idx_household_applicant ON household_members(applicant_id);
@@ -0,0 +1,6 @@
+-- The period in which a scheme accepts applications, and whether it accepts
+-- them at all. Existing schemes stay open without a window.
+
+ALTER TABLE schemes ADD COLUMN open_date TIMESTAMP NULL;
+ALTER TABLE schemes ADD COLUMN close_date TIMESTAMP NULL;
+ALTER TABLE schemes ADD COLUMN is_active BOOLEAN NOT NULL DEFAULT TRUE;
@@ -0,0 +1,16 @@
+-- Optional limits on the number of approved applications of a scheme and on
+-- the benefit amount they commit, and the running totals checked against them
+-- on every approval. The totals start from the applications already approved.
+
+ALTER TABLE schemes ADD COLUMN max_applications INTEGER NULL;
+ALTER TABLE schemes ADD COLUMN budget REAL NULL;
+ALTER TABLE schemes ADD COLUMN approved_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE schemes ADD COLUMN approved_amount REAL NOT NULL DEFAULT 0;
+
+UPDATE schemes SET
+    approved_count = (SELECT COUNT(*) FROM applications a
+                      WHERE a.scheme_id = schemes.id AND a.status = 'approved'),
+    approved_amount = (SELECT COALESCE(SUM(COALESCE(a.recommended_benefit_amount,
+                          (SELECT COALESCE(SUM(b.amount), 0) FROM benefits b WHERE b.scheme_id = schemes.id))), 0)
+                       FROM applications a
+                       WHERE a.scheme_id = schemes.id AND a.status = 'approved');
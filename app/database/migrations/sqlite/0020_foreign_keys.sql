@@ -0,0 +1,6 @@
+-- The foreign keys of household members and benefits already cascade, and
+-- those of applications have no ON DELETE action, which SQLite enforces as
+-- RESTRICT does. Rebuilding applications to state it would cascade the drop to
+-- its documents and review flags, so the schema is left as it is; the MySQL
+-- migration of the same version names and restates the constraints.
+
@@ -0,0 +1,20 @@
+-- What applicants have consented to: sharing their data with partner
+-- agencies and being contacted through each channel. Each purpose has one
+-- row, replaced when consent is given again; withdrawn and expired consents
+-- are kept as a record.
+
+CREATE TABLE consents (
+    id VARCHAR(36) PRIMARY KEY,
+    applicant_id VARCHAR(36) NOT NULL,
+    purpose VARCHAR(50) NOT NULL, -- data_sharing, contact_email, contact_phone or contact_post
+    reference VARCHAR(255) NULL, -- Where the consent was given, e.g. a form number
+    granted_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NULL, -- Consent lapses at this time; never if NULL
+    withdrawn_at TIMESTAMP NULL,
+    recorded_by VARCHAR(36) NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_consents_applicant FOREIGN KEY (applicant_id) REFERENCES applicants(id) ON DELETE CASCADE,
+    CONSTRAINT fk_consents_recorded_by FOREIGN KEY (recorded_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE UNIQUE INDEX idx_consents_purpose ON consents(applicant_id, purpose);
@@ -0,0 +1,18 @@
+-- Amounts of money in whole cents instead of decimals, so that they and their
+-- totals are exact, and the ISO 4217 currency of each benefit. Existing
+-- amounts are converted and rounded to the cent.
+
+ALTER TABLE benefits ADD COLUMN amount_cents INTEGER NULL;
+ALTER TABLE benefits ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'SGD';
+UPDATE benefits SET amount_cents = CAST(ROUND(amount * 100) AS INTEGER);
+ALTER TABLE benefits DROP COLUMN amount;
+
+ALTER TABLE applications ADD COLUMN recommended_benefit_amount_cents INTEGER NULL;
+UPDATE applications SET recommended_benefit_amount_cents = CAST(ROUND(recommended_benefit_amount * 100) AS INTEGER);
+ALTER TABLE applications DROP COLUMN recommended_benefit_amount;
+
+ALTER TABLE schemes ADD COLUMN budget_cents INTEGER NULL;
+ALTER TABLE schemes ADD COLUMN approved_amount_cents INTEGER NOT NULL DEFAULT 0;
+UPDATE schemes SET budget_cents = CAST(ROUND(budget * 100) AS INTEGER), approved_amount_cents = CAST(ROUND(approved_amount * 100) AS INTEGER);
+ALTER TABLE schemes DROP COLUMN budget;
+ALTER TABLE schemes DROP COLUMN approved_amount;
@@ -0,0 +1,6 @@
+-- The caseworker, or admin, an application is assigned to for review, and
+-- when it was assigned
+
+ALTER TABLE applications ADD COLUMN assigned_to VARCHAR(36) NULL REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE applications ADD COLUMN assigned_at TIMESTAMP NULL;
+CREATE INDEX idx_applications_assigned_to ON applications(assigned_to, status);
@@ -0,0 +1,21 @@
+-- Internal comments on applications, kept apart from the application's notes.
+-- Replies refer to the comment starting their thread. Deleted comments keep
+-- their row, without the text, so that their replies stay in place.
+
+CREATE TABLE application_comments (
+    id VARCHAR(36) PRIMARY KEY,
+    application_id VARCHAR(36) NOT NULL,
+    parent_id VARCHAR(36) NULL, -- The comment replied to; NULL for the start of a thread
+    author_id VARCHAR(36) NULL,
+    author_username VARCHAR(255) NULL, -- Kept if the author's account is removed
+    body TEXT NOT NULL,
+    mentions JSON NOT NULL, -- Array of the usernames mentioned
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    edited_at TIMESTAMP NULL,
+    deleted_at TIMESTAMP NULL,
+    CONSTRAINT fk_application_comments_application FOREIGN KEY (application_id) REFERENCES applications(id) ON DELETE CASCADE,
+    CONSTRAINT fk_application_comments_parent FOREIGN KEY (parent_id) REFERENCES application_comments(id) ON DELETE CASCADE,
+    CONSTRAINT fk_application_comments_author FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_application_comments_application ON application_comments(application_id, created_at);
@@ -0,0 +1,16 @@
+-- One-time codes applicants sign in to the portal with. An applicant has at
+-- most one code at a time; requesting another replaces it, and it is deleted
+-- once used. Only a bcrypt hash of the code is kept.
+
+CREATE TABLE applicant_otps (
+    id VARCHAR(36) PRIMARY KEY,
+    applicant_id VARCHAR(36) NOT NULL,
+    channel VARCHAR(10) NOT NULL, -- email or sms
+    code_hash VARCHAR(255) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0, -- Wrong codes entered
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_applicant_otps_applicant FOREIGN KEY (applicant_id) REFERENCES applicants(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_applicant_otps_applicant ON applicant_otps(applicant_id);
@@ -0,0 +1,19 @@
+-- Attributes some agencies record about applicants beyond the built-in ones,
+-- such as housing type. custom_field_definitions is the registry of the
+-- fields this deployment uses, and each applicant's values are kept as one
+-- JSON object keyed by field.
+
+CREATE TABLE custom_field_definitions (
+    field_key VARCHAR(50) PRIMARY KEY, -- Lowercase letters, digits and underscores
+    label VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL, -- string, number, boolean or enum
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    options TEXT NULL, -- Allowed values of enum fields
+    min_value DOUBLE NULL, -- Bounds of number fields
+    max_value DOUBLE NULL,
+    max_length INT NULL, -- Longest value of string fields, in characters
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE applicants ADD COLUMN custom_fields TEXT NULL;
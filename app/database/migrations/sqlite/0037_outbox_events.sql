@@ -0,0 +1,15 @@
+-- Outbox of domain events, such as application.approved, to publish to a
+-- message broker. A row is added in the same transaction as the change the
+-- event describes, claimed by a relay while it publishes, and deleted once
+-- the broker has it.
+
+CREATE TABLE outbox_events (
+    sequence INTEGER PRIMARY KEY AUTOINCREMENT, -- Order the events happened in
+    id VARCHAR(36) NOT NULL UNIQUE, -- The event's ID, shared with its webhook deliveries
+    event VARCHAR(50) NOT NULL,
+    entity_id VARCHAR(36) NULL, -- The applicant or application the event is about, used as the message key
+    payload TEXT NOT NULL, -- The event as posted to webhooks
+    claimed_by VARCHAR(36) NULL, -- The relay publishing the event
+    claimed_until TIMESTAMP NULL, -- When the claim lapses and another relay may publish it
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,12 @@
+-- Settings of feature flags changed at runtime by admins, overriding the
+-- defaults from the configuration. A flag without a row here keeps its
+-- default.
+
+CREATE TABLE feature_flags (
+    name VARCHAR(100) PRIMARY KEY,
+    enabled BOOLEAN NOT NULL,
+    roles TEXT NULL, -- Roles the flag is on for when enabled; all roles when NULL
+    updated_by VARCHAR(36) NULL, -- The admin who last changed the flag
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
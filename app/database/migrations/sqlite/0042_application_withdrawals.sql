@@ -0,0 +1,21 @@
+-- Applications can be withdrawn by the applicant or a caseworker while
+-- pending or approved, recording when, by whom and why.
+--
+-- SQLite cannot change a CHECK constraint, and rebuilding applications would
+-- cascade the drop to its documents, comments and review flags (see 0020).
+-- Allowing another status only loosens the constraint, which SQLite permits
+-- by editing the stored schema. The edit comes after the columns are added,
+-- as ALTER TABLE splices them into the stored text at offsets it parsed
+-- earlier; adding them bumps the schema version, so other connections reload
+-- the schema, and RESET reloads it on this one.
+
+ALTER TABLE applications ADD COLUMN withdrawn_at TIMESTAMP NULL;
+ALTER TABLE applications ADD COLUMN withdrawn_by VARCHAR(36) NULL REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE applications ADD COLUMN withdrawal_reason TEXT NULL;
+
+PRAGMA writable_schema = ON;
+UPDATE sqlite_master
+    SET sql = replace(sql, 'CHECK (status IN (''pending'', ''approved'', ''rejected''))',
+                           'CHECK (status IN (''pending'', ''approved'', ''rejected'', ''withdrawn''))')
+    WHERE type = 'table' AND name = 'applications';
+PRAGMA writable_schema = RESET;
@@ -0,0 +1,17 @@
+-- Approvals record the amount they committed against their scheme's budget,
+-- so that withdrawing one releases exactly that amount even if the scheme's
+-- benefits have changed since. Existing approvals committed their
+-- recommended amount or, without one, their scheme's projected value, taken
+-- here as it is now.
+
+ALTER TABLE applications ADD COLUMN committed_amount_cents BIGINT NULL;
+
+UPDATE applications
+SET committed_amount_cents = COALESCE(recommended_benefit_amount_cents, (
+    SELECT COALESCE(SUM(amount_cents * CASE frequency
+        WHEN 'monthly' THEN COALESCE(duration_months, 1)
+        WHEN 'quarterly' THEN (COALESCE(duration_months, 1) + 2 - (COALESCE(duration_months, 1) + 2) % 3) / 3
+        ELSE 1 END), 0)
+    FROM benefits
+    WHERE benefits.scheme_id = applications.scheme_id))
+WHERE status = 'approved';
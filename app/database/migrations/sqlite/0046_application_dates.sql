@@ -0,0 +1,4 @@
+-- Application dates are calendar dates, the day an application was made;
+-- created_at keeps the time. Existing dates keep their day as written.
+
+UPDATE applications SET application_date = substr(COALESCE(application_date, created_at), 1, 10);
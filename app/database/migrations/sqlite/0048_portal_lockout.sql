@@ -0,0 +1,6 @@
+-- Portal sign-in attempts are counted for each applicant as well as for each
+-- code, so that requesting new codes does not allow more guesses: once too
+-- many attempts have failed, sign-in is locked until the count resets.
+
+ALTER TABLE applicants ADD COLUMN portal_failures INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE applicants ADD COLUMN portal_failures_reset_at TIMESTAMP NULL;
@@ -0,0 +1,351 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// ErrCircuitOpen is returned instead of contacting the database while the
+// circuit breaker is open
+var ErrCircuitOpen = errors.New("database unavailable: circuit breaker open")
+
+// RetryPolicy bounds how often a statement or connection attempt that failed
+// with a transient error is repeated
+type RetryPolicy struct {
+	MaxRetries  int           // Retries after the first attempt; 0 disables retries
+	BaseBackoff time.Duration // Delay before the first retry, doubled after each failure
+	MaxBackoff  time.Duration
+}
+
+// DefaultRetryPolicy is used when the configuration does not set one
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries:  2,
+	BaseBackoff: 50 * time.Millisecond,
+	MaxBackoff:  time.Second,
+}
+
+// backoff returns the delay before the given retry, counting from 1
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	delay := p.BaseBackoff
+	for i := 1; i < retry && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	return delay
+}
+
+// do runs fn until it succeeds, fails with an error retryable does not
+// accept, the retries are used up or ctx is done
+func (p RetryPolicy) do(ctx context.Context, retryable func(error) bool, fn func() error) error {
+	err := fn()
+	for retry := 1; retry <= p.MaxRetries && err != nil && retryable(err); retry++ {
+		timer := time.NewTimer(p.backoff(retry))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		err = fn()
+	}
+	return err
+}
+
+// Circuit breaker states
+const (
+	BreakerClosed   = "closed"    // Requests reach the database
+	BreakerOpen     = "open"      // Requests fail fast with ErrCircuitOpen
+	BreakerHalfOpen = "half_open" // One trial request is let through to decide whether to close
+)
+
+// Breaker is a circuit breaker over the connection pool. After Threshold
+// consecutive connection failures it opens, failing every request fast for
+// Cooldown; then a single trial request decides whether it closes again. Only
+// failures to reach the server are counted; SQL errors are answers.
+type Breaker struct {
+	Threshold int
+	Cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    string
+	failures int
+	openedAt time.Time
+	trial    bool // A half-open trial request is in flight
+}
+
+// NewBreaker creates a closed circuit breaker
+func NewBreaker(threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{Threshold: threshold, Cooldown: cooldown, state: BreakerClosed}
+}
+
+// BreakerStatus is a snapshot of a circuit breaker, as reported by /readyz
+type BreakerStatus struct {
+	State               string     `json:"state" example:"closed" enums:"closed,open,half_open"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"` // When the breaker last opened, while it is not closed
+}
+
+// Status returns the breaker's current state
+func (b *Breaker) Status() BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := BreakerStatus{State: b.currentState(time.Now()), ConsecutiveFailures: b.failures}
+	if status.State != BreakerClosed {
+		openedAt := b.openedAt
+		status.OpenedAt = &openedAt
+	}
+	return status
+}
+
+// currentState returns the state at now, which is half-open once an open
+// breaker's cooldown has passed. b.mu must be held.
+func (b *Breaker) currentState(now time.Time) string {
+	if b.state == BreakerOpen && now.Sub(b.openedAt) >= b.Cooldown {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+// allow returns ErrCircuitOpen if a request may not reach the database now
+func (b *Breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.currentState(time.Now()) {
+	case BreakerOpen:
+		return ErrCircuitOpen
+	case BreakerHalfOpen:
+		if b.trial {
+			return ErrCircuitOpen
+		}
+		b.state = BreakerHalfOpen
+		b.trial = true
+	}
+	return nil
+}
+
+// record counts the outcome of a request allowed through
+func (b *Breaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case isConnectionError(err):
+		b.failures++
+		if b.state == BreakerHalfOpen || b.failures >= b.Threshold {
+			b.state = BreakerOpen
+			b.openedAt = time.Now()
+		}
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded), errors.Is(err, driver.ErrSkip):
+		// Says nothing about the server; a half-open breaker tries again
+	default:
+		b.state = BreakerClosed
+		b.failures = 0
+	}
+	b.trial = false
+}
+
+// call runs fn if the breaker allows it and records its outcome
+func (b *Breaker) call(fn func() error) error {
+	if err := b.allow(); err != nil {
+		return err
+	}
+	err := fn()
+	b.record(err)
+	return err
+}
+
+// isConnectionError reports whether err means the server could not be reached
+// or the connection to it was lost
+func isConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var netErr net.Error
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn) || errors.As(err, &netErr) {
+		return true
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case 1040, 1053: // Too many connections, server shutting down
+			return true
+		}
+	}
+	return false
+}
+
+// isRetryableStatementError reports whether a statement failed without
+// effect, so running it again is safe outside a transaction
+func isRetryableStatementError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		switch mysqlErr.Number {
+		case 1205, 1213: // Lock wait timeout, deadlock: the statement was rolled back
+			return true
+		}
+	}
+	return false
+}
+
+// resilientConnector opens connections through the circuit breaker, retrying
+// failed attempts
+type resilientConnector struct {
+	driver.Connector
+	breaker *Breaker
+	retry   RetryPolicy
+}
+
+func (c *resilientConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	var conn driver.Conn
+	err := c.retry.do(ctx, isConnectionError, func() error {
+		return c.breaker.call(func() error {
+			var err error
+			conn, err = c.Connector.Connect(ctx)
+			return err
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &resilientConn{Conn: conn, breaker: c.breaker, retry: c.retry}, nil
+}
+
+// resilientConn runs statements through the circuit breaker, retrying those
+// outside a transaction that failed without effect. A query on a lost
+// connection is reported as driver.ErrBadConn, so database/sql runs it again
+// on another; other statements are not, as they may have been applied.
+type resilientConn struct {
+	driver.Conn
+	breaker *Breaker
+	retry   RetryPolicy
+	inTx    bool
+}
+
+func (c *resilientConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	var result driver.Result
+	err := c.statement(ctx, func() error {
+		var err error
+		result, err = execer.ExecContext(ctx, query, args)
+		return err
+	})
+	return result, err
+}
+
+func (c *resilientConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	var rows driver.Rows
+	err := c.statement(ctx, func() error {
+		var err error
+		rows, err = queryer.QueryContext(ctx, query, args)
+		return err
+	})
+	if !c.inTx && errors.Is(err, mysql.ErrInvalidConn) {
+		return nil, driver.ErrBadConn
+	}
+	return rows, err
+}
+
+// statement runs fn through the breaker, retrying outside transactions
+func (c *resilientConn) statement(ctx context.Context, fn func() error) error {
+	run := func() error { return c.breaker.call(fn) }
+	if c.inTx {
+		// A deadlock rolls back the whole transaction, so only the caller can
+		// retry it
+		return run()
+	}
+	return c.retry.do(ctx, isRetryableStatementError, run)
+}
+
+func (c *resilientConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var stmt driver.Stmt
+	err := c.breaker.call(func() error {
+		var err error
+		if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+			stmt, err = p.PrepareContext(ctx, query)
+		} else {
+			stmt, err = c.Conn.Prepare(query)
+		}
+		return err
+	})
+	return stmt, err
+}
+
+func (c *resilientConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	var tx driver.Tx
+	err := c.breaker.call(func() error {
+		var err error
+		if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+			tx, err = b.BeginTx(ctx, opts)
+		} else {
+			tx, err = c.Conn.Begin()
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.inTx = true
+	return &resilientTx{Tx: tx, conn: c}, nil
+}
+
+func (c *resilientConn) Ping(ctx context.Context) error {
+	pinger, ok := c.Conn.(driver.Pinger)
+	if !ok {
+		return nil
+	}
+	return c.breaker.call(func() error { return pinger.Ping(ctx) })
+}
+
+func (c *resilientConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *resilientConn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+func (c *resilientConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// resilientTx marks its connection as outside a transaction once it ends
+type resilientTx struct {
+	driver.Tx
+	conn *resilientConn
+}
+
+func (t *resilientTx) Commit() error {
+	t.conn.inTx = false
+	return t.conn.breaker.call(t.Tx.Commit)
+}
+
+func (t *resilientTx) Rollback() error {
+	t.conn.inTx = false
+	return t.Tx.Rollback()
+}
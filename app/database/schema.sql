@@ -1,29 +1,61 @@
 -- Schema
+-- Snapshot of the schema produced by app/database/migrations, plus sample data.
+-- Schema changes must be made as a new migration and mirrored here.
+
+-- Applied migrations (see app/database/migrations)
+CREATE TABLE schema_migrations (
+    version VARCHAR(255) PRIMARY KEY,
+    applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO schema_migrations (version) VALUES ('0001'), ('0002'), ('0003'), ('0004'), ('0005'), ('0006'), ('0007'), ('0008'), ('0009'), ('0010'), ('0011'), ('0012'), ('0013'), ('0014'), ('0015'), ('0016'), ('0017'), ('0018'), ('0019'), ('0020');
 
 -- Applicants table
 CREATE TABLE applicants (
     id VARCHAR(36) PRIMARY KEY,
-    name VARCHAR(255) NOT NULL,
+    name TEXT NOT NULL, -- AES-GCM encrypted
     employment_status ENUM('employed', 'unemployed') NOT NULL,
     sex ENUM('male', 'female', 'other') NOT NULL,
-    date_of_birth DATE NOT NULL,
+    date_of_birth VARCHAR(255) NOT NULL, -- YYYY-MM-DD, AES-GCM encrypted
     marital_status ENUM('single', 'married', 'widowed', 'divorced') NOT NULL,
+    monthly_income DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    version INT NOT NULL DEFAULT 1, -- Incremented on every update, for optimistic locking
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL, -- Set when soft-deleted
+    email VARCHAR(255) NULL, -- For status notifications
+    email_opt_out BOOLEAN NOT NULL DEFAULT FALSE, -- Set when the applicant declines notifications
+    identity_number_encrypted TEXT NULL, -- NRIC/FIN, AES-GCM encrypted
+    identity_number_hash CHAR(64) NULL, -- Keyed blind index of the NRIC/FIN, for lookups
+    phone VARCHAR(20) NULL,
+    address_block VARCHAR(10) NULL,
+    address_street VARCHAR(255) NULL,
+    address_unit VARCHAR(20) NULL,
+    address_building VARCHAR(255) NULL,
+    postal_code CHAR(6) NULL,
+    postal_district CHAR(2) NULL, -- Derived from the postal code, for filtering
+    anonymized_at TIMESTAMP NULL, -- Set when personal data is irreversibly replaced
+    custom_fields JSON NULL, -- Values of the fields in custom_field_definitions, keyed by field
+    portal_failures INT NOT NULL DEFAULT 0, -- Portal sign-in attempts since the last success
+    portal_failures_reset_at TIMESTAMP NULL -- When portal_failures starts again from zero
 );
 
 -- Household members table
 CREATE TABLE household_members (
     id VARCHAR(36) PRIMARY KEY,
     applicant_id VARCHAR(36) NOT NULL,
-    name VARCHAR(255) NOT NULL,
+    name TEXT NOT NULL, -- AES-GCM encrypted
     employment_status ENUM('employed', 'unemployed') NOT NULL,
     sex ENUM('male', 'female', 'other') NOT NULL,
-    date_of_birth DATE NOT NULL,
-    relation VARCHAR(50) NOT NULL, -- e.g., 'son', 'daughter', 'spouse', etc.
+    date_of_birth VARCHAR(255) NOT NULL, -- YYYY-MM-DD, AES-GCM encrypted
+    relation ENUM('spouse', 'son', 'daughter', 'parent', 'sibling', 'other') NOT NULL,
+    monthly_income DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    school_level ENUM('preschool', 'primary', 'secondary', 'tertiary', 'none') NULL, -- As declared; assessed by age if unset
+    identity_number_encrypted TEXT NULL, -- NRIC/FIN if declared, AES-GCM encrypted
+    identity_number_hash CHAR(64) NULL, -- Keyed blind index of the NRIC/FIN; not unique
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-    FOREIGN KEY (applicant_id) REFERENCES applicants(id) ON DELETE CASCADE
+    CONSTRAINT fk_household_members_applicant FOREIGN KEY (applicant_id) REFERENCES applicants(id) ON DELETE CASCADE
 );
 
 -- Schemes table
@@ -32,20 +64,73 @@ CREATE TABLE schemes (
     name VARCHAR(255) NOT NULL,
     description TEXT NOT NULL,
     criteria JSON NOT NULL, -- Store eligibility criteria as JSON
+    version INT NOT NULL DEFAULT 1, -- Incremented on every update, for optimistic locking
+    open_date TIMESTAMP NULL, -- Applications accepted from, if set
+    close_date TIMESTAMP NULL, -- Applications accepted until, if set
+    is_active BOOLEAN NOT NULL DEFAULT TRUE, -- Inactive schemes accept no applications
+    status VARCHAR(20) NOT NULL DEFAULT 'published', -- draft, published or archived; only published schemes accept applications
+    max_applications INT NULL, -- Limit on approved applications, if set
+    budget_cents BIGINT NULL, -- Limit on the benefit amount of approved applications, in cents, if set
+    approved_count INT NOT NULL DEFAULT 0, -- Approved applications, checked against max_applications
+    approved_amount_cents BIGINT NOT NULL DEFAULT 0, -- Benefit amount of approved applications in cents, checked against budget_cents
+    cooling_off_days INT NULL, -- Days after a rejection before the applicant can apply again, if set
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
 );
 
+-- Scheme versions table (effective-dated eligibility terms)
+CREATE TABLE scheme_versions (
+    scheme_id VARCHAR(36) NOT NULL,
+    version INT NOT NULL, -- Matches schemes.version when the terms were saved
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL,
+    criteria JSON NOT NULL,
+    effective_from TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    effective_to TIMESTAMP NULL, -- NULL while the version is the latest
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (scheme_id, version),
+    FOREIGN KEY (scheme_id) REFERENCES schemes(id) ON DELETE CASCADE
+);
+
+-- Scheme translations table (names and descriptions in zh, ms and ta)
+CREATE TABLE scheme_translations (
+    scheme_id VARCHAR(36) NOT NULL,
+    language VARCHAR(8) NOT NULL, -- zh, ms or ta
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (scheme_id, language),
+    CONSTRAINT fk_scheme_translations_scheme FOREIGN KEY (scheme_id) REFERENCES schemes(id) ON DELETE CASCADE
+);
+
 -- Benefits table
 CREATE TABLE benefits (
     id VARCHAR(36) PRIMARY KEY,
     scheme_id VARCHAR(36) NOT NULL,
     name VARCHAR(255) NOT NULL,
     description TEXT,
-    amount DECIMAL(10, 2),
+    amount_cents BIGINT NULL, -- In cents
+    currency CHAR(3) NOT NULL DEFAULT 'SGD', -- ISO 4217 code
+    frequency VARCHAR(20) NOT NULL DEFAULT 'one_off', -- one_off, monthly or quarterly
+    duration_months INT NULL, -- Months a recurring benefit is paid over
+    formula TEXT NULL, -- JSON formula scaling the amount with the household, if any
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-    FOREIGN KEY (scheme_id) REFERENCES schemes(id) ON DELETE CASCADE
+    CONSTRAINT fk_benefits_scheme FOREIGN KEY (scheme_id) REFERENCES schemes(id) ON DELETE CASCADE
+);
+
+-- Scheme checklist items table (what each application to the scheme is reviewed against)
+CREATE TABLE scheme_checklist_items (
+    id VARCHAR(36) PRIMARY KEY,
+    scheme_id VARCHAR(36) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NULL,
+    kind VARCHAR(20) NOT NULL DEFAULT 'other', -- document, interview or other
+    mandatory BOOLEAN NOT NULL DEFAULT FALSE, -- Must be completed before the application is approved
+    position INT NOT NULL DEFAULT 0, -- Items are listed by position, then name
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    CONSTRAINT fk_scheme_checklist_items_scheme FOREIGN KEY (scheme_id) REFERENCES schemes(id) ON DELETE CASCADE
 );
 
 -- Applications table
@@ -53,29 +138,417 @@ CREATE TABLE applications (
     id VARCHAR(36) PRIMARY KEY,
     applicant_id VARCHAR(36) NOT NULL,
     scheme_id VARCHAR(36) NOT NULL,
-    status ENUM('pending', 'approved', 'rejected') NOT NULL DEFAULT 'pending',
-    application_date TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    status ENUM('pending', 'approved', 'rejected', 'withdrawn') NOT NULL DEFAULT 'pending',
+    application_date DATE NOT NULL, -- The day the application was made; created_at has the time
     decision_date TIMESTAMP NULL,
     notes TEXT,
+    version INT NOT NULL DEFAULT 1, -- Incremented on every update, for optimistic locking
+    scheme_version INT NULL, -- Scheme version the application was assessed under
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-    FOREIGN KEY (applicant_id) REFERENCES applicants(id),
-    FOREIGN KEY (scheme_id) REFERENCES schemes(id)
+    deleted_at TIMESTAMP NULL, -- Set when soft-deleted
+    decided_by VARCHAR(36) NULL, -- User who approved or rejected the application
+    decision_reason TEXT NULL,
+    recommended_benefit_amount_cents BIGINT NULL,
+    committed_amount_cents BIGINT NULL, -- Amount an approval took up of the scheme's budget, released if withdrawn
+    assigned_to VARCHAR(36) NULL, -- User reviewing the application
+    assigned_at TIMESTAMP NULL,
+    withdrawn_at TIMESTAMP NULL,
+    withdrawn_by VARCHAR(36) NULL, -- Staff user who withdrew the application; NULL if the applicant did
+    withdrawal_reason TEXT NULL,
+    previous_application_id VARCHAR(36) NULL, -- The rejected application this one resubmits
+    -- Applicants and schemes cannot be deleted while applications refer to them
+    CONSTRAINT fk_applications_applicant FOREIGN KEY (applicant_id) REFERENCES applicants(id) ON DELETE RESTRICT,
+    CONSTRAINT fk_applications_scheme FOREIGN KEY (scheme_id) REFERENCES schemes(id) ON DELETE RESTRICT
+);
+
+-- Users table (staff who can log in to the API)
+CREATE TABLE users (
+    id VARCHAR(36) PRIMARY KEY,
+    username VARCHAR(100) NOT NULL UNIQUE,
+    password_hash VARCHAR(255) NOT NULL, -- bcrypt hash
+    role ENUM('admin', 'caseworker', 'viewer') NOT NULL DEFAULT 'caseworker', -- viewer is read-only
+    email VARCHAR(255) NULL, -- Where task reminders are sent
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+ALTER TABLE applications ADD CONSTRAINT fk_applications_decided_by FOREIGN KEY (decided_by) REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE applications ADD CONSTRAINT fk_applications_assigned_to FOREIGN KEY (assigned_to) REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE applications ADD CONSTRAINT fk_applications_withdrawn_by FOREIGN KEY (withdrawn_by) REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE applications ADD CONSTRAINT fk_applications_previous FOREIGN KEY (previous_application_id) REFERENCES applications(id) ON DELETE SET NULL;
+
+-- Audit logs table (who changed what, for compliance reviews)
+CREATE TABLE audit_logs (
+    id VARCHAR(36) PRIMARY KEY,
+    entity_type VARCHAR(50) NOT NULL, -- e.g., 'applicant', 'scheme', 'application'
+    entity_id VARCHAR(36) NOT NULL,
+    action VARCHAR(20) NOT NULL, -- e.g., 'create', 'update', 'delete'
+    actor_id VARCHAR(36),
+    actor_username VARCHAR(100),
+    before_data JSON NULL,
+    after_data JSON NULL,
+    changes JSON NULL, -- Field-level changes: {"field": {"old": ..., "new": ...}}
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Change events table (outbox of changes exported to the data warehouse)
+CREATE TABLE change_events (
+    sequence BIGINT AUTO_INCREMENT PRIMARY KEY, -- Order the changes were made in
+    id VARCHAR(36) NOT NULL UNIQUE, -- The audit entry's ID
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id VARCHAR(36) NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    actor_id VARCHAR(36) NULL,
+    data JSON NULL, -- The entity after the change, as audited; null for deletes
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Application events table (every transition of an application, replayable into its state)
+CREATE TABLE application_events (
+    sequence BIGINT AUTO_INCREMENT PRIMARY KEY, -- Order events happened in, across applications
+    id VARCHAR(36) NOT NULL UNIQUE,
+    application_id VARCHAR(36) NOT NULL,
+    version INT NOT NULL, -- 1 for an application's first event, counting up
+    type VARCHAR(20) NOT NULL, -- The audited action, such as create or approve
+    actor_id VARCHAR(36) NULL,
+    actor_username VARCHAR(100) NULL,
+    data JSON NULL, -- The fields the event set, or the whole application for create and anonymize events
+    occurred_at TIMESTAMP NOT NULL
+);
+
+-- Outbox events table (domain events waiting to be published to a message broker)
+CREATE TABLE outbox_events (
+    sequence BIGINT AUTO_INCREMENT PRIMARY KEY, -- Order the events happened in
+    id VARCHAR(36) NOT NULL UNIQUE, -- The event's ID, shared with its webhook deliveries
+    event VARCHAR(50) NOT NULL,
+    entity_id VARCHAR(36) NULL, -- The applicant or application the event is about, used as the message key
+    payload JSON NOT NULL, -- The event as posted to webhooks
+    claimed_by VARCHAR(36) NULL, -- The relay publishing the event
+    claimed_until TIMESTAMP NULL, -- When the claim lapses and another relay may publish it
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Webhooks table (subscriptions to application events)
+CREATE TABLE webhooks (
+    id VARCHAR(36) PRIMARY KEY,
+    url VARCHAR(2048) NOT NULL,
+    secret VARCHAR(255) NOT NULL, -- HMAC signing key shared with the receiver
+    events JSON NOT NULL, -- Subscribed event names
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+-- Webhook deliveries table (outbox of queued and attempted deliveries)
+CREATE TABLE webhook_deliveries (
+    id VARCHAR(36) PRIMARY KEY,
+    webhook_id VARCHAR(36) NOT NULL,
+    event_id VARCHAR(36) NOT NULL, -- Shared by the deliveries of one event
+    event VARCHAR(100) NOT NULL,
+    payload JSON NOT NULL,
+    status ENUM('pending', 'delivered', 'failed') NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT NULL,
+    delivered_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE
+);
+
+-- Search terms table (blind-indexed words of encrypted names)
+CREATE TABLE search_terms (
+    entity_type VARCHAR(20) NOT NULL, -- 'applicant' or 'household_member'
+    entity_id VARCHAR(36) NOT NULL,
+    term_hash CHAR(64) NOT NULL, -- Keyed hash of a lower-cased word
+    PRIMARY KEY (entity_type, entity_id, term_hash)
+);
+
+-- Jobs table (background job queue)
+CREATE TABLE jobs (
+    id VARCHAR(36) PRIMARY KEY,
+    type VARCHAR(100) NOT NULL,
+    status ENUM('queued', 'running', 'succeeded', 'failed') NOT NULL DEFAULT 'queued',
+    payload JSON NOT NULL,
+    result JSON NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NULL,
+    run_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- When a queued job is due, or a running job's claim expires
+    created_by VARCHAR(36) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP NULL,
+    finished_at TIMESTAMP NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Review flags table (applications whose applicant no longer meets the scheme's criteria)
+CREATE TABLE review_flags (
+    id VARCHAR(36) PRIMARY KEY,
+    application_id VARCHAR(36) NOT NULL,
+    reason TEXT NOT NULL,
+    scheme_version INT NULL, -- The scheme terms assessed against, if any were in effect
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP NULL, -- Set once a later evaluation finds the applicant eligible again
+    FOREIGN KEY (application_id) REFERENCES applications(id) ON DELETE CASCADE
+);
+
+-- Documents table (supporting documents attached to applications, stored outside the database)
+CREATE TABLE documents (
+    id VARCHAR(36) PRIMARY KEY,
+    application_id VARCHAR(36) NOT NULL,
+    filename VARCHAR(255) NOT NULL, -- As uploaded
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL, -- In bytes
+    sha256 CHAR(64) NOT NULL, -- Hex-encoded checksum of the content
+    storage_key VARCHAR(255) NOT NULL,
+    uploaded_by VARCHAR(36) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (application_id) REFERENCES applications(id) ON DELETE CASCADE,
+    FOREIGN KEY (uploaded_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Application checklist items table (the scheme's checklist as copied to an application, ticked off in review)
+CREATE TABLE application_checklist_items (
+    id VARCHAR(36) PRIMARY KEY,
+    application_id VARCHAR(36) NOT NULL,
+    checklist_item_id VARCHAR(36) NULL, -- The scheme's item this was copied from
+    name VARCHAR(255) NOT NULL,
+    description TEXT NULL,
+    kind VARCHAR(20) NOT NULL DEFAULT 'other',
+    mandatory BOOLEAN NOT NULL DEFAULT FALSE,
+    position INT NOT NULL DEFAULT 0,
+    completed_at TIMESTAMP NULL,
+    completed_by VARCHAR(36) NULL,
+    note TEXT NULL, -- The reviewer's note on completing the item
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    CONSTRAINT fk_application_checklist_items_application FOREIGN KEY (application_id) REFERENCES applications(id) ON DELETE CASCADE,
+    CONSTRAINT fk_application_checklist_items_item FOREIGN KEY (checklist_item_id) REFERENCES scheme_checklist_items(id) ON DELETE SET NULL,
+    CONSTRAINT fk_application_checklist_items_completed_by FOREIGN KEY (completed_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Case notes table (caseworkers' interaction history with an applicant)
+CREATE TABLE case_notes (
+    id VARCHAR(36) PRIMARY KEY,
+    applicant_id VARCHAR(36) NOT NULL,
+    author_id VARCHAR(36) NULL,
+    author_username VARCHAR(255) NULL, -- Kept if the author's account is removed
+    body TEXT NOT NULL,
+    tags JSON NOT NULL, -- Array of lowercase tags
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (applicant_id) REFERENCES applicants(id) ON DELETE CASCADE,
+    FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Applicant photos table (client card photos and thumbnails, stored outside the database)
+CREATE TABLE applicant_photos (
+    applicant_id VARCHAR(36) PRIMARY KEY,
+    content_type VARCHAR(100) NOT NULL, -- Of the uploaded image
+    size BIGINT NOT NULL, -- Of the uploaded image, in bytes
+    width INTEGER NOT NULL, -- In pixels
+    height INTEGER NOT NULL,
+    storage_key VARCHAR(255) NOT NULL,
+    thumbnail_key VARCHAR(255) NOT NULL,
+    thumbnail_content_type VARCHAR(100) NOT NULL,
+    uploaded_by VARCHAR(36) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_applicant_photos_applicant FOREIGN KEY (applicant_id) REFERENCES applicants(id) ON DELETE CASCADE,
+    CONSTRAINT fk_applicant_photos_uploaded_by FOREIGN KEY (uploaded_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Consents table (data sharing and contact channels an applicant agreed to)
+CREATE TABLE consents (
+    id VARCHAR(36) PRIMARY KEY,
+    applicant_id VARCHAR(36) NOT NULL,
+    purpose VARCHAR(50) NOT NULL, -- data_sharing, contact_email, contact_phone, contact_post or income_verification
+    reference VARCHAR(255) NULL, -- Where the consent was given, e.g. a form number
+    granted_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NULL, -- Consent lapses at this time; never if NULL
+    withdrawn_at TIMESTAMP NULL,
+    recorded_by VARCHAR(36) NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_consents_applicant FOREIGN KEY (applicant_id) REFERENCES applicants(id) ON DELETE CASCADE,
+    CONSTRAINT fk_consents_recorded_by FOREIGN KEY (recorded_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Application comments table (internal discussion of applications, in threads)
+CREATE TABLE application_comments (
+    id VARCHAR(36) PRIMARY KEY,
+    application_id VARCHAR(36) NOT NULL,
+    parent_id VARCHAR(36) NULL, -- The comment replied to; NULL for the start of a thread
+    author_id VARCHAR(36) NULL,
+    author_username VARCHAR(255) NULL, -- Kept if the author's account is removed
+    body TEXT NOT NULL,
+    mentions JSON NOT NULL, -- Array of the usernames mentioned
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    edited_at TIMESTAMP NULL,
+    deleted_at TIMESTAMP NULL,
+    CONSTRAINT fk_application_comments_application FOREIGN KEY (application_id) REFERENCES applications(id) ON DELETE CASCADE,
+    CONSTRAINT fk_application_comments_parent FOREIGN KEY (parent_id) REFERENCES application_comments(id) ON DELETE CASCADE,
+    CONSTRAINT fk_application_comments_author FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Tasks table (follow-ups for an applicant or application, by a due date)
+CREATE TABLE tasks (
+    id VARCHAR(36) PRIMARY KEY,
+    applicant_id VARCHAR(36) NOT NULL,
+    application_id VARCHAR(36) NULL,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NULL,
+    due_date TIMESTAMP NULL,
+    assigned_to VARCHAR(36) NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open', -- open, done or cancelled
+    created_by VARCHAR(36) NULL,
+    completed_at TIMESTAMP NULL, -- When the task was done or cancelled
+    reminded_at TIMESTAMP NULL, -- When the assignee was emailed a reminder; cleared when the due date changes
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    CONSTRAINT fk_tasks_applicant FOREIGN KEY (applicant_id) REFERENCES applicants(id) ON DELETE CASCADE,
+    CONSTRAINT fk_tasks_application FOREIGN KEY (application_id) REFERENCES applications(id) ON DELETE CASCADE,
+    CONSTRAINT fk_tasks_assigned_to FOREIGN KEY (assigned_to) REFERENCES users(id) ON DELETE SET NULL,
+    CONSTRAINT fk_tasks_created_by FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Referrals table (help for applicants delivered outside this system)
+CREATE TABLE referrals (
+    id VARCHAR(36) PRIMARY KEY,
+    applicant_id VARCHAR(36) NOT NULL,
+    referred_by VARCHAR(255) NOT NULL, -- The agency or officer making the referral
+    referred_to VARCHAR(255) NOT NULL, -- The agency referred to
+    scheme VARCHAR(255) NULL, -- The agency's scheme or service, if any
+    reason TEXT NOT NULL,
+    outcome VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, accepted, declined or completed
+    outcome_notes TEXT NULL,
+    outcome_at TIMESTAMP NULL, -- When the outcome was last set to other than pending
+    created_by VARCHAR(36) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    CONSTRAINT fk_referrals_applicant FOREIGN KEY (applicant_id) REFERENCES applicants(id) ON DELETE CASCADE,
+    CONSTRAINT fk_referrals_created_by FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Households table (shared by applicants of the same family; the head's household members are the household's)
+CREATE TABLE households (
+    id VARCHAR(36) PRIMARY KEY,
+    head_applicant_id VARCHAR(36) NOT NULL, -- The applicant the members' relations are to
+    created_by VARCHAR(36) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    CONSTRAINT fk_households_head FOREIGN KEY (head_applicant_id) REFERENCES applicants(id) ON DELETE CASCADE,
+    CONSTRAINT fk_households_created_by FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Household memberships table (other applicants in a household, for a period)
+CREATE TABLE household_memberships (
+    id VARCHAR(36) PRIMARY KEY,
+    household_id VARCHAR(36) NOT NULL,
+    applicant_id VARCHAR(36) NOT NULL,
+    relation VARCHAR(20) NOT NULL, -- The applicant's relation to the head, as for household members
+    start_date TIMESTAMP NOT NULL, -- A member from this day
+    end_date TIMESTAMP NULL, -- No longer a member from this day, if set
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    CONSTRAINT fk_household_memberships_household FOREIGN KEY (household_id) REFERENCES households(id) ON DELETE CASCADE,
+    CONSTRAINT fk_household_memberships_applicant FOREIGN KEY (applicant_id) REFERENCES applicants(id) ON DELETE CASCADE
+);
+
+-- Income records table (incomes retrieved for applicants from external sources)
+CREATE TABLE income_records (
+    id VARCHAR(36) PRIMARY KEY,
+    applicant_id VARCHAR(36) NOT NULL,
+    source VARCHAR(50) NOT NULL, -- The provider the income came from
+    monthly_income DECIMAL(10, 2) NOT NULL,
+    previous_monthly_income DECIMAL(10, 2) NOT NULL, -- The applicant's monthly_income before it was replaced
+    assessment_year INT NULL, -- The year the income was assessed for, if the source gives one
+    reference VARCHAR(100) NULL, -- The source's reference for the record, such as a notice number
+    retrieved_by VARCHAR(36) NULL,
+    retrieved_at TIMESTAMP NOT NULL,
+    CONSTRAINT fk_income_records_applicant FOREIGN KEY (applicant_id) REFERENCES applicants(id) ON DELETE CASCADE,
+    CONSTRAINT fk_income_records_retrieved_by FOREIGN KEY (retrieved_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Applicant OTPs table (one-time codes for signing in to the portal; one per applicant)
+CREATE TABLE applicant_otps (
+    id VARCHAR(36) PRIMARY KEY,
+    applicant_id VARCHAR(36) NOT NULL,
+    channel VARCHAR(10) NOT NULL, -- email or sms
+    code_hash VARCHAR(255) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0, -- Codes entered, right or wrong
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_applicant_otps_applicant FOREIGN KEY (applicant_id) REFERENCES applicants(id) ON DELETE CASCADE
+);
+
+-- Custom field definitions table (the registry of applicants' custom fields)
+CREATE TABLE custom_field_definitions (
+    field_key VARCHAR(50) PRIMARY KEY, -- Lowercase letters, digits and underscores
+    label VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL, -- string, number, boolean or enum
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    options JSON NULL, -- Allowed values of enum fields
+    min_value DOUBLE NULL, -- Bounds of number fields
+    max_value DOUBLE NULL,
+    max_length INT NULL, -- Longest value of string fields, in characters
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+-- Feature flags table (runtime overrides of the configured feature flags)
+CREATE TABLE feature_flags (
+    name VARCHAR(100) PRIMARY KEY,
+    enabled BOOLEAN NOT NULL,
+    roles JSON NULL, -- Roles the flag is on for when enabled; all roles when NULL
+    updated_by VARCHAR(36) NULL, -- The admin who last changed the flag
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
 );
 
 -- Indexes for performance
 CREATE INDEX idx_household_applicant ON household_members(applicant_id);
+CREATE INDEX idx_household_members_identity_number ON household_members(identity_number_hash);
+CREATE UNIQUE INDEX idx_households_head ON households(head_applicant_id);
+CREATE INDEX idx_household_memberships_household ON household_memberships(household_id);
+CREATE INDEX idx_household_memberships_applicant ON household_memberships(applicant_id);
+CREATE INDEX idx_income_records_applicant ON income_records(applicant_id, retrieved_at);
 CREATE INDEX idx_benefits_scheme ON benefits(scheme_id);
+CREATE INDEX idx_scheme_checklist_items_scheme ON scheme_checklist_items(scheme_id);
+CREATE INDEX idx_scheme_versions_effective ON scheme_versions(effective_from, effective_to);
 CREATE INDEX idx_applications_applicant ON applications(applicant_id);
 CREATE INDEX idx_applications_scheme ON applications(scheme_id);
+CREATE INDEX idx_applicants_deleted ON applicants(deleted_at);
+CREATE INDEX idx_applications_assigned_to ON applications(assigned_to, status);
+CREATE UNIQUE INDEX idx_applicants_identity_number ON applicants(identity_number_hash);
+CREATE INDEX idx_applicants_postal_district ON applicants(postal_district);
+CREATE INDEX idx_applications_deleted ON applications(deleted_at);
+CREATE INDEX idx_applications_previous ON applications(previous_application_id);
+CREATE INDEX idx_audit_entity ON audit_logs(entity_type, entity_id);
+CREATE INDEX idx_audit_created ON audit_logs(created_at);
+CREATE UNIQUE INDEX idx_application_events_version ON application_events(application_id, version);
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(status, next_attempt_at);
+CREATE INDEX idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, created_at);
+CREATE INDEX idx_search_terms_hash ON search_terms(term_hash, entity_type);
+CREATE INDEX idx_jobs_due ON jobs(status, run_at);
+CREATE INDEX idx_review_flags_application ON review_flags(application_id, resolved_at);
+CREATE INDEX idx_documents_application ON documents(application_id, created_at);
+CREATE INDEX idx_application_checklist_items_application ON application_checklist_items(application_id);
+CREATE INDEX idx_case_notes_applicant ON case_notes(applicant_id, created_at);
+CREATE UNIQUE INDEX idx_consents_purpose ON consents(applicant_id, purpose);
+CREATE INDEX idx_application_comments_application ON application_comments(application_id, created_at);
+CREATE INDEX idx_tasks_due ON tasks(status, due_date);
+CREATE INDEX idx_tasks_assigned_to ON tasks(assigned_to, status);
+CREATE INDEX idx_tasks_applicant ON tasks(applicant_id, status);
+CREATE UNIQUE INDEX idx_applicant_otps_applicant ON applicant_otps(applicant_id);
+CREATE INDEX idx_referrals_applicant ON referrals(applicant_id, created_at);
 
 -- Sample data for testing
 
--- Sample applicants
-INSERT INTO applicants (id, name, employment_status, sex, date_of_birth, marital_status)
+-- Sample applicants. Names and dates of birth stay in plaintext until the
+-- "encryption rotate" command encrypts them.
+INSERT INTO applicants (id, name, employment_status, sex, date_of_birth, marital_status, monthly_income)
 VALUES 
-('01913b7a-4493-74b2-93f8-e684c4ca935c', 'James', 'unemployed', 'male', '1990-07-01', 'single'),
-('01913b80-2c04-7f9d-86a4-497ef68cb3a0', 'Mary', 'unemployed', 'female', '1984-10-06', 'married');
+('01913b7a-4493-74b2-93f8-e684c4ca935c', 'James', 'unemployed', 'male', '1990-07-01', 'single', 0.00),
+('01913b80-2c04-7f9d-86a4-497ef68cb3a0', 'Mary', 'unemployed', 'female', '1984-10-06', 'married', 800.00);
 
 -- Sample household members
 INSERT INTO household_members (id, applicant_id, name, employment_status, sex, date_of_birth, relation)
@@ -89,8 +562,12 @@ VALUES
 ('01913b89-9a43-7163-8757-01cc254783f3', 'Retrenchment Assistance Scheme', 'Financial assistance for retrenched workers', '{"employment_status": "unemployed"}'),
 ('01913b89-befc-7ae3-bb37-3079aa7f1be0', 'Retrenchment Assistance Scheme (families)', 'Financial assistance for retrenched workers with primary school children', '{"employment_status": "unemployed", "has_children": {"school_level": "primary"}}');
 
+-- Sample scheme versions
+INSERT INTO scheme_versions (scheme_id, version, name, description, criteria, effective_from)
+SELECT id, version, name, description, criteria, created_at FROM schemes;
+
 -- Sample benefits
-INSERT INTO benefits (id, scheme_id, name, description, amount)
+INSERT INTO benefits (id, scheme_id, name, description, amount_cents)
 VALUES
-('01913b8b-9b12-7d2c-a1fa-ea613b802ebc', '01913b89-9a43-7163-8757-01cc254783f3', 'SkillsFuture Credits', 'Additional SkillsFuture credits for training', 500.00),
-('01913b8c-5d33-7e9a-b2fa-fb723c904def', '01913b89-befc-7ae3-bb37-3079aa7f1be0', 'School Meal Vouchers', 'Daily school meal vouchers for primary school children', 200.00); 
\ No newline at end of file
+('01913b8b-9b12-7d2c-a1fa-ea613b802ebc', '01913b89-9a43-7163-8757-01cc254783f3', 'SkillsFuture Credits', 'Additional SkillsFuture credits for training', 50000),
+('01913b8c-5d33-7e9a-b2fa-fb723c904def', '01913b89-befc-7ae3-bb37-3079aa7f1be0', 'School Meal Vouchers', 'Daily school meal vouchers for primary school children', 20000); 
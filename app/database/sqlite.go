@@ -0,0 +1,122 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"net/url"
+	"time"
+
+	"modernc.org/sqlite"
+)
+
+// sqliteDriverName is the name the UTC-normalizing SQLite driver is
+// registered under
+const sqliteDriverName = "sqlite-utc"
+
+func init() {
+	sql.Register(sqliteDriverName, utcDriver{&sqlite.Driver{}})
+}
+
+// openSQLite opens an in-process SQLite database at path. An empty path or
+// ":memory:" opens a private in-memory database that lives as long as the
+// connection pool.
+//
+// The repositories use "?" placeholders and portable SQL, so they run
+// unchanged; the differences are handled here. Times are stored as text in
+// SQLite and compared as strings, so, as the MySQL driver does, all time
+// arguments are converted to UTC before they are written.
+func openSQLite(path string) (*sql.DB, error) {
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Set("_time_format", "sqlite")
+
+	memory := path == "" || path == ":memory:"
+	var dsn string
+	if memory {
+		dsn = "file::memory:?" + params.Encode()
+	} else {
+		params.Add("_pragma", "journal_mode(WAL)")
+		params.Set("_txlock", "immediate")
+		dsn = "file:" + path + "?" + params.Encode()
+	}
+
+	db, err := sql.Open(sqliteDriverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("error opening database connection: %v", err)
+	}
+
+	if memory {
+		// Each connection to :memory: is a separate database, so keep exactly
+		// one open for the lifetime of the pool
+		db.SetMaxOpenConns(1)
+		db.SetConnMaxLifetime(0)
+		db.SetConnMaxIdleTime(0)
+	} else {
+		db.SetMaxOpenConns(10)
+		db.SetMaxIdleConns(5)
+	}
+
+	return db, nil
+}
+
+// utcDriver wraps the SQLite driver so its connections normalize time
+// arguments to UTC
+type utcDriver struct {
+	driver.Driver
+}
+
+func (d utcDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &utcConn{conn}, nil
+}
+
+// utcConn converts time.Time arguments to UTC. It only forwards the optional
+// interfaces needed for contexts and transactions; database/sql falls back to
+// prepared statements for everything else.
+type utcConn struct {
+	driver.Conn
+}
+
+func (c *utcConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if t, ok := nv.Value.(time.Time); ok {
+		nv.Value = t.UTC()
+	}
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+func (c *utcConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return b.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+func (c *utcConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return p.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c *utcConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *utcConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
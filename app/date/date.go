@@ -0,0 +1,137 @@
+// Package date represents calendar dates, such as dates of birth, which have
+// no time of day or time zone, so that they are read and written as
+// "2006-01-02" rather than as timestamps at midnight UTC.
+package date
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Layout is the format dates are written in and read from
+const Layout = time.DateOnly
+
+// Date is a calendar date, held as midnight UTC on that day. It is written to
+// JSON as a "2006-01-02" string and read from either such a string or an RFC
+// 3339 timestamp, whose date is taken as written. It is stored in databases
+// as a "2006-01-02" string, such as in DATE columns.
+type Date struct {
+	time.Time
+}
+
+// ErrInvalid is returned when a date cannot be parsed
+var ErrInvalid = errors.New("invalid date")
+
+// New returns the date of the given year, month and day. Out of range months
+// and days are normalized as by time.Date.
+func New(year int, month time.Month, day int) Date {
+	return Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// Of returns the date of t in its own time zone
+func Of(t time.Time) Date {
+	if t.IsZero() {
+		return Date{}
+	}
+	return New(t.Year(), t.Month(), t.Day())
+}
+
+// Parse reads a date such as "1990-07-01". An RFC 3339 timestamp, such as
+// "1990-07-01T00:00:00Z", is also accepted for clients that send dates of
+// birth as timestamps, and its time of day is dropped.
+func Parse(text string) (Date, error) {
+	s := strings.TrimSpace(text)
+	if t, err := time.Parse(Layout, s); err == nil {
+		return Of(t), nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return Of(t), nil
+	}
+	return Date{}, fmt.Errorf("%w %q: want a date such as 2006-01-02", ErrInvalid, text)
+}
+
+// String formats the date as "2006-01-02"
+func (d Date) String() string {
+	return d.Format(Layout)
+}
+
+// MarshalJSON writes the date as a "2006-01-02" string
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON reads the date from a string accepted by Parse. null leaves
+// the date unchanged.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "null" {
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("%w: want a string such as \"2006-01-02\"", ErrInvalid)
+	}
+	parsed, err := Parse(text)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalText writes the date as "2006-01-02", for encodings other than JSON
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText reads the date from text accepted by Parse
+func (d *Date) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Value stores the date as "2006-01-02", so that it is the same day whatever
+// the time zone of the connection
+func (d Date) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// Scan reads a date from a DATE or timestamp column, taking the date of a
+// timestamp in its own time zone. NULL is read as the zero date.
+func (d *Date) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*d = Date{}
+	case time.Time:
+		*d = Of(v)
+	case []byte:
+		return d.scanText(string(v))
+	case string:
+		return d.scanText(v)
+	default:
+		return fmt.Errorf("cannot scan %T into a date", src)
+	}
+	return nil
+}
+
+// scanText reads a date from a database's text representation of a date or
+// timestamp, such as "2006-01-02" or "2006-01-02 15:04:05"
+func (d *Date) scanText(s string) error {
+	if len(s) < len(Layout) {
+		return fmt.Errorf("cannot scan %q into a date", s)
+	}
+	t, err := time.Parse(Layout, s[:len(Layout)])
+	if err != nil {
+		return fmt.Errorf("cannot scan %q into a date: %v", s, err)
+	}
+	*d = Of(t)
+	return nil
+}
@@ -0,0 +1,374 @@
+// Package e2e runs the end-to-end scenario against the full HTTP surface: it
+// logs in, creates and publishes a scheme, creates an applicant, checks
+// eligibility, applies, is refused a duplicate application, approves, and
+// reads back the application, the coverage report and the applicant's
+// history. Each response is compared with a golden file in testdata, after
+// IDs, tokens and timestamps are replaced with placeholders.
+//
+// By default the test builds the server, creates a fresh SQLite database
+// with an admin user, starts the server on a free port and stops it
+// afterwards. With -url it targets a server that is already running, for
+// example on a MySQL database freshly migrated and seeded with only its
+// users:
+//
+//	go test ./app/e2e                      # from the repository root
+//	go test ./app/e2e -update              # rewrite the golden files
+//	go test ./app/e2e -url http://localhost:8080 -password admin123
+package e2e
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"one-client-view-2025tht/app/auth"
+	"one-client-view-2025tht/app/database"
+	"one-client-view-2025tht/app/models"
+)
+
+var (
+	baseURL  = flag.String("url", "", "base URL of a running server; by default one is started on a fresh SQLite database")
+	update   = flag.Bool("update", false, "rewrite the golden files with the responses instead of comparing them")
+	username = flag.String("username", "admin", "admin user to log in as")
+	password = flag.String("password", "e2e-password", "password of the admin user")
+)
+
+// serverPackage is the package of the server built when no -url is given
+const serverPackage = "one-client-view-2025tht/app"
+
+func TestScenario(t *testing.T) {
+	if testing.Short() {
+		t.Skip("end-to-end scenario skipped in short mode")
+	}
+
+	url := *baseURL
+	if url == "" {
+		url = startServer(t, *username, *password)
+	}
+
+	s := &scenario{
+		t:      t,
+		client: &client{baseURL: strings.TrimRight(url, "/"), http: &http.Client{Timeout: 30 * time.Second}},
+		golden: "testdata",
+		update: *update,
+		ids:    make(map[string]string),
+	}
+	s.run(*username, *password)
+}
+
+// startServer builds the server, creates its SQLite database in a temporary
+// directory with an admin user, and starts it on a free port until the test
+// ends. The server log is printed if the test fails. It returns the server's
+// URL.
+func startServer(t *testing.T, username, password string) string {
+	t.Helper()
+	dir := t.TempDir()
+
+	binary := filepath.Join(dir, "server")
+	build := exec.Command("go", "build", "-o", binary, serverPackage)
+	build.Stdout, build.Stderr = os.Stderr, os.Stderr
+	if err := build.Run(); err != nil {
+		t.Fatalf("error building server: %v", err)
+	}
+
+	// The schema is created by opening the database
+	dbPath := filepath.Join(dir, "e2e.db")
+	db, err := database.Initialize(&database.Config{Driver: database.DriverSQLite, Path: dbPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := auth.HashPassword(password)
+	if err == nil {
+		err = models.NewUserRepository(db.DB).Create(&models.User{Username: username, PasswordHash: hash, Role: auth.RoleAdmin})
+	}
+	db.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	port, err := freePort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		t.Fatal(err)
+	}
+
+	logFile, err := os.Create(filepath.Join(dir, "server.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := exec.Command(binary)
+	server.Dir = dir
+	server.Env = append(os.Environ(),
+		"DB_DRIVER=sqlite",
+		"SQLITE_PATH="+dbPath,
+		"PORT="+strconv.Itoa(port),
+		"JWT_SECRET="+base64.StdEncoding.EncodeToString(secret),
+		"ENCRYPTION_KEY="+base64.StdEncoding.EncodeToString(key),
+		"RATE_LIMIT_REQUESTS=0",
+		"CACHE_BACKEND=none",
+		"APP_ENV=development", // Hold every response to the OpenAPI document
+	)
+	server.Stdout, server.Stderr = logFile, logFile
+	if err := server.Start(); err != nil {
+		logFile.Close()
+		t.Fatalf("error starting server: %v", err)
+	}
+	t.Cleanup(func() {
+		server.Process.Signal(os.Interrupt)
+		server.Wait()
+		logFile.Close()
+		if t.Failed() {
+			if content, err := os.ReadFile(logFile.Name()); err == nil {
+				t.Logf("Server log:\n%s", content)
+			}
+		}
+	})
+
+	url := "http://127.0.0.1:" + strconv.Itoa(port)
+	if err := waitReady(url, 30*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	return url
+}
+
+// freePort returns a TCP port nothing is listening on
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// waitReady polls /readyz until the server is ready or the timeout passes
+func waitReady(url string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		resp, err := http.Get(url + "/readyz")
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return nil
+			}
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return errors.New("server did not become ready")
+}
+
+// client makes authenticated JSON requests to the API
+type client struct {
+	baseURL string
+	http    *http.Client
+	token   string
+}
+
+// do sends a request with an optional JSON body and returns the status and
+// decoded JSON body of the response
+func (c *client) do(method, path string, body interface{}) (int, interface{}, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, reader)
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	var decoded interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil && !errors.Is(err, io.EOF) {
+		return resp.StatusCode, nil, fmt.Errorf("%s %s: invalid JSON response: %v", method, path, err)
+	}
+	return resp.StatusCode, decoded, nil
+}
+
+// scenario runs the steps and compares their responses with golden files
+type scenario struct {
+	t      *testing.T
+	client *client
+	golden string
+	update bool
+	ids    map[string]string // Placeholders by the IDs they replace
+}
+
+func (s *scenario) run(username, password string) {
+	status, body, err := s.client.do("POST", "/api/v1/auth/login", map[string]string{"username": username, "password": password})
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	if status != http.StatusOK {
+		s.t.Fatalf("login: status %d: %v", status, body)
+	}
+	s.client.token, _ = body.(map[string]interface{})["token"].(string)
+
+	scheme := s.step("create-scheme", "POST", "/api/v1/schemes", http.StatusCreated, map[string]interface{}{
+		"name":        "E2E Retrenchment Support",
+		"description": "Support for retrenched workers",
+		"criteria":    map[string]interface{}{"employment_status": "unemployed"},
+		"benefits":    []map[string]interface{}{{"name": "Cash Grant", "amount": "300.00"}},
+	})
+	schemeID := field(scheme, "id")
+	s.step("publish-scheme", "POST", "/api/v1/schemes/"+schemeID+"/publish", http.StatusOK, nil)
+
+	applicant := s.step("create-applicant", "POST", "/api/v1/applicants", http.StatusCreated, map[string]interface{}{
+		"name":              "E2E Applicant",
+		"employment_status": "unemployed",
+		"sex":               "female",
+		"date_of_birth":     "1985-06-15",
+		"marital_status":    "single",
+		"monthly_income":    0,
+	})
+	applicantID := field(applicant, "id")
+
+	request := map[string]string{"applicant_id": applicantID, "scheme_id": schemeID}
+	s.step("eligible-schemes", "GET", "/api/v1/schemes/eligible?applicant="+applicantID, http.StatusOK, nil)
+	s.step("validate-application", "POST", "/api/v1/applications/validate", http.StatusOK, request)
+	application := s.step("create-application", "POST", "/api/v1/applications", http.StatusCreated, request)
+	s.step("duplicate-application", "POST", "/api/v1/applications", http.StatusConflict, request)
+	applicationID := field(application, "id")
+
+	s.step("approve-application", "POST", "/api/v1/applications/"+applicationID+"/approve", http.StatusOK, map[string]string{"reason": "Documents verified"})
+	s.step("get-application", "GET", "/api/v1/applications/"+applicationID, http.StatusOK, nil)
+	s.step("application-events", "GET", "/api/v1/applications/"+applicationID+"/events", http.StatusOK, nil)
+	s.step("scheme-coverage", "GET", "/api/v1/reports/schemes/"+schemeID+"/coverage", http.StatusOK, nil)
+	s.step("applicant-history", "GET", "/api/v1/applicants/"+applicantID+"/history", http.StatusOK, nil)
+}
+
+// step sends a request, checks its status and compares its normalized
+// response with the golden file of the step, failing the test if they
+// differ. It returns the response as received, for later steps to use, and
+// stops the test if the request fails, since later steps depend on it.
+func (s *scenario) step(name, method, path string, wantStatus int, body interface{}) interface{} {
+	s.t.Helper()
+	status, response, err := s.client.do(method, path, body)
+	if err != nil {
+		s.t.Fatalf("%s: %v", name, err)
+	}
+	if status != wantStatus {
+		s.t.Fatalf("%s: %s %s: status %d, want %d: %v", name, method, path, status, wantStatus, response)
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(s.normalize("", response)); err != nil {
+		s.t.Fatal(err)
+	}
+	got := buf.Bytes()
+
+	file := filepath.Join(s.golden, name+".json")
+	if s.update {
+		if err := os.WriteFile(file, got, 0o644); err != nil {
+			s.t.Fatal(err)
+		}
+		return response
+	}
+
+	want, err := os.ReadFile(file)
+	if err != nil {
+		s.t.Fatalf("%s: %v (run with -update to create it)", name, err)
+	}
+	if !bytes.Equal(got, want) {
+		s.t.Errorf("%s: response differs from %s\n--- want\n%s--- got\n%s", name, file, want, got)
+	}
+	return response
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// normalize replaces the values that differ between runs: each ID with a
+// placeholder numbered in order of appearance, so references between
+// responses are still checked, and tokens, application dates and timestamps
+// other than dates with fixed placeholders
+func (s *scenario) normalize(key string, value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		// In key order, so IDs are numbered the same on every run
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		out := make(map[string]interface{}, len(v))
+		for _, k := range keys {
+			out[k] = s.normalize(k, v[k])
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, child := range v {
+			out[i] = s.normalize(key, child)
+		}
+		return out
+	case string:
+		switch {
+		case key == "token" || key == "request_id":
+			return "<" + key + ">"
+		case key == "application_date":
+			return "<date>"
+		case uuidPattern.MatchString(v):
+			if _, ok := s.ids[v]; !ok {
+				s.ids[v] = fmt.Sprintf("<id-%d>", len(s.ids)+1)
+			}
+			return s.ids[v]
+		}
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil && !isDate(t) {
+			return "<time>"
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// isDate reports whether t is a calendar date, at midnight UTC
+func isDate(t time.Time) bool {
+	return t.Equal(t.UTC().Truncate(24 * time.Hour))
+}
+
+// field returns a string field of a JSON object
+func field(v interface{}, name string) string {
+	object, _ := v.(map[string]interface{})
+	value, _ := object[name].(string)
+	return value
+}
@@ -0,0 +1,245 @@
+// Package encryption protects sensitive column values at rest.
+//
+// Values are sealed with AES-256-GCM under a random nonce, so equal values
+// produce different ciphertexts. Columns that must be searched or kept unique
+// store a keyed HMAC-SHA256 "blind index" alongside the ciphertext, from which
+// the value cannot be recovered.
+//
+// A Cipher holds a primary key, used for all new values, and any number of
+// previous keys that are only used to decrypt. Each ciphertext records the ID
+// of the key that sealed it:
+//
+//	enc:v1:<key ID>:<base64 nonce and ciphertext>
+//
+// so rotating keys is a matter of making the new key primary, keeping the old
+// one as previous until every value has been re-encrypted, then dropping it.
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KeySize is the length in bytes of an encryption key
+const KeySize = 32
+
+// prefix marks a value as a ciphertext produced by Encrypt
+const prefix = "enc:v1:"
+
+// Cipher encrypts and indexes column values
+type Cipher struct {
+	primary *key
+	keys    []*key // The primary key first, then previous keys
+}
+
+// key is a single key ready for use
+type key struct {
+	id      string
+	aead    cipher.AEAD
+	hashKey []byte
+}
+
+// New creates a cipher that encrypts with the primary key and can also
+// decrypt values sealed with any of the previous keys. All keys must be
+// KeySize bytes.
+func New(primary []byte, previous ...[]byte) (*Cipher, error) {
+	c := &Cipher{}
+	seen := make(map[string]bool)
+	for i, material := range append([][]byte{primary}, previous...) {
+		k, err := newKey(material)
+		if err != nil {
+			if i == 0 {
+				return nil, err
+			}
+			return nil, fmt.Errorf("previous key %d: %v", i, err)
+		}
+		if seen[k.id] {
+			continue
+		}
+		seen[k.id] = true
+		c.keys = append(c.keys, k)
+	}
+	c.primary = c.keys[0]
+	return c, nil
+}
+
+func newKey(material []byte) (*key, error) {
+	if len(material) != KeySize {
+		return nil, fmt.Errorf("encryption key must be %d bytes, got %d", KeySize, len(material))
+	}
+
+	block, err := aes.NewCipher(material)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// The blind index uses a separate key derived from the encryption key
+	mac := hmac.New(sha256.New, material)
+	mac.Write([]byte("blind-index"))
+
+	return &key{id: KeyID(material), aead: aead, hashKey: mac.Sum(nil)}, nil
+}
+
+// KeyID returns the identifier recorded in values sealed with a key: the first
+// eight hex digits of its SHA-256 digest
+func KeyID(material []byte) string {
+	sum := sha256.Sum256(material)
+	return hex.EncodeToString(sum[:4])
+}
+
+// ParseKey decodes a base64-encoded key, as given in configuration
+func ParseKey(encoded string) ([]byte, error) {
+	material, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("encryption key must be base64-encoded: %v", err)
+	}
+	if len(material) != KeySize {
+		return nil, fmt.Errorf("encryption key must be %d bytes, got %d", KeySize, len(material))
+	}
+	return material, nil
+}
+
+// KeyProvider supplies the keys for a Cipher. Implementations may read them
+// from configuration or unwrap them with a key management service.
+type KeyProvider interface {
+	// Keys returns the primary key and any previous keys still needed to
+	// decrypt existing values
+	Keys(ctx context.Context) (primary []byte, previous [][]byte, err error)
+}
+
+// StaticKeys is a KeyProvider for base64-encoded keys given in configuration
+type StaticKeys struct {
+	Primary  string
+	Previous []string
+}
+
+// Keys decodes the configured keys
+func (s StaticKeys) Keys(ctx context.Context) ([]byte, [][]byte, error) {
+	primary, err := ParseKey(s.Primary)
+	if err != nil {
+		return nil, nil, err
+	}
+	previous := make([][]byte, 0, len(s.Previous))
+	for i, encoded := range s.Previous {
+		material, err := ParseKey(encoded)
+		if err != nil {
+			return nil, nil, fmt.Errorf("previous key %d: %v", i+1, err)
+		}
+		previous = append(previous, material)
+	}
+	return primary, previous, nil
+}
+
+// NewFromProvider creates a cipher with the keys supplied by p
+func NewFromProvider(ctx context.Context, p KeyProvider) (*Cipher, error) {
+	primary, previous, err := p.Keys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return New(primary, previous...)
+}
+
+// KeyID returns the ID of the primary key
+func (c *Cipher) KeyID() string {
+	return c.primary.id
+}
+
+// Encrypt seals plaintext with the primary key
+func (c *Cipher) Encrypt(plaintext string) (string, error) {
+	aead := c.primary.aead
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("error generating nonce: %v", err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return prefix + c.primary.id + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// ErrDecrypt is returned when a value cannot be decrypted, because it is
+// corrupt or was encrypted with a key the cipher does not have
+var ErrDecrypt = errors.New("unable to decrypt value")
+
+// Decrypt opens a value returned by Encrypt. Values written before key IDs
+// were recorded, which are bare base64, are tried against every key.
+func (c *Cipher) Decrypt(ciphertext string) (string, error) {
+	if !IsEncrypted(ciphertext) {
+		for _, k := range c.keys {
+			if plaintext, err := k.open(ciphertext); err == nil {
+				return plaintext, nil
+			}
+		}
+		return "", ErrDecrypt
+	}
+
+	id, sealed, ok := strings.Cut(strings.TrimPrefix(ciphertext, prefix), ":")
+	if !ok {
+		return "", ErrDecrypt
+	}
+	for _, k := range c.keys {
+		if k.id == id {
+			return k.open(sealed)
+		}
+	}
+	return "", fmt.Errorf("%w: unknown key %s", ErrDecrypt, id)
+}
+
+func (k *key) open(encoded string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < k.aead.NonceSize() {
+		return "", ErrDecrypt
+	}
+	nonce, sealed := sealed[:k.aead.NonceSize()], sealed[k.aead.NonceSize():]
+	plaintext, err := k.aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", ErrDecrypt
+	}
+	return string(plaintext), nil
+}
+
+// IsEncrypted reports whether a value was produced by Encrypt, as opposed to
+// plaintext stored before its column was encrypted
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// NeedsRotation reports whether a stored value is not yet sealed with the
+// primary key: it is plaintext, in the bare format or under a previous key
+func (c *Cipher) NeedsRotation(value string) bool {
+	return !strings.HasPrefix(value, prefix+c.primary.id+":")
+}
+
+// Hash returns the hex-encoded blind index of a value under the primary key.
+// Equal values always have equal hashes, so it can be used for lookups and
+// unique indexes.
+func (c *Cipher) Hash(value string) string {
+	return c.primary.hash(value)
+}
+
+// Hashes returns the blind index of a value under every key, primary first,
+// for lookups that must also match rows not yet re-indexed after a rotation
+func (c *Cipher) Hashes(value string) []string {
+	hashes := make([]string, len(c.keys))
+	for i, k := range c.keys {
+		hashes[i] = k.hash(value)
+	}
+	return hashes
+}
+
+func (k *key) hash(value string) string {
+	mac := hmac.New(sha256.New, k.hashKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
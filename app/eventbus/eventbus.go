@@ -0,0 +1,144 @@
+// Package eventbus publishes domain events, such as application.approved, to
+// a message broker, so other services can react to them.
+//
+// Where a change raises an event, models.WebhookRepository.Enqueue adds it to
+// the outbox_events outbox in the same transaction as the change. A Relay on
+// each server claims the oldest events in the outbox, publishes them in order
+// to a Broker and deletes them once the broker has them. An event is
+// published again if the server fails in between, so delivery is at least
+// once: consumers should skip events whose ID they have already seen.
+//
+// Each event is published as the JSON body posted to webhooks, under the
+// subject or topic of its name after a prefix, for example
+// "ocv.application.approved", keyed by the ID of the applicant or application
+// it is about.
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"one-client-view-2025tht/app/logging"
+	"one-client-view-2025tht/app/models"
+)
+
+// Message is an event to publish
+type Message struct {
+	ID      string // The event ID, for deduplication
+	Subject string // The NATS subject or Kafka topic
+	Key     string // The ID of the entity the event is about; may be empty
+	Body    []byte // The event as JSON
+}
+
+// Broker publishes messages to a message broker
+type Broker interface {
+	// Publish sends the messages in order, returning once the broker has
+	// accepted all of them
+	Publish(ctx context.Context, messages []Message) error
+	// Close releases the broker's connections
+	Close() error
+}
+
+// Open creates the broker at rawURL, which is one of
+//
+//	nats://[user:password@]host:4222   a NATS server (tls:// for TLS)
+//	kafka+https://[user:password@]host Kafka through a REST Proxy (kafka+http:// without TLS)
+//
+// A NATS token can be given as the user with no password.
+func Open(rawURL string) (Broker, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid event broker: %v", err)
+	}
+
+	switch u.Scheme {
+	case "nats", "tls":
+		return NewNATS(u), nil
+	case "kafka+http", "kafka+https":
+		return NewKafkaREST(u), nil
+	default:
+		return nil, fmt.Errorf("unsupported event broker %q: must be nats://, tls://, kafka+http:// or kafka+https://", rawURL)
+	}
+}
+
+// Relay publishes events from the outbox to a broker
+type Relay struct {
+	Repo         *models.OutboxRepository
+	Broker       Broker
+	Prefix       string        // Prepended to event names, with a dot, to make subjects
+	BatchSize    int           // Most events published at once
+	PollInterval time.Duration // Delay between checks of an empty outbox, doubled after each failure
+	MaxBackoff   time.Duration // Longest delay after failures
+	Lease        time.Duration // How long a batch is claimed for while it is published
+	Logger       *slog.Logger
+}
+
+// NewRelay creates a relay with default settings
+func NewRelay(repo *models.OutboxRepository, broker Broker, prefix string, batchSize int, pollInterval time.Duration) *Relay {
+	return &Relay{
+		Repo:         repo,
+		Broker:       broker,
+		Prefix:       prefix,
+		BatchSize:    batchSize,
+		PollInterval: pollInterval,
+		MaxBackoff:   time.Minute,
+		Lease:        time.Minute,
+		Logger:       logging.Component("eventbus"),
+	}
+}
+
+// Run publishes events until ctx is cancelled, then closes the broker
+func (r *Relay) Run(ctx context.Context) {
+	defer r.Broker.Close()
+
+	delay := r.PollInterval
+	for {
+		published, err := r.PublishNext(ctx)
+		switch {
+		case err != nil:
+			delay = min(delay*2, r.MaxBackoff)
+			r.Logger.Error("Event relay failed", "error", err, "retry_in", delay)
+		case published == r.BatchSize:
+			delay = r.PollInterval
+			continue
+		default:
+			delay = r.PollInterval
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// PublishNext publishes the oldest batch of events, returning how many were
+// published. A batch that fails is released to be published again.
+func (r *Relay) PublishNext(ctx context.Context) (int, error) {
+	events, token, err := r.Repo.Claim(r.BatchSize, r.Lease)
+	if err != nil || len(events) == 0 {
+		return 0, err
+	}
+
+	messages := make([]Message, len(events))
+	for i, e := range events {
+		messages[i] = Message{ID: e.ID, Subject: r.Prefix + "." + e.Event, Key: e.EntityID, Body: e.Payload}
+	}
+
+	publishCtx, cancel := context.WithTimeout(ctx, r.Lease)
+	defer cancel()
+	if err := r.Broker.Publish(publishCtx, messages); err != nil {
+		if releaseErr := r.Repo.Release(token); releaseErr != nil {
+			r.Logger.Error("Failed to release outbox events", "error", releaseErr)
+		}
+		return 0, fmt.Errorf("error publishing %d event(s): %v", len(messages), err)
+	}
+	if err := r.Repo.Delete(token); err != nil {
+		return 0, err
+	}
+	return len(events), nil
+}
@@ -0,0 +1,131 @@
+package eventbus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Content types of the Kafka REST Proxy v2 API
+const (
+	kafkaJSONContentType = "application/vnd.kafka.json.v2+json"
+	kafkaAcceptType      = "application/vnd.kafka.v2+json"
+)
+
+// KafkaREST publishes to Kafka through a REST Proxy speaking the v2 API, such
+// as the Confluent REST Proxy or the Redpanda HTTP Proxy. Messages are sent as
+// JSON records keyed by entity ID, so each applicant's or application's
+// events stay in order on one partition, one request per run of messages to
+// the same topic. Topics must exist unless the cluster creates them.
+type KafkaREST struct {
+	BaseURL  string // The proxy's URL, without the kafka+ prefix
+	Username string // Sent with basic authentication if set
+	Password string
+	Client   *http.Client
+}
+
+// NewKafkaREST creates a broker publishing through the REST Proxy at u, whose
+// scheme is kafka+http or kafka+https
+func NewKafkaREST(u *url.URL) *KafkaREST {
+	base := *u
+	base.Scheme = strings.TrimPrefix(u.Scheme, "kafka+")
+	base.User = nil
+	k := &KafkaREST{
+		BaseURL: strings.TrimRight(base.String(), "/"),
+		Client:  &http.Client{Timeout: 30 * time.Second},
+	}
+	if u.User != nil {
+		k.Username = u.User.Username()
+		k.Password, _ = u.User.Password()
+	}
+	return k
+}
+
+// kafkaRecord is a record produced through the REST Proxy
+type kafkaRecord struct {
+	Key   string          `json:"key,omitempty"`
+	Value json.RawMessage `json:"value"`
+}
+
+// kafkaProduceResponse reports for each produced record why it was not
+// written, if it was not
+type kafkaProduceResponse struct {
+	Offsets []struct {
+		ErrorCode *int   `json:"error_code"`
+		Error     string `json:"error"`
+	} `json:"offsets"`
+}
+
+// Publish produces the messages, in order
+func (k *KafkaREST) Publish(ctx context.Context, messages []Message) error {
+	for start := 0; start < len(messages); {
+		end := start + 1
+		for end < len(messages) && messages[end].Subject == messages[start].Subject {
+			end++
+		}
+		if err := k.produce(ctx, messages[start].Subject, messages[start:end]); err != nil {
+			return err
+		}
+		start = end
+	}
+	return nil
+}
+
+// produce sends messages to one topic in a single request
+func (k *KafkaREST) produce(ctx context.Context, topic string, messages []Message) error {
+	records := make([]kafkaRecord, len(messages))
+	for i, m := range messages {
+		records[i] = kafkaRecord{Key: m.Key, Value: m.Body}
+	}
+	body, err := json.Marshal(map[string]interface{}{"records": records})
+	if err != nil {
+		return fmt.Errorf("error encoding Kafka records: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, k.BaseURL+"/topics/"+url.PathEscape(topic), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", kafkaJSONContentType)
+	req.Header.Set("Accept", kafkaAcceptType)
+	if k.Username != "" {
+		req.SetBasicAuth(k.Username, k.Password)
+	}
+
+	resp, err := k.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending records to Kafka: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("error reading Kafka REST Proxy response: %v", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Kafka REST Proxy responded %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+
+	var result kafkaProduceResponse
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("error decoding Kafka REST Proxy response: %v", err)
+	}
+	for i, offset := range result.Offsets {
+		if offset.ErrorCode != nil || offset.Error != "" {
+			return fmt.Errorf("Kafka rejected record %d for topic %s: %s", i, topic, offset.Error)
+		}
+	}
+	return nil
+}
+
+// Close releases idle connections to the proxy
+func (k *KafkaREST) Close() error {
+	k.Client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,201 @@
+package eventbus
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NATS publishes to a NATS server over its client protocol. Messages carry
+// the event ID in the Nats-Msg-Id header, so JetStream streams capturing the
+// subjects drop duplicates within their deduplication window. Publish
+// returns once the server has acknowledged a PING sent after the messages,
+// so they have all been received.
+type NATS struct {
+	URL     *url.URL
+	Timeout time.Duration // Limit on connecting to the server
+
+	mu      sync.Mutex
+	conn    net.Conn
+	reader  *bufio.Reader
+	headers bool // Whether the server accepts messages with headers
+}
+
+// NewNATS creates a broker publishing to the NATS server at u. The
+// connection is opened when first needed and reopened after failures.
+func NewNATS(u *url.URL) *NATS {
+	return &NATS{URL: u, Timeout: 10 * time.Second}
+}
+
+// natsInfo is the part of the INFO a NATS server sends on connecting that
+// the client uses
+type natsInfo struct {
+	Headers     bool `json:"headers"`
+	TLSRequired bool `json:"tls_required"`
+}
+
+// natsConnect is the CONNECT a NATS client sends after the server's INFO
+type natsConnect struct {
+	Verbose   bool   `json:"verbose"`
+	Pedantic  bool   `json:"pedantic"`
+	Headers   bool   `json:"headers"`
+	Name      string `json:"name"`
+	Lang      string `json:"lang"`
+	Version   string `json:"version"`
+	Protocol  int    `json:"protocol"`
+	User      string `json:"user,omitempty"`
+	Pass      string `json:"pass,omitempty"`
+	AuthToken string `json:"auth_token,omitempty"`
+}
+
+// Publish sends the messages and waits for the server to confirm it has them
+func (n *NATS) Publish(ctx context.Context, messages []Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.conn == nil {
+		if err := n.connect(ctx); err != nil {
+			return err
+		}
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		n.conn.SetDeadline(deadline)
+	} else {
+		n.conn.SetDeadline(time.Time{})
+	}
+
+	var buf strings.Builder
+	for _, m := range messages {
+		if n.headers {
+			header := "NATS/1.0\r\nNats-Msg-Id: " + m.ID + "\r\n\r\n"
+			fmt.Fprintf(&buf, "HPUB %s %d %d\r\n%s%s\r\n", m.Subject, len(header), len(header)+len(m.Body), header, m.Body)
+		} else {
+			fmt.Fprintf(&buf, "PUB %s %d\r\n%s\r\n", m.Subject, len(m.Body), m.Body)
+		}
+	}
+	buf.WriteString("PING\r\n")
+
+	if _, err := n.conn.Write([]byte(buf.String())); err != nil {
+		n.close()
+		return fmt.Errorf("error writing to NATS: %v", err)
+	}
+	if err := n.awaitPong(); err != nil {
+		n.close()
+		return err
+	}
+	return nil
+}
+
+// connect opens the connection, upgrading it to TLS for tls:// URLs and
+// servers that require it, and introduces the client
+func (n *NATS) connect(ctx context.Context) error {
+	dialer := &net.Dialer{Timeout: n.Timeout}
+	host := n.URL.Host
+	if n.URL.Port() == "" {
+		host = net.JoinHostPort(n.URL.Hostname(), "4222")
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return fmt.Errorf("error connecting to NATS: %v", err)
+	}
+	conn.SetDeadline(time.Now().Add(n.Timeout))
+	reader := bufio.NewReader(conn)
+
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("error reading NATS INFO: %v", err)
+	}
+	payload, ok := strings.CutPrefix(strings.TrimSpace(line), "INFO ")
+	if !ok {
+		conn.Close()
+		return fmt.Errorf("unexpected NATS greeting %q", strings.TrimSpace(line))
+	}
+	var info natsInfo
+	if err := json.Unmarshal([]byte(payload), &info); err != nil {
+		conn.Close()
+		return fmt.Errorf("error decoding NATS INFO: %v", err)
+	}
+
+	if n.URL.Scheme == "tls" || info.TLSRequired {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: n.URL.Hostname()})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return fmt.Errorf("error starting TLS with NATS: %v", err)
+		}
+		conn = tlsConn
+		reader = bufio.NewReader(conn)
+	}
+
+	connect := natsConnect{Headers: info.Headers, Name: "one-client-view", Lang: "go", Version: "1.0", Protocol: 1}
+	if user := n.URL.User; user != nil {
+		if pass, ok := user.Password(); ok {
+			connect.User, connect.Pass = user.Username(), pass
+		} else {
+			connect.AuthToken = user.Username()
+		}
+	}
+	data, err := json.Marshal(connect)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("error encoding NATS CONNECT: %v", err)
+	}
+
+	n.conn, n.reader, n.headers = conn, reader, info.Headers
+	if _, err := conn.Write([]byte("CONNECT " + string(data) + "\r\nPING\r\n")); err != nil {
+		n.close()
+		return fmt.Errorf("error writing to NATS: %v", err)
+	}
+	if err := n.awaitPong(); err != nil {
+		n.close()
+		return err
+	}
+	return nil
+}
+
+// awaitPong reads until the server's PONG, answering its PINGs and
+// returning its errors
+func (n *NATS) awaitPong() error {
+	for {
+		line, err := n.reader.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("error reading from NATS: %v", err)
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err := n.conn.Write([]byte("PONG\r\n")); err != nil {
+				return fmt.Errorf("error writing to NATS: %v", err)
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return errors.New("NATS error: " + strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "-ERR")), "'"))
+		}
+		// +OK and INFO updates need no answer
+	}
+}
+
+// close drops the connection, so the next Publish reconnects
+func (n *NATS) close() {
+	if n.conn != nil {
+		n.conn.Close()
+		n.conn, n.reader = nil, nil
+	}
+}
+
+// Close closes the connection
+func (n *NATS) Close() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.close()
+	return nil
+}
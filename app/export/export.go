@@ -0,0 +1,183 @@
+// Package export ships changes to the data warehouse, so analytics can run
+// without access to the database.
+//
+// Every audited mutation adds a models.ChangeEvent to the change_events
+// outbox in the same transaction, holding only the fields of the changed
+// entity the warehouse reports on, and applicants' personal details only if
+// they consent to data sharing. On a schedule, an Exporter reads the outbox in order, writes the events in
+// batches as newline-delimited JSON to a Sink, and removes each batch once it
+// is written. A batch is written again if the server fails before removing
+// it, so delivery is at least once: consumers should skip events whose id
+// they have already loaded.
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/storage"
+)
+
+// ContentType is the media type of exported batches
+const ContentType = "application/x-ndjson"
+
+// Sink receives exported batches
+type Sink interface {
+	// Write stores a batch under name, replacing any batch of that name
+	Write(ctx context.Context, name string, data []byte) error
+}
+
+// OpenSink creates the sink at rawURL, which is one of
+//
+//	s3://bucket/prefix   objects in a bucket of the S3 service in s3
+//	file:///dir          files in a local directory
+//	https://host/path    POSTed to an HTTP endpoint, with token if not empty
+func OpenSink(ctx context.Context, rawURL string, s3 storage.Config, token string) (Sink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid export sink: %v", err)
+	}
+
+	switch u.Scheme {
+	case "s3":
+		s3.S3Bucket = u.Host
+		store, err := storage.NewS3(ctx, s3)
+		if err != nil {
+			return nil, err
+		}
+		return &StoreSink{Store: store, Prefix: strings.Trim(u.Path, "/")}, nil
+	case "file":
+		store, err := storage.NewLocal(u.Path)
+		if err != nil {
+			return nil, err
+		}
+		return &StoreSink{Store: store}, nil
+	case "http", "https":
+		return &HTTPSink{URL: rawURL, Token: token, Client: &http.Client{Timeout: time.Minute}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported export sink %q: must be s3://, file:// or http(s)://", rawURL)
+	}
+}
+
+// StoreSink writes each batch as an object of a storage.Store
+type StoreSink struct {
+	Store  storage.Store
+	Prefix string // Prepended to batch names, with a slash
+}
+
+// Write stores the batch
+func (s *StoreSink) Write(ctx context.Context, name string, data []byte) error {
+	if s.Prefix != "" {
+		name = s.Prefix + "/" + name
+	}
+	return s.Store.Put(ctx, name, bytes.NewReader(data), int64(len(data)), ContentType)
+}
+
+// HTTPSink POSTs each batch to an endpoint, with the batch's name in the
+// X-Export-Batch header. Any 2xx response is a success.
+type HTTPSink struct {
+	URL    string
+	Token  string // Sent as a bearer token if set
+	Client *http.Client
+}
+
+// Write sends the batch
+func (s *HTTPSink) Write(ctx context.Context, name string, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", ContentType)
+	req.Header.Set("X-Export-Batch", name)
+	if s.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.Token)
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending batch: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("export endpoint responded %s", resp.Status)
+	}
+	return nil
+}
+
+// Exporter moves events from the outbox to a sink, as the handler of
+// models.JobChangeExport jobs
+type Exporter struct {
+	Repo      *models.ChangeEventRepository
+	Sink      Sink
+	BatchSize int // Most events written per batch
+}
+
+// NewExporter creates an exporter writing batches of up to batchSize events
+func NewExporter(repo *models.ChangeEventRepository, sink Sink, batchSize int) *Exporter {
+	return &Exporter{Repo: repo, Sink: sink, BatchSize: batchSize}
+}
+
+// Run is the job handler exporting every pending event, in batches. Batches
+// written before a failure are kept; the rest are exported by the retry or
+// the next run.
+func (e *Exporter) Run(ctx context.Context, job *models.Job) (interface{}, error) {
+	result := models.ChangeExportResult{Batches: []string{}}
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		events, err := e.Repo.Pending(e.BatchSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(events) == 0 {
+			return result, nil
+		}
+
+		name, data, err := batch(events)
+		if err != nil {
+			return nil, err
+		}
+		if err := e.Sink.Write(ctx, name, data); err != nil {
+			return nil, fmt.Errorf("error writing batch %s: %v", name, err)
+		}
+		if err := e.Repo.DeleteThrough(events[len(events)-1].Sequence); err != nil {
+			return nil, err
+		}
+
+		result.Events += len(events)
+		result.Batches = append(result.Batches, name)
+		if len(events) < e.BatchSize {
+			return result, nil
+		}
+	}
+}
+
+// batch encodes events one per line. The name is derived from the date of
+// the first event and the range of sequence numbers, so a batch written again
+// replaces itself and names sort in the order of the events:
+//
+//	changes/2026/10/14/00000000000000000001-00000000000000000500.ndjson
+func batch(events []models.ChangeEvent) (string, []byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return "", nil, fmt.Errorf("error encoding change event: %v", err)
+		}
+	}
+
+	first, last := events[0], events[len(events)-1]
+	name := fmt.Sprintf("changes/%s/%020d-%020d.ndjson",
+		first.OccurredAt.UTC().Format("2006/01/02"), first.Sequence, last.Sequence)
+	return name, buf.Bytes(), nil
+}
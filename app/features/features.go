@@ -0,0 +1,193 @@
+// Package features gates risky new behaviors behind flags, so they can be
+// turned on for some roles before everyone, and off again without a redeploy
+// if they misbehave.
+//
+// Each flag has a built-in default, which the configuration can change for a
+// deployment. Admins can override either at runtime through the API, turning
+// a flag on or off, or on only for some roles. Overrides are kept in the
+// database, so they reach every replica; each replica reads them again once
+// its copy is older than the refresh interval.
+package features
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"one-client-view-2025tht/app/auth"
+	"one-client-view-2025tht/app/logging"
+	"one-client-view-2025tht/app/models"
+)
+
+// Feature flags
+const (
+	ApplicantStream    = "applicants.stream"
+	FormulaCommitments = "approvals.formula_commitments"
+)
+
+// Flag describes a feature flag
+type Flag struct {
+	Name        string
+	Description string
+	Default     bool // Whether the flag is on unless configured or overridden
+}
+
+// flags are the known flags, by name
+var flags = map[string]Flag{}
+
+func init() {
+	Register(Flag{Name: ApplicantStream, Description: "Serve GET /api/v1/applicants/stream", Default: true})
+	Register(Flag{Name: FormulaCommitments, Description: "Commit approvals' entitlements with benefit formulas applied against scheme budgets, rather than the base projected value of the scheme's benefits", Default: true})
+}
+
+// Register makes a new feature flag known. It is not safe for concurrent use
+// and should be called during initialization.
+func Register(flag Flag) {
+	flags[flag.Name] = flag
+}
+
+// Lookup returns the known flag with the given name
+func Lookup(name string) (Flag, bool) {
+	flag, ok := flags[name]
+	return flag, ok
+}
+
+// All returns the known flags, ordered by name
+func All() []Flag {
+	all := make([]Flag, 0, len(flags))
+	for _, flag := range flags {
+		all = append(all, flag)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+	return all
+}
+
+// Source reads the overrides admins have set
+type Source interface {
+	GetAll() ([]models.FeatureFlag, error)
+}
+
+// Set evaluates the known flags, with the configured defaults and the
+// overrides read from a source
+type Set struct {
+	Source   Source
+	Defaults map[string]bool // Whether each flag is on when not overridden
+	Refresh  time.Duration   // How long overrides are used before being read again
+	Logger   *slog.Logger
+
+	mu        sync.Mutex
+	overrides map[string]models.FeatureFlag
+	loadedAt  time.Time
+}
+
+// New creates a set of the known flags, with those listed in enabled and
+// disabled on or off by default rather than as built in
+func New(source Source, enabled, disabled []string, refresh time.Duration) (*Set, error) {
+	defaults := make(map[string]bool, len(flags))
+	for name, flag := range flags {
+		defaults[name] = flag.Default
+	}
+	for _, name := range enabled {
+		if _, ok := flags[name]; !ok {
+			return nil, fmt.Errorf("unknown feature flag %q", name)
+		}
+		defaults[name] = true
+	}
+	for _, name := range disabled {
+		if _, ok := flags[name]; !ok {
+			return nil, fmt.Errorf("unknown feature flag %q", name)
+		}
+		if slices.Contains(enabled, name) {
+			return nil, fmt.Errorf("feature flag %q is both enabled and disabled", name)
+		}
+		defaults[name] = false
+	}
+	return &Set{Source: source, Defaults: defaults, Refresh: refresh, Logger: logging.Component("features")}, nil
+}
+
+// Enabled reports whether a flag is on for the user making the request in
+// ctx. Unknown flags are off.
+func (s *Set) Enabled(ctx context.Context, name string) bool {
+	role := ""
+	if claims := auth.FromContext(ctx); claims != nil {
+		role = claims.Role
+	}
+	return s.EnabledFor(name, role)
+}
+
+// EnabledFor reports whether a flag is on for users with the given role
+func (s *Set) EnabledFor(name, role string) bool {
+	state, ok := s.State(name)
+	if !ok || !state.Enabled {
+		return false
+	}
+	return len(state.Roles) == 0 || slices.Contains(state.Roles, role)
+}
+
+// State describes a known flag and whether it is on
+func (s *Set) State(name string) (models.FeatureFlagState, bool) {
+	flag, ok := flags[name]
+	if !ok {
+		return models.FeatureFlagState{}, false
+	}
+	state := models.FeatureFlagState{
+		Name:        flag.Name,
+		Description: flag.Description,
+		Default:     s.Defaults[name],
+		Enabled:     s.Defaults[name],
+	}
+	if override, ok := s.current()[name]; ok {
+		state.Enabled = override.Enabled
+		state.Roles = override.Roles
+		state.Override = &override
+	}
+	return state, true
+}
+
+// States describes every known flag, ordered by name
+func (s *Set) States() []models.FeatureFlagState {
+	states := make([]models.FeatureFlagState, 0, len(flags))
+	for _, flag := range All() {
+		state, _ := s.State(flag.Name)
+		states = append(states, state)
+	}
+	return states
+}
+
+// Invalidate discards the overrides read, so the next evaluation reads them
+// again. Callers that change an override call it once the change is
+// committed; other replicas see it after their refresh interval.
+func (s *Set) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loadedAt = time.Time{}
+}
+
+// current returns the overrides, reading them again if they are older than
+// the refresh interval. If they cannot be read, the previous ones are used
+// until the next interval, or none if they have never been read.
+func (s *Set) current() map[string]models.FeatureFlag {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.loadedAt.IsZero() && time.Since(s.loadedAt) < s.Refresh {
+		return s.overrides
+	}
+	s.loadedAt = time.Now()
+
+	all, err := s.Source.GetAll()
+	if err != nil {
+		s.Logger.Error("Failed to read feature flags", "error", err)
+		return s.overrides
+	}
+	overrides := make(map[string]models.FeatureFlag, len(all))
+	for _, override := range all {
+		overrides[override.Name] = override
+	}
+	s.overrides = overrides
+	return overrides
+}
@@ -0,0 +1,261 @@
+// Package fixtures holds realistic demo data, embedded as JSON, and seeds it
+// into a database through the repositories: users, schemes with varied
+// criteria, applicants with households, and applications in every status.
+// Seeding is repeatable; records that already exist are skipped.
+package fixtures
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+
+	"one-client-view-2025tht/app/auth"
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/validation"
+)
+
+//go:embed data/*.json
+var data embed.FS
+
+// User is a demo user with its password in plaintext
+type User struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Role     string `json:"role"`
+	Email    string `json:"email,omitempty"` // Where task reminders are sent
+}
+
+// Application is a demo application, referring to its applicant by identity
+// number and its scheme by name
+type Application struct {
+	Applicant string `json:"applicant"`
+	Scheme    string `json:"scheme"`
+	Status    string `json:"status"`               // pending, approved or rejected
+	DecidedBy string `json:"decided_by,omitempty"` // Username of the user who decided it
+	Reason    string `json:"reason,omitempty"`
+}
+
+// Set is the demo data
+type Set struct {
+	Users        []User
+	Schemes      []models.Scheme
+	Applicants   []models.Applicant
+	Applications []Application
+}
+
+// Load reads the embedded demo data
+func Load() (*Set, error) {
+	set := &Set{}
+	if err := load("data/users.json", &set.Users); err != nil {
+		return nil, err
+	}
+	if err := load("data/applicants.json", &set.Applicants); err != nil {
+		return nil, err
+	}
+	if err := load("data/applications.json", &set.Applications); err != nil {
+		return nil, err
+	}
+
+	// Schemes are active unless the fixture says otherwise, as when created
+	// through the API, and published so the demo data can be applied for
+	var schemes []json.RawMessage
+	if err := load("data/schemes.json", &schemes); err != nil {
+		return nil, err
+	}
+	for i, raw := range schemes {
+		scheme := models.Scheme{IsActive: true, Status: models.SchemePublished}
+		if err := json.Unmarshal(raw, &scheme); err != nil {
+			return nil, fmt.Errorf("error reading scheme %d: %v", i, err)
+		}
+		set.Schemes = append(set.Schemes, scheme)
+	}
+	return set, nil
+}
+
+func load(name string, v interface{}) error {
+	content, err := data.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(content, v); err != nil {
+		return fmt.Errorf("error reading %s: %v", name, err)
+	}
+	return nil
+}
+
+// Repositories are those the demo data is written with
+type Repositories struct {
+	Users        *models.UserRepository
+	Applicants   *models.ApplicantRepository
+	Schemes      *models.SchemeRepository
+	Applications *models.ApplicationRepository
+	CustomFields *models.CustomFieldRepository // Defines the custom fields demo data is validated against
+}
+
+// Counts is the number of records of one kind created and skipped by Seed
+type Counts struct {
+	Created int
+	Skipped int
+}
+
+func (c Counts) String() string {
+	return fmt.Sprintf("%d created, %d skipped", c.Created, c.Skipped)
+}
+
+// Result is what Seed wrote
+type Result struct {
+	Users        Counts
+	Schemes      Counts
+	Applicants   Counts
+	Applications Counts
+}
+
+// Seed writes the demo data. Schemes with the same name, applicants with the
+// same identity number and applications for a scheme the applicant has
+// already applied for are skipped. Users are only created in a
+// database with none, so the demo passwords never reach an environment with
+// real accounts.
+func (s *Set) Seed(repos Repositories) (Result, error) {
+	var result Result
+	if err := s.seedUsers(repos, &result); err != nil {
+		return result, err
+	}
+	fields, err := repos.CustomFields.GetAll()
+	if err != nil {
+		return result, err
+	}
+	schemeIDs, err := s.seedSchemes(repos, fields, &result)
+	if err != nil {
+		return result, err
+	}
+	applicantIDs, err := s.seedApplicants(repos, fields, &result)
+	if err != nil {
+		return result, err
+	}
+	return result, s.seedApplications(repos, schemeIDs, applicantIDs, &result)
+}
+
+func (s *Set) seedUsers(repos Repositories, result *Result) error {
+	count, err := repos.Users.Count()
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		result.Users.Skipped = len(s.Users)
+		return nil
+	}
+
+	for _, u := range s.Users {
+		switch u.Role {
+		case auth.RoleAdmin, auth.RoleCaseworker, auth.RoleViewer:
+		default:
+			return fmt.Errorf("user %s: invalid role %q", u.Username, u.Role)
+		}
+		hash, err := auth.HashPassword(u.Password)
+		if err != nil {
+			return err
+		}
+		if err := repos.Users.Create(&models.User{Username: u.Username, PasswordHash: hash, Role: u.Role, Email: u.Email}); err != nil {
+			return fmt.Errorf("user %s: %v", u.Username, err)
+		}
+		result.Users.Created++
+	}
+	return nil
+}
+
+// seedSchemes returns the IDs of the demo schemes by name
+func (s *Set) seedSchemes(repos Repositories, fields []models.CustomField, result *Result) (map[string]string, error) {
+	existing, err := repos.Schemes.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]string)
+	for _, scheme := range existing {
+		ids[scheme.Name] = scheme.ID
+	}
+
+	for i := range s.Schemes {
+		scheme := s.Schemes[i]
+		if _, ok := ids[scheme.Name]; ok {
+			result.Schemes.Skipped++
+			continue
+		}
+		if err := validation.Scheme(&scheme, fields); err != nil {
+			return nil, fmt.Errorf("scheme %s: %v", scheme.Name, err)
+		}
+		if err := repos.Schemes.Create(&scheme); err != nil {
+			return nil, fmt.Errorf("scheme %s: %v", scheme.Name, err)
+		}
+		ids[scheme.Name] = scheme.ID
+		result.Schemes.Created++
+	}
+	return ids, nil
+}
+
+// seedApplicants returns the IDs of the demo applicants by identity number
+func (s *Set) seedApplicants(repos Repositories, fields []models.CustomField, result *Result) (map[string]string, error) {
+	ids := make(map[string]string)
+	for i := range s.Applicants {
+		applicant := s.Applicants[i]
+		existing, err := repos.Applicants.GetByIdentityNumber(applicant.IdentityNumber)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			ids[applicant.IdentityNumber] = existing.ID
+			result.Applicants.Skipped++
+			continue
+		}
+		if err := validation.Applicant(&applicant, fields); err != nil {
+			return nil, fmt.Errorf("applicant %s: %v", applicant.IdentityNumber, err)
+		}
+		if err := repos.Applicants.Create(&applicant); err != nil {
+			return nil, fmt.Errorf("applicant %s: %v", applicant.IdentityNumber, err)
+		}
+		ids[applicant.IdentityNumber] = applicant.ID
+		result.Applicants.Created++
+	}
+	return ids, nil
+}
+
+func (s *Set) seedApplications(repos Repositories, schemeIDs, applicantIDs map[string]string, result *Result) error {
+	for _, a := range s.Applications {
+		label := a.Applicant + " for " + a.Scheme
+		application := models.Application{ApplicantID: applicantIDs[a.Applicant], SchemeID: schemeIDs[a.Scheme]}
+		if application.ApplicantID == "" || application.SchemeID == "" {
+			return fmt.Errorf("application by %s: unknown applicant or scheme", label)
+		}
+
+		// Decided applications would not stop another being created
+		existing, err := repos.Applications.Find(models.ApplicationFilter{ApplicantID: application.ApplicantID, SchemeID: application.SchemeID})
+		if err != nil {
+			return err
+		}
+		if len(existing) > 0 {
+			result.Applications.Skipped++
+			continue
+		}
+		if err := repos.Applications.Create(&application); err != nil {
+			return fmt.Errorf("application by %s: %v", label, err)
+		}
+		result.Applications.Created++
+
+		if a.Status == "" || a.Status == "pending" {
+			continue
+		}
+		var decidedBy string
+		if a.DecidedBy != "" {
+			user, err := repos.Users.GetByUsername(a.DecidedBy)
+			if err != nil {
+				return err
+			}
+			if user != nil {
+				decidedBy = user.ID
+			}
+		}
+		if err := repos.Applications.Decide(&application, a.Status, decidedBy, a.Reason, nil); err != nil {
+			return fmt.Errorf("application by %s: %v", label, err)
+		}
+	}
+	return nil
+}
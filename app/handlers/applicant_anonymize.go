@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/auth"
+	"one-client-view-2025tht/app/logging"
+	"one-client-view-2025tht/app/models"
+)
+
+// AnonymizeApplicant handles POST /api/v1/applicants/{id}/anonymize
+// @Summary Anonymize an applicant
+// @Description Irreversibly replace the personal data of an applicant, including a deleted one, to honour a data-protection request. Names become random tokens and dates of birth 1 January of the birth year, for the applicant and their household; the NRIC, email, phone and address apart from the postal district are removed, case notes and application notes are replaced, and documents and the photo are deleted. Sex, marital and employment status, incomes, relations and applications are kept for statistics. Snapshots of the data are dropped from the audit log, which records the anonymization. Anonymized applicants cannot be changed. Requires the admin role.
+// @Tags applicants
+// @Produce json
+// @Param id path string true "Applicant ID"
+// @Success 200 {object} models.ApplicantResponse
+// @Failure 403 {object} apierrors.APIError "Requires the admin role"
+// @Failure 404 {object} apierrors.APIError "Applicant not found"
+// @Failure 409 {object} apierrors.APIError "Applicant has already been anonymized"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applicants/{id}/anonymize [post]
+func (h *ApplicantHandler) AnonymizeApplicant(w http.ResponseWriter, r *http.Request) {
+	if !hasRole(r, auth.RoleAdmin) {
+		apierrors.Write(w, r, apierrors.Forbidden("Anonymizing applicants requires the admin role"))
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	var keys []string
+	err := models.WithTx(h.ApplicantRepo.DB, func(tx *sql.Tx) error {
+		var err error
+		if keys, err = h.ApplicantRepo.WithTx(tx).Anonymize(id); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityApplicant, id,
+			models.AuditActionAnonymize, actorFrom(r), nil, nil)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		apierrors.Write(w, r, apierrors.NotFound("Applicant not found"))
+		return
+	}
+	if errors.Is(err, models.ErrAnonymized) {
+		apierrors.Write(w, r, apierrors.Conflict("Applicant has already been anonymized"))
+		return
+	}
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to anonymize applicant", err))
+		return
+	}
+	h.ApplicantCache.Invalidate(id)
+
+	// The records are gone, so a failure here only leaves unreachable files
+	for _, key := range keys {
+		if err := h.Store.Delete(r.Context(), key); err != nil {
+			logging.FromContext(r.Context()).Error("Failed to delete file of anonymized applicant", "key", key, "error", err)
+		}
+	}
+
+	anonymized, err := h.ApplicantRepo.GetByIDIncludingDeleted(id)
+	if err != nil || anonymized == nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get anonymized applicant", err))
+		return
+	}
+
+	response := models.ApplicantResponse{
+		Applicant: *anonymized,
+		Household: anonymized.Household,
+	}
+
+	setETag(w, anonymized.Version)
+	writeJSON(w, r, http.StatusOK, response)
+}
@@ -1,39 +1,85 @@
 package handlers
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
-	"time"
+	"strings"
 
 	"github.com/gorilla/mux"
 
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/auth"
+	"one-client-view-2025tht/app/date"
+	"one-client-view-2025tht/app/mergepatch"
 	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/storage"
+	"one-client-view-2025tht/app/validation"
 )
 
 // ApplicantHandler handles HTTP requests related to applicants
 type ApplicantHandler struct {
-	ApplicantRepo *models.ApplicantRepository
+	ApplicantRepo   *models.ApplicantRepository
+	ApplicantCache  *models.CachedApplicantStore // Invalidated on every change to an applicant
+	ApplicationRepo *models.ApplicationRepository
+	AuditRepo       *models.AuditRepository
+	WebhookRepo     *models.WebhookRepository
+	JobRepo         *models.JobRepository
+	CustomFieldRepo *models.CustomFieldRepository // Defines the custom fields applicants are validated against
+	Store           storage.Store                 // Holds the documents and photos removed by anonymization
 }
 
-// NewApplicantHandler creates a new handler with the given repository
-func NewApplicantHandler(repo *models.ApplicantRepository) *ApplicantHandler {
-	return &ApplicantHandler{ApplicantRepo: repo}
+// NewApplicantHandler creates a new handler with the given repositories and
+// store
+func NewApplicantHandler(repo *models.ApplicantRepository, applicantCache *models.CachedApplicantStore, applicationRepo *models.ApplicationRepository, auditRepo *models.AuditRepository, webhookRepo *models.WebhookRepository, jobRepo *models.JobRepository, customFieldRepo *models.CustomFieldRepository, store storage.Store) *ApplicantHandler {
+	return &ApplicantHandler{
+		ApplicantRepo:   repo,
+		ApplicantCache:  applicantCache,
+		ApplicationRepo: applicationRepo,
+		AuditRepo:       auditRepo,
+		WebhookRepo:     webhookRepo,
+		JobRepo:         jobRepo,
+		CustomFieldRepo: customFieldRepo,
+		Store:           store,
+	}
 }
 
-// GetApplicants handles GET /api/applicants
+// GetApplicants handles GET /api/v1/applicants
 // @Summary Get all applicants
-// @Description Retrieve a list of all applicants with their household members
+// @Description Retrieve a list of applicants with their household members, optionally filtered. Custom fields are filtered with custom.{key}={value} query parameters, such as custom.dialect=hokkien, matching strings and enum values case-insensitively and numbers and booleans by value.
 // @Tags applicants
 // @Accept json
 // @Produce json
+// @Param name query string false "Partial, case-insensitive match on name"
+// @Param employment_status query string false "Employment status" Enums(employed, unemployed)
+// @Param marital_status query string false "Marital status" Enums(single, married, widowed, divorced)
+// @Param sex query string false "Sex" Enums(male, female, other)
+// @Param postal_district query string false "Two-digit postal district of the applicant's address, 01 to 28"
+// @Param min_age query int false "Minimum age in years"
+// @Param max_age query int false "Maximum age in years"
+// @Param include_deleted query bool false "Include soft-deleted applicants (admin only)"
+// @Param view query string false "full (the default) or minimal, which masks identity numbers, dates of birth and phone numbers, reduces addresses to their postal district and omits household members; viewers always get minimal" Enums(full, minimal)
+// @Param fields query string false "Comma-separated fields to return, such as id,name,household.name; all if omitted"
 // @Success 200 {array} models.ApplicantResponse
-// @Failure 500 {object} string "Internal server error"
-// @Router /api/applicants [get]
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 403 {object} apierrors.APIError "Forbidden"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applicants [get]
 func (h *ApplicantHandler) GetApplicants(w http.ResponseWriter, r *http.Request) {
-	applicants, err := h.ApplicantRepo.GetAll()
+	filter, apiErr := h.applicantFilterParams(r)
+	if apiErr != nil {
+		apierrors.Write(w, r, apiErr)
+		return
+	}
+
+	applicants, err := h.ApplicantRepo.Find(filter)
 	if err != nil {
-		http.Error(w, "Failed to get applicants: "+err.Error(), http.StatusInternalServerError)
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applicants", err))
 		return
 	}
 
@@ -46,33 +92,116 @@ func (h *ApplicantHandler) GetApplicants(w http.ResponseWriter, r *http.Request)
 		})
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	writeList(w, r, response, 0)
+}
+
+// applicantFilterParams parses the query parameters shared by the applicant
+// list and stream endpoints
+func (h *ApplicantHandler) applicantFilterParams(r *http.Request) (models.ApplicantFilter, *apierrors.APIError) {
+	query := r.URL.Query()
+	filter := models.ApplicantFilter{
+		Name:             query.Get("name"),
+		EmploymentStatus: query.Get("employment_status"),
+		MaritalStatus:    query.Get("marital_status"),
+		Sex:              query.Get("sex"),
+		PostalDistrict:   models.NormalizePostalDistrict(query.Get("postal_district")),
+	}
+	if !validation.ValidPostalDistrict(filter.PostalDistrict) {
+		return filter, apierrors.BadRequest("Invalid postal_district").WithDetails("postal_district must be between 01 and 28")
+	}
+
+	var apiErr *apierrors.APIError
+	if filter.IncludeDeleted, apiErr = includeDeletedParam(r); apiErr != nil {
+		return filter, apiErr
+	}
+
+	var err error
+	if filter.MinAge, err = parseAgeParam(query.Get("min_age")); err != nil {
+		return filter, apierrors.BadRequest("Invalid min_age").WithDetails(err.Error())
+	}
+	if filter.MaxAge, err = parseAgeParam(query.Get("max_age")); err != nil {
+		return filter, apierrors.BadRequest("Invalid max_age").WithDetails(err.Error())
+	}
+	if filter.MinAge != nil && filter.MaxAge != nil && *filter.MinAge > *filter.MaxAge {
+		return filter, apierrors.BadRequest("min_age cannot be greater than max_age")
+	}
+
+	if filter.CustomFields, apiErr = h.customFieldFilter(r); apiErr != nil {
+		return filter, apiErr
+	}
+
+	return filter, nil
+}
+
+// customFieldFilter returns the custom field values to filter applicants by,
+// from the custom.{key} query parameters, which must name defined fields
+func (h *ApplicantHandler) customFieldFilter(r *http.Request) (map[string]string, *apierrors.APIError) {
+	var filter map[string]string
+	for name, values := range r.URL.Query() {
+		if strings.HasPrefix(name, models.CustomFieldPrefix) && len(values) > 0 {
+			if filter == nil {
+				filter = map[string]string{}
+			}
+			filter[strings.TrimPrefix(name, models.CustomFieldPrefix)] = values[0]
+		}
+	}
+	if filter == nil {
+		return nil, nil
+	}
+
+	fields, err := h.CustomFieldRepo.GetAll()
+	if err != nil {
+		return nil, apierrors.Internal("Failed to get custom fields", err)
+	}
+	for key := range filter {
+		if !slices.ContainsFunc(fields, func(f models.CustomField) bool { return f.Key == key }) {
+			return nil, apierrors.BadRequest("Invalid custom field filter").
+				WithDetails(key + " is not a defined custom field")
+		}
+	}
+	return filter, nil
 }
 
-// GetApplicant handles GET /api/applicants/{id}
+// GetApplicant handles GET /api/v1/applicants/{id}
 // @Summary Get applicant by ID
 // @Description Retrieve a specific applicant by their ID
 // @Tags applicants
 // @Accept json
 // @Produce json
 // @Param id path string true "Applicant ID"
+// @Param include_deleted query bool false "Include soft-deleted applicants (admin only)"
+// @Param view query string false "full (the default) or minimal, which masks identity numbers, dates of birth and phone numbers, reduces addresses to their postal district and omits household members; viewers always get minimal" Enums(full, minimal)
+// @Param fields query string false "Comma-separated fields to return, such as id,name,household.name; all if omitted"
 // @Success 200 {object} models.ApplicantResponse
-// @Failure 404 {object} string "Applicant not found"
-// @Failure 500 {object} string "Internal server error"
-// @Router /api/applicants/{id} [get]
+// @Failure 403 {object} apierrors.APIError "Forbidden"
+// @Failure 404 {object} apierrors.APIError "Applicant not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applicants/{id} [get]
 func (h *ApplicantHandler) GetApplicant(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
 
-	applicant, err := h.ApplicantRepo.GetByID(id)
+	includeDeleted, apiErr := includeDeletedParam(r)
+	if apiErr != nil {
+		apierrors.Write(w, r, apiErr)
+		return
+	}
+
+	var applicant *models.Applicant
+	var err error
+	if includeDeleted {
+		applicant, err = h.ApplicantRepo.GetByIDIncludingDeleted(id)
+	} else {
+		applicant, err = h.ApplicantRepo.GetByID(id)
+	}
 	if err != nil {
-		http.Error(w, "Failed to get applicant: "+err.Error(), http.StatusInternalServerError)
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applicant", err))
 		return
 	}
 
 	if applicant == nil {
-		http.Error(w, "Applicant not found", http.StatusNotFound)
+		apierrors.Write(w, r, apierrors.NotFound("Applicant not found"))
 		return
 	}
 
@@ -81,11 +210,11 @@ func (h *ApplicantHandler) GetApplicant(w http.ResponseWriter, r *http.Request)
 		Household: applicant.Household,
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	setETag(w, applicant.Version)
+	writeJSON(w, r, http.StatusOK, response)
 }
 
-// CreateApplicant handles POST /api/applicants
+// CreateApplicant handles POST /api/v1/applicants
 // @Summary Create a new applicant
 // @Description Add a new applicant to the system
 // @Tags applicants
@@ -93,20 +222,17 @@ func (h *ApplicantHandler) GetApplicant(w http.ResponseWriter, r *http.Request)
 // @Produce json
 // @Param applicant body models.Applicant true "Applicant information"
 // @Success 201 {object} models.ApplicantResponse
-// @Failure 400 {object} string "Bad request"
-// @Failure 500 {object} string "Internal server error"
-// @Router /api/applicants [post]
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 409 {object} apierrors.APIError "Duplicate identity number"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applicants [post]
 func (h *ApplicantHandler) CreateApplicant(w http.ResponseWriter, r *http.Request) {
 	var applicant models.Applicant
 	err := json.NewDecoder(r.Body).Decode(&applicant)
 	if err != nil {
-		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
-		return
-	}
-
-	// Basic validation
-	if applicant.Name == "" {
-		http.Error(w, "Name is required", http.StatusBadRequest)
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
 		return
 	}
 
@@ -114,12 +240,12 @@ func (h *ApplicantHandler) CreateApplicant(w http.ResponseWriter, r *http.Reques
 	if applicant.DateOfBirth.IsZero() {
 		dateStr := r.FormValue("date_of_birth")
 		if dateStr != "" {
-			date, err := time.Parse("2006-01-02", dateStr)
+			dob, err := date.Parse(dateStr)
 			if err != nil {
-				http.Error(w, "Invalid date format for date_of_birth: "+err.Error(), http.StatusBadRequest)
+				apierrors.Write(w, r, apierrors.BadRequest("Invalid date format for date_of_birth").WithDetails(err.Error()))
 				return
 			}
-			applicant.DateOfBirth = date
+			applicant.DateOfBirth = dob
 		}
 	}
 
@@ -128,19 +254,41 @@ func (h *ApplicantHandler) CreateApplicant(w http.ResponseWriter, r *http.Reques
 		if applicant.Household[i].DateOfBirth.IsZero() {
 			dateStr := r.FormValue("household[" + strconv.Itoa(i) + "].date_of_birth")
 			if dateStr != "" {
-				date, err := time.Parse("2006-01-02", dateStr)
+				dob, err := date.Parse(dateStr)
 				if err != nil {
-					http.Error(w, "Invalid date format for household member date_of_birth: "+err.Error(), http.StatusBadRequest)
+					apierrors.Write(w, r, apierrors.BadRequest("Invalid date format for household member date_of_birth").WithDetails(err.Error()))
 					return
 				}
-				applicant.Household[i].DateOfBirth = date
+				applicant.Household[i].DateOfBirth = dob
 			}
 		}
 	}
 
-	err = h.ApplicantRepo.Create(&applicant)
+	fields, ok := loadCustomFields(w, r, h.CustomFieldRepo)
+	if !ok {
+		return
+	}
+	if err := validation.Applicant(&applicant, fields); err != nil {
+		apierrors.Write(w, r, validationError(err))
+		return
+	}
+
+	err = models.WithTx(h.ApplicantRepo.DB, func(tx *sql.Tx) error {
+		if err := h.ApplicantRepo.WithTx(tx).Create(&applicant); err != nil {
+			return err
+		}
+		if err := h.AuditRepo.WithTx(tx).Record(models.AuditEntityApplicant, applicant.ID,
+			models.AuditActionCreate, actorFrom(r), nil, &applicant); err != nil {
+			return err
+		}
+		return h.WebhookRepo.WithTx(tx).Enqueue(models.EventApplicantCreated, &applicant)
+	})
+	if errors.Is(err, models.ErrDuplicateIdentityNumber) {
+		apierrors.Write(w, r, duplicateIdentityNumber())
+		return
+	}
 	if err != nil {
-		http.Error(w, "Failed to create applicant: "+err.Error(), http.StatusInternalServerError)
+		apierrors.Write(w, r, apierrors.Internal("Failed to create applicant", err))
 		return
 	}
 
@@ -149,24 +297,68 @@ func (h *ApplicantHandler) CreateApplicant(w http.ResponseWriter, r *http.Reques
 		Household: applicant.Household,
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(response)
+	setETag(w, applicant.Version)
+	writeJSON(w, r, http.StatusCreated, response)
+}
+
+// GetApplicantByIdentityNumber handles GET /api/v1/applicants/by-nric/{nric}
+// @Summary Get applicant by NRIC
+// @Description Look up an applicant by NRIC or FIN, e.g. to check whether a person is already registered
+// @Tags applicants
+// @Produce json
+// @Param nric path string true "NRIC or FIN"
+// @Param view query string false "full (the default) or minimal, which masks identity numbers, dates of birth and phone numbers, reduces addresses to their postal district and omits household members; viewers always get minimal" Enums(full, minimal)
+// @Param fields query string false "Comma-separated fields to return, such as id,name,household.name; all if omitted"
+// @Success 200 {object} models.ApplicantResponse
+// @Failure 400 {object} apierrors.APIError "Invalid NRIC or FIN"
+// @Failure 404 {object} apierrors.APIError "Applicant not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applicants/by-nric/{nric} [get]
+func (h *ApplicantHandler) GetApplicantByIdentityNumber(w http.ResponseWriter, r *http.Request) {
+	nric := mux.Vars(r)["nric"]
+	if !validation.ValidIdentityNumber(nric) {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid NRIC or FIN"))
+		return
+	}
+
+	applicant, err := h.ApplicantRepo.GetByIdentityNumber(nric)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applicant", err))
+		return
+	}
+	if applicant == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Applicant not found"))
+		return
+	}
+
+	response := models.ApplicantResponse{
+		Applicant: *applicant,
+		Household: applicant.Household,
+	}
+
+	setETag(w, applicant.Version)
+	writeJSON(w, r, http.StatusOK, response)
 }
 
-// UpdateApplicant handles PUT /api/applicants/{id}
+// UpdateApplicant handles PUT /api/v1/applicants/{id}
 // @Summary Update applicant
 // @Description Update an existing applicant's information
 // @Tags applicants
 // @Accept json
 // @Produce json
 // @Param id path string true "Applicant ID"
+// @Param If-Match header string false "ETag of the version being updated; alternatively send version in the body"
 // @Param applicant body models.Applicant true "Updated applicant information"
 // @Success 200 {object} models.Applicant
-// @Failure 400 {object} string "Bad request"
-// @Failure 404 {object} string "Applicant not found"
-// @Failure 500 {object} string "Internal server error"
-// @Router /api/applicants/{id} [put]
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 404 {object} apierrors.APIError "Applicant not found"
+// @Failure 409 {object} apierrors.APIError "Version conflict, duplicate identity number or anonymized applicant"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 428 {object} apierrors.APIError "Missing If-Match header or version"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applicants/{id} [put]
 func (h *ApplicantHandler) UpdateApplicant(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
@@ -174,88 +366,378 @@ func (h *ApplicantHandler) UpdateApplicant(w http.ResponseWriter, r *http.Reques
 	// Check if applicant exists
 	existing, err := h.ApplicantRepo.GetByID(id)
 	if err != nil {
-		http.Error(w, "Failed to get applicant: "+err.Error(), http.StatusInternalServerError)
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applicant", err))
 		return
 	}
 	if existing == nil {
-		http.Error(w, "Applicant not found", http.StatusNotFound)
+		apierrors.Write(w, r, apierrors.NotFound("Applicant not found"))
+		return
+	}
+	if existing.AnonymizedAt != nil {
+		apierrors.Write(w, r, anonymizedApplicant())
 		return
 	}
 
 	var applicant models.Applicant
 	err = json.NewDecoder(r.Body).Decode(&applicant)
 	if err != nil {
-		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
 		return
 	}
 
 	// Ensure ID matches path parameter
 	applicant.ID = id
 
-	// Basic validation
-	if applicant.Name == "" {
-		http.Error(w, "Name is required", http.StatusBadRequest)
+	// Reject updates based on a stale version
+	version, apiErr := expectedVersion(r, applicant.Version, existing.Version)
+	if apiErr != nil {
+		apierrors.Write(w, r, apiErr)
 		return
 	}
+	if version != existing.Version {
+		apierrors.Write(w, r, versionConflict())
+		return
+	}
+	applicant.Version = version
 
 	// Parse date strings if they came in a different format
 	if applicant.DateOfBirth.IsZero() {
 		dateStr := r.FormValue("date_of_birth")
 		if dateStr != "" {
-			date, err := time.Parse("2006-01-02", dateStr)
+			dob, err := date.Parse(dateStr)
 			if err != nil {
-				http.Error(w, "Invalid date format for date_of_birth: "+err.Error(), http.StatusBadRequest)
+				apierrors.Write(w, r, apierrors.BadRequest("Invalid date format for date_of_birth").WithDetails(err.Error()))
 				return
 			}
-			applicant.DateOfBirth = date
+			applicant.DateOfBirth = dob
 		} else {
 			applicant.DateOfBirth = existing.DateOfBirth
 		}
 	}
 
-	err = h.ApplicantRepo.Update(&applicant)
+	// Household members are not changed by this update
+	after := applicant
+	after.CreatedAt = existing.CreatedAt
+	after.Household = existing.Household
+	after.Version = version + 1
+
+	fields, ok := loadCustomFields(w, r, h.CustomFieldRepo)
+	if !ok {
+		return
+	}
+	if err := validation.Applicant(&after, fields); err != nil {
+		apierrors.Write(w, r, validationError(err))
+		return
+	}
+
+	err = models.WithTx(h.ApplicantRepo.DB, func(tx *sql.Tx) error {
+		if err := h.ApplicantRepo.WithTx(tx).Update(&applicant); err != nil {
+			return err
+		}
+		if err := h.AuditRepo.WithTx(tx).Record(models.AuditEntityApplicant, id,
+			models.AuditActionUpdate, actorFrom(r), existing, &after); err != nil {
+			return err
+		}
+		return h.WebhookRepo.WithTx(tx).Enqueue(models.EventApplicantUpdated, &after)
+	})
+	if errors.Is(err, models.ErrVersionConflict) {
+		apierrors.Write(w, r, versionConflict())
+		return
+	}
+	if errors.Is(err, models.ErrDuplicateIdentityNumber) {
+		apierrors.Write(w, r, duplicateIdentityNumber())
+		return
+	}
 	if err != nil {
-		http.Error(w, "Failed to update applicant: "+err.Error(), http.StatusInternalServerError)
+		apierrors.Write(w, r, apierrors.Internal("Failed to update applicant", err))
 		return
 	}
+	h.ApplicantCache.Invalidate(id)
 
 	// Note: this doesn't update household members - would need separate endpoints for that
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(applicant)
+	setETag(w, applicant.Version)
+	writeJSON(w, r, http.StatusOK, applicant)
+}
+
+// PatchApplicant handles PATCH /api/v1/applicants/{id}
+// @Summary Partially update applicant
+// @Description Update selected fields of an applicant using JSON Merge Patch (RFC 7396): fields present in the body replace the stored values and omitted fields are left unchanged. Household members are not changed.
+// @Tags applicants
+// @Accept json
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param id path string true "Applicant ID"
+// @Param If-Match header string false "ETag of the version being updated; alternatively send version in the body"
+// @Param patch body models.Applicant true "Fields to update"
+// @Success 200 {object} models.ApplicantResponse
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 404 {object} apierrors.APIError "Applicant not found"
+// @Failure 409 {object} apierrors.APIError "Version conflict, duplicate identity number or anonymized applicant"
+// @Failure 415 {object} apierrors.APIError "Unsupported Content-Type"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 428 {object} apierrors.APIError "Missing If-Match header or version"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applicants/{id} [patch]
+func (h *ApplicantHandler) PatchApplicant(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	existing, err := h.ApplicantRepo.GetByID(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applicant", err))
+		return
+	}
+	if existing == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Applicant not found"))
+		return
+	}
+	if existing.AnonymizedAt != nil {
+		apierrors.Write(w, r, anonymizedApplicant())
+		return
+	}
+
+	patch, patchVersion, apiErr := readMergePatch(r)
+	if apiErr != nil {
+		apierrors.Write(w, r, apiErr)
+		return
+	}
+
+	// Reject updates based on a stale version
+	version, apiErr := expectedVersion(r, patchVersion, existing.Version)
+	if apiErr != nil {
+		apierrors.Write(w, r, apiErr)
+		return
+	}
+	if version != existing.Version {
+		apierrors.Write(w, r, versionConflict())
+		return
+	}
+
+	var applicant models.Applicant
+	if err := mergepatch.ApplyTo(existing, patch, &applicant); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid patch").WithDetails(err.Error()))
+		return
+	}
+
+	// Fields managed by the server and household members are kept as stored
+	applicant.ID = existing.ID
+	applicant.CreatedAt = existing.CreatedAt
+	applicant.UpdatedAt = existing.UpdatedAt
+	applicant.DeletedAt = existing.DeletedAt
+	applicant.Household = existing.Household
+	applicant.Version = version
+
+	fields, ok := loadCustomFields(w, r, h.CustomFieldRepo)
+	if !ok {
+		return
+	}
+	if err := validation.Applicant(&applicant, fields); err != nil {
+		apierrors.Write(w, r, validationError(err))
+		return
+	}
+
+	err = models.WithTx(h.ApplicantRepo.DB, func(tx *sql.Tx) error {
+		if err := h.ApplicantRepo.WithTx(tx).Update(&applicant); err != nil {
+			return err
+		}
+		if err := h.AuditRepo.WithTx(tx).Record(models.AuditEntityApplicant, id,
+			models.AuditActionUpdate, actorFrom(r), existing, &applicant); err != nil {
+			return err
+		}
+		return h.WebhookRepo.WithTx(tx).Enqueue(models.EventApplicantUpdated, &applicant)
+	})
+	if errors.Is(err, models.ErrVersionConflict) {
+		apierrors.Write(w, r, versionConflict())
+		return
+	}
+	if errors.Is(err, models.ErrDuplicateIdentityNumber) {
+		apierrors.Write(w, r, duplicateIdentityNumber())
+		return
+	}
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to update applicant", err))
+		return
+	}
+	h.ApplicantCache.Invalidate(id)
+
+	response := models.ApplicantResponse{
+		Applicant: applicant,
+		Household: applicant.Household,
+	}
+
+	setETag(w, applicant.Version)
+	writeJSON(w, r, http.StatusOK, response)
 }
 
-// DeleteApplicant handles DELETE /api/applicants/{id}
+// DeleteApplicant handles DELETE /api/v1/applicants/{id}
 // @Summary Delete applicant
-// @Description Remove an applicant from the system
+// @Description Soft-delete an applicant, keeping their case history. Deleted applicants can be restored. Applicants with applications cannot be deleted unless cascade is set, which also soft-deletes their applications and requires the admin role.
 // @Tags applicants
 // @Accept json
 // @Produce json
 // @Param id path string true "Applicant ID"
+// @Param cascade query bool false "Also delete the applicant's applications (admin only)"
 // @Success 204 "No content"
-// @Failure 404 {object} string "Applicant not found"
-// @Failure 500 {object} string "Internal server error"
-// @Router /api/applicants/{id} [delete]
+// @Failure 400 {object} apierrors.APIError "Invalid cascade"
+// @Failure 403 {object} apierrors.APIError "cascade requires the admin role"
+// @Failure 404 {object} apierrors.APIError "Applicant not found"
+// @Failure 409 {object} apierrors.APIError "Applicant has applications; details lists their IDs"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applicants/{id} [delete]
 func (h *ApplicantHandler) DeleteApplicant(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
 
+	cascade := false
+	if value := r.URL.Query().Get("cascade"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			apierrors.Write(w, r, apierrors.BadRequest("Invalid cascade").WithDetails(err.Error()))
+			return
+		}
+		cascade = parsed
+	}
+	if cascade && !hasRole(r, auth.RoleAdmin) {
+		apierrors.Write(w, r, apierrors.Forbidden("cascade requires the admin role"))
+		return
+	}
+
 	// Check if applicant exists
 	existing, err := h.ApplicantRepo.GetByID(id)
 	if err != nil {
-		http.Error(w, "Failed to get applicant: "+err.Error(), http.StatusInternalServerError)
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applicant", err))
 		return
 	}
 	if existing == nil {
-		http.Error(w, "Applicant not found", http.StatusNotFound)
+		apierrors.Write(w, r, apierrors.NotFound("Applicant not found"))
 		return
 	}
 
-	err = h.ApplicantRepo.Delete(id)
+	// The applications are checked and deleted in the same transaction as
+	// the applicant, so none can be left behind
+	var blocking []string
+	actor := actorFrom(r)
+	err = models.WithTx(h.ApplicantRepo.DB, func(tx *sql.Tx) error {
+		applicationRepo := h.ApplicationRepo.WithTx(tx)
+		audit := h.AuditRepo.WithTx(tx)
+
+		applications, err := applicationRepo.GetByApplicantID(id)
+		if err != nil {
+			return err
+		}
+		if len(applications) > 0 && !cascade {
+			for _, a := range applications {
+				blocking = append(blocking, a.ID)
+			}
+			return errHasApplications
+		}
+
+		for i := range applications {
+			a := &applications[i]
+			before := applicationSnapshot(a)
+			if err := applicationRepo.Delete(a); err != nil {
+				return err
+			}
+			if err := audit.Record(models.AuditEntityApplication, a.ID,
+				models.AuditActionDelete, actor, before, nil); err != nil {
+				return err
+			}
+		}
+
+		if err := h.ApplicantRepo.WithTx(tx).Delete(id); err != nil {
+			return err
+		}
+		return audit.Record(models.AuditEntityApplicant, id,
+			models.AuditActionDelete, actor, existing, nil)
+	})
+	if errors.Is(err, errHasApplications) {
+		apierrors.Write(w, r, apierrors.Conflict("Applicant has applications").
+			WithDetails(map[string]interface{}{"application_ids": blocking}))
+		return
+	}
 	if err != nil {
-		http.Error(w, "Failed to delete applicant: "+err.Error(), http.StatusInternalServerError)
+		apierrors.Write(w, r, apierrors.Internal("Failed to delete applicant", err))
 		return
 	}
+	h.ApplicantCache.Invalidate(id)
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// errHasApplications aborts the deletion of an applicant with applications
+// when they are not to be deleted too
+var errHasApplications = errors.New("applicant has applications")
+
+// RestoreApplicant handles POST /api/v1/applicants/{id}/restore
+// @Summary Restore applicant
+// @Description Restore a soft-deleted applicant
+// @Tags applicants
+// @Accept json
+// @Produce json
+// @Param id path string true "Applicant ID"
+// @Success 200 {object} models.ApplicantResponse
+// @Failure 404 {object} apierrors.APIError "Applicant not found"
+// @Failure 409 {object} apierrors.APIError "Applicant is not deleted"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applicants/{id}/restore [post]
+func (h *ApplicantHandler) RestoreApplicant(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	existing, err := h.ApplicantRepo.GetByIDIncludingDeleted(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applicant", err))
+		return
+	}
+	if existing == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Applicant not found"))
+		return
+	}
+	if existing.DeletedAt == nil {
+		apierrors.Write(w, r, apierrors.Conflict("Applicant is not deleted"))
+		return
+	}
+
+	restored := *existing
+	restored.DeletedAt = nil
+
+	err = models.WithTx(h.ApplicantRepo.DB, func(tx *sql.Tx) error {
+		if err := h.ApplicantRepo.WithTx(tx).Restore(id); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityApplicant, id,
+			models.AuditActionRestore, actorFrom(r), existing, &restored)
+	})
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to restore applicant", err))
+		return
+	}
+	h.ApplicantCache.Invalidate(id)
+
+	response := models.ApplicantResponse{
+		Applicant: restored,
+		Household: restored.Household,
+	}
+
+	setETag(w, restored.Version)
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// parseAgeParam parses an optional non-negative age query parameter
+func parseAgeParam(value string) (*int, error) {
+	if value == "" {
+		return nil, nil
+	}
+	age, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, err
+	}
+	if age < 0 {
+		return nil, fmt.Errorf("age cannot be negative")
+	}
+	return &age, nil
+}
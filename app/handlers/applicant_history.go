@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/models"
+)
+
+// GetApplicantHistory handles GET /api/v1/applicants/{id}/history
+// @Summary Get an applicant's change history
+// @Description List the field-level changes made to an applicant, oldest first, with when each was made and by whom, reconstructed from the audit log. Use it to see, for example, when employment status changed relative to an application date.
+// @Tags applicants
+// @Produce json
+// @Param id path string true "Applicant ID"
+// @Param field query string false "Only changes to this field, e.g. employment_status"
+// @Param from query string false "Only changes at or after this time (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Only changes before this time (RFC3339 or YYYY-MM-DD)"
+// @Success 200 {array} models.HistoryEntry
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 404 {object} apierrors.APIError "Applicant not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applicants/{id}/history [get]
+func (h *ApplicantHandler) GetApplicantHistory(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var filter models.AuditFilter
+	var err error
+	if filter.From, err = parseTimeParam(query.Get("from")); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid from").WithDetails(err.Error()))
+		return
+	}
+	if filter.To, err = parseTimeParam(query.Get("to")); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid to").WithDetails(err.Error()))
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	applicant, err := h.ApplicantRepo.GetByID(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applicant", err))
+		return
+	}
+	if applicant == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Applicant not found"))
+		return
+	}
+
+	history, err := h.AuditRepo.History(models.AuditEntityApplicant, id, query.Get("field"), filter)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applicant history", err))
+		return
+	}
+
+	writeList(w, r, history, 0)
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/models"
+)
+
+// ValidateHousehold handles GET /api/v1/applicants/{id}/household/validate
+// @Summary Validate an applicant's household
+// @Description Check the household members of an applicant against each other and against other households: at most one member may be a spouse, sons and daughters must have been born when the applicant was between 12 and 65, and parents must have been that age when the applicant was born. An NRIC/FIN may not appear twice in the household, be the applicant's own, or belong to a member of another applicant's household or to another applicant. Deleted applicants are not compared. The problems found are reported, not rejected: households are saved as declared, as some, such as those of adoptive parents, are legitimately unusual.
+// @Tags applicants
+// @Produce json
+// @Param id path string true "Applicant ID"
+// @Success 200 {object} models.HouseholdValidation
+// @Failure 404 {object} apierrors.APIError "Applicant not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applicants/{id}/household/validate [get]
+func (h *ApplicantHandler) ValidateHousehold(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	applicant, err := h.ApplicantCache.GetByID(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applicant", err))
+		return
+	}
+	if applicant == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Applicant not found"))
+		return
+	}
+
+	identityNumbers := make([]string, 0, len(applicant.Household))
+	for _, m := range applicant.Household {
+		identityNumbers = append(identityNumbers, m.IdentityNumber)
+	}
+	matches, err := h.ApplicantRepo.FindIdentityNumbers(identityNumbers, applicant.ID)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to check other households", err))
+		return
+	}
+
+	issues := append(applicant.HouseholdIssues(), applicant.OtherHouseholdIssues(matches)...)
+	writeJSON(w, r, http.StatusOK, models.HouseholdValidation{
+		ApplicantID: applicant.ID,
+		Valid:       len(issues) == 0,
+		Issues:      issues,
+	})
+}
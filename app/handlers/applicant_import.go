@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/jobs"
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/validation"
+)
+
+// maxImportApplicants is the most applicants a single import may contain
+const maxImportApplicants = 10000
+
+// importApplicantsJob is the payload of an applicants.import job. The
+// applicants are encrypted, as they hold personal data.
+type importApplicantsJob struct {
+	ActorID       string `json:"actor_id"`
+	ActorUsername string `json:"actor_username"`
+	Applicants    string `json:"applicants"`
+}
+
+// ImportApplicants handles POST /api/v1/applicants/import
+// @Summary Import applicants in bulk
+// @Description Queue a job creating many applicants with their household members. Each applicant is validated and created separately, so invalid ones and those whose NRIC is already registered are reported in the job's result without stopping the others. Poll the returned job for the outcome.
+// @Tags applicants
+// @Accept json
+// @Produce json
+// @Param request body models.ImportApplicantsRequest true "Applicants to create, at most 10000"
+// @Success 202 {object} models.Job "Queued; the result is a models.ImportApplicantsResult"
+// @Failure 400 {object} apierrors.APIError "Invalid request body"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applicants/import [post]
+func (h *ApplicantHandler) ImportApplicants(w http.ResponseWriter, r *http.Request) {
+	var req models.ImportApplicantsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
+		return
+	}
+	if len(req.Applicants) == 0 {
+		apierrors.Write(w, r, apierrors.BadRequest("applicants must not be empty"))
+		return
+	}
+	if len(req.Applicants) > maxImportApplicants {
+		apierrors.Write(w, r, apierrors.BadRequest("At most "+strconv.Itoa(maxImportApplicants)+" applicants can be imported at once"))
+		return
+	}
+
+	data, err := json.Marshal(req.Applicants)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to queue import", err))
+		return
+	}
+	sealed, err := h.ApplicantRepo.Cipher.Encrypt(string(data))
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to queue import", err))
+		return
+	}
+
+	actor := actorFrom(r)
+	job, err := h.JobRepo.Enqueue(models.JobImportApplicants, importApplicantsJob{
+		ActorID:       actor.ID,
+		ActorUsername: actor.Username,
+		Applicants:    sealed,
+	}, actor.ID)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to queue import", err))
+		return
+	}
+
+	writeJobAccepted(w, r, job)
+}
+
+// RunImportJob is the handler of applicants.import jobs. Each applicant is
+// created in its own transaction with an ID derived from the job, so that an
+// attempt retried after a failure skips the applicants already created.
+func (h *ApplicantHandler) RunImportJob(ctx context.Context, job *models.Job) (interface{}, error) {
+	var payload importApplicantsJob
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return nil, jobs.Permanent(fmt.Errorf("invalid payload: %v", err))
+	}
+	data, err := h.ApplicantRepo.Cipher.Decrypt(payload.Applicants)
+	if err != nil {
+		return nil, jobs.Permanent(fmt.Errorf("error decrypting applicants: %v", err))
+	}
+	var applicants []models.Applicant
+	if err := json.Unmarshal([]byte(data), &applicants); err != nil {
+		return nil, jobs.Permanent(fmt.Errorf("invalid applicants: %v", err))
+	}
+
+	jobID, err := uuid.Parse(job.ID)
+	if err != nil {
+		return nil, jobs.Permanent(fmt.Errorf("invalid job ID: %v", err))
+	}
+	actor := models.Actor{ID: payload.ActorID, Username: payload.ActorUsername}
+	fields, err := h.CustomFieldRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	result := models.ImportApplicantsResult{
+		Total:      len(applicants),
+		CreatedIDs: []string{},
+		Failures:   []models.ImportFailure{},
+	}
+	for i := range applicants {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		applicant := &applicants[i]
+		applicant.ID = uuid.NewSHA1(jobID, []byte(strconv.Itoa(i))).String()
+
+		if err := validation.Applicant(applicant, fields); err != nil {
+			failure := models.ImportFailure{Index: i, Error: "validation failed"}
+			var fields validation.Errors
+			if errors.As(err, &fields) {
+				failure.Fields = fields
+			} else {
+				failure.Error = err.Error()
+			}
+			result.Failures = append(result.Failures, failure)
+			continue
+		}
+
+		// Created by an earlier attempt
+		existing, err := h.ApplicantRepo.GetByIDIncludingDeleted(applicant.ID)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			err = models.WithTx(h.ApplicantRepo.DB, func(tx *sql.Tx) error {
+				if err := h.ApplicantRepo.WithTx(tx).Create(applicant); err != nil {
+					return err
+				}
+				if err := h.AuditRepo.WithTx(tx).Record(models.AuditEntityApplicant, applicant.ID,
+					models.AuditActionCreate, actor, nil, applicant); err != nil {
+					return err
+				}
+				return h.WebhookRepo.WithTx(tx).Enqueue(models.EventApplicantCreated, applicant)
+			})
+			if errors.Is(err, models.ErrDuplicateIdentityNumber) {
+				result.Failures = append(result.Failures, models.ImportFailure{Index: i, Error: err.Error()})
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		result.Created++
+		result.CreatedIDs = append(result.CreatedIDs, applicant.ID)
+	}
+
+	return result, nil
+}
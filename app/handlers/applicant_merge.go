@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/validation"
+)
+
+// MergeApplicant handles POST /api/v1/applicants/{id}/merge
+// @Summary Merge a duplicate applicant
+// @Description Merge the source applicant into this one. The source's household members, applications, case notes, referrals and income records are moved to the target (each application recording a merge event), differing fields are resolved by the policy (blank fields are always filled from the other record), and the source is soft-deleted.
+// @Tags applicants
+// @Accept json
+// @Produce json
+// @Param id path string true "Target applicant ID"
+// @Param If-Match header string true "ETag of the target version being merged into"
+// @Param merge body models.MergeRequest true "Source applicant and conflict policy"
+// @Success 200 {object} models.ApplicantResponse
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 404 {object} apierrors.APIError "Applicant not found"
+// @Failure 409 {object} apierrors.APIError "Version conflict, duplicate identity number or anonymized applicant"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 428 {object} apierrors.APIError "Missing If-Match header"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applicants/{id}/merge [post]
+func (h *ApplicantHandler) MergeApplicant(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	var request models.MergeRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
+		return
+	}
+	if err := validation.MergeRequest(&request, id); err != nil {
+		apierrors.Write(w, r, validationError(err))
+		return
+	}
+
+	target, err := h.ApplicantRepo.GetByID(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applicant", err))
+		return
+	}
+	if target == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Applicant not found"))
+		return
+	}
+	if target.AnonymizedAt != nil {
+		apierrors.Write(w, r, anonymizedApplicant())
+		return
+	}
+
+	// Reject merges into a stale version of the target
+	version, apiErr := expectedVersion(r, 0, target.Version)
+	if apiErr != nil {
+		apierrors.Write(w, r, apiErr)
+		return
+	}
+	if version != target.Version {
+		apierrors.Write(w, r, versionConflict())
+		return
+	}
+
+	source, err := h.ApplicantRepo.GetByID(request.SourceID)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applicant", err))
+		return
+	}
+	if source == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Source applicant not found"))
+		return
+	}
+	if source.AnonymizedAt != nil {
+		apierrors.Write(w, r, anonymizedApplicant())
+		return
+	}
+
+	// Records of different people must not be merged
+	if target.IdentityNumber != "" && source.IdentityNumber != "" && target.IdentityNumber != source.IdentityNumber {
+		apierrors.Write(w, r, apierrors.Validation(map[string]string{
+			"source_id": "has a different identity number from the target applicant",
+		}))
+		return
+	}
+
+	merged := models.MergeApplicants(target, source, request.Policy)
+	fields, ok := loadCustomFields(w, r, h.CustomFieldRepo)
+	if !ok {
+		return
+	}
+	if err := validation.Applicant(&merged, fields); err != nil {
+		apierrors.Write(w, r, validationError(err))
+		return
+	}
+
+	actor := actorFrom(r)
+	err = models.WithTx(h.ApplicantRepo.DB, func(tx *sql.Tx) error {
+		moved, err := h.ApplicationRepo.WithTx(tx).Find(models.ApplicationFilter{ApplicantID: source.ID, IncludeDeleted: true})
+		if err != nil {
+			return err
+		}
+		if err := h.ApplicantRepo.WithTx(tx).Merge(&merged, source); err != nil {
+			return err
+		}
+
+		audit := h.AuditRepo.WithTx(tx)
+		if err := audit.Record(models.AuditEntityApplicant, target.ID,
+			models.AuditActionMerge, actor, target, &merged); err != nil {
+			return err
+		}
+		if err := audit.Record(models.AuditEntityApplicant, source.ID,
+			models.AuditActionMerge, actor, source, nil); err != nil {
+			return err
+		}
+		for i := range moved {
+			before := applicationSnapshot(&moved[i])
+			after := *before
+			after.ApplicantID = target.ID
+			if err := audit.Record(models.AuditEntityApplication, before.ID,
+				models.AuditActionMerge, actor, before, &after); err != nil {
+				return err
+			}
+		}
+		return h.WebhookRepo.WithTx(tx).Enqueue(models.EventApplicantUpdated, &merged)
+	})
+	if errors.Is(err, models.ErrVersionConflict) {
+		apierrors.Write(w, r, versionConflict())
+		return
+	}
+	if errors.Is(err, models.ErrDuplicateIdentityNumber) {
+		apierrors.Write(w, r, duplicateIdentityNumber())
+		return
+	}
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to merge applicants", err))
+		return
+	}
+	h.ApplicantCache.Invalidate(target.ID, source.ID)
+
+	response := models.ApplicantResponse{
+		Applicant: merged,
+		Household: merged.Household,
+	}
+
+	setETag(w, merged.Version)
+	writeJSON(w, r, http.StatusOK, response)
+}
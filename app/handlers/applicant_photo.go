@@ -0,0 +1,310 @@
+package handlers
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/auth"
+	"one-client-view-2025tht/app/imaging"
+	"one-client-view-2025tht/app/logging"
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/storage"
+)
+
+// Sizes of an applicant's photo that can be downloaded
+const (
+	photoSizeThumbnail = "thumbnail"
+	photoSizeOriginal  = "original"
+)
+
+// PhotoHandler handles requests for applicants' photos
+type PhotoHandler struct {
+	PhotoRepo     *models.PhotoRepository
+	ApplicantRepo *models.ApplicantRepository
+	AuditRepo     *models.AuditRepository
+	Store         storage.Store
+	MaxSize       int64 // Largest file accepted, in bytes
+	ThumbnailSize int   // Longest side of thumbnails, in pixels
+}
+
+// NewPhotoHandler creates a new handler with the given repositories, store
+// and limits
+func NewPhotoHandler(photoRepo *models.PhotoRepository, applicantRepo *models.ApplicantRepository, auditRepo *models.AuditRepository, store storage.Store, maxSize int64, thumbnailSize int) *PhotoHandler {
+	return &PhotoHandler{
+		PhotoRepo:     photoRepo,
+		ApplicantRepo: applicantRepo,
+		AuditRepo:     auditRepo,
+		Store:         store,
+		MaxSize:       maxSize,
+		ThumbnailSize: thumbnailSize,
+	}
+}
+
+// photo loads the photo of the applicant named in the path, writing a 404 if
+// the applicant does not exist or has no photo
+func (h *PhotoHandler) photo(w http.ResponseWriter, r *http.Request) *models.ApplicantPhoto {
+	photo, err := h.PhotoRepo.GetByApplicantID(mux.Vars(r)["id"])
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get photo", err))
+		return nil
+	}
+	if photo == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Photo not found"))
+		return nil
+	}
+	return photo
+}
+
+// PutPhoto handles PUT /api/v1/applicants/{id}/photo
+// @Summary Upload an applicant's photo
+// @Description Set the photo shown on an applicant's client card, replacing any they had. The image must be a JPEG or PNG, detected from its content, of at most 40 megapixels. A thumbnail fitting within the configured size (by default 256 pixels) is made from it; both are re-encoded, which drops metadata such as where the photo was taken.
+// @Tags applicants
+// @Accept mpfd
+// @Produce json
+// @Param id path string true "Applicant ID"
+// @Param file formData file true "The photo"
+// @Success 200 {object} models.ApplicantPhoto "Photo replaced"
+// @Success 201 {object} models.ApplicantPhoto "Photo added"
+// @Failure 400 {object} apierrors.APIError "Invalid multipart form"
+// @Failure 404 {object} apierrors.APIError "Applicant not found"
+// @Failure 413 {object} apierrors.APIError "File too large"
+// @Failure 415 {object} apierrors.APIError "Not multipart/form-data, or not a JPEG or PNG"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applicants/{id}/photo [put]
+func (h *PhotoHandler) PutPhoto(w http.ResponseWriter, r *http.Request) {
+	applicant, err := h.ApplicantRepo.GetByID(mux.Vars(r)["id"])
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applicant", err))
+		return
+	}
+	if applicant == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Applicant not found"))
+		return
+	}
+
+	tooLarge := apierrors.PayloadTooLarge("File too large").
+		WithDetails("photos may be at most " + strconv.FormatInt(h.MaxSize, 10) + " bytes")
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.MaxSize+multipartOverhead)
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		var maxBytes *http.MaxBytesError
+		switch {
+		case errors.As(err, &maxBytes):
+			apierrors.Write(w, r, tooLarge)
+		case errors.Is(err, http.ErrNotMultipart):
+			apierrors.Write(w, r, apierrors.UnsupportedMediaType("Content-Type must be multipart/form-data"))
+		default:
+			apierrors.Write(w, r, apierrors.BadRequest("Invalid multipart form").WithDetails(err.Error()))
+		}
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Validation(map[string]string{"file": "is required"}))
+		return
+	}
+	defer file.Close()
+
+	if header.Size > h.MaxSize {
+		apierrors.Write(w, r, tooLarge)
+		return
+	}
+	if header.Size == 0 {
+		apierrors.Write(w, r, apierrors.Validation(map[string]string{"file": "must not be empty"}))
+		return
+	}
+
+	// Photos are small enough to decode in memory
+	data, err := io.ReadAll(file)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to read file", err))
+		return
+	}
+
+	// The declared type is not trusted; detect it from the content
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(data))
+	if contentType != imaging.TypeJPEG && contentType != imaging.TypePNG {
+		apierrors.Write(w, r, apierrors.UnsupportedMediaType("File type not allowed").
+			WithDetails(contentType+" is not one of "+imaging.TypeJPEG+", "+imaging.TypePNG))
+		return
+	}
+
+	thumbnail, err := imaging.MakeThumbnail(data, h.ThumbnailSize)
+	if err != nil {
+		message := "is not a valid JPEG or PNG image"
+		if errors.Is(err, imaging.ErrTooLarge) {
+			message = "must have at most " + strconv.Itoa(imaging.MaxPixels) + " pixels"
+		}
+		apierrors.Write(w, r, apierrors.Validation(map[string]string{"file": message}))
+		return
+	}
+
+	// Each upload gets its own keys, so the previous photo can be served
+	// until the new one is saved
+	key := "applicants/" + applicant.ID + "/photo/" + uuid.New().String()
+	photo := models.ApplicantPhoto{
+		ApplicantID:          applicant.ID,
+		ContentType:          contentType,
+		Size:                 int64(len(data)),
+		Width:                thumbnail.Width,
+		Height:               thumbnail.Height,
+		StorageKey:           key,
+		ThumbnailKey:         key + "-thumbnail",
+		ThumbnailContentType: thumbnail.ContentType,
+		UploadedBy:           actorFrom(r).ID,
+	}
+
+	if err := h.Store.Put(r.Context(), photo.StorageKey, bytes.NewReader(data), photo.Size, photo.ContentType); err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to store photo", err))
+		return
+	}
+	if err := h.Store.Put(r.Context(), photo.ThumbnailKey, bytes.NewReader(thumbnail.Data), int64(len(thumbnail.Data)), photo.ThumbnailContentType); err != nil {
+		h.deleteImages(r, photo.StorageKey)
+		apierrors.Write(w, r, apierrors.Internal("Failed to store photo", err))
+		return
+	}
+
+	var previous *models.ApplicantPhoto
+	err = models.WithTx(h.PhotoRepo.DB, func(tx *sql.Tx) error {
+		repo := h.PhotoRepo.WithTx(tx)
+		var err error
+		if previous, err = repo.GetByApplicantID(applicant.ID); err != nil {
+			return err
+		}
+		if err := repo.Put(&photo); err != nil {
+			return err
+		}
+		action := models.AuditActionCreate
+		if previous != nil {
+			action = models.AuditActionUpdate
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityPhoto, applicant.ID,
+			action, actorFrom(r), previous, &photo)
+	})
+	if err != nil {
+		h.deleteImages(r, photo.StorageKey, photo.ThumbnailKey)
+		apierrors.Write(w, r, apierrors.Internal("Failed to save photo", err))
+		return
+	}
+
+	status := http.StatusCreated
+	if previous != nil {
+		h.deleteImages(r, previous.StorageKey, previous.ThumbnailKey)
+		status = http.StatusOK
+	}
+	writeJSON(w, r, status, photo)
+}
+
+// GetPhoto handles GET /api/v1/applicants/{id}/photo
+// @Summary Download an applicant's photo
+// @Description Download an applicant's photo, by default as a thumbnail. Not available to the viewer role.
+// @Tags applicants
+// @Produce image/jpeg,image/png
+// @Param id path string true "Applicant ID"
+// @Param size query string false "Size of the image (default thumbnail)" Enums(thumbnail, original)
+// @Success 200 {file} file "The photo, as a JPEG or PNG"
+// @Failure 400 {object} apierrors.APIError "Invalid size"
+// @Failure 403 {object} apierrors.APIError "Forbidden"
+// @Failure 404 {object} apierrors.APIError "Photo not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applicants/{id}/photo [get]
+func (h *PhotoHandler) GetPhoto(w http.ResponseWriter, r *http.Request) {
+	// Photos identify applicants, so like documents they are not
+	// available to viewers
+	if hasRole(r, auth.RoleViewer) {
+		apierrors.Write(w, r, apierrors.Forbidden("Photos cannot be downloaded by the "+auth.RoleViewer+" role"))
+		return
+	}
+
+	size := r.URL.Query().Get("size")
+	if size != "" && size != photoSizeThumbnail && size != photoSizeOriginal {
+		apierrors.Write(w, r, apierrors.BadRequest("size must be "+photoSizeThumbnail+" or "+photoSizeOriginal))
+		return
+	}
+
+	photo := h.photo(w, r)
+	if photo == nil {
+		return
+	}
+
+	key, contentType := photo.ThumbnailKey, photo.ThumbnailContentType
+	if size == photoSizeOriginal {
+		key, contentType = photo.StorageKey, photo.ContentType
+		w.Header().Set("Content-Length", strconv.FormatInt(photo.Size, 10))
+	}
+
+	content, err := h.Store.Get(r.Context(), key)
+	if errors.Is(err, storage.ErrNotFound) {
+		apierrors.Write(w, r, apierrors.Internal("Photo content is missing", err))
+		return
+	}
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get photo", err))
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "private, no-cache")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if _, err := io.Copy(w, content); err != nil {
+		logging.FromContext(r.Context()).Warn("Failed to send photo", "applicant_id", photo.ApplicantID, "error", err)
+	}
+}
+
+// DeletePhoto handles DELETE /api/v1/applicants/{id}/photo
+// @Summary Delete an applicant's photo
+// @Description Remove an applicant's photo and delete its images
+// @Tags applicants
+// @Produce json
+// @Param id path string true "Applicant ID"
+// @Success 204 "No content"
+// @Failure 404 {object} apierrors.APIError "Photo not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applicants/{id}/photo [delete]
+func (h *PhotoHandler) DeletePhoto(w http.ResponseWriter, r *http.Request) {
+	photo := h.photo(w, r)
+	if photo == nil {
+		return
+	}
+
+	err := models.WithTx(h.PhotoRepo.DB, func(tx *sql.Tx) error {
+		if err := h.PhotoRepo.WithTx(tx).Delete(photo.ApplicantID); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityPhoto, photo.ApplicantID,
+			models.AuditActionDelete, actorFrom(r), photo, nil)
+	})
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to delete photo", err))
+		return
+	}
+
+	h.deleteImages(r, photo.StorageKey, photo.ThumbnailKey)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// deleteImages deletes stored images no longer referred to. A failure only
+// leaves an unreachable file, so it is logged.
+func (h *PhotoHandler) deleteImages(r *http.Request, keys ...string) {
+	for _, key := range keys {
+		if err := h.Store.Delete(r.Context(), key); err != nil {
+			logging.FromContext(r.Context()).Error("Failed to delete photo image", "key", key, "error", err)
+		}
+	}
+}
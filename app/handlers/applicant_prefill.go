@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/integration"
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/validation"
+)
+
+// PrefillHandler handles requests to prefill new applicants from an external
+// registry
+type PrefillHandler struct {
+	Provider      integration.PersonDataProvider // Nil when prefill is not configured
+	ApplicantRepo *models.ApplicantRepository
+}
+
+// NewPrefillHandler creates a new handler with the given provider, which may
+// be nil, and repository
+func NewPrefillHandler(provider integration.PersonDataProvider, applicantRepo *models.ApplicantRepository) *PrefillHandler {
+	return &PrefillHandler{Provider: provider, ApplicantRepo: applicantRepo}
+}
+
+// PrefillApplicant handles POST /api/v1/applicants/prefill
+// @Summary Prefill an applicant from an external registry
+// @Description Fetch what the configured registry, such as MyInfo, holds about a person: their name, sex, date of birth, marital status, registered address and people who may belong in their household, such as children from birth records. Nothing is saved; review the data, add employment and income, and create the applicant as usual. If the person is already an applicant, existing_applicant_id is set.
+// @Tags applicants
+// @Produce json
+// @Param nric query string true "NRIC or FIN"
+// @Success 200 {object} models.ApplicantPrefill
+// @Failure 400 {object} apierrors.APIError "Invalid NRIC or FIN"
+// @Failure 404 {object} apierrors.APIError "Person not found in the registry"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Failure 502 {object} apierrors.APIError "Registry request failed"
+// @Failure 503 {object} apierrors.APIError "Prefill not configured"
+// @Security BearerAuth
+// @Router /api/v1/applicants/prefill [post]
+func (h *PrefillHandler) PrefillApplicant(w http.ResponseWriter, r *http.Request) {
+	if h.Provider == nil {
+		apierrors.Write(w, r, apierrors.ServiceUnavailable("Prefill is not configured"))
+		return
+	}
+
+	nric := strings.ToUpper(strings.TrimSpace(r.URL.Query().Get("nric")))
+	if !validation.ValidIdentityNumber(nric) {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid NRIC or FIN"))
+		return
+	}
+
+	prefill, err := h.Provider.Person(r.Context(), nric)
+	if errors.Is(err, integration.ErrNotFound) {
+		apierrors.Write(w, r, apierrors.NotFound("Person not found in "+h.Provider.Name()))
+		return
+	}
+	if err != nil {
+		apierrors.Write(w, r, apierrors.BadGateway("Failed to get person from "+h.Provider.Name(), err))
+		return
+	}
+	prefill.Source = h.Provider.Name()
+
+	existing, err := h.ApplicantRepo.GetByIdentityNumber(nric)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applicant", err))
+		return
+	}
+	if existing != nil {
+		prefill.ExistingApplicantID = existing.ID
+	}
+
+	writeJSON(w, r, http.StatusOK, prefill)
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/logging"
+	"one-client-view-2025tht/app/models"
+)
+
+// ndjsonContentType is the media type of newline-delimited JSON streams
+const ndjsonContentType = "application/x-ndjson"
+
+// streamFlushEvery is how many lines a stream writes between flushes
+const streamFlushEvery = 100
+
+// streamWriteTimeout is how long a stream may take to write the lines
+// between flushes. The deadline is extended at each flush, so the server's
+// write timeout does not cut off streams that are still moving.
+const streamWriteTimeout = time.Minute
+
+// StreamApplicants handles GET /api/v1/applicants/stream
+// @Summary Stream applicants
+// @Description Stream the applicants matching the filters of the list endpoint as newline-delimited JSON, one applicant with their household per line, in order of ID. Applicants are written as they are read rather than held in memory, so any number can be downloaded. To resume an interrupted download, or fetch the next page after a limit, pass the ID of the last applicant received as after. An error once the stream has begun closes the connection, so the stream is seen to be incomplete.
+// @Tags applicants
+// @Produce application/x-ndjson
+// @Param after query string false "ID of the applicant to start after; from the start if omitted"
+// @Param limit query int false "Most applicants to stream; all if omitted"
+// @Param name query string false "Partial, case-insensitive match on name"
+// @Param employment_status query string false "Employment status" Enums(employed, unemployed)
+// @Param marital_status query string false "Marital status" Enums(single, married, widowed, divorced)
+// @Param sex query string false "Sex" Enums(male, female, other)
+// @Param postal_district query string false "Two-digit postal district of the applicant's address, 01 to 28"
+// @Param min_age query int false "Minimum age in years"
+// @Param max_age query int false "Maximum age in years"
+// @Param include_deleted query bool false "Include soft-deleted applicants (admin only)"
+// @Param view query string false "full (the default) or minimal, which masks identity numbers, dates of birth and phone numbers, reduces addresses to their postal district and omits household members; viewers always get minimal" Enums(full, minimal)
+// @Param fields query string false "Comma-separated fields to return, such as id,name,household.name; all if omitted"
+// @Success 200 {file} file "One models.ApplicantResponse per line"
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 403 {object} apierrors.APIError "Forbidden"
+// @Failure 404 {object} apierrors.APIError "The applicants.stream feature flag is off"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applicants/stream [get]
+func (h *ApplicantHandler) StreamApplicants(w http.ResponseWriter, r *http.Request) {
+	filter, apiErr := h.applicantFilterParams(r)
+	if apiErr != nil {
+		apierrors.Write(w, r, apiErr)
+		return
+	}
+
+	query := r.URL.Query()
+	limit := 0
+	if value := query.Get("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 {
+			apierrors.Write(w, r, apierrors.BadRequest("limit must be a positive integer"))
+			return
+		}
+	}
+
+	ctx := r.Context()
+	rc := http.NewResponseController(w)
+	rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+	enc := json.NewEncoder(w)
+	started := false
+	written := 0
+
+	err := h.ApplicantRepo.Stream(filter, query.Get("after"), limit, func(a models.Applicant) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !started {
+			w.Header().Set("Content-Type", ndjsonContentType)
+			w.WriteHeader(http.StatusOK)
+			started = true
+		}
+		if err := enc.Encode(models.ApplicantResponse{Applicant: a, Household: a.Household}); err != nil {
+			return err
+		}
+		written++
+		if written%streamFlushEvery == 0 {
+			rc.Flush()
+			rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+		}
+		return nil
+	})
+	if err != nil {
+		if !started {
+			apierrors.Write(w, r, apierrors.Internal("Failed to stream applicants", err))
+			return
+		}
+		// The status has been sent, so the connection is cut for the client
+		// to see the stream is incomplete, and resume it with after
+		if ctx.Err() == nil {
+			logging.FromContext(ctx).Error("Failed to stream applicants", "error", err, "written", written)
+		}
+		panic(http.ErrAbortHandler)
+	}
+
+	if !started {
+		w.Header().Set("Content-Type", ndjsonContentType)
+		w.WriteHeader(http.StatusOK)
+	}
+}
@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/models"
+)
+
+// timelineTypes are the types of timeline entries, in the order they would
+// have happened in when recorded at the same time
+var timelineTypes = []string{
+	models.TimelineChange,
+	models.TimelineApplication,
+	models.TimelineStatusChange,
+	models.TimelineDocument,
+	models.TimelineCaseNote,
+	models.TimelineTask,
+	models.TimelineReferral,
+}
+
+// GetApplicantTimeline handles GET /api/v1/applicants/{id}/timeline
+// @Summary Get an applicant's timeline
+// @Description List everything that has happened to an applicant in one feed, newest first: changes to their record from the audit log, their applications and each change of an application's status, the documents attached, case notes, tasks such as appointments, and referrals to other agencies. Each entry's type names the member holding its record. Entries of deleted applications are left out. The number of queries does not grow with the number of applications.
+// @Tags applicants
+// @Produce json
+// @Param id path string true "Applicant ID"
+// @Param type query string false "Comma-separated types of entries to list; all if omitted, e.g. application,status_change"
+// @Param from query string false "Only entries at or after this time (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Only entries before this time (RFC3339 or YYYY-MM-DD)"
+// @Param limit query int false "Most entries to list, newest first; all if omitted"
+// @Param view query string false "full (the default) or minimal, which masks identity numbers, dates of birth and phone numbers, reduces addresses to their postal district and omits household members and the text of case notes; viewers always get minimal" Enums(full, minimal)
+// @Param fields query string false "Comma-separated fields to return, such as type,occurred_at; all if omitted"
+// @Success 200 {array} models.TimelineEntry
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 404 {object} apierrors.APIError "Applicant not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applicants/{id}/timeline [get]
+func (h *ProfileHandler) GetApplicantTimeline(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	types := map[string]bool{}
+	for _, t := range timelineTypes {
+		types[t] = !query.Has("type")
+	}
+	if query.Has("type") {
+		for _, t := range strings.Split(query.Get("type"), ",") {
+			if _, ok := types[strings.TrimSpace(t)]; !ok {
+				apierrors.Write(w, r, apierrors.BadRequest("Invalid type").
+					WithDetails("type must be a comma-separated list of "+strings.Join(timelineTypes, ", ")))
+				return
+			}
+			types[strings.TrimSpace(t)] = true
+		}
+	}
+	var filter models.AuditFilter
+	var err error
+	if filter.From, err = parseTimeParam(query.Get("from")); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid from").WithDetails(err.Error()))
+		return
+	}
+	if filter.To, err = parseTimeParam(query.Get("to")); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid to").WithDetails(err.Error()))
+		return
+	}
+	limit := 0
+	if value := query.Get("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 {
+			apierrors.Write(w, r, apierrors.BadRequest("limit must be a positive integer"))
+			return
+		}
+	}
+
+	id := mux.Vars(r)["id"]
+	applicant, err := h.ApplicantCache.GetByID(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applicant", err))
+		return
+	}
+	if applicant == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Applicant not found"))
+		return
+	}
+
+	var entries []models.TimelineEntry
+	add := func(entry models.TimelineEntry) {
+		if !types[entry.Type] || entry.OccurredAt.Before(filter.From) ||
+			(!filter.To.IsZero() && !entry.OccurredAt.Before(filter.To)) {
+			return
+		}
+		entries = append(entries, entry)
+	}
+
+	if types[models.TimelineChange] {
+		changes, err := h.AuditRepo.History(models.AuditEntityApplicant, id, "", filter)
+		if err != nil {
+			apierrors.Write(w, r, apierrors.Internal("Failed to get applicant history", err))
+			return
+		}
+		for i := range changes {
+			c := &changes[i]
+			add(models.TimelineEntry{Type: models.TimelineChange, OccurredAt: c.ChangedAt,
+				ActorID: c.ActorID, ActorUsername: c.ActorUsername, Change: c})
+		}
+	}
+
+	applications, err := h.ApplicationRepo.GetByApplicantID(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applications", err))
+		return
+	}
+	applicationIDs := make([]string, len(applications))
+	for i := range applications {
+		a := &applications[i]
+		applicationIDs[i] = a.ID
+		add(models.TimelineEntry{Type: models.TimelineApplication, OccurredAt: a.CreatedAt,
+			ApplicationID: a.ID, Application: a})
+	}
+
+	if types[models.TimelineStatusChange] {
+		events, err := h.EventRepo.ForApplications(applicationIDs)
+		if err != nil {
+			apierrors.Write(w, r, apierrors.Internal("Failed to get application events", err))
+			return
+		}
+		for i := range events {
+			e := &events[i]
+			if !e.ChangesStatus() {
+				continue
+			}
+			add(models.TimelineEntry{Type: models.TimelineStatusChange, OccurredAt: e.OccurredAt,
+				ApplicationID: e.ApplicationID, ActorID: e.ActorID, ActorUsername: e.ActorUsername, StatusChange: e})
+		}
+	}
+
+	if types[models.TimelineDocument] {
+		documents, err := h.DocumentRepo.GetByApplicationIDs(applicationIDs)
+		if err != nil {
+			apierrors.Write(w, r, apierrors.Internal("Failed to get documents", err))
+			return
+		}
+		for _, list := range documents {
+			for i := range list {
+				d := &list[i]
+				add(models.TimelineEntry{Type: models.TimelineDocument, OccurredAt: d.CreatedAt,
+					ApplicationID: d.ApplicationID, ActorID: d.UploadedBy, Document: d})
+			}
+		}
+	}
+
+	if types[models.TimelineCaseNote] {
+		notes, err := h.CaseNoteRepo.GetByApplicantID(id, "")
+		if err != nil {
+			apierrors.Write(w, r, apierrors.Internal("Failed to get case notes", err))
+			return
+		}
+		for i := range notes {
+			n := &notes[i]
+			add(models.TimelineEntry{Type: models.TimelineCaseNote, OccurredAt: n.CreatedAt,
+				ActorID: n.AuthorID, ActorUsername: n.AuthorUsername, CaseNote: n})
+		}
+	}
+
+	if types[models.TimelineTask] {
+		tasks, err := h.TaskRepo.Find(models.TaskFilter{ApplicantID: id})
+		if err != nil {
+			apierrors.Write(w, r, apierrors.Internal("Failed to get tasks", err))
+			return
+		}
+		for i := range tasks {
+			t := &tasks[i]
+			add(models.TimelineEntry{Type: models.TimelineTask, OccurredAt: t.CreatedAt,
+				ApplicationID: t.ApplicationID, ActorID: t.CreatedBy, Task: t})
+		}
+	}
+
+	if types[models.TimelineReferral] {
+		referrals, err := h.ReferralRepo.GetByApplicantID(id, "")
+		if err != nil {
+			apierrors.Write(w, r, apierrors.Internal("Failed to get referrals", err))
+			return
+		}
+		for i := range referrals {
+			f := &referrals[i]
+			add(models.TimelineEntry{Type: models.TimelineReferral, OccurredAt: f.CreatedAt,
+				ActorID: f.CreatedBy, Referral: f})
+		}
+	}
+
+	sortTimeline(entries)
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	writeList(w, r, entries, limit)
+}
+
+// sortTimeline orders timeline entries newest first. Entries of the same
+// time, such as an application and the document uploaded with it, are listed
+// in the reverse order of timelineTypes, as later entries are.
+func sortTimeline(entries []models.TimelineEntry) {
+	rank := make(map[string]int, len(timelineTypes))
+	for i, t := range timelineTypes {
+		rank[t] = i
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if !a.OccurredAt.Equal(b.OccurredAt) {
+			return a.OccurredAt.After(b.OccurredAt)
+		}
+		return rank[a.Type] > rank[b.Type]
+	})
+}
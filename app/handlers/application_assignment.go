@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/auth"
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/validation"
+)
+
+// AssignApplication handles POST /api/v1/applications/{id}/assign
+// @Summary Assign application
+// @Description Assign a pending application for review to a caseworker or admin, the authenticated user if user_id is omitted. Caseworkers can only assign applications to themselves, and only those not assigned to someone else; admins can assign and reassign to anyone.
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param assignment body models.AssignRequest false "User to assign the application to"
+// @Success 200 {object} models.SwaggerApplicationResponse
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 403 {object} apierrors.APIError "Caseworkers can only assign unassigned applications to themselves"
+// @Failure 404 {object} apierrors.APIError "Application not found"
+// @Failure 409 {object} apierrors.APIError "Application has already been decided, or version conflict"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applications/{id}/assign [post]
+func (h *ApplicationHandler) AssignApplication(w http.ResponseWriter, r *http.Request) {
+	var request models.AssignRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil && !errors.Is(err, io.EOF) {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
+		return
+	}
+
+	actor := actorFrom(r)
+	if request.UserID == "" {
+		request.UserID = actor.ID
+	}
+
+	existing, ok := h.assignable(w, r)
+	if !ok {
+		return
+	}
+	if !hasRole(r, auth.RoleAdmin) &&
+		(request.UserID != actor.ID || (existing.AssignedTo != "" && existing.AssignedTo != actor.ID)) {
+		apierrors.Write(w, r, apierrors.Forbidden("Caseworkers can only assign unassigned applications to themselves"))
+		return
+	}
+
+	assignee, err := assignableUser(h.UserRepo, request.UserID)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get user", err))
+		return
+	}
+	if assignee == nil {
+		apierrors.Write(w, r, validationError(validation.Errors{"user_id": "must be a caseworker or admin"}))
+		return
+	}
+
+	h.assign(w, r, existing, assignee.ID, models.AuditActionAssign)
+}
+
+// UnassignApplication handles POST /api/v1/applications/{id}/unassign
+// @Summary Unassign application
+// @Description Remove the assignment of a pending application, returning it to the unassigned queue. Caseworkers can only unassign applications assigned to themselves.
+// @Tags applications
+// @Produce json
+// @Param id path string true "Application ID"
+// @Success 200 {object} models.SwaggerApplicationResponse
+// @Failure 403 {object} apierrors.APIError "Caseworkers can only unassign their own applications"
+// @Failure 404 {object} apierrors.APIError "Application not found"
+// @Failure 409 {object} apierrors.APIError "Application has already been decided, or version conflict"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applications/{id}/unassign [post]
+func (h *ApplicationHandler) UnassignApplication(w http.ResponseWriter, r *http.Request) {
+	existing, ok := h.assignable(w, r)
+	if !ok {
+		return
+	}
+	if !hasRole(r, auth.RoleAdmin) && existing.AssignedTo != actorFrom(r).ID {
+		apierrors.Write(w, r, apierrors.Forbidden("Caseworkers can only unassign their own applications"))
+		return
+	}
+
+	h.assign(w, r, existing, "", models.AuditActionUnassign)
+}
+
+// assignable gets the pending application named in the path, writing an
+// error response and returning false if there is none
+func (h *ApplicationHandler) assignable(w http.ResponseWriter, r *http.Request) (*models.Application, bool) {
+	existing, err := h.ApplicationRepo.GetByID(mux.Vars(r)["id"])
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get application", err))
+		return nil, false
+	}
+	if existing == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Application not found"))
+		return nil, false
+	}
+	if existing.Status != "pending" {
+		apierrors.Write(w, r, apierrors.Conflict("Application has already been decided"))
+		return nil, false
+	}
+	return existing, true
+}
+
+// assign assigns the application to userID, or unassigns it if empty,
+// recording the audit action given, and writes the updated application
+func (h *ApplicationHandler) assign(w http.ResponseWriter, r *http.Request, existing *models.Application, userID, action string) {
+	before := applicationSnapshot(existing)
+	err := models.WithTx(h.ApplicationRepo.DB, func(tx *sql.Tx) error {
+		if err := h.ApplicationRepo.WithTx(tx).Assign(existing, userID); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityApplication, existing.ID,
+			action, actorFrom(r), before, applicationSnapshot(existing))
+	})
+	if errors.Is(err, models.ErrVersionConflict) {
+		apierrors.Write(w, r, versionConflict())
+		return
+	}
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to assign application", err))
+		return
+	}
+
+	if existing.Applicant == nil || existing.Scheme == nil {
+		apierrors.Write(w, r, apierrors.Internal("Invalid application data", nil))
+		return
+	}
+
+	response := models.ApplicationResponse{
+		Application: *existing,
+		Applicant: &models.ApplicantResponse{
+			Applicant: *existing.Applicant,
+			Household: existing.Applicant.Household,
+		},
+		Scheme: &models.SchemeResponse{
+			Scheme:   *existing.Scheme,
+			Benefits: existing.Scheme.Benefits,
+		},
+	}
+
+	setETag(w, existing.Version)
+	writeJSON(w, r, http.StatusOK, response)
+}
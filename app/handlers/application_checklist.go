@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/validation"
+)
+
+// GetApplicationChecklist handles GET /api/v1/applications/{id}/checklist
+// @Summary Get an application's review checklist
+// @Description List the items of an application's review checklist, copied from its scheme's when it was created, by position, then name, with who completed each and when. Mandatory items must be completed before the application is approved.
+// @Tags applications
+// @Produce json
+// @Param id path string true "Application ID"
+// @Success 200 {array} models.ApplicationChecklistItem
+// @Failure 404 {object} apierrors.APIError "Application not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applications/{id}/checklist [get]
+func (h *ApplicationHandler) GetApplicationChecklist(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	application, err := h.ApplicationRepo.GetByID(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get application", err))
+		return
+	}
+	if application == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Application not found"))
+		return
+	}
+
+	items, err := h.ApplicationRepo.GetChecklist(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get checklist", err))
+		return
+	}
+
+	writeList(w, r, items, 0)
+}
+
+// TickChecklistItem handles PUT /api/v1/applications/{id}/checklist/{itemId}
+// @Summary Tick or untick an application checklist item
+// @Description Mark an item of a pending application's review checklist completed by the authenticated user, with an optional note, or not completed. Items of decided or withdrawn applications cannot be changed.
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param itemId path string true "Checklist item ID"
+// @Param tick body models.ChecklistTickRequest true "Whether the item is completed"
+// @Success 200 {object} models.ApplicationChecklistItem
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 404 {object} apierrors.APIError "Application or checklist item not found"
+// @Failure 409 {object} apierrors.APIError "Application is no longer pending"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applications/{id}/checklist/{itemId} [put]
+func (h *ApplicationHandler) TickChecklistItem(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	application, err := h.ApplicationRepo.GetByID(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get application", err))
+		return
+	}
+	if application == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Application not found"))
+		return
+	}
+
+	existing, err := h.ApplicationRepo.GetChecklistItem(id, vars["itemId"])
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get checklist item", err))
+		return
+	}
+	if existing == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Checklist item not found"))
+		return
+	}
+
+	var request models.ChecklistTickRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
+		return
+	}
+	request.Note = strings.TrimSpace(request.Note)
+
+	if err := validation.ChecklistTickRequest(&request); err != nil {
+		apierrors.Write(w, r, validationError(err))
+		return
+	}
+
+	actor := actorFrom(r)
+	item := *existing
+	err = models.WithTx(h.ApplicationRepo.DB, func(tx *sql.Tx) error {
+		if err := h.ApplicationRepo.WithTx(tx).TickChecklistItem(&item, request.Completed, actor.ID, request.Note); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityApplicationChecklist, item.ID,
+			models.AuditActionUpdate, actor, existing, &item)
+	})
+	if errors.Is(err, models.ErrAlreadyDecided) {
+		apierrors.Write(w, r, apierrors.Conflict("Application is no longer pending").
+			WithDetails("only the checklists of pending applications can be changed; this one is "+application.Status))
+		return
+	}
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to update checklist item", err))
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, item)
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/auth"
+	"one-client-view-2025tht/app/features"
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/validation"
+)
+
+// ApproveApplication handles POST /api/v1/applications/{id}/approve
+// @Summary Approve application
+// @Description Approve a pending application, recording the approver, the reason and optionally a recommended benefit amount. Every mandatory item of the application's review checklist must have been completed. The approval takes up the scheme's capacity: its recommended amount, or the projected value of the applicant's entitlement to the scheme's benefits with benefit formulas applied to their household, counts against any budget. Requires the admin role.
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param decision body models.DecisionRequest true "Reason for the decision"
+// @Success 200 {object} models.SwaggerApplicationResponse
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 403 {object} apierrors.APIError "Requires the admin role"
+// @Failure 404 {object} apierrors.APIError "Application not found"
+// @Failure 409 {object} apierrors.APIError "Application has already been decided, has mandatory checklist items not completed, or scheme has no remaining capacity"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applications/{id}/approve [post]
+func (h *ApplicationHandler) ApproveApplication(w http.ResponseWriter, r *http.Request) {
+	h.decideApplication(w, r, "approved", models.AuditActionApprove, models.EventApplicationApproved)
+}
+
+// RejectApplication handles POST /api/v1/applications/{id}/reject
+// @Summary Reject application
+// @Description Reject a pending application, recording who rejected it and why. Requires the admin role.
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param decision body object{reason=string} true "Reason for the decision"
+// @Success 200 {object} models.SwaggerApplicationResponse
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 403 {object} apierrors.APIError "Requires the admin role"
+// @Failure 404 {object} apierrors.APIError "Application not found"
+// @Failure 409 {object} apierrors.APIError "Application has already been decided"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applications/{id}/reject [post]
+func (h *ApplicationHandler) RejectApplication(w http.ResponseWriter, r *http.Request) {
+	h.decideApplication(w, r, "rejected", models.AuditActionReject, models.EventApplicationRejected)
+}
+
+// decideApplication approves or rejects an application on behalf of the
+// authenticated user, recording the audit action and queueing the webhook
+// event given
+func (h *ApplicationHandler) decideApplication(w http.ResponseWriter, r *http.Request, status, action, event string) {
+	if !hasRole(r, auth.RoleAdmin) {
+		apierrors.Write(w, r, apierrors.Forbidden("Deciding applications requires the admin role"))
+		return
+	}
+
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	existing, err := h.ApplicationRepo.GetByID(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get application", err))
+		return
+	}
+	if existing == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Application not found"))
+		return
+	}
+
+	var request models.DecisionRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
+		return
+	}
+
+	if err := validation.DecisionRequest(&request, status == "approved"); err != nil {
+		apierrors.Write(w, r, validationError(err))
+		return
+	}
+
+	actor := actorFrom(r)
+	before := applicationSnapshot(existing)
+
+	err = models.WithTx(h.ApplicationRepo.DB, func(tx *sql.Tx) error {
+		applicationRepo := h.ApplicationRepo.WithTx(tx)
+		applicationRepo.BaseCommitments = !h.Flags.Enabled(r.Context(), features.FormulaCommitments)
+		if err := applicationRepo.Decide(existing, status, actor.ID,
+			request.Reason, request.RecommendedBenefitAmount); err != nil {
+			return err
+		}
+		if err := h.AuditRepo.WithTx(tx).Record(models.AuditEntityApplication, id,
+			action, actor, before, applicationSnapshot(existing)); err != nil {
+			return err
+		}
+		return h.WebhookRepo.WithTx(tx).Enqueue(event, applicationSnapshot(existing))
+	})
+	if errors.Is(err, models.ErrAlreadyDecided) {
+		apierrors.Write(w, r, apierrors.Conflict("Application has already been decided"))
+		return
+	}
+	if errors.Is(err, models.ErrChecklistIncomplete) {
+		apierrors.Write(w, r, apierrors.Conflict("Application has mandatory checklist items not completed").
+			WithDetails(err.Error()))
+		return
+	}
+	if errors.Is(err, models.ErrCapacityExhausted) {
+		apierrors.Write(w, r, apierrors.Conflict("Scheme has no remaining capacity"))
+		return
+	}
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to decide application", err))
+		return
+	}
+	if status == "approved" {
+		h.SchemeCache.Invalidate()
+	}
+
+	if existing.Applicant == nil || existing.Scheme == nil {
+		apierrors.Write(w, r, apierrors.Internal("Invalid application data", nil))
+		return
+	}
+
+	h.Notifier.ApplicationStatus(existing)
+
+	response := models.ApplicationResponse{
+		Application: *existing,
+		Applicant: &models.ApplicantResponse{
+			Applicant: *existing.Applicant,
+			Household: existing.Applicant.Household,
+		},
+		Scheme: &models.SchemeResponse{
+			Scheme:   *existing.Scheme,
+			Benefits: existing.Scheme.Benefits,
+		},
+	}
+
+	setETag(w, existing.Version)
+	writeJSON(w, r, http.StatusOK, response)
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/auth"
+	"one-client-view-2025tht/app/cache"
+	"one-client-view-2025tht/app/database"
+	"one-client-view-2025tht/app/date"
+	"one-client-view-2025tht/app/encryption"
+	"one-client-view-2025tht/app/features"
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/money"
+)
+
+// approveWithFlags approves an application of an applicant with two children
+// for a scheme paying 100.00 plus 100.00 per child, with the given feature
+// flags turned off, and returns the amount the scheme then has approved
+func approveWithFlags(t *testing.T, disabled []string) money.Amount {
+	t.Helper()
+	db, err := database.Initialize(&database.Config{
+		Driver: database.DriverSQLite,
+		Path:   filepath.Join(t.TempDir(), "test.sqlite"),
+	})
+	if err != nil {
+		t.Fatalf("initializing database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	cipher, err := encryption.New([]byte(strings.Repeat("t", 32)))
+	if err != nil {
+		t.Fatalf("creating cipher: %v", err)
+	}
+	applicantRepo := models.NewApplicantRepository(db.DB, cipher)
+	schemeRepo := models.NewSchemeRepository(db.DB)
+	applicationRepo := models.NewApplicationRepository(db.DB, applicantRepo, schemeRepo)
+	flags, err := features.New(models.NewFeatureFlagRepository(db.DB), nil, disabled, time.Minute)
+	if err != nil {
+		t.Fatalf("creating feature flags: %v", err)
+	}
+	h := NewApplicationHandler(applicationRepo, applicantRepo, schemeRepo,
+		models.NewCachedSchemeStore(schemeRepo, cache.NewMemory(), time.Minute),
+		models.NewAuditRepository(db.DB), models.NewWebhookRepository(db.DB),
+		nil, nil, nil, nil, nil, nil, flags)
+
+	scheme := &models.Scheme{
+		Name:        "Family grant",
+		Description: "Scaled with the household",
+		IsActive:    true,
+		Status:      models.SchemePublished,
+		Benefits: []models.Benefit{{
+			Name:    "Grant",
+			Amount:  money.Amount(10000),
+			Formula: &models.BenefitFormula{PerChildAmount: money.Amount(10000)},
+		}},
+	}
+	if err := schemeRepo.Create(scheme); err != nil {
+		t.Fatalf("creating scheme: %v", err)
+	}
+	applicant := &models.Applicant{
+		Name:             "Tan Ah Kow",
+		EmploymentStatus: "unemployed",
+		Sex:              "male",
+		DateOfBirth:      date.New(1970, 1, 1),
+		MaritalStatus:    "married",
+		Household: []models.HouseholdMember{
+			{Name: "Child 1", Relation: models.RelationSon, Sex: "male", EmploymentStatus: "unemployed", DateOfBirth: date.New(2016, 1, 1)},
+			{Name: "Child 2", Relation: models.RelationDaughter, Sex: "female", EmploymentStatus: "unemployed", DateOfBirth: date.New(2018, 1, 1)},
+		},
+	}
+	if err := applicantRepo.Create(applicant); err != nil {
+		t.Fatalf("creating applicant: %v", err)
+	}
+	application := &models.Application{ApplicantID: applicant.ID, SchemeID: scheme.ID}
+	if err := applicationRepo.Create(application); err != nil {
+		t.Fatalf("creating application: %v", err)
+	}
+
+	r := httptest.NewRequest("POST", "/api/v1/applications/"+application.ID+"/approve",
+		strings.NewReader(`{"reason": "Documents verified"}`))
+	r = mux.SetURLVars(r, map[string]string{"id": application.ID})
+	r = r.WithContext(auth.NewContext(r.Context(), &auth.Claims{Username: "admin", Role: auth.RoleAdmin}))
+	w := httptest.NewRecorder()
+	h.ApproveApplication(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("approving application: status %d: %s", w.Code, w.Body)
+	}
+
+	approved, err := schemeRepo.GetByID(scheme.ID)
+	if err != nil {
+		t.Fatalf("getting scheme: %v", err)
+	}
+	return approved.ApprovedAmount
+}
+
+func TestApprovalCommitmentFollowsFormulaFlag(t *testing.T) {
+	if got := approveWithFlags(t, nil); got != money.Amount(30000) {
+		t.Errorf("with %s on, approved amount = %v, want 300.00", features.FormulaCommitments, got)
+	}
+	if got := approveWithFlags(t, []string{features.FormulaCommitments}); got != money.Amount(10000) {
+		t.Errorf("with %s off, approved amount = %v, want 100.00", features.FormulaCommitments, got)
+	}
+}
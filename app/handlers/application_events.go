@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+)
+
+// GetApplicationEvents handles GET /api/v1/applications/{id}/events
+// @Summary Get an application's events
+// @Description List every transition of an application as an immutable event, oldest first, including those of a deleted application. Create, anonymize and backfill events hold the whole application and the others the fields they set, so replaying them in order rebuilds the application's current state. Each event has a version counting up from 1 within the application and a sequence ordering events across applications. The data of events of purged and anonymized applications is dropped.
+// @Tags applications
+// @Produce json
+// @Param id path string true "Application ID"
+// @Success 200 {array} models.ApplicationEvent
+// @Failure 404 {object} apierrors.APIError "Application not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applications/{id}/events [get]
+func (h *ApplicationHandler) GetApplicationEvents(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	application, err := h.ApplicationRepo.GetByIDIncludingDeleted(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get application", err))
+		return
+	}
+	if application == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Application not found"))
+		return
+	}
+
+	events, err := h.EventRepo.ForApplication(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get application events", err))
+		return
+	}
+
+	writeList(w, r, events, 0)
+}
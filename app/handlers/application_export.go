@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/models"
+)
+
+// Supported export formats
+const (
+	exportFormatCSV  = "csv"
+	exportFormatXLSX = "xlsx"
+)
+
+// exportHeader lists the columns of an application export
+var exportHeader = []string{
+	"Application ID",
+	"Status",
+	"Application Date",
+	"Decision Date",
+	"Decided By",
+	"Decision Reason",
+	"Recommended Benefit Amount",
+	"Notes",
+	"Applicant ID",
+	"Applicant Name",
+	"Scheme ID",
+	"Scheme Name",
+	"Created At",
+	"Updated At",
+}
+
+// spreadsheetText escapes free text for a spreadsheet cell. Text starting
+// with a character spreadsheets read as the start of a formula is prefixed
+// with an apostrophe, so that a value such as =HYPERLINK(...) entered by a
+// user is shown rather than run when the export is opened.
+func spreadsheetText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// exportRow flattens an application, with its applicant and scheme names,
+// into a row matching exportHeader
+func exportRow(a models.Application) []string {
+	var applicantName, schemeName, decisionDate, recommendedAmount string
+	if a.Applicant != nil {
+		applicantName = spreadsheetText(a.Applicant.Name)
+	}
+	if a.Scheme != nil {
+		schemeName = spreadsheetText(a.Scheme.Name)
+	}
+	if a.DecisionDate != nil {
+		decisionDate = a.DecisionDate.Format(time.RFC3339)
+	}
+	if a.RecommendedBenefitAmount != nil {
+		recommendedAmount = a.RecommendedBenefitAmount.String()
+	}
+
+	return []string{
+		a.ID,
+		a.Status,
+		a.ApplicationDate.String(),
+		decisionDate,
+		a.DecidedBy,
+		spreadsheetText(a.DecisionReason),
+		recommendedAmount,
+		spreadsheetText(a.Notes),
+		a.ApplicantID,
+		applicantName,
+		a.SchemeID,
+		schemeName,
+		a.CreatedAt.Format(time.RFC3339),
+		a.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// ExportApplications handles GET /api/v1/applications/export
+// @Summary Export applications
+// @Description Download all applications, newest first, with applicant and scheme names, as a CSV or Excel file, streamed in batches. Text that spreadsheets would run as a formula is prefixed with an apostrophe. Applicants are only named if they consent to data_sharing; otherwise only their ID is included. Accepts the same filters as the list endpoint.
+// @Tags applications
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "Export format" Enums(csv, xlsx) default(csv)
+// @Param status query string false "Status" Enums(pending, approved, rejected, withdrawn)
+// @Param scheme_id query string false "Scheme ID"
+// @Param applicant_id query string false "Applicant ID"
+// @Param applied_after query string false "Only applications made on or after this date (YYYY-MM-DD)"
+// @Param applied_before query string false "Only applications made before this date (YYYY-MM-DD)"
+// @Param assigned_to query string false "User ID the applications are assigned to, me for the authenticated user, or none for unassigned applications"
+// @Param include_deleted query bool false "Include soft-deleted applications (admin only)"
+// @Success 200 {file} file "Export file"
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 403 {object} apierrors.APIError "Forbidden"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applications/export [get]
+func (h *ApplicationHandler) ExportApplications(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = exportFormatCSV
+	}
+	if format != exportFormatCSV && format != exportFormatXLSX {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid format").
+			WithDetails("format must be one of: csv, xlsx"))
+		return
+	}
+
+	filter, apiErr := applicationFilterParams(r)
+	if apiErr != nil {
+		apierrors.Write(w, r, apiErr)
+		return
+	}
+
+	var rows exportWriter
+	switch format {
+	case exportFormatXLSX:
+		xlsx, err := newXLSXExportWriter(w)
+		if err != nil {
+			apierrors.Write(w, r, apierrors.Internal("Failed to start export", err))
+			return
+		}
+		defer xlsx.Release()
+		rows = xlsx
+	default:
+		rows = newCSVExportWriter(w)
+	}
+
+	filename := fmt.Sprintf("applications-%s.%s", time.Now().Format("20060102"), format)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Header().Set("Content-Type", rows.ContentType())
+
+	// Applications are written a batch at a time as they are read, so the
+	// export is never held in memory. The status line is sent once writing
+	// starts, so a failure past this point aborts the response instead of
+	// returning a JSON error.
+	now := time.Now()
+	err := rows.Write(exportHeader)
+	if err == nil {
+		err = h.ApplicationRepo.Stream(filter, func(applications []models.Application) error {
+			// Exports leave the system, so applicants who have not consented
+			// to data sharing are not named
+			applicantIDs := make([]string, len(applications))
+			for i := range applications {
+				applicantIDs[i] = applications[i].ApplicantID
+			}
+			consents, err := h.ConsentRepo.GetByApplicantIDs(applicantIDs)
+			if err != nil {
+				return err
+			}
+			for i := range applications {
+				if !models.Consented(consents[applications[i].ApplicantID], models.ConsentDataSharing, now) {
+					applications[i].Applicant = nil
+				}
+				if err := rows.Write(exportRow(applications[i])); err != nil {
+					return err
+				}
+			}
+			return rows.Flush()
+		})
+	}
+	if err == nil {
+		err = rows.Close()
+	}
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// exportWriter writes the rows of an export to a response
+type exportWriter interface {
+	ContentType() string
+	Write(row []string) error
+	Flush() error // Sends the rows written so far, if the format allows
+	Close() error // Completes the export
+}
+
+// csvExportWriter writes an export as CSV, sending the rows written so far
+// whenever it is flushed
+type csvExportWriter struct {
+	w  http.ResponseWriter
+	cw *csv.Writer
+}
+
+func newCSVExportWriter(w http.ResponseWriter) *csvExportWriter {
+	return &csvExportWriter{w: w, cw: csv.NewWriter(w)}
+}
+
+func (e *csvExportWriter) ContentType() string { return "text/csv; charset=utf-8" }
+
+func (e *csvExportWriter) Write(row []string) error { return e.cw.Write(row) }
+
+func (e *csvExportWriter) Flush() error {
+	e.cw.Flush()
+	if err := e.cw.Error(); err != nil {
+		return err
+	}
+	if err := http.NewResponseController(e.w).Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
+
+func (e *csvExportWriter) Close() error { return e.Flush() }
+
+// xlsxExportWriter writes an export as a single-sheet workbook. Rows are
+// written through excelize's stream writer, which keeps them on disk rather
+// than in memory, and the workbook is sent once complete, as its format
+// requires.
+type xlsxExportWriter struct {
+	w    http.ResponseWriter
+	f    *excelize.File
+	sw   *excelize.StreamWriter
+	rows int
+}
+
+func newXLSXExportWriter(w http.ResponseWriter) (*xlsxExportWriter, error) {
+	f := excelize.NewFile()
+
+	const sheet = "Applications"
+	if err := f.SetSheetName("Sheet1", sheet); err != nil {
+		f.Close()
+		return nil, err
+	}
+	sw, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &xlsxExportWriter{w: w, f: f, sw: sw}, nil
+}
+
+func (e *xlsxExportWriter) ContentType() string {
+	return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+}
+
+func (e *xlsxExportWriter) Write(row []string) error {
+	e.rows++
+	cell, err := excelize.CoordinatesToCellName(1, e.rows)
+	if err != nil {
+		return err
+	}
+	return e.sw.SetRow(cell, stringCells(row))
+}
+
+func (e *xlsxExportWriter) Flush() error { return nil }
+
+func (e *xlsxExportWriter) Close() error {
+	if err := e.sw.Flush(); err != nil {
+		return err
+	}
+	_, err := e.f.WriteTo(e.w)
+	return err
+}
+
+// Release removes the workbook's temporary files
+func (e *xlsxExportWriter) Release() {
+	e.f.Close()
+}
+
+// stringCells converts a row of strings into cell values for excelize
+func stringCells(row []string) []interface{} {
+	cells := make([]interface{}, len(row))
+	for i, v := range row {
+		cells[i] = v
+	}
+	return cells
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"testing"
+
+	"one-client-view-2025tht/app/models"
+)
+
+func TestSpreadsheetText(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"Meets criteria", "Meets criteria"},
+		{"=HYPERLINK(\"http://evil.example\")", "'=HYPERLINK(\"http://evil.example\")"},
+		{"+1+1", "'+1+1"},
+		{"-2+3", "'-2+3"},
+		{"@SUM(A1:A2)", "'@SUM(A1:A2)"},
+		{"\t=1", "'\t=1"},
+		{"\r=1", "'\r=1"},
+		{"a=1", "a=1"},
+	}
+	for _, tt := range tests {
+		if got := spreadsheetText(tt.in); got != tt.want {
+			t.Errorf("spreadsheetText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestExportRowEscapesFreeText(t *testing.T) {
+	row := exportRow(models.Application{
+		ID:             "a1",
+		Status:         "rejected",
+		DecisionReason: "=cmd|' /C calc'!A0",
+		Notes:          "@notes",
+		Applicant:      &models.Applicant{Name: "+Tan"},
+		Scheme:         &models.Scheme{Name: "-Scheme"},
+	})
+
+	want := map[string]string{
+		"Decision Reason": "'=cmd|' /C calc'!A0",
+		"Notes":           "'@notes",
+		"Applicant Name":  "'+Tan",
+		"Scheme Name":     "'-Scheme",
+		"Application ID":  "a1",
+	}
+	for i, column := range exportHeader {
+		if value, ok := want[column]; ok && row[i] != value {
+			t.Errorf("%s = %q, want %q", column, row[i], value)
+		}
+	}
+}
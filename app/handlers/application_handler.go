@@ -1,12 +1,22 @@
 package handlers
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/features"
+	"one-client-view-2025tht/app/mergepatch"
 	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/notify"
+	"one-client-view-2025tht/app/validation"
 )
 
 // ApplicationHandler handles HTTP requests related to applications
@@ -14,153 +24,301 @@ type ApplicationHandler struct {
 	ApplicationRepo *models.ApplicationRepository
 	ApplicantRepo   *models.ApplicantRepository
 	SchemeRepo      *models.SchemeRepository
+	SchemeCache     *models.CachedSchemeStore // Invalidated when approvals change the capacity of a scheme
+	AuditRepo       *models.AuditRepository
+	WebhookRepo     *models.WebhookRepository
+	ReviewFlagRepo  *models.ReviewFlagRepository
+	EventRepo       *models.ApplicationEventRepository
+	ConsentRepo     *models.ConsentRepository   // Checked before applicants are named in exports
+	UserRepo        *models.UserRepository      // Looks up the users applications are assigned to
+	Notifier        *notify.Notifier            // Emails applicants on submission and decisions; may be nil
+	HouseholdRepo   *models.HouseholdRepository // Resolves the shared households applicants are assessed against
+	Flags           *features.Set               // Decides how approvals commit against scheme budgets
 }
 
-// NewApplicationHandler creates a new handler with the given repositories
-func NewApplicationHandler(appRepo *models.ApplicationRepository, applicantRepo *models.ApplicantRepository, schemeRepo *models.SchemeRepository) *ApplicationHandler {
+// NewApplicationHandler creates a new handler with the given repositories and notifier
+func NewApplicationHandler(appRepo *models.ApplicationRepository, applicantRepo *models.ApplicantRepository, schemeRepo *models.SchemeRepository, schemeCache *models.CachedSchemeStore, auditRepo *models.AuditRepository, webhookRepo *models.WebhookRepository, reviewFlagRepo *models.ReviewFlagRepository, eventRepo *models.ApplicationEventRepository, consentRepo *models.ConsentRepository, userRepo *models.UserRepository, notifier *notify.Notifier, householdRepo *models.HouseholdRepository, flags *features.Set) *ApplicationHandler {
 	return &ApplicationHandler{
 		ApplicationRepo: appRepo,
 		ApplicantRepo:   applicantRepo,
 		SchemeRepo:      schemeRepo,
+		SchemeCache:     schemeCache,
+		AuditRepo:       auditRepo,
+		WebhookRepo:     webhookRepo,
+		ReviewFlagRepo:  reviewFlagRepo,
+		EventRepo:       eventRepo,
+		ConsentRepo:     consentRepo,
+		UserRepo:        userRepo,
+		Notifier:        notifier,
+		HouseholdRepo:   householdRepo,
+		Flags:           flags,
 	}
 }
 
-// GetApplications handles GET /api/applications
+// GetApplications handles GET /api/v1/applications
 // @Summary Get all applications
-// @Description Retrieve a list of all financial assistance applications
+// @Description Retrieve financial assistance applications, newest first, optionally filtered. Each refers to its applicant and scheme by ID unless asked to expand them.
 // @Tags applications
 // @Accept json
 // @Produce json
+// @Param status query string false "Status" Enums(pending, approved, rejected, withdrawn)
+// @Param scheme_id query string false "Scheme ID"
+// @Param applicant_id query string false "Applicant ID"
+// @Param applied_after query string false "Only applications made on or after this date (YYYY-MM-DD)"
+// @Param applied_before query string false "Only applications made before this date (YYYY-MM-DD)"
+// @Param assigned_to query string false "User ID the applications are assigned to, me for the authenticated user, or none for unassigned applications"
+// @Param include_deleted query bool false "Include soft-deleted applications (admin only)"
+// @Param view query string false "full (the default) or minimal, which masks identity numbers, dates of birth and phone numbers, reduces addresses to their postal district and omits household members; viewers always get minimal" Enums(full, minimal)
+// @Param expand query string false "Comma-separated records to embed in each application: applicant, scheme or both; only their IDs if omitted"
+// @Param fields query string false "Comma-separated fields to return, such as id,name,household.name; all if omitted"
 // @Success 200 {array} models.SwaggerApplicationResponse
-// @Failure 500 {object} string "Internal server error"
-// @Router /api/applications [get]
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 403 {object} apierrors.APIError "Forbidden"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applications [get]
 func (h *ApplicationHandler) GetApplications(w http.ResponseWriter, r *http.Request) {
-	applications, err := h.ApplicationRepo.GetAll()
+	filter, apiErr := applicationFilterParams(r)
+	if apiErr != nil {
+		apierrors.Write(w, r, apiErr)
+		return
+	}
+
+	expand, apiErr := expandParam(r)
+	if apiErr != nil {
+		apierrors.Write(w, r, apiErr)
+		return
+	}
+
+	applications, err := h.ApplicationRepo.FindExpanded(filter, expand)
 	if err != nil {
-		http.Error(w, "Failed to get applications: "+err.Error(), http.StatusInternalServerError)
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applications", err))
 		return
 	}
 
 	// Convert to response objects
 	var response []models.ApplicationResponse
 	for _, a := range applications {
-		if a.Applicant == nil || a.Scheme == nil {
+		if (expand.Applicant && a.Applicant == nil) || (expand.Scheme && a.Scheme == nil) {
 			continue // Skip invalid applications
 		}
 
-		response = append(response, models.ApplicationResponse{
-			Application: a,
-			Applicant: models.ApplicantResponse{
+		item := models.ApplicationResponse{Application: a}
+		if a.Applicant != nil {
+			item.Applicant = &models.ApplicantResponse{
 				Applicant: *a.Applicant,
 				Household: a.Applicant.Household,
+			}
+		}
+		if a.Scheme != nil {
+			item.Scheme = &models.SchemeResponse{
+				Scheme:   *a.Scheme,
+				Benefits: a.Scheme.Benefits,
+			}
+		}
+		response = append(response, item)
+	}
+
+	writeList(w, r, response, 0)
+}
+
+// expandParam parses the comma-separated records to embed in each
+// application of a list: applicant, scheme, or both
+func expandParam(r *http.Request) (models.ApplicationExpansion, *apierrors.APIError) {
+	var expand models.ApplicationExpansion
+	value := r.URL.Query().Get("expand")
+	if value == "" {
+		return expand, nil
+	}
+	for _, name := range strings.Split(value, ",") {
+		switch strings.TrimSpace(name) {
+		case "applicant":
+			expand.Applicant = true
+		case "scheme":
+			expand.Scheme = true
+		default:
+			return expand, apierrors.BadRequest("Invalid expand").WithDetails("must be a comma-separated list of: applicant, scheme")
+		}
+	}
+	return expand, nil
+}
+
+// GetApplicantApplications handles GET /api/v1/applicants/{id}/applications
+// @Summary Get applications for an applicant
+// @Description Retrieve the application history of a specific applicant
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param id path string true "Applicant ID"
+// @Param view query string false "full (the default) or minimal, which masks identity numbers, dates of birth and phone numbers, reduces addresses to their postal district and omits household members; viewers always get minimal" Enums(full, minimal)
+// @Param fields query string false "Comma-separated fields to return, such as id,name,household.name; all if omitted"
+// @Success 200 {array} models.SwaggerApplicationResponse
+// @Failure 404 {object} apierrors.APIError "Applicant not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applicants/{id}/applications [get]
+func (h *ApplicationHandler) GetApplicantApplications(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	// Check if applicant exists
+	applicant, err := h.ApplicantRepo.GetByID(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applicant", err))
+		return
+	}
+	if applicant == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Applicant not found"))
+		return
+	}
+
+	applications, err := h.ApplicationRepo.GetByApplicantID(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applications", err))
+		return
+	}
+
+	// Convert to response objects
+	var response []models.ApplicationResponse
+	for _, a := range applications {
+		if a.Scheme == nil {
+			continue // Skip invalid applications
+		}
+
+		response = append(response, models.ApplicationResponse{
+			Application: a,
+			Applicant: &models.ApplicantResponse{
+				Applicant: *applicant,
+				Household: applicant.Household,
 			},
-			Scheme: models.SchemeResponse{
+			Scheme: &models.SchemeResponse{
 				Scheme:   *a.Scheme,
 				Benefits: a.Scheme.Benefits,
 			},
 		})
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	writeList(w, r, response, 0)
 }
 
-// GetApplication handles GET /api/applications/{id}
+// GetApplication handles GET /api/v1/applications/{id}
 // @Summary Get application by ID
-// @Description Retrieve a specific application by its ID
+// @Description Retrieve a specific application by its ID, with the rejected applications it resubmits, newest first
 // @Tags applications
 // @Accept json
 // @Produce json
 // @Param id path string true "Application ID"
+// @Param include_deleted query bool false "Include soft-deleted applications (admin only)"
+// @Param view query string false "full (the default) or minimal, which masks identity numbers, dates of birth and phone numbers, reduces addresses to their postal district and omits household members; viewers always get minimal" Enums(full, minimal)
+// @Param fields query string false "Comma-separated fields to return, such as id,name,household.name; all if omitted"
 // @Success 200 {object} models.SwaggerApplicationResponse
-// @Failure 404 {object} string "Application not found"
-// @Failure 500 {object} string "Internal server error"
-// @Router /api/applications/{id} [get]
+// @Failure 403 {object} apierrors.APIError "Forbidden"
+// @Failure 404 {object} apierrors.APIError "Application not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applications/{id} [get]
 func (h *ApplicationHandler) GetApplication(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
 
-	application, err := h.ApplicationRepo.GetByID(id)
+	includeDeleted, apiErr := includeDeletedParam(r)
+	if apiErr != nil {
+		apierrors.Write(w, r, apiErr)
+		return
+	}
+
+	var application *models.Application
+	var err error
+	if includeDeleted {
+		application, err = h.ApplicationRepo.GetByIDIncludingDeleted(id)
+	} else {
+		application, err = h.ApplicationRepo.GetByID(id)
+	}
 	if err != nil {
-		http.Error(w, "Failed to get application: "+err.Error(), http.StatusInternalServerError)
+		apierrors.Write(w, r, apierrors.Internal("Failed to get application", err))
 		return
 	}
 
 	if application == nil {
-		http.Error(w, "Application not found", http.StatusNotFound)
+		apierrors.Write(w, r, apierrors.NotFound("Application not found"))
 		return
 	}
 
 	if application.Applicant == nil || application.Scheme == nil {
-		http.Error(w, "Invalid application data", http.StatusInternalServerError)
+		apierrors.Write(w, r, apierrors.Internal("Invalid application data", nil))
+		return
+	}
+
+	previous, err := h.ApplicationRepo.PreviousApplications(application)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get previous applications", err))
 		return
 	}
 
 	response := models.ApplicationResponse{
 		Application: *application,
-		Applicant: models.ApplicantResponse{
+		Applicant: &models.ApplicantResponse{
 			Applicant: *application.Applicant,
 			Household: application.Applicant.Household,
 		},
-		Scheme: models.SchemeResponse{
+		Scheme: &models.SchemeResponse{
 			Scheme:   *application.Scheme,
 			Benefits: application.Scheme.Benefits,
 		},
+		PreviousApplications: previous,
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	setETag(w, application.Version)
+	writeJSON(w, r, http.StatusOK, response)
 }
 
-// CreateApplication handles POST /api/applications
+// CreateApplication handles POST /api/v1/applications
 // @Summary Create a new application
-// @Description Submit a new application for a financial assistance scheme
+// @Description Submit a new application for a financial assistance scheme. An application from an applicant the scheme has rejected before is linked to their latest rejected application by previous_application_id, and is refused until the scheme's cooling_off_days have passed since that rejection.
 // @Tags applications
 // @Accept json
 // @Produce json
 // @Param application body models.ApplicationRequest true "Application information"
 // @Success 201 {object} models.SwaggerApplicationResponse
-// @Failure 400 {object} string "Bad request"
-// @Failure 404 {object} string "Applicant or scheme not found"
-// @Failure 500 {object} string "Internal server error"
-// @Router /api/applications [post]
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 404 {object} apierrors.APIError "Applicant or scheme not found"
+// @Failure 409 {object} apierrors.APIError "Applicant already has an active application for this scheme, or was rejected for it within its cooling-off period"
+// @Failure 422 {object} apierrors.APIError "Validation failed, scheme is not open for applications, or applicant is not eligible for this scheme"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applications [post]
 func (h *ApplicationHandler) CreateApplication(w http.ResponseWriter, r *http.Request) {
 	var request models.ApplicationRequest
 	err := json.NewDecoder(r.Body).Decode(&request)
 	if err != nil {
-		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
 		return
 	}
 
-	// Basic validation
-	if request.ApplicantID == "" {
-		http.Error(w, "Applicant ID is required", http.StatusBadRequest)
-		return
-	}
-	if request.SchemeID == "" {
-		http.Error(w, "Scheme ID is required", http.StatusBadRequest)
+	if err := validation.ApplicationRequest(&request); err != nil {
+		apierrors.Write(w, r, validationError(err))
 		return
 	}
 
 	// Check if applicant exists
 	applicant, err := h.ApplicantRepo.GetByID(request.ApplicantID)
 	if err != nil {
-		http.Error(w, "Failed to get applicant: "+err.Error(), http.StatusInternalServerError)
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applicant", err))
 		return
 	}
 	if applicant == nil {
-		http.Error(w, "Applicant not found", http.StatusNotFound)
+		apierrors.Write(w, r, apierrors.NotFound("Applicant not found"))
 		return
 	}
 
 	// Check if scheme exists
 	scheme, err := h.SchemeRepo.GetByID(request.SchemeID)
 	if err != nil {
-		http.Error(w, "Failed to get scheme: "+err.Error(), http.StatusInternalServerError)
+		apierrors.Write(w, r, apierrors.Internal("Failed to get scheme", err))
 		return
 	}
 	if scheme == nil {
-		http.Error(w, "Scheme not found", http.StatusNotFound)
+		apierrors.Write(w, r, apierrors.NotFound("Scheme not found"))
 		return
 	}
 
@@ -173,49 +331,121 @@ func (h *ApplicationHandler) CreateApplication(w http.ResponseWriter, r *http.Re
 	}
 
 	// Try to create the application
-	err = h.ApplicationRepo.Create(application)
+	err = models.WithTx(h.ApplicationRepo.DB, func(tx *sql.Tx) error {
+		if err := h.ApplicationRepo.WithTx(tx).Create(application); err != nil {
+			return err
+		}
+		if err := h.AuditRepo.WithTx(tx).Record(models.AuditEntityApplication, application.ID,
+			models.AuditActionCreate, actorFrom(r), nil, application); err != nil {
+			return err
+		}
+		return h.WebhookRepo.WithTx(tx).Enqueue(models.EventApplicationCreated, applicationSnapshot(application))
+	})
+	if errors.Is(err, models.ErrSchemeClosed) {
+		apierrors.Write(w, r, apierrors.Unprocessable("Scheme is not open for applications"))
+		return
+	}
+	if errors.Is(err, models.ErrNotEligible) {
+		apierrors.Write(w, r, apierrors.Unprocessable("Applicant is not eligible for this scheme"))
+		return
+	}
+	if errors.Is(err, models.ErrDuplicateApplication) {
+		apierrors.Write(w, r, apierrors.Conflict("Applicant already has an active application for this scheme"))
+		return
+	}
+	if errors.Is(err, models.ErrCoolingOff) {
+		apierrors.Write(w, r, apierrors.Conflict("Applicant cannot reapply for this scheme yet").WithDetails(err.Error()))
+		return
+	}
 	if err != nil {
-		http.Error(w, "Failed to create application: "+err.Error(), http.StatusInternalServerError)
+		apierrors.Write(w, r, apierrors.Internal("Failed to create application", err))
 		return
 	}
 
 	// Get the created application with all details
 	createdApp, err := h.ApplicationRepo.GetByID(application.ID)
 	if err != nil {
-		http.Error(w, "Application created but failed to retrieve details: "+err.Error(), http.StatusInternalServerError)
+		apierrors.Write(w, r, apierrors.Internal("Application created but failed to retrieve details", err))
+		return
+	}
+
+	h.Notifier.ApplicationStatus(createdApp)
+
+	previous, err := h.ApplicationRepo.PreviousApplications(createdApp)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Application created but failed to retrieve previous applications", err))
 		return
 	}
 
 	response := models.ApplicationResponse{
 		Application: *createdApp,
-		Applicant: models.ApplicantResponse{
+		Applicant: &models.ApplicantResponse{
 			Applicant: *createdApp.Applicant,
 			Household: createdApp.Applicant.Household,
 		},
-		Scheme: models.SchemeResponse{
+		Scheme: &models.SchemeResponse{
 			Scheme:   *createdApp.Scheme,
 			Benefits: createdApp.Scheme.Benefits,
 		},
+		PreviousApplications: previous,
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(response)
+	setETag(w, createdApp.Version)
+	writeJSON(w, r, http.StatusCreated, response)
 }
 
-// UpdateApplication handles PUT /api/applications/{id}
+// ValidateApplication handles POST /api/v1/applications/validate
+// @Summary Check an application without submitting it
+// @Description Run every check made when creating an application (the applicant and scheme exist, the scheme is open for applications, the applicant is eligible, has no pending or approved application for the scheme, and was not rejected for it within its cooling-off period) without creating it, reporting whether each passed. Checks that depend on a missing applicant or scheme are skipped.
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param application body models.ApplicationRequest true "Application information"
+// @Success 200 {object} models.SubmissionAssessment
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applications/validate [post]
+func (h *ApplicationHandler) ValidateApplication(w http.ResponseWriter, r *http.Request) {
+	var request models.ApplicationRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
+		return
+	}
+
+	if err := validation.ApplicationRequest(&request); err != nil {
+		apierrors.Write(w, r, validationError(err))
+		return
+	}
+
+	assessment, err := h.ApplicationRepo.Assess(request.ApplicantID, request.SchemeID, time.Now())
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to check application", err))
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, assessment)
+}
+
+// UpdateApplication handles PUT /api/v1/applications/{id}
 // @Summary Update application
-// @Description Update an existing application's status or notes
+// @Description Update an existing application's notes. The status cannot be changed directly; use the approve and reject endpoints.
 // @Tags applications
 // @Accept json
 // @Produce json
 // @Param id path string true "Application ID"
-// @Param application body object{status=string,notes=string} true "Updated application information"
+// @Param If-Match header string false "ETag of the version being updated; alternatively send version in the body"
+// @Param application body object{status=string,notes=string,version=int} true "Updated application information"
 // @Success 200 {object} models.SwaggerApplicationResponse
-// @Failure 400 {object} string "Bad request"
-// @Failure 404 {object} string "Application not found"
-// @Failure 500 {object} string "Internal server error"
-// @Router /api/applications/{id} [put]
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 404 {object} apierrors.APIError "Application not found"
+// @Failure 409 {object} apierrors.APIError "Version conflict"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 428 {object} apierrors.APIError "Missing If-Match header or version"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applications/{id} [put]
 func (h *ApplicationHandler) UpdateApplication(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
@@ -223,26 +453,40 @@ func (h *ApplicationHandler) UpdateApplication(w http.ResponseWriter, r *http.Re
 	// Check if application exists
 	existing, err := h.ApplicationRepo.GetByID(id)
 	if err != nil {
-		http.Error(w, "Failed to get application: "+err.Error(), http.StatusInternalServerError)
+		apierrors.Write(w, r, apierrors.Internal("Failed to get application", err))
 		return
 	}
 	if existing == nil {
-		http.Error(w, "Application not found", http.StatusNotFound)
+		apierrors.Write(w, r, apierrors.NotFound("Application not found"))
 		return
 	}
 
 	var request struct {
-		Status string `json:"status"`
-		Notes  string `json:"notes"`
+		Status  string `json:"status"`
+		Notes   string `json:"notes"`
+		Version int    `json:"version"`
 	}
 
 	err = json.NewDecoder(r.Body).Decode(&request)
 	if err != nil {
-		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
+		return
+	}
+
+	// Reject updates based on a stale version
+	version, apiErr := expectedVersion(r, request.Version, existing.Version)
+	if apiErr != nil {
+		apierrors.Write(w, r, apiErr)
+		return
+	}
+	if version != existing.Version {
+		apierrors.Write(w, r, versionConflict())
 		return
 	}
 
-	// Update only status and notes
+	before := applicationSnapshot(existing)
+
+	// Update only status and notes; status changes are rejected by validation
 	if request.Status != "" {
 		existing.Status = request.Status
 	}
@@ -250,46 +494,172 @@ func (h *ApplicationHandler) UpdateApplication(w http.ResponseWriter, r *http.Re
 		existing.Notes = request.Notes
 	}
 
-	err = h.ApplicationRepo.Update(existing)
+	if err := validation.ApplicationUpdate(before, existing); err != nil {
+		apierrors.Write(w, r, validationError(err))
+		return
+	}
+
+	err = models.WithTx(h.ApplicationRepo.DB, func(tx *sql.Tx) error {
+		if err := h.ApplicationRepo.WithTx(tx).Update(existing); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityApplication, id,
+			models.AuditActionUpdate, actorFrom(r), before, applicationSnapshot(existing))
+	})
+	if errors.Is(err, models.ErrVersionConflict) {
+		apierrors.Write(w, r, versionConflict())
+		return
+	}
 	if err != nil {
-		http.Error(w, "Failed to update application: "+err.Error(), http.StatusInternalServerError)
+		apierrors.Write(w, r, apierrors.Internal("Failed to update application", err))
 		return
 	}
 
 	// Get the updated application with all details
 	updatedApp, err := h.ApplicationRepo.GetByID(existing.ID)
 	if err != nil {
-		http.Error(w, "Application updated but failed to retrieve details: "+err.Error(), http.StatusInternalServerError)
+		apierrors.Write(w, r, apierrors.Internal("Application updated but failed to retrieve details", err))
+		return
+	}
+
+	response := models.ApplicationResponse{
+		Application: *updatedApp,
+		Applicant: &models.ApplicantResponse{
+			Applicant: *updatedApp.Applicant,
+			Household: updatedApp.Applicant.Household,
+		},
+		Scheme: &models.SchemeResponse{
+			Scheme:   *updatedApp.Scheme,
+			Benefits: updatedApp.Scheme.Benefits,
+		},
+	}
+
+	setETag(w, updatedApp.Version)
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// PatchApplication handles PATCH /api/v1/applications/{id}
+// @Summary Partially update application
+// @Description Update an application's notes using JSON Merge Patch (RFC 7396): omitted fields are left unchanged and "notes": null clears the notes. The status cannot be changed directly; use the approve and reject endpoints.
+// @Tags applications
+// @Accept json
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param If-Match header string false "ETag of the version being updated; alternatively send version in the body"
+// @Param patch body object{status=string,notes=string,version=int} true "Fields to update"
+// @Success 200 {object} models.SwaggerApplicationResponse
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 404 {object} apierrors.APIError "Application not found"
+// @Failure 409 {object} apierrors.APIError "Version conflict"
+// @Failure 415 {object} apierrors.APIError "Unsupported Content-Type"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 428 {object} apierrors.APIError "Missing If-Match header or version"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applications/{id} [patch]
+func (h *ApplicationHandler) PatchApplication(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	existing, err := h.ApplicationRepo.GetByID(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get application", err))
+		return
+	}
+	if existing == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Application not found"))
+		return
+	}
+
+	patch, patchVersion, apiErr := readMergePatch(r)
+	if apiErr != nil {
+		apierrors.Write(w, r, apiErr)
+		return
+	}
+
+	// Reject updates based on a stale version
+	version, apiErr := expectedVersion(r, patchVersion, existing.Version)
+	if apiErr != nil {
+		apierrors.Write(w, r, apiErr)
+		return
+	}
+	if version != existing.Version {
+		apierrors.Write(w, r, versionConflict())
+		return
+	}
+
+	// Only status and notes can be patched, so merge onto just those fields
+	type patchable struct {
+		Status string `json:"status"`
+		Notes  string `json:"notes,omitempty"`
+	}
+	var patched patchable
+	current := patchable{Status: existing.Status, Notes: existing.Notes}
+	if err := mergepatch.ApplyTo(current, patch, &patched); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid patch").WithDetails(err.Error()))
+		return
+	}
+
+	before := applicationSnapshot(existing)
+	existing.Status = patched.Status
+	existing.Notes = patched.Notes
+
+	if err := validation.ApplicationUpdate(before, existing); err != nil {
+		apierrors.Write(w, r, validationError(err))
+		return
+	}
+
+	err = models.WithTx(h.ApplicationRepo.DB, func(tx *sql.Tx) error {
+		if err := h.ApplicationRepo.WithTx(tx).Update(existing); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityApplication, id,
+			models.AuditActionUpdate, actorFrom(r), before, applicationSnapshot(existing))
+	})
+	if errors.Is(err, models.ErrVersionConflict) {
+		apierrors.Write(w, r, versionConflict())
+		return
+	}
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to update application", err))
+		return
+	}
+
+	updatedApp, err := h.ApplicationRepo.GetByID(existing.ID)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Application updated but failed to retrieve details", err))
 		return
 	}
 
 	response := models.ApplicationResponse{
 		Application: *updatedApp,
-		Applicant: models.ApplicantResponse{
+		Applicant: &models.ApplicantResponse{
 			Applicant: *updatedApp.Applicant,
 			Household: updatedApp.Applicant.Household,
 		},
-		Scheme: models.SchemeResponse{
+		Scheme: &models.SchemeResponse{
 			Scheme:   *updatedApp.Scheme,
 			Benefits: updatedApp.Scheme.Benefits,
 		},
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	setETag(w, updatedApp.Version)
+	writeJSON(w, r, http.StatusOK, response)
 }
 
-// DeleteApplication handles DELETE /api/applications/{id}
+// DeleteApplication handles DELETE /api/v1/applications/{id}
 // @Summary Delete application
-// @Description Remove an application from the system
+// @Description Soft-delete an application, keeping it for case history. Deleting an approved application releases the capacity it took up of its scheme. Deleted applications can be restored.
 // @Tags applications
 // @Accept json
 // @Produce json
 // @Param id path string true "Application ID"
 // @Success 204 "No content"
-// @Failure 404 {object} string "Application not found"
-// @Failure 500 {object} string "Internal server error"
-// @Router /api/applications/{id} [delete]
+// @Failure 404 {object} apierrors.APIError "Application not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applications/{id} [delete]
 func (h *ApplicationHandler) DeleteApplication(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
@@ -297,19 +667,143 @@ func (h *ApplicationHandler) DeleteApplication(w http.ResponseWriter, r *http.Re
 	// Check if application exists
 	existing, err := h.ApplicationRepo.GetByID(id)
 	if err != nil {
-		http.Error(w, "Failed to get application: "+err.Error(), http.StatusInternalServerError)
+		apierrors.Write(w, r, apierrors.Internal("Failed to get application", err))
 		return
 	}
 	if existing == nil {
-		http.Error(w, "Application not found", http.StatusNotFound)
+		apierrors.Write(w, r, apierrors.NotFound("Application not found"))
 		return
 	}
 
-	err = h.ApplicationRepo.Delete(id)
+	before := applicationSnapshot(existing)
+
+	err = models.WithTx(h.ApplicationRepo.DB, func(tx *sql.Tx) error {
+		if err := h.ApplicationRepo.WithTx(tx).Delete(existing); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityApplication, id,
+			models.AuditActionDelete, actorFrom(r), before, nil)
+	})
 	if err != nil {
-		http.Error(w, "Failed to delete application: "+err.Error(), http.StatusInternalServerError)
+		apierrors.Write(w, r, apierrors.Internal("Failed to delete application", err))
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// RestoreApplication handles POST /api/v1/applications/{id}/restore
+// @Summary Restore application
+// @Description Restore a soft-deleted application. Restoring an approved application takes up capacity of its scheme again, and fails with 409 if the scheme has none left.
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Success 200 {object} models.SwaggerApplicationResponse
+// @Failure 404 {object} apierrors.APIError "Application not found"
+// @Failure 409 {object} apierrors.APIError "Application is not deleted, or its scheme has no remaining capacity"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applications/{id}/restore [post]
+func (h *ApplicationHandler) RestoreApplication(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	existing, err := h.ApplicationRepo.GetByIDIncludingDeleted(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get application", err))
+		return
+	}
+	if existing == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Application not found"))
+		return
+	}
+	if existing.DeletedAt == nil {
+		apierrors.Write(w, r, apierrors.Conflict("Application is not deleted"))
+		return
+	}
+
+	before := applicationSnapshot(existing)
+
+	err = models.WithTx(h.ApplicationRepo.DB, func(tx *sql.Tx) error {
+		if err := h.ApplicationRepo.WithTx(tx).Restore(existing); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityApplication, id,
+			models.AuditActionRestore, actorFrom(r), before, applicationSnapshot(existing))
+	})
+	if errors.Is(err, models.ErrCapacityExhausted) {
+		apierrors.Write(w, r, apierrors.Conflict("Scheme has no remaining capacity").
+			WithDetails("restoring the approved application would exceed its scheme's max_applications or budget"))
+		return
+	}
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to restore application", err))
+		return
+	}
+
+	if existing.Applicant == nil || existing.Scheme == nil {
+		apierrors.Write(w, r, apierrors.Internal("Invalid application data", nil))
+		return
+	}
+
+	response := models.ApplicationResponse{
+		Application: *existing,
+		Applicant: &models.ApplicantResponse{
+			Applicant: *existing.Applicant,
+			Household: existing.Applicant.Household,
+		},
+		Scheme: &models.SchemeResponse{
+			Scheme:   *existing.Scheme,
+			Benefits: existing.Scheme.Benefits,
+		},
+	}
+
+	setETag(w, existing.Version)
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// applicationFilterParams parses the query parameters shared by the
+// application list and export endpoints
+func applicationFilterParams(r *http.Request) (models.ApplicationFilter, *apierrors.APIError) {
+	query := r.URL.Query()
+	filter := models.ApplicationFilter{
+		Status:      query.Get("status"),
+		SchemeID:    query.Get("scheme_id"),
+		ApplicantID: query.Get("applicant_id"),
+	}
+
+	var apiErr *apierrors.APIError
+	if filter.IncludeDeleted, apiErr = includeDeletedParam(r); apiErr != nil {
+		return filter, apiErr
+	}
+
+	filter.AssignedTo, filter.Unassigned = assignedToParam(r)
+
+	if filter.Status != "" && !slices.Contains(validation.ApplicationStatuses, filter.Status) {
+		return filter, apierrors.BadRequest("Invalid status").
+			WithDetails("must be one of: " + strings.Join(validation.ApplicationStatuses, ", "))
+	}
+
+	var err error
+	if filter.AppliedAfter, err = parseDateParam(query.Get("applied_after")); err != nil {
+		return filter, apierrors.BadRequest("Invalid applied_after").WithDetails(err.Error())
+	}
+	if filter.AppliedBefore, err = parseDateParam(query.Get("applied_before")); err != nil {
+		return filter, apierrors.BadRequest("Invalid applied_before").WithDetails(err.Error())
+	}
+	if !filter.AppliedAfter.IsZero() && !filter.AppliedBefore.IsZero() && !filter.AppliedAfter.Before(filter.AppliedBefore.Time) {
+		return filter, apierrors.BadRequest("applied_after must be before applied_before")
+	}
+
+	return filter, nil
+}
+
+// applicationSnapshot copies an application without its embedded applicant and
+// scheme, which are audited separately
+func applicationSnapshot(a *models.Application) *models.Application {
+	snapshot := *a
+	snapshot.Applicant = nil
+	snapshot.Scheme = nil
+	return &snapshot
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/jobs"
+	"one-client-view-2025tht/app/models"
+)
+
+// GetApplicationFlags handles GET /api/v1/applications/{id}/flags
+// @Summary Get an application's review flags
+// @Description List the flags raised when scheduled re-evaluation found the applicant no longer met the scheme's criteria, newest first. A flag is resolved once the applicant is found eligible again.
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Success 200 {array} models.ReviewFlag
+// @Failure 404 {object} apierrors.APIError "Application not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applications/{id}/flags [get]
+func (h *ApplicationHandler) GetApplicationFlags(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	application, err := h.ApplicationRepo.GetByID(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get application", err))
+		return
+	}
+	if application == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Application not found"))
+		return
+	}
+
+	flags, err := h.ReviewFlagRepo.GetByApplicationID(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get review flags", err))
+		return
+	}
+
+	writeList(w, r, flags, 0)
+}
+
+// RunEligibilityReviewJob is the handler of eligibility.review jobs, which
+// re-evaluate the applicants of active applications and flag those no longer
+// eligible
+func (h *ApplicationHandler) RunEligibilityReviewJob(ctx context.Context, job *models.Job) (interface{}, error) {
+	var req models.EligibilityReviewJob
+	if err := json.Unmarshal(job.Payload, &req); err != nil || req.AsOf.IsZero() {
+		return nil, jobs.Permanent(fmt.Errorf("invalid payload: %v", err))
+	}
+
+	var applications []models.Application
+	for _, status := range models.ActiveApplicationStatuses {
+		found, err := h.ApplicationRepo.Find(models.ApplicationFilter{Status: status})
+		if err != nil {
+			return nil, err
+		}
+		applications = append(applications, found...)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := h.HouseholdRepo.ResolveApplications(applications, req.AsOf); err != nil {
+		return nil, err
+	}
+	findings, err := models.ReviewEligibility(h.SchemeRepo, applications, req.AsOf)
+	if err != nil {
+		return nil, err
+	}
+
+	result := models.EligibilityReviewResult{AsOf: req.AsOf, Checked: len(findings)}
+	for _, f := range findings {
+		if !f.Eligible {
+			result.Ineligible++
+		}
+	}
+	if result.Flagged, result.Resolved, err = h.ReviewFlagRepo.Record(findings); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/auth"
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/validation"
+)
+
+// WithdrawApplication handles POST /api/v1/applications/{id}/withdraw
+// @Summary Withdraw application
+// @Description Withdraw a pending or approved application, moving it to the terminal withdrawn status with the reason given. Withdrawing an approval releases the scheme capacity it took up. Applicants signed in to the portal can withdraw their own applications; caseworkers and admins can withdraw any. The applicant is emailed, as is the user the application is assigned to, if it was not them.
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param withdrawal body models.WithdrawRequest true "Reason for the withdrawal"
+// @Success 200 {object} models.SwaggerApplicationResponse
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 404 {object} apierrors.APIError "Application not found"
+// @Failure 409 {object} apierrors.APIError "Application has been rejected or withdrawn already, or version conflict"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applications/{id}/withdraw [post]
+func (h *ApplicationHandler) WithdrawApplication(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	existing, err := h.ApplicationRepo.GetByID(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get application", err))
+		return
+	}
+	if existing == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Application not found"))
+		return
+	}
+
+	var request models.WithdrawRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
+		return
+	}
+	request.Reason = strings.TrimSpace(request.Reason)
+
+	if err := validation.WithdrawRequest(&request); err != nil {
+		apierrors.Write(w, r, validationError(err))
+		return
+	}
+
+	// Applicants are recorded in the audit log, but withdrawn_by only names
+	// staff users
+	actor := actorFrom(r)
+	withdrawnBy := actor.ID
+	if hasRole(r, auth.RoleApplicant) {
+		withdrawnBy = ""
+	}
+
+	// The user reviewing the application is told it needs no more work,
+	// unless they withdrew it themselves
+	var assignee *models.User
+	if existing.AssignedTo != "" && existing.AssignedTo != actor.ID {
+		assignee, err = h.UserRepo.GetByID(existing.AssignedTo)
+		if err != nil {
+			apierrors.Write(w, r, apierrors.Internal("Failed to get assigned user", err))
+			return
+		}
+	}
+
+	wasApproved := existing.Status == "approved"
+	before := applicationSnapshot(existing)
+
+	err = models.WithTx(h.ApplicationRepo.DB, func(tx *sql.Tx) error {
+		if err := h.ApplicationRepo.WithTx(tx).Withdraw(existing, withdrawnBy, request.Reason); err != nil {
+			return err
+		}
+		if err := h.AuditRepo.WithTx(tx).Record(models.AuditEntityApplication, id,
+			models.AuditActionWithdraw, actor, before, applicationSnapshot(existing)); err != nil {
+			return err
+		}
+		return h.WebhookRepo.WithTx(tx).Enqueue(models.EventApplicationWithdrawn, applicationSnapshot(existing))
+	})
+	if errors.Is(err, models.ErrNotWithdrawable) {
+		apierrors.Write(w, r, apierrors.Conflict("Application can no longer be withdrawn").
+			WithDetails("only "+strings.Join(models.WithdrawableApplicationStatuses, " or ")+
+				" applications can be withdrawn; this one is "+existing.Status))
+		return
+	}
+	if errors.Is(err, models.ErrVersionConflict) {
+		apierrors.Write(w, r, versionConflict())
+		return
+	}
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to withdraw application", err))
+		return
+	}
+	if wasApproved {
+		h.SchemeCache.Invalidate()
+	}
+
+	if existing.Applicant == nil || existing.Scheme == nil {
+		apierrors.Write(w, r, apierrors.Internal("Invalid application data", nil))
+		return
+	}
+
+	h.Notifier.ApplicationStatus(existing)
+	if assignee != nil {
+		h.Notifier.ApplicationWithdrawn(existing, assignee)
+	}
+
+	response := models.ApplicationResponse{
+		Application: *existing,
+		Applicant: &models.ApplicantResponse{
+			Applicant: *existing.Applicant,
+			Household: existing.Applicant.Household,
+		},
+		Scheme: &models.SchemeResponse{
+			Scheme:   *existing.Scheme,
+			Benefits: existing.Scheme.Benefits,
+		},
+	}
+
+	setETag(w, existing.Version)
+	writeJSON(w, r, http.StatusOK, response)
+}
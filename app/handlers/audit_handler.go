@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/models"
+)
+
+// Limits on the number of audit entries returned per request
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+// AuditHandler handles HTTP requests related to the audit log
+type AuditHandler struct {
+	AuditRepo *models.AuditRepository
+}
+
+// NewAuditHandler creates a new handler with the given repository
+func NewAuditHandler(repo *models.AuditRepository) *AuditHandler {
+	return &AuditHandler{AuditRepo: repo}
+}
+
+// GetAuditLogs handles GET /api/v1/audit
+// @Summary Get audit log entries
+// @Description Retrieve recorded mutations, newest first, for compliance reviews
+// @Tags audit
+// @Accept json
+// @Produce json
+// @Param entity_type query string false "Entity type" Enums(applicant, scheme, application, benefit, document, case_note, applicant_photo, consent, scheme_translation, task, comment, referral, custom_field, feature_flag, household, household_membership, checklist_item, application_checklist_item)
+// @Param entity_id query string false "Entity ID"
+// @Param action query string false "Action" Enums(create, update, delete, restore, approve, reject, merge, purge, anonymize, assign, unassign, publish, archive)
+// @Param actor query string false "Actor user ID or username"
+// @Param from query string false "Only entries at or after this time (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Only entries before this time (RFC3339 or YYYY-MM-DD)"
+// @Param limit query int false "Maximum number of entries (default 100, max 1000)"
+// @Success 200 {array} models.AuditLog
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/audit [get]
+func (h *AuditHandler) GetAuditLogs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.AuditFilter{
+		EntityType: query.Get("entity_type"),
+		EntityID:   query.Get("entity_id"),
+		Action:     query.Get("action"),
+		Actor:      query.Get("actor"),
+		Limit:      defaultAuditLimit,
+	}
+
+	var err error
+	if filter.From, err = parseTimeParam(query.Get("from")); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid from").WithDetails(err.Error()))
+		return
+	}
+	if filter.To, err = parseTimeParam(query.Get("to")); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid to").WithDetails(err.Error()))
+		return
+	}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > maxAuditLimit {
+			apierrors.Write(w, r, apierrors.BadRequest("limit must be between 1 and 1000"))
+			return
+		}
+		filter.Limit = limit
+	}
+
+	logs, err := h.AuditRepo.Find(filter)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get audit logs", err))
+		return
+	}
+
+	writeList(w, r, logs, filter.Limit)
+}
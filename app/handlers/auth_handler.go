@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/auth"
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/validation"
+)
+
+// AuthHandler handles HTTP requests related to authentication
+type AuthHandler struct {
+	UserRepo *models.UserRepository
+	Tokens   *auth.TokenManager
+}
+
+// NewAuthHandler creates a new handler with the given repository and token manager
+func NewAuthHandler(userRepo *models.UserRepository, tokens *auth.TokenManager) *AuthHandler {
+	return &AuthHandler{
+		UserRepo: userRepo,
+		Tokens:   tokens,
+	}
+}
+
+// Login handles POST /api/v1/auth/login
+// @Summary Log in
+// @Description Authenticate with a username and password to obtain a bearer token
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param credentials body models.LoginRequest true "User credentials"
+// @Success 200 {object} models.LoginResponse
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 401 {object} apierrors.APIError "Invalid username or password"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Router /api/v1/auth/login [post]
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var request models.LoginRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
+		return
+	}
+
+	if err := validation.LoginRequest(&request); err != nil {
+		apierrors.Write(w, r, validationError(err))
+		return
+	}
+
+	user, err := h.UserRepo.GetByUsername(request.Username)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get user", err))
+		return
+	}
+
+	// Use the same response, taking as long, for unknown users and wrong
+	// passwords
+	if user == nil {
+		auth.CheckNoPassword(request.Password)
+		apierrors.Write(w, r, apierrors.Unauthorized("Invalid username or password"))
+		return
+	}
+	if !auth.CheckPassword(user.PasswordHash, request.Password) {
+		apierrors.Write(w, r, apierrors.Unauthorized("Invalid username or password"))
+		return
+	}
+
+	token, expiresAt, err := h.Tokens.Issue(user.ID, user.Username, user.Role)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to issue token", err))
+		return
+	}
+
+	response := models.LoginResponse{
+		Token:     token,
+		TokenType: "Bearer",
+		ExpiresAt: expiresAt,
+		User:      *user,
+	}
+
+	writeJSON(w, r, http.StatusOK, response)
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/auth"
+	"one-client-view-2025tht/app/backup"
+	"one-client-view-2025tht/app/database"
+	"one-client-view-2025tht/app/jobs"
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/storage"
+)
+
+// backupKeyPrefix is where backups are kept in the store
+const backupKeyPrefix = "backups/"
+
+// BackupHandler takes backups of the database
+type BackupHandler struct {
+	DB      *database.Database
+	Store   storage.Store // Where backups are kept, alongside documents
+	JobRepo *models.JobRepository
+}
+
+// NewBackupHandler creates a new handler backing up the given database to
+// the store
+func NewBackupHandler(db *database.Database, store storage.Store, jobRepo *models.JobRepository) *BackupHandler {
+	return &BackupHandler{DB: db, Store: store, JobRepo: jobRepo}
+}
+
+// BackupJob is the payload of a database.backup job
+type BackupJob struct {
+	Key string `json:"key"` // Where to store the backup, chosen when queued so retries replace a partial one
+}
+
+// BackupResult is the result of a database.backup job
+type BackupResult struct {
+	Key      string          `json:"key" example:"backups/20261014T130000Z.zip"` // Where the backup is stored; pass to ocvctl backup restore -key
+	Size     int64           `json:"size"`                                       // Size of the archive in bytes
+	Manifest backup.Manifest `json:"manifest"`
+}
+
+// QueueBackup handles POST /api/v1/admin/backup
+// @Summary Back up the database
+// @Description Queue a job taking a consistent logical backup of the database: a zip of every table as JSON, kept in document storage under backups/. The job queue and migration bookkeeping are left out, and documents and photos, which are already in storage, are not copied. Restore a backup with ocvctl backup restore.
+// @Tags admin
+// @Produce json
+// @Success 202 {object} models.Job "Queued; the result is a handlers.BackupResult"
+// @Failure 403 {object} apierrors.APIError "Requires the admin role"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/admin/backup [post]
+func (h *BackupHandler) QueueBackup(w http.ResponseWriter, r *http.Request) {
+	if !hasRole(r, auth.RoleAdmin) {
+		apierrors.Write(w, r, apierrors.Forbidden("Backups require the admin role"))
+		return
+	}
+
+	payload := BackupJob{Key: backupKeyPrefix + time.Now().UTC().Format("20060102T150405Z") + ".zip"}
+	job, err := h.JobRepo.Enqueue(models.JobBackup, payload, actorFrom(r).ID)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to queue backup", err))
+		return
+	}
+
+	writeJobAccepted(w, r, job)
+}
+
+// RunBackupJob is the handler of database.backup jobs. The archive is
+// written to a temporary file first, as stores need its size up front.
+func (h *BackupHandler) RunBackupJob(ctx context.Context, job *models.Job) (interface{}, error) {
+	var req BackupJob
+	if err := json.Unmarshal(job.Payload, &req); err != nil || req.Key == "" {
+		return nil, jobs.Permanent(fmt.Errorf("invalid payload: %v", err))
+	}
+
+	f, err := os.CreateTemp("", "ocv-backup-*.zip")
+	if err != nil {
+		return nil, fmt.Errorf("error creating temporary file: %v", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	manifest, err := backup.Create(ctx, h.DB, f)
+	if err != nil {
+		return nil, err
+	}
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if err := h.Store.Put(ctx, req.Key, f, size, backup.ContentType); err != nil {
+		return nil, fmt.Errorf("error storing backup: %v", err)
+	}
+
+	return BackupResult{Key: req.Key, Size: size, Manifest: *manifest}, nil
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/models"
+)
+
+// EstimateBenefits handles GET /api/v1/applicants/{id}/schemes/{schemeId}/estimate
+// @Summary Estimate an applicant's benefits from a scheme
+// @Description Compute the amount of each of a scheme's benefits the applicant would be entitled to, applying any benefit formulas to their household at as_of (default now), and the total over the benefits' durations. The estimate is computed whether or not the applicant is eligible; eligible says whether they are, assessed as for eligible schemes.
+// @Tags schemes
+// @Produce json
+// @Param id path string true "Applicant ID"
+// @Param schemeId path string true "Scheme ID"
+// @Param as_of query string false "Date or RFC3339 time to count the household and assess eligibility at (default now)"
+// @Success 200 {object} models.BenefitEstimate
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 404 {object} apierrors.APIError "Applicant or scheme not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applicants/{id}/schemes/{schemeId}/estimate [get]
+func (h *SchemeHandler) EstimateBenefits(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	asOf := time.Now()
+	if value := r.URL.Query().Get("as_of"); value != "" {
+		t, err := parseTimeParam(value)
+		if err != nil {
+			apierrors.Write(w, r, apierrors.BadRequest("Invalid as_of").WithDetails(err.Error()))
+			return
+		}
+		asOf = t
+	}
+
+	applicant, err := h.ApplicantCache.GetByID(vars["id"])
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applicant", err))
+		return
+	}
+	if applicant == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Applicant not found"))
+		return
+	}
+
+	scheme, err := h.SchemeRepo.GetByID(vars["schemeId"])
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get scheme", err))
+		return
+	}
+	if scheme == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Scheme not found"))
+		return
+	}
+
+	// Eligibility and formulas count the shared household the applicant
+	// belongs to at as_of, if any
+	applicant, err = h.HouseholdRepo.Resolve(applicant, asOf)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get shared household", err))
+		return
+	}
+
+	eligibleSchemes, err := models.EligibleSchemes(h.SchemeCache, applicant, asOf)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get eligible schemes", err))
+		return
+	}
+	eligible := false
+	for _, s := range eligibleSchemes {
+		if s.ID == scheme.ID {
+			eligible = true
+			break
+		}
+	}
+
+	writeJSON(w, r, http.StatusOK, models.EstimateBenefits(scheme, applicant, eligible, asOf))
+}
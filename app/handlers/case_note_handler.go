@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/validation"
+)
+
+// CaseNoteHandler handles requests for the case notes of applicants
+type CaseNoteHandler struct {
+	CaseNoteRepo  *models.CaseNoteRepository
+	ApplicantRepo *models.ApplicantRepository
+	AuditRepo     *models.AuditRepository
+}
+
+// NewCaseNoteHandler creates a new handler with the given repositories
+func NewCaseNoteHandler(caseNoteRepo *models.CaseNoteRepository, applicantRepo *models.ApplicantRepository, auditRepo *models.AuditRepository) *CaseNoteHandler {
+	return &CaseNoteHandler{
+		CaseNoteRepo:  caseNoteRepo,
+		ApplicantRepo: applicantRepo,
+		AuditRepo:     auditRepo,
+	}
+}
+
+// applicant loads the applicant named in the path, writing a 404 if it does
+// not exist
+func (h *CaseNoteHandler) applicant(w http.ResponseWriter, r *http.Request) *models.Applicant {
+	applicant, err := h.ApplicantRepo.GetByID(mux.Vars(r)["id"])
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applicant", err))
+		return nil
+	}
+	if applicant == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Applicant not found"))
+		return nil
+	}
+	return applicant
+}
+
+// GetCaseNotes handles GET /api/v1/applicants/{id}/notes
+// @Summary List an applicant's case notes
+// @Description List the case notes recorded for an applicant, oldest first, as a history of interactions. The minimal view omits the text of each note.
+// @Tags applicants
+// @Produce json
+// @Param id path string true "Applicant ID"
+// @Param tag query string false "Only notes with this tag"
+// @Success 200 {array} models.CaseNote
+// @Failure 404 {object} apierrors.APIError "Applicant not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applicants/{id}/notes [get]
+func (h *CaseNoteHandler) GetCaseNotes(w http.ResponseWriter, r *http.Request) {
+	applicant := h.applicant(w, r)
+	if applicant == nil {
+		return
+	}
+
+	tag := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("tag")))
+	notes, err := h.CaseNoteRepo.GetByApplicantID(applicant.ID, tag)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get case notes", err))
+		return
+	}
+
+	writeList(w, r, notes, 0)
+}
+
+// CreateCaseNote handles POST /api/v1/applicants/{id}/notes
+// @Summary Add a case note
+// @Description Record an interaction with an applicant, such as a call or home visit. The authenticated user is recorded as the author. Tags are lowercased and repeated tags dropped.
+// @Tags applicants
+// @Accept json
+// @Produce json
+// @Param id path string true "Applicant ID"
+// @Param note body models.CaseNoteRequest true "Case note"
+// @Success 201 {object} models.CaseNote
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 404 {object} apierrors.APIError "Applicant not found"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applicants/{id}/notes [post]
+func (h *CaseNoteHandler) CreateCaseNote(w http.ResponseWriter, r *http.Request) {
+	applicant := h.applicant(w, r)
+	if applicant == nil {
+		return
+	}
+
+	var request models.CaseNoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
+		return
+	}
+
+	request.Tags = models.NormalizeTags(request.Tags)
+	if err := validation.CaseNoteRequest(&request); err != nil {
+		apierrors.Write(w, r, validationError(err))
+		return
+	}
+
+	actor := actorFrom(r)
+	note := models.CaseNote{
+		ApplicantID:    applicant.ID,
+		AuthorID:       actor.ID,
+		AuthorUsername: actor.Username,
+		Body:           request.Body,
+		Tags:           request.Tags,
+	}
+
+	err := models.WithTx(h.CaseNoteRepo.DB, func(tx *sql.Tx) error {
+		if err := h.CaseNoteRepo.WithTx(tx).Create(&note); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityCaseNote, note.ID,
+			models.AuditActionCreate, actor, nil, &note)
+	})
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to create case note", err))
+		return
+	}
+
+	writeJSON(w, r, http.StatusCreated, note)
+}
@@ -0,0 +1,315 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"slices"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/auth"
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/notify"
+	"one-client-view-2025tht/app/validation"
+)
+
+// CommentHandler handles requests for the internal comments on applications
+type CommentHandler struct {
+	CommentRepo     *models.CommentRepository
+	ApplicationRepo *models.ApplicationRepository
+	UserRepo        *models.UserRepository
+	AuditRepo       *models.AuditRepository
+	Notifier        *notify.Notifier // Emails mentioned users; may be nil
+}
+
+// NewCommentHandler creates a new handler with the given repositories and notifier
+func NewCommentHandler(commentRepo *models.CommentRepository, applicationRepo *models.ApplicationRepository, userRepo *models.UserRepository, auditRepo *models.AuditRepository, notifier *notify.Notifier) *CommentHandler {
+	return &CommentHandler{
+		CommentRepo:     commentRepo,
+		ApplicationRepo: applicationRepo,
+		UserRepo:        userRepo,
+		AuditRepo:       auditRepo,
+		Notifier:        notifier,
+	}
+}
+
+// application loads the application named in the path, writing a 404 if it
+// does not exist
+func (h *CommentHandler) application(w http.ResponseWriter, r *http.Request) *models.Application {
+	application, err := h.ApplicationRepo.GetByID(mux.Vars(r)["id"])
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get application", err))
+		return nil
+	}
+	if application == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Application not found"))
+		return nil
+	}
+	return application
+}
+
+// comment loads the comment named in the path, writing a 404 if it does not
+// exist, is not on the application or has been deleted
+func (h *CommentHandler) comment(w http.ResponseWriter, r *http.Request, applicationID string) *models.Comment {
+	comment, err := h.CommentRepo.GetByID(mux.Vars(r)["commentId"])
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get comment", err))
+		return nil
+	}
+	if comment == nil || comment.ApplicationID != applicationID || comment.DeletedAt != nil {
+		apierrors.Write(w, r, apierrors.NotFound("Comment not found"))
+		return nil
+	}
+	return comment
+}
+
+// GetComments handles GET /api/v1/applications/{id}/comments
+// @Summary List an application's comments
+// @Description List the internal comment threads on an application, oldest first, each with its replies. Comments are not shown to the applicant, unlike the application's notes. Deleted comments are listed, without their text, only while they have replies. The minimal view omits the text of each comment.
+// @Tags applications
+// @Produce json
+// @Param id path string true "Application ID"
+// @Success 200 {array} models.Comment
+// @Failure 404 {object} apierrors.APIError "Application not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applications/{id}/comments [get]
+func (h *CommentHandler) GetComments(w http.ResponseWriter, r *http.Request) {
+	application := h.application(w, r)
+	if application == nil {
+		return
+	}
+
+	comments, err := h.CommentRepo.GetByApplicationID(application.ID)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get comments", err))
+		return
+	}
+
+	writeList(w, r, comments, 0)
+}
+
+// CreateComment handles POST /api/v1/applications/{id}/comments
+// @Summary Add a comment
+// @Description Comment on an application, or reply to a comment with parent_id; a reply to a reply joins the same thread. The authenticated user is recorded as the author. Users mentioned with @username are emailed, if they have an email address.
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param comment body models.CommentRequest true "Comment"
+// @Success 201 {object} models.Comment
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 404 {object} apierrors.APIError "Application not found"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applications/{id}/comments [post]
+func (h *CommentHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
+	application := h.application(w, r)
+	if application == nil {
+		return
+	}
+
+	var request models.CommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
+		return
+	}
+	if err := validation.CommentRequest(&request); err != nil {
+		apierrors.Write(w, r, validationError(err))
+		return
+	}
+
+	actor := actorFrom(r)
+	comment := models.Comment{
+		ApplicationID:  application.ID,
+		AuthorID:       actor.ID,
+		AuthorUsername: actor.Username,
+		Body:           request.Body,
+	}
+
+	if request.ParentID != "" {
+		parent, err := h.CommentRepo.GetByID(request.ParentID)
+		if err != nil {
+			apierrors.Write(w, r, apierrors.Internal("Failed to get comment", err))
+			return
+		}
+		if parent == nil || parent.ApplicationID != application.ID {
+			apierrors.Write(w, r, validationError(validation.Errors{"parent_id": "must be a comment on the application"}))
+			return
+		}
+		comment.ParentID = parent.ID
+		if parent.ParentID != "" {
+			comment.ParentID = parent.ParentID
+		}
+	}
+
+	mentioned, err := h.mentionedUsers(request.Body)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get mentioned users", err))
+		return
+	}
+	comment.Mentions = usernames(mentioned)
+
+	err = models.WithTx(h.CommentRepo.DB, func(tx *sql.Tx) error {
+		if err := h.CommentRepo.WithTx(tx).Create(&comment); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityComment, comment.ID,
+			models.AuditActionCreate, actor, nil, &comment)
+	})
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to create comment", err))
+		return
+	}
+
+	h.notifyMentioned(&comment, mentioned, nil)
+	writeJSON(w, r, http.StatusCreated, comment)
+}
+
+// UpdateComment handles PUT /api/v1/applications/{id}/comments/{commentId}
+// @Summary Edit a comment
+// @Description Replace the text of a comment, recording when it was edited. Only the author can edit a comment. Users newly mentioned are emailed.
+// @Tags applications
+// @Accept json
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param commentId path string true "Comment ID"
+// @Param comment body models.CommentEditRequest true "Comment"
+// @Success 200 {object} models.Comment
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 403 {object} apierrors.APIError "Only the author can edit a comment"
+// @Failure 404 {object} apierrors.APIError "Application or comment not found"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applications/{id}/comments/{commentId} [put]
+func (h *CommentHandler) UpdateComment(w http.ResponseWriter, r *http.Request) {
+	application := h.application(w, r)
+	if application == nil {
+		return
+	}
+	existing := h.comment(w, r, application.ID)
+	if existing == nil {
+		return
+	}
+
+	actor := actorFrom(r)
+	if existing.AuthorID != actor.ID {
+		apierrors.Write(w, r, apierrors.Forbidden("Only the author can edit a comment"))
+		return
+	}
+
+	var request models.CommentEditRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
+		return
+	}
+	if err := validation.CommentEditRequest(&request); err != nil {
+		apierrors.Write(w, r, validationError(err))
+		return
+	}
+
+	mentioned, err := h.mentionedUsers(request.Body)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get mentioned users", err))
+		return
+	}
+
+	comment := *existing
+	err = models.WithTx(h.CommentRepo.DB, func(tx *sql.Tx) error {
+		if err := h.CommentRepo.WithTx(tx).Edit(&comment, request.Body, usernames(mentioned)); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityComment, comment.ID,
+			models.AuditActionUpdate, actor, existing, &comment)
+	})
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to edit comment", err))
+		return
+	}
+
+	h.notifyMentioned(&comment, mentioned, existing.Mentions)
+	writeJSON(w, r, http.StatusOK, comment)
+}
+
+// DeleteComment handles DELETE /api/v1/applications/{id}/comments/{commentId}
+// @Summary Delete a comment
+// @Description Delete a comment, removing its text; a comment starting a thread stays listed, without its text, while it has replies. Caseworkers can only delete their own comments.
+// @Tags applications
+// @Param id path string true "Application ID"
+// @Param commentId path string true "Comment ID"
+// @Success 204 "No Content"
+// @Failure 403 {object} apierrors.APIError "Only the author or an admin can delete a comment"
+// @Failure 404 {object} apierrors.APIError "Application or comment not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applications/{id}/comments/{commentId} [delete]
+func (h *CommentHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	application := h.application(w, r)
+	if application == nil {
+		return
+	}
+	existing := h.comment(w, r, application.ID)
+	if existing == nil {
+		return
+	}
+
+	actor := actorFrom(r)
+	if existing.AuthorID != actor.ID && !hasRole(r, auth.RoleAdmin) {
+		apierrors.Write(w, r, apierrors.Forbidden("Only the author or an admin can delete a comment"))
+		return
+	}
+
+	comment := *existing
+	err := models.WithTx(h.CommentRepo.DB, func(tx *sql.Tx) error {
+		if err := h.CommentRepo.WithTx(tx).Delete(&comment); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityComment, comment.ID,
+			models.AuditActionDelete, actor, existing, nil)
+	})
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to delete comment", err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// mentionedUsers returns the users mentioned in a comment's text, ignoring
+// mentions of usernames that do not exist
+func (h *CommentHandler) mentionedUsers(body string) ([]*models.User, error) {
+	var users []*models.User
+	for _, username := range models.ParseMentions(body) {
+		user, err := h.UserRepo.GetByUsername(username)
+		if err != nil {
+			return nil, err
+		}
+		if user != nil {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+// notifyMentioned emails the users mentioned in a comment, except its author
+// and those already mentioned before an edit
+func (h *CommentHandler) notifyMentioned(c *models.Comment, mentioned []*models.User, before []string) {
+	for _, user := range mentioned {
+		if user.ID != c.AuthorID && !slices.Contains(before, user.Username) {
+			h.Notifier.Mention(c, user)
+		}
+	}
+}
+
+// usernames lists the usernames of users
+func usernames(users []*models.User) []string {
+	names := []string{}
+	for _, user := range users {
+		names = append(names, user.Username)
+	}
+	return names
+}
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/validation"
+)
+
+// ConsentHandler handles requests for the consents of applicants
+type ConsentHandler struct {
+	ConsentRepo   *models.ConsentRepository
+	ApplicantRepo *models.ApplicantRepository
+	AuditRepo     *models.AuditRepository
+}
+
+// NewConsentHandler creates a new handler with the given repositories
+func NewConsentHandler(consentRepo *models.ConsentRepository, applicantRepo *models.ApplicantRepository, auditRepo *models.AuditRepository) *ConsentHandler {
+	return &ConsentHandler{
+		ConsentRepo:   consentRepo,
+		ApplicantRepo: applicantRepo,
+		AuditRepo:     auditRepo,
+	}
+}
+
+// applicant loads the applicant named in the path, writing a 404 if it does
+// not exist
+func (h *ConsentHandler) applicant(w http.ResponseWriter, r *http.Request) *models.Applicant {
+	applicant, err := h.ApplicantRepo.GetByID(mux.Vars(r)["id"])
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applicant", err))
+		return nil
+	}
+	if applicant == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Applicant not found"))
+		return nil
+	}
+	return applicant
+}
+
+// consentPurpose reads the purpose named in the path, writing a 400 if it is
+// not one of models.ConsentPurposes
+func consentPurpose(w http.ResponseWriter, r *http.Request) (string, bool) {
+	purpose := mux.Vars(r)["purpose"]
+	if !slices.Contains(models.ConsentPurposes, purpose) {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid purpose").
+			WithDetails("purpose must be one of: "+strings.Join(models.ConsentPurposes, ", ")))
+		return "", false
+	}
+	return purpose, true
+}
+
+// GetConsents handles GET /api/v1/applicants/{id}/consents
+// @Summary List an applicant's consents
+// @Description List the consents recorded for an applicant, one per purpose, including withdrawn and expired ones. active tells whether each is in force now.
+// @Tags applicants
+// @Produce json
+// @Param id path string true "Applicant ID"
+// @Success 200 {array} models.Consent
+// @Failure 404 {object} apierrors.APIError "Applicant not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applicants/{id}/consents [get]
+func (h *ConsentHandler) GetConsents(w http.ResponseWriter, r *http.Request) {
+	applicant := h.applicant(w, r)
+	if applicant == nil {
+		return
+	}
+
+	consents, err := h.ConsentRepo.GetByApplicantID(applicant.ID)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get consents", err))
+		return
+	}
+
+	writeList(w, r, consents, 0)
+}
+
+// PutConsent handles PUT /api/v1/applicants/{id}/consents/{purpose}
+// @Summary Record consent
+// @Description Record that an applicant consents to a purpose: data_sharing with partner agencies, including exports, being contacted by email (contact_email), phone (contact_phone) or post (contact_post), or having their income retrieved from government agencies (income_verification). This replaces any earlier consent to the purpose, including a withdrawn one. The authenticated user is recorded.
+// @Tags applicants
+// @Accept json
+// @Produce json
+// @Param id path string true "Applicant ID"
+// @Param purpose path string true "Purpose" Enums(data_sharing, contact_email, contact_phone, contact_post, income_verification)
+// @Param consent body models.ConsentRequest true "Consent"
+// @Success 200 {object} models.Consent "Consent replaced"
+// @Success 201 {object} models.Consent "Consent recorded"
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 404 {object} apierrors.APIError "Applicant not found"
+// @Failure 409 {object} apierrors.APIError "Applicant has been anonymized"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applicants/{id}/consents/{purpose} [put]
+func (h *ConsentHandler) PutConsent(w http.ResponseWriter, r *http.Request) {
+	purpose, ok := consentPurpose(w, r)
+	if !ok {
+		return
+	}
+	applicant := h.applicant(w, r)
+	if applicant == nil {
+		return
+	}
+	if applicant.AnonymizedAt != nil {
+		apierrors.Write(w, r, anonymizedApplicant())
+		return
+	}
+
+	var request models.ConsentRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
+		return
+	}
+	now := time.Now()
+	if err := validation.ConsentRequest(&request, now); err != nil {
+		apierrors.Write(w, r, validationError(err))
+		return
+	}
+
+	actor := actorFrom(r)
+	consent := models.Consent{
+		ApplicantID: applicant.ID,
+		Purpose:     purpose,
+		Reference:   strings.TrimSpace(request.Reference),
+		GrantedAt:   now,
+		ExpiresAt:   request.ExpiresAt,
+		RecordedBy:  actor.ID,
+	}
+	if request.GrantedAt != nil {
+		consent.GrantedAt = *request.GrantedAt
+	}
+
+	var existing *models.Consent
+	err := models.WithTx(h.ConsentRepo.DB, func(tx *sql.Tx) error {
+		repo := h.ConsentRepo.WithTx(tx)
+		var err error
+		if existing, err = repo.Get(applicant.ID, purpose); err != nil {
+			return err
+		}
+		if err := repo.Put(&consent); err != nil {
+			return err
+		}
+		action := models.AuditActionCreate
+		if existing != nil {
+			action = models.AuditActionUpdate
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityConsent, consent.ID,
+			action, actor, existing, &consent)
+	})
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to record consent", err))
+		return
+	}
+
+	status := http.StatusCreated
+	if existing != nil {
+		status = http.StatusOK
+	}
+	writeJSON(w, r, status, consent)
+}
+
+// WithdrawConsent handles DELETE /api/v1/applicants/{id}/consents/{purpose}
+// @Summary Withdraw consent
+// @Description Record that an applicant withdraws their consent to a purpose, effective now. The consent is kept, with withdrawn_at set, as a record of what was agreed.
+// @Tags applicants
+// @Produce json
+// @Param id path string true "Applicant ID"
+// @Param purpose path string true "Purpose" Enums(data_sharing, contact_email, contact_phone, contact_post, income_verification)
+// @Success 200 {object} models.Consent
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 404 {object} apierrors.APIError "Applicant or consent not found"
+// @Failure 409 {object} apierrors.APIError "Consent already withdrawn"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applicants/{id}/consents/{purpose} [delete]
+func (h *ConsentHandler) WithdrawConsent(w http.ResponseWriter, r *http.Request) {
+	purpose, ok := consentPurpose(w, r)
+	if !ok {
+		return
+	}
+	applicant := h.applicant(w, r)
+	if applicant == nil {
+		return
+	}
+
+	existing, err := h.ConsentRepo.Get(applicant.ID, purpose)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get consent", err))
+		return
+	}
+	if existing == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Consent not found"))
+		return
+	}
+	if existing.WithdrawnAt != nil {
+		apierrors.Write(w, r, apierrors.Conflict("Consent has already been withdrawn"))
+		return
+	}
+
+	actor := actorFrom(r)
+	consent := *existing
+	err = models.WithTx(h.ConsentRepo.DB, func(tx *sql.Tx) error {
+		if err := h.ConsentRepo.WithTx(tx).Withdraw(&consent, time.Now(), actor.ID); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityConsent, consent.ID,
+			models.AuditActionUpdate, actor, existing, &consent)
+	})
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to withdraw consent", err))
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, consent)
+}
@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/auth"
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/validation"
+)
+
+// CustomFieldHandler handles requests for the registry of the custom fields
+// recorded about applicants. Anyone may read the registry, so frontends can
+// render the fields; changing it requires the admin role.
+type CustomFieldHandler struct {
+	CustomFieldRepo *models.CustomFieldRepository
+	AuditRepo       *models.AuditRepository
+}
+
+// NewCustomFieldHandler creates a new handler with the given repositories
+func NewCustomFieldHandler(customFieldRepo *models.CustomFieldRepository, auditRepo *models.AuditRepository) *CustomFieldHandler {
+	return &CustomFieldHandler{
+		CustomFieldRepo: customFieldRepo,
+		AuditRepo:       auditRepo,
+	}
+}
+
+// loadCustomFields loads the custom field definitions that applicants and
+// scheme criteria are validated against, writing a 500 if they cannot be read
+func loadCustomFields(w http.ResponseWriter, r *http.Request, repo *models.CustomFieldRepository) ([]models.CustomField, bool) {
+	fields, err := repo.GetAll()
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get custom fields", err))
+		return nil, false
+	}
+	return fields, true
+}
+
+// requireCustomFieldAdmin writes a 403 and returns false unless the user is
+// an admin
+func requireCustomFieldAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if !hasRole(r, auth.RoleAdmin) {
+		apierrors.Write(w, r, apierrors.Forbidden("Managing custom fields requires the admin role"))
+		return false
+	}
+	return true
+}
+
+// field loads the custom field named in the path, writing a 404 if it does not
+// exist
+func (h *CustomFieldHandler) field(w http.ResponseWriter, r *http.Request) *models.CustomField {
+	field, err := h.CustomFieldRepo.GetByKey(mux.Vars(r)["key"])
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get custom field", err))
+		return nil
+	}
+	if field == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Custom field not found"))
+		return nil
+	}
+	return field
+}
+
+// GetCustomFields handles GET /api/v1/custom-fields
+// @Summary List custom fields
+// @Description List the custom fields defined for applicants, ordered by key. Their values are set in the custom_fields of applicants, can filter GET /api/v1/applicants with custom.{key}={value}, and can be compared in scheme criteria rules as the field custom.{key}.
+// @Tags custom-fields
+// @Produce json
+// @Success 200 {array} models.CustomField
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/custom-fields [get]
+func (h *CustomFieldHandler) GetCustomFields(w http.ResponseWriter, r *http.Request) {
+	fields, ok := loadCustomFields(w, r, h.CustomFieldRepo)
+	if !ok {
+		return
+	}
+
+	writeList(w, r, fields, 0)
+}
+
+// GetCustomField handles GET /api/v1/custom-fields/{key}
+// @Summary Get a custom field
+// @Description Retrieve the definition of a custom field
+// @Tags custom-fields
+// @Produce json
+// @Param key path string true "Custom field key"
+// @Success 200 {object} models.CustomField
+// @Failure 404 {object} apierrors.APIError "Custom field not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/custom-fields/{key} [get]
+func (h *CustomFieldHandler) GetCustomField(w http.ResponseWriter, r *http.Request) {
+	field := h.field(w, r)
+	if field == nil {
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, field)
+}
+
+// CreateCustomField handles POST /api/v1/custom-fields
+// @Summary Define a custom field
+// @Description Define a custom field for applicants: a string (optionally with max_length), number (optionally with min and max), boolean, or enum with the given options. Applicants saved from then on are validated against it; making it required does not affect applicants until they are next saved. Requires the admin role.
+// @Tags custom-fields
+// @Accept json
+// @Produce json
+// @Param field body models.CustomField true "Custom field"
+// @Success 201 {object} models.CustomField
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 403 {object} apierrors.APIError "Requires the admin role"
+// @Failure 409 {object} apierrors.APIError "Custom field already exists"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/custom-fields [post]
+func (h *CustomFieldHandler) CreateCustomField(w http.ResponseWriter, r *http.Request) {
+	if !requireCustomFieldAdmin(w, r) {
+		return
+	}
+
+	var field models.CustomField
+	if err := json.NewDecoder(r.Body).Decode(&field); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
+		return
+	}
+	if err := validation.CustomField(&field); err != nil {
+		apierrors.Write(w, r, validationError(err))
+		return
+	}
+
+	existing, err := h.CustomFieldRepo.GetByKey(field.Key)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get custom field", err))
+		return
+	}
+	if existing != nil {
+		apierrors.Write(w, r, apierrors.Conflict("Custom field already exists").
+			WithDetails("update it with PUT /api/v1/custom-fields/"+field.Key))
+		return
+	}
+
+	err = models.WithTx(h.CustomFieldRepo.DB, func(tx *sql.Tx) error {
+		if err := h.CustomFieldRepo.WithTx(tx).Create(&field); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityCustomField, field.Key,
+			models.AuditActionCreate, actorFrom(r), nil, &field)
+	})
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to create custom field", err))
+		return
+	}
+
+	writeJSON(w, r, http.StatusCreated, field)
+}
+
+// UpdateCustomField handles PUT /api/v1/custom-fields/{key}
+// @Summary Update a custom field
+// @Description Replace the definition of a custom field. Its key and type cannot be changed. Values stored before the change are kept, and applicants are validated against the new definition when next saved. Requires the admin role.
+// @Tags custom-fields
+// @Accept json
+// @Produce json
+// @Param key path string true "Custom field key"
+// @Param field body models.CustomField true "Custom field"
+// @Success 200 {object} models.CustomField
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 403 {object} apierrors.APIError "Requires the admin role"
+// @Failure 404 {object} apierrors.APIError "Custom field not found"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/custom-fields/{key} [put]
+func (h *CustomFieldHandler) UpdateCustomField(w http.ResponseWriter, r *http.Request) {
+	if !requireCustomFieldAdmin(w, r) {
+		return
+	}
+	existing := h.field(w, r)
+	if existing == nil {
+		return
+	}
+
+	var field models.CustomField
+	if err := json.NewDecoder(r.Body).Decode(&field); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
+		return
+	}
+	if field.Key == "" {
+		field.Key = existing.Key
+	}
+	if field.Type == "" {
+		field.Type = existing.Type
+	}
+	fields := validation.Errors{}
+	if field.Key != existing.Key {
+		fields["key"] = "cannot be changed"
+	}
+	if field.Type != existing.Type {
+		fields["type"] = "cannot be changed"
+	}
+	if len(fields) > 0 {
+		apierrors.Write(w, r, validationError(fields))
+		return
+	}
+	if err := validation.CustomField(&field); err != nil {
+		apierrors.Write(w, r, validationError(err))
+		return
+	}
+	field.CreatedAt = existing.CreatedAt
+
+	err := models.WithTx(h.CustomFieldRepo.DB, func(tx *sql.Tx) error {
+		if err := h.CustomFieldRepo.WithTx(tx).Update(&field); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityCustomField, field.Key,
+			models.AuditActionUpdate, actorFrom(r), existing, &field)
+	})
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to update custom field", err))
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, field)
+}
+
+// DeleteCustomField handles DELETE /api/v1/custom-fields/{key}
+// @Summary Delete a custom field
+// @Description Delete the definition of a custom field no applicant, including a deleted one, has a value for. Scheme criteria comparing it stop matching anyone. Requires the admin role.
+// @Tags custom-fields
+// @Param key path string true "Custom field key"
+// @Success 204 "No Content"
+// @Failure 403 {object} apierrors.APIError "Requires the admin role"
+// @Failure 404 {object} apierrors.APIError "Custom field not found"
+// @Failure 409 {object} apierrors.APIError "Custom field is in use"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/custom-fields/{key} [delete]
+func (h *CustomFieldHandler) DeleteCustomField(w http.ResponseWriter, r *http.Request) {
+	if !requireCustomFieldAdmin(w, r) {
+		return
+	}
+	existing := h.field(w, r)
+	if existing == nil {
+		return
+	}
+
+	count, err := h.CustomFieldRepo.CountInUse(existing.Key)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to check custom field", err))
+		return
+	}
+	if count > 0 {
+		apierrors.Write(w, r, apierrors.Conflict("Custom field is in use").
+			WithDetails(strconv.Itoa(count)+" applicant(s) have a value for it; remove the values first"))
+		return
+	}
+
+	err = models.WithTx(h.CustomFieldRepo.DB, func(tx *sql.Tx) error {
+		if err := h.CustomFieldRepo.WithTx(tx).Delete(existing.Key); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityCustomField, existing.Key,
+			models.AuditActionDelete, actorFrom(r), existing, nil)
+	})
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to delete custom field", err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,319 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/auth"
+	"one-client-view-2025tht/app/logging"
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/storage"
+)
+
+// multipartOverhead allows for the multipart headers and boundaries around
+// an uploaded file when limiting the request size
+const multipartOverhead = 64 << 10
+
+// maxFilenameLength is the longest filename kept, matching the column
+const maxFilenameLength = 255
+
+// DocumentHandler handles requests for the supporting documents of
+// applications
+type DocumentHandler struct {
+	DocumentRepo    *models.DocumentRepository
+	ApplicationRepo *models.ApplicationRepository
+	AuditRepo       *models.AuditRepository
+	Store           storage.Store
+	MaxSize         int64    // Largest file accepted, in bytes
+	AllowedTypes    []string // Media types accepted, detected from the content
+}
+
+// NewDocumentHandler creates a new handler with the given repositories,
+// store and upload limits
+func NewDocumentHandler(documentRepo *models.DocumentRepository, appRepo *models.ApplicationRepository, auditRepo *models.AuditRepository, store storage.Store, maxSize int64, allowedTypes []string) *DocumentHandler {
+	return &DocumentHandler{
+		DocumentRepo:    documentRepo,
+		ApplicationRepo: appRepo,
+		AuditRepo:       auditRepo,
+		Store:           store,
+		MaxSize:         maxSize,
+		AllowedTypes:    allowedTypes,
+	}
+}
+
+// application loads the application named in the path, writing a 404 if it
+// does not exist
+func (h *DocumentHandler) application(w http.ResponseWriter, r *http.Request) *models.Application {
+	application, err := h.ApplicationRepo.GetByID(mux.Vars(r)["id"])
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get application", err))
+		return nil
+	}
+	if application == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Application not found"))
+		return nil
+	}
+	return application
+}
+
+// document loads the document named in the path, writing a 404 if it does
+// not exist
+func (h *DocumentHandler) document(w http.ResponseWriter, r *http.Request) *models.Document {
+	vars := mux.Vars(r)
+	document, err := h.DocumentRepo.GetByID(vars["id"], vars["documentId"])
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get document", err))
+		return nil
+	}
+	if document == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Document not found"))
+		return nil
+	}
+	return document
+}
+
+// GetDocuments handles GET /api/v1/applications/{id}/documents
+// @Summary List an application's documents
+// @Description List the metadata of the documents attached to an application, oldest first
+// @Tags applications
+// @Produce json
+// @Param id path string true "Application ID"
+// @Success 200 {array} models.Document
+// @Failure 404 {object} apierrors.APIError "Application not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applications/{id}/documents [get]
+func (h *DocumentHandler) GetDocuments(w http.ResponseWriter, r *http.Request) {
+	application := h.application(w, r)
+	if application == nil {
+		return
+	}
+
+	documents, err := h.DocumentRepo.GetByApplicationID(application.ID)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get documents", err))
+		return
+	}
+
+	writeList(w, r, documents, 0)
+}
+
+// UploadDocument handles POST /api/v1/applications/{id}/documents
+// @Summary Upload a document
+// @Description Attach a supporting document, such as a payslip or letter, to an application. The file's type is detected from its content and must be one of the configured types (by default PDF, JPEG or PNG).
+// @Tags applications
+// @Accept mpfd
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param file formData file true "The document"
+// @Success 201 {object} models.Document
+// @Failure 400 {object} apierrors.APIError "Invalid multipart form"
+// @Failure 404 {object} apierrors.APIError "Application not found"
+// @Failure 413 {object} apierrors.APIError "File too large"
+// @Failure 415 {object} apierrors.APIError "Not multipart/form-data, or file type not allowed"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applications/{id}/documents [post]
+func (h *DocumentHandler) UploadDocument(w http.ResponseWriter, r *http.Request) {
+	application := h.application(w, r)
+	if application == nil {
+		return
+	}
+
+	tooLarge := apierrors.PayloadTooLarge("File too large").
+		WithDetails("documents may be at most " + strconv.FormatInt(h.MaxSize, 10) + " bytes")
+
+	// Files beyond the first megabyte are spooled to temporary files
+	r.Body = http.MaxBytesReader(w, r.Body, h.MaxSize+multipartOverhead)
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		var maxBytes *http.MaxBytesError
+		switch {
+		case errors.As(err, &maxBytes):
+			apierrors.Write(w, r, tooLarge)
+		case errors.Is(err, http.ErrNotMultipart):
+			apierrors.Write(w, r, apierrors.UnsupportedMediaType("Content-Type must be multipart/form-data"))
+		default:
+			apierrors.Write(w, r, apierrors.BadRequest("Invalid multipart form").WithDetails(err.Error()))
+		}
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Validation(map[string]string{"file": "is required"}))
+		return
+	}
+	defer file.Close()
+
+	if header.Size > h.MaxSize {
+		apierrors.Write(w, r, tooLarge)
+		return
+	}
+	if header.Size == 0 {
+		apierrors.Write(w, r, apierrors.Validation(map[string]string{"file": "must not be empty"}))
+		return
+	}
+
+	// The declared type is not trusted; detect it from the content
+	sniff := make([]byte, 512)
+	n, err := io.ReadFull(file, sniff)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		apierrors.Write(w, r, apierrors.Internal("Failed to read file", err))
+		return
+	}
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(sniff[:n]))
+	if !slices.Contains(h.AllowedTypes, contentType) {
+		apierrors.Write(w, r, apierrors.UnsupportedMediaType("File type not allowed").
+			WithDetails(contentType+" is not one of "+strings.Join(h.AllowedTypes, ", ")))
+		return
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to read file", err))
+		return
+	}
+
+	document := models.Document{
+		ID:            uuid.New().String(),
+		ApplicationID: application.ID,
+		Filename:      documentFilename(header.Filename),
+		ContentType:   contentType,
+		Size:          header.Size,
+		UploadedBy:    actorFrom(r).ID,
+	}
+	document.StorageKey = "applications/" + application.ID + "/" + document.ID
+
+	hash := sha256.New()
+	if err := h.Store.Put(r.Context(), document.StorageKey, io.TeeReader(file, hash), document.Size, contentType); err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to store document", err))
+		return
+	}
+	document.SHA256 = hex.EncodeToString(hash.Sum(nil))
+
+	err = models.WithTx(h.DocumentRepo.DB, func(tx *sql.Tx) error {
+		if err := h.DocumentRepo.WithTx(tx).Create(&document); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityDocument, document.ID,
+			models.AuditActionCreate, actorFrom(r), nil, &document)
+	})
+	if err != nil {
+		if err := h.Store.Delete(r.Context(), document.StorageKey); err != nil {
+			logging.FromContext(r.Context()).Error("Failed to delete unsaved document", "key", document.StorageKey, "error", err)
+		}
+		apierrors.Write(w, r, apierrors.Internal("Failed to save document", err))
+		return
+	}
+
+	writeJSON(w, r, http.StatusCreated, document)
+}
+
+// documentFilename reduces an uploaded file's name to its base name, so a
+// client cannot supply a path
+func documentFilename(name string) string {
+	name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, `\`, "/")))
+	if name == "" || name == "." || name == "/" {
+		return "document"
+	}
+	if len(name) > maxFilenameLength {
+		name = strings.ToValidUTF8(name[:maxFilenameLength], "")
+	}
+	return name
+}
+
+// DownloadDocument handles GET /api/v1/applications/{id}/documents/{documentId}
+// @Summary Download a document
+// @Description Download the content of a document attached to an application. Not available to the viewer role.
+// @Tags applications
+// @Produce application/octet-stream
+// @Param id path string true "Application ID"
+// @Param documentId path string true "Document ID"
+// @Success 200 {file} file "The document, with its detected content type"
+// @Failure 403 {object} apierrors.APIError "Forbidden"
+// @Failure 404 {object} apierrors.APIError "Document not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applications/{id}/documents/{documentId} [get]
+func (h *DocumentHandler) DownloadDocument(w http.ResponseWriter, r *http.Request) {
+	// Documents are not data-minimized, so viewers cannot see them
+	if hasRole(r, auth.RoleViewer) {
+		apierrors.Write(w, r, apierrors.Forbidden("Documents cannot be downloaded by the "+auth.RoleViewer+" role"))
+		return
+	}
+
+	document := h.document(w, r)
+	if document == nil {
+		return
+	}
+
+	content, err := h.Store.Get(r.Context(), document.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		apierrors.Write(w, r, apierrors.Internal("Document content is missing", err))
+		return
+	}
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get document", err))
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", document.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(document.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": document.Filename}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if _, err := io.Copy(w, content); err != nil {
+		logging.FromContext(r.Context()).Warn("Failed to send document", "document_id", document.ID, "error", err)
+	}
+}
+
+// DeleteDocument handles DELETE /api/v1/applications/{id}/documents/{documentId}
+// @Summary Delete a document
+// @Description Remove a document from an application and delete its content
+// @Tags applications
+// @Produce json
+// @Param id path string true "Application ID"
+// @Param documentId path string true "Document ID"
+// @Success 204 "No content"
+// @Failure 404 {object} apierrors.APIError "Document not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applications/{id}/documents/{documentId} [delete]
+func (h *DocumentHandler) DeleteDocument(w http.ResponseWriter, r *http.Request) {
+	document := h.document(w, r)
+	if document == nil {
+		return
+	}
+
+	err := models.WithTx(h.DocumentRepo.DB, func(tx *sql.Tx) error {
+		if err := h.DocumentRepo.WithTx(tx).Delete(document.ID); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityDocument, document.ID,
+			models.AuditActionDelete, actorFrom(r), document, nil)
+	})
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to delete document", err))
+		return
+	}
+
+	// The metadata is gone, so a failure here only leaves an unreachable file
+	if err := h.Store.Delete(r.Context(), document.StorageKey); err != nil {
+		logging.FromContext(r.Context()).Error("Failed to delete content of document", "document_id", document.ID, "error", err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/jobs"
+	"one-client-view-2025tht/app/models"
+)
+
+// maxBatchEligibilityApplicants is the most applicant IDs a batch eligibility
+// run may name
+const maxBatchEligibilityApplicants = 10000
+
+// schemeSnapshot is a SchemeStore over schemes read once, so a batch run
+// assesses every applicant against the same terms
+type schemeSnapshot struct {
+	schemes  []models.Scheme
+	versions []models.SchemeVersion
+}
+
+func (s schemeSnapshot) GetAll() ([]models.Scheme, error)                { return s.schemes, nil }
+func (s schemeSnapshot) GetAllVersions() ([]models.SchemeVersion, error) { return s.versions, nil }
+
+// RunBatchEligibility handles POST /api/v1/schemes/eligible/batch
+// @Summary Check eligibility in bulk
+// @Description Queue a job finding the schemes each of the given applicants, or every applicant, is eligible for, assessed against the terms in effect at as_of. Poll the returned job for the outcome.
+// @Tags schemes
+// @Accept json
+// @Produce json
+// @Param request body models.BatchEligibilityRequest false "Applicants to check and the time to assess at"
+// @Success 202 {object} models.Job "Queued; the result is a models.BatchEligibilityResult"
+// @Failure 400 {object} apierrors.APIError "Invalid request body"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/schemes/eligible/batch [post]
+func (h *SchemeHandler) RunBatchEligibility(w http.ResponseWriter, r *http.Request) {
+	// The body is optional
+	var req models.BatchEligibilityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
+		return
+	}
+	if len(req.ApplicantIDs) > maxBatchEligibilityApplicants {
+		apierrors.Write(w, r, apierrors.BadRequest("At most "+strconv.Itoa(maxBatchEligibilityApplicants)+" applicants can be checked at once"))
+		return
+	}
+	if req.AsOf == nil {
+		// Fixed now, so retries give the same answer
+		now := time.Now()
+		req.AsOf = &now
+	}
+
+	job, err := h.JobRepo.Enqueue(models.JobBatchEligibility, req, actorFrom(r).ID)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to queue eligibility check", err))
+		return
+	}
+
+	writeJobAccepted(w, r, job)
+}
+
+// RunBatchEligibilityJob is the handler of eligibility.batch jobs
+func (h *SchemeHandler) RunBatchEligibilityJob(ctx context.Context, job *models.Job) (interface{}, error) {
+	var req models.BatchEligibilityRequest
+	if err := json.Unmarshal(job.Payload, &req); err != nil || req.AsOf == nil {
+		return nil, jobs.Permanent(fmt.Errorf("invalid payload: %v", err))
+	}
+
+	schemes, err := h.SchemeCache.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("error getting schemes: %v", err)
+	}
+	versions, err := h.SchemeCache.GetAllVersions()
+	if err != nil {
+		return nil, fmt.Errorf("error getting scheme versions: %v", err)
+	}
+	snapshot := schemeSnapshot{schemes: schemes, versions: versions}
+
+	result := models.BatchEligibilityResult{AsOf: *req.AsOf, Applicants: []models.ApplicantEligibility{}}
+
+	var applicants []models.Applicant
+	if len(req.ApplicantIDs) == 0 {
+		if applicants, err = h.ApplicantCache.Repo.GetAll(); err != nil {
+			return nil, err
+		}
+	} else {
+		found, err := h.ApplicantCache.Repo.GetByIDs(req.ApplicantIDs)
+		if err != nil {
+			return nil, err
+		}
+		seen := make(map[string]bool)
+		for _, id := range req.ApplicantIDs {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			if a := found[id]; a != nil && a.DeletedAt == nil {
+				applicants = append(applicants, *a)
+			} else {
+				result.NotFound = append(result.NotFound, id)
+			}
+		}
+		sort.Strings(result.NotFound)
+	}
+	// Applicants in shared households are assessed against them
+	if err := h.HouseholdRepo.ResolveEach(applicants, *req.AsOf); err != nil {
+		return nil, err
+	}
+
+	for i := range applicants {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		eligible, err := models.EligibleSchemes(snapshot, &applicants[i], *req.AsOf)
+		if err != nil {
+			return nil, err
+		}
+		entry := models.ApplicantEligibility{ApplicantID: applicants[i].ID, SchemeIDs: []string{}}
+		for _, s := range eligible {
+			entry.SchemeIDs = append(entry.SchemeIDs, s.ID)
+		}
+		result.Applicants = append(result.Applicants, entry)
+	}
+
+	return result, nil
+}
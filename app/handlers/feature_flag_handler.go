@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"slices"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/auth"
+	"one-client-view-2025tht/app/features"
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/validation"
+)
+
+// FeatureFlagHandler handles requests for the feature flags, which only
+// admins may read or change
+type FeatureFlagHandler struct {
+	Flags           *features.Set
+	FeatureFlagRepo *models.FeatureFlagRepository
+	AuditRepo       *models.AuditRepository
+}
+
+// NewFeatureFlagHandler creates a new handler for the given flags
+func NewFeatureFlagHandler(flags *features.Set, featureFlagRepo *models.FeatureFlagRepository, auditRepo *models.AuditRepository) *FeatureFlagHandler {
+	return &FeatureFlagHandler{
+		Flags:           flags,
+		FeatureFlagRepo: featureFlagRepo,
+		AuditRepo:       auditRepo,
+	}
+}
+
+// requireFeatureFlagAdmin writes a 403 and returns false unless the user is
+// an admin
+func requireFeatureFlagAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if !hasRole(r, auth.RoleAdmin) {
+		apierrors.Write(w, r, apierrors.Forbidden("Feature flags require the admin role"))
+		return false
+	}
+	return true
+}
+
+// flag returns the known flag named in the path, writing a 404 if there is
+// none
+func (h *FeatureFlagHandler) flag(w http.ResponseWriter, r *http.Request) (features.Flag, bool) {
+	flag, ok := features.Lookup(mux.Vars(r)["name"])
+	if !ok {
+		apierrors.Write(w, r, apierrors.NotFound("Feature flag not found"))
+	}
+	return flag, ok
+}
+
+// GetFeatureFlags handles GET /api/v1/admin/features
+// @Summary List feature flags
+// @Description List the known feature flags, with their configured defaults, the overrides admins have set and whether each is on. Requires the admin role.
+// @Tags admin
+// @Produce json
+// @Success 200 {array} models.FeatureFlagState
+// @Failure 403 {object} apierrors.APIError "Requires the admin role"
+// @Security BearerAuth
+// @Router /api/v1/admin/features [get]
+func (h *FeatureFlagHandler) GetFeatureFlags(w http.ResponseWriter, r *http.Request) {
+	if !requireFeatureFlagAdmin(w, r) {
+		return
+	}
+	writeJSON(w, r, http.StatusOK, h.Flags.States())
+}
+
+// SetFeatureFlag handles PUT /api/v1/admin/features/{name}
+// @Summary Override a feature flag
+// @Description Turn a feature flag on or off, whatever its default, or on for the given roles only. The change applies at once on the server handling the request, and on the others within the refresh interval (features.refresh_interval, 30 seconds by default). Requires the admin role.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param name path string true "Feature flag name"
+// @Param flag body models.FeatureFlagRequest true "Setting"
+// @Success 200 {object} models.FeatureFlagState
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 403 {object} apierrors.APIError "Requires the admin role"
+// @Failure 404 {object} apierrors.APIError "Feature flag not found"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/admin/features/{name} [put]
+func (h *FeatureFlagHandler) SetFeatureFlag(w http.ResponseWriter, r *http.Request) {
+	if !requireFeatureFlagAdmin(w, r) {
+		return
+	}
+	flag, ok := h.flag(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.FeatureFlagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
+		return
+	}
+	fields := validation.Errors{}
+	if req.Enabled == nil {
+		fields["enabled"] = "is required"
+	}
+	for _, role := range req.Roles {
+		if !slices.Contains([]string{auth.RoleAdmin, auth.RoleCaseworker, auth.RoleViewer, auth.RoleApplicant}, role) {
+			fields["roles"] = "must be admin, caseworker, viewer or applicant"
+		}
+	}
+	if len(fields) > 0 {
+		apierrors.Write(w, r, validationError(fields))
+		return
+	}
+
+	override := models.FeatureFlag{Name: flag.Name, Enabled: *req.Enabled, Roles: req.Roles, UpdatedBy: actorFrom(r).ID}
+	err := models.WithTx(h.FeatureFlagRepo.DB, func(tx *sql.Tx) error {
+		repo := h.FeatureFlagRepo.WithTx(tx)
+		existing, err := repo.GetByName(flag.Name)
+		if err != nil {
+			return err
+		}
+		if err := repo.Save(&override); err != nil {
+			return err
+		}
+		action := models.AuditActionUpdate
+		if existing == nil {
+			action = models.AuditActionCreate
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityFeatureFlag, flag.Name, action, actorFrom(r), existing, &override)
+	})
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to set feature flag", err))
+		return
+	}
+	h.Flags.Invalidate()
+
+	state, _ := h.Flags.State(flag.Name)
+	writeJSON(w, r, http.StatusOK, state)
+}
+
+// ResetFeatureFlag handles DELETE /api/v1/admin/features/{name}
+// @Summary Reset a feature flag
+// @Description Remove the override of a feature flag, returning it to its configured default. Requires the admin role.
+// @Tags admin
+// @Produce json
+// @Param name path string true "Feature flag name"
+// @Success 200 {object} models.FeatureFlagState
+// @Failure 403 {object} apierrors.APIError "Requires the admin role"
+// @Failure 404 {object} apierrors.APIError "Feature flag not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/admin/features/{name} [delete]
+func (h *FeatureFlagHandler) ResetFeatureFlag(w http.ResponseWriter, r *http.Request) {
+	if !requireFeatureFlagAdmin(w, r) {
+		return
+	}
+	flag, ok := h.flag(w, r)
+	if !ok {
+		return
+	}
+
+	err := models.WithTx(h.FeatureFlagRepo.DB, func(tx *sql.Tx) error {
+		repo := h.FeatureFlagRepo.WithTx(tx)
+		existing, err := repo.GetByName(flag.Name)
+		if err != nil || existing == nil {
+			return err
+		}
+		if err := repo.Delete(flag.Name); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityFeatureFlag, flag.Name, models.AuditActionDelete, actorFrom(r), existing, nil)
+	})
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to reset feature flag", err))
+		return
+	}
+	h.Flags.Invalidate()
+
+	state, _ := h.Flags.State(flag.Name)
+	writeJSON(w, r, http.StatusOK, state)
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"one-client-view-2025tht/app/database"
+)
+
+// readinessTimeout bounds how long /readyz waits for the database
+const readinessTimeout = 2 * time.Second
+
+// HealthHandler reports whether the server can serve requests
+type HealthHandler struct {
+	DB *database.Database
+}
+
+// NewHealthHandler creates a new handler checking the given database
+func NewHealthHandler(db *database.Database) *HealthHandler {
+	return &HealthHandler{DB: db}
+}
+
+// Readiness is the response of /readyz
+type Readiness struct {
+	Status   string         `json:"status" example:"ready" enums:"ready,unavailable"`
+	Database DatabaseHealth `json:"database"`
+}
+
+// DatabaseHealth is the state of the database connection
+type DatabaseHealth struct {
+	Status  string                  `json:"status" example:"up" enums:"up,down"`
+	Error   string                  `json:"error,omitempty"`   // Why the database could not be reached
+	Breaker *database.BreakerStatus `json:"breaker,omitempty"` // The circuit breaker guarding MySQL connections; omitted for SQLite
+}
+
+// Ready handles GET /readyz
+// @Summary Check readiness
+// @Description Ping the database and report the state of its circuit breaker. Responds 503 while the database cannot be reached or the breaker is open, so load balancers stop routing to this instance.
+// @Tags health
+// @Produce json
+// @Success 200 {object} handlers.Readiness
+// @Failure 503 {object} handlers.Readiness "Not ready"
+// @Router /readyz [get]
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+	defer cancel()
+
+	result := Readiness{Status: "ready", Database: DatabaseHealth{Status: "up"}}
+	if err := h.DB.PingContext(ctx); err != nil {
+		result.Database.Status = "down"
+		result.Database.Error = err.Error()
+	}
+	if h.DB.Breaker != nil {
+		status := h.DB.Breaker.Status()
+		result.Database.Breaker = &status
+	}
+
+	code := http.StatusOK
+	if result.Database.Status != "up" || (result.Database.Breaker != nil && result.Database.Breaker.State == database.BreakerOpen) {
+		result.Status = "unavailable"
+		code = http.StatusServiceUnavailable
+	}
+
+	writeJSON(w, r, code, result)
+}
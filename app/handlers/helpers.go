@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/auth"
+	"one-client-view-2025tht/app/date"
+	"one-client-view-2025tht/app/mergepatch"
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/validation"
+)
+
+// parseTimeParam parses an optional RFC3339 timestamp or YYYY-MM-DD date
+func parseTimeParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// parseDateParam parses an optional YYYY-MM-DD date, or the date of an
+// RFC3339 timestamp as written
+func parseDateParam(value string) (date.Date, error) {
+	if value == "" {
+		return date.Date{}, nil
+	}
+	return date.Parse(value)
+}
+
+// actorFrom identifies the authenticated user making the request
+func actorFrom(r *http.Request) models.Actor {
+	claims := auth.FromContext(r.Context())
+	if claims == nil {
+		return models.Actor{}
+	}
+	return models.Actor{ID: claims.UserID(), Username: claims.Username}
+}
+
+// hasRole reports whether the authenticated user has the given role
+func hasRole(r *http.Request, role string) bool {
+	claims := auth.FromContext(r.Context())
+	return claims != nil && claims.Role == role
+}
+
+// assignableUser gets the user with the given ID if work can be assigned to
+// them, as a caseworker or admin, or nil otherwise
+func assignableUser(users *models.UserRepository, id string) (*models.User, error) {
+	user, err := users.GetByID(id)
+	if err != nil || user == nil {
+		return nil, err
+	}
+	if user.Role != auth.RoleAdmin && user.Role != auth.RoleCaseworker {
+		return nil, nil
+	}
+	return user, nil
+}
+
+// assignedToParam parses the assigned_to query parameter of lists that can
+// be filtered by assignee: a user ID, me for the authenticated user, or none
+// for work not assigned to anyone
+func assignedToParam(r *http.Request) (assignedTo string, unassigned bool) {
+	switch value := r.URL.Query().Get("assigned_to"); value {
+	case "me":
+		return actorFrom(r).ID, false
+	case "none":
+		return "", true
+	default:
+		return value, false
+	}
+}
+
+// includeDeletedParam parses the admin-only include_deleted query parameter
+func includeDeletedParam(r *http.Request) (bool, *apierrors.APIError) {
+	value := r.URL.Query().Get("include_deleted")
+	if value == "" {
+		return false, nil
+	}
+
+	include, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, apierrors.BadRequest("Invalid include_deleted").WithDetails(err.Error())
+	}
+	if include && !hasRole(r, auth.RoleAdmin) {
+		return false, apierrors.Forbidden("include_deleted requires the admin role")
+	}
+	return include, nil
+}
+
+// validationError converts a validation failure into a 422 response listing
+// the invalid fields
+func validationError(err error) *apierrors.APIError {
+	var fields validation.Errors
+	if errors.As(err, &fields) {
+		return apierrors.Validation(fields)
+	}
+	return apierrors.Unprocessable(err.Error())
+}
+
+// setETag sets the ETag response header from a record's version
+func setETag(w http.ResponseWriter, version int) {
+	w.Header().Set("ETag", `"`+strconv.Itoa(version)+`"`)
+}
+
+// expectedVersion returns the version an update was based on, taken from the
+// If-Match header or, failing that, the version field of the payload. An
+// If-Match of "*" matches the current version.
+func expectedVersion(r *http.Request, payloadVersion, current int) (int, *apierrors.APIError) {
+	if header := strings.TrimSpace(r.Header.Get("If-Match")); header != "" {
+		if header == "*" {
+			return current, nil
+		}
+		tag := strings.Trim(strings.TrimPrefix(header, "W/"), `"`)
+		version, err := strconv.Atoi(tag)
+		if err != nil || version < 1 {
+			return 0, apierrors.BadRequest("Invalid If-Match header").
+				WithDetails(`expected an ETag returned by this API, e.g. "3"`)
+		}
+		return version, nil
+	}
+	if payloadVersion > 0 {
+		return payloadVersion, nil
+	}
+	return 0, apierrors.PreconditionRequired("Updates require an If-Match header or a version field")
+}
+
+// duplicateIdentityNumber is the error returned when an applicant is saved with
+// another applicant's identity number
+func duplicateIdentityNumber() *apierrors.APIError {
+	return apierrors.Conflict("An applicant with this identity number already exists").
+		WithDetails("look the applicant up with GET /api/v1/applicants/by-nric/{nric}, or merge the records")
+}
+
+// anonymizedApplicant is the error returned when changing an applicant whose
+// personal data has been anonymized
+func anonymizedApplicant() *apierrors.APIError {
+	return apierrors.Conflict("Applicant has been anonymized").
+		WithDetails("anonymized applicants cannot be changed; register the person again if needed")
+}
+
+// versionConflict is the error returned when an update is based on a stale
+// version of a record
+func versionConflict() *apierrors.APIError {
+	return apierrors.Conflict("Version conflict").
+		WithDetails("the record was modified by another request; fetch it again and retry")
+}
+
+// readMergePatch reads a JSON Merge Patch request body, returning it along
+// with the version field it carries, if any. The version is read from the
+// patch itself rather than the merged result, so that a patch which omits it
+// still requires an If-Match header.
+func readMergePatch(r *http.Request) ([]byte, int, *apierrors.APIError) {
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || (mediaType != mergepatch.ContentType && mediaType != "application/json") {
+			return nil, 0, apierrors.UnsupportedMediaType("Unsupported Content-Type").
+				WithDetails("PATCH requests take " + mergepatch.ContentType)
+		}
+	}
+
+	patch, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, 0, apierrors.BadRequest("Invalid request body").WithDetails(err.Error())
+	}
+
+	var meta struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(patch, &meta); err != nil {
+		return nil, 0, apierrors.BadRequest("Invalid request body").WithDetails(err.Error())
+	}
+	return patch, meta.Version, nil
+}
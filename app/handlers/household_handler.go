@@ -0,0 +1,384 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/validation"
+)
+
+// HouseholdHandler handles requests for the households shared by applicants
+// of the same family
+type HouseholdHandler struct {
+	HouseholdRepo *models.HouseholdRepository
+	ApplicantRepo *models.ApplicantRepository
+	AuditRepo     *models.AuditRepository
+}
+
+// NewHouseholdHandler creates a new handler with the given repositories
+func NewHouseholdHandler(householdRepo *models.HouseholdRepository, applicantRepo *models.ApplicantRepository, auditRepo *models.AuditRepository) *HouseholdHandler {
+	return &HouseholdHandler{
+		HouseholdRepo: householdRepo,
+		ApplicantRepo: applicantRepo,
+		AuditRepo:     auditRepo,
+	}
+}
+
+// household loads the household named in the path, writing a 404 if it does
+// not exist
+func (h *HouseholdHandler) household(w http.ResponseWriter, r *http.Request) *models.Household {
+	household, err := h.HouseholdRepo.GetByID(mux.Vars(r)["id"])
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get household", err))
+		return nil
+	}
+	if household == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Household not found"))
+		return nil
+	}
+	return household
+}
+
+// membership loads the membership named in the path, writing a 404 if it
+// does not exist or is not of the household
+func (h *HouseholdHandler) membership(w http.ResponseWriter, r *http.Request, householdID string) *models.HouseholdMembership {
+	membership, err := h.HouseholdRepo.GetMembership(mux.Vars(r)["membershipId"])
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get household membership", err))
+		return nil
+	}
+	if membership == nil || membership.HouseholdID != householdID {
+		apierrors.Write(w, r, apierrors.NotFound("Household membership not found"))
+		return nil
+	}
+	return membership
+}
+
+// writeMembershipError writes the response for an error saving a household
+// or membership
+func writeMembershipError(w http.ResponseWriter, r *http.Request, message string, err error) {
+	switch {
+	case errors.Is(err, models.ErrHouseholdExists), errors.Is(err, models.ErrMembershipOverlap):
+		apierrors.Write(w, r, apierrors.Conflict(err.Error()))
+	default:
+		apierrors.Write(w, r, apierrors.Internal(message, err))
+	}
+}
+
+// CreateHousehold handles POST /api/v1/households
+// @Summary Create a shared household
+// @Description Create a household shared by applicants of the same family, headed by the given applicant. The head's household members are the household's, so other applicants who join it need not declare them again. An applicant heads at most one household and cannot head one while belonging to another.
+// @Tags applicants
+// @Accept json
+// @Produce json
+// @Param household body models.HouseholdRequest true "Household"
+// @Success 201 {object} models.Household
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 409 {object} apierrors.APIError "The applicant already heads or belongs to a household"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/households [post]
+func (h *HouseholdHandler) CreateHousehold(w http.ResponseWriter, r *http.Request) {
+	var request models.HouseholdRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
+		return
+	}
+	if request.HeadApplicantID == "" {
+		apierrors.Write(w, r, validationError(validation.Errors{"head_applicant_id": "is required"}))
+		return
+	}
+	head, err := h.ApplicantRepo.GetByID(request.HeadApplicantID)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applicant", err))
+		return
+	}
+	if head == nil {
+		apierrors.Write(w, r, validationError(validation.Errors{"head_applicant_id": "applicant not found"}))
+		return
+	}
+
+	actor := actorFrom(r)
+	household := models.Household{HeadApplicantID: head.ID, CreatedBy: actor.ID}
+	err = models.WithTx(h.HouseholdRepo.DB, func(tx *sql.Tx) error {
+		if err := h.HouseholdRepo.WithTx(tx).Create(&household); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityHousehold, household.ID,
+			models.AuditActionCreate, actor, nil, &household)
+	})
+	if err != nil {
+		writeMembershipError(w, r, "Failed to create household", err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusCreated, household)
+}
+
+// GetHousehold handles GET /api/v1/households/{id}
+// @Summary Get a shared household
+// @Description Retrieve a household with the memberships of the applicants other than its head, present and past, by start date. Its members are the head's household members; see GET /api/v1/applicants/{id}/household for the household an applicant is assessed against.
+// @Tags applicants
+// @Produce json
+// @Param id path string true "Household ID"
+// @Success 200 {object} models.Household
+// @Failure 404 {object} apierrors.APIError "Household not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/households/{id} [get]
+func (h *HouseholdHandler) GetHousehold(w http.ResponseWriter, r *http.Request) {
+	household := h.household(w, r)
+	if household == nil {
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, household)
+}
+
+// DeleteHousehold handles DELETE /api/v1/households/{id}
+// @Summary Delete a shared household
+// @Description Delete a household and its memberships. Its applicants are assessed against their own household members again, including for past dates.
+// @Tags applicants
+// @Param id path string true "Household ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} apierrors.APIError "Household not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/households/{id} [delete]
+func (h *HouseholdHandler) DeleteHousehold(w http.ResponseWriter, r *http.Request) {
+	existing := h.household(w, r)
+	if existing == nil {
+		return
+	}
+
+	actor := actorFrom(r)
+	err := models.WithTx(h.HouseholdRepo.DB, func(tx *sql.Tx) error {
+		if err := h.HouseholdRepo.WithTx(tx).Delete(existing.ID); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityHousehold, existing.ID,
+			models.AuditActionDelete, actor, existing, nil)
+	})
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to delete household", err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodeMembership reads and validates the body of a request adding or
+// replacing a membership, writing the response if it is invalid
+func decodeMembership(w http.ResponseWriter, r *http.Request, adding bool) (*models.HouseholdMembershipRequest, bool) {
+	var request models.HouseholdMembershipRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
+		return nil, false
+	}
+	if err := validation.HouseholdMembershipRequest(&request, adding); err != nil {
+		apierrors.Write(w, r, validationError(err))
+		return nil, false
+	}
+	return &request, true
+}
+
+// CreateMembership handles POST /api/v1/households/{id}/memberships
+// @Summary Add an applicant to a shared household
+// @Description Record that an applicant belongs to a household from start_date, and until end_date if given, with their relation to its head. Meanwhile they are assessed for eligibility and benefits against the household, seen from their side, instead of their own household members. An applicant belongs to at most one household at a time, and cannot join one while heading another.
+// @Tags applicants
+// @Accept json
+// @Produce json
+// @Param id path string true "Household ID"
+// @Param membership body models.HouseholdMembershipRequest true "Membership"
+// @Success 201 {object} models.HouseholdMembership
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 404 {object} apierrors.APIError "Household not found"
+// @Failure 409 {object} apierrors.APIError "The applicant belongs to or heads a household during the period"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/households/{id}/memberships [post]
+func (h *HouseholdHandler) CreateMembership(w http.ResponseWriter, r *http.Request) {
+	household := h.household(w, r)
+	if household == nil {
+		return
+	}
+	request, ok := decodeMembership(w, r, true)
+	if !ok {
+		return
+	}
+	applicant, err := h.ApplicantRepo.GetByID(request.ApplicantID)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applicant", err))
+		return
+	}
+	if applicant == nil {
+		apierrors.Write(w, r, validationError(validation.Errors{"applicant_id": "applicant not found"}))
+		return
+	}
+
+	actor := actorFrom(r)
+	membership := models.HouseholdMembership{
+		HouseholdID: household.ID,
+		ApplicantID: applicant.ID,
+		Relation:    request.Relation,
+		StartDate:   request.StartDate,
+		EndDate:     request.EndDate,
+	}
+	err = models.WithTx(h.HouseholdRepo.DB, func(tx *sql.Tx) error {
+		if err := h.HouseholdRepo.WithTx(tx).CreateMembership(&membership); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityHouseholdMembership, membership.ID,
+			models.AuditActionCreate, actor, nil, &membership)
+	})
+	if err != nil {
+		writeMembershipError(w, r, "Failed to add applicant to household", err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusCreated, membership)
+}
+
+// UpdateMembership handles PUT /api/v1/households/{id}/memberships/{membershipId}
+// @Summary Update a household membership
+// @Description Replace the relation and dates of a membership, for example setting its end_date when the applicant leaves the household. Its applicant cannot be changed.
+// @Tags applicants
+// @Accept json
+// @Produce json
+// @Param id path string true "Household ID"
+// @Param membershipId path string true "Membership ID"
+// @Param membership body models.HouseholdMembershipRequest true "Membership"
+// @Success 200 {object} models.HouseholdMembership
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 404 {object} apierrors.APIError "Household or membership not found"
+// @Failure 409 {object} apierrors.APIError "The applicant belongs to or heads a household during the period"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/households/{id}/memberships/{membershipId} [put]
+func (h *HouseholdHandler) UpdateMembership(w http.ResponseWriter, r *http.Request) {
+	household := h.household(w, r)
+	if household == nil {
+		return
+	}
+	existing := h.membership(w, r, household.ID)
+	if existing == nil {
+		return
+	}
+	request, ok := decodeMembership(w, r, false)
+	if !ok {
+		return
+	}
+	if request.ApplicantID != "" && request.ApplicantID != existing.ApplicantID {
+		apierrors.Write(w, r, validationError(validation.Errors{"applicant_id": "cannot be changed"}))
+		return
+	}
+
+	actor := actorFrom(r)
+	membership := *existing
+	membership.Relation = request.Relation
+	membership.StartDate = request.StartDate
+	membership.EndDate = request.EndDate
+	err := models.WithTx(h.HouseholdRepo.DB, func(tx *sql.Tx) error {
+		if err := h.HouseholdRepo.WithTx(tx).UpdateMembership(&membership); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityHouseholdMembership, membership.ID,
+			models.AuditActionUpdate, actor, existing, &membership)
+	})
+	if err != nil {
+		writeMembershipError(w, r, "Failed to update household membership", err)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, membership)
+}
+
+// DeleteMembership handles DELETE /api/v1/households/{id}/memberships/{membershipId}
+// @Summary Delete a household membership
+// @Description Delete a membership recorded in error. When an applicant leaves a household, set the membership's end_date instead, so they are still assessed against the household for the time they belonged to it.
+// @Tags applicants
+// @Param id path string true "Household ID"
+// @Param membershipId path string true "Membership ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} apierrors.APIError "Household or membership not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/households/{id}/memberships/{membershipId} [delete]
+func (h *HouseholdHandler) DeleteMembership(w http.ResponseWriter, r *http.Request) {
+	household := h.household(w, r)
+	if household == nil {
+		return
+	}
+	existing := h.membership(w, r, household.ID)
+	if existing == nil {
+		return
+	}
+
+	actor := actorFrom(r)
+	err := models.WithTx(h.HouseholdRepo.DB, func(tx *sql.Tx) error {
+		if err := h.HouseholdRepo.WithTx(tx).DeleteMembership(existing); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityHouseholdMembership, existing.ID,
+			models.AuditActionDelete, actor, existing, nil)
+	})
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to delete household membership", err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetSharedHousehold handles GET /api/v1/applicants/{id}/household
+// @Summary Get the household an applicant is assessed against
+// @Description Get the household members an applicant is assessed against for eligibility and benefits at as_of (default now). For an applicant belonging to a shared household then, these are the household's head, the head's household members and the other applicants belonging to it, each with their relation to this applicant; relations without a counterpart, such as in-laws, are other. Otherwise they are the applicant's own household members.
+// @Tags applicants
+// @Produce json
+// @Param id path string true "Applicant ID"
+// @Param as_of query string false "Date to assess the household at (RFC3339 or YYYY-MM-DD); defaults to now"
+// @Success 200 {object} models.SharedHousehold
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 404 {object} apierrors.APIError "Applicant not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applicants/{id}/household [get]
+func (h *HouseholdHandler) GetSharedHousehold(w http.ResponseWriter, r *http.Request) {
+	asOf := time.Now()
+	if value := r.URL.Query().Get("as_of"); value != "" {
+		t, err := parseTimeParam(value)
+		if err != nil {
+			apierrors.Write(w, r, apierrors.BadRequest("Invalid as_of").WithDetails(err.Error()))
+			return
+		}
+		asOf = t
+	}
+
+	applicant, err := h.ApplicantRepo.GetByID(mux.Vars(r)["id"])
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applicant", err))
+		return
+	}
+	if applicant == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Applicant not found"))
+		return
+	}
+
+	shared, err := h.HouseholdRepo.Shared(applicant, asOf)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get shared household", err))
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, shared)
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/integration"
+	"one-client-view-2025tht/app/models"
+)
+
+// IncomeHandler handles requests to retrieve applicants' assessed incomes
+// from an external source
+type IncomeHandler struct {
+	Provider       integration.IncomeProvider // Nil when income retrieval is not configured
+	IncomeRepo     *models.IncomeRepository
+	ApplicantRepo  *models.ApplicantRepository
+	ApplicantCache *models.CachedApplicantStore // Invalidated when an income is retrieved
+	ConsentRepo    *models.ConsentRepository
+	AuditRepo      *models.AuditRepository
+	WebhookRepo    *models.WebhookRepository
+}
+
+// NewIncomeHandler creates a new handler with the given provider, which may
+// be nil, and repositories
+func NewIncomeHandler(provider integration.IncomeProvider, incomeRepo *models.IncomeRepository, applicantRepo *models.ApplicantRepository, applicantCache *models.CachedApplicantStore, consentRepo *models.ConsentRepository, auditRepo *models.AuditRepository, webhookRepo *models.WebhookRepository) *IncomeHandler {
+	return &IncomeHandler{
+		Provider:       provider,
+		IncomeRepo:     incomeRepo,
+		ApplicantRepo:  applicantRepo,
+		ApplicantCache: applicantCache,
+		ConsentRepo:    consentRepo,
+		AuditRepo:      auditRepo,
+		WebhookRepo:    webhookRepo,
+	}
+}
+
+// GetIncomeRecords handles GET /api/v1/applicants/{id}/income
+// @Summary List the incomes retrieved for an applicant
+// @Description List the incomes retrieved for an applicant from external sources, newest first, each with its source, the year assessed, when and by whom it was retrieved, and the monthly_income it replaced
+// @Tags applicants
+// @Produce json
+// @Param id path string true "Applicant ID"
+// @Success 200 {array} models.IncomeRecord
+// @Failure 404 {object} apierrors.APIError "Applicant not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applicants/{id}/income [get]
+func (h *IncomeHandler) GetIncomeRecords(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	applicant, err := h.ApplicantCache.GetByID(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applicant", err))
+		return
+	}
+	if applicant == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Applicant not found"))
+		return
+	}
+
+	records, err := h.IncomeRepo.GetByApplicantID(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get income records", err))
+		return
+	}
+
+	writeList(w, r, records, 0)
+}
+
+// RefreshIncome handles POST /api/v1/applicants/{id}/income/refresh
+// @Summary Retrieve an applicant's assessed income
+// @Description Fetch the latest income assessed for an applicant from the configured source, such as IRAS, by their NRIC/FIN, and replace their monthly_income with it. The applicant must have an identity number and consent to income_verification. The retrieval is recorded with its source and date even when the income is unchanged, and a change to the applicant is audited like an update.
+// @Tags applicants
+// @Produce json
+// @Param id path string true "Applicant ID"
+// @Success 201 {object} models.IncomeRecord
+// @Failure 404 {object} apierrors.APIError "Applicant not found, or no income at the source"
+// @Failure 409 {object} apierrors.APIError "Applicant has no identity number, has not consented to income_verification, has been anonymized, or was changed meanwhile"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Failure 502 {object} apierrors.APIError "Source request failed"
+// @Failure 503 {object} apierrors.APIError "Income retrieval not configured"
+// @Security BearerAuth
+// @Router /api/v1/applicants/{id}/income/refresh [post]
+func (h *IncomeHandler) RefreshIncome(w http.ResponseWriter, r *http.Request) {
+	if h.Provider == nil {
+		apierrors.Write(w, r, apierrors.ServiceUnavailable("Income retrieval is not configured"))
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	existing, err := h.ApplicantRepo.GetByID(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applicant", err))
+		return
+	}
+	if existing == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Applicant not found"))
+		return
+	}
+	if existing.AnonymizedAt != nil {
+		apierrors.Write(w, r, anonymizedApplicant())
+		return
+	}
+	if existing.IdentityNumber == "" {
+		apierrors.Write(w, r, apierrors.Conflict("Applicant has no identity number").
+			WithDetails("incomes are retrieved by NRIC/FIN; record the applicant's identity_number first"))
+		return
+	}
+
+	now := time.Now()
+	consents, err := h.ConsentRepo.GetByApplicantID(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get consents", err))
+		return
+	}
+	if !models.Consented(consents, models.ConsentIncomeVerification, now) {
+		apierrors.Write(w, r, apierrors.Conflict("Applicant has not consented to income verification").
+			WithDetails("record the applicant's consent to "+models.ConsentIncomeVerification+" first"))
+		return
+	}
+
+	assessment, err := h.Provider.Income(r.Context(), existing.IdentityNumber)
+	if errors.Is(err, integration.ErrNotFound) {
+		apierrors.Write(w, r, apierrors.NotFound("Income not found in "+h.Provider.Name()))
+		return
+	}
+	if err != nil {
+		apierrors.Write(w, r, apierrors.BadGateway("Failed to get income from "+h.Provider.Name(), err))
+		return
+	}
+
+	actor := actorFrom(r)
+	record := models.IncomeRecord{
+		ApplicantID:           id,
+		Source:                h.Provider.Name(),
+		MonthlyIncome:         assessment.MonthlyIncome,
+		PreviousMonthlyIncome: existing.MonthlyIncome,
+		Reference:             assessment.Reference,
+		RetrievedBy:           actor.ID,
+		RetrievedAt:           now,
+	}
+	if assessment.AssessmentYear != 0 {
+		record.AssessmentYear = &assessment.AssessmentYear
+	}
+
+	applicant := *existing
+	applicant.MonthlyIncome = assessment.MonthlyIncome
+	err = models.WithTx(h.ApplicantRepo.DB, func(tx *sql.Tx) error {
+		if err := h.IncomeRepo.WithTx(tx).Create(&record); err != nil {
+			return err
+		}
+		if applicant.MonthlyIncome == existing.MonthlyIncome {
+			return nil
+		}
+		if err := h.ApplicantRepo.WithTx(tx).Update(&applicant); err != nil {
+			return err
+		}
+		if err := h.AuditRepo.WithTx(tx).Record(models.AuditEntityApplicant, id,
+			models.AuditActionUpdate, actor, existing, &applicant); err != nil {
+			return err
+		}
+		return h.WebhookRepo.WithTx(tx).Enqueue(models.EventApplicantUpdated, &applicant)
+	})
+	if errors.Is(err, models.ErrVersionConflict) {
+		apierrors.Write(w, r, versionConflict())
+		return
+	}
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to save income", err))
+		return
+	}
+	h.ApplicantCache.Invalidate(id)
+
+	writeJSON(w, r, http.StatusCreated, record)
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/auth"
+	"one-client-view-2025tht/app/models"
+)
+
+// JobHandler handles HTTP requests for polling background jobs
+type JobHandler struct {
+	JobRepo *models.JobRepository
+}
+
+// NewJobHandler creates a new handler with the given repository
+func NewJobHandler(jobRepo *models.JobRepository) *JobHandler {
+	return &JobHandler{JobRepo: jobRepo}
+}
+
+// GetJob handles GET /api/v1/jobs/{id}
+// @Summary Get job status
+// @Description Poll a background job started by an endpoint that returned 202 Accepted. The result is set once the job has succeeded; failed attempts are retried with backoff and last_error holds the latest failure. Users see only their own jobs; admins see all jobs.
+// @Tags jobs
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} models.Job
+// @Failure 404 {object} apierrors.APIError "Job not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/jobs/{id} [get]
+func (h *JobHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	job, apiErr := h.visibleJob(r)
+	if apiErr != nil {
+		apierrors.Write(w, r, apiErr)
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, job)
+}
+
+// RetryJob handles POST /api/v1/jobs/{id}/retry
+// @Summary Retry a failed job
+// @Description Queue a job that has failed for good to run again now, with its attempts reset
+// @Tags jobs
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 202 {object} models.Job
+// @Failure 404 {object} apierrors.APIError "Job not found"
+// @Failure 409 {object} apierrors.APIError "Job has not failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/jobs/{id}/retry [post]
+func (h *JobHandler) RetryJob(w http.ResponseWriter, r *http.Request) {
+	job, apiErr := h.visibleJob(r)
+	if apiErr != nil {
+		apierrors.Write(w, r, apiErr)
+		return
+	}
+
+	retried, err := h.JobRepo.Retry(job.ID)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to retry job", err))
+		return
+	}
+	if !retried {
+		apierrors.Write(w, r, apierrors.Conflict("Only failed jobs can be retried"))
+		return
+	}
+
+	job, err = h.JobRepo.GetByID(job.ID)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get job", err))
+		return
+	}
+	writeJobAccepted(w, r, job)
+}
+
+// visibleJob loads the job named in the path, if the authenticated user
+// created it or is an admin. Other users' jobs are reported as not found.
+func (h *JobHandler) visibleJob(r *http.Request) (*models.Job, *apierrors.APIError) {
+	job, err := h.JobRepo.GetByID(mux.Vars(r)["id"])
+	if err != nil {
+		return nil, apierrors.Internal("Failed to get job", err)
+	}
+	if job == nil || (job.CreatedBy != actorFrom(r).ID && !hasRole(r, auth.RoleAdmin)) {
+		return nil, apierrors.NotFound("Job not found")
+	}
+	return job, nil
+}
+
+// writeJobAccepted responds 202 Accepted with a queued job, whose status can
+// be polled at the Location
+func writeJobAccepted(w http.ResponseWriter, r *http.Request, job *models.Job) {
+	w.Header().Set("Location", "/api/v1/jobs/"+job.ID)
+	writeJSON(w, r, http.StatusAccepted, job)
+}
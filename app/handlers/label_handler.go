@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+
+	"one-client-view-2025tht/app/i18n"
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/validation"
+)
+
+// labelled lists the values of each enumeration served with labels
+var labelled = map[string][]string{
+	i18n.LabelApplicationStatus: validation.ApplicationStatuses,
+	i18n.LabelEmploymentStatus:  validation.EmploymentStatuses,
+	i18n.LabelSex:               validation.Sexes,
+	i18n.LabelMaritalStatus:     validation.MaritalStatuses,
+	i18n.LabelRelation:          models.Relations,
+	i18n.LabelSchoolLevel:       validation.SchoolLevels,
+	i18n.LabelConsentPurpose:    models.ConsentPurposes,
+	i18n.LabelBenefitFrequency:  models.BenefitFrequencies,
+	i18n.LabelReferralOutcome:   models.ReferralOutcomes,
+	i18n.LabelSchemeStatus:      models.SchemeStatuses,
+}
+
+// LabelHandler serves the labels of enumerated values, for frontends to
+// display in their users' language
+type LabelHandler struct{}
+
+// NewLabelHandler creates a new handler
+func NewLabelHandler() *LabelHandler {
+	return &LabelHandler{}
+}
+
+// GetLabels handles GET /api/v1/labels
+// @Summary Get labels of enumerated values
+// @Description Retrieve the labels of the values of application_status, employment_status, sex, marital_status, relation, school_level, consent_purpose, benefit_frequency, referral_outcome and scheme_status, in the language preferred by Accept-Language: English (en), Chinese (zh), Malay (ms) or Tamil (ta). Values are listed in the order the API documents them.
+// @Tags labels
+// @Produce json
+// @Param Accept-Language header string false "Preferred languages: en, zh, ms or ta"
+// @Success 200 {object} map[string][]models.Label
+// @Router /api/v1/labels [get]
+func (h *LabelHandler) GetLabels(w http.ResponseWriter, r *http.Request) {
+	lang := i18n.FromRequest(r)
+
+	response := make(map[string][]models.Label, len(labelled))
+	for enumeration, values := range labelled {
+		labels := make([]models.Label, len(values))
+		for i, value := range values {
+			labels[i] = models.Label{Value: value, Label: i18n.Label(lang, enumeration, value)}
+		}
+		response[enumeration] = labels
+	}
+
+	w.Header().Set("Content-Language", lang)
+	w.Header().Add("Vary", "Accept-Language")
+	writeJSON(w, r, http.StatusOK, response)
+}
@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/auth"
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/notify"
+	"one-client-view-2025tht/app/validation"
+)
+
+// portalCodeInterval is how soon after a code was sent another can be
+// requested, so that requests cannot flood an applicant with messages
+const portalCodeInterval = time.Minute
+
+// PortalHandler handles applicants' sign-in to the portal with one-time codes
+type PortalHandler struct {
+	ApplicantRepo *models.ApplicantRepository
+	OTPRepo       *models.ApplicantOTPRepository
+	Tokens        *auth.TokenManager // Issues applicants' tokens, with the portal's lifetime
+	Notifier      *notify.Notifier
+	CodeExpiry    time.Duration
+	MaxAttempts   int           // Codes entered before a code stops working
+	MaxFailures   int           // Sign-in attempts without success before sign-in is locked
+	LockoutWindow time.Duration // How long after the first attempt counted the count resets
+}
+
+// NewPortalHandler creates a new handler with the given repositories, token
+// manager and notifier
+func NewPortalHandler(applicantRepo *models.ApplicantRepository, otpRepo *models.ApplicantOTPRepository, tokens *auth.TokenManager, notifier *notify.Notifier, codeExpiry time.Duration, maxAttempts, maxFailures int, lockoutWindow time.Duration) *PortalHandler {
+	return &PortalHandler{
+		ApplicantRepo: applicantRepo,
+		OTPRepo:       otpRepo,
+		Tokens:        tokens,
+		Notifier:      notifier,
+		CodeExpiry:    codeExpiry,
+		MaxAttempts:   maxAttempts,
+		MaxFailures:   maxFailures,
+		LockoutWindow: lockoutWindow,
+	}
+}
+
+// RequestCode handles POST /api/v1/portal/code
+// @Summary Request a portal sign-in code
+// @Description Send a one-time code to the applicant with the given NRIC or FIN, by email or text message to the phone on record, to sign in to the portal with. The response is the same whether or not the applicant exists and has an email address or phone, so that it does not tell who has applied. A new code replaces the previous one, and no code is sent within a minute of the last.
+// @Tags portal
+// @Accept json
+// @Produce json
+// @Param request body models.PortalCodeRequest true "Applicant and channel"
+// @Success 202 "Accepted"
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Router /api/v1/portal/code [post]
+func (h *PortalHandler) RequestCode(w http.ResponseWriter, r *http.Request) {
+	var request models.PortalCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
+		return
+	}
+	if err := validation.PortalCodeRequest(&request); err != nil {
+		apierrors.Write(w, r, validationError(err))
+		return
+	}
+
+	// Hash a code before looking the applicant up, so that the response time
+	// does not tell whether they exist
+	code, err := auth.NewOTP()
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to generate code", err))
+		return
+	}
+	hash, err := auth.HashPassword(code)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to generate code", err))
+		return
+	}
+
+	applicant, err := h.ApplicantRepo.GetByIdentityNumber(request.IdentityNumber)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applicant", err))
+		return
+	}
+	if applicant == nil || applicant.AnonymizedAt != nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	previous, err := h.OTPRepo.GetByApplicantID(applicant.ID)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get code", err))
+		return
+	}
+	if previous != nil && time.Since(previous.CreatedAt) < portalCodeInterval {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	otp := models.ApplicantOTP{
+		ApplicantID: applicant.ID,
+		Channel:     request.Channel,
+		CodeHash:    hash,
+		ExpiresAt:   time.Now().Add(h.CodeExpiry),
+	}
+	err = models.WithTx(h.OTPRepo.DB, func(tx *sql.Tx) error {
+		return h.OTPRepo.WithTx(tx).Replace(&otp)
+	})
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to save code", err))
+		return
+	}
+
+	h.Notifier.PortalCode(applicant, request.Channel, code, h.CodeExpiry)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// Login handles POST /api/v1/portal/login
+// @Summary Sign in to the portal
+// @Description Exchange a one-time code from POST /api/v1/portal/code for a bearer token that lets the applicant read their own record, profile, applications and eligible schemes, and nothing else. A code can be used once, and stops working once it expires or after too many wrong codes. Too many attempts without signing in lock the applicant's sign-in for a time, whatever code is entered.
+// @Tags portal
+// @Accept json
+// @Produce json
+// @Param credentials body models.PortalLoginRequest true "Applicant and code"
+// @Success 200 {object} models.PortalLoginResponse
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 401 {object} apierrors.APIError "Invalid or expired code"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Router /api/v1/portal/login [post]
+func (h *PortalHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var request models.PortalLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
+		return
+	}
+	if err := validation.PortalLoginRequest(&request); err != nil {
+		apierrors.Write(w, r, validationError(err))
+		return
+	}
+
+	applicant, err := h.ApplicantRepo.GetByIdentityNumber(request.IdentityNumber)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applicant", err))
+		return
+	}
+
+	// Attempts are counted against the applicant as well as the code, so
+	// that requesting new codes does not allow more than MaxFailures guesses
+	// within LockoutWindow. A locked applicant is treated as having no code.
+	var otp *models.ApplicantOTP
+	if applicant != nil {
+		err = h.OTPRepo.RecordLoginAttempt(applicant.ID, h.MaxFailures, h.LockoutWindow, time.Now())
+		if err != nil && !errors.Is(err, models.ErrPortalLocked) {
+			apierrors.Write(w, r, apierrors.Internal("Failed to save sign-in attempt", err))
+			return
+		}
+		if err == nil {
+			otp, err = h.OTPRepo.GetByApplicantID(applicant.ID)
+			if err != nil {
+				apierrors.Write(w, r, apierrors.Internal("Failed to get code", err))
+				return
+			}
+		}
+	}
+
+	// Each attempt is counted before the code is checked, and a code is used
+	// by deleting it, so concurrent requests can neither make more than
+	// MaxAttempts attempts nor use a code twice. A code is hashed even when
+	// there is none to check, and unknown, locked applicants and bad codes
+	// get the same response, so neither the response nor its timing tells
+	// who has a code.
+	valid := false
+	if otp == nil {
+		auth.CheckNoPassword(request.Code)
+	} else if err := h.OTPRepo.RecordAttempt(otp, h.MaxAttempts, time.Now()); errors.Is(err, models.ErrOTPUnavailable) {
+		auth.CheckNoPassword(request.Code)
+	} else if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to save code", err))
+		return
+	} else {
+		valid = auth.CheckPassword(otp.CodeHash, request.Code)
+	}
+	if valid {
+		if err := h.OTPRepo.Delete(otp.ID); errors.Is(err, models.ErrOTPUnavailable) {
+			valid = false
+		} else if err != nil {
+			apierrors.Write(w, r, apierrors.Internal("Failed to save code", err))
+			return
+		}
+	}
+	if !valid {
+		apierrors.Write(w, r, apierrors.Unauthorized("Invalid or expired code"))
+		return
+	}
+	if err := h.OTPRepo.ResetLoginAttempts(applicant.ID); err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to save sign-in attempt", err))
+		return
+	}
+
+	token, expiresAt, err := h.Tokens.Issue(applicant.ID, "", auth.RoleApplicant)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to issue token", err))
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, models.PortalLoginResponse{
+		Token:       token,
+		TokenType:   "Bearer",
+		ExpiresAt:   expiresAt,
+		ApplicantID: applicant.ID,
+	})
+}
+
+// ApplicantInPath is the owner of requests for the applicant with the ID in
+// the path
+func ApplicantInPath(r *http.Request) (string, error) {
+	return mux.Vars(r)["id"], nil
+}
+
+// ApplicantInQuery is the owner of requests naming an applicant in the
+// applicant query parameter
+func ApplicantInQuery(r *http.Request) (string, error) {
+	return r.URL.Query().Get("applicant"), nil
+}
+
+// ApplicationOwner returns the owner of requests for the application with
+// the ID in the path: its applicant
+func ApplicationOwner(applications *models.ApplicationRepository) func(r *http.Request) (string, error) {
+	return func(r *http.Request) (string, error) {
+		application, err := applications.GetByID(mux.Vars(r)["id"])
+		if err != nil || application == nil {
+			return "", err
+		}
+		return application.ApplicantID, nil
+	}
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"one-client-view-2025tht/app/auth"
+	"one-client-view-2025tht/app/database"
+	"one-client-view-2025tht/app/date"
+	"one-client-view-2025tht/app/encryption"
+	"one-client-view-2025tht/app/models"
+)
+
+func TestPortalLockoutSurvivesNewCodes(t *testing.T) {
+	db, err := database.Initialize(&database.Config{
+		Driver: database.DriverSQLite,
+		Path:   filepath.Join(t.TempDir(), "test.sqlite"),
+	})
+	if err != nil {
+		t.Fatalf("initializing database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	cipher, err := encryption.New([]byte(strings.Repeat("t", 32)))
+	if err != nil {
+		t.Fatalf("creating cipher: %v", err)
+	}
+	applicantRepo := models.NewApplicantRepository(db.DB, cipher)
+	applicant := &models.Applicant{
+		Name:             "Tan Ah Kow",
+		EmploymentStatus: "unemployed",
+		Sex:              "male",
+		DateOfBirth:      date.New(1970, 1, 1),
+		MaritalStatus:    "single",
+		IdentityNumber:   "S1234567D",
+		Email:            "tan@example.com",
+	}
+	if err := applicantRepo.Create(applicant); err != nil {
+		t.Fatalf("creating applicant: %v", err)
+	}
+
+	const maxAttempts = 5
+	h := NewPortalHandler(applicantRepo, models.NewApplicantOTPRepository(db.DB),
+		auth.NewTokenManager([]byte(strings.Repeat("k", 32)), time.Minute), nil,
+		10*time.Minute, maxAttempts, maxAttempts, time.Hour)
+
+	// requestCode requests a new code and sets it to a known one, sent long
+	// enough ago that another can be requested straight away
+	const code = "123456"
+	hash, err := auth.HashPassword(code)
+	if err != nil {
+		t.Fatalf("hashing code: %v", err)
+	}
+	requestCode := func() {
+		t.Helper()
+		w := httptest.NewRecorder()
+		h.RequestCode(w, httptest.NewRequest("POST", "/api/v1/portal/code",
+			strings.NewReader(`{"identity_number": "S1234567D", "channel": "email"}`)))
+		if w.Code != http.StatusAccepted {
+			t.Fatalf("requesting code: status %d: %s", w.Code, w.Body)
+		}
+		_, err := db.DB.Exec(`UPDATE applicant_otps SET code_hash = ?, created_at = ? WHERE applicant_id = ?`,
+			hash, time.Now().Add(-portalCodeInterval), applicant.ID)
+		if err != nil {
+			t.Fatalf("setting code: %v", err)
+		}
+	}
+	login := func(code string) int {
+		t.Helper()
+		w := httptest.NewRecorder()
+		h.Login(w, httptest.NewRequest("POST", "/api/v1/portal/login",
+			strings.NewReader(`{"identity_number": "S1234567D", "code": "`+code+`"}`)))
+		return w.Code
+	}
+
+	requestCode()
+	if status := login(code); status != http.StatusOK {
+		t.Fatalf("signing in: status %d, want %d", status, http.StatusOK)
+	}
+
+	requestCode()
+	for i := 0; i < maxAttempts; i++ {
+		if status := login("000000"); status != http.StatusUnauthorized {
+			t.Fatalf("wrong code %d: status %d, want %d", i+1, status, http.StatusUnauthorized)
+		}
+	}
+
+	// A new code does not allow more guesses, not even the right one
+	requestCode()
+	if status := login(code); status != http.StatusUnauthorized {
+		t.Errorf("signing in with a new code while locked: status %d, want %d", status, http.StatusUnauthorized)
+	}
+
+	// Once the window has passed, the code works again
+	if _, err := db.DB.Exec(`UPDATE applicants SET portal_failures_reset_at = ? WHERE id = ?`,
+		time.Now().Add(-time.Second), applicant.ID); err != nil {
+		t.Fatalf("ending lockout: %v", err)
+	}
+	if status := login(code); status != http.StatusOK {
+		t.Errorf("signing in after the lockout: status %d, want %d", status, http.StatusOK)
+	}
+}
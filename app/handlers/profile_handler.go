@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/auth"
+	"one-client-view-2025tht/app/models"
+)
+
+// ProfileHandler serves the one-client view, gathering everything known
+// about an applicant into one response
+type ProfileHandler struct {
+	ApplicantCache  *models.CachedApplicantStore
+	ApplicationRepo *models.ApplicationRepository
+	SchemeCache     *models.CachedSchemeStore // Serves eligibility checks
+	CaseNoteRepo    *models.CaseNoteRepository
+	DocumentRepo    *models.DocumentRepository
+	ConsentRepo     *models.ConsentRepository // Decides which contact details are shared
+	ReferralRepo    *models.ReferralRepository
+	TranslationRepo *models.SchemeTranslationRepository
+	EventRepo       *models.ApplicationEventRepository // Serves the status changes in timelines
+	TaskRepo        *models.TaskRepository
+	AuditRepo       *models.AuditRepository     // Serves the changes to applicants in timelines
+	HouseholdRepo   *models.HouseholdRepository // Resolves the shared households applicants are assessed against
+}
+
+// NewProfileHandler creates a new handler with the given repositories
+func NewProfileHandler(applicantCache *models.CachedApplicantStore, appRepo *models.ApplicationRepository, schemeCache *models.CachedSchemeStore, caseNoteRepo *models.CaseNoteRepository, documentRepo *models.DocumentRepository, consentRepo *models.ConsentRepository, referralRepo *models.ReferralRepository, translationRepo *models.SchemeTranslationRepository, eventRepo *models.ApplicationEventRepository, taskRepo *models.TaskRepository, auditRepo *models.AuditRepository, householdRepo *models.HouseholdRepository) *ProfileHandler {
+	return &ProfileHandler{
+		ApplicantCache:  applicantCache,
+		ApplicationRepo: appRepo,
+		SchemeCache:     schemeCache,
+		CaseNoteRepo:    caseNoteRepo,
+		DocumentRepo:    documentRepo,
+		ConsentRepo:     consentRepo,
+		ReferralRepo:    referralRepo,
+		TranslationRepo: translationRepo,
+		EventRepo:       eventRepo,
+		TaskRepo:        taskRepo,
+		AuditRepo:       auditRepo,
+		HouseholdRepo:   householdRepo,
+	}
+}
+
+// GetApplicantProfile handles GET /api/v1/applicants/{id}/profile
+// @Summary Get an applicant's profile
+// @Description Retrieve everything known about an applicant in one response: the applicant and household, all applications with their schemes and documents, the schemes the applicant is eligible for at as_of (default now), the case notes, the consents and the referrals to other agencies, newest first. Scheme names and descriptions are served in the language preferred by Accept-Language where the scheme has been translated into it. The email, phone and address apart from its postal district are only included while the applicant consents to being contacted that way (contact_email, contact_phone and contact_post). Applicants signed in to the portal get their own profile without case notes. The number of queries does not grow with the number of applications.
+// @Tags applicants
+// @Produce json
+// @Param id path string true "Applicant ID"
+// @Param as_of query string false "Date or RFC3339 time to assess eligibility at (default now)"
+// @Param view query string false "full (the default) or minimal, which masks identity numbers, dates of birth and phone numbers, reduces addresses to their postal district and omits household members and the text of case notes; viewers always get minimal" Enums(full, minimal)
+// @Param fields query string false "Comma-separated fields to return, such as id,name,household.name; all if omitted"
+// @Param Accept-Language header string false "Preferred languages: en, zh, ms or ta"
+// @Success 200 {object} models.SwaggerApplicantProfile
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 404 {object} apierrors.APIError "Applicant not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applicants/{id}/profile [get]
+func (h *ProfileHandler) GetApplicantProfile(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	asOf := time.Now()
+	if value := r.URL.Query().Get("as_of"); value != "" {
+		t, err := parseTimeParam(value)
+		if err != nil {
+			apierrors.Write(w, r, apierrors.BadRequest("Invalid as_of").WithDetails(err.Error()))
+			return
+		}
+		asOf = t
+	}
+
+	applicant, err := h.ApplicantCache.GetByID(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applicant", err))
+		return
+	}
+	if applicant == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Applicant not found"))
+		return
+	}
+
+	applications, err := h.ApplicationRepo.GetByApplicantID(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applications", err))
+		return
+	}
+
+	applicationIDs := make([]string, len(applications))
+	for i := range applications {
+		applicationIDs[i] = applications[i].ID
+	}
+	documents, err := h.DocumentRepo.GetByApplicationIDs(applicationIDs)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get documents", err))
+		return
+	}
+
+	// Eligibility is assessed against the shared household the applicant
+	// belongs to at as_of, if any, while the profile shows their own
+	assessed, err := h.HouseholdRepo.Resolve(applicant, asOf)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get shared household", err))
+		return
+	}
+	schemes, err := models.EligibleSchemes(h.SchemeCache, assessed, asOf)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get eligible schemes", err))
+		return
+	}
+
+	// Case notes are kept among staff, so applicants viewing their own
+	// profile in the portal do not get them
+	var notes []models.CaseNote
+	if !hasRole(r, auth.RoleApplicant) {
+		notes, err = h.CaseNoteRepo.GetByApplicantID(id, "")
+		if err != nil {
+			apierrors.Write(w, r, apierrors.Internal("Failed to get case notes", err))
+			return
+		}
+	}
+
+	consents, err := h.ConsentRepo.GetByApplicantID(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get consents", err))
+		return
+	}
+
+	referrals, err := h.ReferralRepo.GetByApplicantID(id, "")
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get referrals", err))
+		return
+	}
+
+	// Lists are always present, so clients need not distinguish missing
+	// from empty
+	shareable := models.ShareableApplicant(*applicant, consents, time.Now())
+	profile := models.ApplicantProfile{
+		Applicant: models.ApplicantResponse{
+			Applicant: shareable,
+			Household: shareable.Household,
+		},
+		Applications:    []models.ProfileApplication{},
+		EligibleSchemes: []models.SchemeResponse{},
+		CaseNotes:       notes,
+		Consents:        consents,
+		Referrals:       referrals,
+		AsOf:            asOf,
+	}
+	if profile.CaseNotes == nil {
+		profile.CaseNotes = []models.CaseNote{}
+	}
+	if profile.Consents == nil {
+		profile.Consents = []models.Consent{}
+	}
+	if profile.Referrals == nil {
+		profile.Referrals = []models.Referral{}
+	}
+
+	// Translate the schemes applied for and those eligible for together
+	var applied []models.Application
+	var localized []models.Scheme
+	for _, a := range applications {
+		if a.Scheme == nil {
+			continue // Skip invalid applications
+		}
+		applied = append(applied, a)
+		localized = append(localized, *a.Scheme)
+	}
+	localized = append(localized, schemes...)
+	if err := localizeSchemes(w, r, h.TranslationRepo, localized); err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get scheme translations", err))
+		return
+	}
+
+	for i, a := range applied {
+		application := models.ProfileApplication{
+			Application: a,
+			Scheme: models.SchemeResponse{
+				Scheme:   localized[i],
+				Benefits: a.Scheme.Benefits,
+			},
+			Documents: documents[a.ID],
+		}
+		if application.Documents == nil {
+			application.Documents = []models.Document{}
+		}
+		profile.Applications = append(profile.Applications, application)
+	}
+
+	for _, s := range localized[len(applied):] {
+		profile.EligibleSchemes = append(profile.EligibleSchemes, models.SchemeResponse{
+			Scheme:   s,
+			Benefits: s.Benefits,
+		})
+	}
+
+	writeJSON(w, r, http.StatusOK, profile)
+}
@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/validation"
+)
+
+// ReferralHandler handles requests for the referrals of applicants to help
+// outside this system
+type ReferralHandler struct {
+	ReferralRepo  *models.ReferralRepository
+	ApplicantRepo *models.ApplicantRepository
+	AuditRepo     *models.AuditRepository
+}
+
+// NewReferralHandler creates a new handler with the given repositories
+func NewReferralHandler(referralRepo *models.ReferralRepository, applicantRepo *models.ApplicantRepository, auditRepo *models.AuditRepository) *ReferralHandler {
+	return &ReferralHandler{
+		ReferralRepo:  referralRepo,
+		ApplicantRepo: applicantRepo,
+		AuditRepo:     auditRepo,
+	}
+}
+
+// applicant loads the applicant named in the path, writing a 404 if it does
+// not exist
+func (h *ReferralHandler) applicant(w http.ResponseWriter, r *http.Request) *models.Applicant {
+	applicant, err := h.ApplicantRepo.GetByID(mux.Vars(r)["id"])
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applicant", err))
+		return nil
+	}
+	if applicant == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Applicant not found"))
+		return nil
+	}
+	return applicant
+}
+
+// referral loads the referral named in the path, writing a 404 if it does not
+// exist or is not the applicant's
+func (h *ReferralHandler) referral(w http.ResponseWriter, r *http.Request, applicantID string) *models.Referral {
+	referral, err := h.ReferralRepo.GetByID(mux.Vars(r)["referralId"])
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get referral", err))
+		return nil
+	}
+	if referral == nil || referral.ApplicantID != applicantID {
+		apierrors.Write(w, r, apierrors.NotFound("Referral not found"))
+		return nil
+	}
+	return referral
+}
+
+// GetReferrals handles GET /api/v1/applicants/{id}/referrals
+// @Summary List an applicant's referrals
+// @Description List the referrals of an applicant to help delivered outside this system, such as another agency's scheme, newest first.
+// @Tags applicants
+// @Produce json
+// @Param id path string true "Applicant ID"
+// @Param outcome query string false "Only referrals with this outcome" Enums(pending, accepted, declined, completed)
+// @Success 200 {array} models.Referral
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 404 {object} apierrors.APIError "Applicant not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applicants/{id}/referrals [get]
+func (h *ReferralHandler) GetReferrals(w http.ResponseWriter, r *http.Request) {
+	outcome := r.URL.Query().Get("outcome")
+	if outcome != "" && !slices.Contains(models.ReferralOutcomes, outcome) {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid outcome").
+			WithDetails("must be one of: "+strings.Join(models.ReferralOutcomes, ", ")))
+		return
+	}
+
+	applicant := h.applicant(w, r)
+	if applicant == nil {
+		return
+	}
+
+	referrals, err := h.ReferralRepo.GetByApplicantID(applicant.ID, outcome)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get referrals", err))
+		return
+	}
+
+	writeList(w, r, referrals, 0)
+}
+
+// GetReferral handles GET /api/v1/applicants/{id}/referrals/{referralId}
+// @Summary Get a referral
+// @Description Retrieve one of an applicant's referrals
+// @Tags applicants
+// @Produce json
+// @Param id path string true "Applicant ID"
+// @Param referralId path string true "Referral ID"
+// @Success 200 {object} models.Referral
+// @Failure 404 {object} apierrors.APIError "Applicant or referral not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applicants/{id}/referrals/{referralId} [get]
+func (h *ReferralHandler) GetReferral(w http.ResponseWriter, r *http.Request) {
+	applicant := h.applicant(w, r)
+	if applicant == nil {
+		return
+	}
+	referral := h.referral(w, r, applicant.ID)
+	if referral == nil {
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, referral)
+}
+
+// CreateReferral handles POST /api/v1/applicants/{id}/referrals
+// @Summary Record a referral
+// @Description Record that an applicant was referred to another agency, or to one of its schemes or services. referred_by defaults to the authenticated user's username, and the outcome to pending; outcome_at is set whenever the outcome is set to other than pending.
+// @Tags applicants
+// @Accept json
+// @Produce json
+// @Param id path string true "Applicant ID"
+// @Param referral body models.ReferralRequest true "Referral"
+// @Success 201 {object} models.Referral
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 404 {object} apierrors.APIError "Applicant not found"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applicants/{id}/referrals [post]
+func (h *ReferralHandler) CreateReferral(w http.ResponseWriter, r *http.Request) {
+	applicant := h.applicant(w, r)
+	if applicant == nil {
+		return
+	}
+
+	var request models.ReferralRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
+		return
+	}
+	if err := validation.ReferralRequest(&request); err != nil {
+		apierrors.Write(w, r, validationError(err))
+		return
+	}
+
+	actor := actorFrom(r)
+	referral := models.Referral{
+		ApplicantID: applicant.ID,
+		Outcome:     models.ReferralPending,
+		CreatedBy:   actor.ID,
+	}
+	applyReferral(&referral, &request, actor, time.Now())
+
+	err := models.WithTx(h.ReferralRepo.DB, func(tx *sql.Tx) error {
+		if err := h.ReferralRepo.WithTx(tx).Create(&referral); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityReferral, referral.ID,
+			models.AuditActionCreate, actor, nil, &referral)
+	})
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to create referral", err))
+		return
+	}
+
+	writeJSON(w, r, http.StatusCreated, referral)
+}
+
+// UpdateReferral handles PUT /api/v1/applicants/{id}/referrals/{referralId}
+// @Summary Update a referral
+// @Description Replace a referral, for example to record its outcome. Omitted fields take their defaults, as when recording one.
+// @Tags applicants
+// @Accept json
+// @Produce json
+// @Param id path string true "Applicant ID"
+// @Param referralId path string true "Referral ID"
+// @Param referral body models.ReferralRequest true "Referral"
+// @Success 200 {object} models.Referral
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 404 {object} apierrors.APIError "Applicant or referral not found"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applicants/{id}/referrals/{referralId} [put]
+func (h *ReferralHandler) UpdateReferral(w http.ResponseWriter, r *http.Request) {
+	applicant := h.applicant(w, r)
+	if applicant == nil {
+		return
+	}
+	existing := h.referral(w, r, applicant.ID)
+	if existing == nil {
+		return
+	}
+
+	var request models.ReferralRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
+		return
+	}
+	if err := validation.ReferralRequest(&request); err != nil {
+		apierrors.Write(w, r, validationError(err))
+		return
+	}
+
+	actor := actorFrom(r)
+	referral := *existing
+	applyReferral(&referral, &request, actor, time.Now())
+
+	err := models.WithTx(h.ReferralRepo.DB, func(tx *sql.Tx) error {
+		if err := h.ReferralRepo.WithTx(tx).Update(&referral); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityReferral, referral.ID,
+			models.AuditActionUpdate, actor, existing, &referral)
+	})
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to update referral", err))
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, referral)
+}
+
+// DeleteReferral handles DELETE /api/v1/applicants/{id}/referrals/{referralId}
+// @Summary Delete a referral
+// @Description Delete a referral recorded in error
+// @Tags applicants
+// @Param id path string true "Applicant ID"
+// @Param referralId path string true "Referral ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} apierrors.APIError "Applicant or referral not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applicants/{id}/referrals/{referralId} [delete]
+func (h *ReferralHandler) DeleteReferral(w http.ResponseWriter, r *http.Request) {
+	applicant := h.applicant(w, r)
+	if applicant == nil {
+		return
+	}
+	existing := h.referral(w, r, applicant.ID)
+	if existing == nil {
+		return
+	}
+
+	actor := actorFrom(r)
+	err := models.WithTx(h.ReferralRepo.DB, func(tx *sql.Tx) error {
+		if err := h.ReferralRepo.WithTx(tx).Delete(existing.ID); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityReferral, existing.ID,
+			models.AuditActionDelete, actor, existing, nil)
+	})
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to delete referral", err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// applyReferral sets a referral's fields from a request, defaulting
+// referred_by to the actor's username and the outcome to pending. outcome_at
+// records when the outcome changed to other than pending, and is cleared when
+// it returns to pending.
+func applyReferral(f *models.Referral, request *models.ReferralRequest, actor models.Actor, now time.Time) {
+	f.ReferredBy = request.ReferredBy
+	if f.ReferredBy == "" {
+		f.ReferredBy = actor.Username
+	}
+	f.ReferredTo = request.ReferredTo
+	f.Scheme = request.Scheme
+	f.Reason = request.Reason
+	f.OutcomeNotes = request.OutcomeNotes
+
+	outcome := request.Outcome
+	if outcome == "" {
+		outcome = models.ReferralPending
+	}
+	switch {
+	case outcome == models.ReferralPending:
+		f.OutcomeAt = nil
+	case outcome != f.Outcome || f.OutcomeAt == nil:
+		f.OutcomeAt = &now
+	}
+	f.Outcome = outcome
+}
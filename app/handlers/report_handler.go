@@ -0,0 +1,353 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/jobs"
+	"one-client-view-2025tht/app/models"
+)
+
+// ReportHandler handles HTTP requests for aggregate statistics
+type ReportHandler struct {
+	ReportRepo     *models.ReportRepository
+	SchemeRepo     *models.SchemeRepository
+	SchemeCache    *models.CachedSchemeStore    // Serves scheme versions for coverage reports
+	ApplicantCache *models.CachedApplicantStore // Its repository lists applicants for coverage reports
+	JobRepo        *models.JobRepository
+	HouseholdRepo  *models.HouseholdRepository // Resolves the shared households applicants are assessed against
+}
+
+// NewReportHandler creates a new handler with the given repositories
+func NewReportHandler(reportRepo *models.ReportRepository, schemeRepo *models.SchemeRepository, schemeCache *models.CachedSchemeStore, applicantCache *models.CachedApplicantStore, jobRepo *models.JobRepository, householdRepo *models.HouseholdRepository) *ReportHandler {
+	return &ReportHandler{
+		ReportRepo:     reportRepo,
+		SchemeRepo:     schemeRepo,
+		SchemeCache:    schemeCache,
+		ApplicantCache: applicantCache,
+		JobRepo:        jobRepo,
+		HouseholdRepo:  householdRepo,
+	}
+}
+
+// GetApplicationsSummary handles GET /api/v1/reports/applications-summary
+// @Summary Get application statistics
+// @Description Count applications by status, scheme and month of application, with the average time to decision and total benefit amounts of approved applications. Accepts the same filters as listing applications.
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param status query string false "Only applications with this status" Enums(pending, approved, rejected, withdrawn)
+// @Param scheme_id query string false "Only applications for this scheme"
+// @Param applicant_id query string false "Only applications by this applicant"
+// @Param applied_after query string false "Only applications made on or after this date (YYYY-MM-DD)"
+// @Param applied_before query string false "Only applications made before this date (YYYY-MM-DD)"
+// @Param assigned_to query string false "User ID the applications are assigned to, me for the authenticated user, or none for unassigned applications"
+// @Param include_deleted query bool false "Include soft-deleted applications (admin only)"
+// @Success 200 {object} models.ApplicationsSummary
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 403 {object} apierrors.APIError "Forbidden"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/reports/applications-summary [get]
+func (h *ReportHandler) GetApplicationsSummary(w http.ResponseWriter, r *http.Request) {
+	filter, apiErr := applicationFilterParams(r)
+	if apiErr != nil {
+		apierrors.Write(w, r, apiErr)
+		return
+	}
+
+	summary, err := h.ReportRepo.ApplicationsSummary(filter)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get application statistics", err))
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, summary)
+}
+
+// QueueApplicationsSummary handles POST /api/v1/reports/applications-summary/jobs
+// @Summary Generate application statistics in the background
+// @Description Queue a job computing the same statistics as GET /api/v1/reports/applications-summary, for large date ranges. Poll the returned job for the outcome.
+// @Tags reports
+// @Produce json
+// @Param status query string false "Only applications with this status" Enums(pending, approved, rejected, withdrawn)
+// @Param scheme_id query string false "Only applications for this scheme"
+// @Param applicant_id query string false "Only applications by this applicant"
+// @Param applied_after query string false "Only applications made on or after this date (YYYY-MM-DD)"
+// @Param applied_before query string false "Only applications made before this date (YYYY-MM-DD)"
+// @Param assigned_to query string false "User ID the applications are assigned to, me for the authenticated user, or none for unassigned applications"
+// @Param include_deleted query bool false "Include soft-deleted applications (admin only)"
+// @Success 202 {object} models.Job "Queued; the result is a models.ApplicationsSummary"
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 403 {object} apierrors.APIError "Forbidden"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/reports/applications-summary/jobs [post]
+func (h *ReportHandler) QueueApplicationsSummary(w http.ResponseWriter, r *http.Request) {
+	filter, apiErr := applicationFilterParams(r)
+	if apiErr != nil {
+		apierrors.Write(w, r, apiErr)
+		return
+	}
+
+	job, err := h.JobRepo.Enqueue(models.JobApplicationsReport, filter, actorFrom(r).ID)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to queue report", err))
+		return
+	}
+
+	writeJobAccepted(w, r, job)
+}
+
+// RunApplicationsSummaryJob is the handler of reports.applications_summary jobs
+func (h *ReportHandler) RunApplicationsSummaryJob(ctx context.Context, job *models.Job) (interface{}, error) {
+	var filter models.ApplicationFilter
+	if err := json.Unmarshal(job.Payload, &filter); err != nil {
+		return nil, jobs.Permanent(fmt.Errorf("invalid payload: %v", err))
+	}
+	return h.ReportRepo.ApplicationsSummary(filter)
+}
+
+// GetWorkload handles GET /api/v1/reports/workload
+// @Summary Get caseworker workload
+// @Description Count, for every caseworker and admin, the pending applications assigned to them and the applications they approved and rejected, with the number of pending applications assigned to no one. Accepts the filters of listing applications other than status and assigned_to.
+// @Tags reports
+// @Produce json
+// @Param scheme_id query string false "Only applications for this scheme"
+// @Param applicant_id query string false "Only applications by this applicant"
+// @Param applied_after query string false "Only applications made on or after this date (YYYY-MM-DD)"
+// @Param applied_before query string false "Only applications made before this date (YYYY-MM-DD)"
+// @Param include_deleted query bool false "Include soft-deleted applications (admin only)"
+// @Success 200 {object} models.WorkloadReport
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 403 {object} apierrors.APIError "Forbidden"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/reports/workload [get]
+func (h *ReportHandler) GetWorkload(w http.ResponseWriter, r *http.Request) {
+	filter, apiErr := applicationFilterParams(r)
+	if apiErr != nil {
+		apierrors.Write(w, r, apiErr)
+		return
+	}
+	// Each count is of its own status and assignee
+	filter.Status = ""
+	filter.AssignedTo = ""
+	filter.Unassigned = false
+
+	report, err := h.ReportRepo.Workload(filter)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get workload", err))
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, report)
+}
+
+// GetGeographicReport handles GET /api/v1/reports/geographic
+// @Summary Get applications by area
+// @Description Count applications, approvals and applicants by the postal district of the applicant's address, or by the planning region most of the district lies in, so planners can see where demand concentrates. Every district or region is listed, with the approximate centre to plot it at; applications by applicants without an address are counted as unlocated. Applicants are placed by their current address. With format=geojson the report is a GeoJSON FeatureCollection of points, one per area with its counts as properties, for mapping tools. Accepts the same filters as listing applications.
+// @Tags reports
+// @Produce json
+// @Produce application/geo+json
+// @Param group_by query string false "Group by postal district or planning region" Enums(district, region) default(district)
+// @Param format query string false "Response format" Enums(json, geojson) default(json)
+// @Param status query string false "Only applications with this status" Enums(pending, approved, rejected, withdrawn)
+// @Param scheme_id query string false "Only applications for this scheme"
+// @Param applied_after query string false "Only applications made on or after this date (YYYY-MM-DD)"
+// @Param applied_before query string false "Only applications made before this date (YYYY-MM-DD)"
+// @Param assigned_to query string false "User ID the applications are assigned to, me for the authenticated user, or none for unassigned applications"
+// @Param include_deleted query bool false "Include soft-deleted applications (admin only)"
+// @Success 200 {object} models.GeographicReport "The report, or with format=geojson a models.GeoJSONFeatureCollection"
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 403 {object} apierrors.APIError "Forbidden"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/reports/geographic [get]
+func (h *ReportHandler) GetGeographicReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	groupBy := query.Get("group_by")
+	if groupBy == "" {
+		groupBy = models.GroupByDistrict
+	}
+	if groupBy != models.GroupByDistrict && groupBy != models.GroupByRegion {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid group_by").
+			WithDetails("group_by must be one of: district, region"))
+		return
+	}
+	format := query.Get("format")
+	if format != "" && format != "json" && format != "geojson" {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid format").
+			WithDetails("format must be one of: json, geojson"))
+		return
+	}
+
+	filter, apiErr := applicationFilterParams(r)
+	if apiErr != nil {
+		apierrors.Write(w, r, apiErr)
+		return
+	}
+
+	report, err := h.ReportRepo.Geographic(filter, groupBy)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get geographic report", err))
+		return
+	}
+
+	if format == "geojson" {
+		w.Header().Set("Content-Type", "application/geo+json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(report.FeatureCollection())
+		return
+	}
+	writeJSON(w, r, http.StatusOK, report)
+}
+
+// GetSchemeReport handles GET /api/v1/reports/schemes/{id}
+// @Summary Get application statistics for a scheme
+// @Description Count a scheme's applications by status and month of application, with the average time to decision and total benefit amounts of approved applications
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param id path string true "Scheme ID"
+// @Param status query string false "Only applications with this status" Enums(pending, approved, rejected, withdrawn)
+// @Param applicant_id query string false "Only applications by this applicant"
+// @Param applied_after query string false "Only applications made on or after this date (YYYY-MM-DD)"
+// @Param applied_before query string false "Only applications made before this date (YYYY-MM-DD)"
+// @Param assigned_to query string false "User ID the applications are assigned to, me for the authenticated user, or none for unassigned applications"
+// @Param include_deleted query bool false "Include soft-deleted applications (admin only)"
+// @Success 200 {object} models.SchemeReport
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 403 {object} apierrors.APIError "Forbidden"
+// @Failure 404 {object} apierrors.APIError "Scheme not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/reports/schemes/{id} [get]
+func (h *ReportHandler) GetSchemeReport(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	filter, apiErr := applicationFilterParams(r)
+	if apiErr != nil {
+		apierrors.Write(w, r, apiErr)
+		return
+	}
+
+	scheme, err := h.SchemeRepo.GetByID(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get scheme", err))
+		return
+	}
+	if scheme == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Scheme not found"))
+		return
+	}
+
+	report, err := h.ReportRepo.SchemeReport(scheme, filter)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get scheme statistics", err))
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, report)
+}
+
+// GetSchemeCoverage handles GET /api/v1/reports/schemes/{id}/coverage
+// @Summary Get eligibility coverage for a scheme
+// @Description Compare the registered applicants who meet a scheme's criteria at as_of, as assessed by the batch eligibility check, with those who have applied for it, to find under-utilized schemes
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param id path string true "Scheme ID"
+// @Param as_of query string false "Time to assess eligibility at (RFC3339 or YYYY-MM-DD); now if omitted"
+// @Success 200 {object} models.SchemeCoverage
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 404 {object} apierrors.APIError "Scheme not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/reports/schemes/{id}/coverage [get]
+func (h *ReportHandler) GetSchemeCoverage(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	asOf, err := parseTimeParam(r.URL.Query().Get("as_of"))
+	if err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid as_of").WithDetails(err.Error()))
+		return
+	}
+	if asOf.IsZero() {
+		asOf = time.Now()
+	}
+
+	scheme, err := h.SchemeRepo.GetByID(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get scheme", err))
+		return
+	}
+	if scheme == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Scheme not found"))
+		return
+	}
+
+	versions, err := h.SchemeCache.GetAllVersions()
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get scheme versions", err))
+		return
+	}
+	// Only this scheme is assessed
+	snapshot := schemeSnapshot{schemes: []models.Scheme{*scheme}, versions: versions}
+
+	applicants, err := h.ApplicantCache.Repo.GetAll()
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applicants", err))
+		return
+	}
+	if err := h.HouseholdRepo.ResolveEach(applicants, asOf); err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get shared households", err))
+		return
+	}
+	applied, err := h.ReportRepo.SchemeApplicantIDs(scheme.ID)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get scheme applicants", err))
+		return
+	}
+
+	coverage := models.SchemeCoverage{
+		SchemeID:          scheme.ID,
+		SchemeName:        scheme.Name,
+		AsOf:              asOf,
+		SchemeOpen:        scheme.OpenAt(asOf),
+		Applicants:        len(applicants),
+		AppliedApplicants: len(applied),
+	}
+	for i := range applicants {
+		if err := r.Context().Err(); err != nil {
+			return
+		}
+
+		eligible, err := models.EligibleSchemes(snapshot, &applicants[i], asOf)
+		if err != nil {
+			apierrors.Write(w, r, apierrors.Internal("Failed to check eligibility", err))
+			return
+		}
+		if len(eligible) == 0 {
+			continue
+		}
+		coverage.EligibleApplicants++
+		if applied[applicants[i].ID] {
+			coverage.EligibleApplied++
+		} else {
+			coverage.EligibleNotApplied++
+		}
+	}
+	if coverage.EligibleApplicants > 0 {
+		rate := float64(coverage.EligibleApplied) / float64(coverage.EligibleApplicants)
+		coverage.UptakeRate = &rate
+	}
+
+	writeJSON(w, r, http.StatusOK, coverage)
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/jsonapi"
+)
+
+// Headers describing list responses, which are plain JSON arrays
+const (
+	resultCountHeader = "X-Result-Count" // Number of items in the response
+	resultLimitHeader = "X-Result-Limit" // Most items the response could hold, on lists cut to a limit
+)
+
+// writeJSON writes a successful response with the given status, as plain
+// JSON or, to clients that ask for it with Accept, as a JSON:API document
+func writeJSON(w http.ResponseWriter, r *http.Request, status int, v interface{}) {
+	write(w, r, status, v, nil)
+}
+
+// writeList writes a list of items, as [] rather than null when there are
+// none. The number of items and, if limit is positive, the limit the list was
+// cut to are returned in the X-Result-Count and X-Result-Limit headers, and
+// as count and limit in the meta of JSON:API documents.
+func writeList[T any](w http.ResponseWriter, r *http.Request, items []T, limit int) {
+	if items == nil {
+		items = []T{}
+	}
+	meta := map[string]interface{}{"count": len(items)}
+	w.Header().Set(resultCountHeader, strconv.Itoa(len(items)))
+	if limit > 0 {
+		meta["limit"] = limit
+		w.Header().Set(resultLimitHeader, strconv.Itoa(limit))
+	}
+	write(w, r, http.StatusOK, items, meta)
+}
+
+func write(w http.ResponseWriter, r *http.Request, status int, v interface{}, meta map[string]interface{}) {
+	w.Header().Add("Vary", "Accept")
+	if jsonapi.Accepts(r) {
+		body, err := jsonapi.Marshal(v, r.URL.RequestURI(), meta)
+		if err != nil {
+			apierrors.Write(w, r, apierrors.Internal("Failed to encode response", err))
+			return
+		}
+		w.Header().Set("Content-Type", jsonapi.MediaType)
+		w.WriteHeader(status)
+		w.Write(body)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
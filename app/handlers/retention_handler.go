@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/auth"
+	"one-client-view-2025tht/app/jobs"
+	"one-client-view-2025tht/app/logging"
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/retention"
+	"one-client-view-2025tht/app/storage"
+)
+
+// Limits on retention runs
+const (
+	defaultRetentionRunsLimit = 20
+	maxRetentionRunsLimit     = 100
+	maxRetentionRunIDs        = 1000 // Most matched IDs reported per rule
+)
+
+// RetentionHandler handles the runs of the retention rules, which purge or
+// anonymize records kept longer than allowed. Its endpoints require the admin
+// role.
+type RetentionHandler struct {
+	RetentionRepo  *models.RetentionRepository
+	ApplicantRepo  *models.ApplicantRepository
+	ApplicantCache *models.CachedApplicantStore // Invalidated for every applicant anonymized
+	AuditRepo      *models.AuditRepository
+	JobRepo        *models.JobRepository
+	Store          storage.Store
+	Rules          []retention.Rule
+}
+
+// NewRetentionHandler creates a new handler applying the given rules
+func NewRetentionHandler(retentionRepo *models.RetentionRepository, applicantRepo *models.ApplicantRepository, applicantCache *models.CachedApplicantStore, auditRepo *models.AuditRepository, jobRepo *models.JobRepository, store storage.Store, rules []retention.Rule) *RetentionHandler {
+	return &RetentionHandler{
+		RetentionRepo:  retentionRepo,
+		ApplicantRepo:  applicantRepo,
+		ApplicantCache: applicantCache,
+		AuditRepo:      auditRepo,
+		JobRepo:        jobRepo,
+		Store:          store,
+		Rules:          rules,
+	}
+}
+
+// GetRetentionRuns handles GET /api/v1/retention/runs
+// @Summary List retention runs
+// @Description List the latest runs of the retention rules, scheduled or requested, newest first. The result of each finished run is a models.RetentionRunResult reporting, for each rule, its cutoff and the records it matched and purged or anonymized. Requires the admin role.
+// @Tags retention
+// @Produce json
+// @Param limit query int false "Maximum number of runs (default 20, max 100)"
+// @Success 200 {array} models.Job
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 403 {object} apierrors.APIError "Requires the admin role"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/retention/runs [get]
+func (h *RetentionHandler) GetRetentionRuns(w http.ResponseWriter, r *http.Request) {
+	if !hasRole(r, auth.RoleAdmin) {
+		apierrors.Write(w, r, apierrors.Forbidden("Retention runs require the admin role"))
+		return
+	}
+
+	limit := defaultRetentionRunsLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxRetentionRunsLimit {
+			apierrors.Write(w, r, apierrors.BadRequest("limit must be between 1 and 100"))
+			return
+		}
+		limit = parsed
+	}
+
+	runs, err := h.JobRepo.FindByType(models.JobRetentionRun, limit)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get retention runs", err))
+		return
+	}
+
+	writeList(w, r, runs, limit)
+}
+
+// QueueRetentionRun handles POST /api/v1/retention/runs
+// @Summary Run the retention rules
+// @Description Queue a run of the configured retention rules: rejected applications past their period are purged with their documents, and applicants inactive for longer than theirs are anonymized. Runs are dry runs unless dry_run=false, reporting what would be removed without changing anything. Poll the returned job for the outcome. Requires the admin role.
+// @Tags retention
+// @Produce json
+// @Param dry_run query bool false "Only report what the rules match (default true)"
+// @Success 202 {object} models.Job "Queued; the result is a models.RetentionRunResult"
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 403 {object} apierrors.APIError "Requires the admin role"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/retention/runs [post]
+func (h *RetentionHandler) QueueRetentionRun(w http.ResponseWriter, r *http.Request) {
+	if !hasRole(r, auth.RoleAdmin) {
+		apierrors.Write(w, r, apierrors.Forbidden("Retention runs require the admin role"))
+		return
+	}
+
+	dryRun := true
+	if value := r.URL.Query().Get("dry_run"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			apierrors.Write(w, r, apierrors.BadRequest("Invalid dry_run").WithDetails(err.Error()))
+			return
+		}
+		dryRun = parsed
+	}
+
+	actor := actorFrom(r)
+	payload := models.RetentionRunJob{
+		AsOf:          time.Now(),
+		DryRun:        dryRun,
+		ActorID:       actor.ID,
+		ActorUsername: actor.Username,
+	}
+	job, err := h.JobRepo.Enqueue(models.JobRetentionRun, payload, actor.ID)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to queue retention run", err))
+		return
+	}
+
+	writeJobAccepted(w, r, job)
+}
+
+// RunRetentionJob is the handler of retention.run jobs. Each record is
+// purged or anonymized in its own transaction, with an audit entry, so a
+// failed run keeps what it completed and its retry carries on from there.
+func (h *RetentionHandler) RunRetentionJob(ctx context.Context, job *models.Job) (interface{}, error) {
+	var req models.RetentionRunJob
+	if err := json.Unmarshal(job.Payload, &req); err != nil || req.AsOf.IsZero() {
+		return nil, jobs.Permanent(fmt.Errorf("invalid payload: %v", err))
+	}
+	actor := models.Actor{ID: req.ActorID, Username: req.ActorUsername}
+
+	result := models.RetentionRunResult{AsOf: req.AsOf, DryRun: req.DryRun, Rules: []models.RetentionRuleResult{}}
+	for _, rule := range h.Rules {
+		cutoff := rule.Period.Before(req.AsOf)
+
+		var ids []string
+		var err error
+		switch rule.Name {
+		case retention.RuleRejectedApplications:
+			ids, err = h.RetentionRepo.RejectedApplicationsBefore(cutoff)
+		case retention.RuleInactiveApplicants:
+			ids, err = h.RetentionRepo.InactiveApplicantsBefore(cutoff)
+		default:
+			err = jobs.Permanent(fmt.Errorf("unknown retention rule %q", rule.Name))
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		ruleResult := models.RetentionRuleResult{
+			Rule:    rule.Name,
+			Action:  rule.Action,
+			Period:  rule.Period.String(),
+			Cutoff:  cutoff,
+			Matched: len(ids),
+			IDs:     ids[:min(len(ids), maxRetentionRunIDs)],
+		}
+		if !req.DryRun {
+			for _, id := range ids {
+				if err := ctx.Err(); err != nil {
+					return nil, err
+				}
+				if err := h.apply(ctx, rule, id, actor); err != nil {
+					return nil, err
+				}
+				ruleResult.Applied++
+			}
+		}
+		result.Rules = append(result.Rules, ruleResult)
+	}
+
+	return result, nil
+}
+
+// apply purges or anonymizes one record matched by rule, then deletes the
+// files it held
+func (h *RetentionHandler) apply(ctx context.Context, rule retention.Rule, id string, actor models.Actor) error {
+	var keys []string
+	err := models.WithTx(h.AuditRepo.DB, func(tx *sql.Tx) error {
+		var err error
+		switch rule.Action {
+		case retention.ActionPurge:
+			if keys, err = h.RetentionRepo.WithTx(tx).PurgeApplication(id); err != nil {
+				return err
+			}
+			return h.AuditRepo.WithTx(tx).Record(models.AuditEntityApplication, id,
+				models.AuditActionPurge, actor, nil, nil)
+		case retention.ActionAnonymize:
+			if keys, err = h.ApplicantRepo.WithTx(tx).Anonymize(id); err != nil {
+				return err
+			}
+			return h.AuditRepo.WithTx(tx).Record(models.AuditEntityApplicant, id,
+				models.AuditActionAnonymize, actor, nil, nil)
+		}
+		return jobs.Permanent(fmt.Errorf("unknown retention action %q", rule.Action))
+	})
+	if err != nil {
+		return fmt.Errorf("error applying %s to %s: %v", rule.Name, id, err)
+	}
+	if rule.Action == retention.ActionAnonymize {
+		h.ApplicantCache.Invalidate(id)
+	}
+
+	// The records are gone, so a failure here only leaves unreachable files
+	for _, key := range keys {
+		if err := h.Store.Delete(ctx, key); err != nil {
+			logging.FromContext(ctx).Error("Failed to delete retained file", "key", key, "error", err)
+		}
+	}
+	return nil
+}
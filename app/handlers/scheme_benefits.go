@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/validation"
+)
+
+// CreateBenefit handles POST /api/v1/schemes/{id}/benefits
+// @Summary Add a benefit to a scheme
+// @Description Add a new benefit to an existing scheme. Benefits of published schemes can only be changed by admins, and those of archived schemes not at all.
+// @Tags schemes
+// @Accept json
+// @Produce json
+// @Param id path string true "Scheme ID"
+// @Param benefit body models.Benefit true "Benefit information"
+// @Success 201 {object} models.Benefit
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 403 {object} apierrors.APIError "Published schemes can only be changed by admins"
+// @Failure 404 {object} apierrors.APIError "Scheme not found"
+// @Failure 409 {object} apierrors.APIError "Scheme is archived"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/schemes/{id}/benefits [post]
+func (h *SchemeHandler) CreateBenefit(w http.ResponseWriter, r *http.Request) {
+	schemeID := mux.Vars(r)["id"]
+
+	scheme, err := h.SchemeRepo.GetByID(schemeID)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get scheme", err))
+		return
+	}
+	if scheme == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Scheme not found"))
+		return
+	}
+	if !canEdit(w, r, scheme) {
+		return
+	}
+
+	var benefit models.Benefit
+	if err := json.NewDecoder(r.Body).Decode(&benefit); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
+		return
+	}
+
+	// IDs are assigned by the server
+	benefit.ID = ""
+	benefit.SchemeID = schemeID
+
+	if err := validation.Benefit(&benefit); err != nil {
+		apierrors.Write(w, r, validationError(err))
+		return
+	}
+
+	err = models.WithTx(h.SchemeRepo.DB, func(tx *sql.Tx) error {
+		if err := h.SchemeRepo.WithTx(tx).CreateBenefit(&benefit); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityBenefit, benefit.ID,
+			models.AuditActionCreate, actorFrom(r), nil, &benefit)
+	})
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to create benefit", err))
+		return
+	}
+	h.SchemeCache.Invalidate()
+
+	writeJSON(w, r, http.StatusCreated, benefit)
+}
+
+// UpdateBenefit handles PUT /api/v1/schemes/{id}/benefits/{benefitId}
+// @Summary Update a scheme benefit
+// @Description Replace the name, description and amount of a scheme's benefit, e.g. when a policy change adjusts the amount
+// @Tags schemes
+// @Accept json
+// @Produce json
+// @Param id path string true "Scheme ID"
+// @Param benefitId path string true "Benefit ID"
+// @Param benefit body models.Benefit true "Updated benefit information"
+// @Success 200 {object} models.Benefit
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 403 {object} apierrors.APIError "Published schemes can only be changed by admins"
+// @Failure 404 {object} apierrors.APIError "Benefit not found"
+// @Failure 409 {object} apierrors.APIError "Scheme is archived"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/schemes/{id}/benefits/{benefitId} [put]
+func (h *SchemeHandler) UpdateBenefit(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	schemeID := vars["id"]
+	benefitID := vars["benefitId"]
+
+	existing, err := h.SchemeRepo.GetBenefit(schemeID, benefitID)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get benefit", err))
+		return
+	}
+	if existing == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Benefit not found"))
+		return
+	}
+	if !h.canEditScheme(w, r, schemeID) {
+		return
+	}
+
+	var benefit models.Benefit
+	if err := json.NewDecoder(r.Body).Decode(&benefit); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
+		return
+	}
+
+	// Ensure IDs match path parameters
+	benefit.ID = benefitID
+	benefit.SchemeID = schemeID
+	benefit.CreatedAt = existing.CreatedAt
+
+	if err := validation.Benefit(&benefit); err != nil {
+		apierrors.Write(w, r, validationError(err))
+		return
+	}
+
+	err = models.WithTx(h.SchemeRepo.DB, func(tx *sql.Tx) error {
+		if err := h.SchemeRepo.WithTx(tx).UpdateBenefit(&benefit); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityBenefit, benefitID,
+			models.AuditActionUpdate, actorFrom(r), existing, &benefit)
+	})
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to update benefit", err))
+		return
+	}
+	h.SchemeCache.Invalidate()
+
+	writeJSON(w, r, http.StatusOK, benefit)
+}
+
+// DeleteBenefit handles DELETE /api/v1/schemes/{id}/benefits/{benefitId}
+// @Summary Delete a scheme benefit
+// @Description Remove a benefit from a scheme
+// @Tags schemes
+// @Produce json
+// @Param id path string true "Scheme ID"
+// @Param benefitId path string true "Benefit ID"
+// @Success 204 "No content"
+// @Failure 403 {object} apierrors.APIError "Published schemes can only be changed by admins"
+// @Failure 404 {object} apierrors.APIError "Benefit not found"
+// @Failure 409 {object} apierrors.APIError "Scheme is archived"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/schemes/{id}/benefits/{benefitId} [delete]
+func (h *SchemeHandler) DeleteBenefit(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	schemeID := vars["id"]
+	benefitID := vars["benefitId"]
+
+	existing, err := h.SchemeRepo.GetBenefit(schemeID, benefitID)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get benefit", err))
+		return
+	}
+	if existing == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Benefit not found"))
+		return
+	}
+	if !h.canEditScheme(w, r, schemeID) {
+		return
+	}
+
+	err = models.WithTx(h.SchemeRepo.DB, func(tx *sql.Tx) error {
+		if err := h.SchemeRepo.WithTx(tx).DeleteBenefit(benefitID); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityBenefit, benefitID,
+			models.AuditActionDelete, actorFrom(r), existing, nil)
+	})
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to delete benefit", err))
+		return
+	}
+	h.SchemeCache.Invalidate()
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/validation"
+)
+
+// CreateChecklistItem handles POST /api/v1/schemes/{id}/checklist
+// @Summary Add an item to a scheme's review checklist
+// @Description Add a review checklist item, such as a document to verify or an interview to conduct, to a scheme. Applications created from then on get a copy of it; mandatory items must be completed before an application is approved. Checklists of published schemes can only be changed by admins, and those of archived schemes not at all.
+// @Tags schemes
+// @Accept json
+// @Produce json
+// @Param id path string true "Scheme ID"
+// @Param item body models.ChecklistItem true "Checklist item"
+// @Success 201 {object} models.ChecklistItem
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 403 {object} apierrors.APIError "Published schemes can only be changed by admins"
+// @Failure 404 {object} apierrors.APIError "Scheme not found"
+// @Failure 409 {object} apierrors.APIError "Scheme is archived"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/schemes/{id}/checklist [post]
+func (h *SchemeHandler) CreateChecklistItem(w http.ResponseWriter, r *http.Request) {
+	schemeID := mux.Vars(r)["id"]
+	if !h.canEditScheme(w, r, schemeID) {
+		return
+	}
+
+	var item models.ChecklistItem
+	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
+		return
+	}
+
+	// IDs are assigned by the server
+	item.ID = ""
+	item.SchemeID = schemeID
+
+	if err := validation.ChecklistItem(&item); err != nil {
+		apierrors.Write(w, r, validationError(err))
+		return
+	}
+
+	err := models.WithTx(h.SchemeRepo.DB, func(tx *sql.Tx) error {
+		if err := h.SchemeRepo.WithTx(tx).CreateChecklistItem(&item); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityChecklistItem, item.ID,
+			models.AuditActionCreate, actorFrom(r), nil, &item)
+	})
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to create checklist item", err))
+		return
+	}
+	h.SchemeCache.Invalidate()
+
+	writeJSON(w, r, http.StatusCreated, item)
+}
+
+// UpdateChecklistItem handles PUT /api/v1/schemes/{id}/checklist/{itemId}
+// @Summary Update a scheme checklist item
+// @Description Replace the name, description, kind, mandatory flag and position of an item of a scheme's review checklist. The copies on existing applications are not changed.
+// @Tags schemes
+// @Accept json
+// @Produce json
+// @Param id path string true "Scheme ID"
+// @Param itemId path string true "Checklist item ID"
+// @Param item body models.ChecklistItem true "Updated checklist item"
+// @Success 200 {object} models.ChecklistItem
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 403 {object} apierrors.APIError "Published schemes can only be changed by admins"
+// @Failure 404 {object} apierrors.APIError "Checklist item not found"
+// @Failure 409 {object} apierrors.APIError "Scheme is archived"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/schemes/{id}/checklist/{itemId} [put]
+func (h *SchemeHandler) UpdateChecklistItem(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	schemeID := vars["id"]
+	itemID := vars["itemId"]
+
+	existing, err := h.SchemeRepo.GetChecklistItem(schemeID, itemID)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get checklist item", err))
+		return
+	}
+	if existing == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Checklist item not found"))
+		return
+	}
+	if !h.canEditScheme(w, r, schemeID) {
+		return
+	}
+
+	var item models.ChecklistItem
+	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
+		return
+	}
+
+	// Ensure IDs match path parameters
+	item.ID = itemID
+	item.SchemeID = schemeID
+	item.CreatedAt = existing.CreatedAt
+
+	if err := validation.ChecklistItem(&item); err != nil {
+		apierrors.Write(w, r, validationError(err))
+		return
+	}
+
+	err = models.WithTx(h.SchemeRepo.DB, func(tx *sql.Tx) error {
+		if err := h.SchemeRepo.WithTx(tx).UpdateChecklistItem(&item); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityChecklistItem, itemID,
+			models.AuditActionUpdate, actorFrom(r), existing, &item)
+	})
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to update checklist item", err))
+		return
+	}
+	h.SchemeCache.Invalidate()
+
+	writeJSON(w, r, http.StatusOK, item)
+}
+
+// DeleteChecklistItem handles DELETE /api/v1/schemes/{id}/checklist/{itemId}
+// @Summary Delete a scheme checklist item
+// @Description Remove an item from a scheme's review checklist. The copies on existing applications are kept and must still be completed if mandatory.
+// @Tags schemes
+// @Produce json
+// @Param id path string true "Scheme ID"
+// @Param itemId path string true "Checklist item ID"
+// @Success 204 "No content"
+// @Failure 403 {object} apierrors.APIError "Published schemes can only be changed by admins"
+// @Failure 404 {object} apierrors.APIError "Checklist item not found"
+// @Failure 409 {object} apierrors.APIError "Scheme is archived"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/schemes/{id}/checklist/{itemId} [delete]
+func (h *SchemeHandler) DeleteChecklistItem(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	schemeID := vars["id"]
+	itemID := vars["itemId"]
+
+	existing, err := h.SchemeRepo.GetChecklistItem(schemeID, itemID)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get checklist item", err))
+		return
+	}
+	if existing == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Checklist item not found"))
+		return
+	}
+	if !h.canEditScheme(w, r, schemeID) {
+		return
+	}
+
+	err = models.WithTx(h.SchemeRepo.DB, func(tx *sql.Tx) error {
+		if err := h.SchemeRepo.WithTx(tx).DeleteChecklistItem(itemID); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityChecklistItem, itemID,
+			models.AuditActionDelete, actorFrom(r), existing, nil)
+	})
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to delete checklist item", err))
+		return
+	}
+	h.SchemeCache.Invalidate()
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,131 +1,228 @@
 package handlers
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/auth"
+	"one-client-view-2025tht/app/mergepatch"
 	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/validation"
 )
 
 // SchemeHandler handles HTTP requests related to schemes
 type SchemeHandler struct {
-	SchemeRepo    *models.SchemeRepository
-	ApplicantRepo *models.ApplicantRepository
+	SchemeRepo      *models.SchemeRepository
+	SchemeCache     *models.CachedSchemeStore    // Serves scheme listings and eligibility checks; invalidated on every change
+	ApplicantCache  *models.CachedApplicantStore // Serves applicants for eligibility checks
+	TranslationRepo *models.SchemeTranslationRepository
+	AuditRepo       *models.AuditRepository
+	JobRepo         *models.JobRepository
+	CustomFieldRepo *models.CustomFieldRepository // Defines the custom fields criteria may compare
+	HouseholdRepo   *models.HouseholdRepository   // Resolves the shared households applicants are assessed against
 }
 
 // NewSchemeHandler creates a new handler with the given repositories
-func NewSchemeHandler(schemeRepo *models.SchemeRepository, applicantRepo *models.ApplicantRepository) *SchemeHandler {
+func NewSchemeHandler(schemeRepo *models.SchemeRepository, schemeCache *models.CachedSchemeStore, applicantCache *models.CachedApplicantStore, translationRepo *models.SchemeTranslationRepository, auditRepo *models.AuditRepository, jobRepo *models.JobRepository, customFieldRepo *models.CustomFieldRepository, householdRepo *models.HouseholdRepository) *SchemeHandler {
 	return &SchemeHandler{
-		SchemeRepo:    schemeRepo,
-		ApplicantRepo: applicantRepo,
+		SchemeRepo:      schemeRepo,
+		SchemeCache:     schemeCache,
+		ApplicantCache:  applicantCache,
+		TranslationRepo: translationRepo,
+		AuditRepo:       auditRepo,
+		JobRepo:         jobRepo,
+		CustomFieldRepo: customFieldRepo,
+		HouseholdRepo:   householdRepo,
 	}
 }
 
-// GetSchemes handles GET /api/schemes
+// GetSchemes handles GET /api/v1/schemes
 // @Summary Get all schemes
-// @Description Retrieve a list of all financial assistance schemes
+// @Description Retrieve a list of all financial assistance schemes, optionally only those that are or are not open for applications now, or with a status. Without a token, or with an applicant's, only published schemes are listed. Names and descriptions are served in the language preferred by Accept-Language where the scheme has been translated into it.
 // @Tags schemes
 // @Accept json
 // @Produce json
+// @Param active query bool false "true for schemes open for applications now, false for the rest"
+// @Param status query string false "Only schemes with this status" Enums(draft, published, archived)
+// @Param fields query string false "Comma-separated fields to return, such as id,name,household.name; all if omitted"
+// @Param Accept-Language header string false "Preferred languages: en, zh, ms or ta"
 // @Success 200 {array} models.SchemeResponse
-// @Failure 500 {object} string "Internal server error"
-// @Router /api/schemes [get]
+// @Failure 400 {object} apierrors.APIError "Invalid active or status"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Router /api/v1/schemes [get]
 func (h *SchemeHandler) GetSchemes(w http.ResponseWriter, r *http.Request) {
-	schemes, err := h.SchemeRepo.GetAll()
+	var active *bool
+	if value := r.URL.Query().Get("active"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			apierrors.Write(w, r, apierrors.BadRequest("Invalid active").WithDetails(err.Error()))
+			return
+		}
+		active = &parsed
+	}
+	status := r.URL.Query().Get("status")
+	if status != "" && !slices.Contains(models.SchemeStatuses, status) {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid status").
+			WithDetails("must be one of: "+strings.Join(models.SchemeStatuses, ", ")))
+		return
+	}
+
+	// Drafts and archived schemes are not offered to the public, so only
+	// staff can list them
+	claims := auth.FromContext(r.Context())
+	if claims == nil || claims.Role == auth.RoleApplicant {
+		if status != "" && status != models.SchemePublished {
+			writeList(w, r, []models.SchemeResponse{}, 0)
+			return
+		}
+		status = models.SchemePublished
+	}
+
+	schemes, err := h.SchemeCache.GetAll()
 	if err != nil {
-		http.Error(w, "Failed to get schemes: "+err.Error(), http.StatusInternalServerError)
+		apierrors.Write(w, r, apierrors.Internal("Failed to get schemes", err))
+		return
+	}
+
+	now := time.Now()
+	var listed []models.Scheme
+	for _, s := range schemes {
+		if (active == nil || s.OpenAt(now) == *active) && (status == "" || s.Status == status) {
+			listed = append(listed, s)
+		}
+	}
+	if err := localizeSchemes(w, r, h.TranslationRepo, listed); err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get scheme translations", err))
 		return
 	}
 
 	// Convert to response objects
 	var response []models.SchemeResponse
-	for _, s := range schemes {
+	for _, s := range listed {
 		response = append(response, models.SchemeResponse{
 			Scheme:   s,
 			Benefits: s.Benefits,
 		})
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	writeList(w, r, response, 0)
 }
 
-// GetScheme handles GET /api/schemes/{id}
+// GetScheme handles GET /api/v1/schemes/{id}
 // @Summary Get scheme by ID
-// @Description Retrieve a specific scheme by its ID
+// @Description Retrieve a specific scheme by its ID, with its name and description in the language preferred by Accept-Language if it has been translated into it
 // @Tags schemes
 // @Accept json
 // @Produce json
 // @Param id path string true "Scheme ID"
+// @Param fields query string false "Comma-separated fields to return, such as id,name,household.name; all if omitted"
+// @Param Accept-Language header string false "Preferred languages: en, zh, ms or ta"
 // @Success 200 {object} models.SchemeResponse
-// @Failure 404 {object} string "Scheme not found"
-// @Failure 500 {object} string "Internal server error"
-// @Router /api/schemes/{id} [get]
+// @Failure 404 {object} apierrors.APIError "Scheme not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/schemes/{id} [get]
 func (h *SchemeHandler) GetScheme(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
 
 	scheme, err := h.SchemeRepo.GetByID(id)
 	if err != nil {
-		http.Error(w, "Failed to get scheme: "+err.Error(), http.StatusInternalServerError)
+		apierrors.Write(w, r, apierrors.Internal("Failed to get scheme", err))
 		return
 	}
 
 	if scheme == nil {
-		http.Error(w, "Scheme not found", http.StatusNotFound)
+		apierrors.Write(w, r, apierrors.NotFound("Scheme not found"))
+		return
+	}
+
+	localized := []models.Scheme{*scheme}
+	if err := localizeSchemes(w, r, h.TranslationRepo, localized); err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get scheme translations", err))
 		return
 	}
 
 	response := models.SchemeResponse{
-		Scheme:   *scheme,
+		Scheme:   localized[0],
 		Benefits: scheme.Benefits,
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	setETag(w, scheme.Version)
+	writeJSON(w, r, http.StatusOK, response)
 }
 
-// GetEligibleSchemes handles GET /api/schemes/eligible?applicant={id}
+// GetEligibleSchemes handles GET /api/v1/schemes/eligible?applicant={id}
 // @Summary Get eligible schemes for an applicant
-// @Description Retrieve all schemes that an applicant is eligible for
+// @Description Retrieve all schemes that an applicant is eligible for, assessed against the terms of each scheme in effect at as_of (default now). Names and descriptions are served in the language preferred by Accept-Language where the scheme has been translated into it.
 // @Tags schemes
 // @Accept json
 // @Produce json
 // @Param applicant query string true "Applicant ID"
+// @Param as_of query string false "Date or RFC3339 time to assess eligibility at (default now)"
+// @Param Accept-Language header string false "Preferred languages: en, zh, ms or ta"
 // @Success 200 {object} models.EligibleSchemesResponse
-// @Failure 400 {object} string "Bad request"
-// @Failure 404 {object} string "Applicant not found"
-// @Failure 500 {object} string "Internal server error"
-// @Router /api/schemes/eligible [get]
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 404 {object} apierrors.APIError "Applicant not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/schemes/eligible [get]
 func (h *SchemeHandler) GetEligibleSchemes(w http.ResponseWriter, r *http.Request) {
 	applicantID := r.URL.Query().Get("applicant")
 	if applicantID == "" {
-		http.Error(w, "Applicant ID is required", http.StatusBadRequest)
+		apierrors.Write(w, r, apierrors.BadRequest("Applicant ID is required"))
 		return
 	}
 
+	asOf := time.Now()
+	if value := r.URL.Query().Get("as_of"); value != "" {
+		t, err := parseTimeParam(value)
+		if err != nil {
+			apierrors.Write(w, r, apierrors.BadRequest("Invalid as_of").WithDetails(err.Error()))
+			return
+		}
+		asOf = t
+	}
+
 	// Check if applicant exists
-	applicant, err := h.ApplicantRepo.GetByID(applicantID)
+	applicant, err := h.ApplicantCache.GetByID(applicantID)
 	if err != nil {
-		http.Error(w, "Failed to get applicant: "+err.Error(), http.StatusInternalServerError)
+		apierrors.Write(w, r, apierrors.Internal("Failed to get applicant", err))
 		return
 	}
 	if applicant == nil {
-		http.Error(w, "Applicant not found", http.StatusNotFound)
+		apierrors.Write(w, r, apierrors.NotFound("Applicant not found"))
 		return
 	}
 
-	// Get eligible schemes
-	schemes, err := h.SchemeRepo.GetEligibleSchemes(applicantID, h.ApplicantRepo)
+	// Get eligible schemes, assessed against the shared household the
+	// applicant belongs to at as_of, if any
+	applicant, err = h.HouseholdRepo.Resolve(applicant, asOf)
 	if err != nil {
-		http.Error(w, "Failed to get eligible schemes: "+err.Error(), http.StatusInternalServerError)
+		apierrors.Write(w, r, apierrors.Internal("Failed to get shared household", err))
+		return
+	}
+	schemes, err := models.EligibleSchemes(h.SchemeCache, applicant, asOf)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get eligible schemes", err))
+		return
+	}
+	if err := localizeSchemes(w, r, h.TranslationRepo, schemes); err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get scheme translations", err))
 		return
 	}
 
 	// Convert to response objects
-	var schemeResponses []models.SchemeResponse
+	schemeResponses := []models.SchemeResponse{}
 	for _, s := range schemes {
 		schemeResponses = append(schemeResponses, models.SchemeResponse{
 			Scheme:   s,
@@ -135,71 +232,120 @@ func (h *SchemeHandler) GetEligibleSchemes(w http.ResponseWriter, r *http.Reques
 
 	response := models.EligibleSchemesResponse{
 		ApplicantID: applicantID,
+		AsOf:        asOf,
 		Schemes:     schemeResponses,
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// GetSchemeVersions handles GET /api/v1/schemes/{id}/versions
+// @Summary Get scheme versions
+// @Description Retrieve the history of a scheme's terms with the period each version was in effect, oldest first
+// @Tags schemes
+// @Produce json
+// @Param id path string true "Scheme ID"
+// @Success 200 {array} models.SchemeVersion
+// @Failure 404 {object} apierrors.APIError "Scheme not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/schemes/{id}/versions [get]
+func (h *SchemeHandler) GetSchemeVersions(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	scheme, err := h.SchemeRepo.GetByID(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get scheme", err))
+		return
+	}
+	if scheme == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Scheme not found"))
+		return
+	}
+
+	versions, err := h.SchemeRepo.GetVersions(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get scheme versions", err))
+		return
+	}
+
+	writeList(w, r, versions, 0)
 }
 
-// CreateScheme handles POST /api/schemes
+// CreateScheme handles POST /api/v1/schemes
 // @Summary Create a new scheme
-// @Description Add a new financial assistance scheme
+// @Description Add a new financial assistance scheme as a draft, which can be changed without being offered to anyone until an admin publishes it with POST /api/v1/schemes/{id}/publish. Caseworkers and admins can change drafts.
 // @Tags schemes
 // @Accept json
 // @Produce json
 // @Param scheme body models.Scheme true "Scheme information"
 // @Success 201 {object} models.SchemeResponse
-// @Failure 400 {object} string "Bad request"
-// @Failure 500 {object} string "Internal server error"
-// @Router /api/schemes [post]
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/schemes [post]
 func (h *SchemeHandler) CreateScheme(w http.ResponseWriter, r *http.Request) {
-	var scheme models.Scheme
+	// Schemes are active unless created otherwise
+	scheme := models.Scheme{IsActive: true}
 	err := json.NewDecoder(r.Body).Decode(&scheme)
 	if err != nil {
-		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
 		return
 	}
+	scheme.Status = models.SchemeDraft
 
-	// Basic validation
-	if scheme.Name == "" {
-		http.Error(w, "Name is required", http.StatusBadRequest)
+	fields, ok := loadCustomFields(w, r, h.CustomFieldRepo)
+	if !ok {
 		return
 	}
-	if scheme.Description == "" {
-		http.Error(w, "Description is required", http.StatusBadRequest)
+	if err := validation.Scheme(&scheme, fields); err != nil {
+		apierrors.Write(w, r, validationError(err))
 		return
 	}
 
-	err = h.SchemeRepo.Create(&scheme)
+	err = models.WithTx(h.SchemeRepo.DB, func(tx *sql.Tx) error {
+		if err := h.SchemeRepo.WithTx(tx).Create(&scheme); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityScheme, scheme.ID,
+			models.AuditActionCreate, actorFrom(r), nil, &scheme)
+	})
 	if err != nil {
-		http.Error(w, "Failed to create scheme: "+err.Error(), http.StatusInternalServerError)
+		apierrors.Write(w, r, apierrors.Internal("Failed to create scheme", err))
 		return
 	}
+	h.SchemeCache.Invalidate()
 
 	response := models.SchemeResponse{
 		Scheme:   scheme,
 		Benefits: scheme.Benefits,
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(response)
+	setETag(w, scheme.Version)
+	writeJSON(w, r, http.StatusCreated, response)
 }
 
-// UpdateScheme handles PUT /api/schemes/{id}
+// UpdateScheme handles PUT /api/v1/schemes/{id}
 // @Summary Update scheme
-// @Description Update an existing scheme's information
+// @Description Update an existing scheme's information. The new name, description and criteria take effect at effective_from (default now); applications keep the terms they were assessed under. Published schemes can only be changed by admins, and archived ones not at all; the status is changed by publishing and archiving, and benefits and the checklist through their own endpoints.
 // @Tags schemes
 // @Accept json
 // @Produce json
 // @Param id path string true "Scheme ID"
+// @Param If-Match header string false "ETag of the version being updated; alternatively send version in the body"
 // @Param scheme body models.Scheme true "Updated scheme information"
 // @Success 200 {object} models.SchemeResponse
-// @Failure 400 {object} string "Bad request"
-// @Failure 404 {object} string "Scheme not found"
-// @Failure 500 {object} string "Internal server error"
-// @Router /api/schemes/{id} [put]
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 403 {object} apierrors.APIError "Published schemes can only be changed by admins"
+// @Failure 404 {object} apierrors.APIError "Scheme not found"
+// @Failure 409 {object} apierrors.APIError "Version conflict, or scheme is archived"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 428 {object} apierrors.APIError "Missing If-Match header or version"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/schemes/{id} [put]
 func (h *SchemeHandler) UpdateScheme(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
@@ -207,63 +353,213 @@ func (h *SchemeHandler) UpdateScheme(w http.ResponseWriter, r *http.Request) {
 	// Check if scheme exists
 	existing, err := h.SchemeRepo.GetByID(id)
 	if err != nil {
-		http.Error(w, "Failed to get scheme: "+err.Error(), http.StatusInternalServerError)
+		apierrors.Write(w, r, apierrors.Internal("Failed to get scheme", err))
 		return
 	}
 	if existing == nil {
-		http.Error(w, "Scheme not found", http.StatusNotFound)
+		apierrors.Write(w, r, apierrors.NotFound("Scheme not found"))
+		return
+	}
+	if !canEdit(w, r, existing) {
 		return
 	}
 
-	var scheme models.Scheme
+	// Schemes are active unless updated otherwise
+	scheme := models.Scheme{IsActive: true}
 	err = json.NewDecoder(r.Body).Decode(&scheme)
 	if err != nil {
-		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
 		return
 	}
 
 	// Ensure ID matches path parameter
 	scheme.ID = id
 
-	// Basic validation
-	if scheme.Name == "" {
-		http.Error(w, "Name is required", http.StatusBadRequest)
+	// Reject updates based on a stale version
+	version, apiErr := expectedVersion(r, scheme.Version, existing.Version)
+	if apiErr != nil {
+		apierrors.Write(w, r, apiErr)
 		return
 	}
-	if scheme.Description == "" {
-		http.Error(w, "Description is required", http.StatusBadRequest)
+	if version != existing.Version {
+		apierrors.Write(w, r, versionConflict())
 		return
 	}
+	scheme.Version = version
 
-	// Preserve benefits
+	// Benefits and the checklist are managed through their own endpoints, the
+	// approved totals by approvals and the status by publishing and archiving
+	scheme.Status = existing.Status
 	scheme.Benefits = existing.Benefits
+	scheme.Checklist = existing.Checklist
+	scheme.ApprovedCount = existing.ApprovedCount
+	scheme.ApprovedAmount = existing.ApprovedAmount
+
+	fields, ok := loadCustomFields(w, r, h.CustomFieldRepo)
+	if !ok {
+		return
+	}
+	if err := validation.Scheme(&scheme, fields); err != nil {
+		apierrors.Write(w, r, validationError(err))
+		return
+	}
 
-	err = h.SchemeRepo.Update(&scheme)
+	err = models.WithTx(h.SchemeRepo.DB, func(tx *sql.Tx) error {
+		if err := h.SchemeRepo.WithTx(tx).Update(&scheme); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityScheme, id,
+			models.AuditActionUpdate, actorFrom(r), existing, &scheme)
+	})
+	if errors.Is(err, models.ErrVersionConflict) {
+		apierrors.Write(w, r, versionConflict())
+		return
+	}
+	if errors.Is(err, models.ErrEffectiveDate) {
+		apierrors.Write(w, r, apierrors.Validation(map[string]string{"effective_from": "must not be before the start of the latest version"}))
+		return
+	}
 	if err != nil {
-		http.Error(w, "Failed to update scheme: "+err.Error(), http.StatusInternalServerError)
+		apierrors.Write(w, r, apierrors.Internal("Failed to update scheme", err))
 		return
 	}
+	h.SchemeCache.Invalidate()
 
 	response := models.SchemeResponse{
 		Scheme:   scheme,
 		Benefits: scheme.Benefits,
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	setETag(w, scheme.Version)
+	writeJSON(w, r, http.StatusOK, response)
 }
 
-// DeleteScheme handles DELETE /api/schemes/{id}
+// PatchScheme handles PATCH /api/v1/schemes/{id}
+// @Summary Partially update scheme
+// @Description Update selected fields of a scheme using JSON Merge Patch (RFC 7396): fields present in the body replace the stored values, nested criteria are merged, and null removes an optional criterion. The new terms take effect at effective_from (default now). Benefits, the checklist and the status are not changed. Published schemes can only be changed by admins, and archived ones not at all.
+// @Tags schemes
+// @Accept json
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param id path string true "Scheme ID"
+// @Param If-Match header string false "ETag of the version being updated; alternatively send version in the body"
+// @Param patch body models.Scheme true "Fields to update"
+// @Success 200 {object} models.SchemeResponse
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 403 {object} apierrors.APIError "Published schemes can only be changed by admins"
+// @Failure 404 {object} apierrors.APIError "Scheme not found"
+// @Failure 409 {object} apierrors.APIError "Version conflict, or scheme is archived"
+// @Failure 415 {object} apierrors.APIError "Unsupported Content-Type"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 428 {object} apierrors.APIError "Missing If-Match header or version"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/schemes/{id} [patch]
+func (h *SchemeHandler) PatchScheme(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	existing, err := h.SchemeRepo.GetByID(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get scheme", err))
+		return
+	}
+	if existing == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Scheme not found"))
+		return
+	}
+	if !canEdit(w, r, existing) {
+		return
+	}
+
+	patch, patchVersion, apiErr := readMergePatch(r)
+	if apiErr != nil {
+		apierrors.Write(w, r, apiErr)
+		return
+	}
+
+	// Reject updates based on a stale version
+	version, apiErr := expectedVersion(r, patchVersion, existing.Version)
+	if apiErr != nil {
+		apierrors.Write(w, r, apiErr)
+		return
+	}
+	if version != existing.Version {
+		apierrors.Write(w, r, versionConflict())
+		return
+	}
+
+	var scheme models.Scheme
+	if err := mergepatch.ApplyTo(existing, patch, &scheme); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid patch").WithDetails(err.Error()))
+		return
+	}
+
+	// Fields managed by the server, benefits and the checklist are kept as
+	// stored
+	scheme.ID = existing.ID
+	scheme.Status = existing.Status
+	scheme.CreatedAt = existing.CreatedAt
+	scheme.UpdatedAt = existing.UpdatedAt
+	scheme.Benefits = existing.Benefits
+	scheme.Checklist = existing.Checklist
+	scheme.ApprovedCount = existing.ApprovedCount
+	scheme.ApprovedAmount = existing.ApprovedAmount
+	scheme.Version = version
+
+	fields, ok := loadCustomFields(w, r, h.CustomFieldRepo)
+	if !ok {
+		return
+	}
+	if err := validation.Scheme(&scheme, fields); err != nil {
+		apierrors.Write(w, r, validationError(err))
+		return
+	}
+
+	err = models.WithTx(h.SchemeRepo.DB, func(tx *sql.Tx) error {
+		if err := h.SchemeRepo.WithTx(tx).Update(&scheme); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityScheme, id,
+			models.AuditActionUpdate, actorFrom(r), existing, &scheme)
+	})
+	if errors.Is(err, models.ErrVersionConflict) {
+		apierrors.Write(w, r, versionConflict())
+		return
+	}
+	if errors.Is(err, models.ErrEffectiveDate) {
+		apierrors.Write(w, r, apierrors.Validation(map[string]string{"effective_from": "must not be before the start of the latest version"}))
+		return
+	}
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to update scheme", err))
+		return
+	}
+	h.SchemeCache.Invalidate()
+
+	response := models.SchemeResponse{
+		Scheme:   scheme,
+		Benefits: scheme.Benefits,
+	}
+
+	setETag(w, scheme.Version)
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// DeleteScheme handles DELETE /api/v1/schemes/{id}
 // @Summary Delete scheme
-// @Description Remove a scheme from the system
+// @Description Remove a scheme from the system with its benefits and checklist. Schemes with applications, including deleted ones, cannot be deleted; archive them instead. Published schemes can only be deleted by admins.
 // @Tags schemes
 // @Accept json
 // @Produce json
 // @Param id path string true "Scheme ID"
 // @Success 204 "No content"
-// @Failure 404 {object} string "Scheme not found"
-// @Failure 500 {object} string "Internal server error"
-// @Router /api/schemes/{id} [delete]
+// @Failure 403 {object} apierrors.APIError "Published schemes can only be deleted by admins"
+// @Failure 404 {object} apierrors.APIError "Scheme not found"
+// @Failure 409 {object} apierrors.APIError "Scheme has applications"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/schemes/{id} [delete]
 func (h *SchemeHandler) DeleteScheme(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
@@ -271,19 +567,35 @@ func (h *SchemeHandler) DeleteScheme(w http.ResponseWriter, r *http.Request) {
 	// Check if scheme exists
 	existing, err := h.SchemeRepo.GetByID(id)
 	if err != nil {
-		http.Error(w, "Failed to get scheme: "+err.Error(), http.StatusInternalServerError)
+		apierrors.Write(w, r, apierrors.Internal("Failed to get scheme", err))
 		return
 	}
 	if existing == nil {
-		http.Error(w, "Scheme not found", http.StatusNotFound)
+		apierrors.Write(w, r, apierrors.NotFound("Scheme not found"))
+		return
+	}
+	if existing.Status == models.SchemePublished && !hasRole(r, auth.RoleAdmin) {
+		apierrors.Write(w, r, apierrors.Forbidden("Deleting published schemes requires the admin role"))
 		return
 	}
 
-	err = h.SchemeRepo.Delete(id)
+	err = models.WithTx(h.SchemeRepo.DB, func(tx *sql.Tx) error {
+		if err := h.SchemeRepo.WithTx(tx).Delete(id); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityScheme, id,
+			models.AuditActionDelete, actorFrom(r), existing, nil)
+	})
+	if errors.Is(err, models.ErrSchemeInUse) {
+		apierrors.Write(w, r, apierrors.Conflict("Scheme has applications").
+			WithDetails("archive the scheme with POST /api/v1/schemes/"+id+"/archive instead"))
+		return
+	}
 	if err != nil {
-		http.Error(w, "Failed to delete scheme: "+err.Error(), http.StatusInternalServerError)
+		apierrors.Write(w, r, apierrors.Internal("Failed to delete scheme", err))
 		return
 	}
+	h.SchemeCache.Invalidate()
 
 	w.WriteHeader(http.StatusNoContent)
 }
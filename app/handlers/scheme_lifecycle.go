@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/auth"
+	"one-client-view-2025tht/app/models"
+)
+
+// canEdit checks that the authenticated user may change a scheme, its
+// benefits and checklist included, writing an error if not: drafts can be
+// changed by caseworkers and admins, published schemes only by admins, and
+// archived schemes by no one
+func canEdit(w http.ResponseWriter, r *http.Request, scheme *models.Scheme) bool {
+	switch {
+	case scheme.Status == models.SchemeArchived:
+		apierrors.Write(w, r, apierrors.Conflict("Scheme is archived").
+			WithDetails("publish the scheme again to change it"))
+		return false
+	case scheme.Status == models.SchemePublished && !hasRole(r, auth.RoleAdmin):
+		apierrors.Write(w, r, apierrors.Forbidden("Changing published schemes requires the admin role"))
+		return false
+	}
+	return true
+}
+
+// canEditScheme loads the scheme with the given ID and checks with canEdit
+// that it may be changed, writing an error if not
+func (h *SchemeHandler) canEditScheme(w http.ResponseWriter, r *http.Request, id string) bool {
+	scheme, err := h.SchemeRepo.GetByID(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get scheme", err))
+		return false
+	}
+	if scheme == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Scheme not found"))
+		return false
+	}
+	return canEdit(w, r, scheme)
+}
+
+// PublishScheme handles POST /api/v1/schemes/{id}/publish
+// @Summary Publish a scheme
+// @Description Publish a draft or archived scheme, offering it to applicants: published schemes are listed publicly, assessed for eligibility and accept applications while active and within their window. Publishing an archived scheme offers it again. Requires the admin role.
+// @Tags schemes
+// @Produce json
+// @Param id path string true "Scheme ID"
+// @Success 200 {object} models.SchemeResponse
+// @Failure 403 {object} apierrors.APIError "Requires the admin role"
+// @Failure 404 {object} apierrors.APIError "Scheme not found"
+// @Failure 409 {object} apierrors.APIError "Scheme is already published"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/schemes/{id}/publish [post]
+func (h *SchemeHandler) PublishScheme(w http.ResponseWriter, r *http.Request) {
+	h.setStatus(w, r, models.SchemePublished, models.AuditActionPublish)
+}
+
+// ArchiveScheme handles POST /api/v1/schemes/{id}/archive
+// @Summary Archive a scheme
+// @Description Archive a draft or published scheme, withdrawing it: archived schemes are no longer listed publicly, assessed for eligibility or open for applications, and cannot be changed. Existing applications are kept and can still be decided. Requires the admin role.
+// @Tags schemes
+// @Produce json
+// @Param id path string true "Scheme ID"
+// @Success 200 {object} models.SchemeResponse
+// @Failure 403 {object} apierrors.APIError "Requires the admin role"
+// @Failure 404 {object} apierrors.APIError "Scheme not found"
+// @Failure 409 {object} apierrors.APIError "Scheme is already archived"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/schemes/{id}/archive [post]
+func (h *SchemeHandler) ArchiveScheme(w http.ResponseWriter, r *http.Request) {
+	h.setStatus(w, r, models.SchemeArchived, models.AuditActionArchive)
+}
+
+// setStatus moves the scheme named in the path to status, recording action in
+// the audit log
+func (h *SchemeHandler) setStatus(w http.ResponseWriter, r *http.Request, status, action string) {
+	if !hasRole(r, auth.RoleAdmin) {
+		apierrors.Write(w, r, apierrors.Forbidden("Publishing and archiving schemes requires the admin role"))
+		return
+	}
+
+	existing, err := h.SchemeRepo.GetByID(mux.Vars(r)["id"])
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get scheme", err))
+		return
+	}
+	if existing == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Scheme not found"))
+		return
+	}
+	if existing.Status == status {
+		apierrors.Write(w, r, apierrors.Conflict("Scheme is already "+status))
+		return
+	}
+
+	scheme := *existing
+	scheme.Status = status
+	err = models.WithTx(h.SchemeRepo.DB, func(tx *sql.Tx) error {
+		if err := h.SchemeRepo.WithTx(tx).SetStatus(&scheme); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityScheme, scheme.ID,
+			action, actorFrom(r), existing, &scheme)
+	})
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to update scheme", err))
+		return
+	}
+	h.SchemeCache.Invalidate()
+
+	response := models.SchemeResponse{
+		Scheme:   scheme,
+		Benefits: scheme.Benefits,
+	}
+
+	setETag(w, scheme.Version)
+	writeJSON(w, r, http.StatusOK, response)
+}
+
+// CloneScheme handles POST /api/v1/schemes/{id}/clone
+// @Summary Clone a scheme
+// @Description Copy a scheme with its criteria, window, limits, benefits and checklist into a new draft named with a " (copy)" suffix, for example to start next year's scheme from this year's. The copy has new IDs, its own version history starting at 1, no approvals and no translations. Schemes in any status can be cloned.
+// @Tags schemes
+// @Produce json
+// @Param id path string true "Scheme ID"
+// @Success 201 {object} models.SchemeResponse
+// @Failure 404 {object} apierrors.APIError "Scheme not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/schemes/{id}/clone [post]
+func (h *SchemeHandler) CloneScheme(w http.ResponseWriter, r *http.Request) {
+	source, err := h.SchemeRepo.GetByID(mux.Vars(r)["id"])
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get scheme", err))
+		return
+	}
+	if source == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Scheme not found"))
+		return
+	}
+
+	scheme := source.Clone()
+	err = models.WithTx(h.SchemeRepo.DB, func(tx *sql.Tx) error {
+		if err := h.SchemeRepo.WithTx(tx).Create(&scheme); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityScheme, scheme.ID,
+			models.AuditActionCreate, actorFrom(r), nil, &scheme)
+	})
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to clone scheme", err))
+		return
+	}
+	h.SchemeCache.Invalidate()
+
+	response := models.SchemeResponse{
+		Scheme:   scheme,
+		Benefits: scheme.Benefits,
+	}
+
+	setETag(w, scheme.Version)
+	writeJSON(w, r, http.StatusCreated, response)
+}
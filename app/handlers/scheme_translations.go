@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/i18n"
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/validation"
+)
+
+// localizeSchemes replaces the names and descriptions of schemes with their
+// translations into the language negotiated for the request, where they have
+// one, and names the language in the Content-Language header
+func localizeSchemes(w http.ResponseWriter, r *http.Request, repo *models.SchemeTranslationRepository, schemes []models.Scheme) error {
+	lang := i18n.FromRequest(r)
+	w.Header().Set("Content-Language", lang)
+	w.Header().Add("Vary", "Accept-Language")
+	if lang == i18n.English || len(schemes) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(schemes))
+	for i, s := range schemes {
+		ids[i] = s.ID
+	}
+	translations, err := repo.GetByLanguage(lang, ids)
+	if err != nil {
+		return err
+	}
+	for i := range schemes {
+		schemes[i] = schemes[i].Localize(translations)
+	}
+	return nil
+}
+
+// translationLanguage reads the language named in the path, writing a 400 if
+// it is not one schemes are translated into
+func translationLanguage(w http.ResponseWriter, r *http.Request) (string, bool) {
+	language := mux.Vars(r)["language"]
+	if language == i18n.English || !i18n.Supported(language) {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid language").
+			WithDetails("language must be one of: "+strings.Join(i18n.Languages[1:], ", ")))
+		return "", false
+	}
+	return language, true
+}
+
+// scheme loads the scheme named in the path, writing a 404 if it does not
+// exist
+func (h *SchemeHandler) scheme(w http.ResponseWriter, r *http.Request) *models.Scheme {
+	scheme, err := h.SchemeRepo.GetByID(mux.Vars(r)["id"])
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get scheme", err))
+		return nil
+	}
+	if scheme == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Scheme not found"))
+		return nil
+	}
+	return scheme
+}
+
+// GetSchemeTranslations handles GET /api/v1/schemes/{id}/translations
+// @Summary List a scheme's translations
+// @Description List the translations of a scheme's name and description into Chinese (zh), Malay (ms) and Tamil (ta). Scheme endpoints serve them to clients whose Accept-Language prefers the language, and English otherwise.
+// @Tags schemes
+// @Produce json
+// @Param id path string true "Scheme ID"
+// @Success 200 {array} models.SchemeTranslation
+// @Failure 404 {object} apierrors.APIError "Scheme not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/schemes/{id}/translations [get]
+func (h *SchemeHandler) GetSchemeTranslations(w http.ResponseWriter, r *http.Request) {
+	scheme := h.scheme(w, r)
+	if scheme == nil {
+		return
+	}
+
+	translations, err := h.TranslationRepo.GetBySchemeID(scheme.ID)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get scheme translations", err))
+		return
+	}
+
+	writeList(w, r, translations, 0)
+}
+
+// PutSchemeTranslation handles PUT /api/v1/schemes/{id}/translations/{language}
+// @Summary Translate a scheme
+// @Description Save the name and description of a scheme in Chinese (zh), Malay (ms) or Tamil (ta), replacing any earlier translation into the language. Translations are not versioned with the scheme's terms, so update them when the English name or description changes.
+// @Tags schemes
+// @Accept json
+// @Produce json
+// @Param id path string true "Scheme ID"
+// @Param language path string true "Language" Enums(zh, ms, ta)
+// @Param translation body models.SchemeTranslationRequest true "Translation"
+// @Success 200 {object} models.SchemeTranslation "Translation replaced"
+// @Success 201 {object} models.SchemeTranslation "Translation created"
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 404 {object} apierrors.APIError "Scheme not found"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/schemes/{id}/translations/{language} [put]
+func (h *SchemeHandler) PutSchemeTranslation(w http.ResponseWriter, r *http.Request) {
+	language, ok := translationLanguage(w, r)
+	if !ok {
+		return
+	}
+	scheme := h.scheme(w, r)
+	if scheme == nil {
+		return
+	}
+
+	var request models.SchemeTranslationRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
+		return
+	}
+	request.Name = strings.TrimSpace(request.Name)
+	request.Description = strings.TrimSpace(request.Description)
+	if err := validation.SchemeTranslationRequest(&request); err != nil {
+		apierrors.Write(w, r, validationError(err))
+		return
+	}
+
+	translation := models.SchemeTranslation{
+		SchemeID:    scheme.ID,
+		Language:    language,
+		Name:        request.Name,
+		Description: request.Description,
+	}
+
+	var existing *models.SchemeTranslation
+	err := models.WithTx(h.TranslationRepo.DB, func(tx *sql.Tx) error {
+		repo := h.TranslationRepo.WithTx(tx)
+		var err error
+		if existing, err = repo.Get(scheme.ID, language); err != nil {
+			return err
+		}
+		if err := repo.Put(&translation); err != nil {
+			return err
+		}
+		action := models.AuditActionCreate
+		if existing != nil {
+			action = models.AuditActionUpdate
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntitySchemeTranslation, scheme.ID,
+			action, actorFrom(r), existing, &translation)
+	})
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to save scheme translation", err))
+		return
+	}
+
+	status := http.StatusCreated
+	if existing != nil {
+		status = http.StatusOK
+	}
+	writeJSON(w, r, status, translation)
+}
+
+// DeleteSchemeTranslation handles DELETE /api/v1/schemes/{id}/translations/{language}
+// @Summary Delete a scheme translation
+// @Description Delete the translation of a scheme into a language, which is then served in English
+// @Tags schemes
+// @Param id path string true "Scheme ID"
+// @Param language path string true "Language" Enums(zh, ms, ta)
+// @Success 204 "No content"
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 404 {object} apierrors.APIError "Scheme or translation not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/schemes/{id}/translations/{language} [delete]
+func (h *SchemeHandler) DeleteSchemeTranslation(w http.ResponseWriter, r *http.Request) {
+	language, ok := translationLanguage(w, r)
+	if !ok {
+		return
+	}
+	scheme := h.scheme(w, r)
+	if scheme == nil {
+		return
+	}
+
+	existing, err := h.TranslationRepo.Get(scheme.ID, language)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get scheme translation", err))
+		return
+	}
+	if existing == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Translation not found"))
+		return
+	}
+
+	err = models.WithTx(h.TranslationRepo.DB, func(tx *sql.Tx) error {
+		if err := h.TranslationRepo.WithTx(tx).Delete(scheme.ID, language); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntitySchemeTranslation, scheme.ID,
+			models.AuditActionDelete, actorFrom(r), existing, nil)
+	})
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to delete scheme translation", err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
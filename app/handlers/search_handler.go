@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/models"
+)
+
+// Limits on search requests
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+	maxSearchTerms     = 10
+)
+
+// SearchHandler handles HTTP requests for searching across entities
+type SearchHandler struct {
+	ApplicantRepo   *models.ApplicantRepository
+	SchemeRepo      *models.SchemeRepository
+	ApplicationRepo *models.ApplicationRepository
+}
+
+// NewSearchHandler creates a new handler with the given repositories
+func NewSearchHandler(applicantRepo *models.ApplicantRepository, schemeRepo *models.SchemeRepository, appRepo *models.ApplicationRepository) *SearchHandler {
+	return &SearchHandler{
+		ApplicantRepo:   applicantRepo,
+		SchemeRepo:      schemeRepo,
+		ApplicationRepo: appRepo,
+	}
+}
+
+// Search handles GET /api/v1/search
+// @Summary Search across entities
+// @Description Find applicants and household members by name, schemes by name and applications by notes. Words match whole and case-insensitively; results matching more of the words rank first.
+// @Tags search
+// @Accept json
+// @Produce json
+// @Param q query string true "Words to search for, e.g. Tan primary school"
+// @Param types query string false "Comma-separated entity types to search (default all): applicant, household_member, scheme, application"
+// @Param limit query int false "Maximum number of results (default 20, max 100)"
+// @Success 200 {array} models.SearchResult
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/search [get]
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	terms := models.SearchTerms(query.Get("q"))
+	if len(terms) == 0 {
+		apierrors.Write(w, r, apierrors.BadRequest("q is required"))
+		return
+	}
+	if len(terms) > maxSearchTerms {
+		apierrors.Write(w, r, apierrors.BadRequest("q must have at most "+strconv.Itoa(maxSearchTerms)+" words"))
+		return
+	}
+
+	types := models.SearchTypes
+	if typesStr := query.Get("types"); typesStr != "" {
+		types = nil
+		for _, t := range strings.Split(typesStr, ",") {
+			t = strings.TrimSpace(t)
+			if !slices.Contains(models.SearchTypes, t) {
+				apierrors.Write(w, r, apierrors.BadRequest("Invalid types").
+					WithDetails("must be one of: "+strings.Join(models.SearchTypes, ", ")))
+				return
+			}
+			types = append(types, t)
+		}
+	}
+
+	limit := defaultSearchLimit
+	if limitStr := query.Get("limit"); limitStr != "" {
+		n, err := strconv.Atoi(limitStr)
+		if err != nil || n < 1 || n > maxSearchLimit {
+			apierrors.Write(w, r, apierrors.BadRequest("limit must be between 1 and 100"))
+			return
+		}
+		limit = n
+	}
+
+	results := []models.SearchResult{}
+	for _, t := range types {
+		found, err := h.search(t, terms, limit)
+		if err != nil {
+			apierrors.Write(w, r, apierrors.Internal("Failed to search", err))
+			return
+		}
+		results = append(results, found...)
+	}
+
+	// Best matches first, keeping the order each type was found in for ties
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	writeList(w, r, results, limit)
+}
+
+// search finds up to limit entities of one type matching the terms
+func (h *SearchHandler) search(entityType string, terms []string, limit int) ([]models.SearchResult, error) {
+	var results []models.SearchResult
+
+	// add appends a result if the highlighted field matches any term. Database
+	// matches on schemes and notes are by substring, so some are dropped here.
+	add := func(result models.SearchResult, field, text string) {
+		highlighted, score := models.Highlight(text, terms)
+		if score == 0 {
+			return
+		}
+		result.Type = entityType
+		result.Score = score
+		result.Highlights = map[string]string{field: highlighted}
+		results = append(results, result)
+	}
+
+	switch entityType {
+	case models.SearchTypeApplicant:
+		applicants, err := h.ApplicantRepo.Search(terms, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range applicants {
+			add(models.SearchResult{ID: a.ID, Title: a.Name}, "name", a.Name)
+		}
+	case models.SearchTypeHouseholdMember:
+		members, err := h.ApplicantRepo.SearchHouseholdMembers(terms, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range members {
+			add(models.SearchResult{ID: m.ID, Title: m.Name, ApplicantID: m.ApplicantID}, "name", m.Name)
+		}
+	case models.SearchTypeScheme:
+		schemes, err := h.SchemeRepo.Search(terms, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range schemes {
+			add(models.SearchResult{ID: s.ID, Title: s.Name}, "name", s.Name)
+		}
+	case models.SearchTypeApplication:
+		applications, err := h.ApplicationRepo.Search(terms, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range applications {
+			result := models.SearchResult{ID: a.ID, ApplicantID: a.ApplicantID, SchemeID: a.SchemeID}
+			if a.Applicant != nil {
+				result.Title = a.Applicant.Name
+			}
+			add(result, "notes", a.Notes)
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,407 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/jobs"
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/notify"
+	"one-client-view-2025tht/app/validation"
+)
+
+// TaskHandler handles requests for tasks, the follow-ups to be done for
+// applicants and their applications
+type TaskHandler struct {
+	TaskRepo        *models.TaskRepository
+	ApplicantRepo   *models.ApplicantRepository
+	ApplicationRepo *models.ApplicationRepository
+	UserRepo        *models.UserRepository
+	AuditRepo       *models.AuditRepository
+	Notifier        *notify.Notifier // Emails assignees reminders; may be nil
+	ReminderLead    time.Duration    // How long before a task is due its assignee is reminded
+}
+
+// NewTaskHandler creates a new handler with the given repositories and notifier
+func NewTaskHandler(taskRepo *models.TaskRepository, applicantRepo *models.ApplicantRepository, applicationRepo *models.ApplicationRepository, userRepo *models.UserRepository, auditRepo *models.AuditRepository, notifier *notify.Notifier, reminderLead time.Duration) *TaskHandler {
+	return &TaskHandler{
+		TaskRepo:        taskRepo,
+		ApplicantRepo:   applicantRepo,
+		ApplicationRepo: applicationRepo,
+		UserRepo:        userRepo,
+		AuditRepo:       auditRepo,
+		Notifier:        notifier,
+		ReminderLead:    reminderLead,
+	}
+}
+
+// GetTasks handles GET /api/v1/tasks
+// @Summary List tasks
+// @Description List tasks, soonest due first, with tasks without a due date last
+// @Tags tasks
+// @Produce json
+// @Param status query string false "Task status" Enums(open, done, cancelled)
+// @Param assigned_to query string false "User ID the tasks are assigned to, me for the authenticated user, or none for unassigned tasks"
+// @Param applicant_id query string false "Applicant ID"
+// @Param application_id query string false "Application ID"
+// @Success 200 {array} models.Task
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/tasks [get]
+func (h *TaskHandler) GetTasks(w http.ResponseWriter, r *http.Request) {
+	filter, apiErr := taskFilterParams(r)
+	if apiErr != nil {
+		apierrors.Write(w, r, apiErr)
+		return
+	}
+
+	tasks, err := h.TaskRepo.Find(filter)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get tasks", err))
+		return
+	}
+
+	writeList(w, r, tasks, 0)
+}
+
+// GetOverdueTasks handles GET /api/v1/tasks/overdue
+// @Summary List overdue tasks
+// @Description List the open tasks past their due date, the longest overdue first
+// @Tags tasks
+// @Produce json
+// @Param assigned_to query string false "User ID the tasks are assigned to, me for the authenticated user, or none for unassigned tasks"
+// @Param applicant_id query string false "Applicant ID"
+// @Param application_id query string false "Application ID"
+// @Success 200 {array} models.Task
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/tasks/overdue [get]
+func (h *TaskHandler) GetOverdueTasks(w http.ResponseWriter, r *http.Request) {
+	filter, apiErr := taskFilterParams(r)
+	if apiErr != nil {
+		apierrors.Write(w, r, apiErr)
+		return
+	}
+
+	tasks, err := h.TaskRepo.Overdue(filter, time.Now())
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get overdue tasks", err))
+		return
+	}
+
+	writeList(w, r, tasks, 0)
+}
+
+// GetTask handles GET /api/v1/tasks/{id}
+// @Summary Get task
+// @Description Get a task by ID
+// @Tags tasks
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} models.Task
+// @Failure 404 {object} apierrors.APIError "Task not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/tasks/{id} [get]
+func (h *TaskHandler) GetTask(w http.ResponseWriter, r *http.Request) {
+	task := h.task(w, r)
+	if task == nil {
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, task)
+}
+
+// CreateTask handles POST /api/v1/tasks
+// @Summary Create task
+// @Description Create a follow-up for an applicant, or for one of their applications, optionally due by a date and assigned to a caseworker or admin. The assignee is emailed a reminder once the task is due soon. The authenticated user is recorded as its creator.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param task body models.TaskRequest true "Task"
+// @Success 201 {object} models.Task
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 404 {object} apierrors.APIError "Applicant or application not found"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/tasks [post]
+func (h *TaskHandler) CreateTask(w http.ResponseWriter, r *http.Request) {
+	var request models.TaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
+		return
+	}
+
+	actor := actorFrom(r)
+	task := models.Task{CreatedBy: actor.ID}
+	if !h.apply(w, r, &request, &task) {
+		return
+	}
+
+	err := models.WithTx(h.TaskRepo.DB, func(tx *sql.Tx) error {
+		if err := h.TaskRepo.WithTx(tx).Create(&task); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityTask, task.ID,
+			models.AuditActionCreate, actor, nil, &task)
+	})
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to create task", err))
+		return
+	}
+
+	writeJSON(w, r, http.StatusCreated, task)
+}
+
+// UpdateTask handles PUT /api/v1/tasks/{id}
+// @Summary Update task
+// @Description Replace a task, for example to mark it done or move its due date. Changing the due date or assignee lets the assignee be reminded again.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param task body models.TaskRequest true "Task"
+// @Success 200 {object} models.Task
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 404 {object} apierrors.APIError "Task, applicant or application not found"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/tasks/{id} [put]
+func (h *TaskHandler) UpdateTask(w http.ResponseWriter, r *http.Request) {
+	existing := h.task(w, r)
+	if existing == nil {
+		return
+	}
+
+	var request models.TaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
+		return
+	}
+
+	task := *existing
+	if !h.apply(w, r, &request, &task) {
+		return
+	}
+
+	err := models.WithTx(h.TaskRepo.DB, func(tx *sql.Tx) error {
+		if err := h.TaskRepo.WithTx(tx).Update(&task); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityTask, task.ID,
+			models.AuditActionUpdate, actorFrom(r), existing, &task)
+	})
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to update task", err))
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, task)
+}
+
+// DeleteTask handles DELETE /api/v1/tasks/{id}
+// @Summary Delete task
+// @Description Delete a task. Tasks no longer needed can instead be cancelled, keeping them on record.
+// @Tags tasks
+// @Param id path string true "Task ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} apierrors.APIError "Task not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/tasks/{id} [delete]
+func (h *TaskHandler) DeleteTask(w http.ResponseWriter, r *http.Request) {
+	existing := h.task(w, r)
+	if existing == nil {
+		return
+	}
+
+	err := models.WithTx(h.TaskRepo.DB, func(tx *sql.Tx) error {
+		if err := h.TaskRepo.WithTx(tx).Delete(existing.ID); err != nil {
+			return err
+		}
+		return h.AuditRepo.WithTx(tx).Record(models.AuditEntityTask, existing.ID,
+			models.AuditActionDelete, actorFrom(r), existing, nil)
+	})
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to delete task", err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RunTaskReminderJob is the handler of tasks.remind jobs, which email the
+// assignees of open tasks due within the reminder lead, or overdue, that they
+// have not yet been reminded of
+func (h *TaskHandler) RunTaskReminderJob(ctx context.Context, job *models.Job) (interface{}, error) {
+	var req models.TaskReminderJob
+	if err := json.Unmarshal(job.Payload, &req); err != nil || req.AsOf.IsZero() {
+		return nil, jobs.Permanent(fmt.Errorf("invalid payload: %v", err))
+	}
+
+	tasks, err := h.TaskRepo.Find(models.TaskFilter{
+		Status:      models.TaskOpen,
+		DueBefore:   req.AsOf.Add(h.ReminderLead),
+		NotReminded: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := models.TaskReminderResult{AsOf: req.AsOf}
+	assignees := map[string]*models.User{}
+	for i := range tasks {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		t := &tasks[i]
+		if t.AssignedTo == "" {
+			continue
+		}
+
+		assignee, found := assignees[t.AssignedTo]
+		if !found {
+			if assignee, err = h.UserRepo.GetByID(t.AssignedTo); err != nil {
+				return nil, err
+			}
+			assignees[t.AssignedTo] = assignee
+		}
+		if assignee == nil || !h.Notifier.TaskReminder(t, assignee) {
+			result.Skipped++
+			continue
+		}
+
+		if err := h.TaskRepo.MarkReminded(t, time.Now()); err != nil {
+			return nil, err
+		}
+		result.Reminded++
+	}
+
+	return result, nil
+}
+
+// task loads the task named in the path, writing a 404 if it does not exist
+func (h *TaskHandler) task(w http.ResponseWriter, r *http.Request) *models.Task {
+	task, err := h.TaskRepo.GetByID(mux.Vars(r)["id"])
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get task", err))
+		return nil
+	}
+	if task == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Task not found"))
+		return nil
+	}
+	return task
+}
+
+// apply validates a task request and copies it onto the task, taking the
+// applicant from the application if one is given. Completing or cancelling
+// the task records when, and changing its due date or assignee clears the
+// reminder sent. It writes an error response and returns false if the request
+// is invalid.
+func (h *TaskHandler) apply(w http.ResponseWriter, r *http.Request, request *models.TaskRequest, t *models.Task) bool {
+	if request.Status == "" {
+		request.Status = models.TaskOpen
+	}
+	if err := validation.TaskRequest(request); err != nil {
+		apierrors.Write(w, r, validationError(err))
+		return false
+	}
+
+	if request.ApplicationID != "" {
+		application, err := h.ApplicationRepo.GetByID(request.ApplicationID)
+		if err != nil {
+			apierrors.Write(w, r, apierrors.Internal("Failed to get application", err))
+			return false
+		}
+		if application == nil {
+			apierrors.Write(w, r, apierrors.NotFound("Application not found"))
+			return false
+		}
+		if request.ApplicantID != "" && request.ApplicantID != application.ApplicantID {
+			apierrors.Write(w, r, validationError(validation.Errors{"applicant_id": "must be the applicant of the application"}))
+			return false
+		}
+		request.ApplicantID = application.ApplicantID
+	} else {
+		applicant, err := h.ApplicantRepo.GetByID(request.ApplicantID)
+		if err != nil {
+			apierrors.Write(w, r, apierrors.Internal("Failed to get applicant", err))
+			return false
+		}
+		if applicant == nil {
+			apierrors.Write(w, r, apierrors.NotFound("Applicant not found"))
+			return false
+		}
+	}
+
+	if request.AssignedTo != "" {
+		assignee, err := assignableUser(h.UserRepo, request.AssignedTo)
+		if err != nil {
+			apierrors.Write(w, r, apierrors.Internal("Failed to get user", err))
+			return false
+		}
+		if assignee == nil {
+			apierrors.Write(w, r, validationError(validation.Errors{"assigned_to": "must be a caseworker or admin"}))
+			return false
+		}
+	}
+
+	if request.AssignedTo != t.AssignedTo || !sameTime(request.DueDate, t.DueDate) {
+		t.RemindedAt = nil
+	}
+	switch {
+	case request.Status == models.TaskOpen:
+		t.CompletedAt = nil
+	case t.Status != request.Status || t.CompletedAt == nil:
+		now := time.Now()
+		t.CompletedAt = &now
+	}
+
+	t.ApplicantID = request.ApplicantID
+	t.ApplicationID = request.ApplicationID
+	t.Title = request.Title
+	t.Description = request.Description
+	t.DueDate = request.DueDate
+	t.AssignedTo = request.AssignedTo
+	t.Status = request.Status
+	return true
+}
+
+// sameTime reports whether two optional times are both unset or equal
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// taskFilterParams parses the query parameters of the task lists
+func taskFilterParams(r *http.Request) (models.TaskFilter, *apierrors.APIError) {
+	query := r.URL.Query()
+	filter := models.TaskFilter{
+		Status:        query.Get("status"),
+		ApplicantID:   query.Get("applicant_id"),
+		ApplicationID: query.Get("application_id"),
+	}
+	filter.AssignedTo, filter.Unassigned = assignedToParam(r)
+
+	if filter.Status != "" && !slices.Contains(models.TaskStatuses, filter.Status) {
+		return filter, apierrors.BadRequest("Invalid status").
+			WithDetails("must be one of: " + strings.Join(models.TaskStatuses, ", "))
+	}
+
+	return filter, nil
+}
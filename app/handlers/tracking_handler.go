@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/auth"
+	"one-client-view-2025tht/app/i18n"
+	"one-client-view-2025tht/app/models"
+)
+
+// nextSteps tells applicants what happens next at each application status,
+// in English; the catalogue holds the translations
+var nextSteps = map[string]string{
+	"pending":   "Your application is being reviewed. We will email you once a decision has been made.",
+	"approved":  "Your application has been approved. We will contact you about receiving your benefit.",
+	"rejected":  "Your application was not approved. Please contact us if you would like to know more or to apply again.",
+	"withdrawn": "Your application has been withdrawn and will not be considered further. You are welcome to apply again if your circumstances change.",
+}
+
+// TrackingHandler handles requests for applicants' tracking of their
+// applications
+type TrackingHandler struct {
+	ApplicationRepo *models.ApplicationRepository
+	Tokens          *auth.TrackingTokens
+}
+
+// NewTrackingHandler creates a new handler with the given repository and
+// token issuer
+func NewTrackingHandler(applicationRepo *models.ApplicationRepository, tokens *auth.TrackingTokens) *TrackingHandler {
+	return &TrackingHandler{
+		ApplicationRepo: applicationRepo,
+		Tokens:          tokens,
+	}
+}
+
+// GetTrackingToken handles GET /api/v1/applications/{id}/tracking-token
+// @Summary Get an application's tracking token
+// @Description Retrieve the token the applicant can check the application's status with at /api/v1/track/{token}, without an account. The token is also included in the email sent when the application is submitted. Tokens do not expire, and the same token is returned every time.
+// @Tags applications
+// @Produce json
+// @Param id path string true "Application ID"
+// @Success 200 {object} models.TrackingToken
+// @Failure 404 {object} apierrors.APIError "Application not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/applications/{id}/tracking-token [get]
+func (h *TrackingHandler) GetTrackingToken(w http.ResponseWriter, r *http.Request) {
+	application, err := h.ApplicationRepo.GetByID(mux.Vars(r)["id"])
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get application", err))
+		return
+	}
+	if application == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Application not found"))
+		return
+	}
+
+	token, err := h.Tokens.Issue(application.ID)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to issue tracking token", err))
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, models.TrackingToken{ApplicationID: application.ID, Token: token})
+}
+
+// TrackApplication handles GET /api/v1/track/{token}
+// @Summary Track an application
+// @Description Retrieve the status of an application and what happens next, with its tracking token and without signing in, in the language preferred by Accept-Language: English (en), Chinese (zh), Malay (ms) or Tamil (ta). Nothing else about the application or applicant is returned. Tokens that are invalid or whose application has been deleted are not found.
+// @Tags track
+// @Produce json
+// @Param token path string true "Tracking token"
+// @Param Accept-Language header string false "Preferred languages: en, zh, ms or ta"
+// @Success 200 {object} models.ApplicationTracking
+// @Failure 404 {object} apierrors.APIError "Application not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Router /api/v1/track/{token} [get]
+func (h *TrackingHandler) TrackApplication(w http.ResponseWriter, r *http.Request) {
+	id, err := h.Tokens.Verify(mux.Vars(r)["token"])
+	if err != nil {
+		apierrors.Write(w, r, apierrors.NotFound("Application not found"))
+		return
+	}
+
+	application, err := h.ApplicationRepo.GetByID(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get application", err))
+		return
+	}
+	if application == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Application not found"))
+		return
+	}
+
+	lang := i18n.FromRequest(r)
+	tracking := models.ApplicationTracking{
+		Status:    application.Status,
+		Label:     i18n.Label(lang, i18n.LabelApplicationStatus, application.Status),
+		NextSteps: i18n.Message(lang, nextSteps[application.Status]),
+	}
+
+	w.Header().Set("Content-Language", lang)
+	w.Header().Add("Vary", "Accept-Language")
+	writeJSON(w, r, http.StatusOK, tracking)
+}
@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/auth"
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/validation"
+)
+
+// Limits on the number of deliveries returned per request
+const (
+	defaultDeliveryLimit = 100
+	maxDeliveryLimit     = 1000
+)
+
+// WebhookHandler handles HTTP requests related to webhooks. All of its
+// endpoints require the admin role.
+type WebhookHandler struct {
+	WebhookRepo *models.WebhookRepository
+}
+
+// NewWebhookHandler creates a new handler with the given repository
+func NewWebhookHandler(repo *models.WebhookRepository) *WebhookHandler {
+	return &WebhookHandler{WebhookRepo: repo}
+}
+
+// requireAdmin writes a 403 and returns false unless the user is an admin
+func requireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if !hasRole(r, auth.RoleAdmin) {
+		apierrors.Write(w, r, apierrors.Forbidden("Managing webhooks requires the admin role"))
+		return false
+	}
+	return true
+}
+
+// GetWebhooks handles GET /api/v1/webhooks
+// @Summary Get all webhooks
+// @Description Retrieve all registered webhooks. Secrets are not included. Requires the admin role.
+// @Tags webhooks
+// @Produce json
+// @Success 200 {array} models.Webhook
+// @Failure 403 {object} apierrors.APIError "Requires the admin role"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/webhooks [get]
+func (h *WebhookHandler) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	webhooks, err := h.WebhookRepo.GetAll()
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get webhooks", err))
+		return
+	}
+
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+
+	writeList(w, r, webhooks, 0)
+}
+
+// GetWebhook handles GET /api/v1/webhooks/{id}
+// @Summary Get webhook by ID
+// @Description Retrieve a registered webhook. The secret is not included. Requires the admin role.
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} models.Webhook
+// @Failure 403 {object} apierrors.APIError "Requires the admin role"
+// @Failure 404 {object} apierrors.APIError "Webhook not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	webhook, err := h.WebhookRepo.GetByID(mux.Vars(r)["id"])
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get webhook", err))
+		return
+	}
+	if webhook == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Webhook not found"))
+		return
+	}
+
+	webhook.Secret = ""
+
+	writeJSON(w, r, http.StatusOK, webhook)
+}
+
+// CreateWebhook handles POST /api/v1/webhooks
+// @Summary Register a webhook
+// @Description Register a URL to be notified of the given events. A signing secret is generated unless one is supplied, and is only returned in this response. Requires the admin role.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param webhook body object{url=string,events=[]string,secret=string} true "Webhook URL, events and optional secret"
+// @Success 201 {object} models.Webhook
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 403 {object} apierrors.APIError "Requires the admin role"
+// @Failure 422 {object} apierrors.APIError "Validation failed"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	var webhook models.Webhook
+	if err := json.NewDecoder(r.Body).Decode(&webhook); err != nil {
+		apierrors.Write(w, r, apierrors.BadRequest("Invalid request body").WithDetails(err.Error()))
+		return
+	}
+
+	webhook.ID = ""
+	webhook.Active = true
+	if webhook.Secret == "" {
+		secret, err := newWebhookSecret()
+		if err != nil {
+			apierrors.Write(w, r, apierrors.Internal("Failed to generate webhook secret", err))
+			return
+		}
+		webhook.Secret = secret
+	}
+
+	if err := validation.Webhook(&webhook); err != nil {
+		apierrors.Write(w, r, validationError(err))
+		return
+	}
+
+	if err := h.WebhookRepo.Create(&webhook); err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to create webhook", err))
+		return
+	}
+
+	writeJSON(w, r, http.StatusCreated, webhook)
+}
+
+// DeleteWebhook handles DELETE /api/v1/webhooks/{id}
+// @Summary Delete webhook
+// @Description Remove a webhook and discard its queued deliveries. Requires the admin role.
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 204 "No content"
+// @Failure 403 {object} apierrors.APIError "Requires the admin role"
+// @Failure 404 {object} apierrors.APIError "Webhook not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	webhook, err := h.WebhookRepo.GetByID(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get webhook", err))
+		return
+	}
+	if webhook == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Webhook not found"))
+		return
+	}
+
+	if err := h.WebhookRepo.Delete(id); err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to delete webhook", err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetWebhookDeliveries handles GET /api/v1/webhooks/{id}/deliveries
+// @Summary Get webhook deliveries
+// @Description Retrieve the most recent queued and attempted deliveries to a webhook, newest first. Requires the admin role.
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param limit query int false "Maximum number of deliveries (default 100, max 1000)"
+// @Success 200 {array} models.WebhookDelivery
+// @Failure 400 {object} apierrors.APIError "Bad request"
+// @Failure 403 {object} apierrors.APIError "Requires the admin role"
+// @Failure 404 {object} apierrors.APIError "Webhook not found"
+// @Failure 500 {object} apierrors.APIError "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+
+	limit := defaultDeliveryLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxDeliveryLimit {
+			apierrors.Write(w, r, apierrors.BadRequest("limit must be between 1 and 1000"))
+			return
+		}
+		limit = n
+	}
+
+	id := mux.Vars(r)["id"]
+	webhook, err := h.WebhookRepo.GetByID(id)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get webhook", err))
+		return
+	}
+	if webhook == nil {
+		apierrors.Write(w, r, apierrors.NotFound("Webhook not found"))
+		return
+	}
+
+	deliveries, err := h.WebhookRepo.GetDeliveries(id, limit)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to get webhook deliveries", err))
+		return
+	}
+
+	writeList(w, r, deliveries, limit)
+}
+
+// newWebhookSecret generates a random signing secret
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,407 @@
+package i18n
+
+// translations maps a language to the translation of one message or label
+type translations map[string]string
+
+// messages holds the translations of API error and validation messages,
+// keyed by the English message. {0}, {1}, ... stand for values, such as
+// lengths or field names, that are kept as they are.
+var messages = map[string]translations{
+	// Validation messages
+	"is required": {
+		Chinese: "为必填项",
+		Malay:   "wajib diisi",
+		Tamil:   "கட்டாயமானது",
+	},
+	"must be one of: {0}": {
+		Chinese: "必须是以下之一：{0}",
+		Malay:   "mestilah salah satu daripada: {0}",
+		Tamil:   "இவற்றில் ஒன்றாக இருக்க வேண்டும்: {0}",
+	},
+	"must not be in the future": {
+		Chinese: "不能是将来的日期",
+		Malay:   "tidak boleh pada masa hadapan",
+		Tamil:   "எதிர்காலத் தேதியாக இருக்கக்கூடாது",
+	},
+	"must not be negative": {
+		Chinese: "不能为负数",
+		Malay:   "tidak boleh negatif",
+		Tamil:   "எதிர்மறையாக இருக்கக்கூடாது",
+	},
+	"must not be empty": {
+		Chinese: "不能为空",
+		Malay:   "tidak boleh kosong",
+		Tamil:   "காலியாக இருக்கக்கூடாது",
+	},
+	"must be a valid email address": {
+		Chinese: "必须是有效的电子邮件地址",
+		Malay:   "mestilah alamat e-mel yang sah",
+		Tamil:   "சரியான மின்னஞ்சல் முகவரியாக இருக்க வேண்டும்",
+	},
+	"must be a valid phone number": {
+		Chinese: "必须是有效的电话号码",
+		Malay:   "mestilah nombor telefon yang sah",
+		Tamil:   "சரியான தொலைபேசி எண்ணாக இருக்க வேண்டும்",
+	},
+	"must be a valid NRIC or FIN": {
+		Chinese: "必须是有效的 NRIC 或 FIN",
+		Malay:   "mestilah NRIC atau FIN yang sah",
+		Tamil:   "சரியான NRIC அல்லது FIN ஆக இருக்க வேண்டும்",
+	},
+	"must be a valid six-digit postal code": {
+		Chinese: "必须是有效的六位数邮政编码",
+		Malay:   "mestilah poskod enam digit yang sah",
+		Tamil:   "சரியான ஆறு இலக்க அஞ்சல் குறியீடாக இருக்க வேண்டும்",
+	},
+	"must be at most {0} characters": {
+		Chinese: "不能超过 {0} 个字符",
+		Malay:   "mestilah tidak melebihi {0} aksara",
+		Tamil:   "அதிகபட்சம் {0} எழுத்துகள் இருக்க வேண்டும்",
+	},
+	"must have at most {0} tags": {
+		Chinese: "最多只能有 {0} 个标签",
+		Malay:   "mestilah tidak melebihi {0} tag",
+		Tamil:   "அதிகபட்சம் {0} குறிச்சொற்கள் இருக்க வேண்டும்",
+	},
+	"must be 1 to 50 lowercase letters, digits or hyphens": {
+		Chinese: "必须由 1 至 50 个小写字母、数字或连字符组成",
+		Malay:   "mestilah 1 hingga 50 huruf kecil, digit atau tanda sempang",
+		Tamil:   "1 முதல் 50 சிறிய எழுத்துகள், இலக்கங்கள் அல்லது இணைப்புக்குறிகளாக இருக்க வேண்டும்",
+	},
+	"must not be less than amount": {
+		Chinese: "不能少于金额",
+		Malay:   "tidak boleh kurang daripada amaun",
+		Tamil:   "தொகையை விடக் குறைவாக இருக்கக்கூடாது",
+	},
+	"must be a caseworker or admin": {
+		Chinese: "必须是个案工作者或管理员",
+		Malay:   "mestilah pekerja kes atau pentadbir",
+		Tamil:   "வழக்குப் பணியாளர் அல்லது நிர்வாகியாக இருக்க வேண்டும்",
+	},
+	"must be the applicant of the application": {
+		Chinese: "必须是该申请的申请人",
+		Malay:   "mestilah pemohon bagi permohonan tersebut",
+		Tamil:   "விண்ணப்பத்தின் விண்ணப்பதாரராக இருக்க வேண்டும்",
+	},
+	"must be a comment on the application": {
+		Chinese: "必须是该申请的评论",
+		Malay:   "mestilah ulasan pada permohonan tersebut",
+		Tamil:   "விண்ணப்பத்தின் கருத்தாக இருக்க வேண்டும்",
+	},
+	"must be between {0} and {1}": {
+		Chinese: "必须介于 {0} 和 {1} 之间",
+		Malay:   "mestilah antara {0} dan {1}",
+		Tamil:   "{0} முதல் {1} வரை இருக்க வேண்டும்",
+	},
+	"is required for recurring benefits": {
+		Chinese: "定期福利必须填写",
+		Malay:   "diperlukan untuk faedah berulang",
+		Tamil:   "தொடர் நலன்களுக்குத் தேவை",
+	},
+	"must not be set for one-off benefits": {
+		Chinese: "一次性福利不能填写",
+		Malay:   "tidak boleh ditetapkan untuk faedah sekali sahaja",
+		Tamil:   "ஒருமுறை நலன்களுக்கு அமைக்கக்கூடாது",
+	},
+	"must not be before {0}": {
+		Chinese: "不能早于 {0}",
+		Malay:   "tidak boleh sebelum {0}",
+		Tamil:   "{0}-க்கு முன்னதாக இருக்கக்கூடாது",
+	},
+	"must be after {0}": {
+		Chinese: "必须晚于 {0}",
+		Malay:   "mestilah selepas {0}",
+		Tamil:   "{0}-க்குப் பிறகு இருக்க வேண்டும்",
+	},
+	"must not be the target applicant": {
+		Chinese: "不能是目标申请人",
+		Malay:   "tidak boleh sama dengan pemohon sasaran",
+		Tamil:   "இலக்கு விண்ணப்பதாரராக இருக்கக்கூடாது",
+	},
+	"must be an absolute http or https URL": {
+		Chinese: "必须是完整的 http 或 https 网址",
+		Malay:   "mestilah URL http atau https yang lengkap",
+		Tamil:   "முழுமையான http அல்லது https URL ஆக இருக்க வேண்டும்",
+	},
+	"is only allowed when approving": {
+		Chinese: "仅在批准时允许",
+		Malay:   "hanya dibenarkan semasa meluluskan",
+		Tamil:   "அங்கீகரிக்கும்போது மட்டுமே அனுமதிக்கப்படும்",
+	},
+	"cannot be changed directly; approve or reject the application instead": {
+		Chinese: "不能直接更改；请批准或拒绝该申请",
+		Malay:   "tidak boleh diubah secara langsung; luluskan atau tolak permohonan itu",
+		Tamil:   "நேரடியாக மாற்ற முடியாது; அதற்குப் பதிலாக விண்ணப்பத்தை அங்கீகரிக்கவும் அல்லது நிராகரிக்கவும்",
+	},
+	"is not a defined custom field": {
+		Chinese: "不是已定义的自定义字段",
+		Malay:   "bukan medan tersuai yang ditakrifkan",
+		Tamil:   "வரையறுக்கப்பட்ட தனிப்பயன் புலம் அல்ல",
+	},
+	"must be a string": {
+		Chinese: "必须是文本",
+		Malay:   "mestilah teks",
+		Tamil:   "உரையாக இருக்க வேண்டும்",
+	},
+	"must be a number": {
+		Chinese: "必须是数字",
+		Malay:   "mestilah nombor",
+		Tamil:   "எண்ணாக இருக்க வேண்டும்",
+	},
+	"must be true or false": {
+		Chinese: "必须是 true 或 false",
+		Malay:   "mestilah true atau false",
+		Tamil:   "true அல்லது false ஆக இருக்க வேண்டும்",
+	},
+	"must be at least {0}": {
+		Chinese: "不能小于 {0}",
+		Malay:   "mestilah sekurang-kurangnya {0}",
+		Tamil:   "குறைந்தபட்சம் {0} ஆக இருக்க வேண்டும்",
+	},
+	"must be at most {0}": {
+		Chinese: "不能大于 {0}",
+		Malay:   "mestilah tidak melebihi {0}",
+		Tamil:   "அதிகபட்சம் {0} ஆக இருக்க வேண்டும்",
+	},
+
+	// Error messages
+	"Validation failed": {
+		Chinese: "验证失败",
+		Malay:   "Pengesahan gagal",
+		Tamil:   "சரிபார்ப்பு தோல்வியடைந்தது",
+	},
+	"Invalid request body": {
+		Chinese: "请求内容无效",
+		Malay:   "Kandungan permintaan tidak sah",
+		Tamil:   "கோரிக்கை உள்ளடக்கம் தவறானது",
+	},
+	"Invalid username or password": {
+		Chinese: "用户名或密码无效",
+		Malay:   "Nama pengguna atau kata laluan tidak sah",
+		Tamil:   "பயனர்பெயர் அல்லது கடவுச்சொல் தவறானது",
+	},
+	"Invalid or expired code": {
+		Chinese: "验证码无效或已过期",
+		Malay:   "Kod tidak sah atau telah tamat tempoh",
+		Tamil:   "குறியீடு தவறானது அல்லது காலாவதியானது",
+	},
+	"Not available to applicants": {
+		Chinese: "申请人无法使用此功能",
+		Malay:   "Tidak tersedia untuk pemohon",
+		Tamil:   "விண்ணப்பதாரர்களுக்குக் கிடைக்காது",
+	},
+	"Missing bearer token": {
+		Chinese: "缺少访问令牌",
+		Malay:   "Token akses tiada",
+		Tamil:   "அணுகல் டோக்கன் இல்லை",
+	},
+	"Invalid token": {
+		Chinese: "访问令牌无效",
+		Malay:   "Token akses tidak sah",
+		Tamil:   "அணுகல் டோக்கன் தவறானது",
+	},
+	"Resource not found": {
+		Chinese: "找不到资源",
+		Malay:   "Sumber tidak ditemui",
+		Tamil:   "வளம் கண்டுபிடிக்கப்படவில்லை",
+	},
+	"Method not allowed": {
+		Chinese: "不允许使用此方法",
+		Malay:   "Kaedah tidak dibenarkan",
+		Tamil:   "இந்த முறை அனுமதிக்கப்படவில்லை",
+	},
+	"Applicant not found": {
+		Chinese: "找不到申请人",
+		Malay:   "Pemohon tidak ditemui",
+		Tamil:   "விண்ணப்பதாரர் கண்டுபிடிக்கப்படவில்லை",
+	},
+	"Application not found": {
+		Chinese: "找不到申请",
+		Malay:   "Permohonan tidak ditemui",
+		Tamil:   "விண்ணப்பம் கண்டுபிடிக்கப்படவில்லை",
+	},
+	"Scheme not found": {
+		Chinese: "找不到援助计划",
+		Malay:   "Skim tidak ditemui",
+		Tamil:   "திட்டம் கண்டுபிடிக்கப்படவில்லை",
+	},
+	"Consent not found": {
+		Chinese: "找不到同意记录",
+		Malay:   "Persetujuan tidak ditemui",
+		Tamil:   "ஒப்புதல் கண்டுபிடிக்கப்படவில்லை",
+	},
+	"Comment not found": {
+		Chinese: "找不到评论",
+		Malay:   "Ulasan tidak ditemui",
+		Tamil:   "கருத்து கண்டுபிடிக்கப்படவில்லை",
+	},
+	"Referral not found": {
+		Chinese: "找不到转介记录",
+		Malay:   "Rujukan tidak ditemui",
+		Tamil:   "பரிந்துரை கண்டுபிடிக்கப்படவில்லை",
+	},
+	"Custom field not found": {
+		Chinese: "找不到自定义字段",
+		Malay:   "Medan tersuai tidak ditemui",
+		Tamil:   "தனிப்பயன் புலம் கண்டுபிடிக்கப்படவில்லை",
+	},
+	"Task not found": {
+		Chinese: "找不到任务",
+		Malay:   "Tugasan tidak ditemui",
+		Tamil:   "பணி கண்டுபிடிக்கப்படவில்லை",
+	},
+	"Translation not found": {
+		Chinese: "找不到翻译",
+		Malay:   "Terjemahan tidak ditemui",
+		Tamil:   "மொழிபெயர்ப்பு கண்டுபிடிக்கப்படவில்லை",
+	},
+	"Invalid NRIC or FIN": {
+		Chinese: "NRIC 或 FIN 无效",
+		Malay:   "NRIC atau FIN tidak sah",
+		Tamil:   "NRIC அல்லது FIN தவறானது",
+	},
+	"Version conflict": {
+		Chinese: "版本冲突",
+		Malay:   "Konflik versi",
+		Tamil:   "பதிப்பு முரண்பாடு",
+	},
+	"Scheme is not open for applications": {
+		Chinese: "该援助计划目前不接受申请",
+		Malay:   "Skim tidak dibuka untuk permohonan",
+		Tamil:   "இத்திட்டம் விண்ணப்பங்களுக்குத் திறக்கப்படவில்லை",
+	},
+	"Scheme has no remaining capacity": {
+		Chinese: "该援助计划已无剩余名额",
+		Malay:   "Skim tiada baki kapasiti",
+		Tamil:   "இத்திட்டத்தில் மீதமுள்ள இடம் இல்லை",
+	},
+	"Scheme is archived": {
+		Chinese: "计划已归档",
+		Malay:   "Skim telah diarkibkan",
+		Tamil:   "திட்டம் காப்பகப்படுத்தப்பட்டுள்ளது",
+	},
+	"Scheme is already published": {
+		Chinese: "计划已经发布",
+		Malay:   "Skim telah pun diterbitkan",
+		Tamil:   "திட்டம் ஏற்கனவே வெளியிடப்பட்டுள்ளது",
+	},
+	"Scheme is already archived": {
+		Chinese: "计划已经归档",
+		Malay:   "Skim telah pun diarkibkan",
+		Tamil:   "திட்டம் ஏற்கனவே காப்பகப்படுத்தப்பட்டுள்ளது",
+	},
+	"Applicant has been anonymized": {
+		Chinese: "申请人资料已匿名化",
+		Malay:   "Data pemohon telah dinyahnamakan",
+		Tamil:   "விண்ணப்பதாரரின் தரவு அடையாளமற்றதாக்கப்பட்டுள்ளது",
+	},
+	"File too large": {
+		Chinese: "文件过大",
+		Malay:   "Fail terlalu besar",
+		Tamil:   "கோப்பு மிகப் பெரியது",
+	},
+	"File type not allowed": {
+		Chinese: "不允许此文件类型",
+		Malay:   "Jenis fail tidak dibenarkan",
+		Tamil:   "இந்தக் கோப்பு வகை அனுமதிக்கப்படவில்லை",
+	},
+
+	// Next steps shown to applicants tracking an application
+	"Your application is being reviewed. We will email you once a decision has been made.": {
+		Chinese: "您的申请正在审核中。一旦做出决定，我们会通过电子邮件通知您。",
+		Malay:   "Permohonan anda sedang disemak. Kami akan menghantar e-mel kepada anda sebaik sahaja keputusan dibuat.",
+		Tamil:   "உங்கள் விண்ணப்பம் பரிசீலனையில் உள்ளது. முடிவு எடுக்கப்பட்டதும் உங்களுக்கு மின்னஞ்சல் அனுப்புவோம்.",
+	},
+	"Your application has been approved. We will contact you about receiving your benefit.": {
+		Chinese: "您的申请已获批准。我们将与您联系，告知领取援助的安排。",
+		Malay:   "Permohonan anda telah diluluskan. Kami akan menghubungi anda mengenai penerimaan manfaat anda.",
+		Tamil:   "உங்கள் விண்ணப்பம் அங்கீகரிக்கப்பட்டது. உங்கள் உதவியைப் பெறுவது குறித்து நாங்கள் உங்களைத் தொடர்புகொள்வோம்.",
+	},
+	"Your application was not approved. Please contact us if you would like to know more or to apply again.": {
+		Chinese: "您的申请未获批准。如需了解详情或重新申请，请与我们联系。",
+		Malay:   "Permohonan anda tidak diluluskan. Sila hubungi kami jika anda ingin mengetahui lebih lanjut atau memohon semula.",
+		Tamil:   "உங்கள் விண்ணப்பம் அங்கீகரிக்கப்படவில்லை. மேலும் அறிய அல்லது மீண்டும் விண்ணப்பிக்க எங்களைத் தொடர்புகொள்ளவும்.",
+	},
+	"Your application has been withdrawn and will not be considered further. You are welcome to apply again if your circumstances change.": {
+		Chinese: "您的申请已撤回，将不再予以考虑。如情况有变，欢迎重新申请。",
+		Malay:   "Permohonan anda telah ditarik balik dan tidak akan dipertimbangkan lagi. Anda dialu-alukan untuk memohon semula jika keadaan anda berubah.",
+		Tamil:   "உங்கள் விண்ணப்பம் திரும்பப் பெறப்பட்டது, இனி பரிசீலிக்கப்படாது. உங்கள் சூழ்நிலை மாறினால் மீண்டும் விண்ணப்பிக்கலாம்.",
+	},
+}
+
+// Enumerations with labels
+const (
+	LabelApplicationStatus = "application_status"
+	LabelEmploymentStatus  = "employment_status"
+	LabelSex               = "sex"
+	LabelMaritalStatus     = "marital_status"
+	LabelRelation          = "relation"
+	LabelSchoolLevel       = "school_level"
+	LabelConsentPurpose    = "consent_purpose"
+	LabelBenefitFrequency  = "benefit_frequency"
+	LabelReferralOutcome   = "referral_outcome"
+	LabelSchemeStatus      = "scheme_status"
+)
+
+// labels holds the labels of enumerated values, keyed by enumeration and
+// value, in every language including English
+var labels = map[string]map[string]translations{
+	LabelApplicationStatus: {
+		"pending":   {English: "Pending", Chinese: "待处理", Malay: "Dalam proses", Tamil: "நிலுவையில் உள்ளது"},
+		"approved":  {English: "Approved", Chinese: "已批准", Malay: "Diluluskan", Tamil: "அங்கீகரிக்கப்பட்டது"},
+		"rejected":  {English: "Rejected", Chinese: "已拒绝", Malay: "Ditolak", Tamil: "நிராகரிக்கப்பட்டது"},
+		"withdrawn": {English: "Withdrawn", Chinese: "已撤回", Malay: "Ditarik balik", Tamil: "திரும்பப் பெறப்பட்டது"},
+	},
+	LabelEmploymentStatus: {
+		"employed":   {English: "Employed", Chinese: "在职", Malay: "Bekerja", Tamil: "வேலையில் உள்ளவர்"},
+		"unemployed": {English: "Unemployed", Chinese: "失业", Malay: "Tidak bekerja", Tamil: "வேலையில்லாதவர்"},
+	},
+	LabelSex: {
+		"male":   {English: "Male", Chinese: "男", Malay: "Lelaki", Tamil: "ஆண்"},
+		"female": {English: "Female", Chinese: "女", Malay: "Perempuan", Tamil: "பெண்"},
+		"other":  {English: "Other", Chinese: "其他", Malay: "Lain-lain", Tamil: "மற்றவை"},
+	},
+	LabelMaritalStatus: {
+		"single":   {English: "Single", Chinese: "单身", Malay: "Bujang", Tamil: "திருமணமாகாதவர்"},
+		"married":  {English: "Married", Chinese: "已婚", Malay: "Berkahwin", Tamil: "திருமணமானவர்"},
+		"widowed":  {English: "Widowed", Chinese: "丧偶", Malay: "Kematian pasangan", Tamil: "துணையை இழந்தவர்"},
+		"divorced": {English: "Divorced", Chinese: "离婚", Malay: "Bercerai", Tamil: "விவாகரத்தானவர்"},
+	},
+	LabelRelation: {
+		"spouse":   {English: "Spouse", Chinese: "配偶", Malay: "Pasangan", Tamil: "வாழ்க்கைத் துணை"},
+		"son":      {English: "Son", Chinese: "儿子", Malay: "Anak lelaki", Tamil: "மகன்"},
+		"daughter": {English: "Daughter", Chinese: "女儿", Malay: "Anak perempuan", Tamil: "மகள்"},
+		"parent":   {English: "Parent", Chinese: "父母", Malay: "Ibu bapa", Tamil: "பெற்றோர்"},
+		"sibling":  {English: "Sibling", Chinese: "兄弟姐妹", Malay: "Adik-beradik", Tamil: "உடன்பிறப்பு"},
+		"other":    {English: "Other", Chinese: "其他", Malay: "Lain-lain", Tamil: "மற்றவர்"},
+	},
+	LabelSchoolLevel: {
+		"preschool": {English: "Preschool", Chinese: "学前", Malay: "Prasekolah", Tamil: "பாலர் பள்ளி"},
+		"primary":   {English: "Primary", Chinese: "小学", Malay: "Sekolah rendah", Tamil: "தொடக்கப் பள்ளி"},
+		"secondary": {English: "Secondary", Chinese: "中学", Malay: "Sekolah menengah", Tamil: "உயர்நிலைப் பள்ளி"},
+		"tertiary":  {English: "Tertiary", Chinese: "大专及以上", Malay: "Pengajian tinggi", Tamil: "உயர்கல்வி"},
+		"none":      {English: "None", Chinese: "无", Malay: "Tiada", Tamil: "இல்லை"},
+	},
+	LabelConsentPurpose: {
+		"data_sharing":  {English: "Sharing data with partner agencies", Chinese: "与合作机构共享资料", Malay: "Perkongsian data dengan agensi rakan kongsi", Tamil: "கூட்டாளர் அமைப்புகளுடன் தரவுப் பகிர்வு"},
+		"contact_email": {English: "Contact by email", Chinese: "通过电子邮件联系", Malay: "Dihubungi melalui e-mel", Tamil: "மின்னஞ்சல் மூலம் தொடர்பு"},
+		"contact_phone": {English: "Contact by phone", Chinese: "通过电话联系", Malay: "Dihubungi melalui telefon", Tamil: "தொலைபேசி மூலம் தொடர்பு"},
+		"contact_post":  {English: "Contact by post", Chinese: "通过邮寄联系", Malay: "Dihubungi melalui pos", Tamil: "அஞ்சல் மூலம் தொடர்பு"},
+	},
+	LabelBenefitFrequency: {
+		"one_off":   {English: "One-off", Chinese: "一次性", Malay: "Sekali sahaja", Tamil: "ஒருமுறை"},
+		"monthly":   {English: "Monthly", Chinese: "每月", Malay: "Bulanan", Tamil: "மாதாந்திர"},
+		"quarterly": {English: "Quarterly", Chinese: "每季度", Malay: "Suku tahunan", Tamil: "காலாண்டு"},
+	},
+	LabelReferralOutcome: {
+		"pending":   {English: "Pending", Chinese: "待处理", Malay: "Dalam proses", Tamil: "நிலுவையில் உள்ளது"},
+		"accepted":  {English: "Accepted", Chinese: "已接受", Malay: "Diterima", Tamil: "ஏற்கப்பட்டது"},
+		"declined":  {English: "Declined", Chinese: "已拒绝", Malay: "Ditolak", Tamil: "மறுக்கப்பட்டது"},
+		"completed": {English: "Completed", Chinese: "已完成", Malay: "Selesai", Tamil: "நிறைவடைந்தது"},
+	},
+	LabelSchemeStatus: {
+		"draft":     {English: "Draft", Chinese: "草稿", Malay: "Draf", Tamil: "வரைவு"},
+		"published": {English: "Published", Chinese: "已发布", Malay: "Diterbitkan", Tamil: "வெளியிடப்பட்டது"},
+		"archived":  {English: "Archived", Chinese: "已归档", Malay: "Diarkibkan", Tamil: "காப்பகப்படுத்தப்பட்டது"},
+	},
+}
@@ -0,0 +1,166 @@
+// Package i18n negotiates the language of API responses from the
+// Accept-Language header and translates the messages and labels the API
+// serves into it. Text without a translation is served in English.
+package i18n
+
+import (
+	"net/http"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Languages the API can be served in
+const (
+	English = "en"
+	Chinese = "zh"
+	Malay   = "ms"
+	Tamil   = "ta"
+)
+
+// Languages lists the supported languages, the default first
+var Languages = []string{English, Chinese, Malay, Tamil}
+
+// Supported reports whether lang is one of Languages
+func Supported(lang string) bool {
+	return slices.Contains(Languages, lang)
+}
+
+// Negotiate returns the supported language the Accept-Language header value
+// prefers, matching tags by their primary subtag so that zh-SG and zh-Hans
+// are served Chinese. Ties in quality go to the tag listed first. English is
+// returned when the header is empty or names nothing supported.
+func Negotiate(header string) string {
+	type choice struct {
+		lang    string
+		quality float64
+	}
+	var choices []choice
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality <= 0 {
+			continue
+		}
+
+		primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if primary == "*" {
+			primary = English
+		}
+		if Supported(primary) {
+			choices = append(choices, choice{lang: primary, quality: quality})
+		}
+	}
+	if len(choices) == 0 {
+		return English
+	}
+
+	sort.SliceStable(choices, func(i, j int) bool {
+		return choices[i].quality > choices[j].quality
+	})
+	return choices[0].lang
+}
+
+// FromRequest returns the language negotiated for the request
+func FromRequest(r *http.Request) string {
+	return Negotiate(r.Header.Get("Accept-Language"))
+}
+
+// pattern matches messages made from a template with {0}, {1}, ...
+// placeholders, capturing the values substituted for them
+type pattern struct {
+	template string
+	regexp   *regexp.Regexp
+}
+
+var placeholder = regexp.MustCompile(`\{\d\}`)
+
+// patterns holds the templates in messages that have placeholders
+var patterns = func() []pattern {
+	var list []pattern
+	for template := range messages {
+		if !placeholder.MatchString(template) {
+			continue
+		}
+		parts := placeholder.Split(template, -1)
+		for i, part := range parts {
+			parts[i] = regexp.QuoteMeta(part)
+		}
+		list = append(list, pattern{
+			template: template,
+			regexp:   regexp.MustCompile("^" + strings.Join(parts, "(.+?)") + "$"),
+		})
+	}
+	// Try longer templates first, so the most specific one matches
+	sort.Slice(list, func(i, j int) bool {
+		if len(list[i].template) != len(list[j].template) {
+			return len(list[i].template) > len(list[j].template)
+		}
+		return list[i].template < list[j].template
+	})
+	return list
+}()
+
+// Message translates an English message into lang. Messages made from a
+// template in the catalogue, such as "must be at most 255 characters", are
+// translated with the values they were made with. Messages without a
+// translation are returned unchanged.
+func Message(lang, message string) string {
+	if lang == English || message == "" {
+		return message
+	}
+	if translated, ok := messages[message][lang]; ok {
+		return translated
+	}
+
+	for _, p := range patterns {
+		match := p.regexp.FindStringSubmatch(message)
+		if match == nil {
+			continue
+		}
+		translated, ok := messages[p.template][lang]
+		if !ok {
+			return message
+		}
+		return placeholder.ReplaceAllStringFunc(translated, func(s string) string {
+			i, _ := strconv.Atoi(s[1 : len(s)-1])
+			if i+1 < len(match) {
+				return match[i+1]
+			}
+			return s
+		})
+	}
+	return message
+}
+
+// Messages translates each message of a field → message map into lang,
+// returning a new map
+func Messages(lang string, fields map[string]string) map[string]string {
+	translated := make(map[string]string, len(fields))
+	for field, message := range fields {
+		translated[field] = Message(lang, message)
+	}
+	return translated
+}
+
+// Label returns the label of a value of an enumeration, such as the
+// "approved" value of LabelApplicationStatus, in lang. Values without a label
+// are returned unchanged.
+func Label(lang, enumeration, value string) string {
+	if translated, ok := labels[enumeration][value][lang]; ok {
+		return translated
+	}
+	if translated, ok := labels[enumeration][value][English]; ok {
+		return translated
+	}
+	return value
+}
@@ -0,0 +1,101 @@
+// Package imaging decodes uploaded photos and scales them down to
+// thumbnails.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+)
+
+// Media types of the images that can be decoded
+const (
+	TypeJPEG = "image/jpeg"
+	TypePNG  = "image/png"
+)
+
+// MaxPixels is the largest image decoded, so that a small file claiming
+// huge dimensions cannot exhaust memory
+const MaxPixels = 40_000_000
+
+// jpegQuality is the quality thumbnails are encoded at
+const jpegQuality = 85
+
+// Errors returned for images that cannot be used
+var (
+	ErrUnsupported = errors.New("image must be a JPEG or PNG")
+	ErrTooLarge    = fmt.Errorf("image must have at most %d pixels", MaxPixels)
+)
+
+// Thumbnail is an image scaled down from an original
+type Thumbnail struct {
+	Data          []byte
+	ContentType   string
+	Width, Height int // Of the original, in pixels
+}
+
+// MakeThumbnail decodes a JPEG or PNG image and scales it to fit within size
+// pixels on its longest side. Smaller images keep their size, but are still
+// re-encoded, which drops any metadata such as the location a photo was
+// taken. Thumbnails of PNGs are PNGs, so transparency is kept, and of JPEGs,
+// JPEGs.
+func MakeThumbnail(data []byte, size int) (*Thumbnail, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		if errors.Is(err, image.ErrFormat) {
+			return nil, ErrUnsupported
+		}
+		return nil, fmt.Errorf("invalid image: %v", err)
+	}
+	if format != "jpeg" && format != "png" {
+		return nil, ErrUnsupported
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, errors.New("invalid image: no pixels")
+	}
+	if config.Width*config.Height > MaxPixels {
+		return nil, ErrTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %v", err)
+	}
+
+	width, height := fit(config.Width, config.Height, size)
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Src, nil)
+
+	thumbnail := &Thumbnail{Width: config.Width, Height: config.Height}
+	var buf bytes.Buffer
+	if format == "png" {
+		thumbnail.ContentType = TypePNG
+		err = png.Encode(&buf, dst)
+	} else {
+		thumbnail.ContentType = TypeJPEG
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: jpegQuality})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error encoding thumbnail: %v", err)
+	}
+	thumbnail.Data = buf.Bytes()
+
+	return thumbnail, nil
+}
+
+// fit scales width and height down, keeping their ratio, so that neither
+// exceeds size. Neither becomes less than one pixel.
+func fit(width, height, size int) (int, int) {
+	if width <= size && height <= size {
+		return width, height
+	}
+	if width >= height {
+		return size, max(1, height*size/width)
+	}
+	return max(1, width*size/height), size
+}
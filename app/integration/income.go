@@ -0,0 +1,151 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"one-client-view-2025tht/app/models"
+)
+
+// Income backends
+const (
+	IncomeBackendNone = "none" // Income retrieval is disabled
+	IncomeBackendStub = "stub" // StubIncomeProvider, for development
+	IncomeBackendIRAS = "iras" // IRASClient
+)
+
+// maxIncomeResponse is the largest income response read, in bytes
+const maxIncomeResponse = 1 << 20
+
+// IRASClient fetches assessed incomes from an IRAS-style income assessment
+// API, which returns a person's notices of assessment, as in
+//
+//	GET {BaseURL}/assessments/S1234567D
+//	{"assessments": [{"year_of_assessment": 2025, "assessable_income": 42000.00, "notice_number": "..."}]}
+//
+// The latest year's assessable income, which is annual, is returned as a
+// monthly average. Requests carry the API key as a bearer token.
+type IRASClient struct {
+	BaseURL string
+	APIKey  string
+	Client  *http.Client
+}
+
+// NewIRASClient creates a client of the income assessment API at baseURL
+func NewIRASClient(baseURL, apiKey string, timeout time.Duration) *IRASClient {
+	return &IRASClient{
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		APIKey:  apiKey,
+		Client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// Name returns "iras"
+func (c *IRASClient) Name() string {
+	return IncomeBackendIRAS
+}
+
+// irasAssessments is the part of an assessments response that is used
+type irasAssessments struct {
+	Assessments []struct {
+		YearOfAssessment int     `json:"year_of_assessment"`
+		AssessableIncome float64 `json:"assessable_income"`
+		NoticeNumber     string  `json:"notice_number"`
+	} `json:"assessments"`
+}
+
+// Income fetches the latest assessment of the person with the given NRIC or
+// FIN
+func (c *IRASClient) Income(ctx context.Context, identityNumber string) (*models.IncomeAssessment, error) {
+	endpoint := c.BaseURL + "/assessments/" + url.PathEscape(identityNumber)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		// Drop the URL, which holds the identity number, from the error
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("error calling IRAS: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("IRAS responded %s", resp.Status)
+	}
+
+	var body irasAssessments
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxIncomeResponse)).Decode(&body); err != nil {
+		return nil, fmt.Errorf("error decoding IRAS response: %v", err)
+	}
+	if len(body.Assessments) == 0 {
+		return nil, ErrNotFound
+	}
+
+	latest := body.Assessments[0]
+	for _, a := range body.Assessments[1:] {
+		if a.YearOfAssessment > latest.YearOfAssessment {
+			latest = a
+		}
+	}
+	return &models.IncomeAssessment{
+		MonthlyIncome:  monthlyIncome(latest.AssessableIncome),
+		AssessmentYear: latest.YearOfAssessment,
+		Reference:      latest.NoticeNumber,
+	}, nil
+}
+
+// monthlyIncome returns the monthly average of an annual income, to the cent
+func monthlyIncome(annual float64) float64 {
+	return math.Round(annual/12*100) / 100
+}
+
+// StubIncomeProvider makes up incomes, for developing and testing against
+// without access to an agency's API. The same NRIC always gets the same
+// income, between 0 and 7,900 a month in steps of 100, assessed for last
+// year; NRICs whose digits end in 0 are not found. It must not be used in
+// production.
+type StubIncomeProvider struct{}
+
+// Name returns "stub"
+func (StubIncomeProvider) Name() string {
+	return IncomeBackendStub
+}
+
+// Income returns the made-up income of the person with the given NRIC or FIN
+func (StubIncomeProvider) Income(ctx context.Context, identityNumber string) (*models.IncomeAssessment, error) {
+	if len(identityNumber) != 9 {
+		return nil, ErrNotFound
+	}
+	digits, err := strconv.Atoi(identityNumber[1:8])
+	if err != nil || digits%10 == 0 {
+		return nil, ErrNotFound
+	}
+	year := time.Now().Year() - 1
+	return &models.IncomeAssessment{
+		MonthlyIncome:  float64(digits%80) * 100,
+		AssessmentYear: year,
+		Reference:      fmt.Sprintf("STUB-%d-%07d", year, digits),
+	}, nil
+}
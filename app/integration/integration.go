@@ -0,0 +1,38 @@
+// Package integration fetches data about people from external registries,
+// such as MyInfo, to prefill new applicants instead of typing in what the
+// registry already holds, and their assessed incomes from agencies such as
+// IRAS.
+package integration
+
+import (
+	"context"
+	"errors"
+
+	"one-client-view-2025tht/app/models"
+)
+
+// ErrNotFound is returned by providers that hold nothing about a person, or
+// no income for them
+var ErrNotFound = errors.New("person not found")
+
+// PersonDataProvider looks people up in an external registry
+type PersonDataProvider interface {
+	// Name identifies the registry, and is returned as the source of the
+	// data it provides
+	Name() string
+
+	// Person returns what the registry holds about the person with the
+	// given NRIC or FIN, or ErrNotFound
+	Person(ctx context.Context, identityNumber string) (*models.ApplicantPrefill, error)
+}
+
+// IncomeProvider looks up people's assessed incomes at an external source
+type IncomeProvider interface {
+	// Name identifies the source, and is recorded as the source of the
+	// incomes it provides
+	Name() string
+
+	// Income returns the latest income assessed for the person with the
+	// given NRIC or FIN, or ErrNotFound
+	Income(ctx context.Context, identityNumber string) (*models.IncomeAssessment, error)
+}
@@ -0,0 +1,217 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"one-client-view-2025tht/app/date"
+	"one-client-view-2025tht/app/models"
+)
+
+// myinfoAttributes are the person attributes requested from MyInfo
+const myinfoAttributes = "uinfin,name,sex,dob,marital,regadd,childrenbirthrecords"
+
+// maxMyInfoResponse is the largest person response read, in bytes
+const maxMyInfoResponse = 1 << 20
+
+// MyInfoClient fetches person data from a MyInfo-style Person API, which
+// returns each attribute as an object with a value or code, as in
+//
+//	GET {BaseURL}/person/S1234567D?attributes=...&client_id=...
+//	{"name": {"value": "TAN AH KOW"}, "sex": {"code": "M"}, ...}
+//
+// Requests carry the API key as a bearer token. The client expects the
+// response decrypted, as returned by the MyInfo sandbox or a gateway that
+// holds the agency's keys.
+type MyInfoClient struct {
+	BaseURL  string
+	ClientID string
+	APIKey   string
+	Client   *http.Client
+}
+
+// NewMyInfoClient creates a client of the Person API at baseURL
+func NewMyInfoClient(baseURL, clientID, apiKey string, timeout time.Duration) *MyInfoClient {
+	return &MyInfoClient{
+		BaseURL:  strings.TrimRight(baseURL, "/"),
+		ClientID: clientID,
+		APIKey:   apiKey,
+		Client:   &http.Client{Timeout: timeout},
+	}
+}
+
+// Name returns "myinfo"
+func (c *MyInfoClient) Name() string {
+	return "myinfo"
+}
+
+// myinfoField is a single attribute. Attributes the registry does not hold
+// for the person are marked unavailable.
+type myinfoField struct {
+	Value       string `json:"value"`
+	Code        string `json:"code"`
+	Unavailable bool   `json:"unavailable"`
+}
+
+// value returns the field's value, or "" if it is absent or unavailable
+func (f *myinfoField) value() string {
+	if f == nil || f.Unavailable {
+		return ""
+	}
+	return strings.TrimSpace(f.Value)
+}
+
+// code returns the field's code, or "" if it is absent or unavailable
+func (f *myinfoField) code() string {
+	if f == nil || f.Unavailable {
+		return ""
+	}
+	return strings.TrimSpace(f.Code)
+}
+
+// myinfoPerson is the part of a Person API response that is used
+type myinfoPerson struct {
+	UINFIN  *myinfoField `json:"uinfin"`
+	Name    *myinfoField `json:"name"`
+	Sex     *myinfoField `json:"sex"`
+	DOB     *myinfoField `json:"dob"`
+	Marital *myinfoField `json:"marital"`
+	RegAdd  *struct {
+		Type        string       `json:"type"`
+		Block       *myinfoField `json:"block"`
+		Building    *myinfoField `json:"building"`
+		Floor       *myinfoField `json:"floor"`
+		Unit        *myinfoField `json:"unit"`
+		Street      *myinfoField `json:"street"`
+		Postal      *myinfoField `json:"postal"`
+		Unavailable bool         `json:"unavailable"`
+	} `json:"regadd"`
+	Children []struct {
+		Name *myinfoField `json:"name"`
+		Sex  *myinfoField `json:"sex"`
+		DOB  *myinfoField `json:"dob"`
+	} `json:"childrenbirthrecords"`
+}
+
+// MyInfo codes, mapped to the values used for applicants. Unknown sexes and
+// marital statuses are left for the caseworker to enter.
+var (
+	myinfoSexes = map[string]string{"M": "male", "F": "female"}
+
+	myinfoMaritalStatuses = map[string]string{
+		"1": "single",
+		"2": "married",
+		"3": "widowed",
+		"5": "divorced",
+	}
+)
+
+// Person fetches the person with the given NRIC or FIN
+func (c *MyInfoClient) Person(ctx context.Context, identityNumber string) (*models.ApplicantPrefill, error) {
+	query := url.Values{"attributes": {myinfoAttributes}}
+	if c.ClientID != "" {
+		query.Set("client_id", c.ClientID)
+	}
+	endpoint := c.BaseURL + "/person/" + url.PathEscape(identityNumber) + "?" + query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		// Drop the URL, which holds the identity number, from the error
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("error calling MyInfo: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("MyInfo responded %s", resp.Status)
+	}
+
+	var person myinfoPerson
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxMyInfoResponse)).Decode(&person); err != nil {
+		return nil, fmt.Errorf("error decoding MyInfo response: %v", err)
+	}
+
+	return person.prefill(identityNumber), nil
+}
+
+// prefill converts the person to the values used for applicants
+func (p *myinfoPerson) prefill(identityNumber string) *models.ApplicantPrefill {
+	prefill := &models.ApplicantPrefill{
+		IdentityNumber: identityNumber,
+		Name:           p.Name.value(),
+		Sex:            myinfoSexes[p.Sex.code()],
+		DateOfBirth:    myinfoDate(p.DOB),
+		MaritalStatus:  myinfoMaritalStatuses[p.Marital.code()],
+		Household:      []models.HouseholdHint{},
+	}
+	if uinfin := p.UINFIN.value(); uinfin != "" {
+		prefill.IdentityNumber = strings.ToUpper(uinfin)
+	}
+
+	// Only Singapore addresses fit an applicant's address
+	if a := p.RegAdd; a != nil && !a.Unavailable && a.Type == "SG" && a.Street.value() != "" {
+		prefill.Address = &models.Address{
+			Block:      a.Block.value(),
+			Street:     a.Street.value(),
+			Building:   a.Building.value(),
+			PostalCode: a.Postal.value(),
+		}
+		if floor, unit := a.Floor.value(), a.Unit.value(); floor != "" && unit != "" {
+			prefill.Address.Unit = "#" + floor + "-" + unit
+		}
+	}
+
+	for _, child := range p.Children {
+		name := child.Name.value()
+		if name == "" {
+			continue
+		}
+		hint := models.HouseholdHint{
+			Name:        name,
+			Relation:    models.RelationOther,
+			Sex:         myinfoSexes[child.Sex.code()],
+			DateOfBirth: myinfoDate(child.DOB),
+		}
+		switch hint.Sex {
+		case "male":
+			hint.Relation = models.RelationSon
+		case "female":
+			hint.Relation = models.RelationDaughter
+		}
+		prefill.Household = append(prefill.Household, hint)
+	}
+
+	return prefill
+}
+
+// myinfoDate parses a YYYY-MM-DD date, returning nil if it is absent or
+// malformed
+func myinfoDate(f *myinfoField) *date.Date {
+	d, err := date.Parse(f.value())
+	if err != nil {
+		return nil
+	}
+	return &d
+}
@@ -0,0 +1,233 @@
+// Package jobs runs long-running tasks in the background from the queue in
+// the jobs table, so that requests can return at once and clients poll
+// GET /api/v1/jobs/{id} for the outcome.
+//
+// Each job type has a handler registered with a Runner, whose workers claim
+// due jobs of those types, run them and record their results. A failed job is
+// retried with exponential backoff until its type's RetryPolicy gives up, and
+// a job whose worker stops without recording a result is claimed again once
+// its claim expires. Handlers may therefore run more than once for a job and
+// must be safe to repeat.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"one-client-view-2025tht/app/logging"
+	"one-client-view-2025tht/app/models"
+)
+
+// HandlerFunc runs a claimed job and returns its result, which is stored as
+// JSON. It should stop early when ctx is cancelled.
+type HandlerFunc func(ctx context.Context, job *models.Job) (interface{}, error)
+
+// RetryPolicy decides when a failed job is attempted again
+type RetryPolicy struct {
+	MaxAttempts int           // Attempts before a job is marked failed
+	BaseBackoff time.Duration // Delay before the first retry, doubled after each failure
+	MaxBackoff  time.Duration
+}
+
+// DefaultRetryPolicy is used by job types that do not set their own
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	BaseBackoff: 10 * time.Second,
+	MaxBackoff:  10 * time.Minute,
+}
+
+// NextAttempt returns when to retry a job that has failed the given number of
+// attempts, or nil if it should not be retried
+func (p RetryPolicy) NextAttempt(attempts int) *time.Time {
+	if attempts >= p.MaxAttempts {
+		return nil
+	}
+
+	delay := p.BaseBackoff
+	for i := 1; i < attempts && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	next := time.Now().Add(delay)
+	return &next
+}
+
+// permanentError marks a failure that retrying cannot fix
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps an error so the job fails without being retried, e.g.
+// because its payload is invalid
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+// registration is a job type's handler and retry policy
+type registration struct {
+	handler HandlerFunc
+	policy  RetryPolicy
+}
+
+// Runner claims and runs due jobs with a pool of workers. Handlers are given
+// a context holding a logger whose lines carry the job's ID and type.
+type Runner struct {
+	Repo     *models.JobRepository
+	Workers  int           // Jobs run at once
+	Interval time.Duration // How often idle workers poll for due jobs
+	Lease    time.Duration // How long a claim lasts; renewed while the job runs
+	Logger   *slog.Logger
+
+	handlers map[string]registration
+}
+
+// NewRunner creates a runner with the given number of workers, polling the
+// queue every interval when idle
+func NewRunner(repo *models.JobRepository, workers int, interval time.Duration) *Runner {
+	return &Runner{
+		Repo:     repo,
+		Workers:  workers,
+		Interval: interval,
+		Lease:    time.Minute,
+		Logger:   logging.Component("jobs"),
+		handlers: make(map[string]registration),
+	}
+}
+
+// Register sets the handler and retry policy of a job type. Jobs of types
+// with no handler are left in the queue.
+func (r *Runner) Register(jobType string, policy RetryPolicy, handler HandlerFunc) {
+	r.handlers[jobType] = registration{handler: handler, policy: policy}
+}
+
+// types returns the registered job types
+func (r *Runner) types() []string {
+	types := make([]string, 0, len(r.handlers))
+	for t := range r.handlers {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Run runs due jobs until ctx is cancelled, then waits for running jobs to
+// stop
+func (r *Runner) Run(ctx context.Context) {
+	var workers sync.WaitGroup
+	for i := 0; i < r.Workers; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			r.work(ctx)
+		}()
+	}
+	workers.Wait()
+}
+
+// work runs jobs one at a time, polling every Interval while none are due
+func (r *Runner) work(ctx context.Context) {
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	for {
+		ran, err := r.RunNext(ctx)
+		if err != nil {
+			r.Logger.Error("Job queue failed", "error", err)
+		}
+		if ran && err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunNext claims and runs one due job, reporting whether there was one
+func (r *Runner) RunNext(ctx context.Context) (bool, error) {
+	if ctx.Err() != nil {
+		return false, nil
+	}
+
+	job, err := r.Repo.ClaimNext(r.types(), r.Lease)
+	if err != nil || job == nil {
+		return false, err
+	}
+	reg := r.handlers[job.Type]
+
+	// A job claimed again after its worker stopped may already have used
+	// its attempts
+	if job.Attempts > reg.policy.MaxAttempts {
+		return true, r.Repo.MarkAttemptFailed(job, "abandoned after its worker stopped", nil)
+	}
+
+	logger := r.Logger.With("job_id", job.ID, "job_type", job.Type)
+	result, err := r.run(logging.NewContext(ctx, logger), job, reg.handler)
+	if err != nil && ctx.Err() != nil {
+		// Stopped by shutdown; another worker picks the job up later
+		logger.Warn("Job interrupted by shutdown")
+		return true, r.Repo.Release(job)
+	}
+	if err != nil {
+		var next *time.Time
+		var permanent permanentError
+		if !errors.As(err, &permanent) {
+			next = reg.policy.NextAttempt(job.Attempts)
+		}
+		logger.Error("Job failed", "attempt", job.Attempts, "retry", next != nil, "error", err)
+		return true, r.Repo.MarkAttemptFailed(job, err.Error(), next)
+	}
+
+	logger.Debug("Job succeeded", "attempt", job.Attempts)
+	return true, r.Repo.MarkSucceeded(job, result)
+}
+
+// run calls the handler, extending the job's claim while it runs. The
+// handler's context is cancelled if the claim is lost, and a panic in the
+// handler fails the attempt.
+func (r *Runner) run(ctx context.Context, job *models.Job, handler HandlerFunc) (result interface{}, err error) {
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(r.Lease / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				held, err := r.Repo.Extend(job, time.Now().Add(r.Lease))
+				if err != nil {
+					logging.FromContext(ctx).Error("Failed to extend claim on job", "error", err)
+				} else if !held {
+					logging.FromContext(ctx).Warn("Lost claim on job")
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return handler(jobCtx, job)
+}
@@ -0,0 +1,368 @@
+// Package jsonapi serializes API responses as JSON:API documents
+// (https://jsonapi.org) for clients that ask for them with
+// Accept: application/vnd.api+json.
+//
+// Responses are first encoded as the plain JSON the API documents, then
+// rearranged: applicants, applications, schemes and documents become
+// resource objects with their attributes, links and relationships, and the
+// resources embedded in them, such as an application's applicant and scheme,
+// move to the document's included member. Other responses, such as reports,
+// have no resource identity and are returned as the document's meta.
+package jsonapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"one-client-view-2025tht/app/models"
+)
+
+// MediaType is the media type of JSON:API documents
+const MediaType = "application/vnd.api+json"
+
+// Version is the version of JSON:API the documents follow
+const Version = "1.1"
+
+// basePath prefixes the paths of links, which name the version of the API
+// the resources are served by
+const basePath = "/api/v1"
+
+// Accepts reports whether a request asks for JSON:API documents. Media type
+// parameters, which name JSON:API extensions and profiles, are not supported,
+// so an Accept header with only parameterized JSON:API media types is not
+// taken to ask for them.
+func Accepts(r *http.Request) bool {
+	for _, accept := range r.Header.Values("Accept") {
+		for _, part := range strings.Split(accept, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+			if err != nil || mediaType != MediaType {
+				continue
+			}
+			delete(params, "q")
+			if len(params) == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Relationship describes how a resource refers to another, or to a
+// collection of others
+type Relationship struct {
+	Name     string // Member name, such as "scheme"
+	Type     string // Type of the related resources, if they can be identified
+	IDField  string // Attribute holding the related resource's ID, for to-one relationships; moved out of the attributes
+	Embedded bool   // Whether the related resources are embedded under Name, to be moved to included
+	Related  string // Path of the related link, with attribute names in braces, such as /schemes/{scheme_id}
+}
+
+// Type describes how a kind of resource is serialized
+type Type struct {
+	Name          string            // Resource type, such as "applicants"
+	Links         map[string]string // Paths of links by name, with attribute names in braces; "self" defaults to /{Name}/{id}, and is left out if empty
+	Relationships []Relationship
+}
+
+var (
+	applicantType = &Type{
+		Name: "applicants",
+		Relationships: []Relationship{
+			{Name: "applications", Type: "applications", Related: "/applicants/{id}/applications"},
+		},
+	}
+	applicationType = &Type{
+		Name: "applications",
+		Relationships: []Relationship{
+			{Name: "applicant", Type: "applicants", IDField: "applicant_id", Embedded: true, Related: "/applicants/{applicant_id}"},
+			{Name: "scheme", Type: "schemes", IDField: "scheme_id", Embedded: true, Related: "/schemes/{scheme_id}"},
+			{Name: "documents", Type: "documents", Embedded: true, Related: "/applications/{id}/documents"},
+		},
+	}
+	schemeType = &Type{
+		Name: "schemes",
+		Relationships: []Relationship{
+			{Name: "versions", Related: "/schemes/{id}/versions"},
+		},
+	}
+	documentType = &Type{
+		Name: "documents",
+		// A document's own path serves its content, not the resource
+		Links: map[string]string{"self": "", "download": "/applications/{application_id}/documents/{id}"},
+		Relationships: []Relationship{
+			{Name: "application", Type: "applications", IDField: "application_id", Related: "/applications/{application_id}"},
+		},
+	}
+)
+
+// typesByGo are the resource types of the values handlers respond with
+var typesByGo = map[reflect.Type]*Type{
+	reflect.TypeOf(models.Applicant{}):           applicantType,
+	reflect.TypeOf(models.ApplicantResponse{}):   applicantType,
+	reflect.TypeOf(models.Application{}):         applicationType,
+	reflect.TypeOf(models.ApplicationResponse{}): applicationType,
+	reflect.TypeOf(models.ProfileApplication{}):  applicationType,
+	reflect.TypeOf(models.Scheme{}):              schemeType,
+	reflect.TypeOf(models.SchemeResponse{}):      schemeType,
+	reflect.TypeOf(models.Document{}):            documentType,
+}
+
+// typesByName are the resource types by name, for embedded resources
+var typesByName = map[string]*Type{
+	applicantType.Name:   applicantType,
+	applicationType.Name: applicationType,
+	schemeType.Name:      schemeType,
+	documentType.Name:    documentType,
+}
+
+// Resource is a JSON:API resource object
+type Resource struct {
+	Type          string                        `json:"type"`
+	ID            string                        `json:"id"`
+	Attributes    map[string]interface{}        `json:"attributes,omitempty"`
+	Relationships map[string]RelationshipObject `json:"relationships,omitempty"`
+	Links         map[string]string             `json:"links,omitempty"`
+}
+
+// Identifier is a JSON:API resource identifier object
+type Identifier struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// RelationshipObject is a JSON:API relationship object. Data is only written
+// when HasData is set, as null for an empty to-one relationship.
+type RelationshipObject struct {
+	Links   map[string]string
+	Data    interface{}
+	HasData bool
+}
+
+func (r RelationshipObject) MarshalJSON() ([]byte, error) {
+	out := map[string]interface{}{"links": r.Links}
+	if r.HasData {
+		out["data"] = r.Data
+	}
+	return json.Marshal(out)
+}
+
+// Error is a JSON:API error object
+type Error struct {
+	Status string      `json:"status"`
+	Code   string      `json:"code,omitempty"`
+	Title  string      `json:"title"`
+	Meta   interface{} `json:"meta,omitempty"`
+}
+
+// ErrorDocument is the document of a failed request
+type ErrorDocument struct {
+	Errors  []Error           `json:"errors"`
+	JSONAPI map[string]string `json:"jsonapi"`
+}
+
+// Errors returns the document of a failed request
+func Errors(errors ...Error) ErrorDocument {
+	return ErrorDocument{Errors: errors, JSONAPI: map[string]string{"version": Version}}
+}
+
+// Marshal returns the JSON:API document of a response value, as served at
+// self, the request's path and query. meta, which may be nil, describes the
+// response as a whole, such as the number of items in a list.
+func Marshal(v interface{}, self string, meta map[string]interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	doc := map[string]interface{}{
+		"links":   map[string]string{"self": self},
+		"jsonapi": map[string]string{"version": Version},
+	}
+	s := &serializer{included: make(map[Identifier]bool)}
+	switch typ, many := typeOf(reflect.TypeOf(v)); {
+	case typ != nil && many:
+		items, _ := value.([]interface{})
+		resources := make([]*Resource, 0, len(items))
+		for _, item := range items {
+			if resource := s.resource(typ, item); resource != nil {
+				resources = append(resources, resource)
+			}
+		}
+		doc["data"] = resources
+		if len(meta) > 0 {
+			doc["meta"] = meta
+		}
+	case typ != nil:
+		// A missing resource is null primary data
+		var resource *Resource
+		if value != nil {
+			resource = s.resource(typ, value)
+		}
+		doc["data"] = resource
+		if len(meta) > 0 {
+			doc["meta"] = meta
+		}
+	default:
+		// Meta must be an object, so other values, such as lists of
+		// audit entries, are wrapped in one alongside the response's meta
+		if object, ok := value.(map[string]interface{}); ok && len(meta) == 0 {
+			doc["meta"] = object
+		} else {
+			wrapped := map[string]interface{}{"items": value}
+			for key, v := range meta {
+				wrapped[key] = v
+			}
+			doc["meta"] = wrapped
+		}
+	}
+	if len(s.includedResources) > 0 {
+		doc["included"] = s.includedResources
+	}
+	return json.Marshal(doc)
+}
+
+// typeOf returns the resource type of a Go type, or of the elements of a
+// slice of it, and whether it is a slice
+func typeOf(t reflect.Type) (*Type, bool) {
+	if t == nil {
+		return nil, false
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Slice {
+		elem := t.Elem()
+		for elem.Kind() == reflect.Pointer {
+			elem = elem.Elem()
+		}
+		return typesByGo[elem], true
+	}
+	return typesByGo[t], false
+}
+
+// serializer collects the resources included in a document, each once
+type serializer struct {
+	included          map[Identifier]bool
+	includedResources []*Resource
+}
+
+// resource converts a decoded JSON object to a resource of the type, moving
+// the resources embedded in it to included. It returns nil for values that
+// are not objects with an ID.
+func (s *serializer) resource(typ *Type, value interface{}) *Resource {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	id, _ := object["id"].(string)
+	if id == "" {
+		return nil
+	}
+
+	resource := &Resource{Type: typ.Name, ID: id, Attributes: make(map[string]interface{})}
+	for key, v := range object {
+		if key != "id" {
+			resource.Attributes[key] = v
+		}
+	}
+
+	self, hasSelf := typ.Links["self"]
+	if !hasSelf {
+		self = "/" + typ.Name + "/{id}"
+	}
+	resource.Links = make(map[string]string)
+	if self != "" {
+		resource.Links["self"] = expand(self, object)
+	}
+	for name, path := range typ.Links {
+		if name != "self" {
+			resource.Links[name] = expand(path, object)
+		}
+	}
+
+	resource.Relationships = make(map[string]RelationshipObject)
+	for _, rel := range typ.Relationships {
+		obj := RelationshipObject{Links: map[string]string{"related": expand(rel.Related, object)}}
+		if rel.IDField != "" {
+			delete(resource.Attributes, rel.IDField)
+			obj.HasData = true
+			if relatedID, _ := object[rel.IDField].(string); relatedID != "" {
+				obj.Data = Identifier{Type: rel.Type, ID: relatedID}
+			}
+		}
+		if rel.Embedded {
+			if embedded, ok := resource.Attributes[rel.Name]; ok {
+				delete(resource.Attributes, rel.Name)
+				if data, ok := s.include(rel.Type, embedded); ok {
+					obj.Data, obj.HasData = data, true
+				}
+			}
+		}
+		resource.Relationships[rel.Name] = obj
+	}
+	return resource
+}
+
+// include adds embedded resources of a type to included, returning their
+// identifiers
+func (s *serializer) include(typeName string, value interface{}) (interface{}, bool) {
+	typ := typesByName[typeName]
+	if typ == nil {
+		return nil, false
+	}
+	add := func(item interface{}) *Identifier {
+		resource := s.resource(typ, item)
+		if resource == nil {
+			return nil
+		}
+		identifier := Identifier{Type: resource.Type, ID: resource.ID}
+		if !s.included[identifier] {
+			s.included[identifier] = true
+			s.includedResources = append(s.includedResources, resource)
+		}
+		return &identifier
+	}
+
+	if items, ok := value.([]interface{}); ok {
+		identifiers := make([]Identifier, 0, len(items))
+		for _, item := range items {
+			if identifier := add(item); identifier != nil {
+				identifiers = append(identifiers, *identifier)
+			}
+		}
+		return identifiers, true
+	}
+	if identifier := add(value); identifier != nil {
+		return *identifier, true
+	}
+	return nil, false
+}
+
+// expand fills the attribute names in braces in a path with the object's
+// values, and prefixes the path of the API
+func expand(path string, object map[string]interface{}) string {
+	var b strings.Builder
+	b.WriteString(basePath)
+	for {
+		start := strings.IndexByte(path, '{')
+		end := strings.IndexByte(path, '}')
+		if start < 0 || end < start {
+			b.WriteString(path)
+			return b.String()
+		}
+		b.WriteString(path[:start])
+		value, _ := object[path[start+1:end]].(string)
+		b.WriteString(value)
+		path = path[end+1:]
+	}
+}
@@ -0,0 +1,93 @@
+// Package logging sets up the server's structured log, built on log/slog.
+//
+// Setup installs a logger writing JSON or text lines at a minimum level as
+// the slog default, which the standard log package then also writes through.
+// Long-lived parts of the server, such as the job runner and the caches, log
+// through a Component logger, whose lines carry a component field. Code
+// handling a request logs through FromContext, whose lines also carry the
+// request ID.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+)
+
+// Log formats
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// ParseLevel parses a level name: debug, info, warn or error
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return 0, fmt.Errorf("unknown log level %q: must be debug, info, warn or error", name)
+	}
+	return level, nil
+}
+
+// New creates a logger writing lines in format to w, dropping those below
+// level
+func New(w io.Writer, level, format string) (*slog.Logger, error) {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	switch format {
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	case FormatText:
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q: must be json or text", format)
+	}
+}
+
+// Setup makes a logger writing to stderr the default, for slog and the
+// standard log package
+func Setup(level, format string) error {
+	logger, err := New(os.Stderr, level, format)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(logger)
+	return nil
+}
+
+// Component returns the default logger with a component field, naming the
+// part of the server logging. It must be called after Setup, as the logger
+// keeps the handler that was the default at the time.
+func Component(name string) *slog.Logger {
+	return slog.Default().With("component", name)
+}
+
+// Fatal logs msg at the error level and exits
+func Fatal(msg string, args ...interface{}) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// contextKey is the type of the context key the request logger is stored
+// under
+type contextKey struct{}
+
+// NewContext returns a copy of ctx holding logger
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger stored in ctx, or the default logger if
+// there is none
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
@@ -1,87 +1,567 @@
 package main
 
 import (
-	"log"
+	"context"
+	"crypto/tls"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
+	"os/signal"
 	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
-	_ "one-client-view-2025tht/docs" // This will be auto-generated
+	"one-client-view-2025tht/docs" // This will be auto-generated
 
 	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
 	httpSwagger "github.com/swaggo/http-swagger"
+	"golang.org/x/crypto/acme/autocert"
 
+	"one-client-view-2025tht/app/auth"
+	"one-client-view-2025tht/app/cache"
+	"one-client-view-2025tht/app/config"
 	"one-client-view-2025tht/app/database"
+	"one-client-view-2025tht/app/database/migrations"
+	"one-client-view-2025tht/app/encryption"
+	"one-client-view-2025tht/app/eventbus"
+	"one-client-view-2025tht/app/export"
+	"one-client-view-2025tht/app/features"
+	"one-client-view-2025tht/app/fixtures"
 	"one-client-view-2025tht/app/handlers"
+	"one-client-view-2025tht/app/integration"
+	"one-client-view-2025tht/app/jobs"
+	"one-client-view-2025tht/app/logging"
+	"one-client-view-2025tht/app/metrics"
+	"one-client-view-2025tht/app/middleware"
 	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/notify"
+	"one-client-view-2025tht/app/openapi"
+	"one-client-view-2025tht/app/scheduler"
+	"one-client-view-2025tht/app/storage"
+	"one-client-view-2025tht/app/webhooks"
 )
 
 // @host localhost:8080
-// @BasePath /api
+// @BasePath /
 // @schemes http
 
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Bearer token obtained from /api/v1/auth/login, e.g. "Bearer {token}"
+
+// unversionedAPIDeprecated is when the unversioned /api paths were deprecated
+// in favour of /api/v1, sent in their Deprecation header
+var unversionedAPIDeprecated = time.Date(2026, time.October, 14, 0, 0, 0, 0, time.UTC)
+
 func main() {
 	// Load environment variables
 	err := godotenv.Load()
 	if err != nil {
-		log.Println("Warning: .env file not found. Using environment variables.")
+		slog.Warn(".env file not found; using environment variables")
+	}
+
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "YAML configuration file; environment variables override its settings")
+	autoMigrate := flag.Bool("auto-migrate", false, "apply pending database migrations at startup")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage:\n  %s [flags]                  start the API server\n  %s migrate [up|status]       apply or list database migrations\n  %s encryption rotate         re-encrypt personal data with the current key\n  %s search reindex            rebuild the name search index\n  %s events [backfill|verify]  record or check the events of applications\n  %s seed                      load demo users, schemes, applicants and applications\n\nFlags:\n", os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0], os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	// Load configuration. The migrate subcommand only needs the database.
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		logging.Fatal("Failed to load configuration", "error", err)
+	}
+	if *autoMigrate {
+		cfg.Database.AutoMigrate = true
+	}
+	if flag.Arg(0) == "migrate" {
+		err = cfg.Database.Validate()
+	} else {
+		err = cfg.Validate()
+	}
+	if err != nil {
+		logging.Fatal("Invalid configuration", "error", err)
 	}
 
-	// Configure database
-	dbConfig := &database.Config{
-		Host:     getEnv("DB_HOST", "localhost"),
-		Port:     getEnvAsInt("DB_PORT", 3306),
-		User:     getEnv("DB_USER", "root"),
-		Password: getEnv("DB_PASSWORD", ""),
-		DBName:   getEnv("DB_NAME", "one_client_view_2025tht"),
+	// Log at the configured level and format from here on
+	if err := logging.Setup(cfg.Logging.Level, cfg.Logging.Format); err != nil {
+		logging.Fatal("Failed to configure logging", "error", err)
 	}
+	logger := logging.Component("server")
 
 	// Initialize database connection
-	err = database.Initialize(dbConfig)
+	db, err := database.Initialize(cfg.Database.Connection())
+	if err != nil {
+		logging.Fatal("Failed to initialize database", "error", err)
+	}
+	defer db.Close()
+
+	// Run the migrate subcommand instead of the server
+	if flag.Arg(0) == "migrate" {
+		if err := runMigrate(db.DB, db.Driver, flag.Args()[1:]); err != nil {
+			logger.Error("Migration failed", "error", err)
+			db.Close()
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Bring the schema up to date, or warn if it is behind
+	if cfg.Database.AutoMigrate {
+		if err := runMigrate(db.DB, db.Driver, []string{"up"}); err != nil {
+			logger.Error("Migration failed", "error", err)
+			db.Close()
+			os.Exit(1)
+		}
+	} else if pending, err := migrations.Pending(db.DB, db.Driver); err != nil {
+		logger.Warn("Could not check migrations", "error", err)
+	} else if len(pending) > 0 {
+		logger.Warn("Pending database migrations; run with -auto-migrate or the migrate subcommand", "pending", len(pending))
+	}
+
+	// Report rows referring to rows that no longer exist
+	if orphans, err := migrations.CheckIntegrity(db.DB); err != nil {
+		logger.Warn("Could not check referential integrity", "error", err)
+	} else {
+		for _, o := range orphans {
+			logger.Warn("Integrity check found orphaned rows", "table", o.Table, "column", o.Column, "references", o.References, "rows", o.Count)
+		}
+	}
+
+	// Configure authentication
+	tokens := auth.NewTokenManager([]byte(cfg.Auth.JWTSecret), cfg.Auth.TokenExpiry)
+	trackingTokens := auth.NewTrackingTokens(cfg.Auth.TrackingKey())
+	portalTokens := auth.NewTokenManager([]byte(cfg.Auth.JWTSecret), cfg.Portal.TokenExpiry)
+
+	// Configure encryption of sensitive columns
+	cipher, err := encryption.NewFromProvider(context.Background(), cfg.Encryption.Keys())
 	if err != nil {
-		log.Fatalf("Failed to initialize database: %v", err)
+		logging.Fatal("Failed to configure encryption", "error", err)
 	}
-	defer database.Close()
 
 	// Create repositories
-	db := database.GetDB()
-	applicantRepo := models.NewApplicantRepository(db)
-	schemeRepo := models.NewSchemeRepository(db)
-	applicationRepo := models.NewApplicationRepository(db, applicantRepo, schemeRepo)
+	applicantRepo := models.NewApplicantRepository(db.DB, cipher)
+
+	// Run the encryption subcommand instead of the server
+	if flag.Arg(0) == "encryption" {
+		if err := runEncryption(applicantRepo, flag.Args()[1:]); err != nil {
+			logger.Error("Encryption command failed", "error", err)
+			db.Close()
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Run the search subcommand instead of the server
+	if flag.Arg(0) == "search" {
+		if err := runSearch(applicantRepo, flag.Args()[1:]); err != nil {
+			logger.Error("Search command failed", "error", err)
+			db.Close()
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Run the events subcommand instead of the server
+	if flag.Arg(0) == "events" {
+		if err := runEvents(models.NewApplicationEventRepository(db.DB), flag.Args()[1:]); err != nil {
+			logger.Error("Events command failed", "error", err)
+			db.Close()
+			os.Exit(1)
+		}
+		return
+	}
+
+	schemeRepo := models.NewSchemeRepository(db.DB)
+	applicationRepo := models.NewApplicationRepository(db.DB, applicantRepo, schemeRepo)
+	householdRepo := models.NewHouseholdRepository(db.DB, applicantRepo)
+	applicationRepo.HouseholdRepo = householdRepo
+	userRepo := models.NewUserRepository(db.DB)
+	customFieldRepo := models.NewCustomFieldRepository(db.DB)
+
+	// Run the seed subcommand instead of the server
+	if flag.Arg(0) == "seed" {
+		if err := runSeed(fixtures.Repositories{Users: userRepo, Applicants: applicantRepo, Schemes: schemeRepo, Applications: applicationRepo, CustomFields: customFieldRepo}); err != nil {
+			logger.Error("Seeding failed", "error", err)
+			db.Close()
+			os.Exit(1)
+		}
+		return
+	}
+	auditRepo := models.NewAuditRepository(db.DB)
+	auditRepo.ExportChanges = cfg.Export.Sink != ""
+	webhookRepo := models.NewWebhookRepository(db.DB)
+	webhookRepo.PublishEvents = cfg.Events.Broker != ""
+	reportRepo := models.NewReportRepository(db.DB, db.Driver)
+	jobRepo := models.NewJobRepository(db.DB)
+	reviewFlagRepo := models.NewReviewFlagRepository(db.DB)
+	eventRepo := models.NewApplicationEventRepository(db.DB)
+	documentRepo := models.NewDocumentRepository(db.DB)
+	caseNoteRepo := models.NewCaseNoteRepository(db.DB)
+	photoRepo := models.NewPhotoRepository(db.DB)
+	retentionRepo := models.NewRetentionRepository(db.DB)
+	consentRepo := models.NewConsentRepository(db.DB)
+	schemeTranslationRepo := models.NewSchemeTranslationRepository(db.DB)
+	taskRepo := models.NewTaskRepository(db.DB)
+	commentRepo := models.NewCommentRepository(db.DB)
+	otpRepo := models.NewApplicantOTPRepository(db.DB)
+	referralRepo := models.NewReferralRepository(db.DB)
+	incomeRepo := models.NewIncomeRepository(db.DB)
+	featureFlagRepo := models.NewFeatureFlagRepository(db.DB)
+
+	// Configure the cache of schemes and applicants, which also holds
+	// idempotency keys and rate limit counters. Redis shares them between
+	// replicas; with no backend nothing is cached.
+	sharedCache, err := cache.Open(context.Background(), cfg.Cache.Backend, cfg.Cache.RedisURL)
+	if err != nil {
+		logging.Fatal("Failed to configure cache", "error", err)
+	}
+	if closer, ok := sharedCache.(io.Closer); ok {
+		defer closer.Close()
+	}
+	schemeCache := models.NewCachedSchemeStore(schemeRepo, sharedCache, cfg.Cache.SchemeTTL)
+	applicantCache := models.NewCachedApplicantStore(applicantRepo, sharedCache, cfg.Cache.ApplicantTTL)
+
+	// Configure the feature flags, from their configured defaults and the
+	// overrides admins have set; Validate already checked the names
+	flagSet, err := features.New(featureFlagRepo, cfg.Features.Enabled, cfg.Features.Disabled, cfg.Features.RefreshInterval)
+	if err != nil {
+		logging.Fatal("Invalid feature flags", "error", err)
+	}
+
+	// Configure the store uploaded documents and photos are kept in
+	documentStore, err := storage.Open(context.Background(), cfg.Storage.Options())
+	if err != nil {
+		logging.Fatal("Failed to configure document storage", "error", err)
+	}
+
+	// Configure the sink changes are exported to for the data warehouse, if
+	// any. S3 sinks use the storage settings' service and credentials.
+	var exporter *export.Exporter
+	if cfg.Export.Sink != "" {
+		sink, err := export.OpenSink(context.Background(), cfg.Export.Sink, cfg.Storage.Options(), cfg.Export.Token)
+		if err != nil {
+			logging.Fatal("Failed to configure change export", "error", err)
+		}
+		exporter = export.NewExporter(models.NewChangeEventRepository(db.DB), sink, cfg.Export.BatchSize)
+	}
+
+	// Configure the message broker domain events are published to, if any
+	var relay *eventbus.Relay
+	if cfg.Events.Broker != "" {
+		broker, err := eventbus.Open(cfg.Events.Broker)
+		if err != nil {
+			logging.Fatal("Failed to configure event broker", "error", err)
+		}
+		relay = eventbus.NewRelay(models.NewOutboxRepository(db.DB), broker, cfg.Events.Prefix,
+			cfg.Events.BatchSize, cfg.Events.PollInterval)
+	}
+
+	// Configure applicant email notifications, logging them when no mail
+	// server is set
+	var sender notify.Sender = notify.LogSender{}
+	if cfg.SMTP.Host != "" {
+		sender = notify.NewSMTPSender(notify.SMTPConfig{
+			Host:     cfg.SMTP.Host,
+			Port:     cfg.SMTP.Port,
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+			From:     cfg.SMTP.From,
+		})
+	}
+
+	// Text messages, such as portal sign-in codes, are likewise logged when
+	// no gateway is set
+	var smsSender notify.Sender = notify.LogSender{}
+	if cfg.SMS.GatewayURL != "" {
+		smsSender = notify.NewSMSGatewaySender(cfg.SMS.GatewayURL, cfg.SMS.APIKey, cfg.SMS.Timeout)
+	}
+	notifier := notify.NewNotifier(sender, smsSender, trackingTokens)
+
+	// Configure the registry new applicants are prefilled from, if any
+	var personData integration.PersonDataProvider
+	if cfg.MyInfo.URL != "" {
+		personData = integration.NewMyInfoClient(cfg.MyInfo.URL, cfg.MyInfo.ClientID, cfg.MyInfo.APIKey, cfg.MyInfo.Timeout)
+	}
+
+	// Configure the source assessed incomes are retrieved from, if any
+	var incomeProvider integration.IncomeProvider
+	switch cfg.Income.Backend {
+	case integration.IncomeBackendStub:
+		incomeProvider = integration.StubIncomeProvider{}
+	case integration.IncomeBackendIRAS:
+		incomeProvider = integration.NewIRASClient(cfg.Income.URL, cfg.Income.APIKey, cfg.Income.Timeout)
+	}
+
+	// Validate already checked the retention periods
+	retentionRules, err := cfg.Retention.Rules()
+	if err != nil {
+		logging.Fatal("Invalid retention rules", "error", err)
+	}
 
 	// Create handlers
-	applicantHandler := handlers.NewApplicantHandler(applicantRepo)
-	schemeHandler := handlers.NewSchemeHandler(schemeRepo, applicantRepo)
-	applicationHandler := handlers.NewApplicationHandler(applicationRepo, applicantRepo, schemeRepo)
+	authHandler := handlers.NewAuthHandler(userRepo, tokens)
+	portalHandler := handlers.NewPortalHandler(applicantRepo, otpRepo, portalTokens, notifier, cfg.Portal.CodeExpiry, cfg.Portal.MaxAttempts, cfg.Portal.LockoutFailures, cfg.Portal.LockoutWindow)
+	applicantHandler := handlers.NewApplicantHandler(applicantRepo, applicantCache, applicationRepo, auditRepo, webhookRepo, jobRepo, customFieldRepo, documentStore)
+	schemeHandler := handlers.NewSchemeHandler(schemeRepo, schemeCache, applicantCache, schemeTranslationRepo, auditRepo, jobRepo, customFieldRepo, householdRepo)
+	applicationHandler := handlers.NewApplicationHandler(applicationRepo, applicantRepo, schemeRepo, schemeCache, auditRepo, webhookRepo, reviewFlagRepo, eventRepo, consentRepo, userRepo, notifier, householdRepo, flagSet)
+	auditHandler := handlers.NewAuditHandler(auditRepo)
+	webhookHandler := handlers.NewWebhookHandler(webhookRepo)
+	searchHandler := handlers.NewSearchHandler(applicantRepo, schemeRepo, applicationRepo)
+	reportHandler := handlers.NewReportHandler(reportRepo, schemeRepo, schemeCache, applicantCache, jobRepo, householdRepo)
+	jobHandler := handlers.NewJobHandler(jobRepo)
+	documentHandler := handlers.NewDocumentHandler(documentRepo, applicationRepo, auditRepo, documentStore, int64(cfg.Documents.MaxSize), cfg.Documents.AllowedTypes)
+	photoHandler := handlers.NewPhotoHandler(photoRepo, applicantRepo, auditRepo, documentStore, int64(cfg.Photos.MaxSize), cfg.Photos.ThumbnailSize)
+	prefillHandler := handlers.NewPrefillHandler(personData, applicantRepo)
+	caseNoteHandler := handlers.NewCaseNoteHandler(caseNoteRepo, applicantRepo, auditRepo)
+	profileHandler := handlers.NewProfileHandler(applicantCache, applicationRepo, schemeCache, caseNoteRepo, documentRepo, consentRepo, referralRepo, schemeTranslationRepo, eventRepo, taskRepo, auditRepo, householdRepo)
+	consentHandler := handlers.NewConsentHandler(consentRepo, applicantRepo, auditRepo)
+	referralHandler := handlers.NewReferralHandler(referralRepo, applicantRepo, auditRepo)
+	incomeHandler := handlers.NewIncomeHandler(incomeProvider, incomeRepo, applicantRepo, applicantCache, consentRepo, auditRepo, webhookRepo)
+	householdHandler := handlers.NewHouseholdHandler(householdRepo, applicantRepo, auditRepo)
+	customFieldHandler := handlers.NewCustomFieldHandler(customFieldRepo, auditRepo)
+	retentionHandler := handlers.NewRetentionHandler(retentionRepo, applicantRepo, applicantCache, auditRepo, jobRepo, documentStore, retentionRules)
+	taskHandler := handlers.NewTaskHandler(taskRepo, applicantRepo, applicationRepo, userRepo, auditRepo, notifier, cfg.Tasks.ReminderLead)
+	commentHandler := handlers.NewCommentHandler(commentRepo, applicationRepo, userRepo, auditRepo, notifier)
+	trackingHandler := handlers.NewTrackingHandler(applicationRepo, trackingTokens)
+	healthHandler := handlers.NewHealthHandler(db)
+	backupHandler := handlers.NewBackupHandler(db, documentStore, jobRepo)
+	featureFlagHandler := handlers.NewFeatureFlagHandler(flagSet, featureFlagRepo, auditRepo)
+	labelHandler := handlers.NewLabelHandler()
 
 	// Create router
 	router := mux.NewRouter()
+	router.NotFoundHandler = middleware.NoRoute(router)
+	router.MethodNotAllowedHandler = router.NotFoundHandler
+
+	// API routes, under the version of the API they belong to. Breaking
+	// changes go in a new version, so clients can move over when ready.
+	apiRouter := router.PathPrefix("/api/v1").Subrouter()
+	apiRouter.NotFoundHandler = router.NotFoundHandler
+	apiRouter.MethodNotAllowedHandler = router.NotFoundHandler
+
+	// Routes that can be accessed without a token
+	publicRoutes := middleware.RouteSet{}
 
-	// API routes
-	apiRouter := router.PathPrefix("/api").Subrouter()
+	// Routes applicants signed in to the portal can use, for their own
+	// records only
+	ownedRoutes := middleware.OwnedRoutes{}
+	applicationOwner := handlers.ApplicationOwner(applicationRepo)
+
+	// Auth routes
+	publicRoutes.Add(apiRouter.HandleFunc("/auth/login", authHandler.Login).Methods("POST"))
+	publicRoutes.Add(apiRouter.HandleFunc("/portal/code", portalHandler.RequestCode).Methods("POST"))
+	publicRoutes.Add(apiRouter.HandleFunc("/portal/login", portalHandler.Login).Methods("POST"))
 
 	// Applicant routes
 	apiRouter.HandleFunc("/applicants", applicantHandler.GetApplicants).Methods("GET")
 	apiRouter.HandleFunc("/applicants", applicantHandler.CreateApplicant).Methods("POST")
-	apiRouter.HandleFunc("/applicants/{id}", applicantHandler.GetApplicant).Methods("GET")
+	apiRouter.HandleFunc("/applicants/import", applicantHandler.ImportApplicants).Methods("POST")
+	apiRouter.HandleFunc("/applicants/prefill", prefillHandler.PrefillApplicant).Methods("POST")
+	apiRouter.Handle("/applicants/stream", middleware.RequireFeature(flagSet, features.ApplicantStream)(http.HandlerFunc(applicantHandler.StreamApplicants))).Methods("GET")
+	apiRouter.HandleFunc("/applicants/by-nric/{nric}", applicantHandler.GetApplicantByIdentityNumber).Methods("GET")
+	ownedRoutes.Add(apiRouter.HandleFunc("/applicants/{id}", applicantHandler.GetApplicant).Methods("GET"), handlers.ApplicantInPath)
 	apiRouter.HandleFunc("/applicants/{id}", applicantHandler.UpdateApplicant).Methods("PUT")
+	apiRouter.HandleFunc("/applicants/{id}", applicantHandler.PatchApplicant).Methods("PATCH")
 	apiRouter.HandleFunc("/applicants/{id}", applicantHandler.DeleteApplicant).Methods("DELETE")
+	apiRouter.HandleFunc("/applicants/{id}/restore", applicantHandler.RestoreApplicant).Methods("POST")
+	apiRouter.HandleFunc("/applicants/{id}/merge", applicantHandler.MergeApplicant).Methods("POST")
+	apiRouter.HandleFunc("/applicants/{id}/anonymize", applicantHandler.AnonymizeApplicant).Methods("POST")
+	apiRouter.HandleFunc("/applicants/{id}/history", applicantHandler.GetApplicantHistory).Methods("GET")
+	apiRouter.HandleFunc("/applicants/{id}/household", householdHandler.GetSharedHousehold).Methods("GET")
+	apiRouter.HandleFunc("/applicants/{id}/household/validate", applicantHandler.ValidateHousehold).Methods("GET")
+	ownedRoutes.Add(apiRouter.HandleFunc("/applicants/{id}/applications", applicationHandler.GetApplicantApplications).Methods("GET"), handlers.ApplicantInPath)
+	apiRouter.HandleFunc("/applicants/{id}/notes", caseNoteHandler.GetCaseNotes).Methods("GET")
+	apiRouter.HandleFunc("/applicants/{id}/notes", caseNoteHandler.CreateCaseNote).Methods("POST")
+	apiRouter.HandleFunc("/applicants/{id}/consents", consentHandler.GetConsents).Methods("GET")
+	apiRouter.HandleFunc("/applicants/{id}/consents/{purpose}", consentHandler.PutConsent).Methods("PUT")
+	apiRouter.HandleFunc("/applicants/{id}/consents/{purpose}", consentHandler.WithdrawConsent).Methods("DELETE")
+	apiRouter.HandleFunc("/applicants/{id}/referrals", referralHandler.GetReferrals).Methods("GET")
+	apiRouter.HandleFunc("/applicants/{id}/referrals", referralHandler.CreateReferral).Methods("POST")
+	apiRouter.HandleFunc("/applicants/{id}/referrals/{referralId}", referralHandler.GetReferral).Methods("GET")
+	apiRouter.HandleFunc("/applicants/{id}/referrals/{referralId}", referralHandler.UpdateReferral).Methods("PUT")
+	apiRouter.HandleFunc("/applicants/{id}/referrals/{referralId}", referralHandler.DeleteReferral).Methods("DELETE")
+	apiRouter.HandleFunc("/applicants/{id}/income", incomeHandler.GetIncomeRecords).Methods("GET")
+	apiRouter.HandleFunc("/applicants/{id}/income/refresh", incomeHandler.RefreshIncome).Methods("POST")
+	ownedRoutes.Add(apiRouter.HandleFunc("/applicants/{id}/profile", profileHandler.GetApplicantProfile).Methods("GET"), handlers.ApplicantInPath)
+	apiRouter.HandleFunc("/applicants/{id}/timeline", profileHandler.GetApplicantTimeline).Methods("GET")
+	ownedRoutes.Add(apiRouter.HandleFunc("/applicants/{id}/schemes/{schemeId}/estimate", schemeHandler.EstimateBenefits).Methods("GET"), handlers.ApplicantInPath)
+	apiRouter.HandleFunc("/applicants/{id}/photo", photoHandler.GetPhoto).Methods("GET")
+	apiRouter.HandleFunc("/applicants/{id}/photo", photoHandler.PutPhoto).Methods("PUT")
+	apiRouter.HandleFunc("/applicants/{id}/photo", photoHandler.DeletePhoto).Methods("DELETE")
+
+	// Household routes
+	apiRouter.HandleFunc("/households", householdHandler.CreateHousehold).Methods("POST")
+	apiRouter.HandleFunc("/households/{id}", householdHandler.GetHousehold).Methods("GET")
+	apiRouter.HandleFunc("/households/{id}", householdHandler.DeleteHousehold).Methods("DELETE")
+	apiRouter.HandleFunc("/households/{id}/memberships", householdHandler.CreateMembership).Methods("POST")
+	apiRouter.HandleFunc("/households/{id}/memberships/{membershipId}", householdHandler.UpdateMembership).Methods("PUT")
+	apiRouter.HandleFunc("/households/{id}/memberships/{membershipId}", householdHandler.DeleteMembership).Methods("DELETE")
 
 	// Scheme routes
-	apiRouter.HandleFunc("/schemes", schemeHandler.GetSchemes).Methods("GET")
+	publicRoutes.Add(apiRouter.HandleFunc("/schemes", schemeHandler.GetSchemes).Methods("GET"))
 	apiRouter.HandleFunc("/schemes", schemeHandler.CreateScheme).Methods("POST")
-	apiRouter.HandleFunc("/schemes/eligible", schemeHandler.GetEligibleSchemes).Methods("GET")
+	ownedRoutes.Add(apiRouter.HandleFunc("/schemes/eligible", schemeHandler.GetEligibleSchemes).Methods("GET"), handlers.ApplicantInQuery)
+	apiRouter.HandleFunc("/schemes/eligible/batch", schemeHandler.RunBatchEligibility).Methods("POST")
 	apiRouter.HandleFunc("/schemes/{id}", schemeHandler.GetScheme).Methods("GET")
 	apiRouter.HandleFunc("/schemes/{id}", schemeHandler.UpdateScheme).Methods("PUT")
+	apiRouter.HandleFunc("/schemes/{id}", schemeHandler.PatchScheme).Methods("PATCH")
 	apiRouter.HandleFunc("/schemes/{id}", schemeHandler.DeleteScheme).Methods("DELETE")
+	apiRouter.HandleFunc("/schemes/{id}/publish", schemeHandler.PublishScheme).Methods("POST")
+	apiRouter.HandleFunc("/schemes/{id}/archive", schemeHandler.ArchiveScheme).Methods("POST")
+	apiRouter.HandleFunc("/schemes/{id}/clone", schemeHandler.CloneScheme).Methods("POST")
+	apiRouter.HandleFunc("/schemes/{id}/versions", schemeHandler.GetSchemeVersions).Methods("GET")
+	apiRouter.HandleFunc("/schemes/{id}/translations", schemeHandler.GetSchemeTranslations).Methods("GET")
+	apiRouter.HandleFunc("/schemes/{id}/translations/{language}", schemeHandler.PutSchemeTranslation).Methods("PUT")
+	apiRouter.HandleFunc("/schemes/{id}/translations/{language}", schemeHandler.DeleteSchemeTranslation).Methods("DELETE")
+	apiRouter.HandleFunc("/schemes/{id}/benefits", schemeHandler.CreateBenefit).Methods("POST")
+	apiRouter.HandleFunc("/schemes/{id}/benefits/{benefitId}", schemeHandler.UpdateBenefit).Methods("PUT")
+	apiRouter.HandleFunc("/schemes/{id}/benefits/{benefitId}", schemeHandler.DeleteBenefit).Methods("DELETE")
+	apiRouter.HandleFunc("/schemes/{id}/checklist", schemeHandler.CreateChecklistItem).Methods("POST")
+	apiRouter.HandleFunc("/schemes/{id}/checklist/{itemId}", schemeHandler.UpdateChecklistItem).Methods("PUT")
+	apiRouter.HandleFunc("/schemes/{id}/checklist/{itemId}", schemeHandler.DeleteChecklistItem).Methods("DELETE")
 
 	// Application routes
 	apiRouter.HandleFunc("/applications", applicationHandler.GetApplications).Methods("GET")
 	apiRouter.HandleFunc("/applications", applicationHandler.CreateApplication).Methods("POST")
-	apiRouter.HandleFunc("/applications/{id}", applicationHandler.GetApplication).Methods("GET")
+	apiRouter.HandleFunc("/applications/validate", applicationHandler.ValidateApplication).Methods("POST")
+	apiRouter.HandleFunc("/applications/export", applicationHandler.ExportApplications).Methods("GET")
+	ownedRoutes.Add(apiRouter.HandleFunc("/applications/{id}", applicationHandler.GetApplication).Methods("GET"), applicationOwner)
 	apiRouter.HandleFunc("/applications/{id}", applicationHandler.UpdateApplication).Methods("PUT")
+	apiRouter.HandleFunc("/applications/{id}", applicationHandler.PatchApplication).Methods("PATCH")
 	apiRouter.HandleFunc("/applications/{id}", applicationHandler.DeleteApplication).Methods("DELETE")
+	apiRouter.HandleFunc("/applications/{id}/restore", applicationHandler.RestoreApplication).Methods("POST")
+	apiRouter.HandleFunc("/applications/{id}/assign", applicationHandler.AssignApplication).Methods("POST")
+	apiRouter.HandleFunc("/applications/{id}/unassign", applicationHandler.UnassignApplication).Methods("POST")
+	apiRouter.HandleFunc("/applications/{id}/approve", applicationHandler.ApproveApplication).Methods("POST")
+	apiRouter.HandleFunc("/applications/{id}/reject", applicationHandler.RejectApplication).Methods("POST")
+	ownedRoutes.Add(apiRouter.HandleFunc("/applications/{id}/withdraw", applicationHandler.WithdrawApplication).Methods("POST"), applicationOwner)
+	apiRouter.HandleFunc("/applications/{id}/flags", applicationHandler.GetApplicationFlags).Methods("GET")
+	apiRouter.HandleFunc("/applications/{id}/checklist", applicationHandler.GetApplicationChecklist).Methods("GET")
+	apiRouter.HandleFunc("/applications/{id}/checklist/{itemId}", applicationHandler.TickChecklistItem).Methods("PUT")
+	apiRouter.HandleFunc("/applications/{id}/events", applicationHandler.GetApplicationEvents).Methods("GET")
+	apiRouter.HandleFunc("/applications/{id}/tracking-token", trackingHandler.GetTrackingToken).Methods("GET")
+	apiRouter.HandleFunc("/applications/{id}/comments", commentHandler.GetComments).Methods("GET")
+	apiRouter.HandleFunc("/applications/{id}/comments", commentHandler.CreateComment).Methods("POST")
+	apiRouter.HandleFunc("/applications/{id}/comments/{commentId}", commentHandler.UpdateComment).Methods("PUT")
+	apiRouter.HandleFunc("/applications/{id}/comments/{commentId}", commentHandler.DeleteComment).Methods("DELETE")
+	apiRouter.HandleFunc("/applications/{id}/documents", documentHandler.GetDocuments).Methods("GET")
+	apiRouter.HandleFunc("/applications/{id}/documents", documentHandler.UploadDocument).Methods("POST")
+	apiRouter.HandleFunc("/applications/{id}/documents/{documentId}", documentHandler.DownloadDocument).Methods("GET")
+	apiRouter.HandleFunc("/applications/{id}/documents/{documentId}", documentHandler.DeleteDocument).Methods("DELETE")
+
+	// Task routes
+	apiRouter.HandleFunc("/tasks", taskHandler.GetTasks).Methods("GET")
+	apiRouter.HandleFunc("/tasks", taskHandler.CreateTask).Methods("POST")
+	apiRouter.HandleFunc("/tasks/overdue", taskHandler.GetOverdueTasks).Methods("GET")
+	apiRouter.HandleFunc("/tasks/{id}", taskHandler.GetTask).Methods("GET")
+	apiRouter.HandleFunc("/tasks/{id}", taskHandler.UpdateTask).Methods("PUT")
+	apiRouter.HandleFunc("/tasks/{id}", taskHandler.DeleteTask).Methods("DELETE")
+
+	// Tracking routes, public so applicants can follow their applications
+	// without an account
+	publicRoutes.Add(apiRouter.HandleFunc("/track/{token}", trackingHandler.TrackApplication).Methods("GET"))
+
+	// Audit routes
+	apiRouter.HandleFunc("/audit", auditHandler.GetAuditLogs).Methods("GET")
+
+	// Webhook routes
+	apiRouter.HandleFunc("/webhooks", webhookHandler.GetWebhooks).Methods("GET")
+	apiRouter.HandleFunc("/webhooks", webhookHandler.CreateWebhook).Methods("POST")
+	apiRouter.HandleFunc("/webhooks/{id}", webhookHandler.GetWebhook).Methods("GET")
+	apiRouter.HandleFunc("/webhooks/{id}", webhookHandler.DeleteWebhook).Methods("DELETE")
+	apiRouter.HandleFunc("/webhooks/{id}/deliveries", webhookHandler.GetWebhookDeliveries).Methods("GET")
+	apiRouter.HandleFunc("/custom-fields", customFieldHandler.GetCustomFields).Methods("GET")
+	apiRouter.HandleFunc("/custom-fields", customFieldHandler.CreateCustomField).Methods("POST")
+	apiRouter.HandleFunc("/custom-fields/{key}", customFieldHandler.GetCustomField).Methods("GET")
+	apiRouter.HandleFunc("/custom-fields/{key}", customFieldHandler.UpdateCustomField).Methods("PUT")
+	apiRouter.HandleFunc("/custom-fields/{key}", customFieldHandler.DeleteCustomField).Methods("DELETE")
+
+	// Retention routes
+	apiRouter.HandleFunc("/retention/runs", retentionHandler.GetRetentionRuns).Methods("GET")
+	apiRouter.HandleFunc("/retention/runs", retentionHandler.QueueRetentionRun).Methods("POST")
+
+	// Admin routes
+	apiRouter.HandleFunc("/admin/backup", backupHandler.QueueBackup).Methods("POST")
+	apiRouter.HandleFunc("/admin/features", featureFlagHandler.GetFeatureFlags).Methods("GET")
+	apiRouter.HandleFunc("/admin/features/{name}", featureFlagHandler.SetFeatureFlag).Methods("PUT")
+	apiRouter.HandleFunc("/admin/features/{name}", featureFlagHandler.ResetFeatureFlag).Methods("DELETE")
+
+	// Label routes, public so frontends can show them before signing in
+	publicRoutes.Add(apiRouter.HandleFunc("/labels", labelHandler.GetLabels).Methods("GET"))
+
+	// Search routes
+	apiRouter.HandleFunc("/search", searchHandler.Search).Methods("GET")
+
+	// Report routes
+	apiRouter.HandleFunc("/reports/applications-summary", reportHandler.GetApplicationsSummary).Methods("GET")
+	apiRouter.HandleFunc("/reports/applications-summary/jobs", reportHandler.QueueApplicationsSummary).Methods("POST")
+	apiRouter.HandleFunc("/reports/workload", reportHandler.GetWorkload).Methods("GET")
+	apiRouter.HandleFunc("/reports/geographic", reportHandler.GetGeographicReport).Methods("GET")
+	apiRouter.HandleFunc("/reports/schemes/{id}", reportHandler.GetSchemeReport).Methods("GET")
+	apiRouter.HandleFunc("/reports/schemes/{id}/coverage", reportHandler.GetSchemeCoverage).Methods("GET")
+
+	// Job routes
+	apiRouter.HandleFunc("/jobs/{id}", jobHandler.GetJob).Methods("GET")
+	apiRouter.HandleFunc("/jobs/{id}/retry", jobHandler.RetryJob).Methods("POST")
+
+	// Require a valid token on all other API routes, and keep applicants to
+	// their own records
+	apiRouter.Use(middleware.Authenticate(tokens, publicRoutes.Contains))
+	apiRouter.Use(middleware.RequireOwnership(ownedRoutes, publicRoutes))
+
+	// Limit each client's request rate and replay responses to retried
+	// requests, when there is a cache backend to keep counts and responses in
+	if _, noop := sharedCache.(cache.Noop); !noop {
+		if cfg.RateLimit.Requests > 0 {
+			apiRouter.Use(middleware.RateLimit(sharedCache, cfg.RateLimit.Requests, cfg.RateLimit.Window))
+		}
+		apiRouter.Use(middleware.Idempotency(sharedCache, cfg.Cache.IdempotencyTTL))
+	}
+
+	// Viewers cannot make changes and only see personal data in the minimal
+	// view, which others can request with ?view=minimal
+	apiRouter.Use(middleware.ReadOnly(auth.RoleViewer))
+	apiRouter.Use(middleware.Redact(auth.RoleViewer))
+	apiRouter.Use(middleware.APIVersion("1"))
+
+	// Cut responses to the fields clients ask for with ?fields=
+	apiRouter.Use(middleware.SelectFields)
+
+	// In development, hold the handlers to the OpenAPI document generated from
+	// their annotations
+	if cfg.Server.Environment == config.EnvironmentDevelopment {
+		spec, err := openapi.Load([]byte(docs.SwaggerInfo.ReadDoc()))
+		if err != nil {
+			logging.Fatal("Failed to load OpenAPI document", "error", err)
+		}
+		apiRouter.Use(middleware.ValidateContract(spec))
+		logger.Info("Validating requests and responses against the OpenAPI document")
+	}
+
+	// Unversioned paths are deprecated aliases of the version the client asks
+	// for in the API-Version header, by default v1
+	router.PathPrefix("/api/").Handler(middleware.VersionAliases("/api",
+		map[string]http.Handler{"1": apiRouter}, "1", unversionedAPIDeprecated))
+
+	// Readiness for load balancers and orchestrators, without a token
+	router.HandleFunc("/readyz", healthHandler.Ready).Methods("GET")
+
+	// Counters for Prometheus to scrape, without a token
+	router.Handle("/metrics", metrics.Handler()).Methods("GET")
 
 	// Swagger documentation
 	router.PathPrefix("/swagger/").Handler(httpSwagger.Handler(
@@ -91,45 +571,362 @@ func main() {
 		httpSwagger.DomID("swagger-ui"),
 	))
 
-	// Configure CORS middleware
-	router.Use(corsMiddleware)
+	// Answer panics in handlers with a 500 rather than a dropped connection
+	var handler http.Handler = middleware.Recover(router)
+
+	// Configure CORS middleware, around the router so responses for requests
+	// no route matched, such as preflights, carry the headers too
+	handler = middleware.CORS(cfg.CORS.AllowedOrigins)(handler)
+
+	// Configure access logging
+	if cfg.Logging.AccessLog {
+		accessLog, err := openAccessLog(cfg.Logging.Output)
+		if err != nil {
+			logging.Fatal("Failed to open access log", "error", err)
+		}
+		defer accessLog.Close()
+		handler = middleware.LogRequests(accessLog)(handler)
+	}
+
+	// Configure server
+	server := &http.Server{
+		Addr:              cfg.Server.Addr(),
+		Handler:           middleware.RequestID(handler),
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+	}
+
+	// Run queued jobs, including webhook deliveries, deliver emails and
+	// publish domain events in the background
+	dispatcher := webhooks.NewDispatcher(webhookRepo)
+	jobRunner := jobs.NewRunner(jobRepo, cfg.Jobs.Workers, cfg.Jobs.PollInterval)
+	jobRunner.Register(models.JobWebhookDelivery, dispatcher.RetryPolicy(), dispatcher.Deliver)
+	jobRunner.Register(models.JobImportApplicants, jobs.DefaultRetryPolicy, applicantHandler.RunImportJob)
+	jobRunner.Register(models.JobBatchEligibility, jobs.DefaultRetryPolicy, schemeHandler.RunBatchEligibilityJob)
+	jobRunner.Register(models.JobApplicationsReport, jobs.DefaultRetryPolicy, reportHandler.RunApplicationsSummaryJob)
+	jobRunner.Register(models.JobEligibilityReview, jobs.DefaultRetryPolicy, applicationHandler.RunEligibilityReviewJob)
+	if exporter != nil {
+		jobRunner.Register(models.JobChangeExport, jobs.DefaultRetryPolicy, exporter.Run)
+	}
+	jobRunner.Register(models.JobRetentionRun, jobs.DefaultRetryPolicy, retentionHandler.RunRetentionJob)
+	jobRunner.Register(models.JobTaskReminders, jobs.DefaultRetryPolicy, taskHandler.RunTaskReminderJob)
+	jobRunner.Register(models.JobBackup, jobs.DefaultRetryPolicy, backupHandler.RunBackupJob)
+
+	// Queue recurring jobs on their schedules
+	jobScheduler := scheduler.New(jobRepo)
+	if jobScheduler.Location, err = cfg.Scheduler.Location(); err != nil {
+		logging.Fatal("Invalid scheduler time zone", "error", err)
+	}
+	if cfg.Scheduler.EligibilityReview != config.ScheduleOff {
+		schedule, err := scheduler.Parse(cfg.Scheduler.EligibilityReview)
+		if err != nil {
+			logging.Fatal("Invalid eligibility review schedule", "error", err)
+		}
+		jobScheduler.Add(models.JobEligibilityReview, schedule, func(at time.Time) interface{} {
+			return models.EligibilityReviewJob{AsOf: at}
+		})
+	}
+	if exporter != nil && cfg.Scheduler.ChangeExport != config.ScheduleOff {
+		schedule, err := scheduler.Parse(cfg.Scheduler.ChangeExport)
+		if err != nil {
+			logging.Fatal("Invalid change export schedule", "error", err)
+		}
+		jobScheduler.Add(models.JobChangeExport, schedule, func(at time.Time) interface{} {
+			return models.ChangeExportJob{ScheduledAt: at}
+		})
+	}
+	if cfg.Scheduler.Retention != config.ScheduleOff {
+		schedule, err := scheduler.Parse(cfg.Scheduler.Retention)
+		if err != nil {
+			logging.Fatal("Invalid retention schedule", "error", err)
+		}
+		// Scheduled runs only report what they would remove unless enforced
+		jobScheduler.Add(models.JobRetentionRun, schedule, func(at time.Time) interface{} {
+			return models.RetentionRunJob{AsOf: at, DryRun: !cfg.Retention.Enforce}
+		})
+	}
+	if cfg.Scheduler.TaskReminders != config.ScheduleOff {
+		schedule, err := scheduler.Parse(cfg.Scheduler.TaskReminders)
+		if err != nil {
+			logging.Fatal("Invalid task reminder schedule", "error", err)
+		}
+		jobScheduler.Add(models.JobTaskReminders, schedule, func(at time.Time) interface{} {
+			return models.TaskReminderJob{AsOf: at}
+		})
+	}
+
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	workers.Add(3)
+	go func() {
+		defer workers.Done()
+		jobRunner.Run(workerCtx)
+	}()
+	go func() {
+		defer workers.Done()
+		jobScheduler.Run(workerCtx)
+	}()
+	go func() {
+		defer workers.Done()
+		notifier.Run(workerCtx)
+	}()
+	if relay != nil {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			relay.Run(workerCtx)
+		}()
+	}
+
+	// Serve HTTPS, over HTTP/2 where clients support it, when TLS is
+	// configured, optionally redirecting plain HTTP to it
+	var redirectServer *http.Server
+	if cfg.TLS.Enabled() {
+		redirectServer = configureTLS(server, cfg.TLS, cfg.Server)
+	}
 
 	// Start server
-	port := getEnv("PORT", "8080")
-	log.Printf("Server starting on port %s...", port)
-	log.Fatal(http.ListenAndServe(":"+port, router))
+	serverErr := make(chan error, 2)
+	go func() {
+		logger.Info("Server starting", "port", cfg.Server.Port, "tls", cfg.TLS.Enabled())
+		if cfg.TLS.Enabled() {
+			serverErr <- server.ListenAndServeTLS(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+		} else {
+			serverErr <- server.ListenAndServe()
+		}
+	}()
+	if redirectServer != nil {
+		go func() {
+			logger.Info("Redirecting HTTP to HTTPS", "port", cfg.TLS.RedirectPort)
+			serverErr <- redirectServer.ListenAndServe()
+		}()
+	}
+
+	// Wait for a shutdown signal or for the server to fail
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("Server error", "error", err)
+		}
+	case sig := <-stop:
+		logger.Info("Shutting down", "signal", sig.String())
+
+		// Stop accepting connections and let in-flight requests finish
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			logger.Error("Graceful shutdown failed", "error", err)
+			server.Close()
+		}
+	}
+	if redirectServer != nil {
+		redirectServer.Close()
+	}
+
+	// Let running jobs and email deliveries stop before the database is
+	// closed
+	stopWorkers()
+	workers.Wait()
+
+	logger.Info("Server stopped")
 }
 
-// CORS middleware to allow cross-origin requests
-func corsMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+// configureTLS prepares server to serve HTTPS as configured, with the
+// certificate in the configured files or obtained from Let's Encrypt, and
+// returns the server redirecting plain HTTP to it, or nil if there is no
+// redirect port. With Let's Encrypt the redirect port also answers HTTP-01
+// challenges; TLS-ALPN-01 challenges are answered on the HTTPS port, which
+// Let's Encrypt only reaches when it is 443.
+func configureTLS(server *http.Server, c config.TLSConfig, sc config.ServerConfig) *http.Server {
+	server.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
 
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
+	var challenges func(http.Handler) http.Handler
+	if c.Autocert() {
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(c.AutocertDomains...),
+			Cache:      autocert.DirCache(c.AutocertCache),
+			Email:      c.AutocertEmail,
 		}
+		server.TLSConfig = manager.TLSConfig()
+		server.TLSConfig.MinVersion = tls.VersionTLS12
+		challenges = manager.HTTPHandler
+	}
 
-		next.ServeHTTP(w, r)
+	if c.RedirectPort == 0 {
+		return nil
+	}
+	var redirect http.Handler = redirectToHTTPS(sc.Port)
+	if challenges != nil {
+		redirect = challenges(redirect)
+	}
+	return &http.Server{
+		Addr:              ":" + strconv.Itoa(c.RedirectPort),
+		Handler:           redirect,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       sc.ReadTimeout,
+		WriteTimeout:      sc.WriteTimeout,
+		IdleTimeout:       sc.IdleTimeout,
+	}
+}
+
+// redirectToHTTPS redirects every request to the same host and path over
+// HTTPS on the given port, keeping the method with 308 Permanent Redirect
+func redirectToHTTPS(port int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if port != 443 {
+			host = net.JoinHostPort(host, strconv.Itoa(port))
+		}
+		target := url.URL{Scheme: "https", Host: host, Path: r.URL.Path, RawPath: r.URL.RawPath, RawQuery: r.URL.RawQuery}
+		http.Redirect(w, r, target.String(), http.StatusPermanentRedirect)
 	})
 }
 
-// Helper function to get environment variable with a default value
-func getEnv(key, defaultValue string) string {
-	value := os.Getenv(key)
-	if value == "" {
-		return defaultValue
+// runMigrate handles the migrate subcommand: "up" (the default) applies
+// pending migrations and "status" lists them
+func runMigrate(db *sql.DB, dialect string, args []string) error {
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	switch command {
+	case "up":
+		applied, err := migrations.Up(db, dialect)
+		for _, m := range applied {
+			slog.Info("Applied migration", "name", m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			slog.Info("Database schema is up to date")
+		}
+		return nil
+	case "status":
+		all, err := migrations.Status(db, dialect)
+		if err != nil {
+			return err
+		}
+		for _, m := range all {
+			state := "pending"
+			if m.AppliedAt != nil {
+				state = "applied " + m.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%-40s %s\n", m.Name, state)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate command %q (expected up or status)", command)
 	}
-	return value
 }
 
-// Helper function to get environment variable as an integer
-func getEnvAsInt(key string, defaultValue int) int {
-	valueStr := getEnv(key, "")
-	if value, err := strconv.Atoi(valueStr); err == nil {
-		return value
+// runEncryption handles the encryption subcommand: "rotate" re-encrypts
+// personal data not yet sealed with the primary key, after which previous
+// keys can be removed from the configuration
+func runEncryption(applicants *models.ApplicantRepository, args []string) error {
+	if len(args) == 0 || args[0] != "rotate" {
+		command := ""
+		if len(args) > 0 {
+			command = args[0]
+		}
+		return fmt.Errorf("unknown encryption command %q (expected rotate)", command)
 	}
-	return defaultValue
+
+	slog.Info("Re-encrypting personal data", "key_id", applicants.Cipher.KeyID())
+	result, err := applicants.RotateKeys()
+	slog.Info("Re-encrypted personal data", "applicants", result.Applicants, "household_members", result.HouseholdMembers)
+	return err
 }
+
+// runSearch handles the search subcommand: "reindex" rebuilds the search
+// terms of every applicant and household member
+func runSearch(applicants *models.ApplicantRepository, args []string) error {
+	if len(args) == 0 || args[0] != "reindex" {
+		command := ""
+		if len(args) > 0 {
+			command = args[0]
+		}
+		return fmt.Errorf("unknown search command %q (expected reindex)", command)
+	}
+
+	indexed, members, err := applicants.Reindex()
+	if err != nil {
+		return err
+	}
+	slog.Info("Rebuilt search index", "applicants", indexed, "household_members", members)
+	return nil
+}
+
+// runEvents handles the events subcommand: "backfill" records the state of
+// applications made before their events were recorded, and "verify" checks
+// that replaying each application's events rebuilds its stored state
+func runEvents(events *models.ApplicationEventRepository, args []string) error {
+	command := ""
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	switch command {
+	case "backfill":
+		recorded, err := events.Backfill()
+		slog.Info("Recorded backfill events", "applications", recorded)
+		return err
+	case "verify":
+		checked, mismatches, err := events.Verify()
+		if err != nil {
+			return err
+		}
+		for _, m := range mismatches {
+			slog.Warn("Application differs from its events", "application_id", m.ApplicationID, "fields", strings.Join(m.Fields, ","))
+		}
+		slog.Info("Verified application events", "applications", checked, "mismatches", len(mismatches))
+		if len(mismatches) > 0 {
+			return fmt.Errorf("%d application(s) differ from their events", len(mismatches))
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown events command %q (expected backfill or verify)", command)
+	}
+}
+
+// runSeed handles the seed subcommand, which writes the demo data
+func runSeed(repos fixtures.Repositories) error {
+	set, err := fixtures.Load()
+	if err != nil {
+		return err
+	}
+	result, err := set.Seed(repos)
+	slog.Info("Seeded demo data", "users", result.Users.String(), "schemes", result.Schemes.String(),
+		"applicants", result.Applicants.String(), "applications", result.Applications.String())
+	return err
+}
+
+// openAccessLog opens the access log output: "stdout", "stderr" or a file,
+// which is appended to
+func openAccessLog(output string) (io.WriteCloser, error) {
+	switch output {
+	case "stdout":
+		return nopCloser{os.Stdout}, nil
+	case "stderr":
+		return nopCloser{os.Stderr}, nil
+	default:
+		return os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	}
+}
+
+// nopCloser keeps the standard streams open when the access log is closed
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
@@ -0,0 +1,65 @@
+// Package mergepatch implements JSON Merge Patch (RFC 7396): a patch is a JSON
+// document whose members replace those of the target, with null removing a
+// member and nested objects merged recursively.
+package mergepatch
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ContentType is the media type of merge patch request bodies
+const ContentType = "application/merge-patch+json"
+
+// Apply merges patch into the JSON document doc and returns the result
+func Apply(doc, patch []byte) ([]byte, error) {
+	var target interface{}
+	if err := json.Unmarshal(doc, &target); err != nil {
+		return nil, fmt.Errorf("invalid document: %v", err)
+	}
+
+	var p interface{}
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, fmt.Errorf("invalid patch: %v", err)
+	}
+
+	return json.Marshal(merge(target, p))
+}
+
+// ApplyTo marshals original, merges patch into it and unmarshals the result
+// into target, which is typically a fresh value of the same type as original
+func ApplyTo(original interface{}, patch []byte, target interface{}) error {
+	doc, err := json.Marshal(original)
+	if err != nil {
+		return fmt.Errorf("error marshaling document: %v", err)
+	}
+
+	merged, err := Apply(doc, patch)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(merged, target)
+}
+
+// merge implements the MergePatch function from RFC 7396 section 2
+func merge(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = map[string]interface{}{}
+	}
+
+	for key, value := range p {
+		if value == nil {
+			delete(t, key)
+		} else {
+			t[key] = merge(t[key], value)
+		}
+	}
+	return t
+}
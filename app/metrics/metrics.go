@@ -0,0 +1,68 @@
+// Package metrics keeps counters of events worth alerting on, such as
+// recovered panics, and serves them at /metrics in the Prometheus text
+// exposition format, so a Prometheus server or compatible agent can scrape
+// them. Counters are per process and start at zero when the server starts.
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Counter is a count that only goes up
+type Counter struct {
+	name  string
+	help  string
+	value atomic.Int64
+}
+
+// Inc adds one to the counter
+func (c *Counter) Inc() {
+	c.value.Add(1)
+}
+
+// Value returns the current count
+func (c *Counter) Value() int64 {
+	return c.value.Load()
+}
+
+var (
+	mu       sync.Mutex
+	counters = make(map[string]*Counter)
+)
+
+// NewCounter registers a counter, named in Prometheus style with a _total
+// suffix. It panics if the name is already registered, so it is meant to be
+// called when packages are initialized.
+func NewCounter(name, help string) *Counter {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, ok := counters[name]; ok {
+		panic("metrics: counter " + name + " registered twice")
+	}
+	c := &Counter{name: name, help: help}
+	counters[name] = c
+	return c
+}
+
+// Handler serves the registered counters, ordered by name
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		registered := make([]*Counter, 0, len(counters))
+		for _, c := range counters {
+			registered = append(registered, c)
+		}
+		mu.Unlock()
+		sort.Slice(registered, func(i, j int) bool { return registered[i].name < registered[j].name })
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		for _, c := range registered {
+			fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n%s %d\n", c.name, c.help, c.name, c.name, c.Value())
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/auth"
+	"one-client-view-2025tht/app/logging"
+)
+
+// RouteSet is a set of routes, used to exempt specific routes from middleware
+type RouteSet map[*mux.Route]bool
+
+// Add adds a route to the set and returns it, so it can be used inline when
+// registering routes
+func (s RouteSet) Add(route *mux.Route) *mux.Route {
+	s[route] = true
+	return route
+}
+
+// Contains reports whether the request was matched to a route in the set
+func (s RouteSet) Contains(r *http.Request) bool {
+	return s[mux.CurrentRoute(r)]
+}
+
+// Authenticate returns middleware that requires a valid bearer token on every
+// request, except those for which skip returns true. The token's claims are
+// stored in the request context, also on skipped requests that carry a valid
+// token, so that public routes can serve more to signed-in users, and the
+// request's logger records the user's ID.
+func Authenticate(tokens *auth.TokenManager, skip func(r *http.Request) bool) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			if skip != nil && skip(r) {
+				if tokenString, ok := strings.CutPrefix(header, "Bearer "); ok {
+					if claims, err := tokens.Parse(tokenString); err == nil {
+						r = withClaims(r, claims)
+					}
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			tokenString, ok := strings.CutPrefix(header, "Bearer ")
+			if !ok || tokenString == "" {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+				apierrors.Write(w, r, apierrors.Unauthorized("Missing bearer token"))
+				return
+			}
+
+			claims, err := tokens.Parse(tokenString)
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="api", error="invalid_token"`)
+				apierrors.Write(w, r, apierrors.Unauthorized("Invalid token").WithDetails(err.Error()))
+				return
+			}
+
+			next.ServeHTTP(w, withClaims(r, claims))
+		})
+	}
+}
+
+// withClaims returns a copy of r whose context holds the claims and a logger
+// recording the user's ID
+func withClaims(r *http.Request, claims *auth.Claims) *http.Request {
+	ctx := auth.NewContext(r.Context(), claims)
+	ctx = logging.NewContext(ctx, logging.FromContext(ctx).With("user_id", claims.UserID()))
+	return r.WithContext(ctx)
+}
+
+// ReadOnly returns middleware that rejects requests other than GET, HEAD and
+// OPTIONS from users with any of the given roles. It must be wrapped by
+// Authenticate.
+func ReadOnly(roles ...string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+			default:
+				if claims := auth.FromContext(r.Context()); claims != nil && slices.Contains(roles, claims.Role) {
+					apierrors.Write(w, r, apierrors.Forbidden("The "+claims.Role+" role is read-only"))
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
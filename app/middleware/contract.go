@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/logging"
+	"one-client-view-2025tht/app/openapi"
+)
+
+// ValidateContract returns middleware checking every request and response of
+// the router's routes against the OpenAPI document, for use in development.
+// Requests departing from their documented parameters are rejected with a
+// 400 before reaching the handler. Responses departing from their documented
+// statuses and schemas, and requests to routes that are not documented at
+// all, fail with a 500 listing the problems, which are also logged, so drift
+// between the handlers and their annotations is noticed as soon as it is
+// exercised. It must wrap only the handlers, inside Redact, as the minimal
+// view is not described by the document.
+func ValidateContract(spec *openapi.Spec) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := mux.CurrentRoute(r)
+			if route == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			template, err := route.GetPathTemplate()
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			op := spec.Operation(template, r.Method)
+			if op == nil {
+				contractViolation(w, r, "Operation is not documented", []string{r.Method + " " + template + " is not in the OpenAPI document"})
+				return
+			}
+
+			var body []byte
+			if isJSON(r.Header.Get("Content-Type")) && r.Body != nil {
+				if body, err = io.ReadAll(r.Body); err != nil {
+					apierrors.Write(w, r, apierrors.BadRequest("Error reading request body"))
+					return
+				}
+				r.Body = io.NopCloser(bytes.NewReader(body))
+			}
+			if problems := spec.ValidateRequest(op, r, mux.Vars(r), body); len(problems) > 0 {
+				logViolation(r, problems)
+				apierrors.Write(w, r, apierrors.New(http.StatusBadRequest, apierrors.CodeContractViolation,
+					"Request does not match the API documentation").WithDetails(problems))
+				return
+			}
+
+			buf := &bufferedResponse{ResponseWriter: w}
+			next.ServeHTTP(buf, r)
+			if buf.code == 0 {
+				buf.code = http.StatusOK
+			}
+
+			var responseBody []byte
+			if isJSON(buf.Header().Get("Content-Type")) {
+				responseBody = buf.body.Bytes()
+			}
+			if problems := spec.ValidateResponse(op, buf.code, responseBody); len(problems) > 0 {
+				buf.Header().Del("Content-Length")
+				contractViolation(w, r, "Response does not match the API documentation", problems)
+				return
+			}
+			buf.ResponseWriter.WriteHeader(buf.code)
+			buf.ResponseWriter.Write(buf.body.Bytes())
+		})
+	}
+}
+
+// contractViolation fails a request for a departure from the document, which
+// is the server's fault rather than the client's
+func contractViolation(w http.ResponseWriter, r *http.Request, message string, problems []string) {
+	logViolation(r, problems)
+	apierrors.Write(w, r, apierrors.New(http.StatusInternalServerError, apierrors.CodeContractViolation, message).WithDetails(problems))
+}
+
+func logViolation(r *http.Request, problems []string) {
+	for _, problem := range problems {
+		logging.FromContext(r.Context()).Error("Contract violation", "method", r.Method, "path", r.URL.Path, "problem", problem)
+	}
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == "application/json"
+}
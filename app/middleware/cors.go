@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"net/http"
+)
+
+// CORS returns middleware that allows cross-origin requests from the given
+// origins. "*" allows any origin. Preflight requests are passed on like any
+// other, for NoRoute to answer with the methods the path allows.
+func CORS(allowedOrigins []string) func(http.Handler) http.Handler {
+	allowAll := false
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, origin := range allowedOrigins {
+		if origin == "*" {
+			allowAll = true
+		}
+		allowed[origin] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			switch {
+			case allowAll:
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			case allowed[origin]:
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+				w.Header().Add("Vary", "Origin")
+			}
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, If-Match, X-Request-ID, API-Version")
+			w.Header().Set("Access-Control-Expose-Headers", "ETag, X-Request-ID, API-Version, Deprecation, Link, X-Result-Count, X-Result-Limit")
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
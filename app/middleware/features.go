@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"net/http"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/features"
+)
+
+// RequireFeature is middleware that serves a route only while the named
+// feature flag is on for the user, responding as to an unknown route
+// otherwise
+func RequireFeature(flags *features.Set, name string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !flags.Enabled(r.Context(), name) {
+				apierrors.NotFoundHandler().ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
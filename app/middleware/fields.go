@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"one-client-view-2025tht/app/apierrors"
+)
+
+// fieldSet is a selection of fields of an object. A field mapped to nil is
+// kept whole; one mapped to a fieldSet keeps only those of its own fields,
+// in the object or in each object of the array it holds.
+type fieldSet map[string]fieldSet
+
+// parseFields parses a comma-separated list of field names, in which
+// household.name names a field of a nested object
+func parseFields(list string) (fieldSet, bool) {
+	fields := fieldSet{}
+	for _, name := range strings.Split(list, ",") {
+		path := strings.Split(strings.TrimSpace(name), ".")
+		set := fields
+		for i, part := range path {
+			if part == "" {
+				return nil, false
+			}
+			nested, ok := set[part]
+			if ok && nested == nil {
+				// The whole field is already selected
+				break
+			}
+			if i == len(path)-1 {
+				set[part] = nil
+				break
+			}
+			if !ok {
+				nested = fieldSet{}
+				set[part] = nested
+			}
+			set = nested
+		}
+	}
+	return fields, true
+}
+
+// apply removes the fields not in the set from an object, or from each object
+// in an array
+func (f fieldSet) apply(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			nested, ok := f[key]
+			if !ok {
+				delete(v, key)
+				continue
+			}
+			if nested != nil {
+				nested.apply(item)
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			f.apply(item)
+		}
+	}
+}
+
+// applyResources selects the attributes and relationships of the primary
+// resources of a JSON:API document, keeping their type, id and links
+func (f fieldSet) applyResources(document map[string]interface{}) {
+	resources, ok := document["data"].([]interface{})
+	if !ok {
+		resources = []interface{}{document["data"]}
+	}
+	for _, resource := range resources {
+		resource, ok := resource.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, member := range []string{"attributes", "relationships"} {
+			if object, ok := resource[member].(map[string]interface{}); ok {
+				f.apply(object)
+			}
+		}
+	}
+}
+
+// SelectFields is middleware that cuts successful JSON responses to GET
+// requests down to the fields listed in ?fields=, such as
+// ?fields=id,name,household.name, so clients on slow connections need
+// not download what they do not use. Lists have the fields selected in each
+// of their items, as do the lines of NDJSON streams, and JSON:API documents
+// in the attributes and relationships of their primary resources. Fields not in the response are ignored.
+// Handlers are unaware of the selection. It must wrap ValidateContract, as
+// sparse responses are not described by the document.
+func SelectFields(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.Method != http.MethodGet || !query.Has("fields") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		fields, ok := parseFields(query.Get("fields"))
+		if !ok {
+			apierrors.Write(w, r, apierrors.BadRequest("Invalid fields").
+				WithDetails("fields must be a comma-separated list of field names, such as id,name,household.name"))
+			return
+		}
+
+		buf := &bufferedResponse{ResponseWriter: w}
+		buf.rewrite = func(value interface{}) {
+			if buf.code < 200 || buf.code > 299 {
+				return
+			}
+			if document, ok := value.(map[string]interface{}); ok && document["jsonapi"] != nil {
+				fields.applyResources(document)
+				return
+			}
+			fields.apply(value)
+		}
+		next.ServeHTTP(buf, r)
+		buf.flush()
+	})
+}
@@ -0,0 +1,190 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/auth"
+	"one-client-view-2025tht/app/cache"
+	"one-client-view-2025tht/app/logging"
+)
+
+// IdempotencyKeyHeader carries the client's key for a retryable request
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// maxIdempotencyKeyLength is the longest key accepted
+const maxIdempotencyKeyLength = 255
+
+// idempotencyLockTTL bounds how long a key stays reserved by a request that
+// never completes, e.g. because the server stopped
+const idempotencyLockTTL = 5 * time.Minute
+
+// replayedHeaders are the response headers stored with a response and sent
+// again when it is replayed
+var replayedHeaders = []string{"Content-Type", "Location", "ETag"}
+
+// idempotencyRecord is stored under an idempotency key: first while the
+// request is in progress, then with its response
+type idempotencyRecord struct {
+	Fingerprint string            `json:"fingerprint"` // Hash of the request body
+	Done        bool              `json:"done"`
+	Status      int               `json:"status,omitempty"`
+	Header      map[string]string `json:"header,omitempty"`
+	Body        []byte            `json:"body,omitempty"`
+}
+
+// Idempotency returns middleware that makes POST and PATCH requests carrying
+// an Idempotency-Key header safe to retry. The first request with a key runs
+// and its response is stored for ttl; later requests with the same key from
+// the same user get the stored response, marked with Idempotent-Replayed. A
+// key reused with a different body is rejected with 422, and a retry while
+// the first request is still running with 409. Server errors are not stored,
+// so the request can be retried. It must be wrapped by Authenticate.
+func Idempotency(store cache.Cache, ttl time.Duration) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" || (r.Method != http.MethodPost && r.Method != http.MethodPatch) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				apierrors.Write(w, r, apierrors.BadRequest("Idempotency-Key must be at most 255 characters"))
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				apierrors.Write(w, r, apierrors.BadRequest("Failed to read request body"))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			fingerprint := sha256.Sum256(body)
+			record := idempotencyRecord{Fingerprint: hex.EncodeToString(fingerprint[:])}
+
+			cacheKey := idempotencyCacheKey(r, key)
+			pending, _ := json.Marshal(record)
+			reserved, err := store.Add(r.Context(), cacheKey, pending, idempotencyLockTTL)
+			if err != nil {
+				// Without the store the key cannot be honoured; run the
+				// request rather than fail it
+				logging.FromContext(r.Context()).Warn("Idempotency store unavailable", "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !reserved {
+				replayIdempotent(w, r, store, cacheKey, record.Fingerprint)
+				return
+			}
+
+			capture := &capturingResponse{ResponseWriter: w}
+			next.ServeHTTP(capture, r)
+
+			if capture.status() >= http.StatusInternalServerError {
+				if err := store.Delete(r.Context(), cacheKey); err != nil {
+					logging.FromContext(r.Context()).Error("Failed to release idempotency key", "error", err)
+				}
+				return
+			}
+
+			record.Done = true
+			record.Status = capture.status()
+			record.Header = make(map[string]string)
+			for _, name := range replayedHeaders {
+				if value := w.Header().Get(name); value != "" {
+					record.Header[name] = value
+				}
+			}
+			record.Body = capture.body.Bytes()
+			data, err := json.Marshal(record)
+			if err == nil {
+				err = store.Set(r.Context(), cacheKey, data, ttl)
+			}
+			if err != nil {
+				logging.FromContext(r.Context()).Error("Failed to store idempotent response", "error", err)
+			}
+		})
+	}
+}
+
+// idempotencyCacheKey scopes a client's key to its user, method and path, so
+// keys chosen by different users never collide
+func idempotencyCacheKey(r *http.Request, key string) string {
+	user := ""
+	if claims := auth.FromContext(r.Context()); claims != nil {
+		user = claims.UserID()
+	}
+	sum := sha256.Sum256([]byte(user + "\x00" + r.Method + "\x00" + r.URL.Path + "\x00" + key))
+	return "idempotency:" + hex.EncodeToString(sum[:])
+}
+
+// replayIdempotent answers a request whose key is already reserved
+func replayIdempotent(w http.ResponseWriter, r *http.Request, store cache.Cache, cacheKey, fingerprint string) {
+	data, ok, err := store.Get(r.Context(), cacheKey)
+	if err != nil {
+		apierrors.Write(w, r, apierrors.Internal("Failed to read idempotency key", err))
+		return
+	}
+
+	var record idempotencyRecord
+	if !ok || json.Unmarshal(data, &record) != nil {
+		// Expired or released between the reservation attempt and now
+		apierrors.Write(w, r, apierrors.Conflict("A request with this Idempotency-Key is in progress; retry later"))
+		return
+	}
+	if record.Fingerprint != fingerprint {
+		apierrors.Write(w, r, apierrors.Unprocessable("Idempotency-Key has already been used with a different request body"))
+		return
+	}
+	if !record.Done {
+		apierrors.Write(w, r, apierrors.Conflict("A request with this Idempotency-Key is in progress; retry later"))
+		return
+	}
+
+	for name, value := range record.Header {
+		w.Header().Set(name, value)
+	}
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.WriteHeader(record.Status)
+	w.Write(record.Body)
+}
+
+// capturingResponse passes a response through while keeping a copy of its
+// status and body
+type capturingResponse struct {
+	http.ResponseWriter
+	code int
+	body bytes.Buffer
+}
+
+func (c *capturingResponse) WriteHeader(code int) {
+	if c.code == 0 {
+		c.code = code
+	}
+	c.ResponseWriter.WriteHeader(code)
+}
+
+func (c *capturingResponse) Write(p []byte) (int, error) {
+	if c.code == 0 {
+		c.code = http.StatusOK
+	}
+	c.body.Write(p)
+	return c.ResponseWriter.Write(p)
+}
+
+// status returns the response status, which is 200 if the handler wrote
+// nothing
+func (c *capturingResponse) status() int {
+	if c.code == 0 {
+		return http.StatusOK
+	}
+	return c.code
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"one-client-view-2025tht/app/logging"
+	"one-client-view-2025tht/app/requestid"
+)
+
+// RequestID assigns every request an ID, reusing the client's X-Request-ID
+// when it is valid. The ID is stored in the request context, along with a
+// logger whose lines carry it, and echoed in the response headers.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+
+		w.Header().Set(requestid.Header, id)
+		ctx := requestid.NewContext(r.Context(), id)
+		ctx = logging.NewContext(ctx, logging.Component("http").With("request_id", id))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// accessLogEntry is a single structured access log line
+type accessLogEntry struct {
+	Time       string  `json:"time"`
+	RequestID  string  `json:"request_id,omitempty"`
+	Method     string  `json:"method"`
+	Path       string  `json:"path"`
+	Status     int     `json:"status"`
+	DurationMS float64 `json:"duration_ms"`
+	Bytes      int64   `json:"bytes"`
+	RemoteAddr string  `json:"remote_addr"`
+	UserAgent  string  `json:"user_agent,omitempty"`
+}
+
+// LogRequests returns middleware that writes one JSON line per request to out,
+// recording the method, path, status, latency and response size. It should be
+// wrapped by RequestID so entries carry the request ID.
+func LogRequests(out io.Writer) func(http.Handler) http.Handler {
+	var mu sync.Mutex
+	enc := json.NewEncoder(out)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w}
+
+			defer func() {
+				entry := accessLogEntry{
+					Time:       start.UTC().Format(time.RFC3339Nano),
+					RequestID:  requestid.FromContext(r.Context()),
+					Method:     r.Method,
+					Path:       r.URL.Path,
+					Status:     rec.status(),
+					DurationMS: float64(time.Since(start).Microseconds()) / 1000,
+					Bytes:      rec.bytes,
+					RemoteAddr: r.RemoteAddr,
+					UserAgent:  r.UserAgent(),
+				}
+
+				mu.Lock()
+				enc.Encode(entry)
+				mu.Unlock()
+			}()
+
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
+
+// statusRecorder captures the status code and body size written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	code  int
+	bytes int64
+}
+
+func (rec *statusRecorder) WriteHeader(code int) {
+	if rec.code == 0 {
+		rec.code = code
+	}
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+func (rec *statusRecorder) Write(b []byte) (int, error) {
+	if rec.code == 0 {
+		rec.code = http.StatusOK
+	}
+	n, err := rec.ResponseWriter.Write(b)
+	rec.bytes += int64(n)
+	return n, err
+}
+
+// Flush forwards to the underlying writer so streaming responses still work
+func (rec *statusRecorder) Flush() {
+	if f, ok := rec.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (rec *statusRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+// status returns the recorded status, defaulting to 200 if the handler wrote
+// nothing
+func (rec *statusRecorder) status() int {
+	if rec.code == 0 {
+		return http.StatusOK
+	}
+	return rec.code
+}
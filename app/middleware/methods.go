@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+)
+
+// routeMethods are the methods routes are registered for, in the order Allow
+// headers list them
+var routeMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// allowedMethods returns the methods router has a route for at the path of r,
+// with HEAD after GET and OPTIONS last if there are any
+func allowedMethods(router *mux.Router, r *http.Request) []string {
+	var allowed []string
+	for _, method := range routeMethods {
+		probe := r.Clone(r.Context())
+		probe.Method = method
+		var match mux.RouteMatch
+		if router.Match(probe, &match) && match.MatchErr == nil {
+			allowed = append(allowed, method)
+			if method == http.MethodGet {
+				allowed = append(allowed, http.MethodHead)
+			}
+		}
+	}
+	if len(allowed) > 0 {
+		allowed = append(allowed, http.MethodOptions)
+	}
+	return allowed
+}
+
+// NoRoute returns the handler for requests router has no route for, to set
+// as both its NotFoundHandler and MethodNotAllowedHandler. When the path has
+// routes for other methods, it serves HEAD as GET without the body, answers
+// OPTIONS with 204 No Content, and responds to anything else with 405 Method
+// Not Allowed, each with an Allow header listing the methods. Preflight
+// requests also get the methods in Access-Control-Allow-Methods. Paths with
+// no routes get 404.
+//
+// It finds the methods by matching the request again with each of them, since
+// mux reports a wrong method on a subrouter's route as not found.
+func NoRoute(router *mux.Router) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		allowed := allowedMethods(router, r)
+		if len(allowed) == 0 {
+			apierrors.NotFoundHandler().ServeHTTP(w, r)
+			return
+		}
+		allow := strings.Join(allowed, ", ")
+
+		switch r.Method {
+		case http.MethodHead:
+			if allowed[0] == http.MethodGet {
+				get := r.Clone(r.Context())
+				get.Method = http.MethodGet
+				head := &headResponse{ResponseWriter: w}
+				router.ServeHTTP(head, get)
+				head.finish()
+				return
+			}
+		case http.MethodOptions:
+			w.Header().Set("Allow", allow)
+			if r.Header.Get("Access-Control-Request-Method") != "" {
+				w.Header().Set("Access-Control-Allow-Methods", allow)
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		w.Header().Set("Allow", allow)
+		apierrors.Write(w, r, apierrors.New(http.StatusMethodNotAllowed, apierrors.CodeMethodNotAllowed, "Method not allowed").
+			WithDetails("allowed methods: "+allow))
+	})
+}
+
+// headResponse answers a HEAD request with the headers of the GET response,
+// dropping its body but giving its length in Content-Length
+type headResponse struct {
+	http.ResponseWriter
+	code        int
+	length      int64
+	wroteHeader bool
+}
+
+func (h *headResponse) WriteHeader(code int) {
+	if h.code == 0 {
+		h.code = code
+	}
+}
+
+func (h *headResponse) Write(b []byte) (int, error) {
+	if h.code == 0 {
+		h.code = http.StatusOK
+	}
+	h.length += int64(len(b))
+	return len(b), nil
+}
+
+// Flush sends the headers, after which the length can no longer be given
+func (h *headResponse) Flush() {
+	h.writeHeader()
+	if f, ok := h.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (h *headResponse) Unwrap() http.ResponseWriter {
+	return h.ResponseWriter
+}
+
+func (h *headResponse) writeHeader() {
+	if h.wroteHeader {
+		return
+	}
+	h.wroteHeader = true
+	if h.code == 0 {
+		h.code = http.StatusOK
+	}
+	h.ResponseWriter.WriteHeader(h.code)
+}
+
+// finish sends the headers, with the length of the body the handler wrote
+func (h *headResponse) finish() {
+	if !h.wroteHeader && h.length > 0 && h.Header().Get("Content-Length") == "" {
+		h.Header().Set("Content-Length", strconv.FormatInt(h.length, 10))
+	}
+	h.writeHeader()
+}
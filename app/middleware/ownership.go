@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/auth"
+)
+
+// Owner returns the ID of the applicant who owns the resource a request is
+// for, or "" if the resource does not exist
+type Owner func(r *http.Request) (string, error)
+
+// OwnedRoutes maps the routes applicants may use to the owner of the resource
+// each request is for
+type OwnedRoutes map[*mux.Route]Owner
+
+// Add adds a route with the owner of its resources and returns it, so it can
+// be used inline when registering routes
+func (o OwnedRoutes) Add(route *mux.Route, owner Owner) *mux.Route {
+	o[route] = owner
+	return route
+}
+
+// RequireOwnership returns middleware that limits applicants, whose tokens
+// carry auth.RoleApplicant and their applicant ID as subject, to the routes
+// in owned and to resources they own. Other routes are forbidden, and the
+// resources of other applicants are reported not found, so that applicants
+// cannot learn which exist. Requests with other roles, and to the routes in
+// public, are passed through. It must be wrapped by Authenticate.
+func RequireOwnership(owned OwnedRoutes, public RouteSet) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims := auth.FromContext(r.Context())
+			if claims == nil || claims.Role != auth.RoleApplicant || public.Contains(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			owner, ok := owned[mux.CurrentRoute(r)]
+			if !ok {
+				apierrors.Write(w, r, apierrors.Forbidden("Not available to applicants"))
+				return
+			}
+
+			ownerID, err := owner(r)
+			if err != nil {
+				apierrors.Write(w, r, apierrors.Internal("Failed to check ownership", err))
+				return
+			}
+			if ownerID != claims.UserID() {
+				apierrors.Write(w, r, apierrors.NotFound("Resource not found"))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
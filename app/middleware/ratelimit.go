@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/auth"
+	"one-client-view-2025tht/app/cache"
+	"one-client-view-2025tht/app/logging"
+)
+
+// RateLimit returns middleware allowing each client at most limit requests
+// per fixed window. Authenticated clients are counted by user and others by
+// IP address, so the login endpoint is limited too. Counters are kept in
+// counters, so replicas sharing a Redis cache enforce one limit between them.
+// Requests over the limit get 429 with Retry-After; if the counters cannot be
+// reached, requests are allowed. It must be wrapped by Authenticate.
+func RateLimit(counters cache.Cache, limit int, window time.Duration) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			now := time.Now()
+			start := now.Truncate(window)
+			reset := start.Add(window)
+
+			key := "ratelimit:" + rateLimitClient(r) + ":" + strconv.FormatInt(start.Unix(), 10)
+			count, err := counters.Increment(r.Context(), key, window)
+			if err != nil {
+				logging.FromContext(r.Context()).Warn("Rate limit counters unavailable", "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			remaining := int64(limit) - count
+			if remaining < 0 {
+				remaining = 0
+			}
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit))
+			w.Header().Set("X-RateLimit-Remaining", strconv.FormatInt(remaining, 10))
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+
+			if count > int64(limit) {
+				retryAfter := int(reset.Sub(now).Seconds() + 0.999)
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				apierrors.Write(w, r, apierrors.TooManyRequests("Rate limit exceeded; retry after "+strconv.Itoa(retryAfter)+" seconds"))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rateLimitClient identifies the client a request is counted against
+func rateLimitClient(r *http.Request) string {
+	if claims := auth.FromContext(r.Context()); claims != nil {
+		return "user:" + claims.UserID()
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/logging"
+	"one-client-view-2025tht/app/metrics"
+)
+
+// panicsRecovered counts the panics Recover has caught
+var panicsRecovered = metrics.NewCounter("http_panics_recovered_total", "Panics in request handlers recovered from.")
+
+// Recover returns middleware that turns a panic in a handler into a 500
+// response, logging the panic and its stack with the request's logger and
+// counting it in http_panics_recovered_total. If the handler had already
+// started its response, the connection is closed instead, so the client does
+// not take the partial response for a whole one. It should be wrapped by
+// RequestID, and by LogRequests so the 500 is in the access log.
+func Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w}
+
+		defer func() {
+			p := recover()
+			if p == nil {
+				return
+			}
+			if p == http.ErrAbortHandler {
+				// Deliberate aborts are not failures
+				panic(p)
+			}
+
+			panicsRecovered.Inc()
+			logging.FromContext(r.Context()).Error("Panic serving request",
+				"method", r.Method, "path", r.URL.Path, "panic", fmt.Sprint(p), "stack", string(debug.Stack()))
+
+			if rec.code != 0 {
+				panic(http.ErrAbortHandler)
+			}
+			apierrors.Write(rec, r, apierrors.Internal("Internal server error", nil))
+		}()
+
+		next.ServeHTTP(rec, r)
+	})
+}
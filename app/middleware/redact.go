@@ -0,0 +1,264 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+	"one-client-view-2025tht/app/auth"
+	"one-client-view-2025tht/app/jsonapi"
+	"one-client-view-2025tht/app/models"
+)
+
+// Response views, selected with the view query parameter
+const (
+	ViewFull    = "full"    // Responses as written by the handler
+	ViewMinimal = "minimal" // Personal data masked or removed; see redactions
+)
+
+// redaction rewrites the value of a JSON field in the minimal view. It
+// returns false to remove the field.
+type redaction func(value interface{}) (interface{}, bool)
+
+// redactions lists the fields rewritten in the minimal view, wherever they
+// appear in a response: in applicants, in applications embedding them, and
+// in audit snapshots and changes. The text of case notes cannot be masked, so
+// it is removed.
+var redactions = map[string]redaction{
+	"identity_number": maskStrings(models.MaskIdentityNumber),
+	"date_of_birth":   maskStrings(models.MaskDateOfBirth),
+	"phone":           maskStrings(models.MaskPhone),
+	"address":         postalDistrictOnly,
+	"household":       omit,
+	"body":            omit,
+}
+
+// omit removes a field
+func omit(interface{}) (interface{}, bool) { return nil, false }
+
+// maskStrings returns a redaction applying mask to a string value, or to
+// every string nested in an object or array value
+func maskStrings(mask func(string) string) redaction {
+	var apply func(value interface{}) interface{}
+	apply = func(value interface{}) interface{} {
+		switch v := value.(type) {
+		case string:
+			return mask(v)
+		case map[string]interface{}:
+			for key, item := range v {
+				v[key] = apply(item)
+			}
+		case []interface{}:
+			for i, item := range v {
+				v[i] = apply(item)
+			}
+		}
+		return value
+	}
+	return func(value interface{}) (interface{}, bool) {
+		return apply(value), true
+	}
+}
+
+// postalDistrictOnly reduces an address to its postal district, or both
+// addresses of an audited change
+func postalDistrictOnly(value interface{}) (interface{}, bool) {
+	address, ok := value.(map[string]interface{})
+	if !ok {
+		return value, true
+	}
+	_, hasOld := address["old"]
+	_, hasNew := address["new"]
+	if hasOld || hasNew {
+		for key, item := range address {
+			address[key], _ = postalDistrictOnly(item)
+		}
+		return address, true
+	}
+	return map[string]interface{}{"postal_district": address["postal_district"]}, true
+}
+
+// redact applies redactions to every object nested in a decoded JSON value
+func redact(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if rewrite, ok := redactions[key]; ok {
+				if item, keep := rewrite(item); keep {
+					v[key] = item
+				} else {
+					delete(v, key)
+				}
+				continue
+			}
+			redact(item)
+		}
+	case []interface{}:
+		for _, item := range v {
+			redact(item)
+		}
+	}
+}
+
+// Redact returns middleware that serves the minimal view of JSON responses
+// when the request has ?view=minimal, or always to users with any of the
+// given roles, who may not request the full view. Handlers are unaware of the
+// view. It must be wrapped by Authenticate.
+func Redact(minimalRoles ...string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			view := r.URL.Query().Get("view")
+			if view != "" && view != ViewFull && view != ViewMinimal {
+				apierrors.Write(w, r, apierrors.BadRequest("Invalid view").WithDetails("view must be full or minimal"))
+				return
+			}
+
+			if claims := auth.FromContext(r.Context()); claims != nil && slices.Contains(minimalRoles, claims.Role) {
+				if view == ViewFull {
+					apierrors.Write(w, r, apierrors.Forbidden("The full view is not available to the "+claims.Role+" role"))
+					return
+				}
+				view = ViewMinimal
+			}
+
+			if view != ViewMinimal {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			buf := &bufferedResponse{ResponseWriter: w, rewrite: redact}
+			next.ServeHTTP(buf, r)
+			buf.flush()
+		})
+	}
+}
+
+// ndjsonMediaType is the media type of newline-delimited JSON, which streams
+// one JSON value per line
+const ndjsonMediaType = "application/x-ndjson"
+
+// bufferedResponse holds a handler's response so it can be rewritten.
+// Newline-delimited JSON bodies are not held but rewritten a line at a time
+// as they are written, so streams stay streams.
+type bufferedResponse struct {
+	http.ResponseWriter
+	code      int
+	body      bytes.Buffer
+	rewrite   func(value interface{}) // Applied to decoded JSON bodies, or to each line of NDJSON; nil for none
+	streaming bool
+}
+
+func (b *bufferedResponse) WriteHeader(code int) {
+	if b.code == 0 {
+		b.code = code
+	}
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	if b.code == 0 {
+		b.code = http.StatusOK
+	}
+	if !b.streaming && b.rewrite != nil && b.body.Len() == 0 && b.mediaType() == ndjsonMediaType {
+		b.streaming = true
+		b.Header().Del("Content-Length")
+		b.ResponseWriter.WriteHeader(b.code)
+	}
+	b.body.Write(p)
+	if b.streaming {
+		if err := b.writeLines(false); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush sends the complete lines of a stream written so far
+func (b *bufferedResponse) Flush() {
+	if !b.streaming {
+		return
+	}
+	if f, ok := b.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (b *bufferedResponse) Unwrap() http.ResponseWriter {
+	return b.ResponseWriter
+}
+
+func (b *bufferedResponse) mediaType() string {
+	mediaType, _, _ := mime.ParseMediaType(b.Header().Get("Content-Type"))
+	return mediaType
+}
+
+// writeLines rewrites and writes the complete lines of a stream held in body,
+// and the last, unterminated line too if final is set
+func (b *bufferedResponse) writeLines(final bool) error {
+	for b.body.Len() > 0 {
+		data := b.body.Bytes()
+		end := bytes.IndexByte(data, '\n')
+		if end < 0 {
+			if !final {
+				return nil
+			}
+			end = len(data) - 1
+		}
+		line := b.body.Next(end + 1)
+		if len(bytes.TrimSpace(line)) > 0 {
+			line = b.rewriteJSON(line)
+		}
+		if _, err := b.ResponseWriter.Write(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rewriteJSON applies rewrite to an encoded JSON value, returning it
+// re-encoded with a trailing newline, or unchanged if it cannot be decoded
+func (b *bufferedResponse) rewriteJSON(data []byte) []byte {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return data
+	}
+	b.rewrite(value)
+	var out bytes.Buffer
+	if err := json.NewEncoder(&out).Encode(value); err != nil {
+		return data
+	}
+	return out.Bytes()
+}
+
+// flush writes the response, applying rewrite to a JSON or JSON:API body.
+// Other bodies, such as CSV exports, are written unchanged, as are all
+// bodies if rewrite is nil.
+func (b *bufferedResponse) flush() {
+	if b.streaming {
+		b.writeLines(true)
+		return
+	}
+
+	body := b.body.Bytes()
+	mediaType := b.mediaType()
+	if b.rewrite != nil && (mediaType == "application/json" || mediaType == jsonapi.MediaType) && len(body) > 0 {
+		body = b.rewriteJSON(body)
+	}
+
+	if b.code == 0 {
+		b.code = http.StatusOK
+	}
+	if b.Header().Get("Content-Length") != "" {
+		b.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	}
+	b.ResponseWriter.WriteHeader(b.code)
+	b.ResponseWriter.Write(body)
+}
@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"one-client-view-2025tht/app/apierrors"
+)
+
+// APIVersionHeader is the header in which clients of unversioned paths ask
+// for a version of the API, and in which every versioned response names the
+// version that served it
+const APIVersionHeader = "API-Version"
+
+// APIVersion returns middleware naming the version of the API that served
+// each response
+func APIVersion(version string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(APIVersionHeader, version)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// VersionAliases returns a handler serving the deprecated unversioned paths
+// under prefix, such as /api/applicants, from the versions of the API mounted
+// at prefix/v{version}. The version is negotiated with the API-Version
+// request header, and is current if the header is absent, so existing
+// clients keep the behaviour they were written against until they opt in to
+// another. Aliased responses carry a Deprecation header with the time the
+// aliases were deprecated and a Link to the versioned path.
+//
+// Paths that already name a version are passed to it unchanged, so for
+// example a wrong method on a versioned path is still reported as such.
+func VersionAliases(prefix string, versions map[string]http.Handler, current string, deprecatedAt time.Time) http.Handler {
+	supported := make([]string, 0, len(versions))
+	for version := range versions {
+		supported = append(supported, version)
+	}
+	slices.Sort(supported)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rest := strings.TrimPrefix(r.URL.Path, prefix)
+		segment, _, _ := strings.Cut(strings.TrimPrefix(rest, "/"), "/")
+		if version, ok := strings.CutPrefix(segment, "v"); ok && isVersion(version) {
+			handler, ok := versions[version]
+			if !ok {
+				apierrors.Write(w, r, apierrors.NotFound("Unknown API version").
+					WithDetails("supported versions: "+strings.Join(supported, ", ")))
+				return
+			}
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		version := strings.TrimPrefix(strings.TrimSpace(r.Header.Get(APIVersionHeader)), "v")
+		if version == "" {
+			version = current
+		}
+		handler, ok := versions[version]
+		if !ok {
+			apierrors.Write(w, r, apierrors.NotAcceptable("Unsupported API version").
+				WithDetails("supported versions: "+strings.Join(supported, ", ")))
+			return
+		}
+
+		successor := prefix + "/v" + version + rest
+		w.Header().Set("Deprecation", "@"+strconv.FormatInt(deprecatedAt.Unix(), 10))
+		w.Header().Add("Link", "<"+successor+`>; rel="successor-version"`)
+
+		aliased := r.Clone(r.Context())
+		aliased.URL.Path = successor
+		aliased.URL.RawPath = ""
+		handler.ServeHTTP(w, aliased)
+	})
+}
+
+// isVersion reports whether s is a version number
+func isVersion(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
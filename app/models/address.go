@@ -0,0 +1,86 @@
+package models
+
+import "strings"
+
+// postalDistricts maps the postal sector, the first two digits of a postal
+// code, to its postal district
+var postalDistricts = map[string]string{
+	"01": "01", "02": "01", "03": "01", "04": "01", "05": "01", "06": "01",
+	"07": "02", "08": "02",
+	"14": "03", "15": "03", "16": "03",
+	"09": "04", "10": "04",
+	"11": "05", "12": "05", "13": "05",
+	"17": "06",
+	"18": "07", "19": "07",
+	"20": "08", "21": "08",
+	"22": "09", "23": "09",
+	"24": "10", "25": "10", "26": "10", "27": "10",
+	"28": "11", "29": "11", "30": "11",
+	"31": "12", "32": "12", "33": "12",
+	"34": "13", "35": "13", "36": "13", "37": "13",
+	"38": "14", "39": "14", "40": "14", "41": "14",
+	"42": "15", "43": "15", "44": "15", "45": "15",
+	"46": "16", "47": "16", "48": "16",
+	"49": "17", "50": "17", "81": "17",
+	"51": "18", "52": "18",
+	"53": "19", "54": "19", "55": "19", "82": "19",
+	"56": "20", "57": "20",
+	"58": "21", "59": "21",
+	"60": "22", "61": "22", "62": "22", "63": "22", "64": "22",
+	"65": "23", "66": "23", "67": "23", "68": "23",
+	"69": "24", "70": "24", "71": "24",
+	"72": "25", "73": "25",
+	"77": "26", "78": "26",
+	"75": "27", "76": "27",
+	"79": "28", "80": "28",
+}
+
+// PostalDistrict returns the postal district, "01" to "28", of a six-digit
+// postal code, or "" if the code is not in a known sector
+func PostalDistrict(postalCode string) string {
+	postalCode = strings.TrimSpace(postalCode)
+	if len(postalCode) != 6 {
+		return ""
+	}
+	for _, c := range postalCode {
+		if c < '0' || c > '9' {
+			return ""
+		}
+	}
+	return postalDistricts[postalCode[:2]]
+}
+
+// NormalizePostalDistrict returns a postal district in its stored, two-digit
+// form, e.g. "5" becomes "05"
+func NormalizePostalDistrict(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) == 1 {
+		return "0" + s
+	}
+	return s
+}
+
+// NormalizePhone returns a phone number in its stored form: without spaces,
+// hyphens or surrounding blanks
+func NormalizePhone(s string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(s))
+}
+
+// MaskPhone hides all but the last four digits of a phone number, e.g.
+// "+6591234567" becomes "*******4567"
+func MaskPhone(s string) string {
+	return MaskIdentityNumber(s)
+}
+
+// normalizeAddress trims an address's fields and derives its postal district
+func normalizeAddress(a *Address) {
+	if a == nil {
+		return
+	}
+	a.Block = strings.TrimSpace(a.Block)
+	a.Street = strings.TrimSpace(a.Street)
+	a.Unit = strings.TrimSpace(a.Unit)
+	a.Building = strings.TrimSpace(a.Building)
+	a.PostalCode = strings.TrimSpace(a.PostalCode)
+	a.PostalDistrict = PostalDistrict(a.PostalCode)
+}
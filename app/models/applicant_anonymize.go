@@ -0,0 +1,224 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"one-client-view-2025tht/app/date"
+)
+
+// ErrAnonymized is returned when anonymizing an applicant who has already
+// been anonymized
+var ErrAnonymized = errors.New("applicant has been anonymized")
+
+// AnonymizedNote replaces the text of case notes, application notes,
+// application comments and referrals of anonymized applicants
+const AnonymizedNote = "[anonymized]"
+
+// anonymizationToken returns a random name for an anonymized person, from
+// which nothing about them can be recovered
+func anonymizationToken() string {
+	return "anon-" + strings.ReplaceAll(uuid.New().String(), "-", "")[:12]
+}
+
+// anonymizedDateOfBirth keeps only the year of a date of birth, so ages
+// can still be reported in bands
+func anonymizedDateOfBirth(d date.Date) date.Date {
+	return date.New(d.Year(), time.January, 1)
+}
+
+// Anonymize irreversibly replaces the personal data of an applicant,
+// including a deleted one, while keeping what aggregate statistics need.
+//
+// Names become random tokens and dates of birth are cut to the year, for
+// the applicant and their household members. The identity number, email,
+// phone, address other than its postal district and custom field values are
+// removed, and the applicant is opted out of email. The text of their case
+// notes, application notes, comments on their applications and the reasons
+// and outcome notes of their referrals is replaced, their documents and photo
+// are removed, and the snapshots of them in the audit log, the change export
+// outbox, application events and sent webhook deliveries are dropped. Sex,
+// marital and employment status, incomes, relations and the applications
+// themselves are kept, each with an anonymize event holding its new state.
+//
+// It returns the storage keys of the removed documents and photo, which the
+// caller should delete once the transaction commits, sql.ErrNoRows if the
+// applicant does not exist, or ErrAnonymized if they already are anonymized.
+func (r *ApplicantRepository) Anonymize(id string) ([]string, error) {
+	var storageKeys []string
+	err := runInTx(r.DB, r.tx, func(tx *sql.Tx) error {
+		txRepo := r.WithTx(tx)
+
+		applicant, err := txRepo.GetByIDIncludingDeleted(id)
+		if err != nil {
+			return err
+		}
+		if applicant == nil {
+			return sql.ErrNoRows
+		}
+		if applicant.AnonymizedAt != nil {
+			return ErrAnonymized
+		}
+		now := time.Now()
+
+		name, dateOfBirth, err := r.sealPerson(anonymizationToken(), anonymizedDateOfBirth(applicant.DateOfBirth))
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`UPDATE applicants
+			  SET name = ?, date_of_birth = ?, identity_number_encrypted = NULL, identity_number_hash = NULL,
+				  email = NULL, email_opt_out = ?, phone = NULL, address_block = NULL, address_street = NULL,
+				  address_unit = NULL, address_building = NULL, postal_code = NULL, custom_fields = NULL,
+				  version = version + 1, updated_at = ?, anonymized_at = ?
+			  WHERE id = ?`,
+			name, dateOfBirth, true, now, now, id); err != nil {
+			return fmt.Errorf("error anonymizing applicant: %v", err)
+		}
+
+		for _, m := range applicant.Household {
+			name, dateOfBirth, err := r.sealPerson(anonymizationToken(), anonymizedDateOfBirth(m.DateOfBirth))
+			if err != nil {
+				return err
+			}
+			if _, err := tx.Exec(`UPDATE household_members
+				  SET name = ?, date_of_birth = ?, identity_number_encrypted = NULL, identity_number_hash = NULL, updated_at = ?
+				  WHERE id = ?`,
+				name, dateOfBirth, now, m.ID); err != nil {
+				return fmt.Errorf("error anonymizing household member: %v", err)
+			}
+		}
+
+		if _, err := tx.Exec(`DELETE FROM search_terms
+			  WHERE (entity_type = ? AND entity_id = ?)
+			  OR (entity_type = ? AND entity_id IN (SELECT id FROM household_members WHERE applicant_id = ?))`,
+			SearchTypeApplicant, id, SearchTypeHouseholdMember, id); err != nil {
+			return fmt.Errorf("error removing search terms: %v", err)
+		}
+
+		if _, err := tx.Exec(`UPDATE case_notes SET body = ? WHERE applicant_id = ?`, AnonymizedNote, id); err != nil {
+			return fmt.Errorf("error anonymizing case notes: %v", err)
+		}
+		if _, err := tx.Exec(`UPDATE applications SET notes = ? WHERE applicant_id = ? AND notes IS NOT NULL AND notes <> ''`,
+			AnonymizedNote, id); err != nil {
+			return fmt.Errorf("error anonymizing application notes: %v", err)
+		}
+		if _, err := tx.Exec(`UPDATE application_comments SET body = ?, mentions = '[]'
+			  WHERE deleted_at IS NULL AND application_id IN (SELECT id FROM applications WHERE applicant_id = ?)`,
+			AnonymizedNote, id); err != nil {
+			return fmt.Errorf("error anonymizing application comments: %v", err)
+		}
+		if _, err := tx.Exec(`UPDATE referrals SET reason = ?,
+			  outcome_notes = CASE WHEN outcome_notes IS NULL OR outcome_notes = '' THEN outcome_notes ELSE ? END
+			  WHERE applicant_id = ?`, AnonymizedNote, AnonymizedNote, id); err != nil {
+			return fmt.Errorf("error anonymizing referrals: %v", err)
+		}
+
+		keys, documentIDs, err := removeApplicantFiles(tx, id)
+		if err != nil {
+			return err
+		}
+		storageKeys = keys
+		if err := scrubSnapshots(tx, id, documentIDs); err != nil {
+			return err
+		}
+		return recordApplicationSnapshots(tx, id, AuditActionAnonymize)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return storageKeys, nil
+}
+
+// removeApplicantFiles deletes the metadata of an applicant's documents and
+// photo, returning the storage keys of their content and the IDs of the
+// documents
+func removeApplicantFiles(tx *sql.Tx, applicantID string) (keys, documentIDs []string, err error) {
+	rows, err := tx.Query(`SELECT id, storage_key FROM documents
+			  WHERE application_id IN (SELECT id FROM applications WHERE applicant_id = ?)`, applicantID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error querying documents: %v", err)
+	}
+	for rows.Next() {
+		var id, key string
+		if err := rows.Scan(&id, &key); err != nil {
+			rows.Close()
+			return nil, nil, fmt.Errorf("error scanning document row: %v", err)
+		}
+		documentIDs = append(documentIDs, id)
+		keys = append(keys, key)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error iterating document rows: %v", err)
+	}
+
+	var photoKey, thumbnailKey string
+	err = tx.QueryRow(`SELECT storage_key, thumbnail_key FROM applicant_photos WHERE applicant_id = ?`,
+		applicantID).Scan(&photoKey, &thumbnailKey)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return nil, nil, fmt.Errorf("error getting applicant photo: %v", err)
+	default:
+		keys = append(keys, photoKey, thumbnailKey)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM documents WHERE application_id IN (SELECT id FROM applications WHERE applicant_id = ?)`,
+		applicantID); err != nil {
+		return nil, nil, fmt.Errorf("error deleting documents: %v", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM applicant_photos WHERE applicant_id = ?`, applicantID); err != nil {
+		return nil, nil, fmt.Errorf("error deleting applicant photo: %v", err)
+	}
+	return keys, documentIDs, nil
+}
+
+// scrubSnapshots drops the snapshots of an applicant, their photo, case
+// notes, referrals, applications, comments on them and the given documents
+// from the audit log and the change export outbox, and the data of the
+// events of the applications, keeping who did what when.
+// Sent webhook deliveries that mention the applicant are removed.
+func scrubSnapshots(tx *sql.Tx, applicantID string, documentIDs []string) error {
+	condition := `(entity_type = ? AND entity_id = ?)
+			  OR (entity_type = ? AND entity_id = ?)
+			  OR (entity_type = ? AND entity_id IN (SELECT id FROM case_notes WHERE applicant_id = ?))
+			  OR (entity_type = ? AND entity_id IN (SELECT id FROM referrals WHERE applicant_id = ?))
+			  OR (entity_type = ? AND entity_id IN (SELECT id FROM applications WHERE applicant_id = ?))
+			  OR (entity_type = ? AND entity_id IN (SELECT c.id FROM application_comments c
+				  JOIN applications a ON a.id = c.application_id WHERE a.applicant_id = ?))`
+	args := []interface{}{
+		AuditEntityApplicant, applicantID,
+		AuditEntityPhoto, applicantID,
+		AuditEntityCaseNote, applicantID,
+		AuditEntityReferral, applicantID,
+		AuditEntityApplication, applicantID,
+		AuditEntityComment, applicantID,
+	}
+	if len(documentIDs) > 0 {
+		placeholders, ids := inClause(documentIDs)
+		condition += ` OR (entity_type = ? AND entity_id IN (` + placeholders + `))`
+		args = append(append(args, AuditEntityDocument), ids...)
+	}
+
+	if _, err := tx.Exec(`UPDATE audit_logs SET before_data = NULL, after_data = NULL, changes = NULL
+			  WHERE `+condition, args...); err != nil {
+		return fmt.Errorf("error scrubbing audit log: %v", err)
+	}
+	if _, err := tx.Exec(`UPDATE change_events SET data = NULL WHERE `+condition, args...); err != nil {
+		return fmt.Errorf("error scrubbing change events: %v", err)
+	}
+	if _, err := tx.Exec(`UPDATE application_events SET data = NULL
+			  WHERE application_id IN (SELECT id FROM applications WHERE applicant_id = ?)`, applicantID); err != nil {
+		return fmt.Errorf("error scrubbing application events: %v", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM webhook_deliveries WHERE status <> ? AND payload LIKE ?`,
+		DeliveryPending, "%"+applicantID+"%"); err != nil {
+		return fmt.Errorf("error removing webhook deliveries: %v", err)
+	}
+	return nil
+}
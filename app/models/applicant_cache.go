@@ -0,0 +1,98 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"one-client-view-2025tht/app/cache"
+	"one-client-view-2025tht/app/logging"
+)
+
+// applicantCacheKey returns the cache key of an applicant
+func applicantCacheKey(id string) string {
+	return "applicants:" + id
+}
+
+// CachedApplicantStore reads applicants, with their households, through a
+// cache for a time to live. Callers that change an applicant or its household
+// must call Invalidate once the change is committed.
+//
+// Cached applicants are encrypted with the repository's cipher, so personal
+// data is never held in plaintext outside the database, even in a shared
+// cache. Entries the cipher cannot decrypt, e.g. after a key rotation, are
+// treated as misses. As with CachedSchemeStore, cache failures are logged and
+// fall through to the repository.
+type CachedApplicantStore struct {
+	Repo   *ApplicantRepository
+	Cache  cache.Cache
+	TTL    time.Duration
+	Logger *slog.Logger
+}
+
+// NewCachedApplicantStore creates a store caching applicants read from repo
+// in c for ttl
+func NewCachedApplicantStore(repo *ApplicantRepository, c cache.Cache, ttl time.Duration) *CachedApplicantStore {
+	return &CachedApplicantStore{Repo: repo, Cache: c, TTL: ttl, Logger: logging.Component("cache")}
+}
+
+// GetByID returns the applicant with the given ID, excluding soft-deleted
+// ones, from the cache if present. It returns nil if there is none.
+func (s *CachedApplicantStore) GetByID(id string) (*Applicant, error) {
+	ctx := context.Background()
+	key := applicantCacheKey(id)
+
+	sealed, ok, err := s.Cache.Get(ctx, key)
+	if err != nil {
+		s.Logger.Warn("Failed to read from applicant cache", "key", key, "error", err)
+	}
+	if ok {
+		if a, err := s.open(sealed); err == nil {
+			return a, nil
+		}
+		s.Logger.Warn("Discarding unreadable entry in applicant cache", "key", key)
+	}
+
+	a, err := s.Repo.GetByID(id)
+	if err != nil || a == nil {
+		return a, err
+	}
+
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := s.Repo.sealField(string(data))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Cache.Set(ctx, key, []byte(ciphertext), s.TTL); err != nil {
+		s.Logger.Warn("Failed to write to applicant cache", "key", key, "error", err)
+	}
+	return a, nil
+}
+
+// open decrypts and decodes a cached applicant
+func (s *CachedApplicantStore) open(sealed []byte) (*Applicant, error) {
+	data, err := s.Repo.Cipher.Decrypt(string(sealed))
+	if err != nil {
+		return nil, err
+	}
+	var a Applicant
+	if err := json.Unmarshal([]byte(data), &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// Invalidate discards the cached applicants with the given IDs
+func (s *CachedApplicantStore) Invalidate(ids ...string) {
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = applicantCacheKey(id)
+	}
+	if err := s.Cache.Delete(context.Background(), keys...); err != nil {
+		s.Logger.Error("Failed to invalidate applicant cache", "error", err)
+	}
+}
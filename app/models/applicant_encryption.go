@@ -0,0 +1,313 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"one-client-view-2025tht/app/date"
+	"one-client-view-2025tht/app/encryption"
+)
+
+// Applicant and household member names and dates of birth, and applicant
+// identity numbers, are encrypted at rest. Since the database cannot compare
+// them, filtering and sorting on these fields is done after decryption.
+
+// storedDateLayout is the format a date of birth is encrypted in
+const storedDateLayout = date.Layout
+
+// sealField encrypts a value for an encrypted column
+func (r *ApplicantRepository) sealField(value string) (string, error) {
+	ciphertext, err := r.Cipher.Encrypt(value)
+	if err != nil {
+		return "", fmt.Errorf("error encrypting field: %v", err)
+	}
+	return ciphertext, nil
+}
+
+// openField decrypts a value read from an encrypted column. Values written
+// before the column was encrypted are returned as they are, until the key
+// rotation command encrypts them.
+func (r *ApplicantRepository) openField(value string) (string, error) {
+	if !encryption.IsEncrypted(value) {
+		return value, nil
+	}
+	return r.Cipher.Decrypt(value)
+}
+
+// sealDate encrypts a date of birth
+func (r *ApplicantRepository) sealDate(d date.Date) (string, error) {
+	return r.sealField(d.Format(storedDateLayout))
+}
+
+// openDate decrypts a date of birth. Unencrypted values may also be in the
+// form the database driver gives DATE columns.
+func (r *ApplicantRepository) openDate(value string) (date.Date, error) {
+	plaintext, err := r.openField(value)
+	if err != nil {
+		return date.Date{}, err
+	}
+	for _, layout := range []string{storedDateLayout, time.RFC3339Nano, "2006-01-02 15:04:05"} {
+		if t, err := time.Parse(layout, plaintext); err == nil {
+			return date.Of(t), nil
+		}
+	}
+	return date.Date{}, fmt.Errorf("invalid stored date %q", plaintext)
+}
+
+// sealPerson encrypts the name and date of birth of an applicant or household
+// member
+func (r *ApplicantRepository) sealPerson(name string, dateOfBirth date.Date) (string, string, error) {
+	sealedName, err := r.sealField(name)
+	if err != nil {
+		return "", "", err
+	}
+	sealedDate, err := r.sealDate(dateOfBirth)
+	if err != nil {
+		return "", "", err
+	}
+	return sealedName, sealedDate, nil
+}
+
+// openPerson decrypts a name and date of birth read from the database
+func (r *ApplicantRepository) openPerson(name, dateOfBirth string) (string, date.Date, error) {
+	openedName, err := r.openField(name)
+	if err != nil {
+		return "", date.Date{}, fmt.Errorf("error decrypting name: %v", err)
+	}
+	openedDate, err := r.openDate(dateOfBirth)
+	if err != nil {
+		return "", date.Date{}, fmt.Errorf("error decrypting date of birth: %v", err)
+	}
+	return openedName, openedDate, nil
+}
+
+// matches reports whether an applicant meets the filter's conditions on
+// encrypted fields, which whereClause cannot express
+func (f ApplicantFilter) matches(a Applicant, now time.Time) bool {
+	if f.Name != "" && !strings.Contains(strings.ToLower(a.Name), strings.ToLower(f.Name)) {
+		return false
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if f.MinAge != nil && a.DateOfBirth.After(today.AddDate(-*f.MinAge, 0, 0)) {
+		// Younger than MinAge: born after today minus MinAge years
+		return false
+	}
+	if f.MaxAge != nil && !a.DateOfBirth.After(today.AddDate(-(*f.MaxAge+1), 0, 0)) {
+		// Older than MaxAge: born on or before today minus (MaxAge + 1) years
+		return false
+	}
+	return true
+}
+
+// sortApplicants orders applicants by name, case-insensitively
+func sortApplicants(applicants []Applicant) {
+	sort.SliceStable(applicants, func(i, j int) bool {
+		return strings.ToLower(applicants[i].Name) < strings.ToLower(applicants[j].Name)
+	})
+}
+
+// sortHouseholdMembers orders household members by name, case-insensitively
+func sortHouseholdMembers(members []HouseholdMember) {
+	sort.SliceStable(members, func(i, j int) bool {
+		return strings.ToLower(members[i].Name) < strings.ToLower(members[j].Name)
+	})
+}
+
+// RotationResult counts the rows re-encrypted by RotateKeys
+type RotationResult struct {
+	Applicants       int
+	HouseholdMembers int
+}
+
+// rotationBatchSize is the number of rows RotateKeys re-encrypts per
+// transaction
+const rotationBatchSize = 500
+
+// RotateKeys re-encrypts every encrypted column not yet sealed with the
+// cipher's primary key, including plaintext written before encryption was
+// enabled, and recomputes identity number hashes and search terms. Versions
+// and update times are left unchanged, as the stored values are not. Once it
+// completes, keys other than the primary are no longer needed. It is safe to
+// run repeatedly and while the API is serving.
+func (r *ApplicantRepository) RotateKeys() (RotationResult, error) {
+	var result RotationResult
+	var err error
+
+	if result.Applicants, err = r.rotateTable("applicants",
+		`SELECT id, name, date_of_birth, identity_number_encrypted, identity_number_hash FROM applicants`,
+		r.rotateApplicant); err != nil {
+		return result, err
+	}
+	if result.HouseholdMembers, err = r.rotateTable("household_members",
+		`SELECT id, name, date_of_birth, identity_number_encrypted, identity_number_hash FROM household_members`,
+		r.rotateHouseholdMember); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// encryptedRow holds the encrypted columns of a row being rotated
+type encryptedRow struct {
+	ID             string
+	Name           string
+	DateOfBirth    string
+	IdentityNumber sql.NullString
+	IdentityHash   sql.NullString
+}
+
+// needsRotation reports whether any of the row's columns are not sealed with
+// the primary key, or its identity hash is not under the primary key
+func (r *ApplicantRepository) needsRotation(row encryptedRow) (bool, error) {
+	if r.Cipher.NeedsRotation(row.Name) || r.Cipher.NeedsRotation(row.DateOfBirth) {
+		return true, nil
+	}
+	if !row.IdentityNumber.Valid {
+		return false, nil
+	}
+	if r.Cipher.NeedsRotation(row.IdentityNumber.String) {
+		return true, nil
+	}
+	identityNumber, err := r.Cipher.Decrypt(row.IdentityNumber.String)
+	if err != nil {
+		return false, err
+	}
+	return row.IdentityHash.String != r.Cipher.Hash(identityNumber), nil
+}
+
+// rotateTable pages through a table by ID with the given query, which must
+// select the columns of encryptedRow, and re-encrypts rows needing rotation
+// with update, one batch per transaction. Updates only apply while the row's
+// stored name is as read: every save encrypts it afresh, so a row changed in
+// the meantime has already been written with the primary key.
+func (r *ApplicantRepository) rotateTable(table, query string, update func(tx *sql.Tx, row encryptedRow) (sql.Result, error)) (int, error) {
+	rotated := 0
+	after := ""
+	for {
+		rows, err := r.DB.Query(query+` WHERE id > ? ORDER BY id LIMIT ?`, after, rotationBatchSize)
+		if err != nil {
+			return rotated, fmt.Errorf("error querying %s: %v", table, err)
+		}
+
+		var batch []encryptedRow
+		for rows.Next() {
+			var row encryptedRow
+			if err := rows.Scan(&row.ID, &row.Name, &row.DateOfBirth, &row.IdentityNumber, &row.IdentityHash); err != nil {
+				rows.Close()
+				return rotated, fmt.Errorf("error scanning %s row: %v", table, err)
+			}
+			batch = append(batch, row)
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return rotated, fmt.Errorf("error iterating %s rows: %v", table, err)
+		}
+		if len(batch) == 0 {
+			return rotated, nil
+		}
+		after = batch[len(batch)-1].ID
+
+		err = WithTx(r.DB, func(tx *sql.Tx) error {
+			for _, row := range batch {
+				needed, err := r.needsRotation(row)
+				if err != nil {
+					return fmt.Errorf("error decrypting %s row %s: %v", table, row.ID, err)
+				}
+				if !needed {
+					continue
+				}
+				result, err := update(tx, row)
+				if err != nil {
+					return fmt.Errorf("error re-encrypting %s row %s: %v", table, row.ID, err)
+				}
+				if n, err := result.RowsAffected(); err == nil && n > 0 {
+					rotated++
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return rotated, err
+		}
+	}
+}
+
+// resealPerson decrypts a row's name and date of birth and encrypts them with
+// the primary key, also returning the plaintext name
+func (r *ApplicantRepository) resealPerson(row encryptedRow) (plainName, name, dateOfBirth string, err error) {
+	plainName, birthDate, err := r.openPerson(row.Name, row.DateOfBirth)
+	if err != nil {
+		return "", "", "", err
+	}
+	name, dateOfBirth, err = r.sealPerson(plainName, birthDate)
+	return plainName, name, dateOfBirth, err
+}
+
+// reindexRotated re-indexes the search terms of a rotated row under the
+// primary key, unless the rotation was skipped because the row had changed
+func (r *ApplicantRepository) reindexRotated(tx *sql.Tx, result sql.Result, entityType, id, name string) (sql.Result, error) {
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return result, err
+	}
+	return result, r.WithTx(tx).indexTerms(entityType, id, name)
+}
+
+// resealIdentityNumber decrypts a row's identity number and encrypts it with
+// the primary key, returning it with its hash under that key, both nil if
+// the row has none
+func (r *ApplicantRepository) resealIdentityNumber(row encryptedRow) (identityNumber, identityHash interface{}, err error) {
+	if !row.IdentityNumber.Valid {
+		return nil, nil, nil
+	}
+	plaintext, err := r.Cipher.Decrypt(row.IdentityNumber.String)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error decrypting identity number: %v", err)
+	}
+	if identityNumber, err = r.sealField(plaintext); err != nil {
+		return nil, nil, err
+	}
+	return identityNumber, r.Cipher.Hash(plaintext), nil
+}
+
+func (r *ApplicantRepository) rotateApplicant(tx *sql.Tx, row encryptedRow) (sql.Result, error) {
+	plainName, name, dateOfBirth, err := r.resealPerson(row)
+	if err != nil {
+		return nil, err
+	}
+	identityNumber, identityHash, err := r.resealIdentityNumber(row)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := tx.Exec(`UPDATE applicants
+			  SET name = ?, date_of_birth = ?, identity_number_encrypted = ?, identity_number_hash = ?
+			  WHERE id = ? AND name = ?`,
+		name, dateOfBirth, identityNumber, identityHash, row.ID, row.Name)
+	if err != nil {
+		return nil, err
+	}
+	return r.reindexRotated(tx, result, SearchTypeApplicant, row.ID, plainName)
+}
+
+func (r *ApplicantRepository) rotateHouseholdMember(tx *sql.Tx, row encryptedRow) (sql.Result, error) {
+	plainName, name, dateOfBirth, err := r.resealPerson(row)
+	if err != nil {
+		return nil, err
+	}
+	identityNumber, identityHash, err := r.resealIdentityNumber(row)
+	if err != nil {
+		return nil, err
+	}
+	result, err := tx.Exec(`UPDATE household_members
+			  SET name = ?, date_of_birth = ?, identity_number_encrypted = ?, identity_number_hash = ?
+			  WHERE id = ? AND name = ?`,
+		name, dateOfBirth, identityNumber, identityHash, row.ID, row.Name)
+	if err != nil {
+		return nil, err
+	}
+	return r.reindexRotated(tx, result, SearchTypeHouseholdMember, row.ID, plainName)
+}
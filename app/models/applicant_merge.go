@@ -0,0 +1,123 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Policies for resolving fields that differ between merged applicants
+const (
+	MergePreferTarget = "prefer_target" // Keep the target's values (the default)
+	MergePreferSource = "prefer_source" // Take the source's values
+)
+
+// MergePolicies lists the supported merge policies
+var MergePolicies = []string{MergePreferTarget, MergePreferSource}
+
+// MergeApplicants returns the target with its fields merged from source.
+// Where both records have a value, the policy decides which is kept; blank
+// values are always filled from the other record. The applicant stays opted
+// out of email if either record was.
+func MergeApplicants(target, source *Applicant, policy string) Applicant {
+	merged := *target
+	preferSource := policy == MergePreferSource
+
+	pick := func(t, s string) string {
+		if s != "" && (t == "" || preferSource) {
+			return s
+		}
+		return t
+	}
+
+	merged.Name = pick(target.Name, source.Name)
+	merged.IdentityNumber = pick(target.IdentityNumber, source.IdentityNumber)
+	merged.EmploymentStatus = pick(target.EmploymentStatus, source.EmploymentStatus)
+	merged.Sex = pick(target.Sex, source.Sex)
+	merged.MaritalStatus = pick(target.MaritalStatus, source.MaritalStatus)
+	merged.Email = pick(target.Email, source.Email)
+	merged.Phone = pick(target.Phone, source.Phone)
+	if source.Address != nil && (target.Address == nil || preferSource) {
+		address := *source.Address
+		merged.Address = &address
+	}
+	if !source.DateOfBirth.IsZero() && (target.DateOfBirth.IsZero() || preferSource) {
+		merged.DateOfBirth = source.DateOfBirth
+	}
+	if preferSource {
+		merged.MonthlyIncome = source.MonthlyIncome
+	}
+	merged.EmailOptOut = target.EmailOptOut || source.EmailOptOut
+
+	// Custom fields are picked one by one, like the built-in ones
+	if len(target.CustomFields) > 0 || len(source.CustomFields) > 0 {
+		merged.CustomFields = CustomValues{}
+		for key, value := range target.CustomFields {
+			merged.CustomFields[key] = value
+		}
+		for key, value := range source.CustomFields {
+			if _, ok := target.CustomFields[key]; !ok || preferSource {
+				merged.CustomFields[key] = value
+			}
+		}
+	}
+
+	merged.Household = append(append([]HouseholdMember{}, target.Household...), source.Household...)
+	for i := range merged.Household {
+		merged.Household[i].ApplicantID = target.ID
+	}
+
+	return merged
+}
+
+// Merge folds the source applicant into target, which holds the merged
+// fields: the source's household members, applications, case notes,
+// referrals and income records are moved to the target, the target is updated
+// and the source is soft-deleted without its identity number. Both records must still be at the
+// versions read, otherwise ErrVersionConflict is returned. On success
+// target.Version is incremented.
+func (r *ApplicantRepository) Merge(target *Applicant, source *Applicant) error {
+	return runInTx(r.DB, r.tx, func(tx *sql.Tx) error {
+		now := time.Now()
+
+		if _, err := tx.Exec(`UPDATE household_members SET applicant_id = ?, updated_at = ? WHERE applicant_id = ?`,
+			target.ID, now, source.ID); err != nil {
+			return fmt.Errorf("error moving household members: %v", err)
+		}
+
+		if _, err := tx.Exec(`UPDATE applications SET applicant_id = ?, version = version + 1, updated_at = ? WHERE applicant_id = ?`,
+			target.ID, now, source.ID); err != nil {
+			return fmt.Errorf("error moving applications: %v", err)
+		}
+
+		if _, err := tx.Exec(`UPDATE case_notes SET applicant_id = ? WHERE applicant_id = ?`,
+			target.ID, source.ID); err != nil {
+			return fmt.Errorf("error moving case notes: %v", err)
+		}
+
+		if _, err := tx.Exec(`UPDATE referrals SET applicant_id = ?, updated_at = ? WHERE applicant_id = ?`,
+			target.ID, now, source.ID); err != nil {
+			return fmt.Errorf("error moving referrals: %v", err)
+		}
+
+		if _, err := tx.Exec(`UPDATE income_records SET applicant_id = ? WHERE applicant_id = ?`,
+			target.ID, source.ID); err != nil {
+			return fmt.Errorf("error moving income records: %v", err)
+		}
+
+		// The source's identity number is released, so the target can take it
+		result, err := tx.Exec(`UPDATE applicants
+			  SET deleted_at = ?, identity_number_encrypted = NULL, identity_number_hash = NULL,
+				  version = version + 1, updated_at = ?
+			  WHERE id = ? AND version = ? AND deleted_at IS NULL`,
+			now, now, source.ID, source.Version)
+		if err != nil {
+			return fmt.Errorf("error deleting merged applicant: %v", err)
+		}
+		if err := checkVersioned(result); err != nil {
+			return err
+		}
+
+		return r.WithTx(tx).Update(target)
+	})
+}
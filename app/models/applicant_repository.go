@@ -2,29 +2,259 @@ package models
 
 import (
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+
+	"one-client-view-2025tht/app/encryption"
 )
 
+// ErrDuplicateIdentityNumber is returned when saving an applicant whose
+// identity number belongs to another applicant, including a deleted one
+var ErrDuplicateIdentityNumber = errors.New("another applicant has this identity number")
+
 // ApplicantRepository handles database operations for applicants
 type ApplicantRepository struct {
-	DB *sql.DB
+	DB     *sql.DB
+	Cipher *encryption.Cipher // Encrypts names, dates of birth and identity numbers at rest
+	tx     *sql.Tx
+}
+
+// NewApplicantRepository creates a new repository with the given database
+// connection and cipher
+func NewApplicantRepository(db *sql.DB, cipher *encryption.Cipher) *ApplicantRepository {
+	return &ApplicantRepository{DB: db, Cipher: cipher}
+}
+
+// WithTx returns a copy of the repository that runs its queries in tx
+func (r *ApplicantRepository) WithTx(tx *sql.Tx) *ApplicantRepository {
+	return &ApplicantRepository{DB: r.DB, Cipher: r.Cipher, tx: tx}
+}
+
+// conn returns the transaction the repository is bound to, or the database
+func (r *ApplicantRepository) conn() DBTX {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.DB
+}
+
+// applicantColumns is the column list read by scanApplicant
+const applicantColumns = `id, name, identity_number_encrypted, employment_status, sex, date_of_birth, marital_status, monthly_income, email, email_opt_out, phone, address_block, address_street, address_unit, address_building, postal_code, postal_district, version, created_at, updated_at, deleted_at, anonymized_at, custom_fields`
+
+// scanApplicant scans a row selected with applicantColumns, decrypting the
+// name, date of birth and identity number
+func (r *ApplicantRepository) scanApplicant(row rowScanner) (Applicant, error) {
+	var a Applicant
+	var name, dateOfBirth string
+	var identityNumber, email, phone sql.NullString
+	var block, street, unit, building, postalCode, postalDistrict sql.NullString
+	var deletedAt, anonymizedAt sql.NullTime
+	var customFields []byte
+
+	err := row.Scan(&a.ID, &name, &identityNumber, &a.EmploymentStatus, &a.Sex, &dateOfBirth,
+		&a.MaritalStatus, &a.MonthlyIncome, &email, &a.EmailOptOut, &phone,
+		&block, &street, &unit, &building, &postalCode, &postalDistrict,
+		&a.Version, &a.CreatedAt, &a.UpdatedAt, &deletedAt, &anonymizedAt, &customFields)
+	if err != nil {
+		return a, err
+	}
+	if customFields != nil {
+		if err := json.Unmarshal(customFields, &a.CustomFields); err != nil {
+			return a, fmt.Errorf("error unmarshaling custom fields of applicant %s: %v", a.ID, err)
+		}
+	}
+
+	if a.Name, a.DateOfBirth, err = r.openPerson(name, dateOfBirth); err != nil {
+		return a, fmt.Errorf("applicant %s: %v", a.ID, err)
+	}
+
+	a.Email = email.String
+	a.Phone = phone.String
+	if street.Valid || postalCode.Valid {
+		a.Address = &Address{
+			Block:          block.String,
+			Street:         street.String,
+			Unit:           unit.String,
+			Building:       building.String,
+			PostalCode:     postalCode.String,
+			PostalDistrict: postalDistrict.String,
+		}
+	}
+	if deletedAt.Valid {
+		a.DeletedAt = &deletedAt.Time
+	}
+	if anonymizedAt.Valid {
+		a.AnonymizedAt = &anonymizedAt.Time
+	}
+	if identityNumber.Valid {
+		if a.IdentityNumber, err = r.Cipher.Decrypt(identityNumber.String); err != nil {
+			return a, fmt.Errorf("error decrypting identity number of applicant %s: %v", a.ID, err)
+		}
+	}
+	return a, nil
+}
+
+// contactColumns returns the values of the phone and address columns, in
+// the order of applicantColumns, normalizing the applicant's phone number and
+// address. The address columns are all NULL when the applicant has none.
+func contactColumns(a *Applicant) []interface{} {
+	a.Phone = NormalizePhone(a.Phone)
+	values := []interface{}{nullString(a.Phone)}
+	if a.Address == nil {
+		return append(values, nil, nil, nil, nil, nil, nil)
+	}
+	normalizeAddress(a.Address)
+	return append(values, nullString(a.Address.Block), a.Address.Street, nullString(a.Address.Unit),
+		nullString(a.Address.Building), a.Address.PostalCode, nullString(a.Address.PostalDistrict))
+}
+
+// customFieldsColumn returns the value of the custom_fields column of an
+// applicant, which is NULL when they have no custom field values. Fields set
+// to null are removed first, so setting one to null clears it.
+func customFieldsColumn(a *Applicant) (interface{}, error) {
+	for key, value := range a.CustomFields {
+		if value == nil {
+			delete(a.CustomFields, key)
+		}
+	}
+	if len(a.CustomFields) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(a.CustomFields)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling custom fields: %v", err)
+	}
+	// Stored as text, which SQLite compares with LIKE, unlike a blob
+	return string(data), nil
+}
+
+// NormalizeIdentityNumber returns an identity number in its stored form:
+// upper case without surrounding spaces
+func NormalizeIdentityNumber(s string) string {
+	return strings.ToUpper(strings.TrimSpace(s))
+}
+
+// MaskIdentityNumber hides all but the last four characters of an identity
+// number, e.g. "S1234567D" becomes "*****567D"
+func MaskIdentityNumber(s string) string {
+	if len(s) <= 4 {
+		return strings.Repeat("*", len(s))
+	}
+	return strings.Repeat("*", len(s)-4) + s[len(s)-4:]
+}
+
+// MaskName keeps only the first letter of each word of a name, e.g. "Tan Ah
+// Kow" becomes "T** A* K**"
+func MaskName(s string) string {
+	words := strings.Fields(s)
+	for i, word := range words {
+		letters := []rune(word)
+		words[i] = string(letters[0]) + strings.Repeat("*", len(letters)-1)
+	}
+	return strings.Join(words, " ")
+}
+
+// MaskDateOfBirth keeps only the year of a date, e.g. "1990-05-01" becomes
+// "1990-**-**"
+func MaskDateOfBirth(s string) string {
+	if len(s) < 4 {
+		return "****-**-**"
+	}
+	return s[:4] + "-**-**"
 }
 
-// NewApplicantRepository creates a new repository with the given database connection
-func NewApplicantRepository(db *sql.DB) *ApplicantRepository {
-	return &ApplicantRepository{DB: db}
+// identityColumns returns the encrypted identity number and its blind index,
+// both NULL when the applicant has none
+func (r *ApplicantRepository) identityColumns(a *Applicant) (encrypted, hash interface{}, err error) {
+	return r.sealIdentityNumber(&a.IdentityNumber)
+}
+
+// sealIdentityNumber normalizes an identity number and returns it encrypted
+// with its blind index, both NULL when it is empty
+func (r *ApplicantRepository) sealIdentityNumber(identityNumber *string) (encrypted, hash interface{}, err error) {
+	if *identityNumber == "" {
+		return nil, nil, nil
+	}
+	*identityNumber = NormalizeIdentityNumber(*identityNumber)
+	ciphertext, err := r.Cipher.Encrypt(*identityNumber)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error encrypting identity number: %v", err)
+	}
+	return ciphertext, r.Cipher.Hash(*identityNumber), nil
+}
+
+// checkIdentityNumber returns ErrDuplicateIdentityNumber if another applicant
+// has the given applicant's identity number. Hashes under every key are
+// checked, as the unique index only catches rows indexed with the same key.
+func (r *ApplicantRepository) checkIdentityNumber(a *Applicant, hash interface{}) error {
+	if hash == nil {
+		return nil
+	}
+	placeholders, args := inClause(r.Cipher.Hashes(a.IdentityNumber))
+	var id string
+	err := r.conn().QueryRow(`SELECT id FROM applicants WHERE identity_number_hash IN (`+placeholders+`) AND id <> ?`,
+		append(args, a.ID)...).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error checking identity number: %v", err)
+	}
+	return ErrDuplicateIdentityNumber
+}
+
+// GetByIdentityNumber retrieves an applicant by NRIC or FIN, excluding
+// soft-deleted applicants
+func (r *ApplicantRepository) GetByIdentityNumber(identityNumber string) (*Applicant, error) {
+	// Rows not yet re-indexed after a key rotation have a previous key's hash
+	placeholders, args := inClause(r.Cipher.Hashes(NormalizeIdentityNumber(identityNumber)))
+
+	var id string
+	err := r.conn().QueryRow(`SELECT id FROM applicants WHERE identity_number_hash IN (`+placeholders+`) AND deleted_at IS NULL`,
+		args...).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error querying applicant: %v", err)
+	}
+	return r.GetByID(id)
+}
+
+// ApplicantFilter holds optional search parameters for listing applicants.
+// Zero values are ignored.
+type ApplicantFilter struct {
+	Name             string // Partial, case-insensitive match on name, applied after decryption
+	EmploymentStatus string
+	MaritalStatus    string
+	Sex              string
+	PostalDistrict   string // Two-digit postal district, "01" to "28"
+	MinAge           *int
+	MaxAge           *int
+	IncludeDeleted   bool              // Include soft-deleted applicants
+	CustomFields     map[string]string // Values of custom fields by key
 }
 
 // GetAll retrieves all applicants from the database
 func (r *ApplicantRepository) GetAll() ([]Applicant, error) {
-	query := `SELECT id, name, employment_status, sex, date_of_birth, marital_status, created_at, updated_at
-			  FROM applicants
-			  ORDER BY name ASC`
+	return r.Find(ApplicantFilter{})
+}
+
+// Find retrieves applicants matching the given filter
+func (r *ApplicantRepository) Find(filter ApplicantFilter) ([]Applicant, error) {
+	now := time.Now()
+	where, args := filter.whereClause()
 
-	rows, err := r.DB.Query(query)
+	query := `SELECT ` + applicantColumns + `
+			  FROM applicants` + where
+
+	rows, err := r.conn().Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("error querying applicants: %v", err)
 	}
@@ -32,39 +262,255 @@ func (r *ApplicantRepository) GetAll() ([]Applicant, error) {
 
 	var applicants []Applicant
 	for rows.Next() {
-		var a Applicant
-		if err := rows.Scan(&a.ID, &a.Name, &a.EmploymentStatus, &a.Sex, &a.DateOfBirth,
-			&a.MaritalStatus, &a.CreatedAt, &a.UpdatedAt); err != nil {
+		a, err := r.scanApplicant(rows)
+		if err != nil {
 			return nil, fmt.Errorf("error scanning applicant row: %v", err)
 		}
+		if filter.matches(a, now) && filter.matchesCustomFields(a) {
+			applicants = append(applicants, a)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating applicant rows: %v", err)
+	}
+	sortApplicants(applicants)
+	// Release the connection before issuing further queries, which a
+	// transaction requires
+	rows.Close()
 
-		// Get household members for each applicant
-		members, err := r.GetHouseholdMembers(a.ID)
+	if err := r.attachHouseholds(applicants); err != nil {
+		return nil, err
+	}
+
+	return applicants, nil
+}
+
+// streamBatchSize is how many applicant rows Stream reads per query
+const streamBatchSize = 500
+
+// Stream calls each with the applicants matching the filter, with their
+// households, in order of ID, starting after the applicant with ID after if
+// it is not empty and stopping after limit applicants if it is positive. Rows
+// are read a batch at a time with keyset pagination, so memory use does not
+// grow with the number of applicants and no connection is held while each
+// runs. It stops at the first error each returns, which it returns.
+func (r *ApplicantRepository) Stream(filter ApplicantFilter, after string, limit int, each func(Applicant) error) error {
+	now := time.Now()
+	where, args := filter.whereClause()
+	if where == "" {
+		where = " WHERE "
+	} else {
+		where += " AND "
+	}
+
+	sent := 0
+	for {
+		query := `SELECT ` + applicantColumns + `
+				  FROM applicants` + where + `id > ?
+				  ORDER BY id
+				  LIMIT ` + strconv.Itoa(streamBatchSize)
+		batch, last, err := r.streamBatch(query, append(args, after), filter, now)
 		if err != nil {
-			return nil, fmt.Errorf("error getting household members: %v", err)
+			return err
+		}
+		if err := r.attachHouseholds(batch); err != nil {
+			return err
+		}
+
+		for _, a := range batch {
+			if err := each(a); err != nil {
+				return err
+			}
+			sent++
+			if limit > 0 && sent >= limit {
+				return nil
+			}
 		}
-		a.Household = members
+		if last == "" {
+			return nil
+		}
+		after = last
+	}
+}
 
+// streamBatch reads a batch of Stream, returning the applicants matching the
+// filter and the ID of the last row read, or "" if the batch was the last
+func (r *ApplicantRepository) streamBatch(query string, args []interface{}, filter ApplicantFilter, now time.Time) ([]Applicant, string, error) {
+	rows, err := r.conn().Query(query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("error querying applicants: %v", err)
+	}
+	defer rows.Close()
+
+	var applicants []Applicant
+	var last string
+	read := 0
+	for rows.Next() {
+		a, err := r.scanApplicant(rows)
+		if err != nil {
+			return nil, "", fmt.Errorf("error scanning applicant row: %v", err)
+		}
+		read++
+		last = a.ID
+		if filter.matches(a, now) && filter.matchesCustomFields(a) {
+			applicants = append(applicants, a)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("error iterating applicant rows: %v", err)
+	}
+	if read < streamBatchSize {
+		last = ""
+	}
+	return applicants, last, nil
+}
+
+// GetByIDs retrieves the applicants with the given IDs, keyed by ID, using a
+// fixed number of queries regardless of how many IDs are requested. Since it
+// is used to resolve references, soft-deleted applicants are included.
+func (r *ApplicantRepository) GetByIDs(ids []string) (map[string]*Applicant, error) {
+	result := make(map[string]*Applicant)
+	ids = uniqueIDs(ids)
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	placeholders, args := inClause(ids)
+	query := `SELECT ` + applicantColumns + `
+			  FROM applicants
+			  WHERE id IN (` + placeholders + `)`
+
+	rows, err := r.conn().Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying applicants: %v", err)
+	}
+	defer rows.Close()
+
+	var applicants []Applicant
+	for rows.Next() {
+		a, err := r.scanApplicant(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning applicant row: %v", err)
+		}
 		applicants = append(applicants, a)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("error iterating applicant rows: %v", err)
 	}
+	rows.Close()
 
-	return applicants, nil
+	if err := r.attachHouseholds(applicants); err != nil {
+		return nil, err
+	}
+
+	for i := range applicants {
+		result[applicants[i].ID] = &applicants[i]
+	}
+	return result, nil
+}
+
+// attachHouseholds loads the household members of all the given applicants in
+// a single query
+func (r *ApplicantRepository) attachHouseholds(applicants []Applicant) error {
+	ids := make([]string, len(applicants))
+	for i := range applicants {
+		ids[i] = applicants[i].ID
+	}
+
+	households, err := r.getHouseholdMembersByApplicant(ids)
+	if err != nil {
+		return fmt.Errorf("error getting household members: %v", err)
+	}
+
+	for i := range applicants {
+		applicants[i].Household = households[applicants[i].ID]
+	}
+	return nil
 }
 
-// GetByID retrieves an applicant by ID
+// whereClause builds a parameterized WHERE clause for the filter's conditions
+// on unencrypted columns. The name and age conditions are applied by matches,
+// and the custom field conditions by matchesCustomFields.
+func (f ApplicantFilter) whereClause() (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if !f.IncludeDeleted {
+		conditions = append(conditions, "deleted_at IS NULL")
+	}
+	if f.EmploymentStatus != "" {
+		conditions = append(conditions, "employment_status = ?")
+		args = append(args, f.EmploymentStatus)
+	}
+	if f.MaritalStatus != "" {
+		conditions = append(conditions, "marital_status = ?")
+		args = append(args, f.MaritalStatus)
+	}
+	if f.Sex != "" {
+		conditions = append(conditions, "sex = ?")
+		args = append(args, f.Sex)
+	}
+	if f.PostalDistrict != "" {
+		conditions = append(conditions, "postal_district = ?")
+		args = append(args, f.PostalDistrict)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// matchesCustomFields reports whether an applicant has the custom field
+// values of the filter, which are kept as JSON that whereClause cannot query
+// portably
+func (f ApplicantFilter) matchesCustomFields(a Applicant) bool {
+	for key, want := range f.CustomFields {
+		if !customValueIs(a.CustomFields[key], want) {
+			return false
+		}
+	}
+	return true
+}
+
+// customValueIs reports whether a custom field value, which is missing if
+// nil, is the value given as a string in a filter. Strings are compared
+// case-insensitively and numbers by value.
+func customValueIs(value interface{}, want string) bool {
+	switch v := value.(type) {
+	case string:
+		return strings.EqualFold(v, want)
+	case bool:
+		b, err := strconv.ParseBool(want)
+		return err == nil && v == b
+	case float64:
+		f, err := strconv.ParseFloat(want, 64)
+		return err == nil && v == f
+	}
+	return false
+}
+
+// GetByID retrieves an applicant by ID, excluding soft-deleted applicants
 func (r *ApplicantRepository) GetByID(id string) (*Applicant, error) {
-	query := `SELECT id, name, employment_status, sex, date_of_birth, marital_status, created_at, updated_at
+	return r.getByID(id, false)
+}
+
+// GetByIDIncludingDeleted retrieves an applicant by ID, even if soft-deleted
+func (r *ApplicantRepository) GetByIDIncludingDeleted(id string) (*Applicant, error) {
+	return r.getByID(id, true)
+}
+
+func (r *ApplicantRepository) getByID(id string, includeDeleted bool) (*Applicant, error) {
+	query := `SELECT ` + applicantColumns + `
 			  FROM applicants
 			  WHERE id = ?`
+	if !includeDeleted {
+		query += " AND deleted_at IS NULL"
+	}
 
-	var a Applicant
-	err := r.DB.QueryRow(query, id).Scan(&a.ID, &a.Name, &a.EmploymentStatus, &a.Sex,
-		&a.DateOfBirth, &a.MaritalStatus, &a.CreatedAt, &a.UpdatedAt)
-
+	a, err := r.scanApplicant(r.conn().QueryRow(query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // No applicant found
@@ -92,65 +538,188 @@ func (r *ApplicantRepository) Create(a *Applicant) error {
 	now := time.Now()
 	a.CreatedAt = now
 	a.UpdatedAt = now
+	a.Version = 1
 
-	query := `INSERT INTO applicants (id, name, employment_status, sex, date_of_birth, marital_status, created_at, updated_at)
-			  VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
-
-	_, err := r.DB.Exec(query, a.ID, a.Name, a.EmploymentStatus, a.Sex,
-		a.DateOfBirth, a.MaritalStatus, a.CreatedAt, a.UpdatedAt)
-
+	name, dateOfBirth, err := r.sealPerson(a.Name, a.DateOfBirth)
+	if err != nil {
+		return err
+	}
+	identityNumber, identityHash, err := r.identityColumns(a)
+	if err != nil {
+		return err
+	}
+	customFields, err := customFieldsColumn(a)
 	if err != nil {
-		return fmt.Errorf("error creating applicant: %v", err)
+		return err
 	}
 
-	// Create household members
-	for i := range a.Household {
-		a.Household[i].ApplicantID = a.ID
-		if err := r.CreateHouseholdMember(&a.Household[i]); err != nil {
-			return fmt.Errorf("error creating household member: %v", err)
+	query := `INSERT INTO applicants (id, name, identity_number_encrypted, identity_number_hash, employment_status, sex, date_of_birth, marital_status, monthly_income, email, email_opt_out,
+			  phone, address_block, address_street, address_unit, address_building, postal_code, postal_district, version, created_at, updated_at, custom_fields)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	args := []interface{}{a.ID, name, identityNumber, identityHash, a.EmploymentStatus, a.Sex,
+		dateOfBirth, a.MaritalStatus, a.MonthlyIncome, nullString(a.Email), a.EmailOptOut}
+	args = append(append(args, contactColumns(a)...), a.Version, a.CreatedAt, a.UpdatedAt, customFields)
+
+	// Insert the applicant and household members atomically
+	return runInTx(r.DB, r.tx, func(tx *sql.Tx) error {
+		txRepo := r.WithTx(tx)
+		if err := txRepo.checkIdentityNumber(a, identityHash); err != nil {
+			return err
 		}
-	}
 
-	return nil
+		_, err := tx.Exec(query, args...)
+
+		if isUniqueViolation(err) {
+			return ErrDuplicateIdentityNumber
+		}
+		if err != nil {
+			return fmt.Errorf("error creating applicant: %v", err)
+		}
+		if err := txRepo.indexTerms(SearchTypeApplicant, a.ID, a.Name); err != nil {
+			return err
+		}
+
+		// Create household members
+		for i := range a.Household {
+			a.Household[i].ApplicantID = a.ID
+			if err := txRepo.CreateHouseholdMember(&a.Household[i]); err != nil {
+				return fmt.Errorf("error creating household member: %v", err)
+			}
+		}
+
+		return nil
+	})
 }
 
-// Update updates an existing applicant
+// Update updates an existing applicant if its stored version matches
+// a.Version, returning ErrVersionConflict otherwise. On success a.Version is
+// incremented.
 func (r *ApplicantRepository) Update(a *Applicant) error {
 	a.UpdatedAt = time.Now()
 
-	query := `UPDATE applicants
-			  SET name = ?, employment_status = ?, sex = ?,
-				  date_of_birth = ?, marital_status = ?, updated_at = ?
-			  WHERE id = ?`
-
-	_, err := r.DB.Exec(query, a.Name, a.EmploymentStatus, a.Sex,
-		a.DateOfBirth, a.MaritalStatus, a.UpdatedAt, a.ID)
+	name, dateOfBirth, err := r.sealPerson(a.Name, a.DateOfBirth)
+	if err != nil {
+		return err
+	}
+	identityNumber, identityHash, err := r.identityColumns(a)
+	if err != nil {
+		return err
+	}
+	if err := r.checkIdentityNumber(a, identityHash); err != nil {
+		return err
+	}
+	customFields, err := customFieldsColumn(a)
+	if err != nil {
+		return err
+	}
 
+	query := `UPDATE applicants
+			  SET name = ?, identity_number_encrypted = ?, identity_number_hash = ?,
+				  employment_status = ?, sex = ?,
+				  date_of_birth = ?, marital_status = ?, monthly_income = ?,
+				  email = ?, email_opt_out = ?,
+				  phone = ?, address_block = ?, address_street = ?, address_unit = ?,
+				  address_building = ?, postal_code = ?, postal_district = ?,
+				  custom_fields = ?, version = version + 1, updated_at = ?
+			  WHERE id = ? AND version = ?`
+	args := []interface{}{name, identityNumber, identityHash, a.EmploymentStatus, a.Sex,
+		dateOfBirth, a.MaritalStatus, a.MonthlyIncome, nullString(a.Email), a.EmailOptOut}
+	args = append(append(args, contactColumns(a)...), customFields, a.UpdatedAt, a.ID, a.Version)
+
+	err = runInTx(r.DB, r.tx, func(tx *sql.Tx) error {
+		result, err := tx.Exec(query, args...)
+
+		if isUniqueViolation(err) {
+			return ErrDuplicateIdentityNumber
+		}
+		if err != nil {
+			return fmt.Errorf("error updating applicant: %v", err)
+		}
+		if err := checkVersioned(result); err != nil {
+			return err
+		}
+		return r.WithTx(tx).indexTerms(SearchTypeApplicant, a.ID, a.Name)
+	})
 	if err != nil {
-		return fmt.Errorf("error updating applicant: %v", err)
+		return err
 	}
 
+	a.Version++
 	return nil
 }
 
-// Delete removes an applicant
+// Delete soft-deletes an applicant, keeping their case history
 func (r *ApplicantRepository) Delete(id string) error {
-	query := `DELETE FROM applicants WHERE id = ?`
-	_, err := r.DB.Exec(query, id)
+	query := `UPDATE applicants SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`
+	_, err := r.conn().Exec(query, time.Now(), id)
 	if err != nil {
 		return fmt.Errorf("error deleting applicant: %v", err)
 	}
 	return nil
 }
 
+// Restore reverses a soft delete
+func (r *ApplicantRepository) Restore(id string) error {
+	query := `UPDATE applicants SET deleted_at = NULL WHERE id = ?`
+	_, err := r.conn().Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("error restoring applicant: %v", err)
+	}
+	return nil
+}
+
+// HardDelete permanently removes an applicant and their household members.
+// It fails while applications, including deleted ones, refer to the applicant.
+func (r *ApplicantRepository) HardDelete(id string) error {
+	return runInTx(r.DB, r.tx, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM search_terms
+			  WHERE (entity_type = ? AND entity_id = ?)
+			  OR (entity_type = ? AND entity_id IN (SELECT id FROM household_members WHERE applicant_id = ?))`,
+			SearchTypeApplicant, id, SearchTypeHouseholdMember, id); err != nil {
+			return fmt.Errorf("error removing search terms: %v", err)
+		}
+
+		query := `DELETE FROM applicants WHERE id = ?`
+		if _, err := tx.Exec(query, id); err != nil {
+			return fmt.Errorf("error deleting applicant: %v", err)
+		}
+		return nil
+	})
+}
+
+// householdMemberColumns is the column list read by scanHouseholdMember
+const householdMemberColumns = `id, applicant_id, name, identity_number_encrypted, employment_status, sex, date_of_birth, relation, monthly_income, school_level, created_at, updated_at`
+
+// scanHouseholdMember scans a row selected with householdMemberColumns,
+// decrypting the name and date of birth
+func (r *ApplicantRepository) scanHouseholdMember(row rowScanner) (HouseholdMember, error) {
+	var m HouseholdMember
+	var name, dateOfBirth string
+	var identityNumber, schoolLevel sql.NullString
+	err := row.Scan(&m.ID, &m.ApplicantID, &name, &identityNumber, &m.EmploymentStatus, &m.Sex,
+		&dateOfBirth, &m.Relation, &m.MonthlyIncome, &schoolLevel, &m.CreatedAt, &m.UpdatedAt)
+	if err != nil {
+		return m, err
+	}
+	m.SchoolLevel = schoolLevel.String
+	if m.Name, m.DateOfBirth, err = r.openPerson(name, dateOfBirth); err != nil {
+		return m, fmt.Errorf("household member %s: %v", m.ID, err)
+	}
+	if identityNumber.Valid {
+		if m.IdentityNumber, err = r.Cipher.Decrypt(identityNumber.String); err != nil {
+			return m, fmt.Errorf("error decrypting identity number of household member %s: %v", m.ID, err)
+		}
+	}
+	return m, nil
+}
+
 // GetHouseholdMembers retrieves all household members for an applicant
 func (r *ApplicantRepository) GetHouseholdMembers(applicantID string) ([]HouseholdMember, error) {
-	query := `SELECT id, applicant_id, name, employment_status, sex, date_of_birth, relation, created_at, updated_at
+	query := `SELECT ` + householdMemberColumns + `
 			  FROM household_members
-			  WHERE applicant_id = ?
-			  ORDER BY name ASC`
+			  WHERE applicant_id = ?`
 
-	rows, err := r.DB.Query(query, applicantID)
+	rows, err := r.conn().Query(query, applicantID)
 	if err != nil {
 		return nil, fmt.Errorf("error querying household members: %v", err)
 	}
@@ -158,9 +727,8 @@ func (r *ApplicantRepository) GetHouseholdMembers(applicantID string) ([]Househo
 
 	var members []HouseholdMember
 	for rows.Next() {
-		var m HouseholdMember
-		if err := rows.Scan(&m.ID, &m.ApplicantID, &m.Name, &m.EmploymentStatus, &m.Sex,
-			&m.DateOfBirth, &m.Relation, &m.CreatedAt, &m.UpdatedAt); err != nil {
+		m, err := r.scanHouseholdMember(rows)
+		if err != nil {
 			return nil, fmt.Errorf("error scanning household member row: %v", err)
 		}
 		members = append(members, m)
@@ -170,9 +738,48 @@ func (r *ApplicantRepository) GetHouseholdMembers(applicantID string) ([]Househo
 		return nil, fmt.Errorf("error iterating household member rows: %v", err)
 	}
 
+	sortHouseholdMembers(members)
 	return members, nil
 }
 
+// getHouseholdMembersByApplicant retrieves the household members of several
+// applicants at once, keyed by applicant ID
+func (r *ApplicantRepository) getHouseholdMembersByApplicant(applicantIDs []string) (map[string][]HouseholdMember, error) {
+	result := make(map[string][]HouseholdMember)
+	applicantIDs = uniqueIDs(applicantIDs)
+	if len(applicantIDs) == 0 {
+		return result, nil
+	}
+
+	placeholders, args := inClause(applicantIDs)
+	query := `SELECT ` + householdMemberColumns + `
+			  FROM household_members
+			  WHERE applicant_id IN (` + placeholders + `)`
+
+	rows, err := r.conn().Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying household members: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		m, err := r.scanHouseholdMember(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning household member row: %v", err)
+		}
+		result[m.ApplicantID] = append(result[m.ApplicantID], m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating household member rows: %v", err)
+	}
+
+	for _, members := range result {
+		sortHouseholdMembers(members)
+	}
+	return result, nil
+}
+
 // CreateHouseholdMember inserts a new household member
 func (r *ApplicantRepository) CreateHouseholdMember(m *HouseholdMember) error {
 	// Generate UUID if not provided
@@ -184,25 +791,37 @@ func (r *ApplicantRepository) CreateHouseholdMember(m *HouseholdMember) error {
 	m.CreatedAt = now
 	m.UpdatedAt = now
 
-	query := `INSERT INTO household_members (id, applicant_id, name, employment_status, sex, date_of_birth, relation, created_at, updated_at)
-			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
-
-	_, err := r.DB.Exec(query, m.ID, m.ApplicantID, m.Name, m.EmploymentStatus, m.Sex,
-		m.DateOfBirth, m.Relation, m.CreatedAt, m.UpdatedAt)
-
+	name, dateOfBirth, err := r.sealPerson(m.Name, m.DateOfBirth)
+	if err != nil {
+		return err
+	}
+	identityNumber, identityHash, err := r.sealIdentityNumber(&m.IdentityNumber)
 	if err != nil {
-		return fmt.Errorf("error creating household member: %v", err)
+		return err
 	}
 
-	return nil
+	query := `INSERT INTO household_members (id, applicant_id, name, identity_number_encrypted, identity_number_hash, employment_status, sex, date_of_birth, relation, monthly_income, school_level, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	return runInTx(r.DB, r.tx, func(tx *sql.Tx) error {
+		_, err := tx.Exec(query, m.ID, m.ApplicantID, name, identityNumber, identityHash, m.EmploymentStatus, m.Sex,
+			dateOfBirth, m.Relation, m.MonthlyIncome, nullString(m.SchoolLevel), m.CreatedAt, m.UpdatedAt)
+
+		if err != nil {
+			return fmt.Errorf("error creating household member: %v", err)
+		}
+
+		return r.WithTx(tx).indexTerms(SearchTypeHouseholdMember, m.ID, m.Name)
+	})
 }
 
 // DeleteHouseholdMember removes a household member
 func (r *ApplicantRepository) DeleteHouseholdMember(id string) error {
-	query := `DELETE FROM household_members WHERE id = ?`
-	_, err := r.DB.Exec(query, id)
-	if err != nil {
-		return fmt.Errorf("error deleting household member: %v", err)
-	}
-	return nil
+	return runInTx(r.DB, r.tx, func(tx *sql.Tx) error {
+		query := `DELETE FROM household_members WHERE id = ?`
+		if _, err := tx.Exec(query, id); err != nil {
+			return fmt.Errorf("error deleting household member: %v", err)
+		}
+		return r.WithTx(tx).removeTerms(SearchTypeHouseholdMember, id)
+	})
 }
@@ -0,0 +1,175 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrChecklistIncomplete is returned when approving an application whose
+// mandatory review checklist items have not all been completed
+var ErrChecklistIncomplete = errors.New("mandatory checklist items are not complete")
+
+// applicationChecklistItemColumns is the column list read by
+// scanApplicationChecklistItem
+const applicationChecklistItemColumns = `id, application_id, checklist_item_id, name, description, kind, mandatory, position,
+	completed_at, completed_by, note, created_at, updated_at`
+
+// scanApplicationChecklistItem scans a row selected with
+// applicationChecklistItemColumns
+func scanApplicationChecklistItem(row rowScanner) (ApplicationChecklistItem, error) {
+	var item ApplicationChecklistItem
+	var checklistItemID, description, completedBy, note sql.NullString
+	var completedAt sql.NullTime
+
+	if err := row.Scan(&item.ID, &item.ApplicationID, &checklistItemID, &item.Name, &description, &item.Kind,
+		&item.Mandatory, &item.Position, &completedAt, &completedBy, &note, &item.CreatedAt, &item.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return item, err
+		}
+		return item, fmt.Errorf("error scanning application checklist item row: %v", err)
+	}
+	item.ChecklistItemID = checklistItemID.String
+	item.Description = description.String
+	item.CompletedBy = completedBy.String
+	item.Note = note.String
+	if completedAt.Valid {
+		item.CompletedAt = &completedAt.Time
+	}
+
+	return item, nil
+}
+
+// GetChecklist retrieves the review checklist of an application, ordered by
+// position, then name
+func (r *ApplicationRepository) GetChecklist(applicationID string) ([]ApplicationChecklistItem, error) {
+	query := `SELECT ` + applicationChecklistItemColumns + `
+			  FROM application_checklist_items
+			  WHERE application_id = ?
+			  ORDER BY position ASC, name ASC`
+
+	rows, err := r.conn().Query(query, applicationID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying application checklist items: %v", err)
+	}
+	defer rows.Close()
+
+	var items []ApplicationChecklistItem
+	for rows.Next() {
+		item, err := scanApplicationChecklistItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating application checklist item rows: %v", err)
+	}
+
+	return items, nil
+}
+
+// GetChecklistItem retrieves a single item of an application's review
+// checklist
+func (r *ApplicationRepository) GetChecklistItem(applicationID, id string) (*ApplicationChecklistItem, error) {
+	query := `SELECT ` + applicationChecklistItemColumns + `
+			  FROM application_checklist_items
+			  WHERE id = ? AND application_id = ?`
+
+	item, err := scanApplicationChecklistItem(r.conn().QueryRow(query, id, applicationID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Not found
+		}
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+// TickChecklistItem marks an item of an application's checklist completed by
+// the user completedBy, with an optional note, or not completed, clearing
+// both. Only the checklists of pending applications can be changed;
+// ErrAlreadyDecided is returned otherwise. On success item is updated to
+// match.
+func (r *ApplicationRepository) TickChecklistItem(item *ApplicationChecklistItem, completed bool, completedBy, note string) error {
+	now := time.Now()
+	var completedAt *time.Time
+	if completed {
+		completedAt = &now
+	} else {
+		completedBy, note = "", ""
+	}
+
+	query := `UPDATE application_checklist_items
+			  SET completed_at = ?, completed_by = ?, note = ?, updated_at = ?
+			  WHERE id = ? AND EXISTS (
+			      SELECT 1 FROM applications
+			      WHERE applications.id = application_checklist_items.application_id
+			        AND status = 'pending' AND deleted_at IS NULL)`
+
+	result, err := r.conn().Exec(query, completedAt, nullString(completedBy), nullString(note), now, item.ID)
+	if err != nil {
+		return fmt.Errorf("error updating application checklist item: %v", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %v", err)
+	}
+	if rows == 0 {
+		return ErrAlreadyDecided
+	}
+
+	item.CompletedAt = completedAt
+	item.CompletedBy = completedBy
+	item.Note = note
+	item.UpdatedAt = now
+	return nil
+}
+
+// copyChecklist gives a new application a copy of its scheme's review
+// checklist, none of it completed
+func (r *ApplicationRepository) copyChecklist(a *Application) error {
+	items, err := r.SchemeRepo.GetChecklist(a.SchemeID)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO application_checklist_items (id, application_id, checklist_item_id, name, description, kind,
+			      mandatory, position, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	for _, item := range items {
+		_, err := r.conn().Exec(query, uuid.New().String(), a.ID, item.ID, item.Name, item.Description, item.Kind,
+			item.Mandatory, item.Position, a.CreatedAt, a.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("error creating application checklist item: %v", err)
+		}
+	}
+	return nil
+}
+
+// checkChecklist returns ErrChecklistIncomplete, naming the items, if any
+// mandatory item of an application's checklist has not been completed
+func (r *ApplicationRepository) checkChecklist(applicationID string) error {
+	items, err := r.GetChecklist(applicationID)
+	if err != nil {
+		return err
+	}
+
+	var incomplete []string
+	for _, item := range items {
+		if item.Mandatory && item.CompletedAt == nil {
+			incomplete = append(incomplete, item.Name)
+		}
+	}
+	if len(incomplete) > 0 {
+		return fmt.Errorf("%w: %s", ErrChecklistIncomplete, strings.Join(incomplete, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrDuplicateApplication is returned when creating an application for a
+// scheme the applicant already has a pending or approved application for
+var ErrDuplicateApplication = errors.New("applicant already has an active application for this scheme")
+
+// ErrCoolingOff is returned when creating an application for a scheme that
+// rejected the applicant within its cooling-off period
+var ErrCoolingOff = errors.New("applicant was rejected for this scheme within its cooling-off period")
+
+// Checks run before an application is created, in order
+const (
+	CheckApplicant   = "applicant"   // The applicant exists
+	CheckScheme      = "scheme"      // The scheme exists
+	CheckSchemeOpen  = "scheme_open" // The scheme is open for applications
+	CheckEligibility = "eligibility" // The applicant meets the scheme's current criteria
+	CheckDuplicate   = "duplicate"   // The applicant has no active application for the scheme
+	CheckCoolingOff  = "cooling_off" // The scheme's cooling-off period since the applicant's last rejection has passed
+)
+
+// Outcomes of a submission check
+const (
+	CheckPassed  = "passed"
+	CheckFailed  = "failed"
+	CheckSkipped = "skipped" // Not run, because a check it depends on failed
+)
+
+// SubmissionCheck is the outcome of one of the checks run before an
+// application is created
+type SubmissionCheck struct {
+	Name    string `json:"name" example:"eligibility" enums:"applicant,scheme,scheme_open,eligibility,duplicate,cooling_off"`
+	Status  string `json:"status" example:"passed" enums:"passed,failed,skipped"`
+	Message string `json:"message,omitempty"` // Why the check failed or was skipped
+}
+
+// SubmissionAssessment is the outcome of every check run before an
+// application is created
+type SubmissionAssessment struct {
+	Valid         bool              `json:"valid"` // Whether every check passed
+	Checks        []SubmissionCheck `json:"checks"`
+	SchemeVersion int               `json:"scheme_version,omitempty"` // Version of the scheme's terms the application would be assessed under
+
+	PreviousApplicationID string `json:"previous_application_id,omitempty"` // The applicant's latest rejected application for the scheme, which the application would resubmit
+
+	err error // The error Create returns for the first failed check
+}
+
+// fail records a failed check, keeping err for the first failure
+func (s *SubmissionAssessment) fail(name, message string, err error) {
+	s.Checks = append(s.Checks, SubmissionCheck{Name: name, Status: CheckFailed, Message: message})
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+// pass records a passed check
+func (s *SubmissionAssessment) pass(name string) {
+	s.Checks = append(s.Checks, SubmissionCheck{Name: name, Status: CheckPassed})
+}
+
+// skip records a check that could not be run
+func (s *SubmissionAssessment) skip(name, message string) {
+	s.Checks = append(s.Checks, SubmissionCheck{Name: name, Status: CheckSkipped, Message: message})
+}
+
+// Assess runs the checks Create makes before inserting an application by the
+// given applicant for the given scheme at now, without creating it. Every
+// check is reported; those depending on a missing applicant or scheme are
+// skipped.
+func (r *ApplicationRepository) Assess(applicantID, schemeID string, now time.Time) (*SubmissionAssessment, error) {
+	result := &SubmissionAssessment{}
+
+	applicant, err := r.ApplicantRepo.GetByID(applicantID)
+	if err != nil {
+		return nil, fmt.Errorf("error validating applicant: %v", err)
+	}
+	if applicant == nil {
+		result.fail(CheckApplicant, "applicant not found", fmt.Errorf("applicant not found: %s", applicantID))
+	} else {
+		result.pass(CheckApplicant)
+	}
+
+	scheme, err := r.SchemeRepo.GetByID(schemeID)
+	if err != nil {
+		return nil, fmt.Errorf("error validating scheme: %v", err)
+	}
+	if scheme == nil {
+		result.fail(CheckScheme, "scheme not found", fmt.Errorf("scheme not found: %s", schemeID))
+		result.skip(CheckSchemeOpen, "scheme not found")
+	} else {
+		result.pass(CheckScheme)
+		switch {
+		case scheme.OpenAt(now):
+			result.pass(CheckSchemeOpen)
+		case scheme.Status != SchemePublished:
+			result.fail(CheckSchemeOpen, "scheme is "+scheme.Status+", not published", ErrSchemeClosed)
+		default:
+			result.fail(CheckSchemeOpen, "scheme is inactive or outside its application window", ErrSchemeClosed)
+		}
+	}
+
+	if applicant == nil || scheme == nil {
+		result.skip(CheckEligibility, "applicant or scheme not found")
+		result.skip(CheckDuplicate, "applicant or scheme not found")
+		result.skip(CheckCoolingOff, "applicant or scheme not found")
+		return result, nil
+	}
+
+	// Eligibility is assessed under the terms currently in effect, against
+	// the household the applicant belongs to now
+	if r.HouseholdRepo != nil {
+		if applicant, err = r.HouseholdRepo.Resolve(applicant, now); err != nil {
+			return nil, fmt.Errorf("error resolving household: %v", err)
+		}
+	}
+	version, err := r.SchemeRepo.GetVersionAt(schemeID, now)
+	if err != nil {
+		return nil, fmt.Errorf("error getting scheme version: %v", err)
+	}
+	switch {
+	case version == nil:
+		result.fail(CheckEligibility, "scheme has no terms in effect", ErrNotEligible)
+	case !isEligible(applicant, version.Criteria, now):
+		result.SchemeVersion = version.Version
+		result.fail(CheckEligibility, "applicant does not meet the scheme's criteria", ErrNotEligible)
+	default:
+		result.SchemeVersion = version.Version
+		result.pass(CheckEligibility)
+	}
+
+	existing, err := r.activeApplicationID(applicantID, schemeID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != "" {
+		result.fail(CheckDuplicate, "applicant already has an active application for this scheme: "+existing, ErrDuplicateApplication)
+	} else {
+		result.pass(CheckDuplicate)
+	}
+
+	// Applying again after a rejection resubmits the rejected application,
+	// once any cooling-off period counted from its decision has passed
+	previous, err := r.latestRejection(applicantID, schemeID)
+	if err != nil {
+		return nil, err
+	}
+	if previous != nil {
+		result.PreviousApplicationID = previous.ID
+	}
+	if until := coolingOffEnd(scheme, previous); until != nil && now.Before(*until) {
+		result.fail(CheckCoolingOff, "applicant was rejected on "+previous.DecisionDate.Format(time.DateOnly)+
+			" and can reapply from "+until.Format(time.DateOnly),
+			fmt.Errorf("%w; they can reapply from %s", ErrCoolingOff, until.Format(time.DateOnly)))
+	} else {
+		result.pass(CheckCoolingOff)
+	}
+
+	result.Valid = result.err == nil
+	return result, nil
+}
+
+// activeApplicationID returns the ID of an applicant's pending or approved
+// application for a scheme, or "" if there is none
+func (r *ApplicationRepository) activeApplicationID(applicantID, schemeID string) (string, error) {
+	placeholders, args := inClause(ActiveApplicationStatuses)
+	query := `SELECT id FROM applications
+			  WHERE applicant_id = ? AND scheme_id = ? AND deleted_at IS NULL AND status IN (` + placeholders + `)
+			  LIMIT 1`
+
+	var id string
+	err := r.conn().QueryRow(query, append([]interface{}{applicantID, schemeID}, args...)...).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("error checking for duplicate applications: %v", err)
+	}
+	return id, nil
+}
+
+// latestRejection returns the applicant's most recently decided rejected
+// application for a scheme, or nil if there is none
+func (r *ApplicationRepository) latestRejection(applicantID, schemeID string) (*Application, error) {
+	query := `SELECT ` + applicationColumns + ` FROM applications
+			  WHERE applicant_id = ? AND scheme_id = ? AND status = 'rejected' AND deleted_at IS NULL
+			  ORDER BY decision_date DESC
+			  LIMIT 1`
+
+	a, err := scanApplication(r.conn().QueryRow(query, applicantID, schemeID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error checking for rejected applications: %v", err)
+	}
+	return &a, nil
+}
+
+// coolingOffEnd returns when the scheme's cooling-off period after the
+// rejection of previous ends, or nil if there is none to wait for
+func coolingOffEnd(scheme *Scheme, previous *Application) *time.Time {
+	if previous == nil || previous.DecisionDate == nil || scheme.CoolingOffDays == nil || *scheme.CoolingOffDays <= 0 {
+		return nil
+	}
+	until := previous.DecisionDate.AddDate(0, 0, *scheme.CoolingOffDays)
+	return &until
+}
+
+// PreviousApplications returns the applications a resubmits, following
+// previous_application_id back, newest first. Deleted applications are
+// included, so the chain is not broken by a soft delete; their applicants
+// and schemes are not loaded.
+func (r *ApplicationRepository) PreviousApplications(a *Application) ([]Application, error) {
+	var chain []Application
+	seen := map[string]bool{a.ID: true}
+	for id := a.PreviousApplicationID; id != "" && !seen[id]; {
+		seen[id] = true
+		query := `SELECT ` + applicationColumns + ` FROM applications WHERE id = ?`
+		previous, err := scanApplication(r.conn().QueryRow(query, id))
+		if errors.Is(err, sql.ErrNoRows) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error querying previous application: %v", err)
+		}
+		chain = append(chain, previous)
+		id = previous.PreviousApplicationID
+	}
+	return chain, nil
+}
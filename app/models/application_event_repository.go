@@ -0,0 +1,421 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ApplicationEventBackfill is the type of the events that record the state of
+// applications made before events were recorded
+const ApplicationEventBackfill = "backfill"
+
+// applicationSnapshotEvents are the types of events whose data is the whole
+// application rather than the fields they set
+var applicationSnapshotEvents = map[string]bool{
+	AuditActionCreate:        true,
+	AuditActionAnonymize:     true,
+	ApplicationEventBackfill: true,
+}
+
+// ApplicationEventMismatch is an application whose state rebuilt from its
+// events differs from the stored one
+type ApplicationEventMismatch struct {
+	ApplicationID string
+	Fields        []string // The fields that differ, sorted
+}
+
+// ApplicationEventRepository handles database operations for the events of
+// applications
+type ApplicationEventRepository struct {
+	DB *sql.DB
+	tx *sql.Tx
+}
+
+// NewApplicationEventRepository creates a new repository with the given database connection
+func NewApplicationEventRepository(db *sql.DB) *ApplicationEventRepository {
+	return &ApplicationEventRepository{DB: db}
+}
+
+// WithTx returns a copy of the repository that runs its queries in tx
+func (r *ApplicationEventRepository) WithTx(tx *sql.Tx) *ApplicationEventRepository {
+	return &ApplicationEventRepository{DB: r.DB, tx: tx}
+}
+
+// conn returns the transaction the repository is bound to, or the database
+func (r *ApplicationEventRepository) conn() DBTX {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.DB
+}
+
+// applicationEventData returns the data of the event recording an audited
+// transition of an application: the whole application for snapshot events,
+// nothing for purges, and otherwise the new values of the changed fields
+func applicationEventData(l *AuditLog) (json.RawMessage, error) {
+	switch {
+	case applicationSnapshotEvents[l.Action]:
+		return l.After, nil
+	case l.Action == AuditActionPurge:
+		return nil, nil
+	case l.Action == AuditActionDelete:
+		// Deletes are soft, but audited without the deleted application
+		return json.Marshal(map[string]interface{}{"deleted_at": l.CreatedAt})
+	}
+
+	fields := make(map[string]interface{}, len(l.Changes))
+	for key, change := range l.Changes {
+		fields[key] = change.New
+	}
+	return json.Marshal(fields)
+}
+
+// createApplicationEvent adds the transition of an application recorded by an
+// audit entry to its events, on the connection or transaction the entry was
+// stored with
+func createApplicationEvent(conn DBTX, l *AuditLog) error {
+	data, err := applicationEventData(l)
+	if err != nil {
+		return fmt.Errorf("error marshaling application event: %v", err)
+	}
+
+	var version int
+	if err := conn.QueryRow(`SELECT COALESCE(MAX(version), 0) + 1 FROM application_events WHERE application_id = ?`,
+		l.EntityID).Scan(&version); err != nil {
+		return fmt.Errorf("error numbering application event: %v", err)
+	}
+
+	query := `INSERT INTO application_events (id, application_id, version, type, actor_id, actor_username, data, occurred_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err = conn.Exec(query, l.ID, l.EntityID, version, l.Action,
+		nullString(l.ActorID), nullString(l.ActorUsername), nullJSON(data), l.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating application event: %v", err)
+	}
+	return nil
+}
+
+// recordApplicationSnapshots adds an event of the given type holding the
+// whole application, without an actor, for each of an applicant's
+// applications, including deleted ones
+func recordApplicationSnapshots(tx *sql.Tx, applicantID, eventType string) error {
+	rows, err := tx.Query(`SELECT `+applicationColumns+` FROM applications WHERE applicant_id = ?`, applicantID)
+	if err != nil {
+		return fmt.Errorf("error querying applications: %v", err)
+	}
+	var applications []Application
+	for rows.Next() {
+		a, err := scanApplication(rows)
+		if err != nil {
+			rows.Close()
+			return fmt.Errorf("error scanning application row: %v", err)
+		}
+		applications = append(applications, a)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating application rows: %v", err)
+	}
+
+	for i := range applications {
+		if err := recordApplicationSnapshot(tx, &applications[i], eventType); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordApplicationSnapshot adds an event of the given type holding the whole
+// application, without an actor
+func recordApplicationSnapshot(conn DBTX, a *Application, eventType string) error {
+	l, err := NewAuditLog(AuditEntityApplication, a.ID, eventType, Actor{}, nil, a)
+	if err != nil {
+		return err
+	}
+	l.ID = uuid.New().String()
+	l.CreatedAt = time.Now()
+	return createApplicationEvent(conn, l)
+}
+
+// ForApplication retrieves the events of an application, oldest first
+func (r *ApplicationEventRepository) ForApplication(applicationID string) ([]ApplicationEvent, error) {
+	return r.find(`application_id = ? ORDER BY version ASC`, applicationID)
+}
+
+// ForApplications retrieves the events of each of the given applications in
+// one query, in the order they happened
+func (r *ApplicationEventRepository) ForApplications(applicationIDs []string) ([]ApplicationEvent, error) {
+	applicationIDs = uniqueIDs(applicationIDs)
+	if len(applicationIDs) == 0 {
+		return nil, nil
+	}
+	placeholders, args := inClause(applicationIDs)
+	return r.find(`application_id IN (`+placeholders+`) ORDER BY sequence ASC`, args...)
+}
+
+// find retrieves the events matching a WHERE clause, which also orders them
+func (r *ApplicationEventRepository) find(where string, args ...interface{}) ([]ApplicationEvent, error) {
+	query := `SELECT sequence, id, application_id, version, type, actor_id, actor_username, data, occurred_at
+			  FROM application_events
+			  WHERE ` + where
+
+	rows, err := r.conn().Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying application events: %v", err)
+	}
+	defer rows.Close()
+
+	var events []ApplicationEvent
+	for rows.Next() {
+		var e ApplicationEvent
+		var actorID, actorUsername sql.NullString
+		var data []byte
+		if err := rows.Scan(&e.Sequence, &e.ID, &e.ApplicationID, &e.Version, &e.Type,
+			&actorID, &actorUsername, &data, &e.OccurredAt); err != nil {
+			return nil, fmt.Errorf("error scanning application event row: %v", err)
+		}
+		e.ActorID = actorID.String
+		e.ActorUsername = actorUsername.String
+		if len(data) > 0 {
+			e.Data = data
+		}
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating application event rows: %v", err)
+	}
+
+	return events, nil
+}
+
+// ChangesStatus reports whether the event moved the application to another
+// status, as approvals and rejections do, rather than recording its whole
+// state or changing other fields
+func (e ApplicationEvent) ChangesStatus() bool {
+	if applicationSnapshotEvents[e.Type] || e.Data == nil {
+		return false
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(e.Data, &fields); err != nil {
+		return false
+	}
+	_, ok := fields["status"]
+	return ok
+}
+
+// ReplayApplication rebuilds the state of an application from its events,
+// oldest first: snapshot events replace the state, others set the fields they
+// hold, and a null field is cleared. Events whose data was dropped are
+// skipped. It returns nil if there are no events or the application was
+// purged. Bookkeeping fields such as version are only as of the last
+// snapshot.
+func ReplayApplication(events []ApplicationEvent) (*Application, error) {
+	var state map[string]json.RawMessage
+	for _, e := range events {
+		if e.Type == AuditActionPurge {
+			state = nil
+			continue
+		}
+		if e.Data == nil {
+			continue
+		}
+
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(e.Data, &fields); err != nil {
+			return nil, fmt.Errorf("error unmarshaling application event %s: %v", e.ID, err)
+		}
+		if applicationSnapshotEvents[e.Type] || state == nil {
+			state = fields
+			continue
+		}
+		for key, value := range fields {
+			if string(value) == "null" {
+				delete(state, key)
+			} else {
+				state[key] = value
+			}
+		}
+	}
+	if state == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling replayed application: %v", err)
+	}
+	var a Application
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil, fmt.Errorf("error unmarshaling replayed application: %v", err)
+	}
+	return &a, nil
+}
+
+// storedApplications retrieves the applications, including deleted ones,
+// matching a WHERE clause, without their applicants and schemes
+func (r *ApplicationEventRepository) storedApplications(where string, args ...interface{}) ([]Application, error) {
+	rows, err := r.conn().Query(`SELECT `+applicationColumns+` FROM applications`+where+` ORDER BY created_at`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying applications: %v", err)
+	}
+	defer rows.Close()
+
+	var applications []Application
+	for rows.Next() {
+		a, err := scanApplication(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning application row: %v", err)
+		}
+		applications = append(applications, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating application rows: %v", err)
+	}
+
+	return applications, nil
+}
+
+// Backfill records a backfill event holding the current state of each
+// application, including deleted ones, with no create, anonymize or backfill
+// event, such as those made before events were recorded. It returns the
+// number of events recorded.
+func (r *ApplicationEventRepository) Backfill() (int, error) {
+	applications, err := r.storedApplications(` WHERE NOT EXISTS (SELECT 1 FROM application_events e
+			  WHERE e.application_id = applications.id AND e.type IN (?, ?, ?))`,
+		AuditActionCreate, AuditActionAnonymize, ApplicationEventBackfill)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range applications {
+		err := runInTx(r.DB, r.tx, func(tx *sql.Tx) error {
+			return recordApplicationSnapshot(tx, &applications[i], ApplicationEventBackfill)
+		})
+		if err != nil {
+			return i, err
+		}
+	}
+	return len(applications), nil
+}
+
+// Verify rebuilds every application, including deleted ones, from its events
+// and compares it with the stored application, returning the number checked
+// and those that differ. Bookkeeping fields are not compared, and deletion
+// times only by whether they are set, as deletes record when they were
+// audited.
+func (r *ApplicationEventRepository) Verify() (int, []ApplicationEventMismatch, error) {
+	applications, err := r.storedApplications("")
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var mismatches []ApplicationEventMismatch
+	for i := range applications {
+		stored := &applications[i]
+		events, err := r.ForApplication(stored.ID)
+		if err != nil {
+			return i, mismatches, err
+		}
+		replayed, err := ReplayApplication(events)
+		if err != nil {
+			return i, mismatches, err
+		}
+		if replayed == nil {
+			mismatches = append(mismatches, ApplicationEventMismatch{ApplicationID: stored.ID, Fields: []string{"id"}})
+			continue
+		}
+
+		fields, err := differentApplicationFields(stored, replayed)
+		if err != nil {
+			return i, mismatches, err
+		}
+		if len(fields) > 0 {
+			mismatches = append(mismatches, ApplicationEventMismatch{ApplicationID: stored.ID, Fields: fields})
+		}
+	}
+	return len(applications), mismatches, nil
+}
+
+// differentApplicationFields returns the sorted names of the fields that
+// differ between a stored and a replayed application, comparing times to the
+// second the databases keep
+func differentApplicationFields(stored, replayed *Application) ([]string, error) {
+	a, err := applicationFields(stored)
+	if err != nil {
+		return nil, err
+	}
+	b, err := applicationFields(replayed)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := map[string]bool{}
+	for key := range a {
+		keys[key] = true
+	}
+	for key := range b {
+		keys[key] = true
+	}
+
+	var fields []string
+	for key := range keys {
+		if auditIgnoredFields[key] {
+			continue
+		}
+		if key == "deleted_at" {
+			if (a[key] == nil) != (b[key] == nil) {
+				fields = append(fields, key)
+			}
+			continue
+		}
+		if !sameEventValue(a[key], b[key]) {
+			fields = append(fields, key)
+		}
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
+
+// applicationFields decodes the JSON fields of an application
+func applicationFields(a *Application) (map[string]interface{}, error) {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling application: %v", err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("error unmarshaling application: %v", err)
+	}
+	return fields, nil
+}
+
+// sameEventValue reports whether two decoded JSON values are equal, treating
+// times within the same second as equal
+func sameEventValue(a, b interface{}) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	as, aok := a.(string)
+	bs, bok := b.(string)
+	if !aok || !bok {
+		return false
+	}
+	at, err := time.Parse(time.RFC3339Nano, as)
+	if err != nil {
+		return false
+	}
+	bt, err := time.Parse(time.RFC3339Nano, bs)
+	if err != nil {
+		return false
+	}
+	return at.Truncate(time.Second).Equal(bt.Truncate(time.Second))
+}
@@ -2,17 +2,54 @@ package models
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+
+	"one-client-view-2025tht/app/date"
+	"one-client-view-2025tht/app/money"
 )
 
+// ErrNotEligible is returned when creating an application for a scheme the
+// applicant does not meet the criteria of
+var ErrNotEligible = errors.New("applicant is not eligible for this scheme")
+
+// ErrSchemeClosed is returned when creating an application for a scheme that
+// is inactive or outside its application window
+var ErrSchemeClosed = errors.New("scheme is not open for applications")
+
+// ErrAlreadyDecided is returned when approving or rejecting an application
+// that is no longer pending
+var ErrAlreadyDecided = errors.New("application has already been decided")
+
+// ErrNotWithdrawable is returned when withdrawing an application that is
+// neither pending nor approved
+var ErrNotWithdrawable = errors.New("application can no longer be withdrawn")
+
+// WithdrawableApplicationStatuses are the statuses applications can be
+// withdrawn from
+var WithdrawableApplicationStatuses = []string{"pending", "approved"}
+
+// ErrCapacityExhausted is returned when approving an application would exceed
+// its scheme's limit on approved applications or its budget
+var ErrCapacityExhausted = errors.New("scheme has no remaining capacity")
+
 // ApplicationRepository handles database operations for applications
 type ApplicationRepository struct {
 	DB            *sql.DB
 	ApplicantRepo *ApplicantRepository
 	SchemeRepo    *SchemeRepository
+	HouseholdRepo *HouseholdRepository // Resolves the shared households applicants are assessed against, if set
+	// BaseCommitments makes approvals without a recommended amount commit
+	// the base projected value of their scheme's benefits, ignoring benefit
+	// formulas, as they did before formulas were counted
+	BaseCommitments bool
+	tx              *sql.Tx
 }
 
 // NewApplicationRepository creates a new repository with the given database connection
@@ -24,49 +61,264 @@ func NewApplicationRepository(db *sql.DB, applicantRepo *ApplicantRepository, sc
 	}
 }
 
+// WithTx returns a copy of the repository, including the applicant, scheme
+// and household repositories it depends on, that runs its queries in tx
+func (r *ApplicationRepository) WithTx(tx *sql.Tx) *ApplicationRepository {
+	repo := &ApplicationRepository{
+		DB:              r.DB,
+		ApplicantRepo:   r.ApplicantRepo.WithTx(tx),
+		SchemeRepo:      r.SchemeRepo.WithTx(tx),
+		BaseCommitments: r.BaseCommitments,
+		tx:              tx,
+	}
+	if r.HouseholdRepo != nil {
+		repo.HouseholdRepo = r.HouseholdRepo.WithTx(tx)
+	}
+	return repo
+}
+
+// conn returns the transaction the repository is bound to, or the database
+func (r *ApplicationRepository) conn() DBTX {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.DB
+}
+
+// applicationColumns is the column list read by scanApplication
+const applicationColumns = `id, applicant_id, scheme_id, status, application_date, decision_date, notes, version, scheme_version, created_at, updated_at, deleted_at, decided_by, decision_reason, recommended_benefit_amount_cents, committed_amount_cents, assigned_to, assigned_at, withdrawn_at, withdrawn_by, withdrawal_reason, previous_application_id`
+
+// scanApplication scans a row selected with applicationColumns
+func scanApplication(row rowScanner) (Application, error) {
+	var a Application
+	var decisionDate sql.NullTime
+	var notes sql.NullString
+	var schemeVersion sql.NullInt64
+	var deletedAt sql.NullTime
+	var decidedBy, decisionReason sql.NullString
+	var recommendedAmount, committedAmount sql.NullInt64
+	var assignedTo sql.NullString
+	var assignedAt sql.NullTime
+	var withdrawnAt sql.NullTime
+	var withdrawnBy, withdrawalReason sql.NullString
+	var previousApplicationID sql.NullString
+
+	if err := row.Scan(&a.ID, &a.ApplicantID, &a.SchemeID, &a.Status,
+		&a.ApplicationDate, &decisionDate, &notes, &a.Version, &schemeVersion,
+		&a.CreatedAt, &a.UpdatedAt, &deletedAt,
+		&decidedBy, &decisionReason, &recommendedAmount, &committedAmount, &assignedTo, &assignedAt,
+		&withdrawnAt, &withdrawnBy, &withdrawalReason, &previousApplicationID); err != nil {
+		return a, err
+	}
+
+	if decisionDate.Valid {
+		a.DecisionDate = &decisionDate.Time
+	}
+	if notes.Valid {
+		a.Notes = notes.String
+	}
+	if schemeVersion.Valid {
+		a.SchemeVersion = int(schemeVersion.Int64)
+	}
+	if deletedAt.Valid {
+		a.DeletedAt = &deletedAt.Time
+	}
+	if decidedBy.Valid {
+		a.DecidedBy = decidedBy.String
+	}
+	if decisionReason.Valid {
+		a.DecisionReason = decisionReason.String
+	}
+	if recommendedAmount.Valid {
+		cents := money.Amount(recommendedAmount.Int64)
+		a.RecommendedBenefitAmount = &cents
+	}
+	if committedAmount.Valid {
+		cents := money.Amount(committedAmount.Int64)
+		a.CommittedAmount = &cents
+	}
+	if assignedTo.Valid {
+		a.AssignedTo = assignedTo.String
+	}
+	if assignedAt.Valid {
+		a.AssignedAt = &assignedAt.Time
+	}
+	if withdrawnAt.Valid {
+		a.WithdrawnAt = &withdrawnAt.Time
+	}
+	a.WithdrawnBy = withdrawnBy.String
+	a.WithdrawalReason = withdrawalReason.String
+	a.PreviousApplicationID = previousApplicationID.String
+	return a, nil
+}
+
+// ApplicationFilter holds optional parameters for listing applications.
+// Zero values are ignored.
+type ApplicationFilter struct {
+	Status         string
+	SchemeID       string
+	ApplicantID    string
+	AppliedAfter   date.Date // Applied on or after this date
+	AppliedBefore  date.Date // Applied before this date
+	AssignedTo     string    // Assigned to this user ID
+	Unassigned     bool      // Not assigned to anyone
+	IncludeDeleted bool      // Include soft-deleted applications
+}
+
+// whereClause builds a parameterized WHERE clause for the filter
+func (f ApplicationFilter) whereClause() (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if !f.IncludeDeleted {
+		conditions = append(conditions, "deleted_at IS NULL")
+	}
+	if f.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, f.Status)
+	}
+	if f.SchemeID != "" {
+		conditions = append(conditions, "scheme_id = ?")
+		args = append(args, f.SchemeID)
+	}
+	if f.ApplicantID != "" {
+		conditions = append(conditions, "applicant_id = ?")
+		args = append(args, f.ApplicantID)
+	}
+	if f.AssignedTo != "" {
+		conditions = append(conditions, "assigned_to = ?")
+		args = append(args, f.AssignedTo)
+	}
+	if f.Unassigned {
+		conditions = append(conditions, "assigned_to IS NULL")
+	}
+	if !f.AppliedAfter.IsZero() {
+		conditions = append(conditions, "application_date >= ?")
+		args = append(args, f.AppliedAfter)
+	}
+	if !f.AppliedBefore.IsZero() {
+		conditions = append(conditions, "application_date < ?")
+		args = append(args, f.AppliedBefore)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// ApplicationExpansion selects the records loaded with applications. Those
+// not selected are left nil, saving their queries.
+type ApplicationExpansion struct {
+	Applicant bool
+	Scheme    bool
+}
+
+// ExpandAll loads the applicant and scheme of applications
+var ExpandAll = ApplicationExpansion{Applicant: true, Scheme: true}
+
 // GetAll retrieves all applications from the database
 func (r *ApplicationRepository) GetAll() ([]Application, error) {
-	query := `SELECT id, applicant_id, scheme_id, status, application_date, decision_date, notes, created_at, updated_at
-			  FROM applications
-			  ORDER BY application_date DESC`
+	return r.Find(ApplicationFilter{})
+}
 
-	rows, err := r.DB.Query(query)
+// Find retrieves applications matching the filter, with their applicants and
+// schemes
+func (r *ApplicationRepository) Find(filter ApplicationFilter) ([]Application, error) {
+	return r.FindExpanded(filter, ExpandAll)
+}
+
+// FindExpanded retrieves applications matching the filter, with the records
+// selected by expand. Applicants and schemes are loaded in batches, so the
+// number of queries does not grow with the number of applications.
+func (r *ApplicationRepository) FindExpanded(filter ApplicationFilter, expand ApplicationExpansion) ([]Application, error) {
+	where, args := filter.whereClause()
+	query := `SELECT ` + applicationColumns + `
+			  FROM applications` + where + `
+			  ORDER BY application_date DESC, created_at DESC`
+
+	applications, err := r.queryApplications(query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("error querying applications: %v", err)
+		return nil, err
 	}
-	defer rows.Close()
 
-	var applications []Application
-	for rows.Next() {
-		var a Application
-		var decisionDate sql.NullTime
-		var notes sql.NullString
-
-		if err := rows.Scan(&a.ID, &a.ApplicantID, &a.SchemeID, &a.Status,
-			&a.ApplicationDate, &decisionDate, &notes, &a.CreatedAt, &a.UpdatedAt); err != nil {
-			return nil, fmt.Errorf("error scanning application row: %v", err)
+	if expand.Applicant {
+		if err := r.attachApplicants(applications); err != nil {
+			return nil, err
 		}
-
-		if decisionDate.Valid {
-			a.DecisionDate = decisionDate
+	}
+	if expand.Scheme {
+		if err := r.attachSchemes(applications); err != nil {
+			return nil, err
 		}
-		if notes.Valid {
-			a.Notes = notes.String
+	}
+
+	return applications, nil
+}
+
+// Stream calls each with successive batches of the applications matching the
+// filter, newest first, with their applicants and schemes. Only one batch is
+// held at a time, so any number of applications can be read; rows are read by
+// keyset pagination on (created_at, id), so applications made meanwhile are
+// neither repeated nor skipped.
+func (r *ApplicationRepository) Stream(filter ApplicationFilter, each func([]Application) error) error {
+	where, args := filter.whereClause()
+
+	var last *Application
+	for {
+		clause, batchArgs := where, args
+		if last != nil {
+			const keyset = `(created_at < ? OR (created_at = ? AND id < ?))`
+			if clause == "" {
+				clause = " WHERE " + keyset
+			} else {
+				clause += " AND " + keyset
+			}
+			batchArgs = append(append([]interface{}{}, args...), last.CreatedAt, last.CreatedAt, last.ID)
 		}
+		query := `SELECT ` + applicationColumns + `
+				  FROM applications` + clause + `
+				  ORDER BY created_at DESC, id DESC
+				  LIMIT ` + strconv.Itoa(streamBatchSize)
 
-		// Get applicant and scheme details
-		applicant, err := r.ApplicantRepo.GetByID(a.ApplicantID)
+		batch, err := r.queryApplications(query, batchArgs...)
 		if err != nil {
-			return nil, fmt.Errorf("error getting applicant: %v", err)
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := r.attachApplicants(batch); err != nil {
+			return err
 		}
-		a.Applicant = applicant
+		if err := r.attachSchemes(batch); err != nil {
+			return err
+		}
+		if err := each(batch); err != nil {
+			return err
+		}
+		if len(batch) < streamBatchSize {
+			return nil
+		}
+		last = &batch[len(batch)-1]
+	}
+}
 
-		scheme, err := r.SchemeRepo.GetByID(a.SchemeID)
+// queryApplications runs a query selecting applicationColumns and scans the
+// resulting rows
+func (r *ApplicationRepository) queryApplications(query string, args ...interface{}) ([]Application, error) {
+	rows, err := r.conn().Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying applications: %v", err)
+	}
+	defer rows.Close()
+
+	var applications []Application
+	for rows.Next() {
+		a, err := scanApplication(rows)
 		if err != nil {
-			return nil, fmt.Errorf("error getting scheme: %v", err)
+			return nil, fmt.Errorf("error scanning application row: %v", err)
 		}
-		a.Scheme = scheme
-
 		applications = append(applications, a)
 	}
 
@@ -77,19 +329,93 @@ func (r *ApplicationRepository) GetAll() ([]Application, error) {
 	return applications, nil
 }
 
-// GetByID retrieves an application by ID
+// attachApplicants loads the applicants of all the given applications in one batch
+func (r *ApplicationRepository) attachApplicants(applications []Application) error {
+	ids := make([]string, len(applications))
+	for i := range applications {
+		ids[i] = applications[i].ApplicantID
+	}
+
+	applicants, err := r.ApplicantRepo.GetByIDs(ids)
+	if err != nil {
+		return fmt.Errorf("error getting applicants: %v", err)
+	}
+
+	for i := range applications {
+		applications[i].Applicant = applicants[applications[i].ApplicantID]
+	}
+	return nil
+}
+
+// attachSchemes loads the schemes of all the given applications in one batch,
+// each pinned to the version the application was assessed under
+func (r *ApplicationRepository) attachSchemes(applications []Application) error {
+	ids := make([]string, len(applications))
+	for i := range applications {
+		ids[i] = applications[i].SchemeID
+	}
+
+	schemes, err := r.SchemeRepo.GetByIDs(ids)
+	if err != nil {
+		return fmt.Errorf("error getting schemes: %v", err)
+	}
+
+	// Applications of the same scheme version share one copy
+	pinned := make(map[string]*Scheme)
+	for i := range applications {
+		key := applications[i].SchemeID + "@" + strconv.Itoa(applications[i].SchemeVersion)
+		scheme, ok := pinned[key]
+		if !ok {
+			scheme, err = r.pinScheme(schemes[applications[i].SchemeID], applications[i].SchemeVersion)
+			if err != nil {
+				return err
+			}
+			pinned[key] = scheme
+		}
+		applications[i].Scheme = scheme
+	}
+	return nil
+}
+
+// pinScheme returns a copy of the scheme with the terms of the given version,
+// or the scheme itself if it is already at that version. Applications made
+// before schemes were versioned have no version and see the current terms.
+func (r *ApplicationRepository) pinScheme(scheme *Scheme, version int) (*Scheme, error) {
+	if scheme == nil || version == 0 || version == scheme.Version {
+		return scheme, nil
+	}
+
+	v, err := r.SchemeRepo.GetVersion(scheme.ID, version)
+	if err != nil {
+		return nil, fmt.Errorf("error getting scheme version: %v", err)
+	}
+	if v == nil {
+		return scheme, nil
+	}
+
+	pinned := scheme.withVersion(*v)
+	return &pinned, nil
+}
+
+// GetByID retrieves an application by ID, excluding soft-deleted applications
 func (r *ApplicationRepository) GetByID(id string) (*Application, error) {
-	query := `SELECT id, applicant_id, scheme_id, status, application_date, decision_date, notes, created_at, updated_at
-			  FROM applications
-			  WHERE id = ?`
+	return r.getByID(id, false)
+}
 
-	var a Application
-	var decisionDate sql.NullTime
-	var notes sql.NullString
+// GetByIDIncludingDeleted retrieves an application by ID, even if soft-deleted
+func (r *ApplicationRepository) GetByIDIncludingDeleted(id string) (*Application, error) {
+	return r.getByID(id, true)
+}
 
-	err := r.DB.QueryRow(query, id).Scan(&a.ID, &a.ApplicantID, &a.SchemeID, &a.Status,
-		&a.ApplicationDate, &decisionDate, &notes, &a.CreatedAt, &a.UpdatedAt)
+func (r *ApplicationRepository) getByID(id string, includeDeleted bool) (*Application, error) {
+	query := `SELECT ` + applicationColumns + `
+			  FROM applications
+			  WHERE id = ?`
+	if !includeDeleted {
+		query += " AND deleted_at IS NULL"
+	}
 
+	a, err := scanApplication(r.conn().QueryRow(query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // No application found
@@ -97,15 +423,9 @@ func (r *ApplicationRepository) GetByID(id string) (*Application, error) {
 		return nil, fmt.Errorf("error querying application: %v", err)
 	}
 
-	if decisionDate.Valid {
-		a.DecisionDate = decisionDate
-	}
-	if notes.Valid {
-		a.Notes = notes.String
-	}
-
-	// Get applicant and scheme details
-	applicant, err := r.ApplicantRepo.GetByID(a.ApplicantID)
+	// Get applicant and scheme details. The applicant may have been
+	// soft-deleted since applying.
+	applicant, err := r.ApplicantRepo.GetByIDIncludingDeleted(a.ApplicantID)
 	if err != nil {
 		return nil, fmt.Errorf("error getting applicant: %v", err)
 	}
@@ -115,162 +435,409 @@ func (r *ApplicationRepository) GetByID(id string) (*Application, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error getting scheme: %v", err)
 	}
-	a.Scheme = scheme
+	a.Scheme, err = r.pinScheme(scheme, a.SchemeVersion)
+	if err != nil {
+		return nil, err
+	}
 
 	return &a, nil
 }
 
 // GetByApplicantID retrieves all applications for an applicant
 func (r *ApplicationRepository) GetByApplicantID(applicantID string) ([]Application, error) {
-	query := `SELECT id, applicant_id, scheme_id, status, application_date, decision_date, notes, created_at, updated_at
+	query := `SELECT ` + applicationColumns + `
 			  FROM applications
-			  WHERE applicant_id = ?
-			  ORDER BY application_date DESC`
+			  WHERE applicant_id = ? AND deleted_at IS NULL
+			  ORDER BY application_date DESC, created_at DESC`
 
-	rows, err := r.DB.Query(query, applicantID)
+	applications, err := r.queryApplications(query, applicantID)
 	if err != nil {
-		return nil, fmt.Errorf("error querying applications: %v", err)
+		return nil, err
 	}
-	defer rows.Close()
-
-	var applications []Application
-	for rows.Next() {
-		var a Application
-		var decisionDate sql.NullTime
-		var notes sql.NullString
 
-		if err := rows.Scan(&a.ID, &a.ApplicantID, &a.SchemeID, &a.Status,
-			&a.ApplicationDate, &decisionDate, &notes, &a.CreatedAt, &a.UpdatedAt); err != nil {
-			return nil, fmt.Errorf("error scanning application row: %v", err)
-		}
-
-		if decisionDate.Valid {
-			a.DecisionDate = decisionDate
-		}
-		if notes.Valid {
-			a.Notes = notes.String
-		}
-
-		// Get scheme details
-		scheme, err := r.SchemeRepo.GetByID(a.SchemeID)
-		if err != nil {
-			return nil, fmt.Errorf("error getting scheme: %v", err)
-		}
-		a.Scheme = scheme
-
-		applications = append(applications, a)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating application rows: %v", err)
+	// Get scheme details
+	if err := r.attachSchemes(applications); err != nil {
+		return nil, err
 	}
 
 	return applications, nil
 }
 
-// Create inserts a new application into the database
+// Create inserts a new application into the database after the checks of
+// Assess, returning the error of the first that fails: ErrSchemeClosed,
+// ErrNotEligible, ErrDuplicateApplication, ErrCoolingOff, or an error for a
+// missing applicant or scheme. The application is pinned to the scheme terms
+// it was assessed under, linked to the rejected application it resubmits, if
+// any, and given a copy of the scheme's review checklist.
 func (r *ApplicationRepository) Create(a *Application) error {
-	// Validate applicant and scheme exist
-	applicant, err := r.ApplicantRepo.GetByID(a.ApplicantID)
-	if err != nil {
-		return fmt.Errorf("error validating applicant: %v", err)
-	}
-	if applicant == nil {
-		return fmt.Errorf("applicant not found: %s", a.ApplicantID)
-	}
-
-	scheme, err := r.SchemeRepo.GetByID(a.SchemeID)
+	now := time.Now()
+	assessment, err := r.Assess(a.ApplicantID, a.SchemeID, now)
 	if err != nil {
-		return fmt.Errorf("error validating scheme: %v", err)
+		return err
 	}
-	if scheme == nil {
-		return fmt.Errorf("scheme not found: %s", a.SchemeID)
-	}
-
-	// Check if applicant is eligible for the scheme
-	if !isEligible(applicant, scheme) {
-		return fmt.Errorf("applicant is not eligible for this scheme")
+	if assessment.err != nil {
+		return assessment.err
 	}
+	a.SchemeVersion = assessment.SchemeVersion
+	a.PreviousApplicationID = assessment.PreviousApplicationID
 
 	// Generate UUID if not provided
 	if a.ID == "" {
 		a.ID = uuid.New().String()
 	}
 
-	now := time.Now()
 	a.CreatedAt = now
 	a.UpdatedAt = now
-	a.ApplicationDate = now
+	a.ApplicationDate = date.Of(now)
+	a.Version = 1
 
 	// Set default status if not provided
 	if a.Status == "" {
 		a.Status = "pending"
 	}
 
-	query := `INSERT INTO applications (id, applicant_id, scheme_id, status, application_date, notes, created_at, updated_at)
-			  VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	query := `INSERT INTO applications (id, applicant_id, scheme_id, status, application_date, notes, version, scheme_version,
+			      previous_application_id, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-	_, err = r.DB.Exec(query, a.ID, a.ApplicantID, a.SchemeID, a.Status,
-		a.ApplicationDate, a.Notes, a.CreatedAt, a.UpdatedAt)
-
-	if err != nil {
-		return fmt.Errorf("error creating application: %v", err)
-	}
+	// Insert the application and its copy of the scheme's checklist atomically
+	return runInTx(r.DB, r.tx, func(tx *sql.Tx) error {
+		_, err := tx.Exec(query, a.ID, a.ApplicantID, a.SchemeID, a.Status,
+			a.ApplicationDate, a.Notes, a.Version, a.SchemeVersion, nullString(a.PreviousApplicationID), a.CreatedAt, a.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("error creating application: %v", err)
+		}
 
-	return nil
+		return r.WithTx(tx).copyChecklist(a)
+	})
 }
 
-// Update updates an existing application
+// Update updates an existing application if its stored version matches
+// a.Version, returning ErrVersionConflict otherwise. On success a.Version is
+// incremented.
 func (r *ApplicationRepository) Update(a *Application) error {
 	a.UpdatedAt = time.Now()
 
 	var decisionDate interface{}
-	if a.DecisionDate.Valid {
-		decisionDate = a.DecisionDate.Time
-	} else {
-		decisionDate = nil
+	if a.DecisionDate != nil {
+		decisionDate = *a.DecisionDate
 	}
 
 	query := `UPDATE applications
-			  SET status = ?, decision_date = ?, notes = ?, updated_at = ?
-			  WHERE id = ?`
+			  SET status = ?, decision_date = ?, notes = ?, version = version + 1, updated_at = ?
+			  WHERE id = ? AND version = ?`
 
-	_, err := r.DB.Exec(query, a.Status, decisionDate, a.Notes, a.UpdatedAt, a.ID)
+	result, err := r.conn().Exec(query, a.Status, decisionDate, a.Notes, a.UpdatedAt, a.ID, a.Version)
 	if err != nil {
 		return fmt.Errorf("error updating application: %v", err)
 	}
+	if err := checkVersioned(result); err != nil {
+		return err
+	}
 
+	a.Version++
 	return nil
 }
 
-// UpdateStatus updates the status of an application
-func (r *ApplicationRepository) UpdateStatus(id, status string) error {
+// Decide approves or rejects a pending application, recording the deciding
+// user, the reason and, for approvals, the recommended benefit amount. The
+// status check and update happen in a single statement, so concurrent
+// decisions cannot both succeed; ErrAlreadyDecided is returned if the
+// application is no longer pending. Approvals fail with
+// ErrChecklistIncomplete while any mandatory item of the application's review
+// checklist is not complete, and take up the capacity of the scheme, or fail
+// with ErrCapacityExhausted if it has none left; the amount they commit is
+// recorded so that a withdrawal releases exactly that. On success a is updated to
+// match.
+func (r *ApplicationRepository) Decide(a *Application, status, decidedBy, reason string, recommendedAmount *money.Amount) error {
+	if status != "approved" && status != "rejected" {
+		return fmt.Errorf("invalid decision status: %q", status)
+	}
+
 	now := time.Now()
-	var decisionDate interface{}
+	var decider interface{}
+	if decidedBy != "" {
+		decider = decidedBy
+	}
+
+	query := `UPDATE applications
+			  SET status = ?, decision_date = ?, decided_by = ?, decision_reason = ?, recommended_benefit_amount_cents = ?,
+			      committed_amount_cents = ?, version = version + 1, updated_at = ?
+			  WHERE id = ? AND status = 'pending' AND deleted_at IS NULL`
+
+	var committed *money.Amount
+	err := runInTx(r.DB, r.tx, func(tx *sql.Tx) error {
+		if status == "approved" {
+			amount, err := r.WithTx(tx).commitment(a, recommendedAmount, now)
+			if err != nil {
+				return err
+			}
+			committed = &amount
+		}
+
+		result, err := tx.Exec(query, status, now, decider, reason, recommendedAmount, committed, now, a.ID)
+		if err != nil {
+			return fmt.Errorf("error deciding application: %v", err)
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("error checking rows affected: %v", err)
+		}
+		if rows == 0 {
+			return ErrAlreadyDecided
+		}
+
+		if status != "approved" {
+			return nil
+		}
+		if err := r.WithTx(tx).checkChecklist(a.ID); err != nil {
+			return err
+		}
+		return r.WithTx(tx).takeCapacity(a.SchemeID, *committed)
+	})
+	if err != nil {
+		return err
+	}
+
+	a.Status = status
+	a.DecisionDate = &now
+	a.DecidedBy = decidedBy
+	a.DecisionReason = reason
+	a.RecommendedBenefitAmount = recommendedAmount
+	a.CommittedAmount = committed
+	a.Version++
+	a.UpdatedAt = now
+	return nil
+}
 
-	// If status is approved or rejected, set decision date
-	if status == "approved" || status == "rejected" {
-		decisionDate = now
-	} else {
-		decisionDate = nil
+// Withdraw moves a pending or approved application to the terminal withdrawn
+// status if its stored version matches a.Version, returning
+// ErrVersionConflict otherwise, or ErrNotWithdrawable if it has been rejected
+// or withdrawn already. withdrawnBy is the staff user withdrawing it, or empty
+// if the applicant is. Withdrawing an approval releases the capacity of the
+// scheme it took up. On success a is updated to match.
+func (r *ApplicationRepository) Withdraw(a *Application, withdrawnBy, reason string) error {
+	if !slices.Contains(WithdrawableApplicationStatuses, a.Status) {
+		return ErrNotWithdrawable
+	}
+
+	now := time.Now()
+	var withdrawer interface{}
+	if withdrawnBy != "" {
+		withdrawer = withdrawnBy
 	}
 
 	query := `UPDATE applications
-			  SET status = ?, decision_date = ?, updated_at = ?
+			  SET status = 'withdrawn', withdrawn_at = ?, withdrawn_by = ?, withdrawal_reason = ?,
+			      version = version + 1, updated_at = ?
+			  WHERE id = ? AND version = ? AND deleted_at IS NULL`
+
+	err := runInTx(r.DB, r.tx, func(tx *sql.Tx) error {
+		result, err := tx.Exec(query, now, withdrawer, reason, now, a.ID, a.Version)
+		if err != nil {
+			return fmt.Errorf("error withdrawing application: %v", err)
+		}
+		if err := checkVersioned(result); err != nil {
+			return err
+		}
+
+		if a.Status != "approved" {
+			return nil
+		}
+		return r.WithTx(tx).releaseCapacity(a.SchemeID, a.CommittedAmount)
+	})
+	if err != nil {
+		return err
+	}
+
+	a.Status = "withdrawn"
+	a.WithdrawnAt = &now
+	a.WithdrawnBy = withdrawnBy
+	a.WithdrawalReason = reason
+	a.Version++
+	a.UpdatedAt = now
+	return nil
+}
+
+// Assign assigns an application to the user with the given ID, or unassigns
+// it if userID is empty, if its stored version matches a.Version, returning
+// ErrVersionConflict otherwise. On success a is updated to match.
+func (r *ApplicationRepository) Assign(a *Application, userID string) error {
+	now := time.Now()
+	var assignee interface{}
+	var assignedAt *time.Time
+	if userID != "" {
+		assignee = userID
+		assignedAt = &now
+	}
+
+	query := `UPDATE applications
+			  SET assigned_to = ?, assigned_at = ?, version = version + 1, updated_at = ?
+			  WHERE id = ? AND version = ? AND deleted_at IS NULL`
+
+	result, err := r.conn().Exec(query, assignee, assignedAt, now, a.ID, a.Version)
+	if err != nil {
+		return fmt.Errorf("error assigning application: %v", err)
+	}
+	if err := checkVersioned(result); err != nil {
+		return err
+	}
+
+	a.AssignedTo = userID
+	a.AssignedAt = assignedAt
+	a.Version++
+	a.UpdatedAt = now
+	return nil
+}
+
+// commitment returns the amount approving a commits against its scheme's
+// budget: amount if recommended, or else the applicant's entitlement to the
+// scheme's benefits at the given time, counting their household with any
+// benefit formulas, or with BaseCommitments the projected value of the
+// scheme's benefits
+func (r *ApplicationRepository) commitment(a *Application, amount *money.Amount, at time.Time) (money.Amount, error) {
+	if amount != nil {
+		return *amount, nil
+	}
+	if r.BaseCommitments {
+		var total money.Amount
+		query := `SELECT COALESCE(SUM(` + benefitProjectedValueExpr + `), 0) FROM benefits WHERE scheme_id = ?`
+		if err := r.conn().QueryRow(query, a.SchemeID).Scan(&total); err != nil {
+			return 0, fmt.Errorf("error summing scheme benefits: %v", err)
+		}
+		return total, nil
+	}
+
+	applicant, err := r.ApplicantRepo.GetByIDIncludingDeleted(a.ApplicantID)
+	if err != nil {
+		return 0, fmt.Errorf("error getting applicant: %v", err)
+	}
+	if applicant == nil {
+		return 0, fmt.Errorf("applicant not found: %s", a.ApplicantID)
+	}
+	if r.HouseholdRepo != nil {
+		if applicant, err = r.HouseholdRepo.Resolve(applicant, at); err != nil {
+			return 0, fmt.Errorf("error resolving household: %v", err)
+		}
+	}
+
+	scheme, err := r.SchemeRepo.GetByID(a.SchemeID)
+	if err != nil {
+		return 0, fmt.Errorf("error getting scheme: %v", err)
+	}
+	if scheme == nil {
+		return 0, fmt.Errorf("scheme not found: %s", a.SchemeID)
+	}
+
+	return EstimateBenefits(scheme, applicant, true, at).ProjectedValue, nil
+}
+
+// takeCapacity adds an approval committing amount to the approved totals of
+// a scheme. The limits are checked in the same statement, so concurrent
+// approvals cannot exceed them.
+func (r *ApplicationRepository) takeCapacity(schemeID string, amount money.Amount) error {
+	query := `UPDATE schemes
+			  SET approved_count = approved_count + 1, approved_amount_cents = approved_amount_cents + ?
+			  WHERE id = ?
+			    AND (max_applications IS NULL OR approved_count < max_applications)
+			    AND (budget_cents IS NULL OR approved_amount_cents + ? <= budget_cents)`
+
+	result, err := r.conn().Exec(query, amount, schemeID, amount)
+	if err != nil {
+		return fmt.Errorf("error updating scheme capacity: %v", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %v", err)
+	}
+	if rows == 0 {
+		return ErrCapacityExhausted
+	}
+	return nil
+}
+
+// releaseCapacity removes an approval from the approved totals of a scheme,
+// with the amount it committed as recorded when takeCapacity added it
+func (r *ApplicationRepository) releaseCapacity(schemeID string, amount *money.Amount) error {
+	if amount == nil {
+		return fmt.Errorf("approval has no committed amount recorded")
+	}
+
+	query := `UPDATE schemes
+			  SET approved_count = approved_count - 1, approved_amount_cents = approved_amount_cents - ?
 			  WHERE id = ?`
 
-	_, err := r.DB.Exec(query, status, decisionDate, now, id)
+	if _, err := r.conn().Exec(query, *amount, schemeID); err != nil {
+		return fmt.Errorf("error updating scheme capacity: %v", err)
+	}
+	return nil
+}
+
+// Delete soft-deletes an application, keeping it for case history. Deleting
+// an approval releases the capacity of the scheme it took up, as withdrawing
+// it does. Deleting an application already deleted does nothing.
+func (r *ApplicationRepository) Delete(a *Application) error {
+	now := time.Now()
+	query := `UPDATE applications SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`
+
+	err := runInTx(r.DB, r.tx, func(tx *sql.Tx) error {
+		result, err := tx.Exec(query, now, a.ID)
+		if err != nil {
+			return fmt.Errorf("error deleting application: %v", err)
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("error checking rows affected: %v", err)
+		}
+		if rows == 0 || a.Status != "approved" {
+			return nil
+		}
+		return r.WithTx(tx).releaseCapacity(a.SchemeID, a.CommittedAmount)
+	})
+	if err != nil {
+		return err
+	}
+
+	a.DeletedAt = &now
+	return nil
+}
+
+// Restore reverses a soft delete. Restoring an approval takes up the capacity
+// of its scheme again, with the amount it committed, or fails with
+// ErrCapacityExhausted if the scheme has none left. Restoring an application
+// that is not deleted does nothing.
+func (r *ApplicationRepository) Restore(a *Application) error {
+	query := `UPDATE applications SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL`
+
+	err := runInTx(r.DB, r.tx, func(tx *sql.Tx) error {
+		result, err := tx.Exec(query, a.ID)
+		if err != nil {
+			return fmt.Errorf("error restoring application: %v", err)
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("error checking rows affected: %v", err)
+		}
+		if rows == 0 || a.Status != "approved" {
+			return nil
+		}
+		if a.CommittedAmount == nil {
+			return fmt.Errorf("approval has no committed amount recorded")
+		}
+		return r.WithTx(tx).takeCapacity(a.SchemeID, *a.CommittedAmount)
+	})
 	if err != nil {
-		return fmt.Errorf("error updating application status: %v", err)
+		return err
 	}
 
+	a.DeletedAt = nil
 	return nil
 }
 
-// Delete removes an application
-func (r *ApplicationRepository) Delete(id string) error {
+// HardDelete permanently removes an application
+func (r *ApplicationRepository) HardDelete(id string) error {
 	query := `DELETE FROM applications WHERE id = ?`
-	_, err := r.DB.Exec(query, id)
+	_, err := r.conn().Exec(query, id)
 	if err != nil {
 		return fmt.Errorf("error deleting application: %v", err)
 	}
@@ -0,0 +1,198 @@
+package models
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"one-client-view-2025tht/app/database"
+	"one-client-view-2025tht/app/date"
+	"one-client-view-2025tht/app/encryption"
+	"one-client-view-2025tht/app/money"
+)
+
+// newTestRepositories returns repositories over a fresh, migrated SQLite
+// database in a temporary file
+func newTestRepositories(t *testing.T) (*ApplicantRepository, *SchemeRepository, *ApplicationRepository) {
+	t.Helper()
+	db, err := database.Initialize(&database.Config{
+		Driver: database.DriverSQLite,
+		Path:   filepath.Join(t.TempDir(), "test.sqlite"),
+	})
+	if err != nil {
+		t.Fatalf("initializing database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	cipher, err := encryption.New([]byte(strings.Repeat("t", 32)))
+	if err != nil {
+		t.Fatalf("creating cipher: %v", err)
+	}
+	applicants := NewApplicantRepository(db.DB, cipher)
+	schemes := NewSchemeRepository(db.DB)
+	return applicants, schemes, NewApplicationRepository(db.DB, applicants, schemes)
+}
+
+// createApplication creates an applicant with the given household and
+// applies for schemeID
+func createApplication(t *testing.T, applicants *ApplicantRepository, applications *ApplicationRepository,
+	schemeID string, household []HouseholdMember) *Application {
+	t.Helper()
+	applicant := &Applicant{
+		Name:             "Tan Ah Kow",
+		EmploymentStatus: "unemployed",
+		Sex:              "male",
+		DateOfBirth:      date.New(1970, 1, 1),
+		MaritalStatus:    "married",
+		Household:        household,
+	}
+	if err := applicants.Create(applicant); err != nil {
+		t.Fatalf("creating applicant: %v", err)
+	}
+	a := &Application{ApplicantID: applicant.ID, SchemeID: schemeID}
+	if err := applications.Create(a); err != nil {
+		t.Fatalf("creating application: %v", err)
+	}
+	return a
+}
+
+// approveApplication creates an application for schemeID and approves it for
+// amount
+func approveApplication(t *testing.T, applicants *ApplicantRepository, applications *ApplicationRepository,
+	schemeID string, amount money.Amount) *Application {
+	t.Helper()
+	a := createApplication(t, applicants, applications, schemeID, nil)
+	if err := applications.Decide(a, "approved", "", "Meets criteria", &amount); err != nil {
+		t.Fatalf("approving application: %v", err)
+	}
+	return a
+}
+
+func assertCapacity(t *testing.T, schemes *SchemeRepository, schemeID string, count int, amount money.Amount) {
+	t.Helper()
+	s, err := schemes.GetByID(schemeID)
+	if err != nil {
+		t.Fatalf("getting scheme: %v", err)
+	}
+	if s.ApprovedCount != count || s.ApprovedAmount != amount {
+		t.Errorf("approved count, amount = %d, %v, want %d, %v", s.ApprovedCount, s.ApprovedAmount, count, amount)
+	}
+}
+
+func TestDeleteAndRestoreApprovedApplicationCapacity(t *testing.T) {
+	applicants, schemes, applications := newTestRepositories(t)
+
+	maxApplications := 1
+	scheme := &Scheme{
+		Name:            "Capped scheme",
+		Description:     "One approval only",
+		IsActive:        true,
+		Status:          SchemePublished,
+		MaxApplications: &maxApplications,
+		Benefits:        []Benefit{{Name: "Grant", Amount: money.Amount(50000)}},
+	}
+	if err := schemes.Create(scheme); err != nil {
+		t.Fatalf("creating scheme: %v", err)
+	}
+
+	first := approveApplication(t, applicants, applications, scheme.ID, money.Amount(30000))
+	assertCapacity(t, schemes, scheme.ID, 1, money.Amount(30000))
+
+	// Deleting the approval releases its place for another
+	if err := applications.Delete(first); err != nil {
+		t.Fatalf("deleting application: %v", err)
+	}
+	assertCapacity(t, schemes, scheme.ID, 0, 0)
+
+	// Deleting it again releases nothing more
+	if err := applications.Delete(first); err != nil {
+		t.Fatalf("deleting application again: %v", err)
+	}
+	assertCapacity(t, schemes, scheme.ID, 0, 0)
+
+	// Restoring it takes the place up again
+	if err := applications.Restore(first); err != nil {
+		t.Fatalf("restoring application: %v", err)
+	}
+	assertCapacity(t, schemes, scheme.ID, 1, money.Amount(30000))
+
+	// Once the place has gone to another approval, the deleted one cannot be
+	// restored
+	if err := applications.Delete(first); err != nil {
+		t.Fatalf("deleting application: %v", err)
+	}
+	second := approveApplication(t, applicants, applications, scheme.ID, money.Amount(20000))
+	assertCapacity(t, schemes, scheme.ID, 1, money.Amount(20000))
+
+	if err := applications.Restore(first); !errors.Is(err, ErrCapacityExhausted) {
+		t.Fatalf("restoring application over capacity: got %v, want ErrCapacityExhausted", err)
+	}
+	assertCapacity(t, schemes, scheme.ID, 1, money.Amount(20000))
+
+	stored, err := applications.GetByIDIncludingDeleted(first.ID)
+	if err != nil {
+		t.Fatalf("getting application: %v", err)
+	}
+	if stored.DeletedAt == nil {
+		t.Error("application restored over capacity, want it still deleted")
+	}
+	if second.Status != "approved" {
+		t.Errorf("second application status = %q, want approved", second.Status)
+	}
+}
+
+func TestApprovalCommitsFormulaEntitlement(t *testing.T) {
+	applicants, schemes, applications := newTestRepositories(t)
+
+	// 100.00 plus 100.00 for each child, against a budget of 500.00
+	budget := money.Amount(50000)
+	scheme := &Scheme{
+		Name:        "Family grant",
+		Description: "Scaled with the household",
+		IsActive:    true,
+		Status:      SchemePublished,
+		Budget:      &budget,
+		Benefits: []Benefit{{
+			Name:    "Grant",
+			Amount:  money.Amount(10000),
+			Formula: &BenefitFormula{PerChildAmount: money.Amount(10000)},
+		}},
+	}
+	if err := schemes.Create(scheme); err != nil {
+		t.Fatalf("creating scheme: %v", err)
+	}
+
+	child := func(name string) HouseholdMember {
+		return HouseholdMember{Name: name, Relation: RelationSon, Sex: "male",
+			EmploymentStatus: "unemployed", DateOfBirth: date.New(2018, 1, 1)}
+	}
+
+	// Two children: 300.00 committed, where the scheme's base projected
+	// value is only 100.00
+	first := createApplication(t, applicants, applications, scheme.ID,
+		[]HouseholdMember{child("Child 1"), child("Child 2")})
+	if err := applications.Decide(first, "approved", "", "Meets criteria", nil); err != nil {
+		t.Fatalf("approving application: %v", err)
+	}
+	if first.CommittedAmount == nil || *first.CommittedAmount != money.Amount(30000) {
+		t.Errorf("committed amount = %v, want 300.00", first.CommittedAmount)
+	}
+	assertCapacity(t, schemes, scheme.ID, 1, money.Amount(30000))
+
+	// Three children: 400.00 would exceed the 200.00 left of the budget
+	second := createApplication(t, applicants, applications, scheme.ID,
+		[]HouseholdMember{child("Child 1"), child("Child 2"), child("Child 3")})
+	if err := applications.Decide(second, "approved", "", "Meets criteria", nil); !errors.Is(err, ErrCapacityExhausted) {
+		t.Fatalf("approving application over budget: got %v, want ErrCapacityExhausted", err)
+	}
+	assertCapacity(t, schemes, scheme.ID, 1, money.Amount(30000))
+
+	// One child: 200.00 uses up the rest of the budget
+	third := createApplication(t, applicants, applications, scheme.ID,
+		[]HouseholdMember{child("Child 1")})
+	if err := applications.Decide(third, "approved", "", "Meets criteria", nil); err != nil {
+		t.Fatalf("approving application: %v", err)
+	}
+	assertCapacity(t, schemes, scheme.ID, 2, money.Amount(50000))
+}
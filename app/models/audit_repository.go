@@ -0,0 +1,452 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Entity types recorded in the audit log
+const (
+	AuditEntityApplicant            = "applicant"
+	AuditEntityScheme               = "scheme"
+	AuditEntityApplication          = "application"
+	AuditEntityBenefit              = "benefit"
+	AuditEntityDocument             = "document"
+	AuditEntityCaseNote             = "case_note"
+	AuditEntityPhoto                = "applicant_photo"
+	AuditEntityConsent              = "consent"
+	AuditEntitySchemeTranslation    = "scheme_translation"
+	AuditEntityTask                 = "task"
+	AuditEntityComment              = "comment"
+	AuditEntityReferral             = "referral"
+	AuditEntityCustomField          = "custom_field"
+	AuditEntityFeatureFlag          = "feature_flag"
+	AuditEntityHousehold            = "household"
+	AuditEntityHouseholdMembership  = "household_membership"
+	AuditEntityChecklistItem        = "checklist_item"
+	AuditEntityApplicationChecklist = "application_checklist_item"
+)
+
+// Actions recorded in the audit log
+const (
+	AuditActionCreate    = "create"
+	AuditActionUpdate    = "update"
+	AuditActionDelete    = "delete"
+	AuditActionRestore   = "restore"
+	AuditActionApprove   = "approve"
+	AuditActionReject    = "reject"
+	AuditActionWithdraw  = "withdraw"
+	AuditActionMerge     = "merge"
+	AuditActionPurge     = "purge"
+	AuditActionAnonymize = "anonymize"
+	AuditActionAssign    = "assign"
+	AuditActionUnassign  = "unassign"
+	AuditActionPublish   = "publish"
+	AuditActionArchive   = "archive"
+)
+
+// auditIgnoredFields are bookkeeping fields left out of computed changes
+var auditIgnoredFields = map[string]bool{
+	"created_at": true,
+	"updated_at": true,
+	"version":    true,
+}
+
+// auditMaskedFields are sensitive top-level fields stored masked in snapshots,
+// so the audit log does not hold values that are encrypted elsewhere
+var auditMaskedFields = map[string]func(string) string{
+	"identity_number": MaskIdentityNumber,
+}
+
+// auditPersonFields are the personal fields, encrypted at rest, stored masked
+// in applicant snapshots, both for the applicant and for each of their
+// household members
+var auditPersonFields = map[string]func(string) string{
+	"identity_number": MaskIdentityNumber,
+	"name":            MaskName,
+	"date_of_birth":   MaskDateOfBirth,
+}
+
+// AuditFilter holds optional parameters for querying the audit log.
+// Zero values are ignored.
+type AuditFilter struct {
+	EntityType string
+	EntityID   string
+	Action     string
+	Actor      string // Matches either the actor ID or username
+	From       time.Time
+	To         time.Time
+	Limit      int
+}
+
+// AuditRepository handles database operations for audit logs
+type AuditRepository struct {
+	DB            *sql.DB
+	ExportChanges bool // Whether entries are also added to the change_events outbox
+	tx            *sql.Tx
+}
+
+// NewAuditRepository creates a new repository with the given database connection
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{DB: db}
+}
+
+// WithTx returns a copy of the repository that runs its queries in tx, so
+// audit entries are only stored if the audited mutation commits
+func (r *AuditRepository) WithTx(tx *sql.Tx) *AuditRepository {
+	return &AuditRepository{DB: r.DB, ExportChanges: r.ExportChanges, tx: tx}
+}
+
+// conn returns the transaction the repository is bound to, or the database
+func (r *AuditRepository) conn() DBTX {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.DB
+}
+
+// Record stores an audit entry for a mutation. before and after are snapshots
+// of the entity, either of which may be nil for creates and deletes.
+func (r *AuditRepository) Record(entityType, entityID, action string, actor Actor, before, after interface{}) error {
+	entry, err := NewAuditLog(entityType, entityID, action, actor, before, after)
+	if err != nil {
+		return err
+	}
+	return r.Create(entry)
+}
+
+// NewAuditLog builds an audit entry, computing the field-level changes
+// between the before and after snapshots
+func NewAuditLog(entityType, entityID, action string, actor Actor, before, after interface{}) (*AuditLog, error) {
+	beforeJSON, err := snapshotJSON(before)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling audit snapshot: %v", err)
+	}
+	afterJSON, err := snapshotJSON(after)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling audit snapshot: %v", err)
+	}
+
+	// Changes are found before masking, so that a change is recorded even
+	// when the masked values are the same
+	changes, err := diffSnapshots(beforeJSON, afterJSON)
+	if err != nil {
+		return nil, fmt.Errorf("error computing audit changes: %v", err)
+	}
+	for key, change := range changes {
+		changes[key] = FieldChange{Old: maskField(entityType, key, change.Old), New: maskField(entityType, key, change.New)}
+	}
+	if beforeJSON, err = maskSnapshot(entityType, beforeJSON); err != nil {
+		return nil, fmt.Errorf("error masking audit snapshot: %v", err)
+	}
+	if afterJSON, err = maskSnapshot(entityType, afterJSON); err != nil {
+		return nil, fmt.Errorf("error masking audit snapshot: %v", err)
+	}
+
+	return &AuditLog{
+		EntityType:    entityType,
+		EntityID:      entityID,
+		Action:        action,
+		ActorID:       actor.ID,
+		ActorUsername: actor.Username,
+		Before:        beforeJSON,
+		After:         afterJSON,
+		Changes:       changes,
+		subject:       after,
+	}, nil
+}
+
+// snapshotJSON marshals an entity snapshot, returning nil for nil values
+func snapshotJSON(v interface{}) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if string(data) == "null" {
+		return nil, nil
+	}
+	return data, nil
+}
+
+// auditMasks returns the fields masked in snapshots of an entity type
+func auditMasks(entityType string) map[string]func(string) string {
+	if entityType == AuditEntityApplicant {
+		return auditPersonFields
+	}
+	return auditMaskedFields
+}
+
+// maskSnapshot masks the sensitive fields of a JSON object snapshot of an
+// entity of the given type
+func maskSnapshot(entityType string, data json.RawMessage) (json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if data == nil || json.Unmarshal(data, &fields) != nil {
+		return data, nil // Not an object, so there is nothing to mask
+	}
+
+	masked := false
+	for key, raw := range fields {
+		if !maskedField(entityType, key) {
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, err
+		}
+		masking, err := json.Marshal(maskField(entityType, key, value))
+		if err != nil {
+			return nil, err
+		}
+		fields[key] = masking
+		masked = true
+	}
+	if !masked {
+		return data, nil
+	}
+	return json.Marshal(fields)
+}
+
+// maskedField reports whether a top-level field of a snapshot of an entity
+// of the given type is masked, or holds household members whose fields are
+func maskedField(entityType, key string) bool {
+	if entityType == AuditEntityApplicant && key == "household" {
+		return true
+	}
+	_, ok := auditMasks(entityType)[key]
+	return ok
+}
+
+// maskField masks the decoded value of a top-level snapshot field. Strings
+// are masked, other values kept as they are; the household of an applicant
+// has its members' personal fields masked.
+func maskField(entityType, key string, value interface{}) interface{} {
+	if entityType == AuditEntityApplicant && key == "household" {
+		members, _ := value.([]interface{})
+		for _, item := range members {
+			member, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for field, mask := range auditPersonFields {
+				if value, ok := member[field]; ok {
+					member[field] = maskString(mask, value)
+				}
+			}
+		}
+		return value
+	}
+
+	mask, ok := auditMasks(entityType)[key]
+	if !ok {
+		return value
+	}
+	return maskString(mask, value)
+}
+
+// maskString applies mask to a non-empty string value
+func maskString(mask func(string) string, value interface{}) interface{} {
+	s, ok := value.(string)
+	if !ok || s == "" {
+		return value
+	}
+	return mask(s)
+}
+
+// diffSnapshots compares the top-level fields of two JSON objects
+func diffSnapshots(before, after json.RawMessage) (map[string]FieldChange, error) {
+	oldFields := map[string]interface{}{}
+	newFields := map[string]interface{}{}
+	if before != nil {
+		if err := json.Unmarshal(before, &oldFields); err != nil {
+			return nil, err
+		}
+	}
+	if after != nil {
+		if err := json.Unmarshal(after, &newFields); err != nil {
+			return nil, err
+		}
+	}
+
+	changes := map[string]FieldChange{}
+	for key, oldValue := range oldFields {
+		if auditIgnoredFields[key] {
+			continue
+		}
+		if newValue, ok := newFields[key]; !ok || !reflect.DeepEqual(oldValue, newValue) {
+			changes[key] = FieldChange{Old: oldValue, New: newFields[key]}
+		}
+	}
+	for key, newValue := range newFields {
+		if _, ok := oldFields[key]; !ok && !auditIgnoredFields[key] {
+			changes[key] = FieldChange{Old: nil, New: newValue}
+		}
+	}
+
+	return changes, nil
+}
+
+// Create inserts a new audit entry, the application event of entries about
+// applications and, if ExportChanges is set, its change event
+func (r *AuditRepository) Create(l *AuditLog) error {
+	// Generate UUID if not provided
+	if l.ID == "" {
+		l.ID = uuid.New().String()
+	}
+	l.CreatedAt = time.Now()
+
+	changesJSON, err := json.Marshal(l.Changes)
+	if err != nil {
+		return fmt.Errorf("error marshaling audit changes: %v", err)
+	}
+
+	query := `INSERT INTO audit_logs (id, entity_type, entity_id, action, actor_id, actor_username, before_data, after_data, changes, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err = r.conn().Exec(query, l.ID, l.EntityType, l.EntityID, l.Action,
+		nullString(l.ActorID), nullString(l.ActorUsername), nullJSON(l.Before), nullJSON(l.After),
+		changesJSON, l.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating audit log: %v", err)
+	}
+
+	if l.EntityType == AuditEntityApplication {
+		if err := createApplicationEvent(r.conn(), l); err != nil {
+			return err
+		}
+	}
+	if r.ExportChanges {
+		return createChangeEvent(r.conn(), l, &ConsentRepository{DB: r.DB, tx: r.tx})
+	}
+	return nil
+}
+
+// Find retrieves audit entries matching the filter, newest first
+func (r *AuditRepository) Find(filter AuditFilter) ([]AuditLog, error) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.EntityType != "" {
+		conditions = append(conditions, "entity_type = ?")
+		args = append(args, filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		conditions = append(conditions, "entity_id = ?")
+		args = append(args, filter.EntityID)
+	}
+	if filter.Action != "" {
+		conditions = append(conditions, "action = ?")
+		args = append(args, filter.Action)
+	}
+	if filter.Actor != "" {
+		conditions = append(conditions, "(actor_id = ? OR actor_username = ?)")
+		args = append(args, filter.Actor, filter.Actor)
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.From)
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, filter.To)
+	}
+
+	query := `SELECT id, entity_type, entity_id, action, actor_id, actor_username, before_data, after_data, changes, created_at
+			  FROM audit_logs`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at DESC"
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := r.conn().Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying audit logs: %v", err)
+	}
+	defer rows.Close()
+
+	var logs []AuditLog
+	for rows.Next() {
+		var l AuditLog
+		var actorID, actorUsername sql.NullString
+		var before, after, changes []byte
+
+		if err := rows.Scan(&l.ID, &l.EntityType, &l.EntityID, &l.Action, &actorID, &actorUsername,
+			&before, &after, &changes, &l.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning audit log row: %v", err)
+		}
+
+		l.ActorID = actorID.String
+		l.ActorUsername = actorUsername.String
+		if before != nil {
+			l.Before = json.RawMessage(before)
+		}
+		if after != nil {
+			l.After = json.RawMessage(after)
+		}
+		if changes != nil {
+			if err := json.Unmarshal(changes, &l.Changes); err != nil {
+				return nil, fmt.Errorf("error unmarshaling audit changes: %v", err)
+			}
+		}
+
+		logs = append(logs, l)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating audit log rows: %v", err)
+	}
+
+	return logs, nil
+}
+
+// History returns the field-level changes recorded for an entity, oldest
+// first, optionally only those to field and within filter.From and filter.To.
+// Deletions are listed without changes, since every field would appear to be
+// removed.
+func (r *AuditRepository) History(entityType, entityID, field string, filter AuditFilter) ([]HistoryEntry, error) {
+	filter.EntityType = entityType
+	filter.EntityID = entityID
+	logs, err := r.Find(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	history := []HistoryEntry{}
+	for i := len(logs) - 1; i >= 0; i-- {
+		l := logs[i]
+		changes := l.Changes
+		if l.After == nil || changes == nil {
+			changes = map[string]FieldChange{}
+		}
+		if field != "" {
+			change, ok := changes[field]
+			if !ok {
+				continue
+			}
+			changes = map[string]FieldChange{field: change}
+		}
+
+		history = append(history, HistoryEntry{
+			ChangedAt:     l.CreatedAt,
+			Action:        l.Action,
+			ActorID:       l.ActorID,
+			ActorUsername: l.ActorUsername,
+			Changes:       changes,
+			AuditID:       l.ID,
+		})
+	}
+
+	return history, nil
+}
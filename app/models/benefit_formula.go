@@ -0,0 +1,122 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"one-client-view-2025tht/app/money"
+)
+
+// BenefitFormula scales a benefit with the applicant's household. The amount
+// paid is the benefit's amount plus PerChildAmount for each child counted and
+// PerMemberAmount for each household member, capped at MaxAmount if set.
+type BenefitFormula struct {
+	PerChildAmount money.Amount `json:"per_child_amount,omitempty" swaggertype:"string" example:"100.00"`
+	// Children are counted only at these school levels, as derived for
+	// eligibility; every child is counted if empty
+	ChildSchoolLevels []string      `json:"child_school_levels,omitempty" example:"primary,secondary"`
+	PerMemberAmount   money.Amount  `json:"per_member_amount,omitempty" swaggertype:"string" example:"0.00"` // For each household member, not counting the applicant
+	MaxAmount         *money.Amount `json:"max_amount,omitempty" swaggertype:"string" example:"600.00"`
+}
+
+// BenefitEntitlement is the amount of a benefit an applicant is entitled to
+type BenefitEntitlement struct {
+	BenefitID        string       `json:"benefit_id"`
+	Name             string       `json:"name"`
+	BaseAmount       money.Amount `json:"base_amount" swaggertype:"string" example:"300.00"`
+	Children         int          `json:"children"`                                     // Children counted by the formula
+	HouseholdMembers int          `json:"household_members"`                            // Household members counted by the formula
+	Amount           money.Amount `json:"amount" swaggertype:"string" example:"500.00"` // Paid each time
+	Currency         string       `json:"currency" example:"SGD"`
+	Frequency        string       `json:"frequency" enums:"one_off,monthly,quarterly" example:"monthly"`
+	DurationMonths   *int         `json:"duration_months,omitempty" example:"12"`
+	ProjectedValue   money.Amount `json:"projected_value" swaggertype:"string" example:"6000.00"`
+}
+
+// BenefitEstimate is an applicant's estimated entitlement to a scheme's
+// benefits
+type BenefitEstimate struct {
+	ApplicantID    string               `json:"applicant_id"`
+	SchemeID       string               `json:"scheme_id"`
+	AsOf           time.Time            `json:"as_of"`
+	Eligible       bool                 `json:"eligible"` // Whether the applicant is eligible for the scheme at as_of
+	Benefits       []BenefitEntitlement `json:"benefits"`
+	ProjectedValue money.Amount         `json:"projected_value" swaggertype:"string" example:"6000.00"`
+}
+
+// Entitlement computes the amount of the benefit the applicant is entitled
+// to, counting their household at the given time
+func (b *Benefit) Entitlement(applicant *Applicant, at time.Time) BenefitEntitlement {
+	e := BenefitEntitlement{
+		BenefitID:      b.ID,
+		Name:           b.Name,
+		BaseAmount:     b.Amount,
+		Amount:         b.Amount,
+		Currency:       b.Currency,
+		Frequency:      b.Frequency,
+		DurationMonths: b.DurationMonths,
+	}
+
+	if f := b.Formula; f != nil {
+		for i := range applicant.Household {
+			m := &applicant.Household[i]
+			if isChild(m) && (len(f.ChildSchoolLevels) == 0 || slices.Contains(f.ChildSchoolLevels, schoolLevel(m, at))) {
+				e.Children++
+			}
+		}
+		e.HouseholdMembers = len(applicant.Household)
+
+		e.Amount += f.PerChildAmount*money.Amount(e.Children) + f.PerMemberAmount*money.Amount(e.HouseholdMembers)
+		if f.MaxAmount != nil && e.Amount > *f.MaxAmount {
+			e.Amount = *f.MaxAmount
+		}
+	}
+
+	e.ProjectedValue = e.Amount * money.Amount(b.Payments())
+	return e
+}
+
+// EstimateBenefits computes the applicant's entitlement to each of the
+// scheme's benefits at the given time
+func EstimateBenefits(scheme *Scheme, applicant *Applicant, eligible bool, at time.Time) BenefitEstimate {
+	estimate := BenefitEstimate{
+		ApplicantID: applicant.ID,
+		SchemeID:    scheme.ID,
+		AsOf:        at,
+		Eligible:    eligible,
+		Benefits:    []BenefitEntitlement{},
+	}
+	for i := range scheme.Benefits {
+		e := scheme.Benefits[i].Entitlement(applicant, at)
+		estimate.Benefits = append(estimate.Benefits, e)
+		estimate.ProjectedValue += e.ProjectedValue
+	}
+	return estimate
+}
+
+// formulaValue returns the JSON stored for a benefit's formula, or nil
+func formulaValue(f *BenefitFormula) (interface{}, error) {
+	if f == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(f)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling benefit formula: %v", err)
+	}
+	return string(data), nil
+}
+
+// scanFormula reads a benefit's stored formula
+func scanFormula(value sql.NullString) (*BenefitFormula, error) {
+	if !value.Valid || value.String == "" {
+		return nil, nil
+	}
+	var f BenefitFormula
+	if err := json.Unmarshal([]byte(value.String), &f); err != nil {
+		return nil, fmt.Errorf("error unmarshaling benefit formula: %v", err)
+	}
+	return &f, nil
+}
@@ -0,0 +1,137 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CaseNoteRepository handles database operations for case notes
+type CaseNoteRepository struct {
+	DB *sql.DB
+	tx *sql.Tx
+}
+
+// NewCaseNoteRepository creates a new repository with the given database connection
+func NewCaseNoteRepository(db *sql.DB) *CaseNoteRepository {
+	return &CaseNoteRepository{DB: db}
+}
+
+// WithTx returns a copy of the repository that runs its queries in tx
+func (r *CaseNoteRepository) WithTx(tx *sql.Tx) *CaseNoteRepository {
+	return &CaseNoteRepository{DB: r.DB, tx: tx}
+}
+
+// conn returns the transaction the repository is bound to, or the database
+func (r *CaseNoteRepository) conn() DBTX {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.DB
+}
+
+// NormalizeTags lowercases and trims tags, dropping blank and repeated ones
+func NormalizeTags(tags []string) []string {
+	normalized := []string{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
+
+// caseNoteColumns is the column list read by scanCaseNote
+const caseNoteColumns = `id, applicant_id, author_id, author_username, body, tags, created_at`
+
+// scanCaseNote scans a row selected with caseNoteColumns
+func scanCaseNote(row rowScanner) (CaseNote, error) {
+	var n CaseNote
+	var authorID, authorUsername sql.NullString
+	var tagsJSON []byte
+
+	if err := row.Scan(&n.ID, &n.ApplicantID, &authorID, &authorUsername, &n.Body,
+		&tagsJSON, &n.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return n, err
+		}
+		return n, fmt.Errorf("error scanning case note row: %v", err)
+	}
+	n.AuthorID = authorID.String
+	n.AuthorUsername = authorUsername.String
+
+	if err := json.Unmarshal(tagsJSON, &n.Tags); err != nil {
+		return n, fmt.Errorf("error unmarshaling case note tags: %v", err)
+	}
+
+	return n, nil
+}
+
+// GetByApplicantID retrieves the case notes of an applicant, oldest first.
+// If tag is not empty, only notes with that tag are returned.
+func (r *CaseNoteRepository) GetByApplicantID(applicantID, tag string) ([]CaseNote, error) {
+	query := `SELECT ` + caseNoteColumns + `
+			  FROM case_notes
+			  WHERE applicant_id = ?
+			  ORDER BY created_at ASC, id ASC`
+
+	rows, err := r.conn().Query(query, applicantID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying case notes: %v", err)
+	}
+	defer rows.Close()
+
+	// Tags are stored as JSON, which the two drivers query differently, so
+	// the tag is matched here
+	var notes []CaseNote
+	for rows.Next() {
+		n, err := scanCaseNote(rows)
+		if err != nil {
+			return nil, err
+		}
+		if tag == "" || slices.Contains(n.Tags, tag) {
+			notes = append(notes, n)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating case note rows: %v", err)
+	}
+
+	return notes, nil
+}
+
+// Create inserts a new case note. Tags are stored as given; a nil slice is
+// stored as an empty one.
+func (r *CaseNoteRepository) Create(n *CaseNote) error {
+	if n.ID == "" {
+		n.ID = uuid.New().String()
+	}
+	n.CreatedAt = time.Now()
+	if n.Tags == nil {
+		n.Tags = []string{}
+	}
+
+	tagsJSON, err := json.Marshal(n.Tags)
+	if err != nil {
+		return fmt.Errorf("error marshaling case note tags: %v", err)
+	}
+
+	query := `INSERT INTO case_notes (id, applicant_id, author_id, author_username, body, tags, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	_, err = r.conn().Exec(query, n.ID, n.ApplicantID, nullString(n.AuthorID), nullString(n.AuthorUsername),
+		n.Body, tagsJSON, n.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating case note: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,389 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"one-client-view-2025tht/app/date"
+	"one-client-view-2025tht/app/money"
+)
+
+// ChangeExportJob is the payload of a changes.export job
+type ChangeExportJob struct {
+	ScheduledAt time.Time `json:"scheduled_at"`
+}
+
+// ChangeExportResult is the result of a changes.export job
+type ChangeExportResult struct {
+	Events  int      `json:"events"`  // Events exported
+	Batches []string `json:"batches"` // Names of the files written, one per batch
+}
+
+// ChangeEventRepository handles database operations for the change_events
+// outbox, which holds changes until they are exported to the data warehouse
+type ChangeEventRepository struct {
+	DB *sql.DB
+}
+
+// NewChangeEventRepository creates a new repository with the given database connection
+func NewChangeEventRepository(db *sql.DB) *ChangeEventRepository {
+	return &ChangeEventRepository{DB: db}
+}
+
+// The data of change events, which hold only the fields the data warehouse
+// reports on. Identity numbers and free text such as notes and reasons are
+// left out, as are applicants' personal details unless they consent to data
+// sharing.
+
+// applicantChange is the data of a change to an applicant. The shareable
+// fields, from Name on, are only set if the applicant consents to data
+// sharing, and their contact details only as ShareableApplicant allows.
+type applicantChange struct {
+	ID               string     `json:"id"`
+	EmploymentStatus string     `json:"employment_status"`
+	Sex              string     `json:"sex"`
+	BirthYear        int        `json:"birth_year,omitempty"`
+	MaritalStatus    string     `json:"marital_status"`
+	MonthlyIncome    float64    `json:"monthly_income"`
+	PostalDistrict   string     `json:"postal_district,omitempty"`
+	HouseholdSize    int        `json:"household_size"` // Household members besides the applicant
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	DeletedAt        *time.Time `json:"deleted_at,omitempty"`
+	AnonymizedAt     *time.Time `json:"anonymized_at,omitempty"`
+
+	Name        string                  `json:"name,omitempty"`
+	DateOfBirth *date.Date              `json:"date_of_birth,omitempty"`
+	Email       string                  `json:"email,omitempty"`
+	Phone       string                  `json:"phone,omitempty"`
+	Address     *Address                `json:"address,omitempty"`
+	Household   []householdMemberChange `json:"household,omitempty"`
+}
+
+// householdMemberChange is a household member in the data of a change to an
+// applicant who consents to data sharing
+type householdMemberChange struct {
+	Name        string    `json:"name"`
+	Relation    string    `json:"relation"`
+	Sex         string    `json:"sex"`
+	DateOfBirth date.Date `json:"date_of_birth"`
+}
+
+// applicationChange is the data of a change to an application
+type applicationChange struct {
+	ID                       string        `json:"id"`
+	ApplicantID              string        `json:"applicant_id"`
+	SchemeID                 string        `json:"scheme_id"`
+	SchemeVersion            int           `json:"scheme_version,omitempty"`
+	Status                   string        `json:"status"`
+	ApplicationDate          date.Date     `json:"application_date"`
+	DecisionDate             *time.Time    `json:"decision_date,omitempty"`
+	DecidedBy                string        `json:"decided_by,omitempty"`
+	RecommendedBenefitAmount *money.Amount `json:"recommended_benefit_amount,omitempty"`
+	AssignedTo               string        `json:"assigned_to,omitempty"`
+	WithdrawnAt              *time.Time    `json:"withdrawn_at,omitempty"`
+	PreviousApplicationID    string        `json:"previous_application_id,omitempty"`
+	CreatedAt                time.Time     `json:"created_at"`
+	UpdatedAt                time.Time     `json:"updated_at"`
+	DeletedAt                *time.Time    `json:"deleted_at,omitempty"`
+}
+
+// schemeChange is the data of a change to a scheme
+type schemeChange struct {
+	ID              string        `json:"id"`
+	Name            string        `json:"name"`
+	Status          string        `json:"status"`
+	IsActive        bool          `json:"is_active"`
+	OpenDate        *time.Time    `json:"open_date,omitempty"`
+	CloseDate       *time.Time    `json:"close_date,omitempty"`
+	Version         int           `json:"version"`
+	MaxApplications *int          `json:"max_applications,omitempty"`
+	Budget          *money.Amount `json:"budget,omitempty"`
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+}
+
+// benefitChange is the data of a change to a scheme's benefit
+type benefitChange struct {
+	ID             string       `json:"id"`
+	SchemeID       string       `json:"scheme_id"`
+	Name           string       `json:"name"`
+	Amount         money.Amount `json:"amount"`
+	Currency       string       `json:"currency"`
+	Frequency      string       `json:"frequency"`
+	DurationMonths *int         `json:"duration_months,omitempty"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+}
+
+// householdChange is the data of a change to a shared household
+type householdChange struct {
+	ID              string    `json:"id"`
+	HeadApplicantID string    `json:"head_applicant_id"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// householdMembershipChange is the data of a change to an applicant's
+// membership of a household
+type householdMembershipChange struct {
+	ID          string     `json:"id"`
+	HouseholdID string     `json:"household_id"`
+	ApplicantID string     `json:"applicant_id"`
+	Relation    string     `json:"relation"`
+	StartDate   time.Time  `json:"start_date"`
+	EndDate     *time.Time `json:"end_date,omitempty"`
+}
+
+// consentChange is the data of a change to an applicant's consent
+type consentChange struct {
+	ID          string     `json:"id"`
+	ApplicantID string     `json:"applicant_id"`
+	Purpose     string     `json:"purpose"`
+	GrantedAt   time.Time  `json:"granted_at"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	WithdrawnAt *time.Time `json:"withdrawn_at,omitempty"`
+}
+
+// taskChange is the data of a change to a task
+type taskChange struct {
+	ID            string     `json:"id"`
+	ApplicantID   string     `json:"applicant_id"`
+	ApplicationID string     `json:"application_id,omitempty"`
+	Status        string     `json:"status"`
+	DueDate       *time.Time `json:"due_date,omitempty"`
+	AssignedTo    string     `json:"assigned_to,omitempty"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// referralChange is the data of a change to a referral
+type referralChange struct {
+	ID          string     `json:"id"`
+	ApplicantID string     `json:"applicant_id"`
+	ReferredTo  string     `json:"referred_to"`
+	Scheme      string     `json:"scheme,omitempty"`
+	Outcome     string     `json:"outcome"`
+	OutcomeAt   *time.Time `json:"outcome_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// changeEventData builds the data of the change event of an audit entry from
+// the entity after the change, reading applicants' consents from consents. It
+// is nil for deletes and for entities, such as comments and documents, whose
+// contents are not exported; the event then records only that the change was
+// made.
+func changeEventData(l *AuditLog, consents *ConsentRepository) (json.RawMessage, error) {
+	var data interface{}
+	switch e := l.subject.(type) {
+	case *Applicant:
+		given, err := consents.GetByApplicantID(e.ID)
+		if err != nil {
+			return nil, err
+		}
+		change := applicantChange{
+			ID:               e.ID,
+			EmploymentStatus: e.EmploymentStatus,
+			Sex:              e.Sex,
+			MaritalStatus:    e.MaritalStatus,
+			MonthlyIncome:    e.MonthlyIncome,
+			HouseholdSize:    len(e.Household),
+			CreatedAt:        e.CreatedAt,
+			UpdatedAt:        e.UpdatedAt,
+			DeletedAt:        e.DeletedAt,
+			AnonymizedAt:     e.AnonymizedAt,
+		}
+		if !e.DateOfBirth.IsZero() {
+			change.BirthYear = e.DateOfBirth.Year()
+		}
+		if e.Address != nil {
+			change.PostalDistrict = e.Address.PostalDistrict
+		}
+		if Consented(given, ConsentDataSharing, l.CreatedAt) {
+			shared := ShareableApplicant(*e, given, l.CreatedAt)
+			change.Name = shared.Name
+			if !shared.DateOfBirth.IsZero() {
+				change.DateOfBirth = &shared.DateOfBirth
+			}
+			change.Email = shared.Email
+			change.Phone = shared.Phone
+			if Consented(given, ConsentContactPost, l.CreatedAt) {
+				change.Address = shared.Address // Otherwise only its postal district is shared, as postal_district
+			}
+			for _, m := range shared.Household {
+				change.Household = append(change.Household, householdMemberChange{
+					Name:        m.Name,
+					Relation:    m.Relation,
+					Sex:         m.Sex,
+					DateOfBirth: m.DateOfBirth,
+				})
+			}
+		}
+		data = change
+	case *Application:
+		data = applicationChange{
+			ID:                       e.ID,
+			ApplicantID:              e.ApplicantID,
+			SchemeID:                 e.SchemeID,
+			SchemeVersion:            e.SchemeVersion,
+			Status:                   e.Status,
+			ApplicationDate:          e.ApplicationDate,
+			DecisionDate:             e.DecisionDate,
+			DecidedBy:                e.DecidedBy,
+			RecommendedBenefitAmount: e.RecommendedBenefitAmount,
+			AssignedTo:               e.AssignedTo,
+			WithdrawnAt:              e.WithdrawnAt,
+			PreviousApplicationID:    e.PreviousApplicationID,
+			CreatedAt:                e.CreatedAt,
+			UpdatedAt:                e.UpdatedAt,
+			DeletedAt:                e.DeletedAt,
+		}
+	case *Scheme:
+		data = schemeChange{
+			ID:              e.ID,
+			Name:            e.Name,
+			Status:          e.Status,
+			IsActive:        e.IsActive,
+			OpenDate:        e.OpenDate,
+			CloseDate:       e.CloseDate,
+			Version:         e.Version,
+			MaxApplications: e.MaxApplications,
+			Budget:          e.Budget,
+			CreatedAt:       e.CreatedAt,
+			UpdatedAt:       e.UpdatedAt,
+		}
+	case *Benefit:
+		data = benefitChange{
+			ID:             e.ID,
+			SchemeID:       e.SchemeID,
+			Name:           e.Name,
+			Amount:         e.Amount,
+			Currency:       e.Currency,
+			Frequency:      e.Frequency,
+			DurationMonths: e.DurationMonths,
+			CreatedAt:      e.CreatedAt,
+			UpdatedAt:      e.UpdatedAt,
+		}
+	case *Household:
+		data = householdChange{
+			ID:              e.ID,
+			HeadApplicantID: e.HeadApplicantID,
+			CreatedAt:       e.CreatedAt,
+			UpdatedAt:       e.UpdatedAt,
+		}
+	case *HouseholdMembership:
+		data = householdMembershipChange{
+			ID:          e.ID,
+			HouseholdID: e.HouseholdID,
+			ApplicantID: e.ApplicantID,
+			Relation:    e.Relation,
+			StartDate:   e.StartDate,
+			EndDate:     e.EndDate,
+		}
+	case *Consent:
+		data = consentChange{
+			ID:          e.ID,
+			ApplicantID: e.ApplicantID,
+			Purpose:     e.Purpose,
+			GrantedAt:   e.GrantedAt,
+			ExpiresAt:   e.ExpiresAt,
+			WithdrawnAt: e.WithdrawnAt,
+		}
+	case *Task:
+		data = taskChange{
+			ID:            e.ID,
+			ApplicantID:   e.ApplicantID,
+			ApplicationID: e.ApplicationID,
+			Status:        e.Status,
+			DueDate:       e.DueDate,
+			AssignedTo:    e.AssignedTo,
+			CompletedAt:   e.CompletedAt,
+			CreatedAt:     e.CreatedAt,
+			UpdatedAt:     e.UpdatedAt,
+		}
+	case *Referral:
+		data = referralChange{
+			ID:          e.ID,
+			ApplicantID: e.ApplicantID,
+			ReferredTo:  e.ReferredTo,
+			Scheme:      e.Scheme,
+			Outcome:     e.Outcome,
+			OutcomeAt:   e.OutcomeAt,
+			CreatedAt:   e.CreatedAt,
+			UpdatedAt:   e.UpdatedAt,
+		}
+	default:
+		return nil, nil
+	}
+	return json.Marshal(data)
+}
+
+// createChangeEvent adds the change recorded by an audit entry to the
+// outbox, on the connection or transaction the entry was stored with, which
+// consents is bound to as well
+func createChangeEvent(conn DBTX, l *AuditLog, consents *ConsentRepository) error {
+	data, err := changeEventData(l, consents)
+	if err != nil {
+		return fmt.Errorf("error building change event: %v", err)
+	}
+
+	query := `INSERT INTO change_events (id, entity_type, entity_id, action, actor_id, data, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	_, err = conn.Exec(query, l.ID, l.EntityType, l.EntityID, l.Action,
+		nullString(l.ActorID), nullJSON(data), l.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating change event: %v", err)
+	}
+	return nil
+}
+
+// Pending retrieves up to limit events waiting to be exported, oldest first
+func (r *ChangeEventRepository) Pending(limit int) ([]ChangeEvent, error) {
+	query := `SELECT sequence, id, entity_type, entity_id, action, actor_id, data, created_at
+			  FROM change_events
+			  ORDER BY sequence ASC
+			  LIMIT ?`
+
+	rows, err := r.DB.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error querying change events: %v", err)
+	}
+	defer rows.Close()
+
+	var events []ChangeEvent
+	for rows.Next() {
+		var e ChangeEvent
+		var actorID sql.NullString
+		var data []byte
+		if err := rows.Scan(&e.Sequence, &e.ID, &e.EntityType, &e.EntityID, &e.Action,
+			&actorID, &data, &e.OccurredAt); err != nil {
+			return nil, fmt.Errorf("error scanning change event row: %v", err)
+		}
+		e.ActorID = actorID.String
+		if len(data) > 0 {
+			e.Data = data
+		}
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating change event rows: %v", err)
+	}
+
+	return events, nil
+}
+
+// DeleteThrough removes the events up to and including the given sequence
+// number, once they have been exported
+func (r *ChangeEventRepository) DeleteThrough(sequence int64) error {
+	query := `DELETE FROM change_events WHERE sequence <= ?`
+	if _, err := r.DB.Exec(query, sequence); err != nil {
+		return fmt.Errorf("error deleting change events: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CommentRepository handles database operations for application comments
+type CommentRepository struct {
+	DB *sql.DB
+	tx *sql.Tx
+}
+
+// NewCommentRepository creates a new repository with the given database connection
+func NewCommentRepository(db *sql.DB) *CommentRepository {
+	return &CommentRepository{DB: db}
+}
+
+// WithTx returns a copy of the repository that runs its queries in tx
+func (r *CommentRepository) WithTx(tx *sql.Tx) *CommentRepository {
+	return &CommentRepository{DB: r.DB, tx: tx}
+}
+
+// conn returns the transaction the repository is bound to, or the database
+func (r *CommentRepository) conn() DBTX {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.DB
+}
+
+// mentionPattern matches an @ and a username, unless the @ follows a letter
+// or digit, as in an email address
+var mentionPattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9_@])@([A-Za-z0-9_.-]+)`)
+
+// ParseMentions returns the usernames mentioned in a comment, in order of
+// first mention. A trailing full stop or hyphen, as at the end of a sentence,
+// is not part of the username.
+func ParseMentions(body string) []string {
+	mentions := []string{}
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		username := strings.TrimRight(match[1], ".-")
+		if username != "" && !slices.Contains(mentions, username) {
+			mentions = append(mentions, username)
+		}
+	}
+	return mentions
+}
+
+// commentColumns is the column list read by scanComment
+const commentColumns = `id, application_id, parent_id, author_id, author_username, body, mentions,
+	created_at, edited_at, deleted_at`
+
+// scanComment scans a row selected with commentColumns
+func scanComment(row rowScanner) (Comment, error) {
+	var c Comment
+	var parentID, authorID, authorUsername sql.NullString
+	var mentionsJSON []byte
+	var editedAt, deletedAt sql.NullTime
+
+	if err := row.Scan(&c.ID, &c.ApplicationID, &parentID, &authorID, &authorUsername, &c.Body,
+		&mentionsJSON, &c.CreatedAt, &editedAt, &deletedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c, err
+		}
+		return c, fmt.Errorf("error scanning comment row: %v", err)
+	}
+	c.ParentID = parentID.String
+	c.AuthorID = authorID.String
+	c.AuthorUsername = authorUsername.String
+	if editedAt.Valid {
+		c.EditedAt = &editedAt.Time
+	}
+	if deletedAt.Valid {
+		c.DeletedAt = &deletedAt.Time
+	}
+
+	if err := json.Unmarshal(mentionsJSON, &c.Mentions); err != nil {
+		return c, fmt.Errorf("error unmarshaling comment mentions: %v", err)
+	}
+
+	return c, nil
+}
+
+// GetByApplicationID retrieves the comment threads of an application, oldest
+// first, each with its replies. Deleted comments are left out unless they
+// start a thread with replies.
+func (r *CommentRepository) GetByApplicationID(applicationID string) ([]Comment, error) {
+	query := `SELECT ` + commentColumns + `
+			  FROM application_comments
+			  WHERE application_id = ?
+			  ORDER BY created_at ASC, id ASC`
+
+	rows, err := r.conn().Query(query, applicationID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying comments: %v", err)
+	}
+	defer rows.Close()
+
+	var threads []Comment
+	replies := map[string][]Comment{}
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case c.ParentID == "":
+			threads = append(threads, c)
+		case c.DeletedAt == nil:
+			replies[c.ParentID] = append(replies[c.ParentID], c)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating comment rows: %v", err)
+	}
+
+	var comments []Comment
+	for _, c := range threads {
+		c.Replies = replies[c.ID]
+		if c.DeletedAt == nil || len(c.Replies) > 0 {
+			comments = append(comments, c)
+		}
+	}
+	return comments, nil
+}
+
+// GetByID retrieves a comment by ID, without its replies
+func (r *CommentRepository) GetByID(id string) (*Comment, error) {
+	query := `SELECT ` + commentColumns + ` FROM application_comments WHERE id = ?`
+
+	c, err := scanComment(r.conn().QueryRow(query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Create inserts a new comment. A nil Mentions slice is stored as an empty
+// one.
+func (r *CommentRepository) Create(c *Comment) error {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	c.CreatedAt = time.Now()
+	if c.Mentions == nil {
+		c.Mentions = []string{}
+	}
+
+	mentionsJSON, err := json.Marshal(c.Mentions)
+	if err != nil {
+		return fmt.Errorf("error marshaling comment mentions: %v", err)
+	}
+
+	query := `INSERT INTO application_comments (id, application_id, parent_id, author_id, author_username, body, mentions, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err = r.conn().Exec(query, c.ID, c.ApplicationID, nullString(c.ParentID), nullString(c.AuthorID),
+		nullString(c.AuthorUsername), c.Body, mentionsJSON, c.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating comment: %v", err)
+	}
+
+	return nil
+}
+
+// Edit replaces the text and mentions of a comment, recording when
+func (r *CommentRepository) Edit(c *Comment, body string, mentions []string) error {
+	now := time.Now()
+	c.Body = body
+	c.Mentions = mentions
+	c.EditedAt = &now
+
+	mentionsJSON, err := json.Marshal(c.Mentions)
+	if err != nil {
+		return fmt.Errorf("error marshaling comment mentions: %v", err)
+	}
+
+	query := `UPDATE application_comments SET body = ?, mentions = ?, edited_at = ? WHERE id = ?`
+	if _, err := r.conn().Exec(query, c.Body, mentionsJSON, now, c.ID); err != nil {
+		return fmt.Errorf("error editing comment: %v", err)
+	}
+	return nil
+}
+
+// Delete marks a comment deleted and removes its text and mentions, keeping
+// its place in the thread for its replies
+func (r *CommentRepository) Delete(c *Comment) error {
+	now := time.Now()
+	c.Body = ""
+	c.Mentions = []string{}
+	c.DeletedAt = &now
+
+	query := `UPDATE application_comments SET body = '', mentions = '[]', deleted_at = ? WHERE id = ?`
+	if _, err := r.conn().Exec(query, now, c.ID); err != nil {
+		return fmt.Errorf("error deleting comment: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Purposes an applicant can consent to
+const (
+	ConsentDataSharing        = "data_sharing"        // Sharing their data with partner agencies, including in exports
+	ConsentContactEmail       = "contact_email"       // Being contacted by email
+	ConsentContactPhone       = "contact_phone"       // Being contacted by phone
+	ConsentContactPost        = "contact_post"        // Being contacted by post at their address
+	ConsentIncomeVerification = "income_verification" // Having their income retrieved from government agencies
+)
+
+// ConsentPurposes lists the purposes an applicant can consent to
+var ConsentPurposes = []string{ConsentDataSharing, ConsentContactEmail, ConsentContactPhone, ConsentContactPost, ConsentIncomeVerification}
+
+// ActiveAt reports whether the consent is in force at t
+func (c Consent) ActiveAt(t time.Time) bool {
+	return !c.GrantedAt.After(t) &&
+		(c.WithdrawnAt == nil || c.WithdrawnAt.After(t)) &&
+		(c.ExpiresAt == nil || c.ExpiresAt.After(t))
+}
+
+// Consented reports whether one of consents for purpose is in force at t
+func Consented(consents []Consent, purpose string, t time.Time) bool {
+	return slices.ContainsFunc(consents, func(c Consent) bool {
+		return c.Purpose == purpose && c.ActiveAt(t)
+	})
+}
+
+// ShareableApplicant returns a copy of an applicant without the contact
+// details they have not consented to at t: the email without contact_email,
+// the phone without contact_phone, and the address apart from its postal
+// district without contact_post
+func ShareableApplicant(a Applicant, consents []Consent, t time.Time) Applicant {
+	if !Consented(consents, ConsentContactEmail, t) {
+		a.Email = ""
+	}
+	if !Consented(consents, ConsentContactPhone, t) {
+		a.Phone = ""
+	}
+	if a.Address != nil && !Consented(consents, ConsentContactPost, t) {
+		a.Address = &Address{PostalDistrict: a.Address.PostalDistrict}
+	}
+	return a
+}
+
+// ConsentRepository handles database operations for applicants' consents
+type ConsentRepository struct {
+	DB *sql.DB
+	tx *sql.Tx
+}
+
+// NewConsentRepository creates a new repository with the given database connection
+func NewConsentRepository(db *sql.DB) *ConsentRepository {
+	return &ConsentRepository{DB: db}
+}
+
+// WithTx returns a copy of the repository that runs its queries in tx
+func (r *ConsentRepository) WithTx(tx *sql.Tx) *ConsentRepository {
+	return &ConsentRepository{DB: r.DB, tx: tx}
+}
+
+// conn returns the transaction the repository is bound to, or the database
+func (r *ConsentRepository) conn() DBTX {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.DB
+}
+
+// consentColumns is the column list read by scanConsent
+const consentColumns = `id, applicant_id, purpose, reference, granted_at, expires_at, withdrawn_at,
+	recorded_by, updated_at`
+
+// scanConsent scans a row selected with consentColumns, setting Active as of
+// now
+func scanConsent(row rowScanner) (Consent, error) {
+	var c Consent
+	var reference, recordedBy sql.NullString
+	var expiresAt, withdrawnAt sql.NullTime
+
+	if err := row.Scan(&c.ID, &c.ApplicantID, &c.Purpose, &reference, &c.GrantedAt, &expiresAt,
+		&withdrawnAt, &recordedBy, &c.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c, err
+		}
+		return c, fmt.Errorf("error scanning consent row: %v", err)
+	}
+	c.Reference = reference.String
+	c.RecordedBy = recordedBy.String
+	if expiresAt.Valid {
+		c.ExpiresAt = &expiresAt.Time
+	}
+	if withdrawnAt.Valid {
+		c.WithdrawnAt = &withdrawnAt.Time
+	}
+	c.Active = c.ActiveAt(time.Now())
+
+	return c, nil
+}
+
+// GetByApplicantID retrieves an applicant's consents, in the order of
+// ConsentPurposes
+func (r *ConsentRepository) GetByApplicantID(applicantID string) ([]Consent, error) {
+	consents, err := r.GetByApplicantIDs([]string{applicantID})
+	if err != nil {
+		return nil, err
+	}
+	return consents[applicantID], nil
+}
+
+// GetByApplicantIDs retrieves the consents of several applicants, keyed by
+// applicant ID
+func (r *ConsentRepository) GetByApplicantIDs(applicantIDs []string) (map[string][]Consent, error) {
+	consents := map[string][]Consent{}
+	ids := uniqueIDs(applicantIDs)
+	if len(ids) == 0 {
+		return consents, nil
+	}
+
+	placeholders, args := inClause(ids)
+	query := `SELECT ` + consentColumns + `
+			  FROM consents
+			  WHERE applicant_id IN (` + placeholders + `)`
+
+	rows, err := r.conn().Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying consents: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		c, err := scanConsent(rows)
+		if err != nil {
+			return nil, err
+		}
+		consents[c.ApplicantID] = append(consents[c.ApplicantID], c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating consents: %v", err)
+	}
+
+	for _, list := range consents {
+		slices.SortFunc(list, func(a, b Consent) int {
+			return slices.Index(ConsentPurposes, a.Purpose) - slices.Index(ConsentPurposes, b.Purpose)
+		})
+	}
+	return consents, nil
+}
+
+// Get retrieves an applicant's consent to purpose, or nil if none was ever
+// recorded
+func (r *ConsentRepository) Get(applicantID, purpose string) (*Consent, error) {
+	query := `SELECT ` + consentColumns + `
+			  FROM consents
+			  WHERE applicant_id = ? AND purpose = ?`
+
+	c, err := scanConsent(r.conn().QueryRow(query, applicantID, purpose))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // No consent found
+		}
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// Put records consent, replacing any earlier consent of the applicant to
+// the same purpose, including a withdrawn one, and keeping its ID
+func (r *ConsentRepository) Put(c *Consent) error {
+	existing, err := r.Get(c.ApplicantID, c.Purpose)
+	if err != nil {
+		return err
+	}
+
+	c.UpdatedAt = time.Now()
+	c.WithdrawnAt = nil
+	c.Active = c.ActiveAt(c.UpdatedAt)
+
+	if existing != nil {
+		c.ID = existing.ID
+		query := `UPDATE consents
+				  SET reference = ?, granted_at = ?, expires_at = ?, withdrawn_at = NULL, recorded_by = ?, updated_at = ?
+				  WHERE id = ?`
+		if _, err := r.conn().Exec(query, nullString(c.Reference), c.GrantedAt, c.ExpiresAt,
+			nullString(c.RecordedBy), c.UpdatedAt, c.ID); err != nil {
+			return fmt.Errorf("error updating consent: %v", err)
+		}
+		return nil
+	}
+
+	c.ID = uuid.New().String()
+	query := `INSERT INTO consents (id, applicant_id, purpose, reference, granted_at, expires_at, recorded_by, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	if _, err := r.conn().Exec(query, c.ID, c.ApplicantID, c.Purpose, nullString(c.Reference), c.GrantedAt,
+		c.ExpiresAt, nullString(c.RecordedBy), c.UpdatedAt); err != nil {
+		return fmt.Errorf("error creating consent: %v", err)
+	}
+	return nil
+}
+
+// Withdraw records the withdrawal of a consent at the given time
+func (r *ConsentRepository) Withdraw(c *Consent, at time.Time, recordedBy string) error {
+	c.WithdrawnAt = &at
+	c.RecordedBy = recordedBy
+	c.UpdatedAt = time.Now()
+	c.Active = c.ActiveAt(c.UpdatedAt)
+
+	query := `UPDATE consents SET withdrawn_at = ?, recorded_by = ?, updated_at = ? WHERE id = ?`
+	if _, err := r.conn().Exec(query, at, nullString(recordedBy), c.UpdatedAt, c.ID); err != nil {
+		return fmt.Errorf("error withdrawing consent: %v", err)
+	}
+	return nil
+}
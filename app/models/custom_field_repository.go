@@ -0,0 +1,208 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// CustomFieldRepository handles database operations for the definitions of
+// applicants' custom fields
+type CustomFieldRepository struct {
+	DB *sql.DB
+	tx *sql.Tx
+}
+
+// NewCustomFieldRepository creates a new repository with the given database connection
+func NewCustomFieldRepository(db *sql.DB) *CustomFieldRepository {
+	return &CustomFieldRepository{DB: db}
+}
+
+// WithTx returns a copy of the repository that runs its queries in tx
+func (r *CustomFieldRepository) WithTx(tx *sql.Tx) *CustomFieldRepository {
+	return &CustomFieldRepository{DB: r.DB, tx: tx}
+}
+
+// conn returns the transaction the repository is bound to, or the database
+func (r *CustomFieldRepository) conn() DBTX {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.DB
+}
+
+// customFieldColumns is the column list read by scanCustomField
+const customFieldColumns = `field_key, label, type, required, options, min_value, max_value, max_length, created_at, updated_at`
+
+// scanCustomField scans a row selected with customFieldColumns
+func scanCustomField(row rowScanner) (CustomField, error) {
+	var f CustomField
+	var options []byte
+	var minValue, maxValue sql.NullFloat64
+	var maxLength sql.NullInt64
+
+	if err := row.Scan(&f.Key, &f.Label, &f.Type, &f.Required, &options, &minValue, &maxValue, &maxLength,
+		&f.CreatedAt, &f.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return f, err
+		}
+		return f, fmt.Errorf("error scanning custom field row: %v", err)
+	}
+	if options != nil {
+		if err := json.Unmarshal(options, &f.Options); err != nil {
+			return f, fmt.Errorf("error unmarshaling options of custom field %s: %v", f.Key, err)
+		}
+	}
+	if minValue.Valid {
+		f.Min = &minValue.Float64
+	}
+	if maxValue.Valid {
+		f.Max = &maxValue.Float64
+	}
+	if maxLength.Valid {
+		n := int(maxLength.Int64)
+		f.MaxLength = &n
+	}
+
+	return f, nil
+}
+
+// customFieldOptions returns the value of the options column of a field
+func customFieldOptions(f *CustomField) (interface{}, error) {
+	if len(f.Options) == 0 {
+		return nil, nil
+	}
+	options, err := json.Marshal(f.Options)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling custom field options: %v", err)
+	}
+	return options, nil
+}
+
+// GetAll retrieves every custom field definition, ordered by key
+func (r *CustomFieldRepository) GetAll() ([]CustomField, error) {
+	rows, err := r.conn().Query(`SELECT ` + customFieldColumns + ` FROM custom_field_definitions ORDER BY field_key`)
+	if err != nil {
+		return nil, fmt.Errorf("error querying custom fields: %v", err)
+	}
+	defer rows.Close()
+
+	var fields []CustomField
+	for rows.Next() {
+		f, err := scanCustomField(rows)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating custom field rows: %v", err)
+	}
+
+	return fields, nil
+}
+
+// GetByKey retrieves a custom field definition by key
+func (r *CustomFieldRepository) GetByKey(key string) (*CustomField, error) {
+	query := `SELECT ` + customFieldColumns + ` FROM custom_field_definitions WHERE field_key = ?`
+
+	f, err := scanCustomField(r.conn().QueryRow(query, key))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &f, nil
+}
+
+// Create inserts a new custom field definition
+func (r *CustomFieldRepository) Create(f *CustomField) error {
+	now := time.Now()
+	f.CreatedAt = now
+	f.UpdatedAt = now
+
+	options, err := customFieldOptions(f)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO custom_field_definitions (field_key, label, type, required, options, min_value, max_value,
+			  max_length, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err = r.conn().Exec(query, f.Key, f.Label, f.Type, f.Required, options, f.Min, f.Max, f.MaxLength,
+		f.CreatedAt, f.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating custom field: %v", err)
+	}
+
+	return nil
+}
+
+// Update saves every field of a custom field definition except its key, type
+// and when it was created
+func (r *CustomFieldRepository) Update(f *CustomField) error {
+	f.UpdatedAt = time.Now()
+
+	options, err := customFieldOptions(f)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE custom_field_definitions
+			  SET label = ?, required = ?, options = ?, min_value = ?, max_value = ?, max_length = ?, updated_at = ?
+			  WHERE field_key = ?`
+
+	_, err = r.conn().Exec(query, f.Label, f.Required, options, f.Min, f.Max, f.MaxLength, f.UpdatedAt, f.Key)
+	if err != nil {
+		return fmt.Errorf("error updating custom field: %v", err)
+	}
+
+	return nil
+}
+
+// Delete removes a custom field definition
+func (r *CustomFieldRepository) Delete(key string) error {
+	if _, err := r.conn().Exec(`DELETE FROM custom_field_definitions WHERE field_key = ?`, key); err != nil {
+		return fmt.Errorf("error deleting custom field: %v", err)
+	}
+	return nil
+}
+
+// CountInUse returns how many applicants, including deleted ones, have a
+// value for the custom field with the given key
+func (r *CustomFieldRepository) CountInUse(key string) (int, error) {
+	// The LIKE condition narrows the rows down portably, also matching values
+	// equal to the key, so the values are decoded to be sure
+	rows, err := r.conn().Query(`SELECT custom_fields FROM applicants WHERE custom_fields LIKE ? ESCAPE '!'`,
+		`%"`+escapeLike(key)+`"%`)
+	if err != nil {
+		return 0, fmt.Errorf("error querying custom field values: %v", err)
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return 0, fmt.Errorf("error scanning custom field values: %v", err)
+		}
+		var values CustomValues
+		if err := json.Unmarshal(data, &values); err != nil {
+			return 0, fmt.Errorf("error unmarshaling custom field values: %v", err)
+		}
+		if _, ok := values[key]; ok {
+			count++
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("error iterating custom field values: %v", err)
+	}
+
+	return count, nil
+}
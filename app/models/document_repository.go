@@ -0,0 +1,168 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DocumentRepository handles database operations for the metadata of
+// application documents. The files themselves are kept in a storage.Store.
+type DocumentRepository struct {
+	DB *sql.DB
+	tx *sql.Tx
+}
+
+// NewDocumentRepository creates a new repository with the given database connection
+func NewDocumentRepository(db *sql.DB) *DocumentRepository {
+	return &DocumentRepository{DB: db}
+}
+
+// WithTx returns a copy of the repository that runs its queries in tx
+func (r *DocumentRepository) WithTx(tx *sql.Tx) *DocumentRepository {
+	return &DocumentRepository{DB: r.DB, tx: tx}
+}
+
+// conn returns the transaction the repository is bound to, or the database
+func (r *DocumentRepository) conn() DBTX {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.DB
+}
+
+// documentColumns is the column list read by scanDocument
+const documentColumns = `id, application_id, filename, content_type, size, sha256, storage_key, uploaded_by, created_at`
+
+// scanDocument scans a row selected with documentColumns
+func scanDocument(row rowScanner) (Document, error) {
+	var d Document
+	var uploadedBy sql.NullString
+
+	if err := row.Scan(&d.ID, &d.ApplicationID, &d.Filename, &d.ContentType, &d.Size,
+		&d.SHA256, &d.StorageKey, &uploadedBy, &d.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return d, err
+		}
+		return d, fmt.Errorf("error scanning document row: %v", err)
+	}
+	d.UploadedBy = uploadedBy.String
+
+	return d, nil
+}
+
+// GetByApplicationID retrieves the documents attached to an application,
+// oldest first
+func (r *DocumentRepository) GetByApplicationID(applicationID string) ([]Document, error) {
+	query := `SELECT ` + documentColumns + `
+			  FROM documents
+			  WHERE application_id = ?
+			  ORDER BY created_at ASC`
+
+	rows, err := r.conn().Query(query, applicationID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying documents: %v", err)
+	}
+	defer rows.Close()
+
+	var documents []Document
+	for rows.Next() {
+		d, err := scanDocument(rows)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating document rows: %v", err)
+	}
+
+	return documents, nil
+}
+
+// GetByApplicationIDs retrieves the documents attached to each of the given
+// applications in one query, oldest first, keyed by application ID
+func (r *DocumentRepository) GetByApplicationIDs(applicationIDs []string) (map[string][]Document, error) {
+	documents := make(map[string][]Document)
+	applicationIDs = uniqueIDs(applicationIDs)
+	if len(applicationIDs) == 0 {
+		return documents, nil
+	}
+
+	placeholders, args := inClause(applicationIDs)
+	query := `SELECT ` + documentColumns + `
+			  FROM documents
+			  WHERE application_id IN (` + placeholders + `)
+			  ORDER BY created_at ASC`
+
+	rows, err := r.conn().Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying documents: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		d, err := scanDocument(rows)
+		if err != nil {
+			return nil, err
+		}
+		documents[d.ApplicationID] = append(documents[d.ApplicationID], d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating document rows: %v", err)
+	}
+
+	return documents, nil
+}
+
+// GetByID retrieves a document of an application by ID
+func (r *DocumentRepository) GetByID(applicationID, id string) (*Document, error) {
+	query := `SELECT ` + documentColumns + `
+			  FROM documents
+			  WHERE id = ? AND application_id = ?`
+
+	d, err := scanDocument(r.conn().QueryRow(query, id, applicationID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // No document found
+		}
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+// Create inserts the metadata of a stored document. The ID, which the
+// storage key is usually derived from, is generated if not provided.
+func (r *DocumentRepository) Create(d *Document) error {
+	if d.ID == "" {
+		d.ID = uuid.New().String()
+	}
+	d.CreatedAt = time.Now()
+
+	query := `INSERT INTO documents (id, application_id, filename, content_type, size, sha256, storage_key, uploaded_by, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.conn().Exec(query, d.ID, d.ApplicationID, d.Filename, d.ContentType, d.Size,
+		d.SHA256, d.StorageKey, nullString(d.UploadedBy), d.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating document: %v", err)
+	}
+
+	return nil
+}
+
+// Delete removes the metadata of a document
+func (r *DocumentRepository) Delete(id string) error {
+	query := `DELETE FROM documents WHERE id = ?`
+	_, err := r.conn().Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("error deleting document: %v", err)
+	}
+	return nil
+}
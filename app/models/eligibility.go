@@ -0,0 +1,584 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Supported rule operators
+const (
+	OpEq      = "eq"
+	OpIn      = "in"
+	OpGte     = "gte"
+	OpLte     = "lte"
+	OpBetween = "between"
+)
+
+// Rule is a node in an eligibility rule tree. A rule is exactly one of:
+// a boolean group (All/Any), a household predicate, or a comparison of a
+// field against a value. An empty rule matches everything.
+type Rule struct {
+	All       []Rule         `json:"all,omitempty"`
+	Any       []Rule         `json:"any,omitempty"`
+	Household *HouseholdRule `json:"household,omitempty"`
+	Field     string         `json:"field,omitempty"`
+	Op        string         `json:"op,omitempty"`
+	Value     interface{}    `json:"value,omitempty"`
+}
+
+// HouseholdRule matches when the number of household members satisfying
+// Where is at least MinCount (default 1) and, if set, at most MaxCount.
+type HouseholdRule struct {
+	Where    Rule `json:"where"`
+	MinCount *int `json:"min_count,omitempty"`
+	MaxCount *int `json:"max_count,omitempty"`
+}
+
+// CustomFieldPrefix starts the names of rule fields comparing an applicant's
+// custom field, e.g. "custom.housing_type". Applicants without a value for the
+// field never match the comparison.
+const CustomFieldPrefix = "custom."
+
+// ApplicantField resolves a named attribute of an applicant for rule evaluation
+type ApplicantField func(a *Applicant, now time.Time) interface{}
+
+// MemberField resolves a named attribute of a household member for rule evaluation
+type MemberField func(m *HouseholdMember, now time.Time) interface{}
+
+// Operator compares a resolved field value against the value given in a rule
+type Operator func(actual, expected interface{}) (bool, error)
+
+var applicantFields = map[string]ApplicantField{
+	"employment_status": func(a *Applicant, _ time.Time) interface{} { return a.EmploymentStatus },
+	"marital_status":    func(a *Applicant, _ time.Time) interface{} { return a.MaritalStatus },
+	"sex":               func(a *Applicant, _ time.Time) interface{} { return a.Sex },
+	"age":               func(a *Applicant, now time.Time) interface{} { return ageOn(a.DateOfBirth.Time, now) },
+	"household_size":    func(a *Applicant, _ time.Time) interface{} { return len(a.Household) },
+	"monthly_income":    func(a *Applicant, _ time.Time) interface{} { return a.MonthlyIncome },
+	"household_income":  func(a *Applicant, _ time.Time) interface{} { return a.HouseholdIncome() },
+	"per_capita_income": func(a *Applicant, _ time.Time) interface{} { return a.PerCapitaIncome() },
+}
+
+var memberFields = map[string]MemberField{
+	"relation":          func(m *HouseholdMember, _ time.Time) interface{} { return m.Relation },
+	"employment_status": func(m *HouseholdMember, _ time.Time) interface{} { return m.EmploymentStatus },
+	"sex":               func(m *HouseholdMember, _ time.Time) interface{} { return m.Sex },
+	"age":               func(m *HouseholdMember, now time.Time) interface{} { return ageOn(m.DateOfBirth.Time, now) },
+	"is_child":          func(m *HouseholdMember, _ time.Time) interface{} { return isChild(m) },
+	"school_level":      func(m *HouseholdMember, now time.Time) interface{} { return schoolLevel(m, now) },
+	"monthly_income":    func(m *HouseholdMember, _ time.Time) interface{} { return m.MonthlyIncome },
+}
+
+// memberFieldValues lists the values household member fields with a fixed
+// set of values can take, so rules comparing them with anything else are
+// rejected
+var memberFieldValues = map[string][]string{
+	"relation": Relations,
+}
+
+var operators = map[string]Operator{
+	OpEq:      opEq,
+	OpIn:      opIn,
+	OpGte:     opGte,
+	OpLte:     opLte,
+	OpBetween: opBetween,
+}
+
+// RegisterApplicantField makes a new applicant attribute available to rules.
+// It is not safe for concurrent use and should be called during initialization.
+func RegisterApplicantField(name string, fn ApplicantField) {
+	applicantFields[name] = fn
+}
+
+// RegisterMemberField makes a new household member attribute available to rules.
+// It is not safe for concurrent use and should be called during initialization.
+func RegisterMemberField(name string, fn MemberField) {
+	memberFields[name] = fn
+}
+
+// RegisterOperator makes a new comparison operator available to rules.
+// It is not safe for concurrent use and should be called during initialization.
+func RegisterOperator(name string, op Operator) {
+	operators[name] = op
+}
+
+// ruleScope is the subject a rule is evaluated against: either the applicant
+// (where household predicates are allowed) or a single household member.
+type ruleScope struct {
+	applicant *Applicant
+	member    *HouseholdMember
+	now       time.Time
+}
+
+func (s ruleScope) hasField(name string) bool {
+	if s.member != nil {
+		_, ok := memberFields[name]
+		return ok
+	}
+	_, ok := applicantFields[name]
+	return ok || strings.HasPrefix(name, CustomFieldPrefix)
+}
+
+// resolve returns the value of a field, or nil for a custom field the
+// applicant has no value for
+func (s ruleScope) resolve(name string) interface{} {
+	if s.member != nil {
+		return memberFields[name](s.member, s.now)
+	}
+	if key, ok := strings.CutPrefix(name, CustomFieldPrefix); ok {
+		return s.applicant.CustomFields[key]
+	}
+	return applicantFields[name](s.applicant, s.now)
+}
+
+// Validate checks that the rule tree is well-formed, using only known fields
+// and operators with correctly shaped values.
+func (r Rule) Validate() error {
+	return r.validate(false)
+}
+
+func (r Rule) validate(inHousehold bool) error {
+	forms := 0
+	if len(r.All) > 0 {
+		forms++
+	}
+	if len(r.Any) > 0 {
+		forms++
+	}
+	if r.Household != nil {
+		forms++
+	}
+	if r.Field != "" || r.Op != "" {
+		forms++
+	}
+	if forms > 1 {
+		return fmt.Errorf("rule must specify only one of all, any, household or field")
+	}
+
+	for i, child := range r.All {
+		if err := child.validate(inHousehold); err != nil {
+			return fmt.Errorf("all[%d]: %v", i, err)
+		}
+	}
+	for i, child := range r.Any {
+		if err := child.validate(inHousehold); err != nil {
+			return fmt.Errorf("any[%d]: %v", i, err)
+		}
+	}
+
+	if r.Household != nil {
+		if inHousehold {
+			return fmt.Errorf("household predicates cannot be nested")
+		}
+		h := r.Household
+		if h.MinCount != nil && *h.MinCount < 0 {
+			return fmt.Errorf("household: min_count cannot be negative")
+		}
+		if h.MaxCount != nil && *h.MaxCount < 0 {
+			return fmt.Errorf("household: max_count cannot be negative")
+		}
+		if h.MinCount != nil && h.MaxCount != nil && *h.MinCount > *h.MaxCount {
+			return fmt.Errorf("household: min_count cannot be greater than max_count")
+		}
+		if err := h.Where.validate(true); err != nil {
+			return fmt.Errorf("household: %v", err)
+		}
+	}
+
+	if r.Field != "" || r.Op != "" {
+		if inHousehold {
+			if _, ok := memberFields[r.Field]; !ok {
+				return fmt.Errorf("unknown household member field: %q", r.Field)
+			}
+		} else if _, ok := applicantFields[r.Field]; !ok && !validCustomFieldName(r.Field) {
+			return fmt.Errorf("unknown applicant field: %q", r.Field)
+		}
+		if _, ok := operators[r.Op]; !ok {
+			return fmt.Errorf("unknown operator: %q", r.Op)
+		}
+		if err := validateOperand(r.Op, r.Value); err != nil {
+			return fmt.Errorf("field %q: %v", r.Field, err)
+		}
+		if allowed, ok := memberFieldValues[r.Field]; ok && inHousehold {
+			if err := validateEnumOperand(r.Value, allowed); err != nil {
+				return fmt.Errorf("field %q: %v", r.Field, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validCustomFieldName reports whether a rule field names a custom field:
+// CustomFieldPrefix followed by a key
+func validCustomFieldName(name string) bool {
+	key, ok := strings.CutPrefix(name, CustomFieldPrefix)
+	return ok && CustomFieldKeyPattern.MatchString(key)
+}
+
+// CustomFields returns the keys of the custom fields the rule compares, in the
+// order they first appear
+func (r Rule) CustomFields() []string {
+	var keys []string
+	var collect func(r Rule)
+	collect = func(r Rule) {
+		if key, ok := strings.CutPrefix(r.Field, CustomFieldPrefix); ok && !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+		for _, child := range r.All {
+			collect(child)
+		}
+		for _, child := range r.Any {
+			collect(child)
+		}
+	}
+	collect(r)
+	return keys
+}
+
+// validateEnumOperand checks that a rule value, or each of a list of values,
+// is one of allowed
+func validateEnumOperand(value interface{}, allowed []string) error {
+	values, ok := value.([]interface{})
+	if !ok {
+		values = []interface{}{value}
+	}
+	for _, v := range values {
+		s, ok := v.(string)
+		if !ok || !slices.Contains(allowed, s) {
+			return fmt.Errorf("value %v must be one of: %s", v, strings.Join(allowed, ", "))
+		}
+	}
+	return nil
+}
+
+// validateOperand checks the value shape required by the built-in operators
+func validateOperand(op string, value interface{}) error {
+	switch op {
+	case OpEq:
+		if value == nil {
+			return fmt.Errorf("eq requires a value")
+		}
+	case OpIn:
+		if _, ok := value.([]interface{}); !ok {
+			return fmt.Errorf("in requires an array value")
+		}
+	case OpGte, OpLte:
+		if _, ok := toFloat(value); !ok {
+			return fmt.Errorf("%s requires a numeric value", op)
+		}
+	case OpBetween:
+		if _, _, ok := bounds(value); !ok {
+			return fmt.Errorf("between requires an array of two numbers")
+		}
+	}
+	return nil
+}
+
+// Matches reports whether the applicant satisfies the rule at the given time
+func (r Rule) Matches(applicant *Applicant, now time.Time) (bool, error) {
+	return r.evaluate(ruleScope{applicant: applicant, now: now})
+}
+
+func (r Rule) evaluate(s ruleScope) (bool, error) {
+	switch {
+	case len(r.All) > 0:
+		for _, child := range r.All {
+			ok, err := child.evaluate(s)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+
+	case len(r.Any) > 0:
+		for _, child := range r.Any {
+			ok, err := child.evaluate(s)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				return true, nil
+			}
+		}
+		return false, nil
+
+	case r.Household != nil:
+		if s.member != nil {
+			return false, fmt.Errorf("household predicates cannot be nested")
+		}
+		count := 0
+		for i := range s.applicant.Household {
+			memberScope := ruleScope{applicant: s.applicant, member: &s.applicant.Household[i], now: s.now}
+			ok, err := r.Household.Where.evaluate(memberScope)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				count++
+			}
+		}
+		minCount := 1
+		if r.Household.MinCount != nil {
+			minCount = *r.Household.MinCount
+		}
+		if count < minCount {
+			return false, nil
+		}
+		if r.Household.MaxCount != nil && count > *r.Household.MaxCount {
+			return false, nil
+		}
+		return true, nil
+
+	case r.Field != "":
+		if !s.hasField(r.Field) {
+			return false, fmt.Errorf("unknown field: %q", r.Field)
+		}
+		op, ok := operators[r.Op]
+		if !ok {
+			return false, fmt.Errorf("unknown operator: %q", r.Op)
+		}
+		value := s.resolve(r.Field)
+		if value == nil {
+			return false, nil
+		}
+		return op(value, r.Value)
+	}
+
+	return true, nil
+}
+
+// Rule returns the criteria as a single rule tree, combining the flat
+// criteria fields with any custom rules and alternative groups
+func (c Criteria) Rule() Rule {
+	var all []Rule
+
+	if c.EmploymentStatus != "" {
+		all = append(all, Rule{Field: "employment_status", Op: OpEq, Value: c.EmploymentStatus})
+	}
+	if c.MaritalStatus != "" {
+		all = append(all, Rule{Field: "marital_status", Op: OpEq, Value: c.MaritalStatus})
+	}
+	if c.MinAge != nil {
+		all = append(all, Rule{Field: "age", Op: OpGte, Value: *c.MinAge})
+	}
+	if c.MaxAge != nil {
+		all = append(all, Rule{Field: "age", Op: OpLte, Value: *c.MaxAge})
+	}
+	if c.HasChildren.SchoolLevel != "" {
+		all = append(all, Rule{Household: &HouseholdRule{
+			Where: Rule{All: []Rule{
+				{Field: "is_child", Op: OpEq, Value: true},
+				{Field: "school_level", Op: OpEq, Value: c.HasChildren.SchoolLevel},
+			}},
+		}})
+	}
+	if c.MaxHouseholdIncome != nil {
+		all = append(all, Rule{Field: "household_income", Op: OpLte, Value: *c.MaxHouseholdIncome})
+	}
+	if c.MaxPerCapitaIncome != nil {
+		all = append(all, Rule{Field: "per_capita_income", Op: OpLte, Value: *c.MaxPerCapitaIncome})
+	}
+	if c.Rules != nil {
+		all = append(all, *c.Rules)
+	}
+	if len(c.AnyOf) > 0 {
+		groups := make([]Rule, len(c.AnyOf))
+		for i, group := range c.AnyOf {
+			groups[i] = group.Rule()
+		}
+		all = append(all, Rule{Any: groups})
+	}
+
+	return Rule{All: all}
+}
+
+// Validate checks that age and income limits are non-negative, that the age
+// range is not empty, that any custom rules in the criteria are well-formed
+// and that alternative groups are valid and not empty
+func (c Criteria) Validate() error {
+	if c.MinAge != nil && *c.MinAge < 0 {
+		return fmt.Errorf("min_age must not be negative")
+	}
+	if c.MaxAge != nil && *c.MaxAge < 0 {
+		return fmt.Errorf("max_age must not be negative")
+	}
+	if c.MinAge != nil && c.MaxAge != nil && *c.MinAge > *c.MaxAge {
+		return fmt.Errorf("min_age must not be greater than max_age")
+	}
+	if c.MaxHouseholdIncome != nil && *c.MaxHouseholdIncome < 0 {
+		return fmt.Errorf("max_household_income must not be negative")
+	}
+	if c.MaxPerCapitaIncome != nil && *c.MaxPerCapitaIncome < 0 {
+		return fmt.Errorf("max_per_capita_income must not be negative")
+	}
+	if c.Rules != nil {
+		if err := c.Rules.Validate(); err != nil {
+			return fmt.Errorf("rules: %v", err)
+		}
+	}
+	for i, group := range c.AnyOf {
+		// An empty group matches everyone, making the others pointless
+		if len(group.Rule().All) == 0 {
+			return fmt.Errorf("any_of[%d] must not be empty", i)
+		}
+		if err := group.Validate(); err != nil {
+			return fmt.Errorf("any_of[%d]: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// isEligible checks if an applicant meets a scheme's criteria at the given time
+func isEligible(applicant *Applicant, criteria Criteria, at time.Time) bool {
+	ok, err := criteria.Rule().Matches(applicant, at)
+	if err != nil {
+		// Malformed criteria never grant eligibility
+		return false
+	}
+	return ok
+}
+
+// HouseholdIncome returns the combined monthly income of the applicant and
+// their household members
+func (a *Applicant) HouseholdIncome() float64 {
+	total := a.MonthlyIncome
+	for _, m := range a.Household {
+		total += m.MonthlyIncome
+	}
+	return total
+}
+
+// PerCapitaIncome returns the household income divided by the number of
+// people in the household, counting the applicant
+func (a *Applicant) PerCapitaIncome() float64 {
+	return a.HouseholdIncome() / float64(len(a.Household)+1)
+}
+
+// ageOn returns the age in whole years on the given date, which only
+// increases on the anniversary of the date of birth. Those born on 29
+// February turn a year older on 1 March in common years.
+func ageOn(dob, now time.Time) int {
+	age := now.Year() - dob.Year()
+	if now.Month() < dob.Month() || (now.Month() == dob.Month() && now.Day() < dob.Day()) {
+		age--
+	}
+	return age
+}
+
+// isChild reports whether the household member is a child of the applicant
+func isChild(m *HouseholdMember) bool {
+	return m.Relation == RelationSon || m.Relation == RelationDaughter
+}
+
+// schoolLevel returns the school level a household member was declared to be
+// at, or, if none was declared, derives it from their age
+func schoolLevel(m *HouseholdMember, now time.Time) string {
+	if m.SchoolLevel != "" {
+		return m.SchoolLevel
+	}
+	// Primary school is roughly 6-12 years
+	age := ageOn(m.DateOfBirth.Time, now)
+	if age >= 6 && age <= 12 {
+		return "primary"
+	}
+	return ""
+}
+
+func opEq(actual, expected interface{}) (bool, error) {
+	if a, ok := actual.(string); ok {
+		e, ok := expected.(string)
+		return ok && strings.EqualFold(a, e), nil
+	}
+	if a, ok := actual.(bool); ok {
+		e, ok := expected.(bool)
+		return ok && a == e, nil
+	}
+	if a, ok := toFloat(actual); ok {
+		e, ok := toFloat(expected)
+		return ok && a == e, nil
+	}
+	return false, fmt.Errorf("unsupported value type %T", actual)
+}
+
+func opIn(actual, expected interface{}) (bool, error) {
+	values, ok := expected.([]interface{})
+	if !ok {
+		return false, fmt.Errorf("in requires an array value")
+	}
+	for _, v := range values {
+		match, err := opEq(actual, v)
+		if err != nil {
+			return false, err
+		}
+		if match {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func opGte(actual, expected interface{}) (bool, error) {
+	a, e, err := numericOperands(actual, expected)
+	return err == nil && a >= e, err
+}
+
+func opLte(actual, expected interface{}) (bool, error) {
+	a, e, err := numericOperands(actual, expected)
+	return err == nil && a <= e, err
+}
+
+func opBetween(actual, expected interface{}) (bool, error) {
+	a, ok := toFloat(actual)
+	if !ok {
+		return false, fmt.Errorf("between requires a numeric field, got %T", actual)
+	}
+	lo, hi, ok := bounds(expected)
+	if !ok {
+		return false, fmt.Errorf("between requires an array of two numbers")
+	}
+	return a >= lo && a <= hi, nil
+}
+
+func numericOperands(actual, expected interface{}) (float64, float64, error) {
+	a, ok := toFloat(actual)
+	if !ok {
+		return 0, 0, fmt.Errorf("numeric comparison requires a numeric field, got %T", actual)
+	}
+	e, ok := toFloat(expected)
+	if !ok {
+		return 0, 0, fmt.Errorf("numeric comparison requires a numeric value, got %T", expected)
+	}
+	return a, e, nil
+}
+
+// bounds extracts an inclusive [min, max] pair from a rule value
+func bounds(value interface{}) (float64, float64, bool) {
+	values, ok := value.([]interface{})
+	if !ok || len(values) != 2 {
+		return 0, 0, false
+	}
+	lo, ok1 := toFloat(values[0])
+	hi, ok2 := toFloat(values[1])
+	return lo, hi, ok1 && ok2
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,143 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// FeatureFlagRepository handles database operations for admins' settings of
+// feature flags
+type FeatureFlagRepository struct {
+	DB *sql.DB
+	tx *sql.Tx
+}
+
+// NewFeatureFlagRepository creates a new repository with the given database connection
+func NewFeatureFlagRepository(db *sql.DB) *FeatureFlagRepository {
+	return &FeatureFlagRepository{DB: db}
+}
+
+// WithTx returns a copy of the repository that runs its queries in tx
+func (r *FeatureFlagRepository) WithTx(tx *sql.Tx) *FeatureFlagRepository {
+	return &FeatureFlagRepository{DB: r.DB, tx: tx}
+}
+
+// conn returns the transaction the repository is bound to, or the database
+func (r *FeatureFlagRepository) conn() DBTX {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.DB
+}
+
+// featureFlagColumns is the column list read by scanFeatureFlag
+const featureFlagColumns = `name, enabled, roles, updated_by, created_at, updated_at`
+
+// scanFeatureFlag scans a row selected with featureFlagColumns
+func scanFeatureFlag(row rowScanner) (FeatureFlag, error) {
+	var f FeatureFlag
+	var roles []byte
+	var updatedBy sql.NullString
+
+	if err := row.Scan(&f.Name, &f.Enabled, &roles, &updatedBy, &f.CreatedAt, &f.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return f, err
+		}
+		return f, fmt.Errorf("error scanning feature flag row: %v", err)
+	}
+	if roles != nil {
+		if err := json.Unmarshal(roles, &f.Roles); err != nil {
+			return f, fmt.Errorf("error unmarshaling roles of feature flag %s: %v", f.Name, err)
+		}
+	}
+	f.UpdatedBy = updatedBy.String
+
+	return f, nil
+}
+
+// GetAll retrieves every feature flag setting, ordered by name
+func (r *FeatureFlagRepository) GetAll() ([]FeatureFlag, error) {
+	rows, err := r.conn().Query(`SELECT ` + featureFlagColumns + ` FROM feature_flags ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("error querying feature flags: %v", err)
+	}
+	defer rows.Close()
+
+	var flags []FeatureFlag
+	for rows.Next() {
+		f, err := scanFeatureFlag(rows)
+		if err != nil {
+			return nil, err
+		}
+		flags = append(flags, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating feature flag rows: %v", err)
+	}
+
+	return flags, nil
+}
+
+// GetByName retrieves the setting of a feature flag, or nil if it has none
+func (r *FeatureFlagRepository) GetByName(name string) (*FeatureFlag, error) {
+	query := `SELECT ` + featureFlagColumns + ` FROM feature_flags WHERE name = ?`
+
+	f, err := scanFeatureFlag(r.conn().QueryRow(query, name))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &f, nil
+}
+
+// Save creates or replaces the setting of a feature flag, keeping when it
+// was first created
+func (r *FeatureFlagRepository) Save(f *FeatureFlag) error {
+	existing, err := r.GetByName(f.Name)
+	if err != nil {
+		return err
+	}
+
+	var roles interface{}
+	if len(f.Roles) > 0 {
+		data, err := json.Marshal(f.Roles)
+		if err != nil {
+			return fmt.Errorf("error marshaling feature flag roles: %v", err)
+		}
+		roles = data
+	}
+	var updatedBy interface{}
+	if f.UpdatedBy != "" {
+		updatedBy = f.UpdatedBy
+	}
+
+	f.UpdatedAt = time.Now()
+	if existing == nil {
+		f.CreatedAt = f.UpdatedAt
+		_, err = r.conn().Exec(`INSERT INTO feature_flags (name, enabled, roles, updated_by, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?)`, f.Name, f.Enabled, roles, updatedBy, f.CreatedAt, f.UpdatedAt)
+	} else {
+		f.CreatedAt = existing.CreatedAt
+		_, err = r.conn().Exec(`UPDATE feature_flags SET enabled = ?, roles = ?, updated_by = ?, updated_at = ?
+			WHERE name = ?`, f.Enabled, roles, updatedBy, f.UpdatedAt, f.Name)
+	}
+	if err != nil {
+		return fmt.Errorf("error saving feature flag: %v", err)
+	}
+
+	return nil
+}
+
+// Delete removes the setting of a feature flag, returning it to its default
+func (r *FeatureFlagRepository) Delete(name string) error {
+	if _, err := r.conn().Exec(`DELETE FROM feature_flags WHERE name = ?`, name); err != nil {
+		return fmt.Errorf("error deleting feature flag: %v", err)
+	}
+	return nil
+}
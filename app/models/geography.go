@@ -0,0 +1,118 @@
+package models
+
+// Planning regions that postal districts are grouped into
+const (
+	RegionCentral   = "central"
+	RegionEast      = "east"
+	RegionNorth     = "north"
+	RegionNorthEast = "north_east"
+	RegionWest      = "west"
+)
+
+// Region is a planning region of Singapore
+type Region struct {
+	Code string
+	Name string
+}
+
+// Regions lists the planning regions in the order reports list them
+var Regions = []Region{
+	{RegionCentral, "Central"},
+	{RegionEast, "East"},
+	{RegionNorth, "North"},
+	{RegionNorthEast, "North-East"},
+	{RegionWest, "West"},
+}
+
+// District describes a postal district: its general locations, the planning
+// region covering most of it, and an approximate centre for plotting
+type District struct {
+	Code      string
+	Name      string
+	Region    string
+	Longitude float64
+	Latitude  float64
+}
+
+// Districts lists the postal districts in order of code
+var Districts = []District{
+	{"01", "Raffles Place, Cecil, Marina, People's Park", RegionCentral, 103.851, 1.284},
+	{"02", "Anson, Tanjong Pagar", RegionCentral, 103.843, 1.276},
+	{"03", "Queenstown, Tiong Bahru", RegionCentral, 103.817, 1.289},
+	{"04", "Telok Blangah, Harbourfront", RegionCentral, 103.820, 1.270},
+	{"05", "Pasir Panjang, Hong Leong Garden, Clementi New Town", RegionWest, 103.772, 1.297},
+	{"06", "High Street, Beach Road", RegionCentral, 103.853, 1.293},
+	{"07", "Middle Road, Golden Mile", RegionCentral, 103.858, 1.301},
+	{"08", "Little India", RegionCentral, 103.852, 1.307},
+	{"09", "Orchard, Cairnhill, River Valley", RegionCentral, 103.832, 1.302},
+	{"10", "Ardmore, Bukit Timah, Holland Road, Tanglin", RegionCentral, 103.805, 1.318},
+	{"11", "Watten Estate, Novena, Thomson", RegionCentral, 103.838, 1.323},
+	{"12", "Balestier, Toa Payoh, Serangoon", RegionCentral, 103.850, 1.332},
+	{"13", "Macpherson, Braddell", RegionCentral, 103.880, 1.333},
+	{"14", "Geylang, Eunos", RegionCentral, 103.893, 1.318},
+	{"15", "Katong, Joo Chiat, Amber Road", RegionCentral, 103.905, 1.305},
+	{"16", "Bedok, Upper East Coast, Eastwood, Kew Drive", RegionEast, 103.930, 1.324},
+	{"17", "Loyang, Changi", RegionEast, 103.974, 1.364},
+	{"18", "Tampines, Pasir Ris", RegionEast, 103.945, 1.358},
+	{"19", "Serangoon Garden, Hougang, Punggol", RegionNorthEast, 103.898, 1.372},
+	{"20", "Bishan, Ang Mo Kio", RegionNorthEast, 103.846, 1.362},
+	{"21", "Upper Bukit Timah, Clementi Park, Ulu Pandan", RegionWest, 103.776, 1.336},
+	{"22", "Jurong", RegionWest, 103.720, 1.343},
+	{"23", "Hillview, Dairy Farm, Bukit Panjang, Choa Chu Kang", RegionWest, 103.762, 1.378},
+	{"24", "Lim Chu Kang, Tengah", RegionNorth, 103.717, 1.411},
+	{"25", "Kranji, Woodgrove", RegionNorth, 103.786, 1.437},
+	{"26", "Upper Thomson, Springleaf", RegionNorth, 103.818, 1.397},
+	{"27", "Yishun, Sembawang", RegionNorth, 103.833, 1.430},
+	{"28", "Seletar", RegionNorthEast, 103.870, 1.395},
+}
+
+// DistrictRegion returns the planning region of a postal district, or "" if
+// it is not one
+func DistrictRegion(code string) string {
+	for _, d := range Districts {
+		if d.Code == code {
+			return d.Region
+		}
+	}
+	return ""
+}
+
+// GeoJSONPoint is a GeoJSON point geometry
+type GeoJSONPoint struct {
+	Type        string     `json:"type" example:"Point"`
+	Coordinates [2]float64 `json:"coordinates"` // Longitude and latitude
+}
+
+// GeoJSONFeature is a GeoJSON feature locating an area of a geographic
+// report, with its counts as properties
+type GeoJSONFeature struct {
+	Type       string         `json:"type" example:"Feature"`
+	ID         string         `json:"id"`
+	Geometry   GeoJSONPoint   `json:"geometry"`
+	Properties GeographicArea `json:"properties"`
+}
+
+// GeoJSONFeatureCollection is a geographic report as a GeoJSON feature
+// collection (RFC 7946), for mapping tools
+type GeoJSONFeatureCollection struct {
+	Type      string           `json:"type" example:"FeatureCollection"`
+	Features  []GeoJSONFeature `json:"features"`
+	GroupBy   string           `json:"group_by" example:"district"`
+	Unlocated int              `json:"unlocated"`
+}
+
+// FeatureCollection returns the report as a GeoJSON feature collection with
+// a point feature at the approximate centre of each area
+func (r *GeographicReport) FeatureCollection() GeoJSONFeatureCollection {
+	collection := GeoJSONFeatureCollection{Type: "FeatureCollection", Features: []GeoJSONFeature{},
+		GroupBy: r.GroupBy, Unlocated: r.Unlocated}
+	for _, area := range r.Areas {
+		collection.Features = append(collection.Features, GeoJSONFeature{
+			Type:       "Feature",
+			ID:         area.Code,
+			Geometry:   GeoJSONPoint{Type: "Point", Coordinates: [2]float64{area.Longitude, area.Latitude}},
+			Properties: area,
+		})
+	}
+	return collection
+}
@@ -0,0 +1,611 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrHouseholdExists is returned when creating a household headed by an
+// applicant who already heads one
+var ErrHouseholdExists = errors.New("applicant already heads a household")
+
+// ErrMembershipOverlap is returned when an applicant would belong to more
+// than one household at a time, counting the one they head
+var ErrMembershipOverlap = errors.New("applicant already belongs to a household during this period")
+
+// HouseholdRepository handles database operations for shared households and
+// their memberships
+type HouseholdRepository struct {
+	DB            *sql.DB
+	ApplicantRepo *ApplicantRepository // Loads the applicants in households
+	tx            *sql.Tx
+}
+
+// NewHouseholdRepository creates a new repository with the given database
+// connection
+func NewHouseholdRepository(db *sql.DB, applicantRepo *ApplicantRepository) *HouseholdRepository {
+	return &HouseholdRepository{DB: db, ApplicantRepo: applicantRepo}
+}
+
+// WithTx returns a copy of the repository, including the applicant
+// repository it depends on, that runs its queries in tx
+func (r *HouseholdRepository) WithTx(tx *sql.Tx) *HouseholdRepository {
+	return &HouseholdRepository{DB: r.DB, ApplicantRepo: r.ApplicantRepo.WithTx(tx), tx: tx}
+}
+
+// conn returns the transaction the repository is bound to, or the database
+func (r *HouseholdRepository) conn() DBTX {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.DB
+}
+
+// householdColumns is the column list read by scanHousehold
+const householdColumns = `id, head_applicant_id, created_by, created_at, updated_at`
+
+// scanHousehold scans a row selected with householdColumns
+func scanHousehold(row rowScanner) (Household, error) {
+	var h Household
+	var createdBy sql.NullString
+
+	if err := row.Scan(&h.ID, &h.HeadApplicantID, &createdBy, &h.CreatedAt, &h.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return h, err
+		}
+		return h, fmt.Errorf("error scanning household row: %v", err)
+	}
+	h.CreatedBy = createdBy.String
+
+	return h, nil
+}
+
+// membershipColumns is the column list read by scanMembership
+const membershipColumns = `id, household_id, applicant_id, relation, start_date, end_date, created_at, updated_at`
+
+// scanMembership scans a row selected with membershipColumns
+func scanMembership(row rowScanner) (HouseholdMembership, error) {
+	var m HouseholdMembership
+	var endDate sql.NullTime
+
+	if err := row.Scan(&m.ID, &m.HouseholdID, &m.ApplicantID, &m.Relation, &m.StartDate, &endDate,
+		&m.CreatedAt, &m.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return m, err
+		}
+		return m, fmt.Errorf("error scanning household membership row: %v", err)
+	}
+	if endDate.Valid {
+		m.EndDate = &endDate.Time
+	}
+
+	return m, nil
+}
+
+// getHouseholds retrieves the households matching a condition on their
+// columns
+func (r *HouseholdRepository) getHouseholds(where string, args ...interface{}) ([]Household, error) {
+	rows, err := r.conn().Query(`SELECT `+householdColumns+` FROM households WHERE `+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying households: %v", err)
+	}
+	defer rows.Close()
+
+	var households []Household
+	for rows.Next() {
+		h, err := scanHousehold(rows)
+		if err != nil {
+			return nil, err
+		}
+		households = append(households, h)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating household rows: %v", err)
+	}
+
+	return households, nil
+}
+
+// getMemberships retrieves the memberships matching a condition on their
+// columns, ordered by start date
+func (r *HouseholdRepository) getMemberships(where string, args ...interface{}) ([]HouseholdMembership, error) {
+	rows, err := r.conn().Query(`SELECT `+membershipColumns+` FROM household_memberships WHERE `+where+`
+			  ORDER BY start_date, id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying household memberships: %v", err)
+	}
+	defer rows.Close()
+
+	var memberships []HouseholdMembership
+	for rows.Next() {
+		m, err := scanMembership(rows)
+		if err != nil {
+			return nil, err
+		}
+		memberships = append(memberships, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating household membership rows: %v", err)
+	}
+
+	return memberships, nil
+}
+
+// getHousehold retrieves the one household matching a condition, with its
+// memberships, or nil if there is none
+func (r *HouseholdRepository) getHousehold(where string, args ...interface{}) (*Household, error) {
+	households, err := r.getHouseholds(where, args...)
+	if err != nil || len(households) == 0 {
+		return nil, err
+	}
+	h := households[0]
+	if h.Memberships, err = r.getMemberships(`household_id = ?`, h.ID); err != nil {
+		return nil, err
+	}
+	if h.Memberships == nil {
+		h.Memberships = []HouseholdMembership{}
+	}
+	return &h, nil
+}
+
+// GetByID retrieves a household by ID with its memberships
+func (r *HouseholdRepository) GetByID(id string) (*Household, error) {
+	return r.getHousehold(`id = ?`, id)
+}
+
+// GetByHead retrieves the household the applicant heads, if any, with its
+// memberships
+func (r *HouseholdRepository) GetByHead(applicantID string) (*Household, error) {
+	return r.getHousehold(`head_applicant_id = ?`, applicantID)
+}
+
+// MembershipsOf retrieves every membership of an applicant, in any
+// household, ordered by start date
+func (r *HouseholdRepository) MembershipsOf(applicantID string) ([]HouseholdMembership, error) {
+	return r.getMemberships(`applicant_id = ?`, applicantID)
+}
+
+// GetMembership retrieves a membership by ID
+func (r *HouseholdRepository) GetMembership(id string) (*HouseholdMembership, error) {
+	query := `SELECT ` + membershipColumns + ` FROM household_memberships WHERE id = ?`
+
+	m, err := scanMembership(r.conn().QueryRow(query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Create inserts a new household headed by h.HeadApplicantID. It returns
+// ErrHouseholdExists if the applicant already heads one, and
+// ErrMembershipOverlap if they belong to another household now or later.
+func (r *HouseholdRepository) Create(h *Household) error {
+	if h.ID == "" {
+		h.ID = uuid.New().String()
+	}
+	now := time.Now()
+	h.CreatedAt = now
+	h.UpdatedAt = now
+	h.Memberships = []HouseholdMembership{}
+
+	return runInTx(r.DB, r.tx, func(tx *sql.Tx) error {
+		txRepo := r.WithTx(tx)
+		memberships, err := txRepo.MembershipsOf(h.HeadApplicantID)
+		if err != nil {
+			return err
+		}
+		for _, m := range memberships {
+			if m.EndDate == nil || m.EndDate.After(now) {
+				return ErrMembershipOverlap
+			}
+		}
+
+		_, err = tx.Exec(`INSERT INTO households (id, head_applicant_id, created_by, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?)`, h.ID, h.HeadApplicantID, nullString(h.CreatedBy), h.CreatedAt, h.UpdatedAt)
+		if isUniqueViolation(err) {
+			return ErrHouseholdExists
+		}
+		if err != nil {
+			return fmt.Errorf("error creating household: %v", err)
+		}
+		return nil
+	})
+}
+
+// Delete removes a household with its memberships. Its applicants are
+// assessed against their own household members again.
+func (r *HouseholdRepository) Delete(id string) error {
+	return runInTx(r.DB, r.tx, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM household_memberships WHERE household_id = ?`, id); err != nil {
+			return fmt.Errorf("error deleting household memberships: %v", err)
+		}
+		if _, err := tx.Exec(`DELETE FROM households WHERE id = ?`, id); err != nil {
+			return fmt.Errorf("error deleting household: %v", err)
+		}
+		return nil
+	})
+}
+
+// checkMembership returns ErrHouseholdExists if the membership's applicant
+// heads a household, and ErrMembershipOverlap if they have another membership
+// overlapping it
+func (r *HouseholdRepository) checkMembership(m *HouseholdMembership) error {
+	headed, err := r.getHouseholds(`head_applicant_id = ?`, m.ApplicantID)
+	if err != nil {
+		return err
+	}
+	if len(headed) > 0 {
+		return ErrHouseholdExists
+	}
+	memberships, err := r.MembershipsOf(m.ApplicantID)
+	if err != nil {
+		return err
+	}
+	for i := range memberships {
+		if memberships[i].ID != m.ID && memberships[i].Overlaps(m) {
+			return ErrMembershipOverlap
+		}
+	}
+	return nil
+}
+
+// CreateMembership adds an applicant to a household, returning
+// ErrHouseholdExists if they head one and ErrMembershipOverlap if they would
+// then belong to two at once
+func (r *HouseholdRepository) CreateMembership(m *HouseholdMembership) error {
+	if m.ID == "" {
+		m.ID = uuid.New().String()
+	}
+	now := time.Now()
+	m.CreatedAt = now
+	m.UpdatedAt = now
+
+	return runInTx(r.DB, r.tx, func(tx *sql.Tx) error {
+		if err := r.WithTx(tx).checkMembership(m); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`INSERT INTO household_memberships (id, household_id, applicant_id, relation, start_date, end_date, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			m.ID, m.HouseholdID, m.ApplicantID, m.Relation, m.StartDate, m.EndDate, m.CreatedAt, m.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("error creating household membership: %v", err)
+		}
+		return r.WithTx(tx).touch(m.HouseholdID, now)
+	})
+}
+
+// UpdateMembership saves a membership's relation and dates, returning
+// ErrMembershipOverlap if its applicant would then belong to two households
+// at once
+func (r *HouseholdRepository) UpdateMembership(m *HouseholdMembership) error {
+	now := time.Now()
+	m.UpdatedAt = now
+
+	return runInTx(r.DB, r.tx, func(tx *sql.Tx) error {
+		if err := r.WithTx(tx).checkMembership(m); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`UPDATE household_memberships SET relation = ?, start_date = ?, end_date = ?, updated_at = ?
+			  WHERE id = ?`, m.Relation, m.StartDate, m.EndDate, m.UpdatedAt, m.ID)
+		if err != nil {
+			return fmt.Errorf("error updating household membership: %v", err)
+		}
+		return r.WithTx(tx).touch(m.HouseholdID, now)
+	})
+}
+
+// DeleteMembership removes a membership recorded in error. Applicants who
+// leave a household should have its end date set instead, so they are still
+// assessed against it for the time they belonged to it.
+func (r *HouseholdRepository) DeleteMembership(m *HouseholdMembership) error {
+	return runInTx(r.DB, r.tx, func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM household_memberships WHERE id = ?`, m.ID); err != nil {
+			return fmt.Errorf("error deleting household membership: %v", err)
+		}
+		return r.WithTx(tx).touch(m.HouseholdID, time.Now())
+	})
+}
+
+// touch sets the update time of a household whose memberships have changed
+func (r *HouseholdRepository) touch(id string, now time.Time) error {
+	if _, err := r.conn().Exec(`UPDATE households SET updated_at = ? WHERE id = ?`, now, id); err != nil {
+		return fmt.Errorf("error updating household: %v", err)
+	}
+	return nil
+}
+
+// Shared returns the household an applicant is assessed against at t,
+// without changing the applicant
+func (r *HouseholdRepository) Shared(a *Applicant, t time.Time) (*SharedHousehold, error) {
+	copied := *a
+	householdIDs, err := r.resolve([]*Applicant{&copied}, t)
+	if err != nil {
+		return nil, err
+	}
+	shared := &SharedHousehold{ApplicantID: a.ID, HouseholdID: householdIDs[a.ID], AsOf: t, Household: copied.Household}
+	if shared.Household == nil {
+		shared.Household = []HouseholdMember{}
+	}
+	return shared, nil
+}
+
+// Resolve returns a copy of the applicant with the household they are
+// assessed against at t, which is the applicant itself if they belong to no
+// shared household then
+func (r *HouseholdRepository) Resolve(a *Applicant, t time.Time) (*Applicant, error) {
+	copied := *a
+	householdIDs, err := r.resolve([]*Applicant{&copied}, t)
+	if err != nil || householdIDs[a.ID] == "" {
+		return a, err
+	}
+	return &copied, nil
+}
+
+// ResolveAll returns copies of the applicants with the households they are
+// assessed against at t, using a fixed number of queries
+func (r *HouseholdRepository) ResolveAll(applicants []Applicant, t time.Time) ([]Applicant, error) {
+	copied := make([]Applicant, len(applicants))
+	copy(copied, applicants)
+	if err := r.ResolveEach(copied, t); err != nil {
+		return nil, err
+	}
+	return copied, nil
+}
+
+// ResolveEach replaces, in place, the household of each applicant belonging
+// to a shared household at t with that household, using a fixed number of
+// queries. The applicants must not be shared with other callers.
+func (r *HouseholdRepository) ResolveEach(applicants []Applicant, t time.Time) error {
+	pointers := make([]*Applicant, len(applicants))
+	for i := range applicants {
+		pointers[i] = &applicants[i]
+	}
+	_, err := r.resolve(pointers, t)
+	return err
+}
+
+// ResolveApplications replaces the applicant carried by each application with
+// a copy holding the household they are assessed against at t
+func (r *HouseholdRepository) ResolveApplications(applications []Application, t time.Time) error {
+	var pointers []*Applicant
+	for i := range applications {
+		if applications[i].Applicant != nil {
+			copied := *applications[i].Applicant
+			applications[i].Applicant = &copied
+			pointers = append(pointers, &copied)
+		}
+	}
+	_, err := r.resolve(pointers, t)
+	return err
+}
+
+// resolve sets the household of each applicant belonging to a shared
+// household at t, returning the ID of the household of each that does
+func (r *HouseholdRepository) resolve(applicants []*Applicant, t time.Time) (map[string]string, error) {
+	householdOf := map[string]string{}
+	ids := make([]string, 0, len(applicants))
+	for _, a := range applicants {
+		ids = append(ids, a.ID)
+	}
+	ids = uniqueIDs(ids)
+	if len(ids) == 0 {
+		return householdOf, nil
+	}
+
+	// The households the applicants head or belong to at t
+	placeholders, args := inClause(ids)
+	headed, err := r.getHouseholds(`head_applicant_id IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range headed {
+		householdOf[h.HeadApplicantID] = h.ID
+	}
+	memberships, err := r.getMemberships(`applicant_id IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return nil, err
+	}
+	relationOf := map[string]string{}
+	for i := range memberships {
+		m := &memberships[i]
+		if m.CurrentAt(t) && householdOf[m.ApplicantID] == "" {
+			householdOf[m.ApplicantID] = m.HouseholdID
+			relationOf[m.ApplicantID] = m.Relation
+		}
+	}
+	if len(householdOf) == 0 {
+		return householdOf, nil
+	}
+
+	// Their heads and the other applicants in them at t
+	householdIDs := make([]string, 0, len(householdOf))
+	for _, id := range householdOf {
+		householdIDs = append(householdIDs, id)
+	}
+	placeholders, args = inClause(uniqueIDs(householdIDs))
+	households, err := r.getHouseholds(`id IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return nil, err
+	}
+	all, err := r.getMemberships(`household_id IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return nil, err
+	}
+	headOf := map[string]string{}
+	applicantIDs := []string{}
+	for _, h := range households {
+		headOf[h.ID] = h.HeadApplicantID
+		applicantIDs = append(applicantIDs, h.HeadApplicantID)
+	}
+	current := map[string][]HouseholdMembership{}
+	for _, m := range all {
+		if m.CurrentAt(t) {
+			current[m.HouseholdID] = append(current[m.HouseholdID], m)
+			applicantIDs = append(applicantIDs, m.ApplicantID)
+		}
+	}
+	people, err := r.ApplicantRepo.GetByIDs(applicantIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range applicants {
+		householdID := householdOf[a.ID]
+		head := people[headOf[householdID]]
+		if householdID == "" || head == nil || head.DeletedAt != nil {
+			delete(householdOf, a.ID)
+			continue
+		}
+		members := householdMembers(householdID, head, current[householdID], people)
+		if a.ID == head.ID {
+			a.Household = members
+			continue
+		}
+		a.Household = sharedFrom(a, relationOf[a.ID], householdID, head, members)
+	}
+	return householdOf, nil
+}
+
+// householdMembers returns the members of a household as seen by its head:
+// the head's household members and the applicants belonging to it, each
+// counted once. Applicants the head also declared as members replace them,
+// as their own records are kept up to date.
+func householdMembers(householdID string, head *Applicant, memberships []HouseholdMembership, people map[string]*Applicant) []HouseholdMember {
+	var members []HouseholdMember
+	var joined []*Applicant
+	for _, m := range memberships {
+		if a := people[m.ApplicantID]; a != nil && a.DeletedAt == nil {
+			members = append(members, applicantAsMember(a, m.Relation, householdID, head.ID))
+			joined = append(joined, a)
+		}
+	}
+	for _, m := range head.Household {
+		duplicate := false
+		for _, a := range joined {
+			if samePerson(&m, a) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			m.HouseholdID = householdID
+			members = append(members, m)
+		}
+	}
+	sortHouseholdMembers(members)
+	return members
+}
+
+// sharedFrom returns the members of a household as seen by an applicant whose
+// relation to its head is via: the head, and the head's members other than
+// the applicant, with their relations to the applicant
+func sharedFrom(a *Applicant, via, householdID string, head *Applicant, members []HouseholdMember) []HouseholdMember {
+	shared := []HouseholdMember{applicantAsMember(head, inverseRelation(via, head.Sex), householdID, head.ID)}
+	for _, m := range members {
+		if m.MemberApplicantID == a.ID || samePerson(&m, a) {
+			continue
+		}
+		m.Relation = relationVia(via, m.Relation, m.Sex)
+		shared = append(shared, m)
+	}
+	sortHouseholdMembers(shared)
+	return shared
+}
+
+// applicantAsMember returns an applicant as a member of a shared household,
+// which like the head's own members belong to the head
+func applicantAsMember(a *Applicant, relation, householdID, headID string) HouseholdMember {
+	return HouseholdMember{
+		ApplicantID:       headID,
+		Name:              a.Name,
+		IdentityNumber:    a.IdentityNumber,
+		EmploymentStatus:  a.EmploymentStatus,
+		Sex:               a.Sex,
+		DateOfBirth:       a.DateOfBirth,
+		Relation:          relation,
+		MonthlyIncome:     a.MonthlyIncome,
+		CreatedAt:         a.CreatedAt,
+		UpdatedAt:         a.UpdatedAt,
+		HouseholdID:       householdID,
+		MemberApplicantID: a.ID,
+	}
+}
+
+// samePerson reports whether a household member is the given applicant: both
+// have the same identity number or, if either has none, the same name and
+// date of birth
+func samePerson(m *HouseholdMember, a *Applicant) bool {
+	if m.IdentityNumber != "" && a.IdentityNumber != "" {
+		return NormalizeIdentityNumber(m.IdentityNumber) == NormalizeIdentityNumber(a.IdentityNumber)
+	}
+	return strings.EqualFold(strings.TrimSpace(m.Name), strings.TrimSpace(a.Name)) &&
+		m.DateOfBirth.Format(storedDateLayout) == a.DateOfBirth.Format(storedDateLayout)
+}
+
+// childRelation returns the relation of a child of the given sex to a parent
+func childRelation(sex string) string {
+	switch sex {
+	case "male":
+		return RelationSon
+	case "female":
+		return RelationDaughter
+	}
+	return RelationOther
+}
+
+// inverseRelation returns the relation of the head of a household, of the
+// given sex, to someone whose relation to the head is relation
+func inverseRelation(relation, headSex string) string {
+	switch relation {
+	case RelationSpouse, RelationSibling:
+		return relation
+	case RelationSon, RelationDaughter:
+		return RelationParent
+	case RelationParent:
+		return childRelation(headSex)
+	}
+	return RelationOther
+}
+
+// relationVia returns the relation to an applicant, whose relation to the
+// head of their household is via, of a member of the given sex whose relation
+// to the head is relation. Relations without a counterpart in Relations, such
+// as in-laws and grandchildren, are other.
+func relationVia(via, relation, sex string) string {
+	switch via {
+	case RelationSpouse:
+		// The head's children are the spouse's, as stepchildren if not by birth
+		if relation == RelationSon || relation == RelationDaughter {
+			return relation
+		}
+	case RelationSon, RelationDaughter:
+		switch relation {
+		case RelationSpouse:
+			return RelationParent
+		case RelationSon, RelationDaughter:
+			return RelationSibling
+		}
+	case RelationParent:
+		switch relation {
+		case RelationParent:
+			return RelationSpouse
+		case RelationSibling:
+			return childRelation(sex)
+		}
+	case RelationSibling:
+		if relation == RelationParent || relation == RelationSibling {
+			return relation
+		}
+	}
+	return RelationOther
+}
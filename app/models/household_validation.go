@@ -0,0 +1,222 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+)
+
+// Checks of the household validation report
+const (
+	HouseholdCheckMultipleSpouses = "multiple_spouses" // More than one member is the applicant's spouse
+	HouseholdCheckChildAge        = "child_age"        // A son or daughter born when the applicant was implausibly young or old
+	HouseholdCheckParentAge       = "parent_age"       // A parent who was implausibly young or old when the applicant was born
+	HouseholdCheckDuplicateMember = "duplicate_member" // The same NRIC/FIN twice in the household, or a member with the applicant's own
+	HouseholdCheckOtherHousehold  = "other_household"  // A member who is also in another applicant's household, or is another applicant
+)
+
+// Bounds on the age of a parent when their child was born, in whole years.
+// Children born outside them are reported rather than rejected, as adoption
+// and errors in the declared dates both happen.
+const (
+	MinParentAge = 12
+	MaxParentAge = 65
+)
+
+// HouseholdIssue is an inconsistency found between the members of an
+// applicant's household, or between it and other households
+type HouseholdIssue struct {
+	Check            string   `json:"check" example:"multiple_spouses"`                                  // One of the HouseholdCheck constants
+	Members          []string `json:"members"`                                                           // Fields of the members involved, e.g. household[1]
+	MemberIDs        []string `json:"member_ids"`                                                        // IDs of the members involved
+	Message          string   `json:"message" example:"household[0] and household[2] are both a spouse"` // Human-readable description
+	OtherApplicantID string   `json:"other_applicant_id,omitempty"`                                      // For other_household, the applicant whose household or record the member is also in
+}
+
+// HouseholdValidation reports the issues found in an applicant's household
+type HouseholdValidation struct {
+	ApplicantID string           `json:"applicant_id"`
+	Valid       bool             `json:"valid"` // Whether no issues were found
+	Issues      []HouseholdIssue `json:"issues"`
+}
+
+// IdentityNumberMatch is another applicant, or a member of another
+// applicant's household, with a given identity number
+type IdentityNumberMatch struct {
+	IdentityNumber string
+	ApplicantID    string
+	MemberID       string // Empty when the applicant themselves has the number
+}
+
+// memberField returns the field name of the i-th household member
+func memberField(i int) string {
+	return "household[" + strconv.Itoa(i) + "]"
+}
+
+// HouseholdIssues checks the members of the applicant's household against
+// each other and the applicant: that at most one is a spouse, that children
+// and parents are of plausible ages, and that no NRIC/FIN appears twice.
+// Members in other households are checked with
+// ApplicantRepository.FindIdentityNumbers.
+func (a *Applicant) HouseholdIssues() []HouseholdIssue {
+	issues := []HouseholdIssue{}
+
+	var spouses []int
+	for i := range a.Household {
+		if a.Household[i].Relation == RelationSpouse {
+			spouses = append(spouses, i)
+		}
+	}
+	if len(spouses) > 1 {
+		issue := HouseholdIssue{Check: HouseholdCheckMultipleSpouses}
+		for _, i := range spouses {
+			issue.Members = append(issue.Members, memberField(i))
+			issue.MemberIDs = append(issue.MemberIDs, a.Household[i].ID)
+		}
+		issue.Message = strconv.Itoa(len(spouses)) + " members are a spouse of the applicant"
+		issues = append(issues, issue)
+	}
+
+	for i := range a.Household {
+		m := &a.Household[i]
+		if a.DateOfBirth.IsZero() || m.DateOfBirth.IsZero() {
+			continue
+		}
+		switch {
+		case isChild(m):
+			if age := ageOn(a.DateOfBirth.Time, m.DateOfBirth.Time); age < MinParentAge || age > MaxParentAge {
+				issues = append(issues, HouseholdIssue{Check: HouseholdCheckChildAge,
+					Members: []string{memberField(i)}, MemberIDs: []string{m.ID},
+					Message: fmt.Sprintf("%s was born when the applicant was %d, not between %d and %d",
+						memberField(i), age, MinParentAge, MaxParentAge)})
+			}
+		case m.Relation == RelationParent:
+			if age := ageOn(m.DateOfBirth.Time, a.DateOfBirth.Time); age < MinParentAge || age > MaxParentAge {
+				issues = append(issues, HouseholdIssue{Check: HouseholdCheckParentAge,
+					Members: []string{memberField(i)}, MemberIDs: []string{m.ID},
+					Message: fmt.Sprintf("%s was %d when the applicant was born, not between %d and %d",
+						memberField(i), age, MinParentAge, MaxParentAge)})
+			}
+		}
+	}
+
+	seen := map[string]int{}
+	for i := range a.Household {
+		m := &a.Household[i]
+		identityNumber := NormalizeIdentityNumber(m.IdentityNumber)
+		if identityNumber == "" {
+			continue
+		}
+		if identityNumber == NormalizeIdentityNumber(a.IdentityNumber) {
+			issues = append(issues, HouseholdIssue{Check: HouseholdCheckDuplicateMember,
+				Members: []string{memberField(i)}, MemberIDs: []string{m.ID},
+				Message: memberField(i) + " has the applicant's own NRIC/FIN"})
+			continue
+		}
+		if j, ok := seen[identityNumber]; ok {
+			issues = append(issues, HouseholdIssue{Check: HouseholdCheckDuplicateMember,
+				Members: []string{memberField(j), memberField(i)}, MemberIDs: []string{a.Household[j].ID, m.ID},
+				Message: memberField(j) + " and " + memberField(i) + " have the same NRIC/FIN"})
+			continue
+		}
+		seen[identityNumber] = i
+	}
+
+	return issues
+}
+
+// OtherHouseholdIssues reports the members of the applicant's household
+// found in matches, as returned by ApplicantRepository.FindIdentityNumbers
+func (a *Applicant) OtherHouseholdIssues(matches []IdentityNumberMatch) []HouseholdIssue {
+	issues := []HouseholdIssue{}
+	for i := range a.Household {
+		m := &a.Household[i]
+		identityNumber := NormalizeIdentityNumber(m.IdentityNumber)
+		if identityNumber == "" {
+			continue
+		}
+		for _, match := range matches {
+			if match.IdentityNumber != identityNumber {
+				continue
+			}
+			message := memberField(i) + " is also in the household of applicant " + match.ApplicantID
+			if match.MemberID == "" {
+				message = memberField(i) + " is also applicant " + match.ApplicantID
+			}
+			issues = append(issues, HouseholdIssue{Check: HouseholdCheckOtherHousehold,
+				Members: []string{memberField(i)}, MemberIDs: []string{m.ID},
+				Message: message, OtherApplicantID: match.ApplicantID})
+		}
+	}
+	return issues
+}
+
+// FindIdentityNumbers finds the applicants other than excludeApplicantID,
+// and the members of their households, with any of the given identity
+// numbers. Soft-deleted applicants and their households are left out.
+// Hashes under every key are matched, so rows not yet re-indexed after a key
+// rotation are found.
+func (r *ApplicantRepository) FindIdentityNumbers(identityNumbers []string, excludeApplicantID string) ([]IdentityNumberMatch, error) {
+	byHash := map[string]string{}
+	for _, identityNumber := range identityNumbers {
+		identityNumber = NormalizeIdentityNumber(identityNumber)
+		if identityNumber == "" {
+			continue
+		}
+		for _, hash := range r.Cipher.Hashes(identityNumber) {
+			byHash[hash] = identityNumber
+		}
+	}
+	if len(byHash) == 0 {
+		return nil, nil
+	}
+	hashes := make([]string, 0, len(byHash))
+	for hash := range byHash {
+		hashes = append(hashes, hash)
+	}
+	placeholders, args := inClause(hashes)
+
+	var matches []IdentityNumberMatch
+	scan := func(rows *sql.Rows) error {
+		defer rows.Close()
+		for rows.Next() {
+			var match IdentityNumberMatch
+			var memberID sql.NullString
+			var hash string
+			if err := rows.Scan(&match.ApplicantID, &memberID, &hash); err != nil {
+				return fmt.Errorf("error scanning identity number row: %v", err)
+			}
+			match.MemberID = memberID.String
+			match.IdentityNumber = byHash[hash]
+			matches = append(matches, match)
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error iterating identity number rows: %v", err)
+		}
+		return nil
+	}
+
+	rows, err := r.conn().Query(`SELECT id, NULL, identity_number_hash FROM applicants
+			  WHERE identity_number_hash IN (`+placeholders+`) AND id <> ? AND deleted_at IS NULL`,
+		append(args, excludeApplicantID)...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying applicants by identity number: %v", err)
+	}
+	if err := scan(rows); err != nil {
+		return nil, err
+	}
+
+	rows, err = r.conn().Query(`SELECT m.applicant_id, m.id, m.identity_number_hash
+			  FROM household_members m JOIN applicants a ON a.id = m.applicant_id
+			  WHERE m.identity_number_hash IN (`+placeholders+`) AND m.applicant_id <> ? AND a.deleted_at IS NULL
+			  ORDER BY m.applicant_id, m.id`,
+		append(args, excludeApplicantID)...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying household members by identity number: %v", err)
+	}
+	if err := scan(rows); err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
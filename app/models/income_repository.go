@@ -0,0 +1,98 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IncomeRepository handles database operations for the incomes retrieved for
+// applicants from external sources
+type IncomeRepository struct {
+	DB *sql.DB
+	tx *sql.Tx
+}
+
+// NewIncomeRepository creates a new repository with the given database connection
+func NewIncomeRepository(db *sql.DB) *IncomeRepository {
+	return &IncomeRepository{DB: db}
+}
+
+// WithTx returns a copy of the repository that runs its queries in tx
+func (r *IncomeRepository) WithTx(tx *sql.Tx) *IncomeRepository {
+	return &IncomeRepository{DB: r.DB, tx: tx}
+}
+
+// conn returns the transaction the repository is bound to, or the database
+func (r *IncomeRepository) conn() DBTX {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.DB
+}
+
+// GetByApplicantID retrieves the incomes retrieved for an applicant, newest
+// first
+func (r *IncomeRepository) GetByApplicantID(applicantID string) ([]IncomeRecord, error) {
+	query := `SELECT id, applicant_id, source, monthly_income, previous_monthly_income, assessment_year,
+				  reference, retrieved_by, retrieved_at
+			  FROM income_records
+			  WHERE applicant_id = ?
+			  ORDER BY retrieved_at DESC, id DESC`
+
+	rows, err := r.conn().Query(query, applicantID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying income records: %v", err)
+	}
+	defer rows.Close()
+
+	var records []IncomeRecord
+	for rows.Next() {
+		var rec IncomeRecord
+		var assessmentYear sql.NullInt64
+		var reference, retrievedBy sql.NullString
+		if err := rows.Scan(&rec.ID, &rec.ApplicantID, &rec.Source, &rec.MonthlyIncome, &rec.PreviousMonthlyIncome,
+			&assessmentYear, &reference, &retrievedBy, &rec.RetrievedAt); err != nil {
+			return nil, fmt.Errorf("error scanning income record row: %v", err)
+		}
+		if assessmentYear.Valid {
+			year := int(assessmentYear.Int64)
+			rec.AssessmentYear = &year
+		}
+		rec.Reference = reference.String
+		rec.RetrievedBy = retrievedBy.String
+		records = append(records, rec)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating income record rows: %v", err)
+	}
+
+	return records, nil
+}
+
+// Create inserts a new income record, retrieved now unless RetrievedAt is set
+func (r *IncomeRepository) Create(rec *IncomeRecord) error {
+	if rec.ID == "" {
+		rec.ID = uuid.New().String()
+	}
+	if rec.RetrievedAt.IsZero() {
+		rec.RetrievedAt = time.Now()
+	}
+	var assessmentYear interface{}
+	if rec.AssessmentYear != nil {
+		assessmentYear = *rec.AssessmentYear
+	}
+
+	query := `INSERT INTO income_records (id, applicant_id, source, monthly_income, previous_monthly_income,
+				  assessment_year, reference, retrieved_by, retrieved_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := r.conn().Exec(query, rec.ID, rec.ApplicantID, rec.Source, rec.MonthlyIncome, rec.PreviousMonthlyIncome,
+		assessmentYear, nullString(rec.Reference), nullString(rec.RetrievedBy), rec.RetrievedAt)
+	if err != nil {
+		return fmt.Errorf("error creating income record: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,356 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Job statuses
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+)
+
+// Job types
+const (
+	JobImportApplicants   = "applicants.import"
+	JobBatchEligibility   = "eligibility.batch"
+	JobEligibilityReview  = "eligibility.review"
+	JobApplicationsReport = "reports.applications_summary"
+	JobWebhookDelivery    = "webhook.deliver"
+	JobChangeExport       = "changes.export"
+	JobRetentionRun       = "retention.run"
+	JobTaskReminders      = "tasks.remind"
+	JobBackup             = "database.backup"
+)
+
+// JobRepository handles database operations for the background job queue
+type JobRepository struct {
+	DB *sql.DB
+	tx *sql.Tx
+}
+
+// NewJobRepository creates a new repository with the given database connection
+func NewJobRepository(db *sql.DB) *JobRepository {
+	return &JobRepository{DB: db}
+}
+
+// WithTx returns a copy of the repository that runs its queries in tx
+func (r *JobRepository) WithTx(tx *sql.Tx) *JobRepository {
+	return &JobRepository{DB: r.DB, tx: tx}
+}
+
+// conn returns the transaction the repository is bound to, or the database
+func (r *JobRepository) conn() DBTX {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.DB
+}
+
+// jobColumns is the column list read by scanJob
+const jobColumns = `id, type, status, payload, result, attempts, last_error, run_at, created_by,
+	created_at, started_at, finished_at, updated_at`
+
+// scanJob scans a row selected with jobColumns
+func scanJob(row rowScanner) (Job, error) {
+	var j Job
+	var payload, result []byte
+	var lastError, createdBy sql.NullString
+	var startedAt, finishedAt sql.NullTime
+
+	if err := row.Scan(&j.ID, &j.Type, &j.Status, &payload, &result, &j.Attempts, &lastError, &j.RunAt,
+		&createdBy, &j.CreatedAt, &startedAt, &finishedAt, &j.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return j, err
+		}
+		return j, fmt.Errorf("error scanning job row: %v", err)
+	}
+
+	j.Payload = json.RawMessage(payload)
+	if result != nil {
+		j.Result = json.RawMessage(result)
+	}
+	j.LastError = lastError.String
+	j.CreatedBy = createdBy.String
+	if startedAt.Valid {
+		j.StartedAt = &startedAt.Time
+	}
+	if finishedAt.Valid {
+		j.FinishedAt = &finishedAt.Time
+	}
+
+	return j, nil
+}
+
+// Enqueue adds a job of the given type to the queue, due now. createdBy is
+// the ID of the requesting user, or empty for jobs queued by the system. Call
+// it in the same transaction as any change the job depends on, so that the
+// job is queued if and only if the change commits.
+func (r *JobRepository) Enqueue(jobType string, payload interface{}, createdBy string) (*Job, error) {
+	return r.enqueue(uuid.New().String(), jobType, payload, createdBy)
+}
+
+// EnqueueOnce adds a job queued by the system with the given ID, unless a job
+// with that ID already exists, and reports whether it was added. Queueing
+// servers agree on the ID so that the job is only queued once.
+func (r *JobRepository) EnqueueOnce(id, jobType string, payload interface{}) (bool, error) {
+	_, err := r.enqueue(id, jobType, payload, "")
+	if isUniqueViolation(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// enqueue adds a job with the given ID to the queue
+func (r *JobRepository) enqueue(id, jobType string, payload interface{}, createdBy string) (*Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling job payload: %v", err)
+	}
+
+	now := time.Now()
+	j := &Job{
+		ID:        id,
+		Type:      jobType,
+		Status:    JobQueued,
+		Payload:   data,
+		RunAt:     now,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	var creator interface{}
+	if createdBy != "" {
+		creator = createdBy
+	}
+
+	query := `INSERT INTO jobs (id, type, status, payload, attempts, run_at, created_by, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, 0, ?, ?, ?, ?)`
+
+	if _, err := r.conn().Exec(query, j.ID, j.Type, j.Status, data, j.RunAt, creator, j.CreatedAt, j.UpdatedAt); err != nil {
+		return nil, fmt.Errorf("error queueing job: %v", err)
+	}
+
+	return j, nil
+}
+
+// GetByID retrieves a job by ID
+func (r *JobRepository) GetByID(id string) (*Job, error) {
+	query := `SELECT ` + jobColumns + `
+			  FROM jobs
+			  WHERE id = ?`
+
+	j, err := scanJob(r.conn().QueryRow(query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // No job found
+		}
+		return nil, err
+	}
+
+	return &j, nil
+}
+
+// FindByType returns the latest jobs of the given type, newest first, up to
+// limit
+func (r *JobRepository) FindByType(jobType string, limit int) ([]Job, error) {
+	query := `SELECT ` + jobColumns + `
+			  FROM jobs
+			  WHERE type = ?
+			  ORDER BY created_at DESC, id
+			  LIMIT ?`
+
+	rows, err := r.conn().Query(query, jobType, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error querying jobs: %v", err)
+	}
+	defer rows.Close()
+
+	var jobs []Job
+	for rows.Next() {
+		j, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, j)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating jobs: %v", err)
+	}
+
+	return jobs, nil
+}
+
+// ClaimNext claims the job of one of the given types that has been due the
+// longest: a queued job whose time has come, or a running job whose claim
+// has expired because its worker stopped. The claimed job is marked running
+// with its attempts incremented, and is held for lease; its worker must
+// Extend the claim to hold it for longer. It returns nil if no job is due.
+func (r *JobRepository) ClaimNext(types []string, lease time.Duration) (*Job, error) {
+	if len(types) == 0 {
+		return nil, nil
+	}
+
+	now := time.Now()
+	placeholders, args := inClause(types)
+	query := `SELECT ` + jobColumns + `
+			  FROM jobs
+			  WHERE status IN (?, ?) AND run_at <= ? AND type IN (` + placeholders + `)
+			  ORDER BY run_at ASC
+			  LIMIT 10`
+
+	rows, err := r.conn().Query(query, append([]interface{}{JobQueued, JobRunning, now}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying due jobs: %v", err)
+	}
+
+	var due []Job
+	for rows.Next() {
+		j, err := scanJob(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		due = append(due, j)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating due job rows: %v", err)
+	}
+
+	// Claim the first job no other worker has claimed since it was read;
+	// its attempt count changes with every claim
+	claim := `UPDATE jobs
+			  SET status = ?, attempts = ?, run_at = ?, started_at = COALESCE(started_at, ?), updated_at = ?
+			  WHERE id = ? AND attempts = ? AND status IN (?, ?) AND run_at <= ?`
+
+	for _, j := range due {
+		result, err := r.conn().Exec(claim, JobRunning, j.Attempts+1, now.Add(lease), now, now,
+			j.ID, j.Attempts, JobQueued, JobRunning, now)
+		if err != nil {
+			return nil, fmt.Errorf("error claiming job: %v", err)
+		}
+		if n, err := result.RowsAffected(); err != nil {
+			return nil, fmt.Errorf("error checking rows affected: %v", err)
+		} else if n == 1 {
+			j.Status = JobRunning
+			j.Attempts++
+			j.RunAt = now.Add(lease)
+			if j.StartedAt == nil {
+				j.StartedAt = &now
+			}
+			j.UpdatedAt = now
+			return &j, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Extend holds a running job's claim until until. It reports false if the
+// claim has been lost, e.g. because it expired and another worker took the
+// job.
+func (r *JobRepository) Extend(j *Job, until time.Time) (bool, error) {
+	query := `UPDATE jobs SET run_at = ? WHERE id = ? AND attempts = ? AND status = ?`
+
+	result, err := r.conn().Exec(query, until, j.ID, j.Attempts, JobRunning)
+	if err != nil {
+		return false, fmt.Errorf("error extending job claim: %v", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("error checking rows affected: %v", err)
+	}
+	return n == 1, nil
+}
+
+// MarkSucceeded records a job's result, which may be nil
+func (r *JobRepository) MarkSucceeded(j *Job, result interface{}) error {
+	var data interface{}
+	if result != nil {
+		encoded, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("error marshaling job result: %v", err)
+		}
+		data = encoded
+	}
+
+	now := time.Now()
+	query := `UPDATE jobs
+			  SET status = ?, result = ?, last_error = NULL, finished_at = ?, updated_at = ?
+			  WHERE id = ? AND attempts = ?`
+
+	if _, err := r.conn().Exec(query, JobSucceeded, data, now, now, j.ID, j.Attempts); err != nil {
+		return fmt.Errorf("error marking job succeeded: %v", err)
+	}
+	return nil
+}
+
+// MarkAttemptFailed records a failed attempt at a job. The job is retried at
+// nextAttempt, or marked failed for good if nextAttempt is nil.
+func (r *JobRepository) MarkAttemptFailed(j *Job, lastError string, nextAttempt *time.Time) error {
+	now := time.Now()
+	status := JobQueued
+	next := now
+	var finishedAt interface{}
+	if nextAttempt != nil {
+		next = *nextAttempt
+	} else {
+		status = JobFailed
+		finishedAt = now
+	}
+
+	query := `UPDATE jobs
+			  SET status = ?, last_error = ?, run_at = ?, finished_at = ?, updated_at = ?
+			  WHERE id = ? AND attempts = ?`
+
+	if _, err := r.conn().Exec(query, status, lastError, next, finishedAt, now, j.ID, j.Attempts); err != nil {
+		return fmt.Errorf("error recording job failure: %v", err)
+	}
+	return nil
+}
+
+// Release returns a running job to the queue without counting the attempt,
+// e.g. when its worker is stopped before it finishes
+func (r *JobRepository) Release(j *Job) error {
+	now := time.Now()
+	query := `UPDATE jobs
+			  SET status = ?, attempts = ?, run_at = ?, updated_at = ?
+			  WHERE id = ? AND attempts = ? AND status = ?`
+
+	if _, err := r.conn().Exec(query, JobQueued, j.Attempts-1, now, now, j.ID, j.Attempts, JobRunning); err != nil {
+		return fmt.Errorf("error releasing job: %v", err)
+	}
+	return nil
+}
+
+// Retry queues a failed job to run again now, with its attempts reset. It
+// reports false if the job is not failed.
+func (r *JobRepository) Retry(id string) (bool, error) {
+	now := time.Now()
+	query := `UPDATE jobs
+			  SET status = ?, attempts = 0, run_at = ?, finished_at = NULL, updated_at = ?
+			  WHERE id = ? AND status = ?`
+
+	result, err := r.conn().Exec(query, JobQueued, now, now, id, JobFailed)
+	if err != nil {
+		return false, fmt.Errorf("error retrying job: %v", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("error checking rows affected: %v", err)
+	}
+	return n == 1, nil
+}
@@ -1,22 +1,45 @@
 package models
 
 import (
-	"database/sql"
 	"encoding/json"
+	"regexp"
 	"time"
+
+	"one-client-view-2025tht/app/date"
+	"one-client-view-2025tht/app/money"
 )
 
 // Applicant represents an individual applying for financial assistance
 type Applicant struct {
 	ID               string            `json:"id"`
 	Name             string            `json:"name"`
+	IdentityNumber   string            `json:"identity_number,omitempty" example:"S1234567D"` // NRIC or FIN, unique; encrypted at rest
 	EmploymentStatus string            `json:"employment_status"`
 	Sex              string            `json:"sex"`
-	DateOfBirth      time.Time         `json:"date_of_birth"`
+	DateOfBirth      date.Date         `json:"date_of_birth" swaggertype:"string" format:"date" example:"1990-07-01"`
 	MaritalStatus    string            `json:"marital_status"`
+	MonthlyIncome    float64           `json:"monthly_income"`
+	Email            string            `json:"email,omitempty"`
+	EmailOptOut      bool              `json:"email_opt_out"`                         // Set when the applicant declines email notifications
+	Phone            string            `json:"phone,omitempty" example:"+6591234567"` // Stored without spaces or hyphens
+	Address          *Address          `json:"address,omitempty"`
+	Version          int               `json:"version"` // Incremented on every update, for optimistic locking
 	CreatedAt        time.Time         `json:"created_at,omitempty"`
 	UpdatedAt        time.Time         `json:"updated_at,omitempty"`
+	DeletedAt        *time.Time        `json:"deleted_at,omitempty"`
+	AnonymizedAt     *time.Time        `json:"anonymized_at,omitempty"` // Set once personal data has been irreversibly replaced
 	Household        []HouseholdMember `json:"household,omitempty"`
+	CustomFields     CustomValues      `json:"custom_fields,omitempty" swaggertype:"object"` // Values of the custom fields defined in this deployment, by key
+}
+
+// Address is a Singapore postal address
+type Address struct {
+	Block          string `json:"block,omitempty" example:"123"`
+	Street         string `json:"street" example:"Ang Mo Kio Avenue 3"`
+	Unit           string `json:"unit,omitempty" example:"#12-34"`
+	Building       string `json:"building,omitempty"`
+	PostalCode     string `json:"postal_code" example:"560123"`
+	PostalDistrict string `json:"postal_district,omitempty" example:"20"` // Derived from the postal code; read-only
 }
 
 // HouseholdMember represents a family member living with the applicant
@@ -24,19 +47,105 @@ type HouseholdMember struct {
 	ID               string    `json:"id"`
 	ApplicantID      string    `json:"applicant_id"`
 	Name             string    `json:"name"`
+	IdentityNumber   string    `json:"identity_number,omitempty" example:"T0512345C"` // NRIC or FIN, if declared; encrypted at rest
 	EmploymentStatus string    `json:"employment_status"`
 	Sex              string    `json:"sex"`
-	DateOfBirth      time.Time `json:"date_of_birth"`
-	Relation         string    `json:"relation"`
+	DateOfBirth      date.Date `json:"date_of_birth" swaggertype:"string" format:"date" example:"1990-07-01"`
+	Relation         string    `json:"relation" example:"daughter"` // One of Relations
+	MonthlyIncome    float64   `json:"monthly_income"`
+	SchoolLevel      string    `json:"school_level,omitempty" example:"primary"` // As declared; see SchoolLevels
 	CreatedAt        time.Time `json:"created_at,omitempty"`
 	UpdatedAt        time.Time `json:"updated_at,omitempty"`
+
+	HouseholdID       string `json:"household_id,omitempty"`        // Read-only; set on members of a shared household
+	MemberApplicantID string `json:"member_applicant_id,omitempty"` // Read-only; set when the member is another applicant in the shared household
+}
+
+// Relations of a household member to the applicant
+const (
+	RelationSpouse   = "spouse"
+	RelationSon      = "son"
+	RelationDaughter = "daughter"
+	RelationParent   = "parent"
+	RelationSibling  = "sibling"
+	RelationOther    = "other"
+)
+
+// Relations lists every relation a household member can have to the applicant
+var Relations = []string{RelationSpouse, RelationSon, RelationDaughter, RelationParent, RelationSibling, RelationOther}
+
+// Household is shared by applicants of the same family, so the members they
+// have in common are recorded once: the household members of its head are
+// the household's, and other applicants join it for a period
+type Household struct {
+	ID              string                `json:"id"`
+	HeadApplicantID string                `json:"head_applicant_id"` // The applicant the members' relations are to
+	Memberships     []HouseholdMembership `json:"memberships"`       // Other applicants, present and past, by start date
+	CreatedBy       string                `json:"created_by,omitempty"`
+	CreatedAt       time.Time             `json:"created_at"`
+	UpdatedAt       time.Time             `json:"updated_at"`
+}
+
+// HouseholdMembership is an applicant's membership of a household other than
+// as its head
+type HouseholdMembership struct {
+	ID          string     `json:"id"`
+	HouseholdID string     `json:"household_id"`
+	ApplicantID string     `json:"applicant_id"`
+	Relation    string     `json:"relation" example:"spouse"` // The applicant's relation to the head; one of Relations
+	StartDate   time.Time  `json:"start_date"`                // A member from this day
+	EndDate     *time.Time `json:"end_date,omitempty"`        // No longer a member from this day, if set
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// CurrentAt reports whether the membership is in effect at t
+func (m *HouseholdMembership) CurrentAt(t time.Time) bool {
+	return !t.Before(m.StartDate) && (m.EndDate == nil || t.Before(*m.EndDate))
+}
+
+// Overlaps reports whether the membership is in effect at any time the other
+// is
+func (m *HouseholdMembership) Overlaps(other *HouseholdMembership) bool {
+	return (other.EndDate == nil || m.StartDate.Before(*other.EndDate)) &&
+		(m.EndDate == nil || other.StartDate.Before(*m.EndDate))
+}
+
+// HouseholdRequest is the body of a request creating a shared household
+type HouseholdRequest struct {
+	HeadApplicantID string `json:"head_applicant_id"`
+}
+
+// HouseholdMembershipRequest is the body of a request adding an applicant to
+// a household, or replacing their membership
+type HouseholdMembershipRequest struct {
+	ApplicantID string     `json:"applicant_id,omitempty"` // Required when adding; cannot be changed
+	Relation    string     `json:"relation" example:"spouse"`
+	StartDate   time.Time  `json:"start_date"`
+	EndDate     *time.Time `json:"end_date,omitempty"`
+}
+
+// SharedHousehold is the household an applicant is assessed against at a
+// time: the shared household they belong to then, seen from their side, or
+// their own household members if they belong to none
+type SharedHousehold struct {
+	ApplicantID string            `json:"applicant_id"`
+	HouseholdID string            `json:"household_id,omitempty"` // Empty when the applicant's own household members are used
+	AsOf        time.Time         `json:"as_of"`
+	Household   []HouseholdMember `json:"household"`
 }
 
 // Criteria represents the eligibility criteria for schemes
 type Criteria struct {
-	EmploymentStatus string        `json:"employment_status,omitempty"`
-	MaritalStatus    string        `json:"marital_status,omitempty"`
-	HasChildren      ChildCriteria `json:"has_children,omitempty"`
+	EmploymentStatus   string        `json:"employment_status,omitempty"`
+	MaritalStatus      string        `json:"marital_status,omitempty"`
+	MinAge             *int          `json:"min_age,omitempty"` // Applicant's age in whole years, inclusive
+	MaxAge             *int          `json:"max_age,omitempty"` // Applicant's age in whole years, inclusive
+	HasChildren        ChildCriteria `json:"has_children,omitempty"`
+	MaxHouseholdIncome *float64      `json:"max_household_income,omitempty"`  // Monthly, applicant and household combined
+	MaxPerCapitaIncome *float64      `json:"max_per_capita_income,omitempty"` // Monthly household income per person
+	Rules              *Rule         `json:"rules,omitempty"`                 // Custom rules, combined with the fields above using AND
+	AnyOf              []Criteria    `json:"any_of,omitempty"`                // Alternative groups of criteria, at least one of which must also match
 }
 
 // ChildCriteria represents specific criteria related to children
@@ -44,41 +153,791 @@ type ChildCriteria struct {
 	SchoolLevel string `json:"school_level,omitempty"`
 }
 
+// Statuses in the lifecycle of a scheme
+const (
+	SchemeDraft     = "draft"     // Being prepared; editable, but not offered to anyone
+	SchemePublished = "published" // Offered to applicants while active and open
+	SchemeArchived  = "archived"  // Withdrawn; kept for its applications and no longer editable
+)
+
+// SchemeStatuses lists every status a scheme can have
+var SchemeStatuses = []string{SchemeDraft, SchemePublished, SchemeArchived}
+
 // Scheme represents a financial assistance scheme
 type Scheme struct {
-	ID          string    `json:"id"`
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Criteria    Criteria        `json:"criteria"`
+	Version     int             `json:"version"` // Incremented on every update, for optimistic locking
+	CreatedAt   time.Time       `json:"created_at,omitempty"`
+	UpdatedAt   time.Time       `json:"updated_at,omitempty"`
+	Benefits    []Benefit       `json:"benefits,omitempty"`
+	Checklist   []ChecklistItem `json:"checklist,omitempty"` // Review checklist copied to each new application
+
+	// The scheme accepts applications while it is published, active and
+	// within its window; an unset date leaves that end of the window open.
+	// See OpenAt. The status is changed by publishing and archiving the
+	// scheme, not by updates.
+	OpenDate  *time.Time `json:"open_date,omitempty"`
+	CloseDate *time.Time `json:"close_date,omitempty"`
+	IsActive  bool       `json:"is_active"`
+	Status    string     `json:"status" example:"draft" enums:"draft,published,archived"` // Set by the server
+
+	// Optional limits on the applications a scheme approves, checked on every
+	// approval against the running totals of those already approved. An
+	// approval commits its recommended benefit amount, or the projected value
+	// of the applicant's entitlement to the scheme's benefits if none is
+	// recommended. Amounts are in the currency of the scheme's benefits.
+	MaxApplications       *int          `json:"max_applications,omitempty"`
+	Budget                *money.Amount `json:"budget,omitempty" swaggertype:"string" example:"10000.00"`
+	ApprovedCount         int           `json:"approved_count"`                                                    // Set by the server
+	ApprovedAmount        money.Amount  `json:"approved_amount" swaggertype:"string" example:"600.00"`             // Set by the server
+	RemainingApplications *int          `json:"remaining_applications,omitempty"`                                  // Set by the server if max_applications is
+	RemainingBudget       *money.Amount `json:"remaining_budget,omitempty" swaggertype:"string" example:"9400.00"` // Set by the server if budget is
+
+	// CoolingOffDays is how long an applicant must wait after a rejection
+	// before applying to the scheme again, counted from the decision. Unset
+	// or zero lets them reapply straight away.
+	CoolingOffDays *int `json:"cooling_off_days,omitempty" example:"90"`
+
+	// EffectiveFrom is when the name, description and criteria sent in a
+	// create or update take effect, defaulting to now. It is not set on reads;
+	// see SchemeVersion for the effective dates of stored terms.
+	EffectiveFrom *time.Time `json:"effective_from,omitempty"`
+}
+
+// OpenAt reports whether the scheme accepts applications at t: it is
+// published and active, and t is neither before its open date nor after its
+// close date
+func (s *Scheme) OpenAt(t time.Time) bool {
+	if s.Status != SchemePublished || !s.IsActive {
+		return false
+	}
+	if s.OpenDate != nil && t.Before(*s.OpenDate) {
+		return false
+	}
+	return s.CloseDate == nil || !t.After(*s.CloseDate)
+}
+
+// setRemaining sets the capacity the scheme has left under its limits, never
+// less than zero
+func (s *Scheme) setRemaining() {
+	s.RemainingApplications, s.RemainingBudget = nil, nil
+	if s.MaxApplications != nil {
+		remaining := max(*s.MaxApplications-s.ApprovedCount, 0)
+		s.RemainingApplications = &remaining
+	}
+	if s.Budget != nil {
+		remaining := max(*s.Budget-s.ApprovedAmount, 0)
+		s.RemainingBudget = &remaining
+	}
+}
+
+// Clone returns a copy of the scheme with its criteria, benefits and review
+// checklist, as a new draft to create: " (copy)" is appended to the name, and
+// the IDs, approved totals and timestamps are left for the server to set. The
+// history of its terms and its translations are not copied.
+func (s *Scheme) Clone() Scheme {
+	clone := Scheme{
+		Name:            s.Name + " (copy)",
+		Description:     s.Description,
+		Criteria:        s.Criteria,
+		OpenDate:        s.OpenDate,
+		CloseDate:       s.CloseDate,
+		IsActive:        s.IsActive,
+		Status:          SchemeDraft,
+		MaxApplications: s.MaxApplications,
+		Budget:          s.Budget,
+		CoolingOffDays:  s.CoolingOffDays,
+		Benefits:        make([]Benefit, len(s.Benefits)),
+		Checklist:       make([]ChecklistItem, len(s.Checklist)),
+	}
+	for i, b := range s.Benefits {
+		b.ID, b.SchemeID = "", ""
+		b.CreatedAt, b.UpdatedAt = time.Time{}, time.Time{}
+		clone.Benefits[i] = b
+	}
+	for i, item := range s.Checklist {
+		item.ID, item.SchemeID = "", ""
+		item.CreatedAt, item.UpdatedAt = time.Time{}, time.Time{}
+		clone.Checklist[i] = item
+	}
+	return clone
+}
+
+// SchemeVersion is a snapshot of a scheme's eligibility terms and the period
+// they apply to. A new version is saved on every create and update of a scheme.
+type SchemeVersion struct {
+	SchemeID      string     `json:"scheme_id"`
+	Version       int        `json:"version"` // The scheme's version when the terms were saved
+	Name          string     `json:"name"`
+	Description   string     `json:"description"`
+	Criteria      Criteria   `json:"criteria"`
+	EffectiveFrom time.Time  `json:"effective_from"`
+	EffectiveTo   *time.Time `json:"effective_to,omitempty"` // Unset for the latest version
+	CreatedAt     time.Time  `json:"created_at,omitempty"`
+}
+
+// SchemeTranslation is the name and description of a scheme in a language
+// other than English, served instead of the English ones to clients that
+// prefer the language
+type SchemeTranslation struct {
+	SchemeID    string    `json:"scheme_id"`
+	Language    string    `json:"language" example:"zh" enums:"zh,ms,ta"`
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
-	Criteria    Criteria  `json:"criteria"`
-	CreatedAt   time.Time `json:"created_at,omitempty"`
-	UpdatedAt   time.Time `json:"updated_at,omitempty"`
-	Benefits    []Benefit `json:"benefits,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// SchemeTranslationRequest is the body of a request translating a scheme
+type SchemeTranslationRequest struct {
+	Name        string `json:"name" example:"乐龄援助计划"`
+	Description string `json:"description"`
+}
+
+// Label is the label of an enumerated value, such as an application status,
+// in the language of the response
+type Label struct {
+	Value string `json:"value" example:"approved"`
+	Label string `json:"label" example:"Approved"`
 }
 
 // Benefit represents benefits provided by a scheme
 type Benefit struct {
+	ID          string       `json:"id"`
+	SchemeID    string       `json:"scheme_id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Amount      money.Amount `json:"amount,omitempty" swaggertype:"string" example:"300.00"` // Paid each time
+	Currency    string       `json:"currency" example:"SGD"`                                 // ISO 4217 code; defaults to SGD
+
+	// How often the amount is paid, one of BenefitFrequencies, defaulting to
+	// one_off, and for recurring benefits the number of months they are paid
+	// over
+	Frequency      string `json:"frequency" enums:"one_off,monthly,quarterly" example:"monthly"`
+	DurationMonths *int   `json:"duration_months,omitempty" example:"12"`
+
+	Formula *BenefitFormula `json:"formula,omitempty"` // Scales the amount with the household, if set
+
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// Frequencies at which a benefit is paid
+const (
+	BenefitOneOff    = "one_off"
+	BenefitMonthly   = "monthly"
+	BenefitQuarterly = "quarterly"
+)
+
+// BenefitFrequencies lists every frequency a benefit can be paid at
+var BenefitFrequencies = []string{BenefitOneOff, BenefitMonthly, BenefitQuarterly}
+
+// Payments returns the number of times the benefit is paid: once if it is
+// one-off, every month of its duration if monthly, and at the start of every
+// quarter begun within its duration if quarterly, so 12 months is 4 payments
+// and 13 months is 5
+func (b *Benefit) Payments() int {
+	if b.Frequency == "" || b.Frequency == BenefitOneOff || b.DurationMonths == nil {
+		return 1
+	}
+	months := *b.DurationMonths
+	if b.Frequency == BenefitQuarterly {
+		return (months + 2) / 3
+	}
+	return months
+}
+
+// ProjectedValue returns the total the benefit pays over its duration
+func (b *Benefit) ProjectedValue() money.Amount {
+	return b.Amount * money.Amount(b.Payments())
+}
+
+// ProjectedValue returns the total the benefits pay over their durations
+func ProjectedValue(benefits []Benefit) money.Amount {
+	var total money.Amount
+	for i := range benefits {
+		total += benefits[i].ProjectedValue()
+	}
+	return total
+}
+
+// Application represents an application for a financial assistance scheme
+type Application struct {
+	ID              string     `json:"id"`
+	ApplicantID     string     `json:"applicant_id"`
+	SchemeID        string     `json:"scheme_id"`
+	Status          string     `json:"status"`
+	ApplicationDate date.Date  `json:"application_date" swaggertype:"string" format:"date" example:"2026-10-14"` // The day it was made; created_at has the time
+	DecisionDate    *time.Time `json:"decision_date,omitempty"`
+	Notes           string     `json:"notes,omitempty"`
+	Version         int        `json:"version"`                  // Incremented on every update, for optimistic locking
+	SchemeVersion   int        `json:"scheme_version,omitempty"` // Version of the scheme's terms the application was assessed under
+	CreatedAt       time.Time  `json:"created_at,omitempty"`
+	UpdatedAt       time.Time  `json:"updated_at,omitempty"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty"`
+	Applicant       *Applicant `json:"applicant,omitempty"`
+	Scheme          *Scheme    `json:"scheme,omitempty"`
+
+	// Set when the application is approved or rejected
+	DecidedBy                string        `json:"decided_by,omitempty"` // ID of the user who decided
+	DecisionReason           string        `json:"decision_reason,omitempty"`
+	RecommendedBenefitAmount *money.Amount `json:"recommended_benefit_amount,omitempty" swaggertype:"string" example:"500.00"` // Approvals only
+	CommittedAmount          *money.Amount `json:"-"`                                                                          // Taken up of the scheme's budget by an approval
+
+	// Set while the application is assigned to a user for review
+	AssignedTo string     `json:"assigned_to,omitempty"` // ID of the assigned user
+	AssignedAt *time.Time `json:"assigned_at,omitempty"`
+
+	// Set when the application is withdrawn
+	WithdrawnAt      *time.Time `json:"withdrawn_at,omitempty"`
+	WithdrawnBy      string     `json:"withdrawn_by,omitempty"` // ID of the staff user who withdrew it; empty if the applicant did
+	WithdrawalReason string     `json:"withdrawal_reason,omitempty"`
+
+	// Set by the server when the applicant had an application for the scheme
+	// rejected before, to the latest such application, which this one
+	// resubmits
+	PreviousApplicationID string `json:"previous_application_id,omitempty"`
+}
+
+// WithdrawRequest is used for withdrawing an application
+type WithdrawRequest struct {
+	Reason string `json:"reason" example:"No longer needed; found employment"`
+}
+
+// AssignRequest is used for assigning an application for review
+type AssignRequest struct {
+	UserID string `json:"user_id,omitempty"` // The authenticated user if omitted
+}
+
+// TrackingToken is the token an applicant can check an application's status
+// with, without signing in
+type TrackingToken struct {
+	ApplicationID string `json:"application_id"`
+	Token         string `json:"token"`
+}
+
+// ApplicationTracking is the status of an application shown to an applicant
+// with its tracking token, in the language of the response
+type ApplicationTracking struct {
+	Status    string `json:"status" enums:"pending,approved,rejected,withdrawn" example:"pending"`
+	Label     string `json:"label" example:"Pending"`
+	NextSteps string `json:"next_steps"`
+}
+
+// User represents a staff member who can log in to the API
+type User struct {
+	ID           string    `json:"id"`
+	Username     string    `json:"username"`
+	PasswordHash string    `json:"-"`
+	Role         string    `json:"role"`
+	Email        string    `json:"email,omitempty"` // Where task reminders are sent
+	CreatedAt    time.Time `json:"created_at,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at,omitempty"`
+}
+
+// AuditLog records a single create, update or delete of an entity
+type AuditLog struct {
+	ID            string                 `json:"id"`
+	EntityType    string                 `json:"entity_type" example:"applicant"`
+	EntityID      string                 `json:"entity_id"`
+	Action        string                 `json:"action" example:"update" enums:"create,update,delete,restore,approve,reject,withdraw,merge,purge,anonymize,assign,unassign,publish,archive"`
+	ActorID       string                 `json:"actor_id,omitempty"`
+	ActorUsername string                 `json:"actor_username,omitempty"`
+	Before        json.RawMessage        `json:"before,omitempty" swaggertype:"object"`
+	After         json.RawMessage        `json:"after,omitempty" swaggertype:"object"`
+	Changes       map[string]FieldChange `json:"changes,omitempty"`
+	CreatedAt     time.Time              `json:"created_at"`
+
+	subject interface{} // The entity after the change, from which its change event's data is built
+}
+
+// ChangeEvent is a change exported to the data warehouse, taken from its
+// audit entry. Exported events are written one per line as JSON.
+type ChangeEvent struct {
+	Sequence   int64           `json:"sequence"` // Increases in the order changes were made
+	ID         string          `json:"id"`       // The audit entry's ID, unique across exports
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Action     string          `json:"action"`
+	ActorID    string          `json:"actor_id,omitempty"`
+	Data       json.RawMessage `json:"data"` // Fields of the entity after the change, chosen per entity type; null for deletes and for entities whose contents are not exported
+	OccurredAt time.Time       `json:"occurred_at"`
+}
+
+// OutboxEvent is a domain event waiting in the outbox to be published to a
+// message broker
+type OutboxEvent struct {
+	Sequence  int64
+	ID        string // The event's ID, shared with its webhook deliveries
+	Event     string // The event name, e.g. application.approved
+	EntityID  string // The applicant or application the event is about, if known
+	Payload   json.RawMessage
+	CreatedAt time.Time
+}
+
+// ApplicationEvent is an immutable record of one transition of an
+// application, taken from its audit entry. Replaying an application's events
+// in order with ReplayApplication rebuilds its current state.
+type ApplicationEvent struct {
+	Sequence      int64           `json:"sequence"` // Increases in the order events happened, across applications
+	ID            string          `json:"id"`
+	ApplicationID string          `json:"application_id"`
+	Version       int             `json:"version"` // 1 for the application's first event, counting up
+	Type          string          `json:"type" example:"approve" enums:"create,update,delete,restore,approve,reject,withdraw,assign,unassign,merge,purge,anonymize,backfill"`
+	ActorID       string          `json:"actor_id,omitempty"`
+	ActorUsername string          `json:"actor_username,omitempty"`
+	Data          json.RawMessage `json:"data" swaggertype:"object"` // The fields the event set, or the whole application for create, anonymize and backfill events; null once purged or anonymized
+	OccurredAt    time.Time       `json:"occurred_at"`
+}
+
+// HistoryEntry is a timestamped set of field-level changes to an entity,
+// reconstructed from its audit log
+type HistoryEntry struct {
+	ChangedAt     time.Time              `json:"changed_at"`
+	Action        string                 `json:"action" example:"update" enums:"create,update,delete,restore,merge,anonymize"`
+	ActorID       string                 `json:"actor_id,omitempty"`
+	ActorUsername string                 `json:"actor_username,omitempty"`
+	Changes       map[string]FieldChange `json:"changes"` // Empty for deletions
+	AuditID       string                 `json:"audit_id"`
+}
+
+// Types of timeline entries, each naming the member of the entry that holds
+// its record
+const (
+	TimelineApplication  = "application"   // An application was made
+	TimelineStatusChange = "status_change" // An application changed status, such as being approved
+	TimelineCaseNote     = "case_note"
+	TimelineDocument     = "document" // A document was attached to an application
+	TimelineTask         = "task"     // A follow-up, such as an appointment, was set
+	TimelineReferral     = "referral"
+	TimelineChange       = "change" // The applicant's record was changed, as audited
+)
+
+// TimelineEntry is something that happened to an applicant, in the feed
+// that merges the records about them. Type says what happened, and the
+// member of the same name holds the record.
+type TimelineEntry struct {
+	Type          string            `json:"type" example:"status_change" enums:"application,status_change,case_note,document,task,referral,change"`
+	OccurredAt    time.Time         `json:"occurred_at"`
+	ApplicationID string            `json:"application_id,omitempty"` // The application the entry is about, if any
+	ActorID       string            `json:"actor_id,omitempty"`       // The user who did it, where recorded
+	ActorUsername string            `json:"actor_username,omitempty"`
+	Application   *Application      `json:"application,omitempty"`
+	StatusChange  *ApplicationEvent `json:"status_change,omitempty"`
+	CaseNote      *CaseNote         `json:"case_note,omitempty"`
+	Document      *Document         `json:"document,omitempty"`
+	Task          *Task             `json:"task,omitempty"`
+	Referral      *Referral         `json:"referral,omitempty"`
+	Change        *HistoryEntry     `json:"change,omitempty"`
+}
+
+// FieldChange describes how a single top-level field changed in a mutation
+type FieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// Webhook is a URL notified when subscribed events occur
+type Webhook struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url" example:"https://crm.example.com/hooks/ocv"`
+	Events    []string  `json:"events" example:"application.created,application.approved"`
+	Secret    string    `json:"secret,omitempty"` // HMAC signing key, only returned when the webhook is created
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// WebhookDelivery is a queued or attempted delivery of one event to a webhook
+type WebhookDelivery struct {
+	ID            string          `json:"id"`
+	WebhookID     string          `json:"webhook_id"`
+	EventID       string          `json:"event_id"` // Shared by the deliveries of one event, for deduplication
+	Event         string          `json:"event" example:"application.approved"`
+	Payload       json.RawMessage `json:"payload" swaggertype:"object"`
+	Status        string          `json:"status" example:"pending" enums:"pending,delivered,failed"`
+	Attempts      int             `json:"attempts"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	LastError     string          `json:"last_error,omitempty"`
+	DeliveredAt   *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Secret        string          `json:"-"` // The webhook's signing key, loaded for dispatch
+	URL           string          `json:"-"` // The webhook's URL, loaded for dispatch
+}
+
+// ReviewFlag marks an active application whose applicant no longer met the
+// scheme's criteria when eligibility was re-evaluated
+type ReviewFlag struct {
+	ID            string     `json:"id"`
+	ApplicationID string     `json:"application_id"`
+	Reason        string     `json:"reason" example:"Applicant no longer meets the criteria of version 2 of the scheme"`
+	SchemeVersion *int       `json:"scheme_version,omitempty"` // The scheme terms assessed against, if any were in effect
+	CreatedAt     time.Time  `json:"created_at"`
+	ResolvedAt    *time.Time `json:"resolved_at,omitempty"` // Set once the applicant is found eligible again
+}
+
+// Document is a supporting document attached to an application
+type Document struct {
+	ID            string    `json:"id"`
+	ApplicationID string    `json:"application_id"`
+	Filename      string    `json:"filename" example:"payslip-2026-09.pdf"`
+	ContentType   string    `json:"content_type" example:"application/pdf"`
+	Size          int64     `json:"size"`   // In bytes
+	SHA256        string    `json:"sha256"` // Hex-encoded checksum of the content
+	StorageKey    string    `json:"-"`
+	UploadedBy    string    `json:"uploaded_by,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Kinds of review checklist item
+const (
+	ChecklistDocument  = "document"  // A document to verify
+	ChecklistInterview = "interview" // An interview to conduct
+	ChecklistOther     = "other"
+)
+
+// ChecklistKinds lists every kind of review checklist item
+var ChecklistKinds = []string{ChecklistDocument, ChecklistInterview, ChecklistOther}
+
+// ChecklistItem is an item of a scheme's review checklist, such as a
+// document to verify or an interview to conduct. Each application to the
+// scheme gets a copy of the checklist when it is created, so later changes to
+// the scheme's items do not change those of existing applications.
+type ChecklistItem struct {
 	ID          string    `json:"id"`
 	SchemeID    string    `json:"scheme_id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description,omitempty"`
-	Amount      float64   `json:"amount,omitempty"`
+	Name        string    `json:"name" example:"Verify payslips"`
+	Description string    `json:"description,omitempty" example:"Payslips for the last 3 months"`
+	Kind        string    `json:"kind" enums:"document,interview,other" example:"document"` // Defaults to other
+	Mandatory   bool      `json:"mandatory"`                                                // Must be completed before the application is approved
+	Position    int       `json:"position"`                                                 // Items are listed by position, then name
 	CreatedAt   time.Time `json:"created_at,omitempty"`
 	UpdatedAt   time.Time `json:"updated_at,omitempty"`
 }
 
-// Application represents an application for a financial assistance scheme
-type Application struct {
-	ID              string       `json:"id"`
-	ApplicantID     string       `json:"applicant_id"`
-	SchemeID        string       `json:"scheme_id"`
-	Status          string       `json:"status"`
-	ApplicationDate time.Time    `json:"application_date"`
-	DecisionDate    sql.NullTime `json:"decision_date,omitempty"`
-	Notes           string       `json:"notes,omitempty"`
-	CreatedAt       time.Time    `json:"created_at,omitempty"`
-	UpdatedAt       time.Time    `json:"updated_at,omitempty"`
-	Applicant       *Applicant   `json:"applicant,omitempty"`
-	Scheme          *Scheme      `json:"scheme,omitempty"`
+// ApplicationChecklistItem is an item of an application's review checklist,
+// copied from its scheme's when the application was created, and ticked off
+// by the reviewers
+type ApplicationChecklistItem struct {
+	ID              string     `json:"id"`
+	ApplicationID   string     `json:"application_id"`
+	ChecklistItemID string     `json:"checklist_item_id,omitempty"` // The scheme's item this was copied from, unless it has since been deleted
+	Name            string     `json:"name" example:"Verify payslips"`
+	Description     string     `json:"description,omitempty" example:"Payslips for the last 3 months"`
+	Kind            string     `json:"kind" enums:"document,interview,other" example:"document"`
+	Mandatory       bool       `json:"mandatory"`
+	Position        int        `json:"position"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+	CompletedBy     string     `json:"completed_by,omitempty"`
+	Note            string     `json:"note,omitempty" example:"Seen the original payslips"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// ChecklistTickRequest is the body of a request ticking or unticking an item
+// of an application's checklist
+type ChecklistTickRequest struct {
+	Completed bool   `json:"completed"`
+	Note      string `json:"note,omitempty" example:"Seen the original payslips"` // Kept with the completed item; cleared when it is unticked
+}
+
+// ApplicantPrefill is what an external registry, such as MyInfo, holds about
+// a person, for drafting a new applicant with fewer typing errors. Fields the
+// registry does not hold are left empty, and employment and income are
+// never prefilled.
+type ApplicantPrefill struct {
+	IdentityNumber      string          `json:"identity_number" example:"S1234567D"`
+	Name                string          `json:"name,omitempty"`
+	Sex                 string          `json:"sex,omitempty"`
+	DateOfBirth         *date.Date      `json:"date_of_birth,omitempty" swaggertype:"string" format:"date" example:"1990-07-01"`
+	MaritalStatus       string          `json:"marital_status,omitempty"`
+	Address             *Address        `json:"address,omitempty"`
+	Household           []HouseholdHint `json:"household"`                       // People the registry links to the person, to confirm before adding
+	Source              string          `json:"source" example:"myinfo"`         // The registry the data came from
+	ExistingApplicantID string          `json:"existing_applicant_id,omitempty"` // Set when the person is already an applicant
+}
+
+// HouseholdHint is a person an external registry links to an applicant, such
+// as a child from their birth records, who may belong in their household
+type HouseholdHint struct {
+	Name        string     `json:"name"`
+	Relation    string     `json:"relation" example:"daughter"` // One of Relations
+	Sex         string     `json:"sex,omitempty"`
+	DateOfBirth *date.Date `json:"date_of_birth,omitempty" swaggertype:"string" format:"date" example:"2015-03-12"`
+}
+
+// IncomeAssessment is a person's income as assessed by an external source,
+// such as an IRAS notice of assessment or CPF contribution history
+type IncomeAssessment struct {
+	MonthlyIncome  float64 // Average monthly income over the period assessed
+	AssessmentYear int     // The year assessed, or 0 if the source gives none
+	Reference      string  // The source's reference for the assessment, if any
+}
+
+// IncomeRecord records an income retrieved for an applicant from an external
+// source, which replaced their monthly_income
+type IncomeRecord struct {
+	ID                    string    `json:"id"`
+	ApplicantID           string    `json:"applicant_id"`
+	Source                string    `json:"source" example:"iras"` // The provider the income came from
+	MonthlyIncome         float64   `json:"monthly_income" example:"3500"`
+	PreviousMonthlyIncome float64   `json:"previous_monthly_income" example:"2800"`   // The applicant's monthly_income before the retrieval
+	AssessmentYear        *int      `json:"assessment_year,omitempty" example:"2025"` // The year the income was assessed for, if the source gives one
+	Reference             string    `json:"reference,omitempty" example:"NOA-2025-000123"`
+	RetrievedBy           string    `json:"retrieved_by,omitempty"`
+	RetrievedAt           time.Time `json:"retrieved_at"`
+}
+
+// ApplicantPhoto describes an applicant's photo. The uploaded image and a
+// thumbnail of it are kept in a storage.Store.
+type ApplicantPhoto struct {
+	ApplicantID          string    `json:"applicant_id"`
+	ContentType          string    `json:"content_type" example:"image/jpeg"` // Of the uploaded image
+	Size                 int64     `json:"size"`                              // Of the uploaded image, in bytes
+	Width                int       `json:"width" example:"1200"`              // Of the uploaded image, in pixels
+	Height               int       `json:"height" example:"1600"`
+	StorageKey           string    `json:"-"`
+	ThumbnailKey         string    `json:"-"`
+	ThumbnailContentType string    `json:"-"`
+	UploadedBy           string    `json:"uploaded_by,omitempty"`
+	CreatedAt            time.Time `json:"created_at"`
+}
+
+// Consent records an applicant's consent to one purpose, such as sharing
+// their data with partner agencies or being contacted by email. Withdrawn and
+// expired consents are kept as a record.
+type Consent struct {
+	ID          string     `json:"id"`
+	ApplicantID string     `json:"applicant_id"`
+	Purpose     string     `json:"purpose" example:"data_sharing" enums:"data_sharing,contact_email,contact_phone,contact_post,income_verification"`
+	Reference   string     `json:"reference,omitempty" example:"Form SW-12 #4471"` // Where the consent was given
+	GrantedAt   time.Time  `json:"granted_at"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"` // Never, if not set
+	WithdrawnAt *time.Time `json:"withdrawn_at,omitempty"`
+	RecordedBy  string     `json:"recorded_by,omitempty"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Active      bool       `json:"active"` // Whether the consent is in force: given, and neither withdrawn nor expired
+}
+
+// ConsentRequest is the body of a request recording consent
+type ConsentRequest struct {
+	Reference string     `json:"reference,omitempty" example:"Form SW-12 #4471"`
+	GrantedAt *time.Time `json:"granted_at,omitempty"` // Defaults to now
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // Never, if not set
+}
+
+// CaseNote records an interaction with an applicant, such as a call or a
+// home visit. Notes are kept in the order written and are not edited.
+type CaseNote struct {
+	ID             string    `json:"id"`
+	ApplicantID    string    `json:"applicant_id"`
+	AuthorID       string    `json:"author_id,omitempty"`
+	AuthorUsername string    `json:"author_username,omitempty"`
+	Body           string    `json:"body" example:"Called to remind applicant to submit payslips"`
+	Tags           []string  `json:"tags" example:"phone-call,follow-up"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// CaseNoteRequest is the body of a request to add a case note
+type CaseNoteRequest struct {
+	Body string   `json:"body" example:"Called to remind applicant to submit payslips"`
+	Tags []string `json:"tags,omitempty" example:"phone-call,follow-up"`
+}
+
+// Comment is an internal comment on an application, for caseworkers and
+// admins to discuss it, with the replies to it. Unlike the application's
+// notes it is never shown to the applicant. Users are mentioned by writing
+// @ and their username.
+type Comment struct {
+	ID             string     `json:"id"`
+	ApplicationID  string     `json:"application_id"`
+	ParentID       string     `json:"parent_id,omitempty"` // The comment starting the thread, if this is a reply
+	AuthorID       string     `json:"author_id,omitempty"`
+	AuthorUsername string     `json:"author_username,omitempty"`
+	Body           string     `json:"body" example:"@caseworker can you check the payslip dates?"` // Empty once deleted
+	Mentions       []string   `json:"mentions" example:"caseworker"`                               // Usernames of the users mentioned
+	CreatedAt      time.Time  `json:"created_at"`
+	EditedAt       *time.Time `json:"edited_at,omitempty"`
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"` // Deleted comments are listed only while they have replies
+	Replies        []Comment  `json:"replies,omitempty"`    // Oldest first; set on the comments starting a thread when listed
+}
+
+// CommentRequest is the body of a request to add a comment
+type CommentRequest struct {
+	Body     string `json:"body" example:"@caseworker can you check the payslip dates?"`
+	ParentID string `json:"parent_id,omitempty"` // The comment to reply to
+}
+
+// CommentEditRequest is the body of a request to edit a comment
+type CommentEditRequest struct {
+	Body string `json:"body" example:"@caseworker can you check the payslip dates?"`
+}
+
+// Task statuses
+const (
+	TaskOpen      = "open"
+	TaskDone      = "done"
+	TaskCancelled = "cancelled"
+)
+
+// TaskStatuses lists the statuses a task can have
+var TaskStatuses = []string{TaskOpen, TaskDone, TaskCancelled}
+
+// Task is a follow-up to be done for an applicant, or one of their
+// applications, such as verifying a payslip by Friday
+type Task struct {
+	ID            string     `json:"id"`
+	ApplicantID   string     `json:"applicant_id"`
+	ApplicationID string     `json:"application_id,omitempty"`
+	Title         string     `json:"title" example:"Verify payslip"`
+	Description   string     `json:"description,omitempty" example:"Check the March payslip against the declared income"`
+	DueDate       *time.Time `json:"due_date,omitempty"`
+	AssignedTo    string     `json:"assigned_to,omitempty"` // User ID
+	Status        string     `json:"status" example:"open" enums:"open,done,cancelled"`
+	Overdue       bool       `json:"overdue"` // Whether the task is open and past its due date
+	CreatedBy     string     `json:"created_by,omitempty"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"` // When the task was done or cancelled
+	RemindedAt    *time.Time `json:"reminded_at,omitempty"`  // When the assignee was emailed a reminder
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// TaskRequest is the body of a request creating or replacing a task. A task
+// of an application belongs to the application's applicant, so applicant_id
+// may be omitted when application_id is set.
+type TaskRequest struct {
+	ApplicantID   string     `json:"applicant_id,omitempty"`
+	ApplicationID string     `json:"application_id,omitempty"`
+	Title         string     `json:"title" example:"Verify payslip"`
+	Description   string     `json:"description,omitempty" example:"Check the March payslip against the declared income"`
+	DueDate       *time.Time `json:"due_date,omitempty"`
+	AssignedTo    string     `json:"assigned_to,omitempty"`                                       // User ID of a caseworker or admin
+	Status        string     `json:"status,omitempty" example:"open" enums:"open,done,cancelled"` // Defaults to open
+}
+
+// Outcomes of a referral
+const (
+	ReferralPending   = "pending"
+	ReferralAccepted  = "accepted"
+	ReferralDeclined  = "declined"
+	ReferralCompleted = "completed"
+)
+
+// ReferralOutcomes lists every outcome a referral can have
+var ReferralOutcomes = []string{ReferralPending, ReferralAccepted, ReferralDeclined, ReferralCompleted}
+
+// Referral records that an applicant was referred to help delivered outside
+// this system, such as another agency's scheme, and what came of it
+type Referral struct {
+	ID           string     `json:"id"`
+	ApplicantID  string     `json:"applicant_id"`
+	ReferredBy   string     `json:"referred_by" example:"Family Service Centre (Ang Mo Kio)"` // The agency or officer making the referral
+	ReferredTo   string     `json:"referred_to" example:"Workforce Singapore"`                // The agency referred to
+	Scheme       string     `json:"scheme,omitempty" example:"Career Conversion Programme"`   // The agency's scheme or service, if any
+	Reason       string     `json:"reason" example:"Retrenched, looking to retrain"`
+	Outcome      string     `json:"outcome" example:"pending" enums:"pending,accepted,declined,completed"`
+	OutcomeNotes string     `json:"outcome_notes,omitempty"`
+	OutcomeAt    *time.Time `json:"outcome_at,omitempty"` // When the outcome was last set to other than pending
+	CreatedBy    string     `json:"created_by,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// ReferralRequest is the body of a request recording or replacing a referral
+type ReferralRequest struct {
+	ReferredBy   string `json:"referred_by,omitempty" example:"Family Service Centre (Ang Mo Kio)"` // Defaults to the authenticated user's username
+	ReferredTo   string `json:"referred_to" example:"Workforce Singapore"`
+	Scheme       string `json:"scheme,omitempty" example:"Career Conversion Programme"`
+	Reason       string `json:"reason" example:"Retrenched, looking to retrain"`
+	Outcome      string `json:"outcome,omitempty" example:"pending" enums:"pending,accepted,declined,completed"` // Defaults to pending
+	OutcomeNotes string `json:"outcome_notes,omitempty"`
+}
+
+// Types of custom field
+const (
+	CustomFieldString  = "string"
+	CustomFieldNumber  = "number"
+	CustomFieldBoolean = "boolean"
+	CustomFieldEnum    = "enum"
+)
+
+// CustomFieldTypes lists every type a custom field can have
+var CustomFieldTypes = []string{CustomFieldString, CustomFieldNumber, CustomFieldBoolean, CustomFieldEnum}
+
+// CustomFieldKeyPattern matches the keys of custom fields: a lowercase letter
+// followed by up to 49 lowercase letters, digits or underscores
+var CustomFieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// CustomField defines an attribute an agency records about applicants beyond
+// the built-in ones, such as their housing type or CHAS tier. Values are
+// checked against the definition whenever an applicant is saved.
+type CustomField struct {
+	Key       string    `json:"key" example:"housing_type"` // Lowercase letters, digits and underscores; cannot be changed
+	Label     string    `json:"label" example:"Housing type"`
+	Type      string    `json:"type" example:"enum" enums:"string,number,boolean,enum"`                 // Cannot be changed
+	Required  bool      `json:"required"`                                                               // Applicants cannot be saved without a value
+	Options   []string  `json:"options,omitempty" example:"hdb_1_2_room,hdb_3_room,hdb_4_room,private"` // Allowed values of enum fields
+	Min       *float64  `json:"min,omitempty"`                                                          // Smallest value of number fields
+	Max       *float64  `json:"max,omitempty"`                                                          // Largest value of number fields
+	MaxLength *int      `json:"max_length,omitempty"`                                                   // Longest value of string fields, in characters
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CustomValues holds an applicant's custom field values by key: strings for
+// string and enum fields, numbers and booleans
+type CustomValues map[string]interface{}
+
+// FeatureFlag is an admin's setting of a feature flag, which overrides the
+// flag's configured default until it is removed
+type FeatureFlag struct {
+	Name      string    `json:"name" example:"applicants.stream"`
+	Enabled   bool      `json:"enabled"`
+	Roles     []string  `json:"roles,omitempty" example:"admin,caseworker"` // Roles the flag is on for when enabled; every role if empty
+	UpdatedBy string    `json:"updated_by,omitempty"`                       // ID of the admin who last changed the setting
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// FeatureFlagRequest is the body of a request changing a feature flag
+type FeatureFlagRequest struct {
+	Enabled *bool    `json:"enabled"`                                    // Required
+	Roles   []string `json:"roles,omitempty" example:"admin,caseworker"` // Roles the flag is on for when enabled; every role if empty
+}
+
+// FeatureFlagState describes a feature flag and whether it is on
+type FeatureFlagState struct {
+	Name        string       `json:"name" example:"applicants.stream"`
+	Description string       `json:"description" example:"Serve GET /api/v1/applicants/stream"`
+	Default     bool         `json:"default"`                                    // Whether the flag is on when not overridden, as configured
+	Enabled     bool         `json:"enabled"`                                    // Whether the flag is on, for the roles listed if any
+	Roles       []string     `json:"roles,omitempty" example:"admin,caseworker"` // Roles the flag is on for; every role if empty
+	Override    *FeatureFlag `json:"override,omitempty"`                         // The admin's setting in effect, if any
+}
+
+// Job is a task run in the background by the job queue
+type Job struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type" example:"applicants.import"`
+	Status     string          `json:"status" example:"queued" enums:"queued,running,succeeded,failed"`
+	Payload    json.RawMessage `json:"-"`
+	Result     json.RawMessage `json:"result,omitempty" swaggertype:"object"` // Set once the job has succeeded
+	Attempts   int             `json:"attempts"`
+	LastError  string          `json:"last_error,omitempty"`
+	RunAt      time.Time       `json:"run_at"` // When a queued job is next due
+	CreatedBy  string          `json:"created_by,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	StartedAt  *time.Time      `json:"started_at,omitempty"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+// Actor identifies who performed a mutation
+type Actor struct {
+	ID       string
+	Username string
 }
 
 // UnmarshalJSON custom unmarshaler for Scheme to handle the JSON criteria field
@@ -118,10 +977,47 @@ type ApplicantResponse struct {
 	Household []HouseholdMember `json:"household"`
 }
 
+// MarshalJSON writes an applicant without household members with an empty
+// household rather than null
+func (r ApplicantResponse) MarshalJSON() ([]byte, error) {
+	type Alias ApplicantResponse
+	if r.Household == nil {
+		r.Household = []HouseholdMember{}
+	}
+	return json.Marshal(Alias(r))
+}
+
 // SchemeResponse is used for API responses that include benefits
 type SchemeResponse struct {
 	Scheme
-	Benefits []Benefit `json:"benefits"`
+	Benefits       []Benefit    `json:"benefits"`
+	ProjectedValue money.Amount `json:"projected_value" swaggertype:"string" example:"7200.00"` // Set when written, from the benefits
+}
+
+// MarshalJSON writes the scheme as Scheme.MarshalJSON does, which would
+// otherwise be promoted and omit the benefits when there are none, with the
+// benefits always present and their projected value
+func (r SchemeResponse) MarshalJSON() ([]byte, error) {
+	type Alias Scheme
+	criteriaJSON, err := json.Marshal(r.Criteria)
+	if err != nil {
+		return nil, err
+	}
+	benefits := r.Benefits
+	if benefits == nil {
+		benefits = []Benefit{}
+	}
+	return json.Marshal(&struct {
+		Criteria json.RawMessage `json:"criteria"`
+		*Alias
+		Benefits       []Benefit    `json:"benefits"`
+		ProjectedValue money.Amount `json:"projected_value"`
+	}{
+		Criteria:       criteriaJSON,
+		Alias:          (*Alias)(&r.Scheme),
+		Benefits:       benefits,
+		ProjectedValue: ProjectedValue(benefits),
+	})
 }
 
 // ApplicationRequest is used for creating a new application
@@ -131,15 +1027,128 @@ type ApplicationRequest struct {
 	Notes       string `json:"notes,omitempty"`
 }
 
-// ApplicationResponse is used for API responses
+// MergeRequest is used for merging a duplicate applicant into another
+type MergeRequest struct {
+	SourceID string `json:"source_id"`                                            // The duplicate, soft-deleted by the merge
+	Policy   string `json:"policy,omitempty" enums:"prefer_target,prefer_source"` // How to resolve differing fields; default prefer_target
+}
+
+// ImportApplicantsRequest is used for creating applicants in bulk
+type ImportApplicantsRequest struct {
+	Applicants []Applicant `json:"applicants"`
+}
+
+// ImportApplicantsResult is the result of an applicants.import job
+type ImportApplicantsResult struct {
+	Total      int             `json:"total"`
+	Created    int             `json:"created"`
+	CreatedIDs []string        `json:"created_ids"`
+	Failures   []ImportFailure `json:"failures"`
+}
+
+// ImportFailure describes an applicant that could not be imported
+type ImportFailure struct {
+	Index  int               `json:"index"` // Position in the request's applicants
+	Error  string            `json:"error"`
+	Fields map[string]string `json:"fields,omitempty"` // Invalid fields, for validation failures
+}
+
+// BatchEligibilityRequest is used for checking many applicants' eligibility
+type BatchEligibilityRequest struct {
+	ApplicantIDs []string   `json:"applicant_ids,omitempty"` // Every applicant if empty
+	AsOf         *time.Time `json:"as_of,omitempty"`         // Now if omitted
+}
+
+// BatchEligibilityResult is the result of an eligibility.batch job
+type BatchEligibilityResult struct {
+	AsOf       time.Time              `json:"as_of"`
+	Applicants []ApplicantEligibility `json:"applicants"`
+	NotFound   []string               `json:"not_found,omitempty"` // Requested applicant IDs that do not exist
+}
+
+// ApplicantEligibility lists the schemes an applicant is eligible for
+type ApplicantEligibility struct {
+	ApplicantID string   `json:"applicant_id"`
+	SchemeIDs   []string `json:"scheme_ids"`
+}
+
+// DecisionRequest is used for approving or rejecting an application
+type DecisionRequest struct {
+	Reason                   string        `json:"reason"`
+	RecommendedBenefitAmount *money.Amount `json:"recommended_benefit_amount,omitempty" swaggertype:"string" example:"500.00"` // Approvals only
+}
+
+// ApplicationResponse is used for API responses. Lists leave out the
+// applicant and scheme unless asked to expand them.
 type ApplicationResponse struct {
 	Application
-	Applicant ApplicantResponse `json:"applicant"`
-	Scheme    SchemeResponse    `json:"scheme"`
+	Applicant *ApplicantResponse `json:"applicant,omitempty"`
+	Scheme    *SchemeResponse    `json:"scheme,omitempty"`
+
+	// The applications this one resubmits, following previous_application_id
+	// back, newest first. Only single applications include them.
+	PreviousApplications []Application `json:"previous_applications,omitempty"`
+}
+
+// ApplicantProfile is everything known about an applicant, returned by the
+// one-client view
+type ApplicantProfile struct {
+	Applicant       ApplicantResponse    `json:"applicant"`
+	Applications    []ProfileApplication `json:"applications"`     // Newest first
+	EligibleSchemes []SchemeResponse     `json:"eligible_schemes"` // As of AsOf
+	CaseNotes       []CaseNote           `json:"case_notes"`       // Oldest first
+	Referrals       []Referral           `json:"referrals"`        // Newest first
+	Consents        []Consent            `json:"consents"`
+	AsOf            time.Time            `json:"as_of"`
+}
+
+// ProfileApplication is an application in an applicant's profile, with its
+// scheme and documents. The applicant is not repeated.
+type ProfileApplication struct {
+	Application
+	Scheme    SchemeResponse `json:"scheme"`
+	Documents []Document     `json:"documents"`
 }
 
 // EligibleSchemesResponse is used for returning eligible schemes for an applicant
 type EligibleSchemesResponse struct {
 	ApplicantID string           `json:"applicant_id"`
+	AsOf        time.Time        `json:"as_of"`
 	Schemes     []SchemeResponse `json:"schemes"`
 }
+
+// LoginRequest is used for authenticating a user
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// LoginResponse is returned on successful authentication
+type LoginResponse struct {
+	Token     string    `json:"token"`
+	TokenType string    `json:"token_type" example:"Bearer"`
+	ExpiresAt time.Time `json:"expires_at"`
+	User      User      `json:"user"`
+}
+
+// PortalCodeRequest is used by an applicant to ask for a one-time code to
+// sign in to the portal with
+type PortalCodeRequest struct {
+	IdentityNumber string `json:"identity_number" example:"S1234567D"`
+	Channel        string `json:"channel" enums:"email,sms" example:"email"` // Sent to the applicant's email address or phone
+}
+
+// PortalLoginRequest is used by an applicant to sign in to the portal with a
+// one-time code
+type PortalLoginRequest struct {
+	IdentityNumber string `json:"identity_number" example:"S1234567D"`
+	Code           string `json:"code" example:"123456"`
+}
+
+// PortalLoginResponse is returned when an applicant signs in to the portal
+type PortalLoginResponse struct {
+	Token       string    `json:"token"`
+	TokenType   string    `json:"token_type" example:"Bearer"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	ApplicantID string    `json:"applicant_id"`
+}
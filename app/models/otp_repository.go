@@ -0,0 +1,190 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Channels one-time codes are sent through
+const (
+	OTPChannelEmail = "email"
+	OTPChannelSMS   = "sms"
+)
+
+// OTPChannels lists the channels one-time codes can be sent through
+var OTPChannels = []string{OTPChannelEmail, OTPChannelSMS}
+
+// ErrOTPUnavailable is returned when a one-time code has expired, has had
+// too many attempts or has already been used
+var ErrOTPUnavailable = errors.New("one-time code is no longer valid")
+
+// ErrPortalLocked is returned when an applicant has made too many attempts
+// to sign in to the portal without succeeding
+var ErrPortalLocked = errors.New("portal sign-in is locked")
+
+// ApplicantOTP is a one-time code an applicant can sign in to the portal
+// with. Only a hash of the code is kept.
+type ApplicantOTP struct {
+	ID          string
+	ApplicantID string
+	Channel     string
+	CodeHash    string
+	Attempts    int // Codes entered against it, right or wrong
+	ExpiresAt   time.Time
+	CreatedAt   time.Time
+}
+
+// ApplicantOTPRepository handles database operations for applicants'
+// one-time codes
+type ApplicantOTPRepository struct {
+	DB *sql.DB
+	tx *sql.Tx
+}
+
+// NewApplicantOTPRepository creates a new repository with the given database connection
+func NewApplicantOTPRepository(db *sql.DB) *ApplicantOTPRepository {
+	return &ApplicantOTPRepository{DB: db}
+}
+
+// WithTx returns a copy of the repository that runs its queries in tx
+func (r *ApplicantOTPRepository) WithTx(tx *sql.Tx) *ApplicantOTPRepository {
+	return &ApplicantOTPRepository{DB: r.DB, tx: tx}
+}
+
+// conn returns the transaction the repository is bound to, or the database
+func (r *ApplicantOTPRepository) conn() DBTX {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.DB
+}
+
+// GetByApplicantID retrieves the applicant's current code, expired or not,
+// or nil if there is none
+func (r *ApplicantOTPRepository) GetByApplicantID(applicantID string) (*ApplicantOTP, error) {
+	query := `SELECT id, applicant_id, channel, code_hash, attempts, expires_at, created_at
+			  FROM applicant_otps
+			  WHERE applicant_id = ?`
+
+	var o ApplicantOTP
+	err := r.conn().QueryRow(query, applicantID).Scan(&o.ID, &o.ApplicantID, &o.Channel, &o.CodeHash,
+		&o.Attempts, &o.ExpiresAt, &o.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error querying one-time code: %v", err)
+	}
+	return &o, nil
+}
+
+// Replace stores a new code for the applicant in place of any they had. It
+// runs two statements, so should be called in a transaction.
+func (r *ApplicantOTPRepository) Replace(o *ApplicantOTP) error {
+	if o.ID == "" {
+		o.ID = uuid.New().String()
+	}
+	o.CreatedAt = time.Now()
+
+	if _, err := r.conn().Exec(`DELETE FROM applicant_otps WHERE applicant_id = ?`, o.ApplicantID); err != nil {
+		return fmt.Errorf("error deleting one-time code: %v", err)
+	}
+
+	query := `INSERT INTO applicant_otps (id, applicant_id, channel, code_hash, attempts, expires_at, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.conn().Exec(query, o.ID, o.ApplicantID, o.Channel, o.CodeHash, o.Attempts, o.ExpiresAt, o.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating one-time code: %v", err)
+	}
+	return nil
+}
+
+// RecordAttempt counts an attempt to enter o's code, before the code is
+// checked, failing with ErrOTPUnavailable if maxAttempts have already been
+// made, the code has expired at now or it has been used. The count is checked
+// and raised in one statement, so concurrent attempts cannot exceed
+// maxAttempts.
+func (r *ApplicantOTPRepository) RecordAttempt(o *ApplicantOTP, maxAttempts int, now time.Time) error {
+	query := `UPDATE applicant_otps SET attempts = attempts + 1
+			  WHERE id = ? AND attempts < ? AND expires_at > ?`
+
+	result, err := r.conn().Exec(query, o.ID, maxAttempts, now)
+	if err != nil {
+		return fmt.Errorf("error updating one-time code: %v", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %v", err)
+	}
+	if rows == 0 {
+		return ErrOTPUnavailable
+	}
+	o.Attempts++
+	return nil
+}
+
+// Delete removes a code once it has been used, failing with
+// ErrOTPUnavailable if another request already has, so that each code is
+// only used once
+func (r *ApplicantOTPRepository) Delete(id string) error {
+	result, err := r.conn().Exec(`DELETE FROM applicant_otps WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting one-time code: %v", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %v", err)
+	}
+	if rows == 0 {
+		return ErrOTPUnavailable
+	}
+	return nil
+}
+
+// RecordLoginAttempt counts an attempt by the applicant to sign in to the
+// portal, before any code is checked, failing with ErrPortalLocked if
+// maxFailures attempts have been made since they last signed in. The count
+// is kept on the applicant, so requesting a new code does not reset it; it
+// starts again from zero window after the first attempt counted. As with
+// RecordAttempt, the count is checked and raised in one statement.
+func (r *ApplicantOTPRepository) RecordLoginAttempt(applicantID string, maxFailures int, window time.Duration, now time.Time) error {
+	// MySQL sets updated_at on every update unless it is assigned, and
+	// counting attempts does not change the applicant
+	query := `UPDATE applicants SET
+			      portal_failures = CASE WHEN portal_failures_reset_at IS NULL OR portal_failures_reset_at <= ?
+			                        THEN 1 ELSE portal_failures + 1 END,
+			      portal_failures_reset_at = CASE WHEN portal_failures_reset_at IS NULL OR portal_failures_reset_at <= ?
+			                                 THEN ? ELSE portal_failures_reset_at END,
+			      updated_at = updated_at
+			  WHERE id = ? AND (portal_failures < ? OR portal_failures_reset_at IS NULL OR portal_failures_reset_at <= ?)`
+
+	result, err := r.conn().Exec(query, now, now, now.Add(window), applicantID, maxFailures, now)
+	if err != nil {
+		return fmt.Errorf("error counting sign-in attempt: %v", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking rows affected: %v", err)
+	}
+	if rows == 0 {
+		return ErrPortalLocked
+	}
+	return nil
+}
+
+// ResetLoginAttempts clears the count of the applicant's attempts to sign in
+// to the portal once they have signed in
+func (r *ApplicantOTPRepository) ResetLoginAttempts(applicantID string) error {
+	query := `UPDATE applicants SET portal_failures = 0, portal_failures_reset_at = NULL, updated_at = updated_at
+			  WHERE id = ?`
+
+	if _, err := r.conn().Exec(query, applicantID); err != nil {
+		return fmt.Errorf("error resetting sign-in attempts: %v", err)
+	}
+	return nil
+}
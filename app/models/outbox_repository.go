@@ -0,0 +1,154 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxRepository handles database operations for the outbox_events table,
+// which holds domain events until a relay publishes them to a message broker
+type OutboxRepository struct {
+	DB *sql.DB
+}
+
+// NewOutboxRepository creates a new repository with the given database connection
+func NewOutboxRepository(db *sql.DB) *OutboxRepository {
+	return &OutboxRepository{DB: db}
+}
+
+// createOutboxEvent adds an event, marshaled as body, to the outbox on the
+// connection or transaction of the change it describes. The entity ID is
+// taken from the id field of the event's data, if it has one.
+func createOutboxEvent(conn DBTX, payload WebhookEvent, body []byte) error {
+	var entity struct {
+		ID string `json:"id"`
+	}
+	if data, err := json.Marshal(payload.Data); err == nil {
+		_ = json.Unmarshal(data, &entity) // Data that is not an object has no ID
+	}
+
+	query := `INSERT INTO outbox_events (id, event, entity_id, payload, created_at)
+			  VALUES (?, ?, ?, ?, ?)`
+
+	_, err := conn.Exec(query, payload.ID, payload.Event, nullString(entity.ID), body, payload.OccurredAt)
+	if err != nil {
+		return fmt.Errorf("error adding event to outbox: %v", err)
+	}
+	return nil
+}
+
+// Claim claims up to limit of the oldest events for the given lease, so that
+// other relays leave them alone, and returns them oldest first with the claim
+// token to delete or release them with. Nothing is claimed while another
+// relay holds a live claim, so events are published in order.
+func (r *OutboxRepository) Claim(limit int, lease time.Duration) ([]OutboxEvent, string, error) {
+	now := time.Now()
+
+	var claimed int
+	if err := r.DB.QueryRow(`SELECT COUNT(*) FROM outbox_events WHERE claimed_until > ?`, now).Scan(&claimed); err != nil {
+		return nil, "", fmt.Errorf("error checking outbox claims: %v", err)
+	}
+	if claimed > 0 {
+		return nil, "", nil
+	}
+
+	rows, err := r.DB.Query(`SELECT sequence FROM outbox_events ORDER BY sequence ASC LIMIT ?`, limit)
+	if err != nil {
+		return nil, "", fmt.Errorf("error querying outbox: %v", err)
+	}
+	var sequences []string
+	for rows.Next() {
+		var sequence string
+		if err := rows.Scan(&sequence); err != nil {
+			rows.Close()
+			return nil, "", fmt.Errorf("error scanning outbox row: %v", err)
+		}
+		sequences = append(sequences, sequence)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("error iterating outbox rows: %v", err)
+	}
+	if len(sequences) == 0 {
+		return nil, "", nil
+	}
+
+	// Another relay claiming any of the events at the same time wins them
+	token := uuid.New().String()
+	placeholders, args := inClause(sequences)
+	result, err := r.DB.Exec(`UPDATE outbox_events SET claimed_by = ?, claimed_until = ?
+			  WHERE sequence IN (`+placeholders+`) AND (claimed_until IS NULL OR claimed_until <= ?)`,
+		append(append([]interface{}{token, now.Add(lease)}, args...), now)...)
+	if err != nil {
+		return nil, "", fmt.Errorf("error claiming outbox events: %v", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, "", fmt.Errorf("error claiming outbox events: %v", err)
+	}
+	if int(affected) < len(sequences) {
+		// Publishing only some would reorder them
+		return nil, "", r.Release(token)
+	}
+
+	events, err := r.claimed(token)
+	if err != nil {
+		return nil, "", err
+	}
+	return events, token, nil
+}
+
+// claimed retrieves the events claimed with token, oldest first
+func (r *OutboxRepository) claimed(token string) ([]OutboxEvent, error) {
+	query := `SELECT sequence, id, event, entity_id, payload, created_at
+			  FROM outbox_events
+			  WHERE claimed_by = ?
+			  ORDER BY sequence ASC`
+
+	rows, err := r.DB.Query(query, token)
+	if err != nil {
+		return nil, fmt.Errorf("error querying outbox: %v", err)
+	}
+	defer rows.Close()
+
+	var events []OutboxEvent
+	for rows.Next() {
+		var e OutboxEvent
+		var entityID sql.NullString
+		var payload []byte
+		if err := rows.Scan(&e.Sequence, &e.ID, &e.Event, &entityID, &payload, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("error scanning outbox row: %v", err)
+		}
+		e.EntityID = entityID.String
+		e.Payload = payload
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating outbox rows: %v", err)
+	}
+
+	return events, nil
+}
+
+// Delete removes the events claimed with token, once they are published
+func (r *OutboxRepository) Delete(token string) error {
+	if _, err := r.DB.Exec(`DELETE FROM outbox_events WHERE claimed_by = ?`, token); err != nil {
+		return fmt.Errorf("error deleting outbox events: %v", err)
+	}
+	return nil
+}
+
+// Release gives up the claim made with token, so the events can be claimed
+// again straight away
+func (r *OutboxRepository) Release(token string) error {
+	if _, err := r.DB.Exec(`UPDATE outbox_events SET claimed_by = NULL, claimed_until = NULL WHERE claimed_by = ?`,
+		token); err != nil {
+		return fmt.Errorf("error releasing outbox events: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// PhotoRepository handles database operations for the metadata of
+// applicants' photos. The images themselves are kept in a storage.Store.
+type PhotoRepository struct {
+	DB *sql.DB
+	tx *sql.Tx
+}
+
+// NewPhotoRepository creates a new repository with the given database connection
+func NewPhotoRepository(db *sql.DB) *PhotoRepository {
+	return &PhotoRepository{DB: db}
+}
+
+// WithTx returns a copy of the repository that runs its queries in tx
+func (r *PhotoRepository) WithTx(tx *sql.Tx) *PhotoRepository {
+	return &PhotoRepository{DB: r.DB, tx: tx}
+}
+
+// conn returns the transaction the repository is bound to, or the database
+func (r *PhotoRepository) conn() DBTX {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.DB
+}
+
+// GetByApplicantID retrieves an applicant's photo, or nil if they have none
+func (r *PhotoRepository) GetByApplicantID(applicantID string) (*ApplicantPhoto, error) {
+	query := `SELECT applicant_id, content_type, size, width, height, storage_key,
+			         thumbnail_key, thumbnail_content_type, uploaded_by, created_at
+			  FROM applicant_photos
+			  WHERE applicant_id = ?`
+
+	var p ApplicantPhoto
+	var uploadedBy sql.NullString
+	err := r.conn().QueryRow(query, applicantID).Scan(&p.ApplicantID, &p.ContentType, &p.Size,
+		&p.Width, &p.Height, &p.StorageKey, &p.ThumbnailKey, &p.ThumbnailContentType,
+		&uploadedBy, &p.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // No photo found
+		}
+		return nil, fmt.Errorf("error getting applicant photo: %v", err)
+	}
+	p.UploadedBy = uploadedBy.String
+
+	return &p, nil
+}
+
+// Put saves the metadata of an applicant's photo, replacing any they had.
+// Callers should run it in a transaction with the read of the previous
+// photo, whose stored images are then theirs to delete.
+func (r *PhotoRepository) Put(p *ApplicantPhoto) error {
+	p.CreatedAt = time.Now()
+
+	if err := r.Delete(p.ApplicantID); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO applicant_photos (applicant_id, content_type, size, width, height, storage_key,
+			                                thumbnail_key, thumbnail_content_type, uploaded_by, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.conn().Exec(query, p.ApplicantID, p.ContentType, p.Size, p.Width, p.Height,
+		p.StorageKey, p.ThumbnailKey, p.ThumbnailContentType, nullString(p.UploadedBy), p.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("error saving applicant photo: %v", err)
+	}
+
+	return nil
+}
+
+// Delete removes the metadata of an applicant's photo
+func (r *PhotoRepository) Delete(applicantID string) error {
+	query := `DELETE FROM applicant_photos WHERE applicant_id = ?`
+	_, err := r.conn().Exec(query, applicantID)
+	if err != nil {
+		return fmt.Errorf("error deleting applicant photo: %v", err)
+	}
+	return nil
+}
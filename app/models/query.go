@@ -0,0 +1,80 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrVersionConflict is returned when updating a record whose stored version
+// no longer matches the version the caller read
+var ErrVersionConflict = errors.New("record was modified by another request")
+
+// checkVersioned returns ErrVersionConflict if a versioned UPDATE, guarded by
+// "WHERE id = ? AND version = ?", matched no rows
+func checkVersioned(result sql.Result) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error checking updated rows: %v", err)
+	}
+	if n == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// inClause returns the placeholder list and arguments for an IN (...) condition
+// over the given IDs
+func inClause(ids []string) (string, []interface{}) {
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	return strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", "), args
+}
+
+// uniqueIDs returns the distinct non-empty IDs in order of first appearance
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	var unique []string
+	for _, id := range ids {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// isUniqueViolation reports whether err is a unique index violation from
+// MySQL or SQLite
+func isUniqueViolation(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "Error 1062") || strings.Contains(msg, "UNIQUE constraint failed")
+}
+
+// nullString converts an empty string to a NULL column value
+func nullString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// nullJSON converts an empty JSON document to a NULL column value
+func nullJSON(data json.RawMessage) interface{} {
+	if data == nil {
+		return nil
+	}
+	return []byte(data)
+}
@@ -0,0 +1,159 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReferralRepository handles database operations for referrals
+type ReferralRepository struct {
+	DB *sql.DB
+	tx *sql.Tx
+}
+
+// NewReferralRepository creates a new repository with the given database connection
+func NewReferralRepository(db *sql.DB) *ReferralRepository {
+	return &ReferralRepository{DB: db}
+}
+
+// WithTx returns a copy of the repository that runs its queries in tx
+func (r *ReferralRepository) WithTx(tx *sql.Tx) *ReferralRepository {
+	return &ReferralRepository{DB: r.DB, tx: tx}
+}
+
+// conn returns the transaction the repository is bound to, or the database
+func (r *ReferralRepository) conn() DBTX {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.DB
+}
+
+// referralColumns is the column list read by scanReferral
+const referralColumns = `id, applicant_id, referred_by, referred_to, scheme, reason, outcome, outcome_notes,
+	outcome_at, created_by, created_at, updated_at`
+
+// scanReferral scans a row selected with referralColumns
+func scanReferral(row rowScanner) (Referral, error) {
+	var f Referral
+	var scheme, outcomeNotes, createdBy sql.NullString
+	var outcomeAt sql.NullTime
+
+	if err := row.Scan(&f.ID, &f.ApplicantID, &f.ReferredBy, &f.ReferredTo, &scheme, &f.Reason, &f.Outcome,
+		&outcomeNotes, &outcomeAt, &createdBy, &f.CreatedAt, &f.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return f, err
+		}
+		return f, fmt.Errorf("error scanning referral row: %v", err)
+	}
+	f.Scheme = scheme.String
+	f.OutcomeNotes = outcomeNotes.String
+	f.CreatedBy = createdBy.String
+	if outcomeAt.Valid {
+		f.OutcomeAt = &outcomeAt.Time
+	}
+
+	return f, nil
+}
+
+// GetByApplicantID retrieves the referrals of an applicant, newest first. If
+// outcome is not empty, only referrals with that outcome are returned.
+func (r *ReferralRepository) GetByApplicantID(applicantID, outcome string) ([]Referral, error) {
+	query := `SELECT ` + referralColumns + `
+			  FROM referrals
+			  WHERE applicant_id = ?`
+	args := []interface{}{applicantID}
+	if outcome != "" {
+		query += ` AND outcome = ?`
+		args = append(args, outcome)
+	}
+	query += ` ORDER BY created_at DESC, id DESC`
+
+	rows, err := r.conn().Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying referrals: %v", err)
+	}
+	defer rows.Close()
+
+	var referrals []Referral
+	for rows.Next() {
+		f, err := scanReferral(rows)
+		if err != nil {
+			return nil, err
+		}
+		referrals = append(referrals, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating referral rows: %v", err)
+	}
+
+	return referrals, nil
+}
+
+// GetByID retrieves a referral by ID
+func (r *ReferralRepository) GetByID(id string) (*Referral, error) {
+	query := `SELECT ` + referralColumns + ` FROM referrals WHERE id = ?`
+
+	f, err := scanReferral(r.conn().QueryRow(query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &f, nil
+}
+
+// Create inserts a new referral
+func (r *ReferralRepository) Create(f *Referral) error {
+	if f.ID == "" {
+		f.ID = uuid.New().String()
+	}
+	now := time.Now()
+	f.CreatedAt = now
+	f.UpdatedAt = now
+
+	query := `INSERT INTO referrals (id, applicant_id, referred_by, referred_to, scheme, reason, outcome, outcome_notes,
+			  outcome_at, created_by, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.conn().Exec(query, f.ID, f.ApplicantID, f.ReferredBy, f.ReferredTo, nullString(f.Scheme), f.Reason,
+		f.Outcome, nullString(f.OutcomeNotes), f.OutcomeAt, nullString(f.CreatedBy), f.CreatedAt, f.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating referral: %v", err)
+	}
+
+	return nil
+}
+
+// Update saves every field of a referral except its applicant and who created
+// it and when
+func (r *ReferralRepository) Update(f *Referral) error {
+	f.UpdatedAt = time.Now()
+
+	query := `UPDATE referrals
+			  SET referred_by = ?, referred_to = ?, scheme = ?, reason = ?, outcome = ?, outcome_notes = ?,
+			  outcome_at = ?, updated_at = ?
+			  WHERE id = ?`
+
+	_, err := r.conn().Exec(query, f.ReferredBy, f.ReferredTo, nullString(f.Scheme), f.Reason, f.Outcome,
+		nullString(f.OutcomeNotes), f.OutcomeAt, f.UpdatedAt, f.ID)
+	if err != nil {
+		return fmt.Errorf("error updating referral: %v", err)
+	}
+
+	return nil
+}
+
+// Delete removes a referral
+func (r *ReferralRepository) Delete(id string) error {
+	if _, err := r.conn().Exec(`DELETE FROM referrals WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("error deleting referral: %v", err)
+	}
+	return nil
+}
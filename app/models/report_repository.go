@@ -0,0 +1,458 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"one-client-view-2025tht/app/money"
+)
+
+// ReportTotals aggregates a set of applications
+type ReportTotals struct {
+	Total               int            `json:"total"`
+	ByStatus            map[string]int `json:"by_status" example:"pending:3,approved:5,rejected:2"`
+	Decided             int            `json:"decided"`                                                     // Applications with a decision date
+	AverageDecisionDays *float64       `json:"average_decision_days" example:"4.5"`                         // Mean days from application to decision; null if none decided
+	RecommendedBenefits money.Amount   `json:"recommended_benefits" swaggertype:"string" example:"1500.00"` // Sum of recommended benefit amounts of approved applications
+	SchemeBenefits      money.Amount   `json:"scheme_benefits" swaggertype:"string" example:"2400.00"`      // Sum over approved applications of the projected value of their scheme's current benefits
+}
+
+// SchemeSummary aggregates the applications for one scheme
+type SchemeSummary struct {
+	SchemeID   string `json:"scheme_id"`
+	SchemeName string `json:"scheme_name"`
+	ReportTotals
+}
+
+// MonthSummary counts the applications made in one calendar month (UTC)
+type MonthSummary struct {
+	Month    string         `json:"month" example:"2026-10"`
+	Total    int            `json:"total"`
+	ByStatus map[string]int `json:"by_status" example:"pending:3,approved:5,rejected:2"`
+}
+
+// ApplicationsSummary aggregates applications overall, by scheme and by month
+type ApplicationsSummary struct {
+	ReportTotals
+	ByScheme []SchemeSummary `json:"by_scheme"`
+	ByMonth  []MonthSummary  `json:"by_month"`
+}
+
+// SchemeReport aggregates the applications for one scheme, overall and by
+// month
+type SchemeReport struct {
+	SchemeSummary
+	ByMonth []MonthSummary `json:"by_month"`
+}
+
+// CaseworkerWorkload counts the applications assigned to and decided by one
+// user
+type CaseworkerWorkload struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	Role     string `json:"role" example:"caseworker" enums:"admin,caseworker"`
+	Pending  int    `json:"pending"`  // Pending applications assigned to the user
+	Approved int    `json:"approved"` // Applications the user approved
+	Rejected int    `json:"rejected"` // Applications the user rejected
+}
+
+// WorkloadReport counts the applications awaiting and given review by each
+// user who can decide them
+type WorkloadReport struct {
+	Unassigned  int                  `json:"unassigned"` // Pending applications assigned to no one
+	Caseworkers []CaseworkerWorkload `json:"caseworkers"`
+}
+
+// SchemeCoverage compares the registered applicants eligible for a scheme
+// with those who have applied for it
+type SchemeCoverage struct {
+	SchemeID           string    `json:"scheme_id"`
+	SchemeName         string    `json:"scheme_name"`
+	AsOf               time.Time `json:"as_of"`
+	SchemeOpen         bool      `json:"scheme_open"`                // Whether the scheme accepts applications at as_of; no applicant is eligible for a closed scheme
+	Applicants         int       `json:"applicants"`                 // Registered applicants assessed
+	EligibleApplicants int       `json:"eligible_applicants"`        // Applicants meeting the scheme's criteria at as_of
+	AppliedApplicants  int       `json:"applied_applicants"`         // Applicants with an application for the scheme, whatever its status
+	EligibleApplied    int       `json:"eligible_applied"`           // Eligible applicants who have applied
+	EligibleNotApplied int       `json:"eligible_not_applied"`       // Eligible applicants who have not applied
+	UptakeRate         *float64  `json:"uptake_rate" example:"0.42"` // eligible_applied / eligible_applicants; null if none are eligible
+}
+
+// Groupings of the geographic report
+const (
+	GroupByDistrict = "district"
+	GroupByRegion   = "region"
+)
+
+// GeographicArea counts the applications made by applicants living in one
+// postal district or planning region
+type GeographicArea struct {
+	Code         string         `json:"code" example:"20"`                                   // Postal district, "01" to "28", or region code
+	Name         string         `json:"name" example:"Bishan, Ang Mo Kio"`                   // General locations of the district, or name of the region
+	Region       string         `json:"region,omitempty" example:"north_east"`               // For a district, the planning region covering most of it
+	Applications int            `json:"applications"`                                        // Applications matching the filters
+	Approved     int            `json:"approved"`                                            // Of them, those approved
+	ByStatus     map[string]int `json:"by_status" example:"pending:3,approved:5,rejected:2"` // Applications by status
+	Applicants   int            `json:"applicants"`                                          // Distinct applicants making them
+	Longitude    float64        `json:"longitude" example:"103.846"`                         // Approximate centre, for plotting
+	Latitude     float64        `json:"latitude" example:"1.362"`
+}
+
+// GeographicReport counts applications by where their applicants live
+type GeographicReport struct {
+	GroupBy   string           `json:"group_by" example:"district" enums:"district,region"`
+	Areas     []GeographicArea `json:"areas"`     // Every district or region in order of code, including those without applications
+	Unlocated int              `json:"unlocated"` // Applications by applicants without a known postal district
+}
+
+// ReportRepository computes aggregate statistics over applications
+type ReportRepository struct {
+	DB *sql.DB
+	// Driver is the database driver in use, as date arithmetic is not
+	// portable: "mysql" or "sqlite"
+	Driver string
+}
+
+// NewReportRepository creates a new repository with the given database
+// connection and driver name
+func NewReportRepository(db *sql.DB, driver string) *ReportRepository {
+	return &ReportRepository{DB: db, Driver: driver}
+}
+
+// monthExpr returns the SQL expression formatting a time column as YYYY-MM
+func (r *ReportRepository) monthExpr(column string) string {
+	if r.Driver == "sqlite" {
+		return "strftime('%Y-%m', " + column + ")"
+	}
+	return "DATE_FORMAT(" + column + ", '%Y-%m')"
+}
+
+// secondsBetweenExpr returns the SQL expression for the seconds from one time
+// column to another, NULL if either is NULL
+func (r *ReportRepository) secondsBetweenExpr(from, to string) string {
+	if r.Driver == "sqlite" {
+		return "((julianday(" + to + ") - julianday(" + from + ")) * 86400)"
+	}
+	return "TIMESTAMPDIFF(SECOND, " + from + ", " + to + ")"
+}
+
+// totalsAccumulator sums grouped rows into ReportTotals
+type totalsAccumulator struct {
+	totals          ReportTotals
+	decisionSeconds float64
+}
+
+// add counts a group of applications with the same status
+func (acc *totalsAccumulator) add(status string, count, decided int, decisionSeconds float64, recommended, schemeBenefits money.Amount) {
+	if acc.totals.ByStatus == nil {
+		acc.totals.ByStatus = make(map[string]int)
+	}
+	acc.totals.Total += count
+	acc.totals.ByStatus[status] += count
+	acc.totals.Decided += decided
+	acc.decisionSeconds += decisionSeconds
+	if status == "approved" {
+		acc.totals.RecommendedBenefits += recommended
+		acc.totals.SchemeBenefits += schemeBenefits
+	}
+}
+
+// result returns the totals with the average decision time computed
+func (acc *totalsAccumulator) result() ReportTotals {
+	totals := acc.totals
+	if totals.ByStatus == nil {
+		totals.ByStatus = map[string]int{}
+	}
+	if totals.Decided > 0 {
+		days := acc.decisionSeconds / float64(totals.Decided) / 86400
+		totals.AverageDecisionDays = &days
+	}
+	return totals
+}
+
+// ApplicationsSummary aggregates the applications matching the filter overall,
+// by scheme ordered by name, and by month of application
+func (r *ReportRepository) ApplicationsSummary(filter ApplicationFilter) (*ApplicationsSummary, error) {
+	overall, schemes, err := r.schemeTotals(filter)
+	if err != nil {
+		return nil, err
+	}
+	months, err := r.monthSummaries(filter)
+	if err != nil {
+		return nil, err
+	}
+	return &ApplicationsSummary{ReportTotals: overall, ByScheme: schemes, ByMonth: months}, nil
+}
+
+// SchemeReport aggregates the applications for a scheme matching the filter,
+// overall and by month of application. The filter's SchemeID is replaced with
+// the scheme's.
+func (r *ReportRepository) SchemeReport(scheme *Scheme, filter ApplicationFilter) (*SchemeReport, error) {
+	filter.SchemeID = scheme.ID
+	overall, _, err := r.schemeTotals(filter)
+	if err != nil {
+		return nil, err
+	}
+	months, err := r.monthSummaries(filter)
+	if err != nil {
+		return nil, err
+	}
+	return &SchemeReport{
+		SchemeSummary: SchemeSummary{SchemeID: scheme.ID, SchemeName: scheme.Name, ReportTotals: overall},
+		ByMonth:       months,
+	}, nil
+}
+
+// schemeTotals aggregates the applications matching the filter by scheme and
+// status in the database, and combines the groups into totals per scheme and
+// overall. Decision times are measured from when applications were created,
+// as their application dates have no time of day.
+func (r *ReportRepository) schemeTotals(filter ApplicationFilter) (ReportTotals, []SchemeSummary, error) {
+	where, args := filter.whereClause()
+	query := `SELECT a.scheme_id, COALESCE(s.name, ''), a.status,
+				  COUNT(*), COUNT(a.decision_date),
+				  COALESCE(SUM(` + r.secondsBetweenExpr("a.created_at", "a.decision_date") + `), 0),
+				  COALESCE(SUM(a.recommended_benefit_amount_cents), 0),
+				  COALESCE(SUM(b.amount_cents), 0)
+			  FROM (SELECT * FROM applications` + where + `) a
+			  LEFT JOIN schemes s ON s.id = a.scheme_id
+			  LEFT JOIN (SELECT scheme_id, SUM(` + benefitProjectedValueExpr + `) AS amount_cents
+			      FROM benefits GROUP BY scheme_id) b ON b.scheme_id = a.scheme_id
+			  GROUP BY a.scheme_id, s.name, a.status
+			  ORDER BY s.name ASC, a.scheme_id ASC`
+
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return ReportTotals{}, nil, fmt.Errorf("error querying application totals: %v", err)
+	}
+	defer rows.Close()
+
+	var overall totalsAccumulator
+	var schemes []SchemeSummary
+	var accumulators []*totalsAccumulator
+	for rows.Next() {
+		var schemeID, schemeName, status string
+		var count, decided int
+		var decisionSeconds float64
+		var recommended, schemeBenefits money.Amount
+		if err := rows.Scan(&schemeID, &schemeName, &status, &count, &decided,
+			&decisionSeconds, &recommended, &schemeBenefits); err != nil {
+			return ReportTotals{}, nil, fmt.Errorf("error scanning application totals row: %v", err)
+		}
+
+		// Rows are ordered by scheme, so each scheme's groups are adjacent
+		if len(schemes) == 0 || schemes[len(schemes)-1].SchemeID != schemeID {
+			schemes = append(schemes, SchemeSummary{SchemeID: schemeID, SchemeName: schemeName})
+			accumulators = append(accumulators, &totalsAccumulator{})
+		}
+		accumulators[len(accumulators)-1].add(status, count, decided, decisionSeconds, recommended, schemeBenefits)
+		overall.add(status, count, decided, decisionSeconds, recommended, schemeBenefits)
+	}
+	if err := rows.Err(); err != nil {
+		return ReportTotals{}, nil, fmt.Errorf("error iterating application totals rows: %v", err)
+	}
+
+	schemeSummaries := make([]SchemeSummary, len(schemes))
+	for i, s := range schemes {
+		s.ReportTotals = accumulators[i].result()
+		schemeSummaries[i] = s
+	}
+	return overall.result(), schemeSummaries, nil
+}
+
+// monthSummaries counts the applications matching the filter by month of
+// application and status, oldest month first
+func (r *ReportRepository) monthSummaries(filter ApplicationFilter) ([]MonthSummary, error) {
+	where, args := filter.whereClause()
+	month := r.monthExpr("application_date")
+	query := `SELECT ` + month + `, status, COUNT(*)
+			  FROM applications` + where + `
+			  GROUP BY ` + month + `, status
+			  ORDER BY ` + month + ` ASC`
+
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying monthly application counts: %v", err)
+	}
+	defer rows.Close()
+
+	months := []MonthSummary{}
+	for rows.Next() {
+		var name, status string
+		var count int
+		if err := rows.Scan(&name, &status, &count); err != nil {
+			return nil, fmt.Errorf("error scanning monthly application counts row: %v", err)
+		}
+		if len(months) == 0 || months[len(months)-1].Month != name {
+			months = append(months, MonthSummary{Month: name, ByStatus: make(map[string]int)})
+		}
+		m := &months[len(months)-1]
+		m.Total += count
+		m.ByStatus[status] += count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating monthly application counts rows: %v", err)
+	}
+	return months, nil
+}
+
+// SchemeApplicantIDs returns the set of registered applicants with an
+// application for the scheme, whatever its status. Soft-deleted applications
+// and applicants are not counted.
+func (r *ReportRepository) SchemeApplicantIDs(schemeID string) (map[string]bool, error) {
+	query := `SELECT DISTINCT a.applicant_id
+			  FROM applications a
+			  JOIN applicants p ON p.id = a.applicant_id
+			  WHERE a.scheme_id = ? AND a.deleted_at IS NULL AND p.deleted_at IS NULL`
+
+	rows, err := r.DB.Query(query, schemeID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying scheme applicants: %v", err)
+	}
+	defer rows.Close()
+
+	ids := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("error scanning scheme applicant row: %v", err)
+		}
+		ids[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating scheme applicant rows: %v", err)
+	}
+	return ids, nil
+}
+
+// Workload counts, for every caseworker and admin, the pending applications
+// matching the filter assigned to them and those they approved and rejected,
+// with the number of pending applications assigned to no one. Users without
+// any are listed with zero counts.
+func (r *ReportRepository) Workload(filter ApplicationFilter) (*WorkloadReport, error) {
+	where, args := filter.whereClause()
+	query := `SELECT u.id, u.username, u.role,
+				  COALESCE(SUM(CASE WHEN a.status = 'pending' AND a.assigned_to = u.id THEN 1 ELSE 0 END), 0),
+				  COALESCE(SUM(CASE WHEN a.status = 'approved' AND a.decided_by = u.id THEN 1 ELSE 0 END), 0),
+				  COALESCE(SUM(CASE WHEN a.status = 'rejected' AND a.decided_by = u.id THEN 1 ELSE 0 END), 0)
+			  FROM users u
+			  LEFT JOIN (SELECT * FROM applications` + where + `) a ON a.assigned_to = u.id OR a.decided_by = u.id
+			  WHERE u.role IN ('admin', 'caseworker')
+			  GROUP BY u.id, u.username, u.role
+			  ORDER BY u.username ASC`
+
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying workload: %v", err)
+	}
+	defer rows.Close()
+
+	report := &WorkloadReport{Caseworkers: []CaseworkerWorkload{}}
+	for rows.Next() {
+		var c CaseworkerWorkload
+		if err := rows.Scan(&c.UserID, &c.Username, &c.Role, &c.Pending, &c.Approved, &c.Rejected); err != nil {
+			return nil, fmt.Errorf("error scanning workload row: %v", err)
+		}
+		report.Caseworkers = append(report.Caseworkers, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating workload rows: %v", err)
+	}
+
+	unassigned := filter
+	unassigned.Status = "pending"
+	unassigned.AssignedTo = ""
+	unassigned.Unassigned = true
+	where, args = unassigned.whereClause()
+	if err := r.DB.QueryRow(`SELECT COUNT(*) FROM applications`+where, args...).Scan(&report.Unassigned); err != nil {
+		return nil, fmt.Errorf("error counting unassigned applications: %v", err)
+	}
+	return report, nil
+}
+
+// Geographic counts the applications matching the filter by the postal
+// district of their applicant's address, or by the planning region of the
+// district. Applicants are placed by their current address, and deleted and
+// anonymized applicants are counted, as anonymizing keeps the district.
+func (r *ReportRepository) Geographic(filter ApplicationFilter, groupBy string) (*GeographicReport, error) {
+	where, args := filter.whereClause()
+	query := `SELECT COALESCE(p.postal_district, ''), a.applicant_id, a.status, COUNT(*)
+			  FROM (SELECT * FROM applications` + where + `) a
+			  JOIN applicants p ON p.id = a.applicant_id
+			  GROUP BY p.postal_district, a.applicant_id, a.status`
+
+	rows, err := r.DB.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying applications by district: %v", err)
+	}
+	defer rows.Close()
+
+	report := &GeographicReport{GroupBy: groupBy, Areas: []GeographicArea{}}
+	index := map[string]int{}
+	if groupBy == GroupByRegion {
+		for _, region := range Regions {
+			var longitude, latitude float64
+			var n int
+			for _, d := range Districts {
+				if d.Region == region.Code {
+					longitude += d.Longitude
+					latitude += d.Latitude
+					n++
+				}
+			}
+			index[region.Code] = len(report.Areas)
+			report.Areas = append(report.Areas, GeographicArea{Code: region.Code, Name: region.Name,
+				ByStatus: map[string]int{}, Longitude: roundCoordinate(longitude / float64(n)), Latitude: roundCoordinate(latitude / float64(n))})
+		}
+	} else {
+		for _, d := range Districts {
+			index[d.Code] = len(report.Areas)
+			report.Areas = append(report.Areas, GeographicArea{Code: d.Code, Name: d.Name, Region: d.Region,
+				ByStatus: map[string]int{}, Longitude: d.Longitude, Latitude: d.Latitude})
+		}
+	}
+
+	applicants := map[string]map[string]bool{}
+	for rows.Next() {
+		var district, applicantID, status string
+		var count int
+		if err := rows.Scan(&district, &applicantID, &status, &count); err != nil {
+			return nil, fmt.Errorf("error scanning applications by district row: %v", err)
+		}
+		code := district
+		if groupBy == GroupByRegion {
+			code = DistrictRegion(district)
+		}
+		i, ok := index[code]
+		if !ok {
+			report.Unlocated += count
+			continue
+		}
+		area := &report.Areas[i]
+		area.Applications += count
+		area.ByStatus[status] += count
+		if status == "approved" {
+			area.Approved += count
+		}
+		if applicants[code] == nil {
+			applicants[code] = map[string]bool{}
+		}
+		applicants[code][applicantID] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating applications by district rows: %v", err)
+	}
+
+	for code, ids := range applicants {
+		report.Areas[index[code]].Applicants = len(ids)
+	}
+	return report, nil
+}
+
+// roundCoordinate rounds a longitude or latitude to three decimal places,
+// about 100 metres, the precision of the district centres
+func roundCoordinate(x float64) float64 {
+	return math.Round(x*1000) / 1000
+}
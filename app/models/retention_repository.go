@@ -0,0 +1,174 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// RetentionRunJob is the payload of a retention.run job
+type RetentionRunJob struct {
+	AsOf          time.Time `json:"as_of"`              // The time retention periods are counted back from
+	DryRun        bool      `json:"dry_run"`            // Whether to only report what the rules match
+	ActorID       string    `json:"actor_id,omitempty"` // Who requested the run, or empty for scheduled runs
+	ActorUsername string    `json:"actor_username,omitempty"`
+}
+
+// RetentionRunResult is the result of a retention.run job
+type RetentionRunResult struct {
+	AsOf   time.Time             `json:"as_of"`
+	DryRun bool                  `json:"dry_run"`
+	Rules  []RetentionRuleResult `json:"rules"`
+}
+
+// RetentionRuleResult reports what one retention rule matched in a run
+type RetentionRuleResult struct {
+	Rule    string    `json:"rule" example:"rejected_applications"`
+	Action  string    `json:"action" example:"purge" enums:"purge,anonymize"`
+	Period  string    `json:"period" example:"2y"`
+	Cutoff  time.Time `json:"cutoff"`  // Records inactive since before this time are matched
+	Matched int       `json:"matched"` // Records the rule matched
+	Applied int       `json:"applied"` // Records purged or anonymized, always 0 on dry runs
+	IDs     []string  `json:"ids"`     // The first of the matched records, up to 1000
+}
+
+// RetentionRepository finds and removes records kept longer than the
+// retention rules allow
+type RetentionRepository struct {
+	DB *sql.DB
+	tx *sql.Tx
+}
+
+// NewRetentionRepository creates a new repository with the given database connection
+func NewRetentionRepository(db *sql.DB) *RetentionRepository {
+	return &RetentionRepository{DB: db}
+}
+
+// WithTx returns a copy of the repository that runs its queries in tx
+func (r *RetentionRepository) WithTx(tx *sql.Tx) *RetentionRepository {
+	return &RetentionRepository{DB: r.DB, tx: tx}
+}
+
+// conn returns the transaction the repository is bound to, or the database
+func (r *RetentionRepository) conn() DBTX {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.DB
+}
+
+// RejectedApplicationsBefore returns the IDs of applications, including
+// deleted ones, rejected before cutoff, oldest decision first
+func (r *RetentionRepository) RejectedApplicationsBefore(cutoff time.Time) ([]string, error) {
+	return r.ids(`SELECT id FROM applications
+			  WHERE status = ? AND decision_date < ?
+			  ORDER BY decision_date ASC`, "rejected", cutoff)
+}
+
+// InactiveApplicantsBefore returns the IDs of applicants, including deleted
+// ones, not yet anonymized and with no activity since cutoff: neither they
+// nor any of their applications were changed, and no case note was added.
+func (r *RetentionRepository) InactiveApplicantsBefore(cutoff time.Time) ([]string, error) {
+	return r.ids(`SELECT a.id FROM applicants a
+			  WHERE a.anonymized_at IS NULL AND a.updated_at < ?
+			  AND NOT EXISTS (SELECT 1 FROM applications p WHERE p.applicant_id = a.id AND p.updated_at >= ?)
+			  AND NOT EXISTS (SELECT 1 FROM case_notes n WHERE n.applicant_id = a.id AND n.created_at >= ?)
+			  ORDER BY a.updated_at ASC`, cutoff, cutoff, cutoff)
+}
+
+// ids runs a query selecting a single ID column
+func (r *RetentionRepository) ids(query string, args ...interface{}) ([]string, error) {
+	rows, err := r.conn().Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying retention candidates: %v", err)
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("error scanning retention candidate: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating retention candidates: %v", err)
+	}
+	return ids, nil
+}
+
+// PurgeApplication permanently removes an application with its documents,
+// review flags and comments, drops its snapshots and those of its documents
+// and comments from the audit log and the change export outbox, drops the
+// data of its events, and removes sent webhook deliveries that mention it. It returns the storage keys of the documents,
+// which the caller should delete once the transaction commits.
+func (r *RetentionRepository) PurgeApplication(id string) ([]string, error) {
+	var keys []string
+	err := runInTx(r.DB, r.tx, func(tx *sql.Tx) error {
+		rows, err := tx.Query(`SELECT id, storage_key FROM documents WHERE application_id = ?`, id)
+		if err != nil {
+			return fmt.Errorf("error querying documents: %v", err)
+		}
+		documentIDs := []string{id}
+		for rows.Next() {
+			var documentID, key string
+			if err := rows.Scan(&documentID, &key); err != nil {
+				rows.Close()
+				return fmt.Errorf("error scanning document row: %v", err)
+			}
+			documentIDs = append(documentIDs, documentID)
+			keys = append(keys, key)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error iterating document rows: %v", err)
+		}
+
+		entityIDs := documentIDs
+		rows, err = tx.Query(`SELECT id FROM application_comments WHERE application_id = ?`, id)
+		if err != nil {
+			return fmt.Errorf("error querying comments: %v", err)
+		}
+		for rows.Next() {
+			var commentID string
+			if err := rows.Scan(&commentID); err != nil {
+				rows.Close()
+				return fmt.Errorf("error scanning comment row: %v", err)
+			}
+			entityIDs = append(entityIDs, commentID)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error iterating comment rows: %v", err)
+		}
+
+		// Documents, review flags and comments are deleted with the application
+		if _, err := tx.Exec(`DELETE FROM applications WHERE id = ?`, id); err != nil {
+			return fmt.Errorf("error purging application: %v", err)
+		}
+
+		placeholders, args := inClause(entityIDs)
+		condition := `entity_type IN (?, ?, ?) AND entity_id IN (` + placeholders + `)`
+		args = append([]interface{}{AuditEntityApplication, AuditEntityDocument, AuditEntityComment}, args...)
+		if _, err := tx.Exec(`UPDATE audit_logs SET before_data = NULL, after_data = NULL, changes = NULL
+			  WHERE `+condition, args...); err != nil {
+			return fmt.Errorf("error scrubbing audit log: %v", err)
+		}
+		if _, err := tx.Exec(`UPDATE change_events SET data = NULL WHERE `+condition, args...); err != nil {
+			return fmt.Errorf("error scrubbing change events: %v", err)
+		}
+		if _, err := tx.Exec(`UPDATE application_events SET data = NULL WHERE application_id = ?`, id); err != nil {
+			return fmt.Errorf("error scrubbing application events: %v", err)
+		}
+		if _, err := tx.Exec(`DELETE FROM webhook_deliveries WHERE status <> ? AND payload LIKE ?`,
+			DeliveryPending, "%"+id+"%"); err != nil {
+			return fmt.Errorf("error removing webhook deliveries: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
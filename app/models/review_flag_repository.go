@@ -0,0 +1,184 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ActiveApplicationStatuses are the statuses of applications whose
+// eligibility is re-evaluated
+var ActiveApplicationStatuses = []string{"pending", "approved"}
+
+// EligibilityFinding is the outcome of re-evaluating one active application
+type EligibilityFinding struct {
+	ApplicationID string
+	Eligible      bool
+	SchemeVersion *int   // The scheme terms assessed against, if any were in effect
+	Reason        string // Why the applicant is not eligible
+}
+
+// EligibilityReviewJob is the payload of an eligibility.review job
+type EligibilityReviewJob struct {
+	AsOf time.Time `json:"as_of"` // The time to assess eligibility at
+}
+
+// EligibilityReviewResult is the result of an eligibility.review job
+type EligibilityReviewResult struct {
+	AsOf       time.Time `json:"as_of"`
+	Checked    int       `json:"checked"`    // Active applications re-evaluated
+	Ineligible int       `json:"ineligible"` // Of which the applicant no longer meets the criteria
+	Flagged    int       `json:"flagged"`    // Flags raised, excluding applications already flagged
+	Resolved   int       `json:"resolved"`   // Flags resolved because the applicant is eligible again
+}
+
+// ReviewEligibility re-evaluates applications against the terms of their
+// schemes in effect at asOf. Applications must carry their applicants, as
+// returned by ApplicationRepository.Find; those whose applicant has been
+// deleted are skipped.
+func ReviewEligibility(store SchemeStore, applications []Application, asOf time.Time) ([]EligibilityFinding, error) {
+	allVersions, err := store.GetAllVersions()
+	if err != nil {
+		return nil, fmt.Errorf("error getting scheme versions: %v", err)
+	}
+	versions := versionsAt(allVersions, asOf)
+
+	var findings []EligibilityFinding
+	for _, a := range applications {
+		if a.Applicant == nil || a.Applicant.DeletedAt != nil {
+			continue
+		}
+
+		finding := EligibilityFinding{ApplicationID: a.ID, Eligible: true}
+		version, ok := versions[a.SchemeID]
+		if !ok {
+			finding.Eligible = false
+			finding.Reason = "Scheme has no terms in effect"
+		} else {
+			v := version.Version
+			finding.SchemeVersion = &v
+			if !isEligible(a.Applicant, version.Criteria, asOf) {
+				finding.Eligible = false
+				finding.Reason = "Applicant no longer meets the criteria of version " + strconv.Itoa(v) + " of the scheme"
+			}
+		}
+		findings = append(findings, finding)
+	}
+
+	return findings, nil
+}
+
+// ReviewFlagRepository handles database operations for review flags
+type ReviewFlagRepository struct {
+	DB *sql.DB
+	tx *sql.Tx
+}
+
+// NewReviewFlagRepository creates a new repository with the given database connection
+func NewReviewFlagRepository(db *sql.DB) *ReviewFlagRepository {
+	return &ReviewFlagRepository{DB: db}
+}
+
+// WithTx returns a copy of the repository that runs its queries in tx
+func (r *ReviewFlagRepository) WithTx(tx *sql.Tx) *ReviewFlagRepository {
+	return &ReviewFlagRepository{DB: r.DB, tx: tx}
+}
+
+// conn returns the transaction the repository is bound to, or the database
+func (r *ReviewFlagRepository) conn() DBTX {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.DB
+}
+
+// GetByApplicationID retrieves the flags raised on an application, newest
+// first
+func (r *ReviewFlagRepository) GetByApplicationID(applicationID string) ([]ReviewFlag, error) {
+	query := `SELECT id, application_id, reason, scheme_version, created_at, resolved_at
+			  FROM review_flags
+			  WHERE application_id = ?
+			  ORDER BY created_at DESC`
+
+	rows, err := r.conn().Query(query, applicationID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying review flags: %v", err)
+	}
+	defer rows.Close()
+
+	var flags []ReviewFlag
+	for rows.Next() {
+		var f ReviewFlag
+		var schemeVersion sql.NullInt64
+		var resolvedAt sql.NullTime
+		if err := rows.Scan(&f.ID, &f.ApplicationID, &f.Reason, &schemeVersion, &f.CreatedAt, &resolvedAt); err != nil {
+			return nil, fmt.Errorf("error scanning review flag row: %v", err)
+		}
+		if schemeVersion.Valid {
+			v := int(schemeVersion.Int64)
+			f.SchemeVersion = &v
+		}
+		if resolvedAt.Valid {
+			f.ResolvedAt = &resolvedAt.Time
+		}
+		flags = append(flags, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating review flag rows: %v", err)
+	}
+
+	return flags, nil
+}
+
+// Record saves the findings of an eligibility review: a flag is raised on
+// each ineligible application without an unresolved flag, and the unresolved
+// flags of eligible applications are resolved. It returns the number of flags
+// raised and resolved.
+func (r *ReviewFlagRepository) Record(findings []EligibilityFinding) (flagged, resolved int, err error) {
+	err = runInTx(r.DB, r.tx, func(tx *sql.Tx) error {
+		now := time.Now()
+		for _, f := range findings {
+			if f.Eligible {
+				result, err := tx.Exec(`UPDATE review_flags SET resolved_at = ?
+										WHERE application_id = ? AND resolved_at IS NULL`, now, f.ApplicationID)
+				if err != nil {
+					return fmt.Errorf("error resolving review flags: %v", err)
+				}
+				n, err := result.RowsAffected()
+				if err != nil {
+					return fmt.Errorf("error checking rows affected: %v", err)
+				}
+				resolved += int(n)
+				continue
+			}
+
+			var open int
+			if err := tx.QueryRow(`SELECT COUNT(*) FROM review_flags
+								   WHERE application_id = ? AND resolved_at IS NULL`, f.ApplicationID).Scan(&open); err != nil {
+				return fmt.Errorf("error checking review flags: %v", err)
+			}
+			if open > 0 {
+				continue
+			}
+
+			var schemeVersion interface{}
+			if f.SchemeVersion != nil {
+				schemeVersion = *f.SchemeVersion
+			}
+			if _, err := tx.Exec(`INSERT INTO review_flags (id, application_id, reason, scheme_version, created_at)
+								  VALUES (?, ?, ?, ?, ?)`, uuid.New().String(), f.ApplicationID, f.Reason, schemeVersion, now); err != nil {
+				return fmt.Errorf("error creating review flag: %v", err)
+			}
+			flagged++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return flagged, resolved, nil
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"one-client-view-2025tht/app/cache"
+	"one-client-view-2025tht/app/logging"
+)
+
+// Cache keys of the scheme data held by CachedSchemeStore
+const (
+	schemesCacheKey        = "schemes:all"
+	schemeVersionsCacheKey = "schemes:versions"
+)
+
+// CachedSchemeStore is a SchemeStore that keeps the results of another store
+// in a cache for a time to live. Callers that change schemes, their versions
+// or benefits must call Invalidate once the change is committed. Values are
+// stored as JSON, so every read returns a fresh copy and the cache can be
+// shared between replicas.
+//
+// Cache failures are logged and fall through to the underlying store, so a
+// cache outage slows reads rather than failing them.
+type CachedSchemeStore struct {
+	Store  SchemeStore
+	Cache  cache.Cache
+	TTL    time.Duration
+	Logger *slog.Logger
+}
+
+// NewCachedSchemeStore creates a store caching the results of store in c for
+// ttl
+func NewCachedSchemeStore(store SchemeStore, c cache.Cache, ttl time.Duration) *CachedSchemeStore {
+	return &CachedSchemeStore{Store: store, Cache: c, TTL: ttl, Logger: logging.Component("cache")}
+}
+
+// GetAll returns every scheme with its benefits, from the cache if present
+func (s *CachedSchemeStore) GetAll() ([]Scheme, error) {
+	var schemes []Scheme
+	err := s.cached(schemesCacheKey, &schemes, func() (interface{}, error) {
+		return s.Store.GetAll()
+	})
+	return schemes, err
+}
+
+// GetAllVersions returns every version of every scheme, from the cache if
+// present
+func (s *CachedSchemeStore) GetAllVersions() ([]SchemeVersion, error) {
+	var versions []SchemeVersion
+	err := s.cached(schemeVersionsCacheKey, &versions, func() (interface{}, error) {
+		return s.Store.GetAllVersions()
+	})
+	return versions, err
+}
+
+// Invalidate discards the cached scheme data, so the next reads go to the
+// underlying store
+func (s *CachedSchemeStore) Invalidate() {
+	if err := s.Cache.Delete(context.Background(), schemesCacheKey, schemeVersionsCacheKey); err != nil {
+		s.Logger.Error("Failed to invalidate scheme cache", "error", err)
+	}
+}
+
+// cached decodes the value under key into dest, or on a miss loads it with
+// load, caches it and decodes it the same way
+func (s *CachedSchemeStore) cached(key string, dest interface{}, load func() (interface{}, error)) error {
+	ctx := context.Background()
+
+	data, ok, err := s.Cache.Get(ctx, key)
+	if err != nil {
+		s.Logger.Warn("Failed to read from scheme cache", "key", key, "error", err)
+	}
+	if ok {
+		if err := json.Unmarshal(data, dest); err == nil {
+			return nil
+		}
+		s.Logger.Warn("Discarding unreadable entry in scheme cache", "key", key)
+	}
+
+	value, err := load()
+	if err != nil {
+		return err
+	}
+	if data, err = json.Marshal(value); err != nil {
+		return err
+	}
+	if err := s.Cache.Set(ctx, key, data, s.TTL); err != nil {
+		s.Logger.Warn("Failed to write to scheme cache", "key", key, "error", err)
+	}
+	return json.Unmarshal(data, dest)
+}
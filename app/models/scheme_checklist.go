@@ -0,0 +1,176 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// checklistItemColumns is the column list read by scanChecklistItem
+const checklistItemColumns = `id, scheme_id, name, description, kind, mandatory, position, created_at, updated_at`
+
+// scanChecklistItem scans a row selected with checklistItemColumns
+func scanChecklistItem(row rowScanner) (ChecklistItem, error) {
+	var item ChecklistItem
+	var description sql.NullString
+
+	if err := row.Scan(&item.ID, &item.SchemeID, &item.Name, &description, &item.Kind, &item.Mandatory,
+		&item.Position, &item.CreatedAt, &item.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return item, err
+		}
+		return item, fmt.Errorf("error scanning checklist item row: %v", err)
+	}
+	item.Description = description.String
+
+	return item, nil
+}
+
+// GetChecklistItem retrieves a single item of a scheme's review checklist
+func (r *SchemeRepository) GetChecklistItem(schemeID, id string) (*ChecklistItem, error) {
+	query := `SELECT ` + checklistItemColumns + `
+			  FROM scheme_checklist_items
+			  WHERE id = ? AND scheme_id = ?`
+
+	item, err := scanChecklistItem(r.conn().QueryRow(query, id, schemeID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Not found
+		}
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+// GetChecklist retrieves the review checklist of a scheme, ordered by
+// position, then name
+func (r *SchemeRepository) GetChecklist(schemeID string) ([]ChecklistItem, error) {
+	checklists, err := r.getChecklistsByScheme([]string{schemeID})
+	if err != nil {
+		return nil, err
+	}
+	return checklists[schemeID], nil
+}
+
+// getChecklistsByScheme retrieves the review checklists of several schemes
+// at once, keyed by scheme ID
+func (r *SchemeRepository) getChecklistsByScheme(schemeIDs []string) (map[string][]ChecklistItem, error) {
+	result := make(map[string][]ChecklistItem)
+	schemeIDs = uniqueIDs(schemeIDs)
+	if len(schemeIDs) == 0 {
+		return result, nil
+	}
+
+	placeholders, args := inClause(schemeIDs)
+	query := `SELECT ` + checklistItemColumns + `
+			  FROM scheme_checklist_items
+			  WHERE scheme_id IN (` + placeholders + `)
+			  ORDER BY position ASC, name ASC`
+
+	rows, err := r.conn().Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying checklist items: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanChecklistItem(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		result[item.SchemeID] = append(result[item.SchemeID], item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating checklist item rows: %v", err)
+	}
+
+	return result, nil
+}
+
+// attachChecklists loads the review checklists of all the given schemes in a
+// single query
+func (r *SchemeRepository) attachChecklists(schemes []Scheme) error {
+	ids := make([]string, len(schemes))
+	for i := range schemes {
+		ids[i] = schemes[i].ID
+	}
+
+	checklists, err := r.getChecklistsByScheme(ids)
+	if err != nil {
+		return fmt.Errorf("error getting checklists: %v", err)
+	}
+
+	for i := range schemes {
+		schemes[i].Checklist = checklists[schemes[i].ID]
+	}
+	return nil
+}
+
+// CreateChecklistItem inserts a new item into a scheme's review checklist
+func (r *SchemeRepository) CreateChecklistItem(item *ChecklistItem) error {
+	// Generate UUID if not provided
+	if item.ID == "" {
+		item.ID = uuid.New().String()
+	}
+
+	item.setDefaults()
+
+	now := time.Now()
+	item.CreatedAt = now
+	item.UpdatedAt = now
+
+	query := `INSERT INTO scheme_checklist_items (id, scheme_id, name, description, kind, mandatory, position,
+			  created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.conn().Exec(query, item.ID, item.SchemeID, item.Name, item.Description, item.Kind, item.Mandatory,
+		item.Position, item.CreatedAt, item.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating checklist item: %v", err)
+	}
+
+	return nil
+}
+
+// setDefaults fills in the kind of a checklist item sent without one
+func (item *ChecklistItem) setDefaults() {
+	if item.Kind == "" {
+		item.Kind = ChecklistOther
+	}
+}
+
+// UpdateChecklistItem updates an existing checklist item's name,
+// description, kind, mandatory flag and position. The copies on existing
+// applications are kept as they are.
+func (r *SchemeRepository) UpdateChecklistItem(item *ChecklistItem) error {
+	item.setDefaults()
+	item.UpdatedAt = time.Now()
+
+	query := `UPDATE scheme_checklist_items
+			  SET name = ?, description = ?, kind = ?, mandatory = ?, position = ?, updated_at = ?
+			  WHERE id = ? AND scheme_id = ?`
+
+	_, err := r.conn().Exec(query, item.Name, item.Description, item.Kind, item.Mandatory, item.Position,
+		item.UpdatedAt, item.ID, item.SchemeID)
+	if err != nil {
+		return fmt.Errorf("error updating checklist item: %v", err)
+	}
+
+	return nil
+}
+
+// DeleteChecklistItem removes an item from a scheme's review checklist. The
+// copies on existing applications are kept.
+func (r *SchemeRepository) DeleteChecklistItem(id string) error {
+	_, err := r.conn().Exec(`DELETE FROM scheme_checklist_items WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting checklist item: %v", err)
+	}
+	return nil
+}
@@ -3,16 +3,19 @@ package models
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/google/uuid"
+
+	"one-client-view-2025tht/app/money"
 )
 
 // SchemeRepository handles database operations for schemes
 type SchemeRepository struct {
 	DB *sql.DB
+	tx *sql.Tx
 }
 
 // NewSchemeRepository creates a new repository with the given database connection
@@ -20,13 +23,76 @@ func NewSchemeRepository(db *sql.DB) *SchemeRepository {
 	return &SchemeRepository{DB: db}
 }
 
+// WithTx returns a copy of the repository that runs its queries in tx
+func (r *SchemeRepository) WithTx(tx *sql.Tx) *SchemeRepository {
+	return &SchemeRepository{DB: r.DB, tx: tx}
+}
+
+// conn returns the transaction the repository is bound to, or the database
+func (r *SchemeRepository) conn() DBTX {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.DB
+}
+
+// schemeColumns is the column list read by scanScheme
+const schemeColumns = `id, name, description, criteria, version, open_date, close_date, is_active, status,
+	max_applications, budget_cents, approved_count, approved_amount_cents, cooling_off_days, created_at, updated_at`
+
+// scanScheme scans a row selected with schemeColumns and parses its criteria
+func scanScheme(row rowScanner) (Scheme, error) {
+	var s Scheme
+	var criteriaJSON []byte
+	var openDate, closeDate sql.NullTime
+	var maxApplications sql.NullInt64
+	var budget sql.NullInt64
+	var coolingOffDays sql.NullInt64
+
+	if err := row.Scan(&s.ID, &s.Name, &s.Description, &criteriaJSON,
+		&s.Version, &openDate, &closeDate, &s.IsActive, &s.Status,
+		&maxApplications, &budget, &s.ApprovedCount, &s.ApprovedAmount, &coolingOffDays,
+		&s.CreatedAt, &s.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return s, err
+		}
+		return s, fmt.Errorf("error scanning scheme row: %v", err)
+	}
+	if openDate.Valid {
+		s.OpenDate = &openDate.Time
+	}
+	if closeDate.Valid {
+		s.CloseDate = &closeDate.Time
+	}
+	if maxApplications.Valid {
+		n := int(maxApplications.Int64)
+		s.MaxApplications = &n
+	}
+	if budget.Valid {
+		cents := money.Amount(budget.Int64)
+		s.Budget = &cents
+	}
+	if coolingOffDays.Valid {
+		days := int(coolingOffDays.Int64)
+		s.CoolingOffDays = &days
+	}
+	s.setRemaining()
+
+	// Parse criteria JSON
+	if err := json.Unmarshal(criteriaJSON, &s.Criteria); err != nil {
+		return s, fmt.Errorf("error unmarshaling criteria: %v", err)
+	}
+
+	return s, nil
+}
+
 // GetAll retrieves all schemes from the database
 func (r *SchemeRepository) GetAll() ([]Scheme, error) {
-	query := `SELECT id, name, description, criteria, created_at, updated_at
+	query := `SELECT ` + schemeColumns + `
 			  FROM schemes
 			  ORDER BY name ASC`
 
-	rows, err := r.DB.Query(query)
+	rows, err := r.conn().Query(query)
 	if err != nil {
 		return nil, fmt.Errorf("error querying schemes: %v", err)
 	}
@@ -34,60 +100,109 @@ func (r *SchemeRepository) GetAll() ([]Scheme, error) {
 
 	var schemes []Scheme
 	for rows.Next() {
-		var s Scheme
-		var criteriaJSON []byte
-
-		if err := rows.Scan(&s.ID, &s.Name, &s.Description, &criteriaJSON,
-			&s.CreatedAt, &s.UpdatedAt); err != nil {
-			return nil, fmt.Errorf("error scanning scheme row: %v", err)
+		s, err := scanScheme(rows)
+		if err != nil {
+			return nil, err
 		}
+		schemes = append(schemes, s)
+	}
 
-		// Parse criteria JSON
-		if err := json.Unmarshal(criteriaJSON, &s.Criteria); err != nil {
-			return nil, fmt.Errorf("error unmarshaling criteria: %v", err)
-		}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating scheme rows: %v", err)
+	}
+	// Release the connection before issuing further queries, which a
+	// transaction requires
+	rows.Close()
+
+	if err := r.attachBenefits(schemes); err != nil {
+		return nil, err
+	}
+	if err := r.attachChecklists(schemes); err != nil {
+		return nil, err
+	}
+
+	return schemes, nil
+}
 
-		// Get benefits for each scheme
-		benefits, err := r.GetBenefits(s.ID)
+// GetByIDs retrieves the schemes with the given IDs, keyed by ID, using a
+// fixed number of queries regardless of how many IDs are requested
+func (r *SchemeRepository) GetByIDs(ids []string) (map[string]*Scheme, error) {
+	result := make(map[string]*Scheme)
+	ids = uniqueIDs(ids)
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	placeholders, args := inClause(ids)
+	query := `SELECT ` + schemeColumns + `
+			  FROM schemes
+			  WHERE id IN (` + placeholders + `)`
+
+	rows, err := r.conn().Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying schemes: %v", err)
+	}
+	defer rows.Close()
+
+	var schemes []Scheme
+	for rows.Next() {
+		s, err := scanScheme(rows)
 		if err != nil {
-			return nil, fmt.Errorf("error getting benefits: %v", err)
+			return nil, err
 		}
-		s.Benefits = benefits
-
 		schemes = append(schemes, s)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("error iterating scheme rows: %v", err)
 	}
+	rows.Close()
 
-	return schemes, nil
+	if err := r.attachBenefits(schemes); err != nil {
+		return nil, err
+	}
+	if err := r.attachChecklists(schemes); err != nil {
+		return nil, err
+	}
+
+	for i := range schemes {
+		result[schemes[i].ID] = &schemes[i]
+	}
+	return result, nil
+}
+
+// attachBenefits loads the benefits of all the given schemes in a single query
+func (r *SchemeRepository) attachBenefits(schemes []Scheme) error {
+	ids := make([]string, len(schemes))
+	for i := range schemes {
+		ids[i] = schemes[i].ID
+	}
+
+	benefits, err := r.getBenefitsByScheme(ids)
+	if err != nil {
+		return fmt.Errorf("error getting benefits: %v", err)
+	}
+
+	for i := range schemes {
+		schemes[i].Benefits = benefits[schemes[i].ID]
+	}
+	return nil
 }
 
 // GetByID retrieves a scheme by ID
 func (r *SchemeRepository) GetByID(id string) (*Scheme, error) {
-	query := `SELECT id, name, description, criteria, created_at, updated_at
+	query := `SELECT ` + schemeColumns + `
 			  FROM schemes
 			  WHERE id = ?`
 
-	var s Scheme
-	var criteriaJSON []byte
-
-	err := r.DB.QueryRow(query, id).Scan(&s.ID, &s.Name, &s.Description, &criteriaJSON,
-		&s.CreatedAt, &s.UpdatedAt)
-
+	s, err := scanScheme(r.conn().QueryRow(query, id))
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil // No scheme found
 		}
 		return nil, fmt.Errorf("error querying scheme: %v", err)
 	}
 
-	// Parse criteria JSON
-	if err := json.Unmarshal(criteriaJSON, &s.Criteria); err != nil {
-		return nil, fmt.Errorf("error unmarshaling criteria: %v", err)
-	}
-
 	// Get benefits
 	benefits, err := r.GetBenefits(s.ID)
 	if err != nil {
@@ -95,6 +210,12 @@ func (r *SchemeRepository) GetByID(id string) (*Scheme, error) {
 	}
 	s.Benefits = benefits
 
+	checklist, err := r.GetChecklist(s.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting checklist: %v", err)
+	}
+	s.Checklist = checklist
+
 	return &s, nil
 }
 
@@ -108,6 +229,10 @@ func (r *SchemeRepository) Create(s *Scheme) error {
 	now := time.Now()
 	s.CreatedAt = now
 	s.UpdatedAt = now
+	s.Version = 1
+	s.ApprovedCount = 0
+	s.ApprovedAmount = 0
+	s.setRemaining()
 
 	// Convert criteria to JSON
 	criteriaJSON, err := json.Marshal(s.Criteria)
@@ -115,26 +240,42 @@ func (r *SchemeRepository) Create(s *Scheme) error {
 		return fmt.Errorf("error marshaling criteria: %v", err)
 	}
 
-	query := `INSERT INTO schemes (id, name, description, criteria, created_at, updated_at)
-			  VALUES (?, ?, ?, ?, ?, ?)`
+	query := `INSERT INTO schemes (id, name, description, criteria, version, open_date, close_date, is_active,
+			      status, max_applications, budget_cents, cooling_off_days, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-	_, err = r.DB.Exec(query, s.ID, s.Name, s.Description, criteriaJSON, s.CreatedAt, s.UpdatedAt)
-	if err != nil {
-		return fmt.Errorf("error creating scheme: %v", err)
-	}
+	// Insert the scheme, its benefits and its checklist atomically
+	return runInTx(r.DB, r.tx, func(tx *sql.Tx) error {
+		_, err := tx.Exec(query, s.ID, s.Name, s.Description, criteriaJSON, s.Version,
+			s.OpenDate, s.CloseDate, s.IsActive, s.Status, s.MaxApplications, s.Budget, s.CoolingOffDays, s.CreatedAt, s.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("error creating scheme: %v", err)
+		}
 
-	// Create benefits
-	for i := range s.Benefits {
-		s.Benefits[i].SchemeID = s.ID
-		if err := r.CreateBenefit(&s.Benefits[i]); err != nil {
-			return fmt.Errorf("error creating benefit: %v", err)
+		// Create benefits
+		txRepo := r.WithTx(tx)
+		for i := range s.Benefits {
+			s.Benefits[i].SchemeID = s.ID
+			if err := txRepo.CreateBenefit(&s.Benefits[i]); err != nil {
+				return fmt.Errorf("error creating benefit: %v", err)
+			}
+		}
+		for i := range s.Checklist {
+			s.Checklist[i].SchemeID = s.ID
+			if err := txRepo.CreateChecklistItem(&s.Checklist[i]); err != nil {
+				return err
+			}
 		}
-	}
 
-	return nil
+		return txRepo.saveVersion(s)
+	})
 }
 
-// Update updates an existing scheme
+// Update updates an existing scheme if its stored version matches s.Version,
+// returning ErrVersionConflict otherwise. On success s.Version is incremented
+// and the new terms are saved as a version taking effect at s.EffectiveFrom.
+// The approved totals and status are kept as stored; s should carry them so
+// its remaining capacity is set correctly.
 func (r *SchemeRepository) Update(s *Scheme) error {
 	s.UpdatedAt = time.Now()
 
@@ -145,35 +286,137 @@ func (r *SchemeRepository) Update(s *Scheme) error {
 	}
 
 	query := `UPDATE schemes
-			  SET name = ?, description = ?, criteria = ?, updated_at = ?
-			  WHERE id = ?`
+			  SET name = ?, description = ?, criteria = ?, open_date = ?, close_date = ?, is_active = ?,
+			      max_applications = ?, budget_cents = ?, cooling_off_days = ?, version = version + 1, updated_at = ?
+			  WHERE id = ? AND version = ?`
+
+	// Update the scheme and record its new version atomically
+	return runInTx(r.DB, r.tx, func(tx *sql.Tx) error {
+		result, err := tx.Exec(query, s.Name, s.Description, criteriaJSON,
+			s.OpenDate, s.CloseDate, s.IsActive, s.MaxApplications, s.Budget, s.CoolingOffDays, s.UpdatedAt, s.ID, s.Version)
+		if err != nil {
+			return fmt.Errorf("error updating scheme: %v", err)
+		}
+		if err := checkVersioned(result); err != nil {
+			return err
+		}
 
-	_, err = r.DB.Exec(query, s.Name, s.Description, criteriaJSON, s.UpdatedAt, s.ID)
+		s.Version++
+		if err := r.WithTx(tx).saveVersion(s); err != nil {
+			s.Version--
+			return err
+		}
+		s.setRemaining()
+		return nil
+	})
+}
+
+// SetStatus saves the status of a scheme. The version is kept, as the
+// scheme's terms are unchanged.
+func (r *SchemeRepository) SetStatus(s *Scheme) error {
+	s.UpdatedAt = time.Now()
+
+	_, err := r.conn().Exec(`UPDATE schemes SET status = ?, updated_at = ? WHERE id = ?`,
+		s.Status, s.UpdatedAt, s.ID)
 	if err != nil {
-		return fmt.Errorf("error updating scheme: %v", err)
+		return fmt.Errorf("error updating scheme status: %v", err)
 	}
-
 	return nil
 }
 
-// Delete removes a scheme
+// ErrSchemeInUse is returned when deleting a scheme that applications,
+// including deleted ones, refer to
+var ErrSchemeInUse = errors.New("scheme has applications")
+
+// Delete removes a scheme with its benefits, checklist and versions, or returns
+// ErrSchemeInUse if it has applications
 func (r *SchemeRepository) Delete(id string) error {
-	query := `DELETE FROM schemes WHERE id = ?`
-	_, err := r.DB.Exec(query, id)
+	return runInTx(r.DB, r.tx, func(tx *sql.Tx) error {
+		var applications int
+		if err := tx.QueryRow(`SELECT COUNT(*) FROM applications WHERE scheme_id = ?`, id).Scan(&applications); err != nil {
+			return fmt.Errorf("error counting scheme applications: %v", err)
+		}
+		if applications > 0 {
+			return ErrSchemeInUse
+		}
+
+		if _, err := tx.Exec(`DELETE FROM schemes WHERE id = ?`, id); err != nil {
+			return fmt.Errorf("error deleting scheme: %v", err)
+		}
+		return nil
+	})
+}
+
+// benefitColumns is the column list read by scanBenefit
+const benefitColumns = `id, scheme_id, name, description, amount_cents, currency, frequency, duration_months,
+	formula, created_at, updated_at`
+
+// benefitProjectedValueExpr is the SQL equivalent of Benefit.ProjectedValue
+// for a row of benefits. Quarters are counted without integer division, which
+// MySQL does not have, so that both databases agree.
+const benefitProjectedValueExpr = `amount_cents * CASE frequency
+	WHEN 'monthly' THEN COALESCE(duration_months, 1)
+	WHEN 'quarterly' THEN (COALESCE(duration_months, 1) + 2 - (COALESCE(duration_months, 1) + 2) % 3) / 3
+	ELSE 1 END`
+
+// scanBenefit scans a row selected with benefitColumns
+func scanBenefit(row rowScanner) (Benefit, error) {
+	var b Benefit
+	var description, formula sql.NullString
+	var amount, duration sql.NullInt64
+
+	if err := row.Scan(&b.ID, &b.SchemeID, &b.Name, &description, &amount, &b.Currency,
+		&b.Frequency, &duration, &formula, &b.CreatedAt, &b.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return b, err
+		}
+		return b, fmt.Errorf("error scanning benefit row: %v", err)
+	}
+
+	if description.Valid {
+		b.Description = description.String
+	}
+	if amount.Valid {
+		b.Amount = money.Amount(amount.Int64)
+	}
+	if duration.Valid {
+		months := int(duration.Int64)
+		b.DurationMonths = &months
+	}
+	f, err := scanFormula(formula)
 	if err != nil {
-		return fmt.Errorf("error deleting scheme: %v", err)
+		return b, err
 	}
-	return nil
+	b.Formula = f
+
+	return b, nil
+}
+
+// GetBenefit retrieves a single benefit of a scheme
+func (r *SchemeRepository) GetBenefit(schemeID, id string) (*Benefit, error) {
+	query := `SELECT ` + benefitColumns + `
+			  FROM benefits
+			  WHERE id = ? AND scheme_id = ?`
+
+	b, err := scanBenefit(r.conn().QueryRow(query, id, schemeID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Not found
+		}
+		return nil, err
+	}
+
+	return &b, nil
 }
 
 // GetBenefits retrieves all benefits for a scheme
 func (r *SchemeRepository) GetBenefits(schemeID string) ([]Benefit, error) {
-	query := `SELECT id, scheme_id, name, description, amount, created_at, updated_at
+	query := `SELECT ` + benefitColumns + `
 			  FROM benefits
 			  WHERE scheme_id = ?
 			  ORDER BY name ASC`
 
-	rows, err := r.DB.Query(query, schemeID)
+	rows, err := r.conn().Query(query, schemeID)
 	if err != nil {
 		return nil, fmt.Errorf("error querying benefits: %v", err)
 	}
@@ -181,20 +424,9 @@ func (r *SchemeRepository) GetBenefits(schemeID string) ([]Benefit, error) {
 
 	var benefits []Benefit
 	for rows.Next() {
-		var b Benefit
-		var description sql.NullString
-		var amount sql.NullFloat64
-
-		if err := rows.Scan(&b.ID, &b.SchemeID, &b.Name, &description, &amount,
-			&b.CreatedAt, &b.UpdatedAt); err != nil {
-			return nil, fmt.Errorf("error scanning benefit row: %v", err)
-		}
-
-		if description.Valid {
-			b.Description = description.String
-		}
-		if amount.Valid {
-			b.Amount = amount.Float64
+		b, err := scanBenefit(rows)
+		if err != nil {
+			return nil, err
 		}
 
 		benefits = append(benefits, b)
@@ -207,6 +439,43 @@ func (r *SchemeRepository) GetBenefits(schemeID string) ([]Benefit, error) {
 	return benefits, nil
 }
 
+// getBenefitsByScheme retrieves the benefits of several schemes at once, keyed
+// by scheme ID
+func (r *SchemeRepository) getBenefitsByScheme(schemeIDs []string) (map[string][]Benefit, error) {
+	result := make(map[string][]Benefit)
+	schemeIDs = uniqueIDs(schemeIDs)
+	if len(schemeIDs) == 0 {
+		return result, nil
+	}
+
+	placeholders, args := inClause(schemeIDs)
+	query := `SELECT ` + benefitColumns + `
+			  FROM benefits
+			  WHERE scheme_id IN (` + placeholders + `)
+			  ORDER BY name ASC`
+
+	rows, err := r.conn().Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying benefits: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		b, err := scanBenefit(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		result[b.SchemeID] = append(result[b.SchemeID], b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating benefit rows: %v", err)
+	}
+
+	return result, nil
+}
+
 // CreateBenefit inserts a new benefit
 func (r *SchemeRepository) CreateBenefit(b *Benefit) error {
 	// Generate UUID if not provided
@@ -214,14 +483,23 @@ func (r *SchemeRepository) CreateBenefit(b *Benefit) error {
 		b.ID = uuid.New().String()
 	}
 
+	b.setDefaults()
+
 	now := time.Now()
 	b.CreatedAt = now
 	b.UpdatedAt = now
 
-	query := `INSERT INTO benefits (id, scheme_id, name, description, amount, created_at, updated_at)
-			  VALUES (?, ?, ?, ?, ?, ?, ?)`
+	formula, err := formulaValue(b.Formula)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO benefits (id, scheme_id, name, description, amount_cents, currency, frequency, duration_months,
+			  formula, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
-	_, err := r.DB.Exec(query, b.ID, b.SchemeID, b.Name, b.Description, b.Amount, b.CreatedAt, b.UpdatedAt)
+	_, err = r.conn().Exec(query, b.ID, b.SchemeID, b.Name, b.Description, b.Amount, b.Currency, b.Frequency,
+		b.DurationMonths, formula, b.CreatedAt, b.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("error creating benefit: %v", err)
 	}
@@ -229,36 +507,88 @@ func (r *SchemeRepository) CreateBenefit(b *Benefit) error {
 	return nil
 }
 
+// setDefaults fills in the currency and frequency of a benefit sent without
+// them
+func (b *Benefit) setDefaults() {
+	if b.Currency == "" {
+		b.Currency = money.DefaultCurrency
+	}
+	if b.Frequency == "" {
+		b.Frequency = BenefitOneOff
+	}
+}
+
+// UpdateBenefit updates an existing benefit's name, description, amount,
+// currency, frequency and formula
+func (r *SchemeRepository) UpdateBenefit(b *Benefit) error {
+	b.setDefaults()
+	b.UpdatedAt = time.Now()
+
+	formula, err := formulaValue(b.Formula)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE benefits
+			  SET name = ?, description = ?, amount_cents = ?, currency = ?, frequency = ?, duration_months = ?,
+			  formula = ?, updated_at = ?
+			  WHERE id = ? AND scheme_id = ?`
+
+	_, err = r.conn().Exec(query, b.Name, b.Description, b.Amount, b.Currency, b.Frequency, b.DurationMonths,
+		formula, b.UpdatedAt, b.ID, b.SchemeID)
+	if err != nil {
+		return fmt.Errorf("error updating benefit: %v", err)
+	}
+
+	return nil
+}
+
 // DeleteBenefit removes a benefit
 func (r *SchemeRepository) DeleteBenefit(id string) error {
 	query := `DELETE FROM benefits WHERE id = ?`
-	_, err := r.DB.Exec(query, id)
+	_, err := r.conn().Exec(query, id)
 	if err != nil {
 		return fmt.Errorf("error deleting benefit: %v", err)
 	}
 	return nil
 }
 
-// GetEligibleSchemes finds all schemes for which an applicant is eligible
-func (r *SchemeRepository) GetEligibleSchemes(applicantID string, applicantRepo *ApplicantRepository) ([]Scheme, error) {
-	// Get applicant with household
-	applicant, err := applicantRepo.GetByID(applicantID)
+// SchemeStore reads the schemes and scheme versions needed to assess
+// eligibility. SchemeRepository reads them from the database; a
+// CachedSchemeStore decorates it.
+type SchemeStore interface {
+	// GetAll returns every scheme with its benefits and checklist, ordered by name
+	GetAll() ([]Scheme, error)
+	// GetAllVersions returns every version of every scheme
+	GetAllVersions() ([]SchemeVersion, error)
+}
+
+// EligibleSchemes finds all schemes in store open for applications at the
+// given time for which an applicant is eligible then, assessed against the
+// version of each scheme in effect. Each returned scheme carries that
+// version's name, description and criteria.
+func EligibleSchemes(store SchemeStore, applicant *Applicant, asOf time.Time) ([]Scheme, error) {
+	schemes, err := store.GetAll()
 	if err != nil {
-		return nil, fmt.Errorf("error getting applicant: %v", err)
-	}
-	if applicant == nil {
-		return nil, fmt.Errorf("applicant not found: %s", applicantID)
+		return nil, fmt.Errorf("error getting schemes: %v", err)
 	}
 
-	// Get all schemes
-	schemes, err := r.GetAll()
+	allVersions, err := store.GetAllVersions()
 	if err != nil {
-		return nil, fmt.Errorf("error getting schemes: %v", err)
+		return nil, fmt.Errorf("error getting scheme versions: %v", err)
 	}
+	versions := versionsAt(allVersions, asOf)
 
 	var eligibleSchemes []Scheme
 	for _, scheme := range schemes {
-		if isEligible(applicant, &scheme) {
+		// Schemes not yet in effect or not accepting applications are
+		// never eligible
+		version, ok := versions[scheme.ID]
+		if !ok || !scheme.OpenAt(asOf) {
+			continue
+		}
+		scheme = scheme.withVersion(version)
+		if isEligible(applicant, scheme.Criteria, asOf) {
 			eligibleSchemes = append(eligibleSchemes, scheme)
 		}
 	}
@@ -266,41 +596,177 @@ func (r *SchemeRepository) GetEligibleSchemes(applicantID string, applicantRepo
 	return eligibleSchemes, nil
 }
 
-// isEligible checks if an applicant is eligible for a scheme based on criteria
-func isEligible(applicant *Applicant, scheme *Scheme) bool {
-	criteria := scheme.Criteria
+// ErrEffectiveDate is returned when a scheme's new terms would take effect
+// before those of its latest version
+var ErrEffectiveDate = errors.New("effective_from must not be before the start of the scheme's latest version")
+
+// schemeVersionColumns is the column list read by scanSchemeVersion
+const schemeVersionColumns = `scheme_id, version, name, description, criteria, effective_from, effective_to, created_at`
+
+// scanSchemeVersion scans a row selected with schemeVersionColumns
+func scanSchemeVersion(row rowScanner) (SchemeVersion, error) {
+	var v SchemeVersion
+	var criteriaJSON []byte
+	var effectiveTo sql.NullTime
+
+	if err := row.Scan(&v.SchemeID, &v.Version, &v.Name, &v.Description, &criteriaJSON,
+		&v.EffectiveFrom, &effectiveTo, &v.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return v, err
+		}
+		return v, fmt.Errorf("error scanning scheme version row: %v", err)
+	}
+
+	if err := json.Unmarshal(criteriaJSON, &v.Criteria); err != nil {
+		return v, fmt.Errorf("error unmarshaling criteria: %v", err)
+	}
+	if effectiveTo.Valid {
+		v.EffectiveTo = &effectiveTo.Time
+	}
+
+	return v, nil
+}
+
+// withVersion returns a copy of the scheme with the terms of the given version
+func (s Scheme) withVersion(v SchemeVersion) Scheme {
+	s.Name = v.Name
+	s.Description = v.Description
+	s.Criteria = v.Criteria
+	s.Version = v.Version
+	return s
+}
 
-	// Check employment status
-	if criteria.EmploymentStatus != "" &&
-		strings.ToLower(criteria.EmploymentStatus) != strings.ToLower(applicant.EmploymentStatus) {
-		return false
+// saveVersion records the scheme's current terms as a new version taking
+// effect at s.EffectiveFrom, or now if unset, and ends the previous version
+// at the same time. Effective dates are kept to whole seconds, matching the
+// precision of MySQL timestamps.
+func (r *SchemeRepository) saveVersion(s *Scheme) error {
+	effectiveFrom := time.Now()
+	if s.EffectiveFrom != nil {
+		effectiveFrom = *s.EffectiveFrom
+	}
+	effectiveFrom = effectiveFrom.Truncate(time.Second)
+
+	var latestFrom time.Time
+	err := r.conn().QueryRow(`SELECT effective_from FROM scheme_versions
+			  WHERE scheme_id = ? AND effective_to IS NULL`, s.ID).Scan(&latestFrom)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		// First version
+	case err != nil:
+		return fmt.Errorf("error querying latest scheme version: %v", err)
+	case effectiveFrom.Before(latestFrom):
+		return ErrEffectiveDate
 	}
 
-	// Check marital status
-	if criteria.MaritalStatus != "" &&
-		strings.ToLower(criteria.MaritalStatus) != strings.ToLower(applicant.MaritalStatus) {
-		return false
+	if _, err := r.conn().Exec(`UPDATE scheme_versions SET effective_to = ?
+			  WHERE scheme_id = ? AND effective_to IS NULL`, effectiveFrom, s.ID); err != nil {
+		return fmt.Errorf("error ending scheme version: %v", err)
 	}
 
-	// Check children criteria
-	if criteria.HasChildren.SchoolLevel != "" {
-		hasEligibleChild := false
-		for _, member := range applicant.Household {
-			// Check if the member is a child
-			if strings.Contains(strings.ToLower(member.Relation), "son") ||
-				strings.Contains(strings.ToLower(member.Relation), "daughter") {
-				// Check age for primary school (roughly 6-12 years)
-				age := time.Now().Year() - member.DateOfBirth.Year()
-				if age >= 6 && age <= 12 && criteria.HasChildren.SchoolLevel == "primary" {
-					hasEligibleChild = true
-					break
-				}
-			}
+	criteriaJSON, err := json.Marshal(s.Criteria)
+	if err != nil {
+		return fmt.Errorf("error marshaling criteria: %v", err)
+	}
+
+	query := `INSERT INTO scheme_versions (scheme_id, version, name, description, criteria, effective_from, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	if _, err := r.conn().Exec(query, s.ID, s.Version, s.Name, s.Description, criteriaJSON,
+		effectiveFrom, time.Now()); err != nil {
+		return fmt.Errorf("error creating scheme version: %v", err)
+	}
+
+	s.EffectiveFrom = &effectiveFrom
+	return nil
+}
+
+// GetVersions retrieves all versions of a scheme, oldest first
+func (r *SchemeRepository) GetVersions(schemeID string) ([]SchemeVersion, error) {
+	query := `SELECT ` + schemeVersionColumns + `
+			  FROM scheme_versions
+			  WHERE scheme_id = ?
+			  ORDER BY version ASC`
+
+	return r.queryVersions(query, schemeID)
+}
+
+// GetVersion retrieves a single version of a scheme
+func (r *SchemeRepository) GetVersion(schemeID string, version int) (*SchemeVersion, error) {
+	query := `SELECT ` + schemeVersionColumns + `
+			  FROM scheme_versions
+			  WHERE scheme_id = ? AND version = ?`
+
+	v, err := scanSchemeVersion(r.conn().QueryRow(query, schemeID, version))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Not found
+		}
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// GetVersionAt retrieves the version of a scheme in effect at the given time,
+// or nil if the scheme was not in effect then
+func (r *SchemeRepository) GetVersionAt(schemeID string, at time.Time) (*SchemeVersion, error) {
+	query := `SELECT ` + schemeVersionColumns + `
+			  FROM scheme_versions
+			  WHERE scheme_id = ? AND effective_from <= ? AND (effective_to IS NULL OR effective_to > ?)`
+
+	v, err := scanSchemeVersion(r.conn().QueryRow(query, schemeID, at, at))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Not in effect
+		}
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// GetAllVersions retrieves every version of every scheme
+func (r *SchemeRepository) GetAllVersions() ([]SchemeVersion, error) {
+	query := `SELECT ` + schemeVersionColumns + `
+			  FROM scheme_versions
+			  ORDER BY scheme_id ASC, version ASC`
+
+	return r.queryVersions(query)
+}
+
+// versionsAt picks the version of each scheme in effect at the given time,
+// keyed by scheme ID
+func versionsAt(versions []SchemeVersion, at time.Time) map[string]SchemeVersion {
+	result := make(map[string]SchemeVersion)
+	for _, v := range versions {
+		if !v.EffectiveFrom.After(at) && (v.EffectiveTo == nil || v.EffectiveTo.After(at)) {
+			result[v.SchemeID] = v
 		}
-		if !hasEligibleChild {
-			return false
+	}
+	return result
+}
+
+// queryVersions runs a query selecting schemeVersionColumns
+func (r *SchemeRepository) queryVersions(query string, args ...interface{}) ([]SchemeVersion, error) {
+	rows, err := r.conn().Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying scheme versions: %v", err)
+	}
+	defer rows.Close()
+
+	var versions []SchemeVersion
+	for rows.Next() {
+		v, err := scanSchemeVersion(rows)
+		if err != nil {
+			return nil, err
 		}
+		versions = append(versions, v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating scheme version rows: %v", err)
 	}
 
-	return true
+	return versions, nil
 }
@@ -0,0 +1,172 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SchemeTranslationRepository handles database operations for the
+// translations of schemes
+type SchemeTranslationRepository struct {
+	DB *sql.DB
+	tx *sql.Tx
+}
+
+// NewSchemeTranslationRepository creates a new repository with the given database connection
+func NewSchemeTranslationRepository(db *sql.DB) *SchemeTranslationRepository {
+	return &SchemeTranslationRepository{DB: db}
+}
+
+// WithTx returns a copy of the repository that runs its queries in tx
+func (r *SchemeTranslationRepository) WithTx(tx *sql.Tx) *SchemeTranslationRepository {
+	return &SchemeTranslationRepository{DB: r.DB, tx: tx}
+}
+
+// conn returns the transaction the repository is bound to, or the database
+func (r *SchemeTranslationRepository) conn() DBTX {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.DB
+}
+
+// schemeTranslationColumns is the column list read by scanSchemeTranslation
+const schemeTranslationColumns = `scheme_id, language, name, description, updated_at`
+
+// scanSchemeTranslation scans a row selected with schemeTranslationColumns
+func scanSchemeTranslation(row rowScanner) (SchemeTranslation, error) {
+	var t SchemeTranslation
+	if err := row.Scan(&t.SchemeID, &t.Language, &t.Name, &t.Description, &t.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return t, err
+		}
+		return t, fmt.Errorf("error scanning scheme translation row: %v", err)
+	}
+	return t, nil
+}
+
+// GetBySchemeID retrieves the translations of a scheme, ordered by language
+func (r *SchemeTranslationRepository) GetBySchemeID(schemeID string) ([]SchemeTranslation, error) {
+	query := `SELECT ` + schemeTranslationColumns + `
+			  FROM scheme_translations
+			  WHERE scheme_id = ?
+			  ORDER BY language`
+
+	rows, err := r.conn().Query(query, schemeID)
+	if err != nil {
+		return nil, fmt.Errorf("error querying scheme translations: %v", err)
+	}
+	defer rows.Close()
+
+	translations := []SchemeTranslation{}
+	for rows.Next() {
+		t, err := scanSchemeTranslation(rows)
+		if err != nil {
+			return nil, err
+		}
+		translations = append(translations, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating scheme translations: %v", err)
+	}
+	return translations, nil
+}
+
+// GetByLanguage retrieves the translations of several schemes into language,
+// keyed by scheme ID. Schemes without a translation are left out.
+func (r *SchemeTranslationRepository) GetByLanguage(language string, schemeIDs []string) (map[string]SchemeTranslation, error) {
+	translations := map[string]SchemeTranslation{}
+	ids := uniqueIDs(schemeIDs)
+	if len(ids) == 0 {
+		return translations, nil
+	}
+
+	placeholders, args := inClause(ids)
+	query := `SELECT ` + schemeTranslationColumns + `
+			  FROM scheme_translations
+			  WHERE language = ? AND scheme_id IN (` + placeholders + `)`
+
+	rows, err := r.conn().Query(query, append([]interface{}{language}, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying scheme translations: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t, err := scanSchemeTranslation(rows)
+		if err != nil {
+			return nil, err
+		}
+		translations[t.SchemeID] = t
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating scheme translations: %v", err)
+	}
+	return translations, nil
+}
+
+// Get retrieves the translation of a scheme into language, or nil if there
+// is none
+func (r *SchemeTranslationRepository) Get(schemeID, language string) (*SchemeTranslation, error) {
+	query := `SELECT ` + schemeTranslationColumns + `
+			  FROM scheme_translations
+			  WHERE scheme_id = ? AND language = ?`
+
+	t, err := scanSchemeTranslation(r.conn().QueryRow(query, schemeID, language))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // No translation found
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Put saves a translation, replacing any earlier translation of the scheme
+// into the same language
+func (r *SchemeTranslationRepository) Put(t *SchemeTranslation) error {
+	existing, err := r.Get(t.SchemeID, t.Language)
+	if err != nil {
+		return err
+	}
+
+	t.UpdatedAt = time.Now()
+
+	if existing != nil {
+		query := `UPDATE scheme_translations
+				  SET name = ?, description = ?, updated_at = ?
+				  WHERE scheme_id = ? AND language = ?`
+		if _, err := r.conn().Exec(query, t.Name, t.Description, t.UpdatedAt, t.SchemeID, t.Language); err != nil {
+			return fmt.Errorf("error updating scheme translation: %v", err)
+		}
+		return nil
+	}
+
+	query := `INSERT INTO scheme_translations (scheme_id, language, name, description, updated_at)
+			  VALUES (?, ?, ?, ?, ?)`
+	if _, err := r.conn().Exec(query, t.SchemeID, t.Language, t.Name, t.Description, t.UpdatedAt); err != nil {
+		return fmt.Errorf("error creating scheme translation: %v", err)
+	}
+	return nil
+}
+
+// Delete removes the translation of a scheme into language
+func (r *SchemeTranslationRepository) Delete(schemeID, language string) error {
+	query := `DELETE FROM scheme_translations WHERE scheme_id = ? AND language = ?`
+	if _, err := r.conn().Exec(query, schemeID, language); err != nil {
+		return fmt.Errorf("error deleting scheme translation: %v", err)
+	}
+	return nil
+}
+
+// Localize returns a copy of a scheme with the name and description of its
+// translation, if it has one in translations
+func (s Scheme) Localize(translations map[string]SchemeTranslation) Scheme {
+	if t, ok := translations[s.ID]; ok {
+		s.Name = t.Name
+		s.Description = t.Description
+	}
+	return s
+}
@@ -0,0 +1,325 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"html"
+	"strings"
+	"unicode"
+)
+
+// Entity types returned by search
+const (
+	SearchTypeApplicant       = "applicant"
+	SearchTypeHouseholdMember = "household_member"
+	SearchTypeScheme          = "scheme"
+	SearchTypeApplication     = "application"
+)
+
+// SearchTypes lists the searchable entity types
+var SearchTypes = []string{SearchTypeApplicant, SearchTypeHouseholdMember, SearchTypeScheme, SearchTypeApplication}
+
+// SearchResult is a single entity matching a search
+type SearchResult struct {
+	Type        string            `json:"type" example:"applicant" enums:"applicant,household_member,scheme,application"`
+	ID          string            `json:"id"`
+	Title       string            `json:"title"`                  // The entity's name; for applications, the applicant's
+	ApplicantID string            `json:"applicant_id,omitempty"` // For household members and applications
+	SchemeID    string            `json:"scheme_id,omitempty"`    // For applications
+	Score       int               `json:"score"`                  // Number of search terms matched
+	Highlights  map[string]string `json:"highlights"`             // Matched fields, HTML-escaped with matches in <mark> tags
+}
+
+// SearchTerms splits text into its distinct lower-cased words, as matched by
+// search
+func SearchTerms(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	seen := make(map[string]bool, len(words))
+	var terms []string
+	for _, w := range words {
+		if !seen[w] {
+			seen[w] = true
+			terms = append(terms, w)
+		}
+	}
+	return terms
+}
+
+// Highlight returns text, HTML-escaped, with the words matching any of the
+// given lower-cased terms wrapped in <mark> tags, and the number of distinct
+// terms matched
+func Highlight(text string, terms []string) (string, int) {
+	wanted := make(map[string]bool, len(terms))
+	for _, t := range terms {
+		wanted[t] = true
+	}
+
+	var b strings.Builder
+	matched := make(map[string]bool)
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		if !isWordRune(runes[i]) {
+			b.WriteString(html.EscapeString(string(runes[i])))
+			i++
+			continue
+		}
+
+		j := i
+		for j < len(runes) && isWordRune(runes[j]) {
+			j++
+		}
+		word := string(runes[i:j])
+		if lower := strings.ToLower(word); wanted[lower] {
+			matched[lower] = true
+			b.WriteString("<mark>" + html.EscapeString(word) + "</mark>")
+		} else {
+			b.WriteString(html.EscapeString(word))
+		}
+		i = j
+	}
+	return b.String(), len(matched)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// likeConditions returns a condition matching rows where the column contains
+// any of the terms, case-insensitively, with its arguments. Matches are
+// confirmed by whole word with Highlight.
+func likeConditions(column string, terms []string) (string, []interface{}) {
+	conditions := make([]string, len(terms))
+	args := make([]interface{}, len(terms))
+	for i, t := range terms {
+		conditions[i] = "LOWER(" + column + ") LIKE ? ESCAPE '!'"
+		args[i] = "%" + escapeLike(t) + "%"
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")", args
+}
+
+// escapeLike escapes the LIKE wildcards in a search term with "!", which
+// unlike backslash is quoted the same way in MySQL and SQLite
+func escapeLike(s string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(s)
+}
+
+// indexTerms replaces the search terms of an applicant or household member
+// with the blind-indexed words of name
+func (r *ApplicantRepository) indexTerms(entityType, entityID, name string) error {
+	if err := r.removeTerms(entityType, entityID); err != nil {
+		return err
+	}
+	for _, term := range SearchTerms(name) {
+		if _, err := r.conn().Exec(`INSERT INTO search_terms (entity_type, entity_id, term_hash) VALUES (?, ?, ?)`,
+			entityType, entityID, r.Cipher.Hash(term)); err != nil {
+			return fmt.Errorf("error indexing search terms: %v", err)
+		}
+	}
+	return nil
+}
+
+// removeTerms deletes the search terms of an applicant or household member
+func (r *ApplicantRepository) removeTerms(entityType, entityID string) error {
+	if _, err := r.conn().Exec(`DELETE FROM search_terms WHERE entity_type = ? AND entity_id = ?`, entityType, entityID); err != nil {
+		return fmt.Errorf("error removing search terms: %v", err)
+	}
+	return nil
+}
+
+// matchTerms returns the IDs of entities of the given type with any of the
+// terms in their name, most matches first. Hashes under every key are
+// matched, for rows not yet re-indexed after a key rotation.
+func (r *ApplicantRepository) matchTerms(entityType string, terms []string, limit int) ([]string, error) {
+	var hashes []string
+	for _, term := range terms {
+		hashes = append(hashes, r.Cipher.Hashes(term)...)
+	}
+	if len(hashes) == 0 {
+		return nil, nil
+	}
+
+	placeholders, args := inClause(hashes)
+	query := `SELECT entity_id FROM search_terms
+			  WHERE entity_type = ? AND term_hash IN (` + placeholders + `)
+			  GROUP BY entity_id
+			  ORDER BY COUNT(*) DESC, entity_id
+			  LIMIT ?`
+
+	rows, err := r.conn().Query(query, append(append([]interface{}{entityType}, args...), limit)...)
+	if err != nil {
+		return nil, fmt.Errorf("error searching %s names: %v", entityType, err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("error scanning search row: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating search rows: %v", err)
+	}
+	return ids, nil
+}
+
+// Search returns up to limit applicants, excluding soft-deleted ones, with
+// any of the lower-cased terms in their name, most matches first
+func (r *ApplicantRepository) Search(terms []string, limit int) ([]Applicant, error) {
+	ids, err := r.matchTerms(SearchTypeApplicant, terms, limit)
+	if err != nil {
+		return nil, err
+	}
+	byID, err := r.GetByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var applicants []Applicant
+	for _, id := range ids {
+		if a := byID[id]; a != nil && a.DeletedAt == nil {
+			applicants = append(applicants, *a)
+		}
+	}
+	return applicants, nil
+}
+
+// SearchHouseholdMembers returns up to limit household members of applicants
+// that are not soft-deleted, with any of the lower-cased terms in their name,
+// most matches first
+func (r *ApplicantRepository) SearchHouseholdMembers(terms []string, limit int) ([]HouseholdMember, error) {
+	ids, err := r.matchTerms(SearchTypeHouseholdMember, terms, limit)
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	placeholders, args := inClause(ids)
+	query := `SELECT ` + householdMemberColumns + `
+			  FROM household_members
+			  WHERE id IN (` + placeholders + `)
+			  AND applicant_id IN (SELECT id FROM applicants WHERE deleted_at IS NULL)`
+
+	rows, err := r.conn().Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying household members: %v", err)
+	}
+	defer rows.Close()
+
+	byID := make(map[string]HouseholdMember)
+	for rows.Next() {
+		m, err := r.scanHouseholdMember(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning household member row: %v", err)
+		}
+		byID[m.ID] = m
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating household member rows: %v", err)
+	}
+
+	var members []HouseholdMember
+	for _, id := range ids {
+		if m, ok := byID[id]; ok {
+			members = append(members, m)
+		}
+	}
+	return members, nil
+}
+
+// Reindex rebuilds the search terms of every applicant and household member,
+// for data written before search was added. It returns the number of
+// applicants and household members indexed.
+func (r *ApplicantRepository) Reindex() (int, int, error) {
+	applicants, err := r.Find(ApplicantFilter{IncludeDeleted: true})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	members := 0
+	err = runInTx(r.DB, r.tx, func(tx *sql.Tx) error {
+		txRepo := r.WithTx(tx)
+		for _, a := range applicants {
+			if err := txRepo.indexTerms(SearchTypeApplicant, a.ID, a.Name); err != nil {
+				return err
+			}
+			for _, m := range a.Household {
+				if err := txRepo.indexTerms(SearchTypeHouseholdMember, m.ID, m.Name); err != nil {
+					return err
+				}
+				members++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return len(applicants), members, nil
+}
+
+// Search returns up to limit schemes with any of the lower-cased terms in
+// their name
+func (r *SchemeRepository) Search(terms []string, limit int) ([]Scheme, error) {
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	condition, args := likeConditions("name", terms)
+	query := `SELECT ` + schemeColumns + `
+			  FROM schemes
+			  WHERE ` + condition + `
+			  ORDER BY name ASC
+			  LIMIT ?`
+
+	rows, err := r.conn().Query(query, append(args, limit)...)
+	if err != nil {
+		return nil, fmt.Errorf("error searching schemes: %v", err)
+	}
+	defer rows.Close()
+
+	var schemes []Scheme
+	for rows.Next() {
+		s, err := scanScheme(rows)
+		if err != nil {
+			return nil, err
+		}
+		schemes = append(schemes, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating scheme rows: %v", err)
+	}
+	return schemes, nil
+}
+
+// Search returns up to limit applications, excluding soft-deleted ones, with
+// any of the lower-cased terms in their notes, newest first. Applicants and
+// schemes are attached.
+func (r *ApplicationRepository) Search(terms []string, limit int) ([]Application, error) {
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	condition, args := likeConditions("notes", terms)
+	query := `SELECT ` + applicationColumns + `
+			  FROM applications
+			  WHERE deleted_at IS NULL AND ` + condition + `
+			  ORDER BY application_date DESC, created_at DESC
+			  LIMIT ?`
+
+	applications, err := r.queryApplications(query, append(args, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.attachApplicants(applications); err != nil {
+		return nil, err
+	}
+	if err := r.attachSchemes(applications); err != nil {
+		return nil, err
+	}
+	return applications, nil
+}
@@ -8,20 +8,58 @@ type SwaggerApplication struct {
 	ID              string     `json:"id" example:"01913b7a-4493-74b2-93f8-e684c4ca935c"`
 	ApplicantID     string     `json:"applicant_id" example:"01913b7a-4493-74b2-93f8-e684c4ca935c"`
 	SchemeID        string     `json:"scheme_id" example:"01913b89-9a43-7163-8757-01cc254783f3"`
-	Status          string     `json:"status" example:"pending" enums:"pending,approved,rejected"`
-	ApplicationDate time.Time  `json:"application_date"`
+	Status          string     `json:"status" example:"pending" enums:"pending,approved,rejected,withdrawn"`
+	ApplicationDate string     `json:"application_date" format:"date" example:"2026-10-14"` // The day it was made; created_at has the time
 	DecisionDate    *time.Time `json:"decision_date,omitempty"`
 	Notes           string     `json:"notes,omitempty"`
+	Version         int        `json:"version" example:"1"`
+	SchemeVersion   int        `json:"scheme_version,omitempty" example:"1"`
 	CreatedAt       time.Time  `json:"created_at,omitempty"`
 	UpdatedAt       time.Time  `json:"updated_at,omitempty"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty"`
 	Applicant       *Applicant `json:"applicant,omitempty"`
 	Scheme          *Scheme    `json:"scheme,omitempty"`
+
+	DecidedBy                string `json:"decided_by,omitempty" example:"01913b90-1a2b-7c3d-8e4f-5a6b7c8d9e0f"`
+	DecisionReason           string `json:"decision_reason,omitempty" example:"Meets all criteria"`
+	RecommendedBenefitAmount string `json:"recommended_benefit_amount,omitempty" example:"500.00"`
+
+	AssignedTo string     `json:"assigned_to,omitempty" example:"01913b90-1a2b-7c3d-8e4f-5a6b7c8d9e0f"`
+	AssignedAt *time.Time `json:"assigned_at,omitempty"`
+
+	WithdrawnAt      *time.Time `json:"withdrawn_at,omitempty"`
+	WithdrawnBy      string     `json:"withdrawn_by,omitempty" example:"01913b90-1a2b-7c3d-8e4f-5a6b7c8d9e0f"`
+	WithdrawalReason string     `json:"withdrawal_reason,omitempty" example:"No longer needed; found employment"`
+
+	PreviousApplicationID string `json:"previous_application_id,omitempty" example:"01913b7a-4493-74b2-93f8-e684c4ca935c"`
 }
 
 // SwaggerApplicationResponse is a Swagger-friendly version of ApplicationResponse
-// @Description Response containing an application with applicant and scheme details
+// @Description Response containing an application with applicant and scheme details, which lists only include when expanded
 type SwaggerApplicationResponse struct {
 	SwaggerApplication
-	Applicant ApplicantResponse `json:"applicant"`
-	Scheme    SchemeResponse    `json:"scheme"`
+	Applicant *ApplicantResponse `json:"applicant,omitempty"`
+	Scheme    *SchemeResponse    `json:"scheme,omitempty"`
+
+	PreviousApplications []SwaggerApplication `json:"previous_applications,omitempty"`
+}
+
+// SwaggerApplicantProfile is a Swagger-friendly version of ApplicantProfile
+// @Description Everything known about an applicant: household, applications with their schemes and documents, eligible schemes, case notes, referrals and consents
+type SwaggerApplicantProfile struct {
+	Applicant       ApplicantResponse           `json:"applicant"`
+	Applications    []SwaggerProfileApplication `json:"applications"`
+	EligibleSchemes []SchemeResponse            `json:"eligible_schemes"`
+	CaseNotes       []CaseNote                  `json:"case_notes"`
+	Referrals       []Referral                  `json:"referrals"`
+	Consents        []Consent                   `json:"consents"`
+	AsOf            time.Time                   `json:"as_of"`
+}
+
+// SwaggerProfileApplication is a Swagger-friendly version of ProfileApplication
+// @Description Application in an applicant's profile, with its scheme and documents
+type SwaggerProfileApplication struct {
+	SwaggerApplication
+	Scheme    SchemeResponse `json:"scheme"`
+	Documents []Document     `json:"documents"`
 }
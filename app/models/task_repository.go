@@ -0,0 +1,251 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaskReminderJob is the payload of a tasks.remind job
+type TaskReminderJob struct {
+	AsOf time.Time `json:"as_of"` // Tasks due before this time plus the reminder lead are reminded
+}
+
+// TaskReminderResult is the result of a tasks.remind job
+type TaskReminderResult struct {
+	AsOf     time.Time `json:"as_of"`
+	Reminded int       `json:"reminded"` // Tasks whose assignee was emailed
+	Skipped  int       `json:"skipped"`  // Tasks whose assignee has no email address
+}
+
+// OverdueAt reports whether the task is open and past its due date at the
+// given time
+func (t *Task) OverdueAt(at time.Time) bool {
+	return t.Status == TaskOpen && t.DueDate != nil && t.DueDate.Before(at)
+}
+
+// TaskRepository handles database operations for tasks
+type TaskRepository struct {
+	DB *sql.DB
+	tx *sql.Tx
+}
+
+// NewTaskRepository creates a new repository with the given database connection
+func NewTaskRepository(db *sql.DB) *TaskRepository {
+	return &TaskRepository{DB: db}
+}
+
+// WithTx returns a copy of the repository that runs its queries in tx
+func (r *TaskRepository) WithTx(tx *sql.Tx) *TaskRepository {
+	return &TaskRepository{DB: r.DB, tx: tx}
+}
+
+// conn returns the transaction the repository is bound to, or the database
+func (r *TaskRepository) conn() DBTX {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.DB
+}
+
+// TaskFilter holds optional parameters for listing tasks. Zero values are
+// ignored.
+type TaskFilter struct {
+	Status        string
+	ApplicantID   string
+	ApplicationID string
+	AssignedTo    string    // Assigned to this user ID
+	Unassigned    bool      // Not assigned to anyone
+	DueBefore     time.Time // Due before this time; tasks without a due date are excluded
+	NotReminded   bool      // Assignee not yet reminded
+}
+
+// whereClause builds a parameterized WHERE clause for the filter
+func (f TaskFilter) whereClause() (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if f.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, f.Status)
+	}
+	if f.ApplicantID != "" {
+		conditions = append(conditions, "applicant_id = ?")
+		args = append(args, f.ApplicantID)
+	}
+	if f.ApplicationID != "" {
+		conditions = append(conditions, "application_id = ?")
+		args = append(args, f.ApplicationID)
+	}
+	if f.AssignedTo != "" {
+		conditions = append(conditions, "assigned_to = ?")
+		args = append(args, f.AssignedTo)
+	}
+	if f.Unassigned {
+		conditions = append(conditions, "assigned_to IS NULL")
+	}
+	if !f.DueBefore.IsZero() {
+		conditions = append(conditions, "due_date < ?")
+		args = append(args, f.DueBefore)
+	}
+	if f.NotReminded {
+		conditions = append(conditions, "reminded_at IS NULL")
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// taskColumns is the column list read by scanTask
+const taskColumns = `id, applicant_id, application_id, title, description, due_date, assigned_to, status,
+	created_by, completed_at, reminded_at, created_at, updated_at`
+
+// scanTask scans a row selected with taskColumns, setting Overdue as of now
+func scanTask(row rowScanner) (Task, error) {
+	var t Task
+	var applicationID, description, assignedTo, createdBy sql.NullString
+	var dueDate, completedAt, remindedAt sql.NullTime
+
+	if err := row.Scan(&t.ID, &t.ApplicantID, &applicationID, &t.Title, &description, &dueDate,
+		&assignedTo, &t.Status, &createdBy, &completedAt, &remindedAt, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return t, err
+		}
+		return t, fmt.Errorf("error scanning task row: %v", err)
+	}
+	t.ApplicationID = applicationID.String
+	t.Description = description.String
+	t.AssignedTo = assignedTo.String
+	t.CreatedBy = createdBy.String
+	if dueDate.Valid {
+		t.DueDate = &dueDate.Time
+	}
+	if completedAt.Valid {
+		t.CompletedAt = &completedAt.Time
+	}
+	if remindedAt.Valid {
+		t.RemindedAt = &remindedAt.Time
+	}
+	t.Overdue = t.OverdueAt(time.Now())
+
+	return t, nil
+}
+
+// Find retrieves the tasks matching the filter, soonest due first, with tasks
+// without a due date last
+func (r *TaskRepository) Find(filter TaskFilter) ([]Task, error) {
+	where, args := filter.whereClause()
+	query := `SELECT ` + taskColumns + `
+			  FROM tasks` + where + `
+			  ORDER BY due_date IS NULL, due_date ASC, created_at ASC, id ASC`
+
+	rows, err := r.conn().Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying tasks: %v", err)
+	}
+	defer rows.Close()
+
+	var tasks []Task
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating task rows: %v", err)
+	}
+
+	return tasks, nil
+}
+
+// Overdue retrieves the open tasks matching the filter that are past their
+// due date at the given time, the longest overdue first
+func (r *TaskRepository) Overdue(filter TaskFilter, at time.Time) ([]Task, error) {
+	filter.Status = TaskOpen
+	filter.DueBefore = at
+	return r.Find(filter)
+}
+
+// GetByID retrieves a task by ID
+func (r *TaskRepository) GetByID(id string) (*Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id = ?`
+
+	t, err := scanTask(r.conn().QueryRow(query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Create inserts a new task
+func (r *TaskRepository) Create(t *Task) error {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	now := time.Now()
+	t.CreatedAt = now
+	t.UpdatedAt = now
+	t.Overdue = t.OverdueAt(now)
+
+	query := `INSERT INTO tasks (id, applicant_id, application_id, title, description, due_date, assigned_to,
+			  status, created_by, completed_at, reminded_at, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.conn().Exec(query, t.ID, t.ApplicantID, nullString(t.ApplicationID), t.Title,
+		nullString(t.Description), t.DueDate, nullString(t.AssignedTo), t.Status, nullString(t.CreatedBy),
+		t.CompletedAt, t.RemindedAt, t.CreatedAt, t.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating task: %v", err)
+	}
+
+	return nil
+}
+
+// Update saves every field of a task except who created it and when
+func (r *TaskRepository) Update(t *Task) error {
+	t.UpdatedAt = time.Now()
+	t.Overdue = t.OverdueAt(t.UpdatedAt)
+
+	query := `UPDATE tasks
+			  SET applicant_id = ?, application_id = ?, title = ?, description = ?, due_date = ?, assigned_to = ?,
+			  status = ?, completed_at = ?, reminded_at = ?, updated_at = ?
+			  WHERE id = ?`
+
+	_, err := r.conn().Exec(query, t.ApplicantID, nullString(t.ApplicationID), t.Title, nullString(t.Description),
+		t.DueDate, nullString(t.AssignedTo), t.Status, t.CompletedAt, t.RemindedAt, t.UpdatedAt, t.ID)
+	if err != nil {
+		return fmt.Errorf("error updating task: %v", err)
+	}
+
+	return nil
+}
+
+// MarkReminded records that the task's assignee was reminded at the given time
+func (r *TaskRepository) MarkReminded(t *Task, at time.Time) error {
+	t.RemindedAt = &at
+
+	if _, err := r.conn().Exec(`UPDATE tasks SET reminded_at = ? WHERE id = ?`, at, t.ID); err != nil {
+		return fmt.Errorf("error updating task reminder: %v", err)
+	}
+	return nil
+}
+
+// Delete removes a task
+func (r *TaskRepository) Delete(id string) error {
+	if _, err := r.conn().Exec(`DELETE FROM tasks WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("error deleting task: %v", err)
+	}
+	return nil
+}
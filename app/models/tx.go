@@ -0,0 +1,52 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// DBTX is the subset of database operations shared by *sql.DB and *sql.Tx,
+// allowing repositories to run either directly or inside a transaction
+type DBTX interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// WithTx runs fn inside a new transaction, committing if fn returns nil and
+// rolling back otherwise. Repositories can be bound to the transaction with
+// their WithTx methods to compose several operations atomically.
+func WithTx(db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %v", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%v (rollback failed: %v)", err, rbErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %v", err)
+	}
+	return nil
+}
+
+// runInTx runs fn in the given transaction if there is one, otherwise in a new
+// transaction on db
+func runInTx(db *sql.DB, tx *sql.Tx, fn func(tx *sql.Tx) error) error {
+	if tx != nil {
+		return fn(tx)
+	}
+	return WithTx(db, fn)
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserRepository handles database operations for users
+type UserRepository struct {
+	DB *sql.DB
+}
+
+// NewUserRepository creates a new repository with the given database connection
+func NewUserRepository(db *sql.DB) *UserRepository {
+	return &UserRepository{DB: db}
+}
+
+// GetByUsername retrieves a user by username
+func (r *UserRepository) GetByUsername(username string) (*User, error) {
+	query := `SELECT id, username, password_hash, role, email, created_at, updated_at
+			  FROM users
+			  WHERE username = ?`
+
+	var u User
+	var email sql.NullString
+	err := r.DB.QueryRow(query, username).Scan(&u.ID, &u.Username, &u.PasswordHash, &u.Role,
+		&email, &u.CreatedAt, &u.UpdatedAt)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No user found
+		}
+		return nil, fmt.Errorf("error querying user: %v", err)
+	}
+	u.Email = email.String
+
+	return &u, nil
+}
+
+// GetByID retrieves a user by ID
+func (r *UserRepository) GetByID(id string) (*User, error) {
+	query := `SELECT id, username, password_hash, role, email, created_at, updated_at
+			  FROM users
+			  WHERE id = ?`
+
+	var u User
+	var email sql.NullString
+	err := r.DB.QueryRow(query, id).Scan(&u.ID, &u.Username, &u.PasswordHash, &u.Role,
+		&email, &u.CreatedAt, &u.UpdatedAt)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // No user found
+		}
+		return nil, fmt.Errorf("error querying user: %v", err)
+	}
+	u.Email = email.String
+
+	return &u, nil
+}
+
+// Create inserts a new user into the database
+func (r *UserRepository) Create(u *User) error {
+	// Generate UUID if not provided
+	if u.ID == "" {
+		u.ID = uuid.New().String()
+	}
+
+	now := time.Now()
+	u.CreatedAt = now
+	u.UpdatedAt = now
+
+	query := `INSERT INTO users (id, username, password_hash, role, email, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := r.DB.Exec(query, u.ID, u.Username, u.PasswordHash, u.Role, nullString(u.Email), u.CreatedAt, u.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating user: %v", err)
+	}
+
+	return nil
+}
+
+// Count returns the number of users
+func (r *UserRepository) Count() (int, error) {
+	var count int
+	if err := r.DB.QueryRow(`SELECT COUNT(*) FROM users`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("error counting users: %v", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,371 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Domain events, which webhooks can subscribe to and are published to the
+// message broker
+const (
+	EventApplicationCreated   = "application.created"
+	EventApplicationApproved  = "application.approved"
+	EventApplicationRejected  = "application.rejected"
+	EventApplicationWithdrawn = "application.withdrawn"
+	EventApplicantCreated     = "applicant.created"
+	EventApplicantUpdated     = "applicant.updated"
+)
+
+// WebhookEvents lists every event that webhooks can subscribe to
+var WebhookEvents = []string{
+	EventApplicationCreated,
+	EventApplicationApproved,
+	EventApplicationRejected,
+	EventApplicationWithdrawn,
+	EventApplicantCreated,
+	EventApplicantUpdated,
+}
+
+// Webhook delivery statuses
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+// WebhookEvent is the JSON body posted to webhooks
+type WebhookEvent struct {
+	ID         string      `json:"id"`
+	Event      string      `json:"event"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+// WebhookRepository handles database operations for webhooks and their
+// delivery outbox
+type WebhookRepository struct {
+	DB            *sql.DB
+	PublishEvents bool // Whether events are also added to the outbox_events outbox of the message broker
+	tx            *sql.Tx
+}
+
+// NewWebhookRepository creates a new repository with the given database connection
+func NewWebhookRepository(db *sql.DB) *WebhookRepository {
+	return &WebhookRepository{DB: db}
+}
+
+// WithTx returns a copy of the repository that runs its queries in tx
+func (r *WebhookRepository) WithTx(tx *sql.Tx) *WebhookRepository {
+	return &WebhookRepository{DB: r.DB, PublishEvents: r.PublishEvents, tx: tx}
+}
+
+// conn returns the transaction the repository is bound to, or the database
+func (r *WebhookRepository) conn() DBTX {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.DB
+}
+
+// webhookColumns is the column list read by scanWebhook
+const webhookColumns = `id, url, secret, events, active, created_at, updated_at`
+
+// scanWebhook scans a row selected with webhookColumns
+func scanWebhook(row rowScanner) (Webhook, error) {
+	var w Webhook
+	var eventsJSON []byte
+
+	if err := row.Scan(&w.ID, &w.URL, &w.Secret, &eventsJSON, &w.Active,
+		&w.CreatedAt, &w.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return w, err
+		}
+		return w, fmt.Errorf("error scanning webhook row: %v", err)
+	}
+
+	if err := json.Unmarshal(eventsJSON, &w.Events); err != nil {
+		return w, fmt.Errorf("error unmarshaling webhook events: %v", err)
+	}
+
+	return w, nil
+}
+
+// Subscribes reports whether the webhook is active and subscribed to event
+func (w *Webhook) Subscribes(event string) bool {
+	if !w.Active {
+		return false
+	}
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// GetAll retrieves all webhooks
+func (r *WebhookRepository) GetAll() ([]Webhook, error) {
+	query := `SELECT ` + webhookColumns + `
+			  FROM webhooks
+			  ORDER BY created_at ASC`
+
+	rows, err := r.conn().Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("error querying webhooks: %v", err)
+	}
+	defer rows.Close()
+
+	var webhooks []Webhook
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, w)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhook rows: %v", err)
+	}
+
+	return webhooks, nil
+}
+
+// GetByID retrieves a webhook by ID
+func (r *WebhookRepository) GetByID(id string) (*Webhook, error) {
+	query := `SELECT ` + webhookColumns + `
+			  FROM webhooks
+			  WHERE id = ?`
+
+	w, err := scanWebhook(r.conn().QueryRow(query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // No webhook found
+		}
+		return nil, err
+	}
+
+	return &w, nil
+}
+
+// Create inserts a new webhook
+func (r *WebhookRepository) Create(w *Webhook) error {
+	// Generate UUID if not provided
+	if w.ID == "" {
+		w.ID = uuid.New().String()
+	}
+
+	now := time.Now()
+	w.CreatedAt = now
+	w.UpdatedAt = now
+
+	eventsJSON, err := json.Marshal(w.Events)
+	if err != nil {
+		return fmt.Errorf("error marshaling webhook events: %v", err)
+	}
+
+	query := `INSERT INTO webhooks (id, url, secret, events, active, created_at, updated_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	_, err = r.conn().Exec(query, w.ID, w.URL, w.Secret, eventsJSON, w.Active, w.CreatedAt, w.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("error creating webhook: %v", err)
+	}
+
+	return nil
+}
+
+// Delete removes a webhook and its deliveries
+func (r *WebhookRepository) Delete(id string) error {
+	query := `DELETE FROM webhooks WHERE id = ?`
+	_, err := r.conn().Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("error deleting webhook: %v", err)
+	}
+	return nil
+}
+
+// WebhookDeliveryJob is the payload of a JobWebhookDelivery job
+type WebhookDeliveryJob struct {
+	DeliveryID string `json:"delivery_id"`
+}
+
+// Enqueue adds a delivery of the event to the outbox for every active webhook
+// subscribed to it, with a job to send each one, and, if PublishEvents is set,
+// adds the event to the outbox of the message broker. Call it in the same
+// transaction as the change the event describes, so that the event is queued
+// if and only if the change commits.
+func (r *WebhookRepository) Enqueue(event string, data interface{}) error {
+	webhooks, err := r.GetAll()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	payload := WebhookEvent{
+		ID:         uuid.New().String(),
+		Event:      event,
+		OccurredAt: now,
+		Data:       data,
+	}
+
+	jobs := &JobRepository{DB: r.DB, tx: r.tx}
+	var body []byte
+	query := `INSERT INTO webhook_deliveries (id, webhook_id, event_id, event, payload, status, attempts, next_attempt_at, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, 0, ?, ?)`
+
+	for _, w := range webhooks {
+		if !w.Subscribes(event) {
+			continue
+		}
+
+		if body == nil {
+			if body, err = json.Marshal(payload); err != nil {
+				return fmt.Errorf("error marshaling webhook payload: %v", err)
+			}
+		}
+
+		deliveryID := uuid.New().String()
+		if _, err := r.conn().Exec(query, deliveryID, w.ID, payload.ID, event, body,
+			DeliveryPending, now, now); err != nil {
+			return fmt.Errorf("error queueing webhook delivery: %v", err)
+		}
+		// The job shares the delivery's ID, so a failed delivery's job is
+		// easily found to retry
+		if _, err := jobs.enqueue(deliveryID, JobWebhookDelivery, WebhookDeliveryJob{DeliveryID: deliveryID}, ""); err != nil {
+			return err
+		}
+	}
+
+	if r.PublishEvents {
+		if body == nil {
+			if body, err = json.Marshal(payload); err != nil {
+				return fmt.Errorf("error marshaling webhook payload: %v", err)
+			}
+		}
+		return createOutboxEvent(r.conn(), payload, body)
+	}
+	return nil
+}
+
+// deliveryColumns is the column list read by scanDelivery
+const deliveryColumns = `d.id, d.webhook_id, d.event_id, d.event, d.payload, d.status, d.attempts,
+	d.next_attempt_at, d.last_error, d.delivered_at, d.created_at`
+
+// scanDelivery scans a row selected with deliveryColumns, followed by any
+// extra destinations
+func scanDelivery(row rowScanner, extra ...interface{}) (WebhookDelivery, error) {
+	var d WebhookDelivery
+	var payload []byte
+	var lastError sql.NullString
+	var deliveredAt sql.NullTime
+
+	dest := []interface{}{&d.ID, &d.WebhookID, &d.EventID, &d.Event, &payload, &d.Status, &d.Attempts,
+		&d.NextAttemptAt, &lastError, &deliveredAt, &d.CreatedAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return d, err
+		}
+		return d, fmt.Errorf("error scanning webhook delivery row: %v", err)
+	}
+
+	d.Payload = json.RawMessage(payload)
+	if lastError.Valid {
+		d.LastError = lastError.String
+	}
+	if deliveredAt.Valid {
+		d.DeliveredAt = &deliveredAt.Time
+	}
+
+	return d, nil
+}
+
+// GetDeliveries retrieves the most recent deliveries to a webhook, newest first
+func (r *WebhookRepository) GetDeliveries(webhookID string, limit int) ([]WebhookDelivery, error) {
+	query := `SELECT ` + deliveryColumns + `
+			  FROM webhook_deliveries d
+			  WHERE d.webhook_id = ?
+			  ORDER BY d.created_at DESC
+			  LIMIT ?`
+
+	rows, err := r.conn().Query(query, webhookID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error querying webhook deliveries: %v", err)
+	}
+	defer rows.Close()
+
+	var deliveries []WebhookDelivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhook delivery rows: %v", err)
+	}
+
+	return deliveries, nil
+}
+
+// GetDeliveryForDispatch retrieves a delivery by ID with the URL and secret of
+// its webhook. It returns nil if there is none, e.g. because the webhook has
+// been deleted.
+func (r *WebhookRepository) GetDeliveryForDispatch(id string) (*WebhookDelivery, error) {
+	query := `SELECT ` + deliveryColumns + `, w.url, w.secret
+			  FROM webhook_deliveries d
+			  JOIN webhooks w ON w.id = d.webhook_id
+			  WHERE d.id = ?`
+
+	var url, secret string
+	d, err := scanDelivery(r.conn().QueryRow(query, id), &url, &secret)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // No delivery found
+		}
+		return nil, err
+	}
+	d.URL, d.Secret = url, secret
+
+	return &d, nil
+}
+
+// MarkDelivered records a successful delivery
+func (r *WebhookRepository) MarkDelivered(id string, attempts int) error {
+	query := `UPDATE webhook_deliveries
+			  SET status = ?, attempts = ?, delivered_at = ?, last_error = NULL
+			  WHERE id = ?`
+
+	if _, err := r.conn().Exec(query, DeliveryDelivered, attempts, time.Now(), id); err != nil {
+		return fmt.Errorf("error marking webhook delivery delivered: %v", err)
+	}
+	return nil
+}
+
+// MarkAttemptFailed records a failed delivery attempt. The delivery is retried
+// at nextAttempt, or marked failed for good if nextAttempt is nil.
+func (r *WebhookRepository) MarkAttemptFailed(id string, attempts int, lastError string, nextAttempt *time.Time) error {
+	status := DeliveryPending
+	next := time.Now()
+	if nextAttempt != nil {
+		next = *nextAttempt
+	} else {
+		status = DeliveryFailed
+	}
+
+	query := `UPDATE webhook_deliveries
+			  SET status = ?, attempts = ?, last_error = ?, next_attempt_at = ?
+			  WHERE id = ?`
+
+	if _, err := r.conn().Exec(query, status, attempts, lastError, next, id); err != nil {
+		return fmt.Errorf("error recording webhook delivery failure: %v", err)
+	}
+	return nil
+}
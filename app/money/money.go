@@ -0,0 +1,147 @@
+// Package money represents amounts of money exactly, as whole cents, so that
+// benefit amounts, budgets and their totals add up without the rounding
+// errors of floating point.
+package money
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the ISO 4217 code of the currency amounts are in unless
+// they say otherwise
+const DefaultCurrency = "SGD"
+
+// Currencies lists the ISO 4217 codes of the currencies amounts can be in.
+// Only Singapore dollars are paid out for now; the code is stored with each
+// benefit so that others can be added without converting stored amounts.
+var Currencies = []string{DefaultCurrency}
+
+// Amount is an amount of money in cents. It is written to JSON as a decimal
+// string with two places, such as "1234.50", and read from either such a
+// string or a JSON number, which must not have more than two decimal places.
+// It is stored in the database as an integer number of cents.
+type Amount int64
+
+// ErrInvalid is returned when an amount cannot be parsed
+var ErrInvalid = errors.New("invalid amount")
+
+// String formats the amount as a decimal with two places, e.g. "-12.05"
+func (a Amount) String() string {
+	sign := ""
+	cents := int64(a)
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// Parse reads a decimal amount such as "12", "12.5" or "-12.05", with at most
+// two decimal places
+func Parse(text string) (Amount, error) {
+	s := strings.TrimSpace(text)
+	negative := false
+	if rest, ok := strings.CutPrefix(s, "-"); ok {
+		negative, s = true, rest
+	}
+
+	whole, fraction, hasPoint := strings.Cut(s, ".")
+	if whole == "" || !digits(whole) || (hasPoint && (fraction == "" || len(fraction) > 2 || !digits(fraction))) {
+		return 0, fmt.Errorf("%w %q: want a decimal with at most two decimal places", ErrInvalid, text)
+	}
+	for len(fraction) < 2 {
+		fraction += "0"
+	}
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > (math.MaxInt64-99)/100 {
+		return 0, fmt.Errorf("%w %q: out of range", ErrInvalid, text)
+	}
+	cents, _ := strconv.ParseInt(fraction, 10, 64)
+	amount := Amount(units*100 + cents)
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+// digits reports whether s is made only of ASCII digits
+func digits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// MarshalJSON writes the amount as a decimal string
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+// UnmarshalJSON reads the amount from a decimal string or a JSON number. A
+// number is read from its text, not through a float, so 0.1 is exactly ten
+// cents; exponents are not accepted. null leaves the amount unchanged.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	text := string(bytes.TrimSpace(data))
+	if text == "null" {
+		return nil
+	}
+	if strings.HasPrefix(text, `"`) {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+	}
+	parsed, err := Parse(text)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// Value stores the amount as an integer number of cents
+func (a Amount) Value() (driver.Value, error) {
+	return int64(a), nil
+}
+
+// Scan reads an integer number of cents. Drivers may return sums of cents
+// as decimals or floats, which are rounded to the nearest cent.
+func (a *Amount) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case int64:
+		*a = Amount(v)
+	case float64:
+		*a = Amount(math.Round(v))
+	case []byte:
+		return a.scanText(string(v))
+	case string:
+		return a.scanText(v)
+	default:
+		return fmt.Errorf("cannot scan %T into an amount", src)
+	}
+	return nil
+}
+
+// scanText reads a number of cents from a database's text representation of
+// an integer or decimal
+func (a *Amount) scanText(s string) error {
+	if cents, err := strconv.ParseInt(s, 10, 64); err == nil {
+		*a = Amount(cents)
+		return nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("cannot scan %q into an amount: %v", s, err)
+	}
+	*a = Amount(math.Round(f))
+	return nil
+}
@@ -0,0 +1,300 @@
+// Package notify emails applicants when their applications are submitted,
+// approved, rejected or withdrawn, sends applicants the codes they sign in to
+// the portal with, reminds caseworkers of tasks that are due, tells users when
+// they are mentioned in a comment, and tells caseworkers when an application
+// assigned to them is withdrawn.
+//
+// Messages are rendered from the templates in templates/, named after the
+// application status, portal_code, task_reminder, comment_mention or
+// application_withdrawn, and
+// handed to a Sender by a background worker so that requests do not wait on
+// the mail server. Portal codes can also be sent by text message, using only
+// the body of the template.
+// Submission emails include the application's tracking token, with which the
+// applicant can check its status. Applicants without an email address or who
+// have opted out are skipped, as are users without an email address.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"log/slog"
+	"strings"
+	"text/template"
+	"time"
+
+	"one-client-view-2025tht/app/auth"
+	"one-client-view-2025tht/app/logging"
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/money"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"money": func(amount *money.Amount) string { return "$" + amount.String() },
+}).ParseFS(templateFS, "templates/*.tmpl"))
+
+// queueSize is the number of messages buffered for the worker. Messages
+// queued while it is full are dropped and logged.
+const queueSize = 100
+
+// Message is a plain-text email, or a text message without a subject, to a
+// single recipient
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers email messages
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// LogSender writes messages to the log instead of sending them, for local
+// development without a mail server or SMS gateway
+type LogSender struct{}
+
+// Send logs the message with the logger in ctx
+func (LogSender) Send(ctx context.Context, msg Message) error {
+	logging.FromContext(ctx).Info("Message", "to", msg.To, "subject", msg.Subject, "body", msg.Body)
+	return nil
+}
+
+// Notifier renders applicant notifications and queues them for sending. A nil
+// Notifier sends nothing.
+type Notifier struct {
+	Sender    Sender
+	SMSSender Sender               // Sends text messages, whose To is a phone number
+	Tracking  *auth.TrackingTokens // Issues the tracking tokens given to applicants; may be nil
+	Logger    *slog.Logger         // Also given to senders in their context
+	queue     chan delivery
+}
+
+// delivery is a message queued for a sender
+type delivery struct {
+	sender Sender
+	msg    Message
+}
+
+// NewNotifier creates a notifier that delivers emails with sender and text
+// messages with smsSender once Run is started, telling applicants the
+// tracking tokens issued by tracking
+func NewNotifier(sender, smsSender Sender, tracking *auth.TrackingTokens) *Notifier {
+	return &Notifier{
+		Sender:    sender,
+		SMSSender: smsSender,
+		Tracking:  tracking,
+		Logger:    logging.Component("notify"),
+		queue:     make(chan delivery, queueSize),
+	}
+}
+
+// templateData is passed to the message templates
+type templateData struct {
+	Applicant   *models.Applicant
+	Scheme      *models.Scheme
+	Application *models.Application
+	Task        *models.Task
+	Comment     *models.Comment
+	User        *models.User
+
+	TrackingToken string // The application's tracking token, if there is a token issuer
+
+	Code        string // A portal sign-in code
+	CodeMinutes int    // How long the code is valid for
+}
+
+// ApplicationStatus queues an email telling the applicant the application's
+// current status. The application must have its applicant and scheme attached.
+func (n *Notifier) ApplicationStatus(a *models.Application) {
+	if n == nil || a.Applicant == nil || a.Scheme == nil {
+		return
+	}
+	if a.Applicant.Email == "" || a.Applicant.EmailOptOut {
+		return
+	}
+
+	data := templateData{
+		Applicant:   a.Applicant,
+		Scheme:      a.Scheme,
+		Application: a,
+	}
+	if n.Tracking != nil {
+		token, err := n.Tracking.Issue(a.ID)
+		if err != nil {
+			n.Logger.Error("Failed to issue tracking token", "application_id", a.ID, "error", err)
+		}
+		data.TrackingToken = token
+	}
+
+	msg, err := render(a.Status, a.Applicant.Email, data)
+	if err != nil {
+		n.Logger.Error("Failed to render notification", "status", a.Status, "application_id", a.ID, "error", err)
+		return
+	}
+
+	if !n.enqueue(n.Sender, msg) {
+		n.Logger.Warn("Notification queue full, dropped notification", "status", a.Status, "application_id", a.ID)
+	}
+}
+
+// TaskReminder queues an email reminding the task's assignee that it is due
+// soon or overdue. It reports whether the reminder was queued, which it is not
+// if the assignee has no email address or the queue is full.
+func (n *Notifier) TaskReminder(t *models.Task, assignee *models.User) bool {
+	if n == nil || assignee.Email == "" {
+		return false
+	}
+
+	msg, err := render("task_reminder", assignee.Email, templateData{Task: t, User: assignee})
+	if err != nil {
+		n.Logger.Error("Failed to render task reminder", "task_id", t.ID, "error", err)
+		return false
+	}
+
+	if !n.enqueue(n.Sender, msg) {
+		n.Logger.Warn("Notification queue full, dropped task reminder", "task_id", t.ID)
+		return false
+	}
+	return true
+}
+
+// Mention queues an email telling a user they were mentioned in a comment on
+// an application. Users without an email address are skipped.
+func (n *Notifier) Mention(c *models.Comment, mentioned *models.User) {
+	if n == nil || mentioned.Email == "" {
+		return
+	}
+
+	msg, err := render("comment_mention", mentioned.Email, templateData{Comment: c, User: mentioned})
+	if err != nil {
+		n.Logger.Error("Failed to render mention notification", "comment_id", c.ID, "error", err)
+		return
+	}
+
+	if !n.enqueue(n.Sender, msg) {
+		n.Logger.Warn("Notification queue full, dropped mention notification", "comment_id", c.ID)
+	}
+}
+
+// ApplicationWithdrawn queues an email telling the user an application is
+// assigned to that it has been withdrawn, so they can stop reviewing it. The
+// application must have its scheme attached. Users without an email address
+// are skipped.
+func (n *Notifier) ApplicationWithdrawn(a *models.Application, assignee *models.User) {
+	if n == nil || a.Scheme == nil || assignee.Email == "" {
+		return
+	}
+
+	msg, err := render("application_withdrawn", assignee.Email, templateData{Scheme: a.Scheme, Application: a, User: assignee})
+	if err != nil {
+		n.Logger.Error("Failed to render withdrawal notification", "application_id", a.ID, "error", err)
+		return
+	}
+
+	if !n.enqueue(n.Sender, msg) {
+		n.Logger.Warn("Notification queue full, dropped withdrawal notification", "application_id", a.ID)
+	}
+}
+
+// PortalCode queues a code the applicant can sign in to the portal with, by
+// email or text message to their phone. The code is sent even if the
+// applicant has opted out of email notifications, since they asked for it. It
+// reports whether the code was queued, which it is not if the applicant has
+// no email address or phone for the channel or the queue is full.
+func (n *Notifier) PortalCode(applicant *models.Applicant, channel, code string, validFor time.Duration) bool {
+	if n == nil {
+		return false
+	}
+
+	sender, to := n.Sender, applicant.Email
+	if channel == models.OTPChannelSMS {
+		sender, to = n.SMSSender, applicant.Phone
+	}
+	if to == "" || sender == nil {
+		return false
+	}
+
+	msg, err := render("portal_code", to, templateData{
+		Applicant:   applicant,
+		Code:        code,
+		CodeMinutes: int(validFor.Minutes()),
+	})
+	if err != nil {
+		n.Logger.Error("Failed to render portal code", "applicant_id", applicant.ID, "error", err)
+		return false
+	}
+	if channel == models.OTPChannelSMS {
+		msg.Subject = ""
+	}
+
+	if !n.enqueue(sender, msg) {
+		n.Logger.Warn("Notification queue full, dropped portal code", "applicant_id", applicant.ID)
+		return false
+	}
+	return true
+}
+
+// enqueue queues a message for sender, reporting false if the queue is full
+func (n *Notifier) enqueue(sender Sender, msg Message) bool {
+	select {
+	case n.queue <- delivery{sender: sender, msg: msg}:
+		return true
+	default:
+		return false
+	}
+}
+
+// render executes the named template. Its first line is the subject, in the
+// form "Subject: ...", and the rest is the body.
+func render(name, to string, data templateData) (Message, error) {
+	var buf bytes.Buffer
+	if err := templates.ExecuteTemplate(&buf, name+".tmpl", data); err != nil {
+		return Message{}, err
+	}
+
+	header, body, _ := strings.Cut(buf.String(), "\n")
+	subject, ok := strings.CutPrefix(header, "Subject:")
+	if !ok {
+		return Message{}, fmt.Errorf("template %s does not start with a Subject line", name)
+	}
+
+	return Message{
+		To:      to,
+		Subject: strings.TrimSpace(subject),
+		Body:    strings.TrimLeft(body, "\n"),
+	}, nil
+}
+
+// Run sends queued messages until ctx is cancelled, then sends any still
+// queued before returning
+func (n *Notifier) Run(ctx context.Context) {
+	for {
+		select {
+		case d := <-n.queue:
+			n.send(ctx, d)
+		case <-ctx.Done():
+			for {
+				select {
+				case d := <-n.queue:
+					n.send(context.Background(), d)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// send delivers a message, logging failures. Notifications are best effort
+// and are not retried.
+func (n *Notifier) send(ctx context.Context, d delivery) {
+	if err := d.sender.Send(logging.NewContext(ctx, n.Logger), d.msg); err != nil {
+		n.Logger.Error("Failed to send message", "to", d.msg.To, "error", err)
+	}
+}
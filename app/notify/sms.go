@@ -0,0 +1,67 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// SMSGatewaySender sends text messages through an HTTP SMS gateway, posting
+// each as JSON:
+//
+//	POST {URL}
+//	{"to": "+6591234567", "body": "..."}
+//
+// Requests carry the API key, if any, as a bearer token. Any 2xx response is
+// taken as accepted.
+type SMSGatewaySender struct {
+	URL    string
+	APIKey string
+	Client *http.Client
+}
+
+// NewSMSGatewaySender creates a sender posting to the gateway at url
+func NewSMSGatewaySender(url, apiKey string, timeout time.Duration) *SMSGatewaySender {
+	return &SMSGatewaySender{
+		URL:    url,
+		APIKey: apiKey,
+		Client: &http.Client{Timeout: timeout},
+	}
+}
+
+// smsRequest is the body posted to the gateway
+type smsRequest struct {
+	To   string `json:"to"`
+	Body string `json:"body"`
+}
+
+// Send posts the message's body to its recipient's phone number
+func (s *SMSGatewaySender) Send(ctx context.Context, msg Message) error {
+	payload, err := json.Marshal(smsRequest{To: msg.To, Body: msg.Body})
+	if err != nil {
+		return fmt.Errorf("error marshaling text message: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("error creating SMS gateway request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.APIKey)
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending text message: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("SMS gateway returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// SMTPConfig holds the mail server settings
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string // Optional; PLAIN authentication is used when set
+	Password string
+	From     string
+}
+
+// SMTPSender sends messages through an SMTP server
+type SMTPSender struct {
+	Config SMTPConfig
+}
+
+// NewSMTPSender creates a sender for the given server
+func NewSMTPSender(config SMTPConfig) *SMTPSender {
+	return &SMTPSender{Config: config}
+}
+
+// Send delivers a message in a single SMTP transaction
+func (s *SMTPSender) Send(ctx context.Context, msg Message) error {
+	addr := net.JoinHostPort(s.Config.Host, strconv.Itoa(s.Config.Port))
+
+	var auth smtp.Auth
+	if s.Config.Username != "" {
+		auth = smtp.PlainAuth("", s.Config.Username, s.Config.Password, s.Config.Host)
+	}
+
+	if err := smtp.SendMail(addr, auth, s.Config.From, []string{msg.To}, s.format(msg)); err != nil {
+		return fmt.Errorf("error sending email: %v", err)
+	}
+	return nil
+}
+
+// format builds the message headers and body
+func (s *SMTPSender) format(msg Message) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", s.Config.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(msg.Body)
+	return buf.Bytes()
+}
@@ -0,0 +1,392 @@
+// Package openapi checks requests and responses against the Swagger 2.0
+// document generated from the handlers' annotations, so the documented
+// contract cannot silently drift from what the handlers do. It understands
+// the subset of the format swag emits: types, formats, enums, properties,
+// items, additionalProperties, allOf and references to definitions.
+//
+// Values are checked loosely where Go and Swagger 2.0 disagree: null is
+// accepted for any value, as pointers, nil slices and omitted fields are not
+// described, and objects documented without properties accept anything.
+// Object fields that are not documented are reported.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Spec is a parsed Swagger 2.0 document
+type Spec struct {
+	Paths       map[string]map[string]*Operation `json:"paths"` // Operations by path template and lowercase method
+	Definitions map[string]*Schema               `json:"definitions"`
+}
+
+// Operation is one method of a path
+type Operation struct {
+	Parameters []Parameter          `json:"parameters"`
+	Responses  map[string]*Response `json:"responses"` // By status code or "default"
+}
+
+// Parameter is an input of an operation
+type Parameter struct {
+	Name     string        `json:"name"`
+	In       string        `json:"in"` // path, query, header, body or formData
+	Type     string        `json:"type"`
+	Required bool          `json:"required"`
+	Enum     []interface{} `json:"enum"`
+	Items    *Schema       `json:"items"`  // Of array parameters
+	Schema   *Schema       `json:"schema"` // Of the body parameter
+}
+
+// Response is a documented response of an operation
+type Response struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema describes a JSON value
+type Schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Format               string             `json:"format"`
+	Enum                 []interface{}      `json:"enum"`
+	Items                *Schema            `json:"items"`
+	Properties           map[string]*Schema `json:"properties"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"` // true or a schema
+	AllOf                []*Schema          `json:"allOf"`
+	Required             []string           `json:"required"`
+}
+
+// errorDefinition is the schema of error responses
+const errorDefinition = "apierrors.APIError"
+
+// Load parses a Swagger 2.0 document
+func Load(doc []byte) (*Spec, error) {
+	var spec Spec
+	if err := json.Unmarshal(doc, &spec); err != nil {
+		return nil, fmt.Errorf("error parsing OpenAPI document: %v", err)
+	}
+	return &spec, nil
+}
+
+// Operation returns the operation documented for a path template, such as
+// "/api/v1/applicants/{id}", and method, or nil if there is none
+func (s *Spec) Operation(pathTemplate, method string) *Operation {
+	return s.Paths[pathTemplate][strings.ToLower(method)]
+}
+
+// ValidateRequest returns the ways a request to an operation departs from its
+// documented parameters. pathParams are the values of the path's variables,
+// and body the request body if it is JSON, or nil.
+func (s *Spec) ValidateRequest(op *Operation, r *http.Request, pathParams map[string]string, body []byte) []string {
+	var problems []string
+	query := r.URL.Query()
+	for _, p := range op.Parameters {
+		var values []string
+		switch p.In {
+		case "path":
+			if v, ok := pathParams[p.Name]; ok {
+				values = []string{v}
+			}
+		case "query":
+			values = query[p.Name]
+		case "header":
+			values = r.Header.Values(p.Name)
+		case "body":
+			problems = append(problems, s.validateBody(p, body)...)
+			continue
+		default:
+			// Form fields are read by the handlers from multipart bodies
+			continue
+		}
+
+		if len(values) == 0 || (len(values) == 1 && values[0] == "") {
+			if p.Required {
+				problems = append(problems, fmt.Sprintf("%s parameter %s is required", p.In, p.Name))
+			}
+			continue
+		}
+		for _, v := range values {
+			if problem := checkParameter(p, v); problem != "" {
+				problems = append(problems, fmt.Sprintf("%s parameter %s %s", p.In, p.Name, problem))
+			}
+		}
+	}
+	return problems
+}
+
+func (s *Spec) validateBody(p Parameter, body []byte) []string {
+	if len(body) == 0 {
+		if p.Required {
+			return []string{"request body is required"}
+		}
+		return nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		// Malformed JSON is the handler's to reject
+		return nil
+	}
+	var problems []string
+	s.validate(p.Schema, value, "body", &problems)
+	return problems
+}
+
+// checkParameter returns how a non-body parameter value departs from its
+// type and enum, or ""
+func checkParameter(p Parameter, value string) string {
+	typ, enum := p.Type, p.Enum
+	if typ == "array" && p.Items != nil {
+		// Arrays are given as comma-separated values or repeated parameters;
+		// each is checked against the items
+		for _, item := range strings.Split(value, ",") {
+			if problem := checkParameter(Parameter{Type: p.Items.Type, Enum: p.Items.Enum}, item); problem != "" {
+				return problem
+			}
+		}
+		return ""
+	}
+
+	var parsed interface{} = value
+	switch typ {
+	case "integer":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return "must be an integer"
+		}
+		parsed = float64(n)
+	case "number":
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "must be a number"
+		}
+		parsed = n
+	case "boolean":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return "must be a boolean"
+		}
+		return ""
+	}
+	if len(enum) > 0 && !inEnum(enum, parsed) {
+		return "must be one of " + formatEnum(enum)
+	}
+	return ""
+}
+
+// ValidateResponse returns the ways a JSON response of an operation departs
+// from the documented responses. Error statuses that are not documented for
+// the operation, such as those of authentication or rate limiting, are
+// checked against the error schema every endpoint uses.
+func (s *Spec) ValidateResponse(op *Operation, status int, body []byte) []string {
+	response := op.Responses[strconv.Itoa(status)]
+	if response == nil {
+		response = op.Responses["default"]
+	}
+	if response == nil {
+		if status < 400 {
+			return []string{fmt.Sprintf("status %d is not documented", status)}
+		}
+		response = &Response{Schema: &Schema{Ref: "#/definitions/" + errorDefinition}}
+	}
+	if response.Schema == nil || len(body) == 0 {
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return []string{fmt.Sprintf("response body is not valid JSON: %v", err)}
+	}
+	var problems []string
+	s.validate(response.Schema, value, "response", &problems)
+	return problems
+}
+
+// resolve follows a schema's reference to a definition
+func (s *Spec) resolve(schema *Schema) (*Schema, error) {
+	for depth := 0; schema != nil && schema.Ref != ""; depth++ {
+		name, ok := strings.CutPrefix(schema.Ref, "#/definitions/")
+		if !ok || depth > 32 {
+			return nil, fmt.Errorf("unsupported reference %s", schema.Ref)
+		}
+		if schema = s.Definitions[name]; schema == nil {
+			return nil, fmt.Errorf("undefined reference %s", name)
+		}
+	}
+	return schema, nil
+}
+
+// flatten merges a schema's allOf members into it, so their properties are
+// checked together
+func (s *Spec) flatten(schema *Schema) (*Schema, error) {
+	schema, err := s.resolve(schema)
+	if err != nil || len(schema.AllOf) == 0 {
+		return schema, err
+	}
+
+	merged := *schema
+	merged.AllOf = nil
+	merged.Properties = make(map[string]*Schema)
+	for name, prop := range schema.Properties {
+		merged.Properties[name] = prop
+	}
+	for _, member := range schema.AllOf {
+		m, err := s.flatten(member)
+		if err != nil {
+			return nil, err
+		}
+		if merged.Type == "" {
+			merged.Type = m.Type
+		}
+		if merged.Format == "" {
+			merged.Format = m.Format
+		}
+		if merged.Enum == nil {
+			merged.Enum = m.Enum
+		}
+		if merged.Items == nil {
+			merged.Items = m.Items
+		}
+		if merged.AdditionalProperties == nil {
+			merged.AdditionalProperties = m.AdditionalProperties
+		}
+		for name, prop := range m.Properties {
+			merged.Properties[name] = prop
+		}
+		merged.Required = append(merged.Required, m.Required...)
+	}
+	if len(merged.Properties) == 0 {
+		merged.Properties = nil
+	}
+	return &merged, nil
+}
+
+// validate appends to problems the ways value departs from schema. path
+// locates the value, as in body.household[0].name.
+func (s *Spec) validate(schema *Schema, value interface{}, path string, problems *[]string) {
+	if schema == nil || value == nil {
+		return
+	}
+	schema, err := s.flatten(schema)
+	if err != nil {
+		*problems = append(*problems, fmt.Sprintf("%s: %v", path, err))
+		return
+	}
+	fail := func(format string, args ...interface{}) {
+		*problems = append(*problems, path+" "+fmt.Sprintf(format, args...))
+	}
+
+	switch schema.Type {
+	case "":
+		// Undescribed (interface{}) values can be anything
+		if schema.Properties == nil {
+			return
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			fail("must be a string")
+			return
+		}
+		if schema.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, str); err != nil {
+				fail("must be an RFC3339 time")
+			}
+		}
+	case "integer":
+		n, ok := value.(float64)
+		if !ok || n != float64(int64(n)) {
+			fail("must be an integer")
+			return
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			fail("must be a number")
+			return
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			fail("must be a boolean")
+		}
+		return
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			fail("must be an array")
+			return
+		}
+		for i, item := range items {
+			s.validate(schema.Items, item, fmt.Sprintf("%s[%d]", path, i), problems)
+		}
+		return
+	case "file":
+		return
+	}
+
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, value) {
+		fail("must be one of %s", formatEnum(schema.Enum))
+	}
+	if schema.Type == "object" || schema.Properties != nil {
+		s.validateObject(schema, value, path, problems)
+	}
+}
+
+func (s *Spec) validateObject(schema *Schema, value interface{}, path string, problems *[]string) {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		*problems = append(*problems, path+" must be an object")
+		return
+	}
+	for _, name := range schema.Required {
+		if _, ok := object[name]; !ok {
+			*problems = append(*problems, fmt.Sprintf("%s.%s is required", path, name))
+		}
+	}
+
+	var additional *Schema
+	allowAdditional := schema.Properties == nil
+	if len(schema.AdditionalProperties) > 0 {
+		allowAdditional = string(schema.AdditionalProperties) != "false"
+		if err := json.Unmarshal(schema.AdditionalProperties, &additional); err != nil {
+			additional = nil
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		prop := schema.Properties[name]
+		switch {
+		case prop != nil:
+			s.validate(prop, object[name], path+"."+name, problems)
+		case !allowAdditional:
+			*problems = append(*problems, fmt.Sprintf("%s.%s is not documented", path, name))
+		case additional != nil:
+			s.validate(additional, object[name], path+"."+name, problems)
+		}
+	}
+}
+
+// inEnum reports whether a decoded JSON value is one of the enum values
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, e := range enum {
+		if e == value {
+			return true
+		}
+	}
+	return false
+}
+
+func formatEnum(enum []interface{}) string {
+	values := make([]string, len(enum))
+	for i, e := range enum {
+		values[i] = fmt.Sprint(e)
+	}
+	return strings.Join(values, ", ")
+}
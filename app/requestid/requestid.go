@@ -0,0 +1,46 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Header is the HTTP header carrying the request ID, both on incoming requests
+// and on responses
+const Header = "X-Request-ID"
+
+// maxLength bounds client-supplied IDs so they cannot bloat logs
+const maxLength = 128
+
+type contextKey struct{}
+
+// New generates a new request ID
+func New() string {
+	return uuid.New().String()
+}
+
+// Valid reports whether a client-supplied request ID can be used as is. IDs
+// must be non-empty, at most 128 characters and printable ASCII.
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// NewContext returns a copy of ctx carrying the request ID
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID, or an empty string if there is none
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
@@ -0,0 +1,106 @@
+// Package retention defines how long records are kept before they are
+// purged or anonymized, for PDPA compliance.
+//
+// Each rule matches records that have been inactive for longer than its
+// period. Periods are given in whole years, months and days, such as "2y",
+// "18m" or "1y6m", and are counted back in calendar terms from the time of
+// the run.
+package retention
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Off disables a rule
+const Off = "off"
+
+// Rules, named by the records they match
+const (
+	RuleRejectedApplications = "rejected_applications" // Applications rejected longer ago than the period, which are purged
+	RuleInactiveApplicants   = "inactive_applicants"   // Applicants with no activity within the period, who are anonymized
+)
+
+// Actions rules take on the records they match
+const (
+	ActionPurge     = "purge"
+	ActionAnonymize = "anonymize"
+)
+
+// Rule is a retention rule in force
+type Rule struct {
+	Name   string
+	Action string
+	Period Period
+}
+
+// NewRule creates the rule with the given name and period
+func NewRule(name string, period Period) (Rule, error) {
+	switch name {
+	case RuleRejectedApplications:
+		return Rule{Name: name, Action: ActionPurge, Period: period}, nil
+	case RuleInactiveApplicants:
+		return Rule{Name: name, Action: ActionAnonymize, Period: period}, nil
+	}
+	return Rule{}, fmt.Errorf("unknown retention rule %q", name)
+}
+
+// Period is a length of calendar time
+type Period struct {
+	Years, Months, Days int
+}
+
+// ParsePeriod parses a period such as "2y", "18m", "90d" or "1y6m"
+func ParsePeriod(s string) (Period, error) {
+	var p Period
+	rest := strings.TrimSpace(s)
+	if rest == "" {
+		return p, fmt.Errorf("empty period")
+	}
+	for rest != "" {
+		i := strings.IndexFunc(rest, func(c rune) bool { return c < '0' || c > '9' })
+		if i <= 0 {
+			return p, fmt.Errorf("invalid period %q: want a number of years (y), months (m) or days (d), such as 2y or 18m", s)
+		}
+		n, err := strconv.Atoi(rest[:i])
+		if err != nil {
+			return p, fmt.Errorf("invalid period %q: %v", s, err)
+		}
+		switch rest[i] {
+		case 'y':
+			p.Years += n
+		case 'm':
+			p.Months += n
+		case 'd':
+			p.Days += n
+		default:
+			return p, fmt.Errorf("invalid period %q: unknown unit %q", s, rest[i])
+		}
+		rest = rest[i+1:]
+	}
+	if p == (Period{}) {
+		return p, fmt.Errorf("invalid period %q: must not be zero", s)
+	}
+	return p, nil
+}
+
+// Before returns the time the period before t
+func (p Period) Before(t time.Time) time.Time {
+	return t.AddDate(-p.Years, -p.Months, -p.Days)
+}
+
+// String formats the period as ParsePeriod accepts it
+func (p Period) String() string {
+	var b strings.Builder
+	for _, part := range []struct {
+		n    int
+		unit string
+	}{{p.Years, "y"}, {p.Months, "m"}, {p.Days, "d"}} {
+		if part.n != 0 {
+			b.WriteString(strconv.Itoa(part.n) + part.unit)
+		}
+	}
+	return b.String()
+}
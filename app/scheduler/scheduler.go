@@ -0,0 +1,104 @@
+// Package scheduler queues background jobs on cron schedules.
+//
+// Every server runs the scheduler, so that jobs keep being scheduled while
+// any replica is up. Each scheduled run is queued as a job whose ID is derived
+// from the job type and the scheduled time, so when several replicas queue
+// the same run only the first succeeds and the job runs once.
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"one-client-view-2025tht/app/logging"
+	"one-client-view-2025tht/app/models"
+)
+
+// jobNamespace derives the IDs of scheduled jobs
+var jobNamespace = uuid.MustParse("6f0d3e56-5c1b-4f7e-9a57-3b1f5e0c2d84")
+
+// PayloadFunc returns the payload of the job for a run scheduled at the given
+// time
+type PayloadFunc func(at time.Time) interface{}
+
+// entry is a job type queued on a schedule
+type entry struct {
+	jobType  string
+	schedule *Schedule
+	payload  PayloadFunc
+}
+
+// Scheduler queues jobs at the times their schedules match
+type Scheduler struct {
+	Jobs     *models.JobRepository
+	Location *time.Location // Time zone schedules are evaluated in
+	Logger   *slog.Logger
+
+	entries []entry
+}
+
+// New creates a scheduler queueing jobs with repo, evaluating schedules in
+// the server's local time zone
+func New(repo *models.JobRepository) *Scheduler {
+	return &Scheduler{Jobs: repo, Location: time.Local, Logger: logging.Component("scheduler")}
+}
+
+// Add queues a job of the given type whenever schedule matches
+func (s *Scheduler) Add(jobType string, schedule *Schedule, payload PayloadFunc) {
+	s.entries = append(s.entries, entry{jobType: jobType, schedule: schedule, payload: payload})
+}
+
+// Run queues each entry's jobs at their scheduled times until ctx is
+// cancelled. Runs missed while no server was up are not made up.
+func (s *Scheduler) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, e := range s.entries {
+		wg.Add(1)
+		go func(e entry) {
+			defer wg.Done()
+			s.run(ctx, e)
+		}(e)
+	}
+	wg.Wait()
+}
+
+// run queues one entry's jobs until ctx is cancelled
+func (s *Scheduler) run(ctx context.Context, e entry) {
+	for {
+		next := e.schedule.Next(time.Now().In(s.Location))
+		if next.IsZero() {
+			s.Logger.Warn("Schedule never matches; not scheduling its jobs", "job_type", e.jobType)
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if err := s.queue(e, next); err != nil {
+			s.Logger.Error("Failed to queue scheduled job", "job_type", e.jobType, "error", err)
+		}
+	}
+}
+
+// queue queues the run of an entry scheduled at the given time, unless
+// another server already has
+func (s *Scheduler) queue(e entry, at time.Time) error {
+	id := uuid.NewSHA1(jobNamespace, []byte(e.jobType+"@"+at.UTC().Format(time.RFC3339))).String()
+	queued, err := s.Jobs.EnqueueOnce(id, e.jobType, e.payload(at))
+	if err != nil {
+		return err
+	}
+	if queued {
+		s.Logger.Info("Queued scheduled job", "job_type", e.jobType, "job_id", id)
+	}
+	return nil
+}
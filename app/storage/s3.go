@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3 is a Store keeping objects in a bucket of an S3-compatible service,
+// shared by every replica
+type S3 struct {
+	Client *minio.Client
+	Bucket string
+}
+
+// NewS3 connects to the S3 service in cfg and checks the bucket exists
+func NewS3(ctx context.Context, cfg Config) (*S3, error) {
+	if cfg.S3Endpoint == "" || cfg.S3Bucket == "" {
+		return nil, errors.New("S3 endpoint and bucket are required")
+	}
+
+	client, err := minio.New(cfg.S3Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.S3AccessKey, cfg.S3SecretKey, ""),
+		Secure: cfg.S3UseSSL,
+		Region: cfg.S3Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid S3 settings: %v", err)
+	}
+
+	exists, err := client.BucketExists(ctx, cfg.S3Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to S3: %v", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("S3 bucket %q does not exist", cfg.S3Bucket)
+	}
+	return &S3{Client: client, Bucket: cfg.S3Bucket}, nil
+}
+
+// Put uploads the object
+func (s *S3) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	_, err := s.Client.PutObject(ctx, s.Bucket, key, r, size, minio.PutObjectOptions{ContentType: contentType})
+	if err != nil {
+		return fmt.Errorf("error uploading to S3: %v", err)
+	}
+	return nil
+}
+
+// Get opens the object for download
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	obj, err := s.Client.GetObject(ctx, s.Bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error downloading from S3: %v", err)
+	}
+	// GetObject does not fail until the object is read; Stat makes the
+	// request, so a missing object is reported here
+	if _, err := obj.Stat(); err != nil {
+		obj.Close()
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("error downloading from S3: %v", err)
+	}
+	return obj, nil
+}
+
+// Delete removes the object. S3 does not report deleting a missing object.
+func (s *S3) Delete(ctx context.Context, key string) error {
+	if err := s.Client.RemoveObject(ctx, s.Bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("error deleting from S3: %v", err)
+	}
+	return nil
+}
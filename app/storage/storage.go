@@ -0,0 +1,60 @@
+// Package storage keeps uploaded files outside the database.
+//
+// A Store holds objects under string keys. Local keeps them in a directory,
+// which suits a single server or a shared volume; S3 keeps them in a bucket
+// of any S3-compatible service, such as AWS S3 or MinIO, so every replica
+// sees them.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Backends selectable in configuration
+const (
+	BackendLocal = "local" // Local: files in a directory
+	BackendS3    = "s3"    // S3: objects in an S3-compatible bucket
+)
+
+// ErrNotFound is returned when no object is stored under a key
+var ErrNotFound = errors.New("object not found")
+
+// Config holds the settings of every backend; only those of the selected
+// backend are used
+type Config struct {
+	Backend     string
+	LocalDir    string // Directory of the local backend
+	S3Endpoint  string // host[:port] of the S3 service
+	S3Bucket    string
+	S3Region    string
+	S3AccessKey string
+	S3SecretKey string
+	S3UseSSL    bool
+}
+
+// Open creates the store for the configured backend. An empty backend is
+// BackendLocal.
+func Open(ctx context.Context, cfg Config) (Store, error) {
+	switch cfg.Backend {
+	case "", BackendLocal:
+		return NewLocal(cfg.LocalDir)
+	case BackendS3:
+		return NewS3(ctx, cfg)
+	default:
+		return nil, fmt.Errorf("unsupported storage backend: %q", cfg.Backend)
+	}
+}
+
+// Store holds objects under keys
+type Store interface {
+	// Put stores size bytes read from r under key with the given content
+	// type, replacing any object already there
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens the object stored under key, or returns ErrNotFound
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object stored under key, if there is one
+	Delete(ctx context.Context, key string) error
+}
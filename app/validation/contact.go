@@ -0,0 +1,66 @@
+package validation
+
+import (
+	"strconv"
+
+	"one-client-view-2025tht/app/models"
+)
+
+// ValidPhone reports whether s is a Singapore phone number of eight digits
+// starting with 3, 6, 8 or 9, or an international number of a "+" and 8 to 15
+// digits. Spaces and hyphens are ignored.
+func ValidPhone(s string) bool {
+	s = models.NormalizePhone(s)
+	digits := s
+	international := len(s) > 0 && s[0] == '+'
+	if international {
+		digits = s[1:]
+	}
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	if international {
+		return len(digits) >= 8 && len(digits) <= 15
+	}
+	return len(digits) == 8 && (digits[0] == '3' || digits[0] == '6' || digits[0] == '8' || digits[0] == '9')
+}
+
+// Phone checks that a non-empty string field is a valid phone number
+func (v *Validator) Phone(field, value string) {
+	if value == "" {
+		return
+	}
+	v.Check(ValidPhone(value), field, "must be a valid phone number")
+}
+
+// ValidPostalDistrict reports whether s is empty or a two-digit postal
+// district, "01" to "28"
+func ValidPostalDistrict(s string) bool {
+	if s == "" {
+		return true
+	}
+	if len(s) != 2 || s[0] < '0' || s[0] > '9' || s[1] < '0' || s[1] > '9' {
+		return false
+	}
+	n, _ := strconv.Atoi(s)
+	return n >= 1 && n <= 28
+}
+
+// Address checks the fields of an address, if one is given
+func (v *Validator) Address(field string, a *models.Address) {
+	if a == nil {
+		return
+	}
+	nested := v.Nested(field)
+	nested.Required("street", a.Street)
+	nested.Required("postal_code", a.PostalCode)
+	if a.PostalCode != "" {
+		nested.Check(models.PostalDistrict(a.PostalCode) != "", "postal_code", "must be a valid six-digit postal code")
+	}
+	nested.Check(len(a.Block) <= 10, "block", "must be at most 10 characters")
+	nested.Check(len(a.Unit) <= 20, "unit", "must be at most 20 characters")
+	nested.Check(len(a.Street) <= 255, "street", "must be at most 255 characters")
+	nested.Check(len(a.Building) <= 255, "building", "must be at most 255 characters")
+}
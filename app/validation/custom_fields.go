@@ -0,0 +1,128 @@
+package validation
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"one-client-view-2025tht/app/models"
+)
+
+// Limits on custom field definitions, matching the columns
+const (
+	maxCustomFieldLabelLength  = 255
+	maxCustomFieldOptionLength = 100
+)
+
+// CustomField validates the definition of a custom field. Bounds and options
+// are only allowed for the types they apply to.
+func CustomField(f *models.CustomField) error {
+	v := New()
+
+	v.Required("key", f.Key)
+	if f.Key != "" {
+		v.Check(models.CustomFieldKeyPattern.MatchString(f.Key), "key",
+			"must be a lowercase letter followed by up to 49 lowercase letters, digits or underscores")
+	}
+	v.Required("label", f.Label)
+	v.Check(len(f.Label) <= maxCustomFieldLabelLength, "label", "must be at most "+strconv.Itoa(maxCustomFieldLabelLength)+" characters")
+	v.RequiredOneOf("type", f.Type, models.CustomFieldTypes)
+
+	if f.Type == models.CustomFieldEnum {
+		v.Check(len(f.Options) > 0, "options", "must not be empty")
+		for i, option := range f.Options {
+			field := "options[" + strconv.Itoa(i) + "]"
+			v.Required(field, option)
+			v.Check(len(option) <= maxCustomFieldOptionLength, field, "must be at most "+strconv.Itoa(maxCustomFieldOptionLength)+" characters")
+			v.Check(!slices.Contains(f.Options[:i], option), field, "must not contain duplicates")
+		}
+	} else {
+		v.Check(len(f.Options) == 0, "options", "is only allowed for enum fields")
+	}
+
+	if f.Type == models.CustomFieldNumber {
+		if f.Min != nil && f.Max != nil {
+			v.Check(*f.Max >= *f.Min, "max", "must not be less than min")
+		}
+	} else {
+		v.Check(f.Min == nil, "min", "is only allowed for number fields")
+		v.Check(f.Max == nil, "max", "is only allowed for number fields")
+	}
+
+	if f.Type == models.CustomFieldString {
+		if f.MaxLength != nil {
+			v.Check(*f.MaxLength >= 1, "max_length", "must be at least 1")
+		}
+	} else {
+		v.Check(f.MaxLength == nil, "max_length", "is only allowed for string fields")
+	}
+
+	return v.Err()
+}
+
+// customValues checks an applicant's custom field values against the defined
+// fields: every value must be of a defined field and suit its type and
+// bounds, and required fields must have a value
+func customValues(v *Validator, values models.CustomValues, fields []models.CustomField) {
+	for key := range values {
+		if !slices.ContainsFunc(fields, func(f models.CustomField) bool { return f.Key == key }) {
+			v.Add(key, "is not a defined custom field")
+		}
+	}
+
+	for _, f := range fields {
+		value := values[f.Key]
+		if value == nil {
+			v.Check(!f.Required, f.Key, "is required")
+			continue
+		}
+
+		switch f.Type {
+		case models.CustomFieldString:
+			s, ok := value.(string)
+			if !ok {
+				v.Add(f.Key, "must be a string")
+				continue
+			}
+			v.Check(!f.Required || strings.TrimSpace(s) != "", f.Key, "is required")
+			if f.MaxLength != nil {
+				v.Check(utf8.RuneCountInString(s) <= *f.MaxLength, f.Key, "must be at most "+strconv.Itoa(*f.MaxLength)+" characters")
+			}
+		case models.CustomFieldNumber:
+			n, ok := value.(float64)
+			if !ok {
+				v.Add(f.Key, "must be a number")
+				continue
+			}
+			if f.Min != nil {
+				v.Check(n >= *f.Min, f.Key, "must be at least "+formatNumber(*f.Min))
+			}
+			if f.Max != nil {
+				v.Check(n <= *f.Max, f.Key, "must be at most "+formatNumber(*f.Max))
+			}
+		case models.CustomFieldBoolean:
+			_, ok := value.(bool)
+			v.Check(ok, f.Key, "must be true or false")
+		case models.CustomFieldEnum:
+			s, ok := value.(string)
+			v.Check(ok && slices.Contains(f.Options, s), f.Key, "must be one of: "+strings.Join(f.Options, ", "))
+		}
+	}
+}
+
+// customFieldRules checks that the rules of scheme criteria only compare
+// defined custom fields
+func customFieldRules(v *Validator, c *models.Criteria, fields []models.CustomField) {
+	for _, key := range c.Rule().CustomFields() {
+		if !slices.ContainsFunc(fields, func(f models.CustomField) bool { return f.Key == key }) {
+			v.Add("criteria", "uses the undefined custom field "+key)
+		}
+	}
+}
+
+// formatNumber formats a bound for a message, without a fraction if it is
+// whole
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
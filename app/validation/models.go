@@ -0,0 +1,404 @@
+package validation
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"one-client-view-2025tht/app/models"
+	"one-client-view-2025tht/app/money"
+)
+
+// Allowed values of enumerated fields, matching the database schema
+var (
+	EmploymentStatuses  = []string{"employed", "unemployed"}
+	Sexes               = []string{"male", "female", "other"}
+	MaritalStatuses     = []string{"single", "married", "widowed", "divorced"}
+	ApplicationStatuses = []string{"pending", "approved", "rejected", "withdrawn"}
+	SchoolLevels        = []string{"preschool", "primary", "secondary", "tertiary", "none"}
+)
+
+// Applicant validates an applicant, their household members and their values
+// of the given custom fields
+func Applicant(a *models.Applicant, fields []models.CustomField) error {
+	v := New()
+	now := time.Now()
+
+	v.Required("name", a.Name)
+	v.IdentityNumber("identity_number", a.IdentityNumber)
+	v.RequiredOneOf("employment_status", a.EmploymentStatus, EmploymentStatuses)
+	v.RequiredOneOf("sex", a.Sex, Sexes)
+	v.RequiredOneOf("marital_status", a.MaritalStatus, MaritalStatuses)
+	v.Date("date_of_birth", a.DateOfBirth.Time, now)
+	v.NonNegative("monthly_income", a.MonthlyIncome)
+	v.Email("email", a.Email)
+	v.Phone("phone", a.Phone)
+	v.Address("address", a.Address)
+
+	for i := range a.Household {
+		householdMember(v.Nested("household["+strconv.Itoa(i)+"]"), &a.Household[i], now)
+	}
+	customValues(v.Nested("custom_fields"), a.CustomFields, fields)
+
+	return v.Err()
+}
+
+// HouseholdMember validates a single household member
+func HouseholdMember(m *models.HouseholdMember) error {
+	v := New()
+	householdMember(v, m, time.Now())
+	return v.Err()
+}
+
+func householdMember(v *Validator, m *models.HouseholdMember, now time.Time) {
+	v.Required("name", m.Name)
+	v.IdentityNumber("identity_number", m.IdentityNumber)
+	v.RequiredOneOf("employment_status", m.EmploymentStatus, EmploymentStatuses)
+	v.RequiredOneOf("sex", m.Sex, Sexes)
+	v.Date("date_of_birth", m.DateOfBirth.Time, now)
+	v.RequiredOneOf("relation", m.Relation, models.Relations)
+	v.NonNegative("monthly_income", m.MonthlyIncome)
+	v.OneOf("school_level", m.SchoolLevel, SchoolLevels)
+}
+
+// Scheme validates a scheme, its criteria, its benefits and its review
+// checklist. The criteria may only compare the given custom fields.
+func Scheme(s *models.Scheme, fields []models.CustomField) error {
+	v := New()
+
+	v.Required("name", s.Name)
+	v.Required("description", s.Description)
+	if s.OpenDate != nil && s.CloseDate != nil {
+		v.Check(!s.CloseDate.Before(*s.OpenDate), "close_date", "must not be before open_date")
+	}
+	if s.MaxApplications != nil {
+		v.Check(*s.MaxApplications >= 0, "max_applications", "must not be negative")
+	}
+	if s.Budget != nil {
+		v.Amount("budget", *s.Budget)
+	}
+	if s.CoolingOffDays != nil {
+		v.Check(*s.CoolingOffDays >= 0, "cooling_off_days", "must not be negative")
+	}
+
+	criteria(v.Nested("criteria"), &s.Criteria)
+	if err := s.Criteria.Validate(); err != nil {
+		v.Add("criteria", err.Error())
+	}
+	customFieldRules(v, &s.Criteria, fields)
+
+	for i := range s.Benefits {
+		benefit(v.Nested("benefits["+strconv.Itoa(i)+"]"), &s.Benefits[i])
+	}
+	for i := range s.Checklist {
+		checklistItem(v.Nested("checklist["+strconv.Itoa(i)+"]"), &s.Checklist[i])
+	}
+
+	return v.Err()
+}
+
+// criteria checks the enumerated fields of scheme criteria and of each of
+// their alternative groups
+func criteria(v *Validator, c *models.Criteria) {
+	v.OneOf("employment_status", c.EmploymentStatus, EmploymentStatuses)
+	v.OneOf("marital_status", c.MaritalStatus, MaritalStatuses)
+	v.Nested("has_children").OneOf("school_level", c.HasChildren.SchoolLevel, SchoolLevels)
+	for i := range c.AnyOf {
+		criteria(v.Nested("any_of["+strconv.Itoa(i)+"]"), &c.AnyOf[i])
+	}
+}
+
+// Benefit validates a single benefit
+func Benefit(b *models.Benefit) error {
+	v := New()
+	benefit(v, b)
+	return v.Err()
+}
+
+// maxBenefitMonths is the longest a recurring benefit can be paid over
+const maxBenefitMonths = 120
+
+func benefit(v *Validator, b *models.Benefit) {
+	v.Required("name", b.Name)
+	v.Amount("amount", b.Amount)
+	v.OneOf("currency", b.Currency, money.Currencies)
+	v.OneOf("frequency", b.Frequency, models.BenefitFrequencies)
+	if b.Frequency == models.BenefitMonthly || b.Frequency == models.BenefitQuarterly {
+		v.Check(b.DurationMonths != nil, "duration_months", "is required for recurring benefits")
+	} else {
+		v.Check(b.DurationMonths == nil, "duration_months", "must not be set for one-off benefits")
+	}
+	if b.DurationMonths != nil {
+		v.Check(*b.DurationMonths >= 1 && *b.DurationMonths <= maxBenefitMonths, "duration_months",
+			"must be between 1 and "+strconv.Itoa(maxBenefitMonths))
+	}
+	if b.Formula != nil {
+		formula(v.Nested("formula"), b.Formula, b.Amount)
+	}
+}
+
+// ChecklistItem validates a single item of a scheme's review checklist
+func ChecklistItem(item *models.ChecklistItem) error {
+	v := New()
+	checklistItem(v, item)
+	return v.Err()
+}
+
+// maxChecklistNameLength is the longest checklist item name kept, matching
+// the column
+const maxChecklistNameLength = 255
+
+func checklistItem(v *Validator, item *models.ChecklistItem) {
+	v.Required("name", item.Name)
+	v.Check(len(item.Name) <= maxChecklistNameLength, "name",
+		"must be at most "+strconv.Itoa(maxChecklistNameLength)+" characters")
+	v.OneOf("kind", item.Kind, models.ChecklistKinds)
+	v.Check(item.Position >= 0, "position", "must not be negative")
+}
+
+// ChecklistTickRequest validates a request ticking or unticking an item of an
+// application's checklist
+func ChecklistTickRequest(req *models.ChecklistTickRequest) error {
+	v := New()
+	v.Check(req.Completed || req.Note == "", "note", "is only allowed when completing the item")
+	return v.Err()
+}
+
+// formula checks the amounts of a benefit formula and the school levels it
+// counts children at
+func formula(v *Validator, f *models.BenefitFormula, base money.Amount) {
+	v.Amount("per_child_amount", f.PerChildAmount)
+	v.Amount("per_member_amount", f.PerMemberAmount)
+	for i, level := range f.ChildSchoolLevels {
+		v.RequiredOneOf("child_school_levels["+strconv.Itoa(i)+"]", level, SchoolLevels)
+	}
+	if f.MaxAmount != nil {
+		v.Check(*f.MaxAmount >= base, "max_amount", "must not be less than amount")
+	}
+}
+
+// MergeRequest validates a request to merge the source applicant into targetID
+func MergeRequest(req *models.MergeRequest, targetID string) error {
+	v := New()
+	v.Required("source_id", req.SourceID)
+	v.Check(req.SourceID != targetID, "source_id", "must not be the target applicant")
+	v.OneOf("policy", req.Policy, models.MergePolicies)
+	return v.Err()
+}
+
+// ApplicationRequest validates a request to create an application
+func ApplicationRequest(req *models.ApplicationRequest) error {
+	v := New()
+	v.Required("applicant_id", req.ApplicantID)
+	v.Required("scheme_id", req.SchemeID)
+	return v.Err()
+}
+
+// ApplicationUpdate validates a direct update of an application. Its status
+// cannot be changed this way: decisions go through the approve, reject and
+// withdraw endpoints, which record who made them and why.
+func ApplicationUpdate(before, after *models.Application) error {
+	v := New()
+	v.RequiredOneOf("status", after.Status, ApplicationStatuses)
+	v.Check(after.Status == before.Status, "status", "cannot be changed directly; approve, reject or withdraw the application instead")
+	return v.Err()
+}
+
+// DecisionRequest validates a request to approve or reject an application
+func DecisionRequest(req *models.DecisionRequest, approve bool) error {
+	v := New()
+	v.Required("reason", req.Reason)
+	if amount := req.RecommendedBenefitAmount; amount != nil {
+		v.Check(approve, "recommended_benefit_amount", "is only allowed when approving")
+		v.Amount("recommended_benefit_amount", *amount)
+	}
+	return v.Err()
+}
+
+// WithdrawRequest validates a request to withdraw an application
+func WithdrawRequest(req *models.WithdrawRequest) error {
+	v := New()
+	v.Required("reason", req.Reason)
+	return v.Err()
+}
+
+// Webhook validates a webhook registration
+func Webhook(w *models.Webhook) error {
+	v := New()
+
+	v.Required("url", w.URL)
+	if w.URL != "" {
+		u, err := url.Parse(w.URL)
+		v.Check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "",
+			"url", "must be an absolute http or https URL")
+	}
+
+	v.Check(len(w.Events) > 0, "events", "must not be empty")
+	for i, event := range w.Events {
+		v.RequiredOneOf("events["+strconv.Itoa(i)+"]", event, models.WebhookEvents)
+	}
+
+	return v.Err()
+}
+
+// LoginRequest validates login credentials are present
+func LoginRequest(req *models.LoginRequest) error {
+	v := New()
+	v.Required("username", req.Username)
+	v.Required("password", req.Password)
+	return v.Err()
+}
+
+// PortalCodeRequest validates a request for a one-time code
+func PortalCodeRequest(req *models.PortalCodeRequest) error {
+	v := New()
+	v.Required("identity_number", req.IdentityNumber)
+	v.IdentityNumber("identity_number", req.IdentityNumber)
+	v.RequiredOneOf("channel", req.Channel, models.OTPChannels)
+	return v.Err()
+}
+
+// PortalLoginRequest validates an applicant's sign-in with a one-time code
+func PortalLoginRequest(req *models.PortalLoginRequest) error {
+	v := New()
+	v.Required("identity_number", req.IdentityNumber)
+	v.Required("code", req.Code)
+	return v.Err()
+}
+
+// Limits on case notes
+const (
+	maxCaseNoteLength = 10000
+	maxCaseNoteTags   = 10
+	maxTagLength      = 50
+)
+
+// CaseNoteRequest validates a request to add a case note. Tags must already
+// be normalized with models.NormalizeTags.
+func CaseNoteRequest(req *models.CaseNoteRequest) error {
+	v := New()
+	v.Required("body", strings.TrimSpace(req.Body))
+	v.Check(len(req.Body) <= maxCaseNoteLength, "body", "must be at most "+strconv.Itoa(maxCaseNoteLength)+" characters")
+	v.Check(len(req.Tags) <= maxCaseNoteTags, "tags", "must have at most "+strconv.Itoa(maxCaseNoteTags)+" tags")
+	for i, tag := range req.Tags {
+		field := "tags[" + strconv.Itoa(i) + "]"
+		v.Check(ValidTag(tag), field, "must be 1 to 50 lowercase letters, digits or hyphens")
+	}
+	return v.Err()
+}
+
+// ValidTag reports whether s is a tag of 1 to 50 lowercase letters, digits
+// and hyphens
+func ValidTag(s string) bool {
+	if s == "" || len(s) > maxTagLength {
+		return false
+	}
+	for _, c := range s {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// maxConsentReferenceLength is the longest consent reference kept, matching
+// the column
+const maxConsentReferenceLength = 255
+
+// ConsentRequest validates a request to record consent at now. Consent cannot
+// be recorded as given in the future, or as expiring before it was given.
+func ConsentRequest(req *models.ConsentRequest, now time.Time) error {
+	v := New()
+	v.Check(len(req.Reference) <= maxConsentReferenceLength, "reference", "must be at most "+strconv.Itoa(maxConsentReferenceLength)+" characters")
+	if req.GrantedAt != nil {
+		v.Check(!req.GrantedAt.After(now), "granted_at", "must not be in the future")
+	}
+	if req.ExpiresAt != nil {
+		grantedAt := now
+		if req.GrantedAt != nil {
+			grantedAt = *req.GrantedAt
+		}
+		v.Check(req.ExpiresAt.After(grantedAt), "expires_at", "must be after granted_at")
+	}
+	return v.Err()
+}
+
+// maxCommentLength is the longest comment kept
+const maxCommentLength = 10000
+
+// CommentRequest validates a request to add a comment
+func CommentRequest(req *models.CommentRequest) error {
+	v := New()
+	commentBody(v, req.Body)
+	return v.Err()
+}
+
+// CommentEditRequest validates a request to edit a comment
+func CommentEditRequest(req *models.CommentEditRequest) error {
+	v := New()
+	commentBody(v, req.Body)
+	return v.Err()
+}
+
+// commentBody checks the text of a comment
+func commentBody(v *Validator, body string) {
+	v.Required("body", body)
+	v.Check(len(body) <= maxCommentLength, "body", "must be at most "+strconv.Itoa(maxCommentLength)+" characters")
+}
+
+// maxTaskTitleLength is the longest task title kept, matching the column
+const maxTaskTitleLength = 255
+
+// TaskRequest validates a request creating or replacing a task. A task needs
+// an applicant, which is taken from its application if only that is given.
+func TaskRequest(req *models.TaskRequest) error {
+	v := New()
+	if req.ApplicationID == "" {
+		v.Required("applicant_id", req.ApplicantID)
+	}
+	v.Required("title", req.Title)
+	v.Check(len(req.Title) <= maxTaskTitleLength, "title", "must be at most "+strconv.Itoa(maxTaskTitleLength)+" characters")
+	v.Check(len(req.Description) <= maxCaseNoteLength, "description", "must be at most "+strconv.Itoa(maxCaseNoteLength)+" characters")
+	v.OneOf("status", req.Status, models.TaskStatuses)
+	return v.Err()
+}
+
+// ReferralRequest validates a request recording or replacing a referral
+func ReferralRequest(req *models.ReferralRequest) error {
+	v := New()
+	v.Check(len(req.ReferredBy) <= 255, "referred_by", "must be at most 255 characters")
+	v.Required("referred_to", req.ReferredTo)
+	v.Check(len(req.ReferredTo) <= 255, "referred_to", "must be at most 255 characters")
+	v.Check(len(req.Scheme) <= 255, "scheme", "must be at most 255 characters")
+	v.Required("reason", req.Reason)
+	v.Check(len(req.Reason) <= maxCaseNoteLength, "reason", "must be at most "+strconv.Itoa(maxCaseNoteLength)+" characters")
+	v.OneOf("outcome", req.Outcome, models.ReferralOutcomes)
+	v.Check(len(req.OutcomeNotes) <= maxCaseNoteLength, "outcome_notes", "must be at most "+strconv.Itoa(maxCaseNoteLength)+" characters")
+	return v.Err()
+}
+
+// HouseholdMembershipRequest validates a request adding an applicant to a
+// household or replacing their membership, which must name the applicant
+// when adding
+func HouseholdMembershipRequest(req *models.HouseholdMembershipRequest, adding bool) error {
+	v := New()
+	if adding {
+		v.Required("applicant_id", req.ApplicantID)
+	}
+	v.RequiredOneOf("relation", req.Relation, models.Relations)
+	v.Check(!req.StartDate.IsZero(), "start_date", "is required")
+	if req.EndDate != nil {
+		v.Check(req.EndDate.After(req.StartDate), "end_date", "must be after start_date")
+	}
+	return v.Err()
+}
+
+// SchemeTranslationRequest validates a request translating a scheme
+func SchemeTranslationRequest(req *models.SchemeTranslationRequest) error {
+	v := New()
+	v.Required("name", req.Name)
+	v.Check(len(req.Name) <= 255, "name", "must be at most 255 characters")
+	v.Required("description", req.Description)
+	return v.Err()
+}
@@ -0,0 +1,56 @@
+package validation
+
+import (
+	"one-client-view-2025tht/app/models"
+)
+
+// identityNumberWeights are applied to the seven digits of an NRIC or FIN
+var identityNumberWeights = [7]int{2, 7, 6, 5, 4, 3, 2}
+
+// ValidIdentityNumber reports whether s is a well-formed Singapore NRIC or FIN
+// (a prefix letter S, T, F, G or M, seven digits and a check letter) with a
+// correct check letter. Case and surrounding spaces are ignored.
+func ValidIdentityNumber(s string) bool {
+	s = models.NormalizeIdentityNumber(s)
+	if len(s) != 9 {
+		return false
+	}
+
+	sum := 0
+	for i, w := range identityNumberWeights {
+		d := s[i+1]
+		if d < '0' || d > '9' {
+			return false
+		}
+		sum += int(d-'0') * w
+	}
+
+	var checkLetters string
+	switch s[0] {
+	case 'S':
+		checkLetters = "JZIHGFEDCBA"
+	case 'T':
+		sum += 4
+		checkLetters = "JZIHGFEDCBA"
+	case 'F':
+		checkLetters = "XWUTRQPNMLK"
+	case 'G':
+		sum += 4
+		checkLetters = "XWUTRQPNMLK"
+	case 'M':
+		sum += 3
+		checkLetters = "XWUTRQPNJLK"
+	default:
+		return false
+	}
+
+	return s[8] == checkLetters[sum%11]
+}
+
+// IdentityNumber checks that a non-empty string field is a valid NRIC or FIN
+func (v *Validator) IdentityNumber(field, value string) {
+	if value == "" {
+		return
+	}
+	v.Check(ValidIdentityNumber(value), field, "must be a valid NRIC or FIN")
+}
@@ -0,0 +1,125 @@
+// Package validation checks request models before they reach the
+// repositories, collecting every problem as a field → message map rather than
+// stopping at the first.
+package validation
+
+import (
+	"fmt"
+	"net/mail"
+	"sort"
+	"strings"
+	"time"
+
+	"one-client-view-2025tht/app/money"
+)
+
+// Errors maps field names to messages. Nested fields use dotted paths and
+// list indexes, e.g. "household[0].date_of_birth".
+type Errors map[string]string
+
+// Error implements the error interface, listing fields in a stable order
+func (e Errors) Error() string {
+	fields := make([]string, 0, len(e))
+	for field := range e {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		parts[i] = field + ": " + e[field]
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Validator accumulates field errors. The first error for a field wins.
+type Validator struct {
+	errors Errors
+	prefix string
+}
+
+// New creates an empty validator
+func New() *Validator {
+	return &Validator{errors: Errors{}}
+}
+
+// Nested returns a validator that records errors under prefix, e.g. the
+// fields of household[0], into the same error map
+func (v *Validator) Nested(prefix string) *Validator {
+	return &Validator{errors: v.errors, prefix: v.prefix + prefix + "."}
+}
+
+// Add records a message for field unless the field already has one
+func (v *Validator) Add(field, message string) {
+	key := v.prefix + field
+	if _, exists := v.errors[key]; !exists {
+		v.errors[key] = message
+	}
+}
+
+// Check records message for field when ok is false
+func (v *Validator) Check(ok bool, field, message string) {
+	if !ok {
+		v.Add(field, message)
+	}
+}
+
+// Required checks that a string field is not blank
+func (v *Validator) Required(field, value string) {
+	v.Check(strings.TrimSpace(value) != "", field, "is required")
+}
+
+// OneOf checks that a non-empty string field is one of the allowed values
+func (v *Validator) OneOf(field, value string, allowed []string) {
+	if value == "" {
+		return
+	}
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.Add(field, fmt.Sprintf("must be one of: %s", strings.Join(allowed, ", ")))
+}
+
+// RequiredOneOf checks that a string field is set and one of the allowed values
+func (v *Validator) RequiredOneOf(field, value string, allowed []string) {
+	v.Required(field, value)
+	v.OneOf(field, value, allowed)
+}
+
+// Date checks that a date field is set and not after now
+func (v *Validator) Date(field string, value, now time.Time) {
+	if value.IsZero() {
+		v.Add(field, "is required")
+		return
+	}
+	v.Check(!value.After(now), field, "must not be in the future")
+}
+
+// NonNegative checks that a number field is not negative
+func (v *Validator) NonNegative(field string, value float64) {
+	v.Check(value >= 0, field, "must not be negative")
+}
+
+// Amount checks that an amount of money is not negative
+func (v *Validator) Amount(field string, value money.Amount) {
+	v.Check(value >= 0, field, "must not be negative")
+}
+
+// Email checks that a non-empty string field is a plain email address
+func (v *Validator) Email(field, value string) {
+	if value == "" {
+		return
+	}
+	addr, err := mail.ParseAddress(value)
+	v.Check(err == nil && addr.Address == value, field, "must be a valid email address")
+}
+
+// Err returns the accumulated errors, or nil if there are none
+func (v *Validator) Err() error {
+	if len(v.errors) == 0 {
+		return nil
+	}
+	return v.errors
+}
@@ -0,0 +1,149 @@
+// Package webhooks delivers queued webhook events from the outbox in the
+// webhook_deliveries table. Each delivery is sent by a job on the background
+// job queue. Each request is signed so receivers can verify it came from this
+// service, and failed deliveries are retried with exponential backoff.
+//
+// Requests are POSTed as JSON with these headers:
+//
+//	X-Webhook-ID         the event ID, shared by all deliveries of one event
+//	X-Webhook-Event      the event name, e.g. application.approved
+//	X-Webhook-Timestamp  Unix time the request was signed
+//	X-Webhook-Signature  "sha256=" + hex HMAC-SHA256 of "<timestamp>.<body>"
+//	                     keyed with the webhook's secret
+//
+// Receivers should recompute the signature, compare it in constant time and
+// reject old timestamps to prevent replays.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"one-client-view-2025tht/app/jobs"
+	"one-client-view-2025tht/app/models"
+)
+
+// Request headers set on every delivery
+const (
+	HeaderID        = "X-Webhook-ID"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Dispatcher sends deliveries from the outbox, as the handler of
+// models.JobWebhookDelivery jobs
+type Dispatcher struct {
+	Repo        *models.WebhookRepository
+	Client      *http.Client
+	MaxAttempts int           // Attempts before a delivery is marked failed
+	BaseBackoff time.Duration // Delay before the first retry, doubled after each failure
+	MaxBackoff  time.Duration
+}
+
+// NewDispatcher creates a dispatcher with default settings
+func NewDispatcher(repo *models.WebhookRepository) *Dispatcher {
+	return &Dispatcher{
+		Repo:        repo,
+		Client:      &http.Client{Timeout: 10 * time.Second},
+		MaxAttempts: 8,
+		BaseBackoff: 30 * time.Second,
+		MaxBackoff:  time.Hour,
+	}
+}
+
+// RetryPolicy returns the policy to register delivery jobs with
+func (d *Dispatcher) RetryPolicy() jobs.RetryPolicy {
+	return jobs.RetryPolicy{
+		MaxAttempts: d.MaxAttempts,
+		BaseBackoff: d.BaseBackoff,
+		MaxBackoff:  d.MaxBackoff,
+	}
+}
+
+// Deliver is the job handler making one attempt at the delivery in the job's
+// payload, and recording the outcome on the delivery. The job fails, and is
+// retried by the queue, if the attempt fails.
+func (d *Dispatcher) Deliver(ctx context.Context, job *models.Job) (interface{}, error) {
+	var payload models.WebhookDeliveryJob
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return nil, jobs.Permanent(fmt.Errorf("invalid payload: %v", err))
+	}
+
+	delivery, err := d.Repo.GetDeliveryForDispatch(payload.DeliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil || delivery.Status == models.DeliveryDelivered {
+		// Deleted with its webhook, or delivered by an earlier attempt. A
+		// failed delivery is only attempted again if its job is retried.
+		return nil, nil
+	}
+
+	if err := d.send(ctx, *delivery); err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		next := d.RetryPolicy().NextAttempt(job.Attempts)
+		if recordErr := d.Repo.MarkAttemptFailed(delivery.ID, job.Attempts, err.Error(), next); recordErr != nil {
+			return nil, recordErr
+		}
+		if next == nil {
+			return nil, jobs.Permanent(err)
+		}
+		return nil, err
+	}
+
+	if err := d.Repo.MarkDelivered(delivery.ID, job.Attempts); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// send makes a single signed delivery attempt. Any 2xx response is a success.
+func (d *Dispatcher) send(ctx context.Context, delivery models.WebhookDelivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "one-client-view-webhooks/1")
+	req.Header.Set(HeaderID, delivery.EventID)
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(delivery.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	return nil
+}
+
+// Sign returns the X-Webhook-Signature value for a request body sent at the
+// given Unix timestamp
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
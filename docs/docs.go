@@ -15,127 +15,85 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/applicants": {
-            "get": {
-                "description": "Retrieve a list of all applicants with their household members",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "applicants"
-                ],
-                "summary": "Get all applicants",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.ApplicantResponse"
-                            }
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            },
+        "/api/v1/admin/backup": {
             "post": {
-                "description": "Add a new applicant to the system",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Queue a job taking a consistent logical backup of the database: a zip of every table as JSON, kept in document storage under backups/. The job queue and migration bookkeeping are left out, and documents and photos, which are already in storage, are not copied. Restore a backup with ocvctl backup restore.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "applicants"
-                ],
-                "summary": "Create a new applicant",
-                "parameters": [
-                    {
-                        "description": "Applicant information",
-                        "name": "applicant",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Applicant"
-                        }
-                    }
+                    "admin"
                 ],
+                "summary": "Back up the database",
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "202": {
+                        "description": "Queued; the result is a handlers.BackupResult",
                         "schema": {
-                            "$ref": "#/definitions/models.ApplicantResponse"
+                            "$ref": "#/definitions/models.Job"
                         }
                     },
-                    "400": {
-                        "description": "Bad request",
+                    "403": {
+                        "description": "Requires the admin role",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierrors.APIError"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierrors.APIError"
                         }
                     }
                 }
             }
         },
-        "/api/applicants/{id}": {
+        "/api/v1/admin/features": {
             "get": {
-                "description": "Retrieve a specific applicant by their ID",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "List the known feature flags, with their configured defaults, the overrides admins have set and whether each is on. Requires the admin role.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "applicants"
-                ],
-                "summary": "Get applicant by ID",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Applicant ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "admin"
                 ],
+                "summary": "List feature flags",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ApplicantResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Applicant not found",
-                        "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.FeatureFlagState"
+                            }
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "403": {
+                        "description": "Requires the admin role",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierrors.APIError"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/admin/features/{name}": {
             "put": {
-                "description": "Update an existing applicant's information",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Turn a feature flag on or off, whatever its default, or on for the given roles only. The change applies at once on the server handling the request, and on the others within the refresh interval (features.refresh_interval, 30 seconds by default). Requires the admin role.",
                 "consumes": [
                     "application/json"
                 ],
@@ -143,24 +101,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "applicants"
+                    "admin"
                 ],
-                "summary": "Update applicant",
+                "summary": "Override a feature flag",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Applicant ID",
-                        "name": "id",
+                        "description": "Feature flag name",
+                        "name": "name",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated applicant information",
-                        "name": "applicant",
+                        "description": "Setting",
+                        "name": "flag",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Applicant"
+                            "$ref": "#/definitions/models.FeatureFlagRequest"
                         }
                     }
                 ],
@@ -168,72 +126,100 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Applicant"
+                            "$ref": "#/definitions/models.FeatureFlagState"
                         }
                     },
                     "400": {
                         "description": "Bad request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires the admin role",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
                         }
                     },
                     "404": {
-                        "description": "Applicant not found",
+                        "description": "Feature flag not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierrors.APIError"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierrors.APIError"
                         }
                     }
                 }
             },
             "delete": {
-                "description": "Remove an applicant from the system",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Remove the override of a feature flag, returning it to its configured default. Requires the admin role.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "applicants"
+                    "admin"
                 ],
-                "summary": "Delete applicant",
+                "summary": "Reset a feature flag",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Applicant ID",
-                        "name": "id",
+                        "description": "Feature flag name",
+                        "name": "name",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FeatureFlagState"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires the admin role",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
                     },
                     "404": {
-                        "description": "Applicant not found",
+                        "description": "Feature flag not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierrors.APIError"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierrors.APIError"
                         }
                     }
                 }
             }
         },
-        "/api/applications": {
+        "/api/v1/applicants": {
             "get": {
-                "description": "Retrieve a list of all financial assistance applications",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a list of applicants with their household members, optionally filtered. Custom fields are filtered with custom.{key}={value} query parameters, such as custom.dialect=hokkien, matching strings and enum values case-insensitively and numbers and booleans by value.",
                 "consumes": [
                     "application/json"
                 ],
@@ -241,29 +227,127 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "applications"
+                    "applicants"
+                ],
+                "summary": "Get all applicants",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Partial, case-insensitive match on name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "employed",
+                            "unemployed"
+                        ],
+                        "type": "string",
+                        "description": "Employment status",
+                        "name": "employment_status",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "single",
+                            "married",
+                            "widowed",
+                            "divorced"
+                        ],
+                        "type": "string",
+                        "description": "Marital status",
+                        "name": "marital_status",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "male",
+                            "female",
+                            "other"
+                        ],
+                        "type": "string",
+                        "description": "Sex",
+                        "name": "sex",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Two-digit postal district of the applicant's address, 01 to 28",
+                        "name": "postal_district",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum age in years",
+                        "name": "min_age",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum age in years",
+                        "name": "max_age",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include soft-deleted applicants (admin only)",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "full",
+                            "minimal"
+                        ],
+                        "type": "string",
+                        "description": "full (the default) or minimal, which masks identity numbers, dates of birth and phone numbers, reduces addresses to their postal district and omits household members; viewers always get minimal",
+                        "name": "view",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return, such as id,name,household.name; all if omitted",
+                        "name": "fields",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get all applications",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.SwaggerApplicationResponse"
+                                "$ref": "#/definitions/models.ApplicantResponse"
                             }
                         }
                     },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierrors.APIError"
                         }
                     }
                 }
             },
             "post": {
-                "description": "Submit a new application for a financial assistance scheme",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a new applicant to the system",
                 "consumes": [
                     "application/json"
                 ],
@@ -271,17 +355,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "applications"
+                    "applicants"
                 ],
-                "summary": "Create a new application",
+                "summary": "Create a new applicant",
                 "parameters": [
                     {
-                        "description": "Application information",
-                        "name": "application",
+                        "description": "Applicant information",
+                        "name": "applicant",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.ApplicationRequest"
+                            "$ref": "#/definitions/models.Applicant"
                         }
                     }
                 ],
@@ -289,75 +373,112 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.SwaggerApplicationResponse"
+                            "$ref": "#/definitions/models.ApplicantResponse"
                         }
                     },
                     "400": {
                         "description": "Bad request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierrors.APIError"
                         }
                     },
-                    "404": {
-                        "description": "Applicant or scheme not found",
+                    "409": {
+                        "description": "Duplicate identity number",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierrors.APIError"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierrors.APIError"
                         }
                     }
                 }
             }
         },
-        "/api/applications/{id}": {
+        "/api/v1/applicants/by-nric/{nric}": {
             "get": {
-                "description": "Retrieve a specific application by its ID",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Look up an applicant by NRIC or FIN, e.g. to check whether a person is already registered",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "applications"
+                    "applicants"
                 ],
-                "summary": "Get application by ID",
+                "summary": "Get applicant by NRIC",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Application ID",
-                        "name": "id",
+                        "description": "NRIC or FIN",
+                        "name": "nric",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "full",
+                            "minimal"
+                        ],
+                        "type": "string",
+                        "description": "full (the default) or minimal, which masks identity numbers, dates of birth and phone numbers, reduces addresses to their postal district and omits household members; viewers always get minimal",
+                        "name": "view",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return, such as id,name,household.name; all if omitted",
+                        "name": "fields",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.SwaggerApplicationResponse"
+                            "$ref": "#/definitions/models.ApplicantResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid NRIC or FIN",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
                         }
                     },
                     "404": {
-                        "description": "Application not found",
+                        "description": "Applicant not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierrors.APIError"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierrors.APIError"
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update an existing application's status or notes",
+            }
+        },
+        "/api/v1/applicants/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Queue a job creating many applicants with their household members. Each applicant is validated and created separately, so invalid ones and those whose NRIC is already registered are reported in the job's result without stopping the others. Poll the returned job for the outcome.",
                 "consumes": [
                     "application/json"
                 ],
@@ -365,135 +486,256 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "applications"
+                    "applicants"
                 ],
-                "summary": "Update application",
+                "summary": "Import applicants in bulk",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Application ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Updated application information",
-                        "name": "application",
+                        "description": "Applicants to create, at most 10000",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "notes": {
-                                    "type": "string"
-                                },
-                                "status": {
-                                    "type": "string"
-                                }
-                            }
+                            "$ref": "#/definitions/models.ImportApplicantsRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Queued; the result is a models.ImportApplicantsResult",
                         "schema": {
-                            "$ref": "#/definitions/models.SwaggerApplicationResponse"
+                            "$ref": "#/definitions/models.Job"
                         }
                     },
                     "400": {
-                        "description": "Bad request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "404": {
-                        "description": "Application not found",
+                        "description": "Invalid request body",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierrors.APIError"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierrors.APIError"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Remove an application from the system",
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/api/v1/applicants/prefill": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Fetch what the configured registry, such as MyInfo, holds about a person: their name, sex, date of birth, marital status, registered address and people who may belong in their household, such as children from birth records. Nothing is saved; review the data, add employment and income, and create the applicant as usual. If the person is already an applicant, existing_applicant_id is set.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "applications"
+                    "applicants"
                 ],
-                "summary": "Delete application",
+                "summary": "Prefill an applicant from an external registry",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Application ID",
-                        "name": "id",
-                        "in": "path",
+                        "description": "NRIC or FIN",
+                        "name": "nric",
+                        "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApplicantPrefill"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid NRIC or FIN",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
                     },
                     "404": {
-                        "description": "Application not found",
+                        "description": "Person not found in the registry",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierrors.APIError"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "502": {
+                        "description": "Registry request failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "Prefill not configured",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
                         }
                     }
                 }
             }
         },
-        "/api/schemes": {
+        "/api/v1/applicants/stream": {
             "get": {
-                "description": "Retrieve a list of all financial assistance schemes",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Stream the applicants matching the filters of the list endpoint as newline-delimited JSON, one applicant with their household per line, in order of ID. Applicants are written as they are read rather than held in memory, so any number can be downloaded. To resume an interrupted download, or fetch the next page after a limit, pass the ID of the last applicant received as after. An error once the stream has begun closes the connection, so the stream is seen to be incomplete.",
                 "produces": [
-                    "application/json"
+                    "application/x-ndjson"
                 ],
                 "tags": [
-                    "schemes"
+                    "applicants"
+                ],
+                "summary": "Stream applicants",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the applicant to start after; from the start if omitted",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Most applicants to stream; all if omitted",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Partial, case-insensitive match on name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "employed",
+                            "unemployed"
+                        ],
+                        "type": "string",
+                        "description": "Employment status",
+                        "name": "employment_status",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "single",
+                            "married",
+                            "widowed",
+                            "divorced"
+                        ],
+                        "type": "string",
+                        "description": "Marital status",
+                        "name": "marital_status",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "male",
+                            "female",
+                            "other"
+                        ],
+                        "type": "string",
+                        "description": "Sex",
+                        "name": "sex",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Two-digit postal district of the applicant's address, 01 to 28",
+                        "name": "postal_district",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum age in years",
+                        "name": "min_age",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum age in years",
+                        "name": "max_age",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include soft-deleted applicants (admin only)",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "full",
+                            "minimal"
+                        ],
+                        "type": "string",
+                        "description": "full (the default) or minimal, which masks identity numbers, dates of birth and phone numbers, reduces addresses to their postal district and omits household members; viewers always get minimal",
+                        "name": "view",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return, such as id,name,household.name; all if omitted",
+                        "name": "fields",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get all schemes",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "One models.ApplicantResponse per line",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.SchemeResponse"
-                            }
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "The applicants.stream feature flag is off",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierrors.APIError"
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Add a new financial assistance scheme",
+            }
+        },
+        "/api/v1/applicants/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a specific applicant by their ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -501,45 +743,74 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "schemes"
+                    "applicants"
                 ],
-                "summary": "Create a new scheme",
+                "summary": "Get applicant by ID",
                 "parameters": [
                     {
-                        "description": "Scheme information",
-                        "name": "scheme",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Scheme"
-                        }
+                        "type": "string",
+                        "description": "Applicant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include soft-deleted applicants (admin only)",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "full",
+                            "minimal"
+                        ],
+                        "type": "string",
+                        "description": "full (the default) or minimal, which masks identity numbers, dates of birth and phone numbers, reduces addresses to their postal district and omits household members; viewers always get minimal",
+                        "name": "view",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return, such as id,name,household.name; all if omitted",
+                        "name": "fields",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.SchemeResponse"
+                            "$ref": "#/definitions/models.ApplicantResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad request",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Applicant not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierrors.APIError"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierrors.APIError"
                         }
                     }
                 }
-            }
-        },
-        "/api/schemes/eligible": {
-            "get": {
-                "description": "Retrieve all schemes that an applicant is eligible for",
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update an existing applicant's information",
                 "consumes": [
                     "application/json"
                 ],
@@ -547,49 +818,85 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "schemes"
+                    "applicants"
                 ],
-                "summary": "Get eligible schemes for an applicant",
+                "summary": "Update applicant",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "Applicant ID",
-                        "name": "applicant",
-                        "in": "query",
+                        "name": "id",
+                        "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being updated; alternatively send version in the body",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Updated applicant information",
+                        "name": "applicant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Applicant"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.EligibleSchemesResponse"
+                            "$ref": "#/definitions/models.Applicant"
                         }
                     },
                     "400": {
                         "description": "Bad request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierrors.APIError"
                         }
                     },
                     "404": {
                         "description": "Applicant not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Version conflict, duplicate identity number or anonymized applicant",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "428": {
+                        "description": "Missing If-Match header or version",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierrors.APIError"
                         }
                     }
                 }
-            }
-        },
-        "/api/schemes/{id}": {
-            "get": {
-                "description": "Retrieve a specific scheme by its ID",
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Soft-delete an applicant, keeping their case history. Deleted applicants can be restored. Applicants with applications cannot be deleted unless cascade is set, which also soft-deletes their applications and requires the admin role.",
                 "consumes": [
                     "application/json"
                 ],
@@ -597,66 +904,99 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "schemes"
+                    "applicants"
                 ],
-                "summary": "Get scheme by ID",
+                "summary": "Delete applicant",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Scheme ID",
+                        "description": "Applicant ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also delete the applicant's applications (admin only)",
+                        "name": "cascade",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "No content"
+                    },
+                    "400": {
+                        "description": "Invalid cascade",
                         "schema": {
-                            "$ref": "#/definitions/models.SchemeResponse"
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "cascade requires the admin role",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
                         }
                     },
                     "404": {
-                        "description": "Scheme not found",
+                        "description": "Applicant not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Applicant has applications; details lists their IDs",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierrors.APIError"
                         }
                     }
                 }
             },
-            "put": {
-                "description": "Update an existing scheme's information",
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update selected fields of an applicant using JSON Merge Patch (RFC 7396): fields present in the body replace the stored values and omitted fields are left unchanged. Household members are not changed.",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/merge-patch+json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "schemes"
+                    "applicants"
                 ],
-                "summary": "Update scheme",
+                "summary": "Partially update applicant",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Scheme ID",
+                        "description": "Applicant ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated scheme information",
-                        "name": "scheme",
+                        "type": "string",
+                        "description": "ETag of the version being updated; alternatively send version in the body",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "patch",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Scheme"
+                            "$ref": "#/definitions/models.Applicant"
                         }
                     }
                 ],
@@ -664,354 +1004,10711 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.SchemeResponse"
+                            "$ref": "#/definitions/models.ApplicantResponse"
                         }
                     },
                     "400": {
                         "description": "Bad request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierrors.APIError"
                         }
                     },
                     "404": {
-                        "description": "Scheme not found",
+                        "description": "Applicant not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Version conflict, duplicate identity number or anonymized applicant",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Content-Type",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "428": {
+                        "description": "Missing If-Match header or version",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierrors.APIError"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierrors.APIError"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Remove a scheme from the system",
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/api/v1/applicants/{id}/anonymize": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Irreversibly replace the personal data of an applicant, including a deleted one, to honour a data-protection request. Names become random tokens and dates of birth 1 January of the birth year, for the applicant and their household; the NRIC, email, phone and address apart from the postal district are removed, case notes and application notes are replaced, and documents and the photo are deleted. Sex, marital and employment status, incomes, relations and applications are kept for statistics. Snapshots of the data are dropped from the audit log, which records the anonymization. Anonymized applicants cannot be changed. Requires the admin role.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "schemes"
+                    "applicants"
                 ],
-                "summary": "Delete scheme",
+                "summary": "Anonymize an applicant",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Scheme ID",
+                        "description": "Applicant ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No content"
-                    },
-                    "404": {
-                        "description": "Scheme not found",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/models.ApplicantResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "403": {
+                        "description": "Requires the admin role",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/apierrors.APIError"
                         }
+                    },
+                    "404": {
+                        "description": "Applicant not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Applicant has already been anonymized",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applicants/{id}/applications": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the application history of a specific applicant",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applications"
+                ],
+                "summary": "Get applications for an applicant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Applicant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "full",
+                            "minimal"
+                        ],
+                        "type": "string",
+                        "description": "full (the default) or minimal, which masks identity numbers, dates of birth and phone numbers, reduces addresses to their postal district and omits household members; viewers always get minimal",
+                        "name": "view",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return, such as id,name,household.name; all if omitted",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SwaggerApplicationResponse"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Applicant not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applicants/{id}/consents": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the consents recorded for an applicant, one per purpose, including withdrawn and expired ones. active tells whether each is in force now.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applicants"
+                ],
+                "summary": "List an applicant's consents",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Applicant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Consent"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Applicant not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applicants/{id}/consents/{purpose}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Record that an applicant consents to a purpose: data_sharing with partner agencies, including exports, being contacted by email (contact_email), phone (contact_phone) or post (contact_post), or having their income retrieved from government agencies (income_verification). This replaces any earlier consent to the purpose, including a withdrawn one. The authenticated user is recorded.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applicants"
+                ],
+                "summary": "Record consent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Applicant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "data_sharing",
+                            "contact_email",
+                            "contact_phone",
+                            "contact_post",
+                            "income_verification"
+                        ],
+                        "type": "string",
+                        "description": "Purpose",
+                        "name": "purpose",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Consent",
+                        "name": "consent",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ConsentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Consent replaced",
+                        "schema": {
+                            "$ref": "#/definitions/models.Consent"
+                        }
+                    },
+                    "201": {
+                        "description": "Consent recorded",
+                        "schema": {
+                            "$ref": "#/definitions/models.Consent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Applicant not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Applicant has been anonymized",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Record that an applicant withdraws their consent to a purpose, effective now. The consent is kept, with withdrawn_at set, as a record of what was agreed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applicants"
+                ],
+                "summary": "Withdraw consent",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Applicant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "data_sharing",
+                            "contact_email",
+                            "contact_phone",
+                            "contact_post",
+                            "income_verification"
+                        ],
+                        "type": "string",
+                        "description": "Purpose",
+                        "name": "purpose",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Consent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Applicant or consent not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Consent already withdrawn",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applicants/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the field-level changes made to an applicant, oldest first, with when each was made and by whom, reconstructed from the audit log. Use it to see, for example, when employment status changed relative to an application date.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applicants"
+                ],
+                "summary": "Get an applicant's change history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Applicant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only changes to this field, e.g. employment_status",
+                        "name": "field",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only changes at or after this time (RFC3339 or YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only changes before this time (RFC3339 or YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.HistoryEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Applicant not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applicants/{id}/household": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the household members an applicant is assessed against for eligibility and benefits at as_of (default now). For an applicant belonging to a shared household then, these are the household's head, the head's household members and the other applicants belonging to it, each with their relation to this applicant; relations without a counterpart, such as in-laws, are other. Otherwise they are the applicant's own household members.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applicants"
+                ],
+                "summary": "Get the household an applicant is assessed against",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Applicant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date to assess the household at (RFC3339 or YYYY-MM-DD); defaults to now",
+                        "name": "as_of",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SharedHousehold"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Applicant not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applicants/{id}/household/validate": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Check the household members of an applicant against each other and against other households: at most one member may be a spouse, sons and daughters must have been born when the applicant was between 12 and 65, and parents must have been that age when the applicant was born. An NRIC/FIN may not appear twice in the household, be the applicant's own, or belong to a member of another applicant's household or to another applicant. Deleted applicants are not compared. The problems found are reported, not rejected: households are saved as declared, as some, such as those of adoptive parents, are legitimately unusual.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applicants"
+                ],
+                "summary": "Validate an applicant's household",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Applicant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.HouseholdValidation"
+                        }
+                    },
+                    "404": {
+                        "description": "Applicant not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applicants/{id}/income": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the incomes retrieved for an applicant from external sources, newest first, each with its source, the year assessed, when and by whom it was retrieved, and the monthly_income it replaced",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applicants"
+                ],
+                "summary": "List the incomes retrieved for an applicant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Applicant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.IncomeRecord"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Applicant not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applicants/{id}/income/refresh": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Fetch the latest income assessed for an applicant from the configured source, such as IRAS, by their NRIC/FIN, and replace their monthly_income with it. The applicant must have an identity number and consent to income_verification. The retrieval is recorded with its source and date even when the income is unchanged, and a change to the applicant is audited like an update.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applicants"
+                ],
+                "summary": "Retrieve an applicant's assessed income",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Applicant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.IncomeRecord"
+                        }
+                    },
+                    "404": {
+                        "description": "Applicant not found, or no income at the source",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Applicant has no identity number, has not consented to income_verification, has been anonymized, or was changed meanwhile",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "502": {
+                        "description": "Source request failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "503": {
+                        "description": "Income retrieval not configured",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applicants/{id}/merge": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Merge the source applicant into this one. The source's household members, applications, case notes, referrals and income records are moved to the target (each application recording a merge event), differing fields are resolved by the policy (blank fields are always filled from the other record), and the source is soft-deleted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applicants"
+                ],
+                "summary": "Merge a duplicate applicant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Target applicant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the target version being merged into",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Source applicant and conflict policy",
+                        "name": "merge",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MergeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApplicantResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Applicant not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Version conflict, duplicate identity number or anonymized applicant",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "428": {
+                        "description": "Missing If-Match header",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applicants/{id}/notes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the case notes recorded for an applicant, oldest first, as a history of interactions. The minimal view omits the text of each note.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applicants"
+                ],
+                "summary": "List an applicant's case notes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Applicant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only notes with this tag",
+                        "name": "tag",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CaseNote"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Applicant not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Record an interaction with an applicant, such as a call or home visit. The authenticated user is recorded as the author. Tags are lowercased and repeated tags dropped.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applicants"
+                ],
+                "summary": "Add a case note",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Applicant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Case note",
+                        "name": "note",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CaseNoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.CaseNote"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Applicant not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applicants/{id}/photo": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download an applicant's photo, by default as a thumbnail. Not available to the viewer role.",
+                "produces": [
+                    "image/jpeg",
+                    "image/png"
+                ],
+                "tags": [
+                    "applicants"
+                ],
+                "summary": "Download an applicant's photo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Applicant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "thumbnail",
+                            "original"
+                        ],
+                        "type": "string",
+                        "description": "Size of the image (default thumbnail)",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The photo, as a JPEG or PNG",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid size",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Photo not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Set the photo shown on an applicant's client card, replacing any they had. The image must be a JPEG or PNG, detected from its content, of at most 40 megapixels. A thumbnail fitting within the configured size (by default 256 pixels) is made from it; both are re-encoded, which drops metadata such as where the photo was taken.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applicants"
+                ],
+                "summary": "Upload an applicant's photo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Applicant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "The photo",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Photo replaced",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApplicantPhoto"
+                        }
+                    },
+                    "201": {
+                        "description": "Photo added",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApplicantPhoto"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid multipart form",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Applicant not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "413": {
+                        "description": "File too large",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "415": {
+                        "description": "Not multipart/form-data, or not a JPEG or PNG",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove an applicant's photo and delete its images",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applicants"
+                ],
+                "summary": "Delete an applicant's photo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Applicant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No content"
+                    },
+                    "404": {
+                        "description": "Photo not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applicants/{id}/profile": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve everything known about an applicant in one response: the applicant and household, all applications with their schemes and documents, the schemes the applicant is eligible for at as_of (default now), the case notes, the consents and the referrals to other agencies, newest first. Scheme names and descriptions are served in the language preferred by Accept-Language where the scheme has been translated into it. The email, phone and address apart from its postal district are only included while the applicant consents to being contacted that way (contact_email, contact_phone and contact_post). Applicants signed in to the portal get their own profile without case notes. The number of queries does not grow with the number of applications.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applicants"
+                ],
+                "summary": "Get an applicant's profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Applicant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date or RFC3339 time to assess eligibility at (default now)",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "full",
+                            "minimal"
+                        ],
+                        "type": "string",
+                        "description": "full (the default) or minimal, which masks identity numbers, dates of birth and phone numbers, reduces addresses to their postal district and omits household members and the text of case notes; viewers always get minimal",
+                        "name": "view",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return, such as id,name,household.name; all if omitted",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred languages: en, zh, ms or ta",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SwaggerApplicantProfile"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Applicant not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applicants/{id}/referrals": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the referrals of an applicant to help delivered outside this system, such as another agency's scheme, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applicants"
+                ],
+                "summary": "List an applicant's referrals",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Applicant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "accepted",
+                            "declined",
+                            "completed"
+                        ],
+                        "type": "string",
+                        "description": "Only referrals with this outcome",
+                        "name": "outcome",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Referral"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Applicant not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Record that an applicant was referred to another agency, or to one of its schemes or services. referred_by defaults to the authenticated user's username, and the outcome to pending; outcome_at is set whenever the outcome is set to other than pending.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applicants"
+                ],
+                "summary": "Record a referral",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Applicant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Referral",
+                        "name": "referral",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReferralRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Referral"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Applicant not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applicants/{id}/referrals/{referralId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve one of an applicant's referrals",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applicants"
+                ],
+                "summary": "Get a referral",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Applicant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Referral ID",
+                        "name": "referralId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Referral"
+                        }
+                    },
+                    "404": {
+                        "description": "Applicant or referral not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace a referral, for example to record its outcome. Omitted fields take their defaults, as when recording one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applicants"
+                ],
+                "summary": "Update a referral",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Applicant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Referral ID",
+                        "name": "referralId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Referral",
+                        "name": "referral",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReferralRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Referral"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Applicant or referral not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a referral recorded in error",
+                "tags": [
+                    "applicants"
+                ],
+                "summary": "Delete a referral",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Applicant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Referral ID",
+                        "name": "referralId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Applicant or referral not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applicants/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restore a soft-deleted applicant",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applicants"
+                ],
+                "summary": "Restore applicant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Applicant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApplicantResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Applicant not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Applicant is not deleted",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applicants/{id}/schemes/{schemeId}/estimate": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Compute the amount of each of a scheme's benefits the applicant would be entitled to, applying any benefit formulas to their household at as_of (default now), and the total over the benefits' durations. The estimate is computed whether or not the applicant is eligible; eligible says whether they are, assessed as for eligible schemes.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schemes"
+                ],
+                "summary": "Estimate an applicant's benefits from a scheme",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Applicant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Scheme ID",
+                        "name": "schemeId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date or RFC3339 time to count the household and assess eligibility at (default now)",
+                        "name": "as_of",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BenefitEstimate"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Applicant or scheme not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applicants/{id}/timeline": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List everything that has happened to an applicant in one feed, newest first: changes to their record from the audit log, their applications and each change of an application's status, the documents attached, case notes, tasks such as appointments, and referrals to other agencies. Each entry's type names the member holding its record. Entries of deleted applications are left out. The number of queries does not grow with the number of applications.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applicants"
+                ],
+                "summary": "Get an applicant's timeline",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Applicant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated types of entries to list; all if omitted, e.g. application,status_change",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only entries at or after this time (RFC3339 or YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only entries before this time (RFC3339 or YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Most entries to list, newest first; all if omitted",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "full",
+                            "minimal"
+                        ],
+                        "type": "string",
+                        "description": "full (the default) or minimal, which masks identity numbers, dates of birth and phone numbers, reduces addresses to their postal district and omits household members and the text of case notes; viewers always get minimal",
+                        "name": "view",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return, such as type,occurred_at; all if omitted",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TimelineEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Applicant not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applications": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve financial assistance applications, newest first, optionally filtered. Each refers to its applicant and scheme by ID unless asked to expand them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applications"
+                ],
+                "summary": "Get all applications",
+                "parameters": [
+                    {
+                        "enum": [
+                            "pending",
+                            "approved",
+                            "rejected",
+                            "withdrawn"
+                        ],
+                        "type": "string",
+                        "description": "Status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Scheme ID",
+                        "name": "scheme_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Applicant ID",
+                        "name": "applicant_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only applications made on or after this date (YYYY-MM-DD)",
+                        "name": "applied_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only applications made before this date (YYYY-MM-DD)",
+                        "name": "applied_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID the applications are assigned to, me for the authenticated user, or none for unassigned applications",
+                        "name": "assigned_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include soft-deleted applications (admin only)",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "full",
+                            "minimal"
+                        ],
+                        "type": "string",
+                        "description": "full (the default) or minimal, which masks identity numbers, dates of birth and phone numbers, reduces addresses to their postal district and omits household members; viewers always get minimal",
+                        "name": "view",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated records to embed in each application: applicant, scheme or both; only their IDs if omitted",
+                        "name": "expand",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return, such as id,name,household.name; all if omitted",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SwaggerApplicationResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Submit a new application for a financial assistance scheme. An application from an applicant the scheme has rejected before is linked to their latest rejected application by previous_application_id, and is refused until the scheme's cooling_off_days have passed since that rejection.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applications"
+                ],
+                "summary": "Create a new application",
+                "parameters": [
+                    {
+                        "description": "Application information",
+                        "name": "application",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ApplicationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.SwaggerApplicationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Applicant or scheme not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Applicant already has an active application for this scheme, or was rejected for it within its cooling-off period",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed, scheme is not open for applications, or applicant is not eligible for this scheme",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applications/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download all applications, newest first, with applicant and scheme names, as a CSV or Excel file, streamed in batches. Text that spreadsheets would run as a formula is prefixed with an apostrophe. Applicants are only named if they consent to data_sharing; otherwise only their ID is included. Accepts the same filters as the list endpoint.",
+                "produces": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "applications"
+                ],
+                "summary": "Export applications",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "default": "csv",
+                        "description": "Export format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "approved",
+                            "rejected",
+                            "withdrawn"
+                        ],
+                        "type": "string",
+                        "description": "Status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Scheme ID",
+                        "name": "scheme_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Applicant ID",
+                        "name": "applicant_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only applications made on or after this date (YYYY-MM-DD)",
+                        "name": "applied_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only applications made before this date (YYYY-MM-DD)",
+                        "name": "applied_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID the applications are assigned to, me for the authenticated user, or none for unassigned applications",
+                        "name": "assigned_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include soft-deleted applications (admin only)",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Export file",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applications/validate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Run every check made when creating an application (the applicant and scheme exist, the scheme is open for applications, the applicant is eligible, has no pending or approved application for the scheme, and was not rejected for it within its cooling-off period) without creating it, reporting whether each passed. Checks that depend on a missing applicant or scheme are skipped.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applications"
+                ],
+                "summary": "Check an application without submitting it",
+                "parameters": [
+                    {
+                        "description": "Application information",
+                        "name": "application",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ApplicationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SubmissionAssessment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applications/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a specific application by its ID, with the rejected applications it resubmits, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applications"
+                ],
+                "summary": "Get application by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Application ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include soft-deleted applications (admin only)",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "full",
+                            "minimal"
+                        ],
+                        "type": "string",
+                        "description": "full (the default) or minimal, which masks identity numbers, dates of birth and phone numbers, reduces addresses to their postal district and omits household members; viewers always get minimal",
+                        "name": "view",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return, such as id,name,household.name; all if omitted",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SwaggerApplicationResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Application not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update an existing application's notes. The status cannot be changed directly; use the approve and reject endpoints.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applications"
+                ],
+                "summary": "Update application",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Application ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being updated; alternatively send version in the body",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Updated application information",
+                        "name": "application",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "notes": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                },
+                                "version": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SwaggerApplicationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Application not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Version conflict",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "428": {
+                        "description": "Missing If-Match header or version",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Soft-delete an application, keeping it for case history. Deleting an approved application releases the capacity it took up of its scheme. Deleted applications can be restored.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applications"
+                ],
+                "summary": "Delete application",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Application ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No content"
+                    },
+                    "404": {
+                        "description": "Application not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update an application's notes using JSON Merge Patch (RFC 7396): omitted fields are left unchanged and \"notes\": null clears the notes. The status cannot be changed directly; use the approve and reject endpoints.",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applications"
+                ],
+                "summary": "Partially update application",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Application ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being updated; alternatively send version in the body",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "notes": {
+                                    "type": "string"
+                                },
+                                "status": {
+                                    "type": "string"
+                                },
+                                "version": {
+                                    "type": "integer"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SwaggerApplicationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Application not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Version conflict",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Content-Type",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "428": {
+                        "description": "Missing If-Match header or version",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applications/{id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Approve a pending application, recording the approver, the reason and optionally a recommended benefit amount. Every mandatory item of the application's review checklist must have been completed. The approval takes up the scheme's capacity: its recommended amount, or the projected value of the applicant's entitlement to the scheme's benefits with benefit formulas applied to their household, counts against any budget. Requires the admin role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applications"
+                ],
+                "summary": "Approve application",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Application ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason for the decision",
+                        "name": "decision",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DecisionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SwaggerApplicationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires the admin role",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Application not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Application has already been decided, has mandatory checklist items not completed, or scheme has no remaining capacity",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applications/{id}/assign": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Assign a pending application for review to a caseworker or admin, the authenticated user if user_id is omitted. Caseworkers can only assign applications to themselves, and only those not assigned to someone else; admins can assign and reassign to anyone.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applications"
+                ],
+                "summary": "Assign application",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Application ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User to assign the application to",
+                        "name": "assignment",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.AssignRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SwaggerApplicationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Caseworkers can only assign unassigned applications to themselves",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Application not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Application has already been decided, or version conflict",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applications/{id}/checklist": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the items of an application's review checklist, copied from its scheme's when it was created, by position, then name, with who completed each and when. Mandatory items must be completed before the application is approved.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applications"
+                ],
+                "summary": "Get an application's review checklist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Application ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ApplicationChecklistItem"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Application not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applications/{id}/checklist/{itemId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mark an item of a pending application's review checklist completed by the authenticated user, with an optional note, or not completed. Items of decided or withdrawn applications cannot be changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applications"
+                ],
+                "summary": "Tick or untick an application checklist item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Application ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Checklist item ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Whether the item is completed",
+                        "name": "tick",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ChecklistTickRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApplicationChecklistItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Application or checklist item not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Application is no longer pending",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applications/{id}/comments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the internal comment threads on an application, oldest first, each with its replies. Comments are not shown to the applicant, unlike the application's notes. Deleted comments are listed, without their text, only while they have replies. The minimal view omits the text of each comment.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applications"
+                ],
+                "summary": "List an application's comments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Application ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Comment"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Application not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Comment on an application, or reply to a comment with parent_id; a reply to a reply joins the same thread. The authenticated user is recorded as the author. Users mentioned with @username are emailed, if they have an email address.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applications"
+                ],
+                "summary": "Add a comment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Application ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Comment",
+                        "name": "comment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CommentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Comment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Application not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applications/{id}/comments/{commentId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the text of a comment, recording when it was edited. Only the author can edit a comment. Users newly mentioned are emailed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applications"
+                ],
+                "summary": "Edit a comment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Application ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comment ID",
+                        "name": "commentId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Comment",
+                        "name": "comment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CommentEditRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Comment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Only the author can edit a comment",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Application or comment not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a comment, removing its text; a comment starting a thread stays listed, without its text, while it has replies. Caseworkers can only delete their own comments.",
+                "tags": [
+                    "applications"
+                ],
+                "summary": "Delete a comment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Application ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comment ID",
+                        "name": "commentId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Only the author or an admin can delete a comment",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Application or comment not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applications/{id}/documents": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the metadata of the documents attached to an application, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applications"
+                ],
+                "summary": "List an application's documents",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Application ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Document"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Application not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Attach a supporting document, such as a payslip or letter, to an application. The file's type is detected from its content and must be one of the configured types (by default PDF, JPEG or PNG).",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applications"
+                ],
+                "summary": "Upload a document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Application ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "The document",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Document"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid multipart form",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Application not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "413": {
+                        "description": "File too large",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "415": {
+                        "description": "Not multipart/form-data, or file type not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applications/{id}/documents/{documentId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download the content of a document attached to an application. Not available to the viewer role.",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "applications"
+                ],
+                "summary": "Download a document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Application ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "documentId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The document, with its detected content type",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove a document from an application and delete its content",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applications"
+                ],
+                "summary": "Delete a document",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Application ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "documentId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No content"
+                    },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applications/{id}/events": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List every transition of an application as an immutable event, oldest first, including those of a deleted application. Create, anonymize and backfill events hold the whole application and the others the fields they set, so replaying them in order rebuilds the application's current state. Each event has a version counting up from 1 within the application and a sequence ordering events across applications. The data of events of purged and anonymized applications is dropped.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applications"
+                ],
+                "summary": "Get an application's events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Application ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ApplicationEvent"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Application not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applications/{id}/flags": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the flags raised when scheduled re-evaluation found the applicant no longer met the scheme's criteria, newest first. A flag is resolved once the applicant is found eligible again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applications"
+                ],
+                "summary": "Get an application's review flags",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Application ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ReviewFlag"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Application not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applications/{id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Reject a pending application, recording who rejected it and why. Requires the admin role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applications"
+                ],
+                "summary": "Reject application",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Application ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason for the decision",
+                        "name": "decision",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "reason": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SwaggerApplicationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires the admin role",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Application not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Application has already been decided",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applications/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restore a soft-deleted application. Restoring an approved application takes up capacity of its scheme again, and fails with 409 if the scheme has none left.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applications"
+                ],
+                "summary": "Restore application",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Application ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SwaggerApplicationResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Application not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Application is not deleted, or its scheme has no remaining capacity",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applications/{id}/tracking-token": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the token the applicant can check the application's status with at /api/v1/track/{token}, without an account. The token is also included in the email sent when the application is submitted. Tokens do not expire, and the same token is returned every time.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applications"
+                ],
+                "summary": "Get an application's tracking token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Application ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TrackingToken"
+                        }
+                    },
+                    "404": {
+                        "description": "Application not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applications/{id}/unassign": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove the assignment of a pending application, returning it to the unassigned queue. Caseworkers can only unassign applications assigned to themselves.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applications"
+                ],
+                "summary": "Unassign application",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Application ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SwaggerApplicationResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Caseworkers can only unassign their own applications",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Application not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Application has already been decided, or version conflict",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/applications/{id}/withdraw": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Withdraw a pending or approved application, moving it to the terminal withdrawn status with the reason given. Withdrawing an approval releases the scheme capacity it took up. Applicants signed in to the portal can withdraw their own applications; caseworkers and admins can withdraw any. The applicant is emailed, as is the user the application is assigned to, if it was not them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applications"
+                ],
+                "summary": "Withdraw application",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Application ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason for the withdrawal",
+                        "name": "withdrawal",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WithdrawRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SwaggerApplicationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Application not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Application has been rejected or withdrawn already, or version conflict",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/audit": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve recorded mutations, newest first, for compliance reviews",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audit"
+                ],
+                "summary": "Get audit log entries",
+                "parameters": [
+                    {
+                        "enum": [
+                            "applicant",
+                            "scheme",
+                            "application",
+                            "benefit",
+                            "document",
+                            "case_note",
+                            "applicant_photo",
+                            "consent",
+                            "scheme_translation",
+                            "task",
+                            "comment",
+                            "referral",
+                            "custom_field",
+                            "feature_flag",
+                            "household",
+                            "household_membership",
+                            "checklist_item",
+                            "application_checklist_item"
+                        ],
+                        "type": "string",
+                        "description": "Entity type",
+                        "name": "entity_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Entity ID",
+                        "name": "entity_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "create",
+                            "update",
+                            "delete",
+                            "restore",
+                            "approve",
+                            "reject",
+                            "merge",
+                            "purge",
+                            "anonymize",
+                            "assign",
+                            "unassign",
+                            "publish",
+                            "archive"
+                        ],
+                        "type": "string",
+                        "description": "Action",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Actor user ID or username",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only entries at or after this time (RFC3339 or YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only entries before this time (RFC3339 or YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of entries (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AuditLog"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/auth/login": {
+            "post": {
+                "description": "Authenticate with a username and password to obtain a bearer token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Log in",
+                "parameters": [
+                    {
+                        "description": "User credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid username or password",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/custom-fields": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the custom fields defined for applicants, ordered by key. Their values are set in the custom_fields of applicants, can filter GET /api/v1/applicants with custom.{key}={value}, and can be compared in scheme criteria rules as the field custom.{key}.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "custom-fields"
+                ],
+                "summary": "List custom fields",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CustomField"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Define a custom field for applicants: a string (optionally with max_length), number (optionally with min and max), boolean, or enum with the given options. Applicants saved from then on are validated against it; making it required does not affect applicants until they are next saved. Requires the admin role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "custom-fields"
+                ],
+                "summary": "Define a custom field",
+                "parameters": [
+                    {
+                        "description": "Custom field",
+                        "name": "field",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CustomField"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.CustomField"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires the admin role",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Custom field already exists",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/custom-fields/{key}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the definition of a custom field",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "custom-fields"
+                ],
+                "summary": "Get a custom field",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Custom field key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CustomField"
+                        }
+                    },
+                    "404": {
+                        "description": "Custom field not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the definition of a custom field. Its key and type cannot be changed. Values stored before the change are kept, and applicants are validated against the new definition when next saved. Requires the admin role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "custom-fields"
+                ],
+                "summary": "Update a custom field",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Custom field key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Custom field",
+                        "name": "field",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CustomField"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CustomField"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires the admin role",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Custom field not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete the definition of a custom field no applicant, including a deleted one, has a value for. Scheme criteria comparing it stop matching anyone. Requires the admin role.",
+                "tags": [
+                    "custom-fields"
+                ],
+                "summary": "Delete a custom field",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Custom field key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Requires the admin role",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Custom field not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Custom field is in use",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/households": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a household shared by applicants of the same family, headed by the given applicant. The head's household members are the household's, so other applicants who join it need not declare them again. An applicant heads at most one household and cannot head one while belonging to another.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applicants"
+                ],
+                "summary": "Create a shared household",
+                "parameters": [
+                    {
+                        "description": "Household",
+                        "name": "household",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.HouseholdRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Household"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "The applicant already heads or belongs to a household",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/households/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a household with the memberships of the applicants other than its head, present and past, by start date. Its members are the head's household members; see GET /api/v1/applicants/{id}/household for the household an applicant is assessed against.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applicants"
+                ],
+                "summary": "Get a shared household",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Household ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Household"
+                        }
+                    },
+                    "404": {
+                        "description": "Household not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a household and its memberships. Its applicants are assessed against their own household members again, including for past dates.",
+                "tags": [
+                    "applicants"
+                ],
+                "summary": "Delete a shared household",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Household ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Household not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/households/{id}/memberships": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Record that an applicant belongs to a household from start_date, and until end_date if given, with their relation to its head. Meanwhile they are assessed for eligibility and benefits against the household, seen from their side, instead of their own household members. An applicant belongs to at most one household at a time, and cannot join one while heading another.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applicants"
+                ],
+                "summary": "Add an applicant to a shared household",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Household ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Membership",
+                        "name": "membership",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.HouseholdMembershipRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.HouseholdMembership"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Household not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "The applicant belongs to or heads a household during the period",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/households/{id}/memberships/{membershipId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the relation and dates of a membership, for example setting its end_date when the applicant leaves the household. Its applicant cannot be changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "applicants"
+                ],
+                "summary": "Update a household membership",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Household ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Membership ID",
+                        "name": "membershipId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Membership",
+                        "name": "membership",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.HouseholdMembershipRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.HouseholdMembership"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Household or membership not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "The applicant belongs to or heads a household during the period",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a membership recorded in error. When an applicant leaves a household, set the membership's end_date instead, so they are still assessed against the household for the time they belonged to it.",
+                "tags": [
+                    "applicants"
+                ],
+                "summary": "Delete a household membership",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Household ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Membership ID",
+                        "name": "membershipId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Household or membership not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/jobs/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Poll a background job started by an endpoint that returned 202 Accepted. The result is set once the job has succeeded; failed attempts are retried with backoff and last_error holds the latest failure. Users see only their own jobs; admins see all jobs.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Get job status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Job"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/jobs/{id}/retry": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Queue a job that has failed for good to run again now, with its attempts reset",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Retry a failed job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.Job"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Job has not failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/labels": {
+            "get": {
+                "description": "Retrieve the labels of the values of application_status, employment_status, sex, marital_status, relation, school_level, consent_purpose, benefit_frequency, referral_outcome and scheme_status, in the language preferred by Accept-Language: English (en), Chinese (zh), Malay (ms) or Tamil (ta). Values are listed in the order the API documents them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "labels"
+                ],
+                "summary": "Get labels of enumerated values",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Preferred languages: en, zh, ms or ta",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/models.Label"
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/portal/code": {
+            "post": {
+                "description": "Send a one-time code to the applicant with the given NRIC or FIN, by email or text message to the phone on record, to sign in to the portal with. The response is the same whether or not the applicant exists and has an email address or phone, so that it does not tell who has applied. A new code replaces the previous one, and no code is sent within a minute of the last.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "portal"
+                ],
+                "summary": "Request a portal sign-in code",
+                "parameters": [
+                    {
+                        "description": "Applicant and channel",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PortalCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/portal/login": {
+            "post": {
+                "description": "Exchange a one-time code from POST /api/v1/portal/code for a bearer token that lets the applicant read their own record, profile, applications and eligible schemes, and nothing else. A code can be used once, and stops working once it expires or after too many wrong codes. Too many attempts without signing in lock the applicant's sign-in for a time, whatever code is entered.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "portal"
+                ],
+                "summary": "Sign in to the portal",
+                "parameters": [
+                    {
+                        "description": "Applicant and code",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PortalLoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PortalLoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid or expired code",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/reports/applications-summary": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Count applications by status, scheme and month of application, with the average time to decision and total benefit amounts of approved applications. Accepts the same filters as listing applications.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Get application statistics",
+                "parameters": [
+                    {
+                        "enum": [
+                            "pending",
+                            "approved",
+                            "rejected",
+                            "withdrawn"
+                        ],
+                        "type": "string",
+                        "description": "Only applications with this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only applications for this scheme",
+                        "name": "scheme_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only applications by this applicant",
+                        "name": "applicant_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only applications made on or after this date (YYYY-MM-DD)",
+                        "name": "applied_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only applications made before this date (YYYY-MM-DD)",
+                        "name": "applied_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID the applications are assigned to, me for the authenticated user, or none for unassigned applications",
+                        "name": "assigned_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include soft-deleted applications (admin only)",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApplicationsSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/reports/applications-summary/jobs": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Queue a job computing the same statistics as GET /api/v1/reports/applications-summary, for large date ranges. Poll the returned job for the outcome.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Generate application statistics in the background",
+                "parameters": [
+                    {
+                        "enum": [
+                            "pending",
+                            "approved",
+                            "rejected",
+                            "withdrawn"
+                        ],
+                        "type": "string",
+                        "description": "Only applications with this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only applications for this scheme",
+                        "name": "scheme_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only applications by this applicant",
+                        "name": "applicant_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only applications made on or after this date (YYYY-MM-DD)",
+                        "name": "applied_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only applications made before this date (YYYY-MM-DD)",
+                        "name": "applied_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID the applications are assigned to, me for the authenticated user, or none for unassigned applications",
+                        "name": "assigned_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include soft-deleted applications (admin only)",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Queued; the result is a models.ApplicationsSummary",
+                        "schema": {
+                            "$ref": "#/definitions/models.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/reports/geographic": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Count applications, approvals and applicants by the postal district of the applicant's address, or by the planning region most of the district lies in, so planners can see where demand concentrates. Every district or region is listed, with the approximate centre to plot it at; applications by applicants without an address are counted as unlocated. Applicants are placed by their current address. With format=geojson the report is a GeoJSON FeatureCollection of points, one per area with its counts as properties, for mapping tools. Accepts the same filters as listing applications.",
+                "produces": [
+                    "application/json",
+                    "application/geo+json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Get applications by area",
+                "parameters": [
+                    {
+                        "enum": [
+                            "district",
+                            "region"
+                        ],
+                        "type": "string",
+                        "default": "district",
+                        "description": "Group by postal district or planning region",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "geojson"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Response format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "approved",
+                            "rejected",
+                            "withdrawn"
+                        ],
+                        "type": "string",
+                        "description": "Only applications with this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only applications for this scheme",
+                        "name": "scheme_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only applications made on or after this date (YYYY-MM-DD)",
+                        "name": "applied_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only applications made before this date (YYYY-MM-DD)",
+                        "name": "applied_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID the applications are assigned to, me for the authenticated user, or none for unassigned applications",
+                        "name": "assigned_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include soft-deleted applications (admin only)",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The report, or with format=geojson a models.GeoJSONFeatureCollection",
+                        "schema": {
+                            "$ref": "#/definitions/models.GeographicReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/reports/schemes/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Count a scheme's applications by status and month of application, with the average time to decision and total benefit amounts of approved applications",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Get application statistics for a scheme",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scheme ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "approved",
+                            "rejected",
+                            "withdrawn"
+                        ],
+                        "type": "string",
+                        "description": "Only applications with this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only applications by this applicant",
+                        "name": "applicant_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only applications made on or after this date (YYYY-MM-DD)",
+                        "name": "applied_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only applications made before this date (YYYY-MM-DD)",
+                        "name": "applied_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID the applications are assigned to, me for the authenticated user, or none for unassigned applications",
+                        "name": "assigned_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include soft-deleted applications (admin only)",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SchemeReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Scheme not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/reports/schemes/{id}/coverage": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Compare the registered applicants who meet a scheme's criteria at as_of, as assessed by the batch eligibility check, with those who have applied for it, to find under-utilized schemes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Get eligibility coverage for a scheme",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scheme ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Time to assess eligibility at (RFC3339 or YYYY-MM-DD); now if omitted",
+                        "name": "as_of",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SchemeCoverage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Scheme not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/reports/workload": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Count, for every caseworker and admin, the pending applications assigned to them and the applications they approved and rejected, with the number of pending applications assigned to no one. Accepts the filters of listing applications other than status and assigned_to.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Get caseworker workload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only applications for this scheme",
+                        "name": "scheme_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only applications by this applicant",
+                        "name": "applicant_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only applications made on or after this date (YYYY-MM-DD)",
+                        "name": "applied_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only applications made before this date (YYYY-MM-DD)",
+                        "name": "applied_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include soft-deleted applications (admin only)",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WorkloadReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/retention/runs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the latest runs of the retention rules, scheduled or requested, newest first. The result of each finished run is a models.RetentionRunResult reporting, for each rule, its cutoff and the records it matched and purged or anonymized. Requires the admin role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "retention"
+                ],
+                "summary": "List retention runs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of runs (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Job"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires the admin role",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Queue a run of the configured retention rules: rejected applications past their period are purged with their documents, and applicants inactive for longer than theirs are anonymized. Runs are dry runs unless dry_run=false, reporting what would be removed without changing anything. Poll the returned job for the outcome. Requires the admin role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "retention"
+                ],
+                "summary": "Run the retention rules",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only report what the rules match (default true)",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Queued; the result is a models.RetentionRunResult",
+                        "schema": {
+                            "$ref": "#/definitions/models.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires the admin role",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/schemes": {
+            "get": {
+                "description": "Retrieve a list of all financial assistance schemes, optionally only those that are or are not open for applications now, or with a status. Without a token, or with an applicant's, only published schemes are listed. Names and descriptions are served in the language preferred by Accept-Language where the scheme has been translated into it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schemes"
+                ],
+                "summary": "Get all schemes",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "true for schemes open for applications now, false for the rest",
+                        "name": "active",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "draft",
+                            "published",
+                            "archived"
+                        ],
+                        "type": "string",
+                        "description": "Only schemes with this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return, such as id,name,household.name; all if omitted",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred languages: en, zh, ms or ta",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SchemeResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid active or status",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a new financial assistance scheme as a draft, which can be changed without being offered to anyone until an admin publishes it with POST /api/v1/schemes/{id}/publish. Caseworkers and admins can change drafts.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schemes"
+                ],
+                "summary": "Create a new scheme",
+                "parameters": [
+                    {
+                        "description": "Scheme information",
+                        "name": "scheme",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Scheme"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.SchemeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/schemes/eligible": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve all schemes that an applicant is eligible for, assessed against the terms of each scheme in effect at as_of (default now). Names and descriptions are served in the language preferred by Accept-Language where the scheme has been translated into it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schemes"
+                ],
+                "summary": "Get eligible schemes for an applicant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Applicant ID",
+                        "name": "applicant",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date or RFC3339 time to assess eligibility at (default now)",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred languages: en, zh, ms or ta",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.EligibleSchemesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Applicant not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/schemes/eligible/batch": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Queue a job finding the schemes each of the given applicants, or every applicant, is eligible for, assessed against the terms in effect at as_of. Poll the returned job for the outcome.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schemes"
+                ],
+                "summary": "Check eligibility in bulk",
+                "parameters": [
+                    {
+                        "description": "Applicants to check and the time to assess at",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.BatchEligibilityRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Queued; the result is a models.BatchEligibilityResult",
+                        "schema": {
+                            "$ref": "#/definitions/models.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/schemes/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a specific scheme by its ID, with its name and description in the language preferred by Accept-Language if it has been translated into it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schemes"
+                ],
+                "summary": "Get scheme by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scheme ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return, such as id,name,household.name; all if omitted",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred languages: en, zh, ms or ta",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SchemeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Scheme not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update an existing scheme's information. The new name, description and criteria take effect at effective_from (default now); applications keep the terms they were assessed under. Published schemes can only be changed by admins, and archived ones not at all; the status is changed by publishing and archiving, and benefits and the checklist through their own endpoints.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schemes"
+                ],
+                "summary": "Update scheme",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scheme ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being updated; alternatively send version in the body",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Updated scheme information",
+                        "name": "scheme",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Scheme"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SchemeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Published schemes can only be changed by admins",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Scheme not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Version conflict, or scheme is archived",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "428": {
+                        "description": "Missing If-Match header or version",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove a scheme from the system with its benefits and checklist. Schemes with applications, including deleted ones, cannot be deleted; archive them instead. Published schemes can only be deleted by admins.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schemes"
+                ],
+                "summary": "Delete scheme",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scheme ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No content"
+                    },
+                    "403": {
+                        "description": "Published schemes can only be deleted by admins",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Scheme not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Scheme has applications",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update selected fields of a scheme using JSON Merge Patch (RFC 7396): fields present in the body replace the stored values, nested criteria are merged, and null removes an optional criterion. The new terms take effect at effective_from (default now). Benefits, the checklist and the status are not changed. Published schemes can only be changed by admins, and archived ones not at all.",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schemes"
+                ],
+                "summary": "Partially update scheme",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scheme ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being updated; alternatively send version in the body",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Scheme"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SchemeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Published schemes can only be changed by admins",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Scheme not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Version conflict, or scheme is archived",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Content-Type",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "428": {
+                        "description": "Missing If-Match header or version",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/schemes/{id}/archive": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Archive a draft or published scheme, withdrawing it: archived schemes are no longer listed publicly, assessed for eligibility or open for applications, and cannot be changed. Existing applications are kept and can still be decided. Requires the admin role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schemes"
+                ],
+                "summary": "Archive a scheme",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scheme ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SchemeResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires the admin role",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Scheme not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Scheme is already archived",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/schemes/{id}/benefits": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a new benefit to an existing scheme. Benefits of published schemes can only be changed by admins, and those of archived schemes not at all.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schemes"
+                ],
+                "summary": "Add a benefit to a scheme",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scheme ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Benefit information",
+                        "name": "benefit",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Benefit"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Benefit"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Published schemes can only be changed by admins",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Scheme not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Scheme is archived",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/schemes/{id}/benefits/{benefitId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the name, description and amount of a scheme's benefit, e.g. when a policy change adjusts the amount",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schemes"
+                ],
+                "summary": "Update a scheme benefit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scheme ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Benefit ID",
+                        "name": "benefitId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated benefit information",
+                        "name": "benefit",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Benefit"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Benefit"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Published schemes can only be changed by admins",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Benefit not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Scheme is archived",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove a benefit from a scheme",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schemes"
+                ],
+                "summary": "Delete a scheme benefit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scheme ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Benefit ID",
+                        "name": "benefitId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No content"
+                    },
+                    "403": {
+                        "description": "Published schemes can only be changed by admins",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Benefit not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Scheme is archived",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/schemes/{id}/checklist": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a review checklist item, such as a document to verify or an interview to conduct, to a scheme. Applications created from then on get a copy of it; mandatory items must be completed before an application is approved. Checklists of published schemes can only be changed by admins, and those of archived schemes not at all.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schemes"
+                ],
+                "summary": "Add an item to a scheme's review checklist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scheme ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Checklist item",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ChecklistItem"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ChecklistItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Published schemes can only be changed by admins",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Scheme not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Scheme is archived",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/schemes/{id}/checklist/{itemId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the name, description, kind, mandatory flag and position of an item of a scheme's review checklist. The copies on existing applications are not changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schemes"
+                ],
+                "summary": "Update a scheme checklist item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scheme ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Checklist item ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated checklist item",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ChecklistItem"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ChecklistItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Published schemes can only be changed by admins",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Checklist item not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Scheme is archived",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove an item from a scheme's review checklist. The copies on existing applications are kept and must still be completed if mandatory.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schemes"
+                ],
+                "summary": "Delete a scheme checklist item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scheme ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Checklist item ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No content"
+                    },
+                    "403": {
+                        "description": "Published schemes can only be changed by admins",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Checklist item not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Scheme is archived",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/schemes/{id}/clone": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Copy a scheme with its criteria, window, limits, benefits and checklist into a new draft named with a \" (copy)\" suffix, for example to start next year's scheme from this year's. The copy has new IDs, its own version history starting at 1, no approvals and no translations. Schemes in any status can be cloned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schemes"
+                ],
+                "summary": "Clone a scheme",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scheme ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.SchemeResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Scheme not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/schemes/{id}/publish": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Publish a draft or archived scheme, offering it to applicants: published schemes are listed publicly, assessed for eligibility and accept applications while active and within their window. Publishing an archived scheme offers it again. Requires the admin role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schemes"
+                ],
+                "summary": "Publish a scheme",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scheme ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SchemeResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires the admin role",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Scheme not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Scheme is already published",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/schemes/{id}/translations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the translations of a scheme's name and description into Chinese (zh), Malay (ms) and Tamil (ta). Scheme endpoints serve them to clients whose Accept-Language prefers the language, and English otherwise.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schemes"
+                ],
+                "summary": "List a scheme's translations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scheme ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SchemeTranslation"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Scheme not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/schemes/{id}/translations/{language}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Save the name and description of a scheme in Chinese (zh), Malay (ms) or Tamil (ta), replacing any earlier translation into the language. Translations are not versioned with the scheme's terms, so update them when the English name or description changes.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schemes"
+                ],
+                "summary": "Translate a scheme",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scheme ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "zh",
+                            "ms",
+                            "ta"
+                        ],
+                        "type": "string",
+                        "description": "Language",
+                        "name": "language",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Translation",
+                        "name": "translation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SchemeTranslationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Translation replaced",
+                        "schema": {
+                            "$ref": "#/definitions/models.SchemeTranslation"
+                        }
+                    },
+                    "201": {
+                        "description": "Translation created",
+                        "schema": {
+                            "$ref": "#/definitions/models.SchemeTranslation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Scheme not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete the translation of a scheme into a language, which is then served in English",
+                "tags": [
+                    "schemes"
+                ],
+                "summary": "Delete a scheme translation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scheme ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "zh",
+                            "ms",
+                            "ta"
+                        ],
+                        "type": "string",
+                        "description": "Language",
+                        "name": "language",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No content"
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Scheme or translation not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/schemes/{id}/versions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the history of a scheme's terms with the period each version was in effect, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schemes"
+                ],
+                "summary": "Get scheme versions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scheme ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SchemeVersion"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Scheme not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/search": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Find applicants and household members by name, schemes by name and applications by notes. Words match whole and case-insensitively; results matching more of the words rank first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Search across entities",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Words to search for, e.g. Tan primary school",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated entity types to search (default all): applicant, household_member, scheme, application",
+                        "name": "types",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of results (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SearchResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List tasks, soonest due first, with tasks without a due date last",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "List tasks",
+                "parameters": [
+                    {
+                        "enum": [
+                            "open",
+                            "done",
+                            "cancelled"
+                        ],
+                        "type": "string",
+                        "description": "Task status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID the tasks are assigned to, me for the authenticated user, or none for unassigned tasks",
+                        "name": "assigned_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Applicant ID",
+                        "name": "applicant_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Application ID",
+                        "name": "application_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Task"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a follow-up for an applicant, or for one of their applications, optionally due by a date and assigned to a caseworker or admin. The assignee is emailed a reminder once the task is due soon. The authenticated user is recorded as its creator.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Create task",
+                "parameters": [
+                    {
+                        "description": "Task",
+                        "name": "task",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TaskRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Task"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Applicant or application not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/overdue": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the open tasks past their due date, the longest overdue first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "List overdue tasks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID the tasks are assigned to, me for the authenticated user, or none for unassigned tasks",
+                        "name": "assigned_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Applicant ID",
+                        "name": "applicant_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Application ID",
+                        "name": "application_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Task"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tasks/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a task by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Get task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Task"
+                        }
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace a task, for example to mark it done or move its due date. Changing the due date or assignee lets the assignee be reminded again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Update task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Task",
+                        "name": "task",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TaskRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Task"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Task, applicant or application not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a task. Tasks no longer needed can instead be cancelled, keeping them on record.",
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Delete task",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Task ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Task not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/track/{token}": {
+            "get": {
+                "description": "Retrieve the status of an application and what happens next, with its tracking token and without signing in, in the language preferred by Accept-Language: English (en), Chinese (zh), Malay (ms) or Tamil (ta). Nothing else about the application or applicant is returned. Tokens that are invalid or whose application has been deleted are not found.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "track"
+                ],
+                "summary": "Track an application",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tracking token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preferred languages: en, zh, ms or ta",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ApplicationTracking"
+                        }
+                    },
+                    "404": {
+                        "description": "Application not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve all registered webhooks. Secrets are not included. Requires the admin role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get all webhooks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Webhook"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Requires the admin role",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Register a URL to be notified of the given events. A signing secret is generated unless one is supplied, and is only returned in this response. Requires the admin role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Register a webhook",
+                "parameters": [
+                    {
+                        "description": "Webhook URL, events and optional secret",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "events": {
+                                    "type": "array",
+                                    "items": {
+                                        "type": "string"
+                                    }
+                                },
+                                "secret": {
+                                    "type": "string"
+                                },
+                                "url": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires the admin role",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a registered webhook. The secret is not included. Requires the admin role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get webhook by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Webhook"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires the admin role",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove a webhook and discard its queued deliveries. Requires the admin role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No content"
+                    },
+                    "403": {
+                        "description": "Requires the admin role",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the most recent queued and attempted deliveries to a webhook, newest first. Requires the admin role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of deliveries (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.WebhookDelivery"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires the admin role",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/apierrors.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Ping the database and report the state of its circuit breaker. Responds 503 while the database cannot be reached or the breaker is open, so load balancers stop routing to this instance.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Check readiness",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Readiness"
+                        }
+                    },
+                    "503": {
+                        "description": "Not ready",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Readiness"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "apierrors.APIError": {
+            "description": "Error response",
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "not_found"
+                },
+                "details": {
+                    "description": "A string, a list of strings or an object of field errors"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Applicant not found"
+                },
+                "request_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "database.BreakerStatus": {
+            "type": "object",
+            "properties": {
+                "consecutive_failures": {
+                    "type": "integer"
+                },
+                "opened_at": {
+                    "description": "When the breaker last opened, while it is not closed",
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string",
+                    "enum": [
+                        "closed",
+                        "open",
+                        "half_open"
+                    ],
+                    "example": "closed"
+                }
+            }
+        },
+        "handlers.DatabaseHealth": {
+            "type": "object",
+            "properties": {
+                "breaker": {
+                    "description": "The circuit breaker guarding MySQL connections; omitted for SQLite",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/database.BreakerStatus"
+                        }
+                    ]
+                },
+                "error": {
+                    "description": "Why the database could not be reached",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "up",
+                        "down"
+                    ],
+                    "example": "up"
+                }
+            }
+        },
+        "handlers.Readiness": {
+            "type": "object",
+            "properties": {
+                "database": {
+                    "$ref": "#/definitions/handlers.DatabaseHealth"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "ready",
+                        "unavailable"
+                    ],
+                    "example": "ready"
+                }
+            }
+        },
+        "models.Address": {
+            "type": "object",
+            "properties": {
+                "block": {
+                    "type": "string",
+                    "example": "123"
+                },
+                "building": {
+                    "type": "string"
+                },
+                "postal_code": {
+                    "type": "string",
+                    "example": "560123"
+                },
+                "postal_district": {
+                    "description": "Derived from the postal code; read-only",
+                    "type": "string",
+                    "example": "20"
+                },
+                "street": {
+                    "type": "string",
+                    "example": "Ang Mo Kio Avenue 3"
+                },
+                "unit": {
+                    "type": "string",
+                    "example": "#12-34"
+                }
+            }
+        },
+        "models.Applicant": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "$ref": "#/definitions/models.Address"
+                },
+                "anonymized_at": {
+                    "description": "Set once personal data has been irreversibly replaced",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "custom_fields": {
+                    "description": "Values of the custom fields defined in this deployment, by key",
+                    "type": "object"
+                },
+                "date_of_birth": {
+                    "type": "string",
+                    "format": "date",
+                    "example": "1990-07-01"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "email_opt_out": {
+                    "description": "Set when the applicant declines email notifications",
+                    "type": "boolean"
+                },
+                "employment_status": {
+                    "type": "string"
+                },
+                "household": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.HouseholdMember"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "identity_number": {
+                    "description": "NRIC or FIN, unique; encrypted at rest",
+                    "type": "string",
+                    "example": "S1234567D"
+                },
+                "marital_status": {
+                    "type": "string"
+                },
+                "monthly_income": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "description": "Stored without spaces or hyphens",
+                    "type": "string",
+                    "example": "+6591234567"
+                },
+                "sex": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Incremented on every update, for optimistic locking",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ApplicantPhoto": {
+            "type": "object",
+            "properties": {
+                "applicant_id": {
+                    "type": "string"
+                },
+                "content_type": {
+                    "description": "Of the uploaded image",
+                    "type": "string",
+                    "example": "image/jpeg"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "height": {
+                    "type": "integer",
+                    "example": 1600
+                },
+                "size": {
+                    "description": "Of the uploaded image, in bytes",
+                    "type": "integer"
+                },
+                "uploaded_by": {
+                    "type": "string"
+                },
+                "width": {
+                    "description": "Of the uploaded image, in pixels",
+                    "type": "integer",
+                    "example": 1200
+                }
+            }
+        },
+        "models.ApplicantPrefill": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "$ref": "#/definitions/models.Address"
+                },
+                "date_of_birth": {
+                    "type": "string",
+                    "format": "date",
+                    "example": "1990-07-01"
+                },
+                "existing_applicant_id": {
+                    "description": "Set when the person is already an applicant",
+                    "type": "string"
+                },
+                "household": {
+                    "description": "People the registry links to the person, to confirm before adding",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.HouseholdHint"
+                    }
+                },
+                "identity_number": {
+                    "type": "string",
+                    "example": "S1234567D"
+                },
+                "marital_status": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "sex": {
+                    "type": "string"
+                },
+                "source": {
+                    "description": "The registry the data came from",
+                    "type": "string",
+                    "example": "myinfo"
+                }
+            }
+        },
+        "models.ApplicantResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "$ref": "#/definitions/models.Address"
+                },
+                "anonymized_at": {
+                    "description": "Set once personal data has been irreversibly replaced",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "custom_fields": {
+                    "description": "Values of the custom fields defined in this deployment, by key",
+                    "type": "object"
+                },
+                "date_of_birth": {
+                    "type": "string",
+                    "format": "date",
+                    "example": "1990-07-01"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "email_opt_out": {
+                    "description": "Set when the applicant declines email notifications",
+                    "type": "boolean"
+                },
+                "employment_status": {
+                    "type": "string"
+                },
+                "household": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.HouseholdMember"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "identity_number": {
+                    "description": "NRIC or FIN, unique; encrypted at rest",
+                    "type": "string",
+                    "example": "S1234567D"
+                },
+                "marital_status": {
+                    "type": "string"
+                },
+                "monthly_income": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "description": "Stored without spaces or hyphens",
+                    "type": "string",
+                    "example": "+6591234567"
+                },
+                "sex": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Incremented on every update, for optimistic locking",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Application": {
+            "type": "object",
+            "properties": {
+                "applicant": {
+                    "$ref": "#/definitions/models.Applicant"
+                },
+                "applicant_id": {
+                    "type": "string"
+                },
+                "application_date": {
+                    "description": "The day it was made; created_at has the time",
+                    "type": "string",
+                    "format": "date",
+                    "example": "2026-10-14"
+                },
+                "assigned_at": {
+                    "type": "string"
+                },
+                "assigned_to": {
+                    "description": "Set while the application is assigned to a user for review",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "decided_by": {
+                    "description": "Set when the application is approved or rejected",
+                    "type": "string"
+                },
+                "decision_date": {
+                    "type": "string"
+                },
+                "decision_reason": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "previous_application_id": {
+                    "description": "Set by the server when the applicant had an application for the scheme\nrejected before, to the latest such application, which this one\nresubmits",
+                    "type": "string"
+                },
+                "recommended_benefit_amount": {
+                    "description": "Approvals only",
+                    "type": "string",
+                    "example": "500.00"
+                },
+                "scheme": {
+                    "$ref": "#/definitions/models.Scheme"
+                },
+                "scheme_id": {
+                    "type": "string"
+                },
+                "scheme_version": {
+                    "description": "Version of the scheme's terms the application was assessed under",
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Incremented on every update, for optimistic locking",
+                    "type": "integer"
+                },
+                "withdrawal_reason": {
+                    "type": "string"
+                },
+                "withdrawn_at": {
+                    "description": "Set when the application is withdrawn",
+                    "type": "string"
+                },
+                "withdrawn_by": {
+                    "description": "ID of the staff user who withdrew it; empty if the applicant did",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ApplicationChecklistItem": {
+            "type": "object",
+            "properties": {
+                "application_id": {
+                    "type": "string"
+                },
+                "checklist_item_id": {
+                    "description": "The scheme's item this was copied from, unless it has since been deleted",
+                    "type": "string"
+                },
+                "completed_at": {
+                    "type": "string"
+                },
+                "completed_by": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Payslips for the last 3 months"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "document",
+                        "interview",
+                        "other"
+                    ],
+                    "example": "document"
+                },
+                "mandatory": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Verify payslips"
+                },
+                "note": {
+                    "type": "string",
+                    "example": "Seen the original payslips"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ApplicationEvent": {
+            "type": "object",
+            "properties": {
+                "actor_id": {
+                    "type": "string"
+                },
+                "actor_username": {
+                    "type": "string"
+                },
+                "application_id": {
+                    "type": "string"
+                },
+                "data": {
+                    "description": "The fields the event set, or the whole application for create, anonymize and backfill events; null once purged or anonymized",
+                    "type": "object"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "occurred_at": {
+                    "type": "string"
+                },
+                "sequence": {
+                    "description": "Increases in the order events happened, across applications",
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "create",
+                        "update",
+                        "delete",
+                        "restore",
+                        "approve",
+                        "reject",
+                        "withdraw",
+                        "assign",
+                        "unassign",
+                        "merge",
+                        "purge",
+                        "anonymize",
+                        "backfill"
+                    ],
+                    "example": "approve"
+                },
+                "version": {
+                    "description": "1 for the application's first event, counting up",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ApplicationRequest": {
+            "type": "object",
+            "properties": {
+                "applicant_id": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "scheme_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ApplicationTracking": {
+            "type": "object",
+            "properties": {
+                "label": {
+                    "type": "string",
+                    "example": "Pending"
+                },
+                "next_steps": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "approved",
+                        "rejected",
+                        "withdrawn"
+                    ],
+                    "example": "pending"
+                }
+            }
+        },
+        "models.ApplicationsSummary": {
+            "type": "object",
+            "properties": {
+                "average_decision_days": {
+                    "description": "Mean days from application to decision; null if none decided",
+                    "type": "number",
+                    "example": 4.5
+                },
+                "by_month": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MonthSummary"
+                    }
+                },
+                "by_scheme": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SchemeSummary"
+                    }
+                },
+                "by_status": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    },
+                    "example": {
+                        "approved": 5,
+                        "pending": 3,
+                        "rejected": 2
+                    }
+                },
+                "decided": {
+                    "description": "Applications with a decision date",
+                    "type": "integer"
+                },
+                "recommended_benefits": {
+                    "description": "Sum of recommended benefit amounts of approved applications",
+                    "type": "string",
+                    "example": "1500.00"
+                },
+                "scheme_benefits": {
+                    "description": "Sum over approved applications of the projected value of their scheme's current benefits",
+                    "type": "string",
+                    "example": "2400.00"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AssignRequest": {
+            "type": "object",
+            "properties": {
+                "user_id": {
+                    "description": "The authenticated user if omitted",
+                    "type": "string"
+                }
+            }
+        },
+        "models.AuditLog": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "create",
+                        "update",
+                        "delete",
+                        "restore",
+                        "approve",
+                        "reject",
+                        "withdraw",
+                        "merge",
+                        "purge",
+                        "anonymize",
+                        "assign",
+                        "unassign",
+                        "publish",
+                        "archive"
+                    ],
+                    "example": "update"
+                },
+                "actor_id": {
+                    "type": "string"
+                },
+                "actor_username": {
+                    "type": "string"
+                },
+                "after": {
+                    "type": "object"
+                },
+                "before": {
+                    "type": "object"
+                },
+                "changes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.FieldChange"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "entity_id": {
+                    "type": "string"
+                },
+                "entity_type": {
+                    "type": "string",
+                    "example": "applicant"
+                },
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BatchEligibilityRequest": {
+            "type": "object",
+            "properties": {
+                "applicant_ids": {
+                    "description": "Every applicant if empty",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "as_of": {
+                    "description": "Now if omitted",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Benefit": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Paid each time",
+                    "type": "string",
+                    "example": "300.00"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "ISO 4217 code; defaults to SGD",
+                    "type": "string",
+                    "example": "SGD"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "duration_months": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "formula": {
+                    "description": "Scales the amount with the household, if set",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.BenefitFormula"
+                        }
+                    ]
+                },
+                "frequency": {
+                    "description": "How often the amount is paid, one of BenefitFrequencies, defaulting to\none_off, and for recurring benefits the number of months they are paid\nover",
+                    "type": "string",
+                    "enum": [
+                        "one_off",
+                        "monthly",
+                        "quarterly"
+                    ],
+                    "example": "monthly"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scheme_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BenefitEntitlement": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Paid each time",
+                    "type": "string",
+                    "example": "500.00"
+                },
+                "base_amount": {
+                    "type": "string",
+                    "example": "300.00"
+                },
+                "benefit_id": {
+                    "type": "string"
+                },
+                "children": {
+                    "description": "Children counted by the formula",
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "SGD"
+                },
+                "duration_months": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "frequency": {
+                    "type": "string",
+                    "enum": [
+                        "one_off",
+                        "monthly",
+                        "quarterly"
+                    ],
+                    "example": "monthly"
+                },
+                "household_members": {
+                    "description": "Household members counted by the formula",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "projected_value": {
+                    "type": "string",
+                    "example": "6000.00"
+                }
+            }
+        },
+        "models.BenefitEstimate": {
+            "type": "object",
+            "properties": {
+                "applicant_id": {
+                    "type": "string"
+                },
+                "as_of": {
+                    "type": "string"
+                },
+                "benefits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BenefitEntitlement"
+                    }
+                },
+                "eligible": {
+                    "description": "Whether the applicant is eligible for the scheme at as_of",
+                    "type": "boolean"
+                },
+                "projected_value": {
+                    "type": "string",
+                    "example": "6000.00"
+                },
+                "scheme_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BenefitFormula": {
+            "type": "object",
+            "properties": {
+                "child_school_levels": {
+                    "description": "Children are counted only at these school levels, as derived for\neligibility; every child is counted if empty",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "primary",
+                        "secondary"
+                    ]
+                },
+                "max_amount": {
+                    "type": "string",
+                    "example": "600.00"
+                },
+                "per_child_amount": {
+                    "type": "string",
+                    "example": "100.00"
+                },
+                "per_member_amount": {
+                    "description": "For each household member, not counting the applicant",
+                    "type": "string",
+                    "example": "0.00"
+                }
+            }
+        },
+        "models.CaseNote": {
+            "type": "object",
+            "properties": {
+                "applicant_id": {
+                    "type": "string"
+                },
+                "author_id": {
+                    "type": "string"
+                },
+                "author_username": {
+                    "type": "string"
+                },
+                "body": {
+                    "type": "string",
+                    "example": "Called to remind applicant to submit payslips"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "phone-call",
+                        "follow-up"
+                    ]
+                }
+            }
+        },
+        "models.CaseNoteRequest": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "example": "Called to remind applicant to submit payslips"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "phone-call",
+                        "follow-up"
+                    ]
+                }
+            }
+        },
+        "models.CaseworkerWorkload": {
+            "type": "object",
+            "properties": {
+                "approved": {
+                    "description": "Applications the user approved",
+                    "type": "integer"
+                },
+                "pending": {
+                    "description": "Pending applications assigned to the user",
+                    "type": "integer"
+                },
+                "rejected": {
+                    "description": "Applications the user rejected",
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "admin",
+                        "caseworker"
+                    ],
+                    "example": "caseworker"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ChecklistItem": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Payslips for the last 3 months"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "Defaults to other",
+                    "type": "string",
+                    "enum": [
+                        "document",
+                        "interview",
+                        "other"
+                    ],
+                    "example": "document"
+                },
+                "mandatory": {
+                    "description": "Must be completed before the application is approved",
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Verify payslips"
+                },
+                "position": {
+                    "description": "Items are listed by position, then name",
+                    "type": "integer"
+                },
+                "scheme_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ChecklistTickRequest": {
+            "type": "object",
+            "properties": {
+                "completed": {
+                    "type": "boolean"
+                },
+                "note": {
+                    "description": "Kept with the completed item; cleared when it is unticked",
+                    "type": "string",
+                    "example": "Seen the original payslips"
+                }
+            }
+        },
+        "models.ChildCriteria": {
+            "type": "object",
+            "properties": {
+                "school_level": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Comment": {
+            "type": "object",
+            "properties": {
+                "application_id": {
+                    "type": "string"
+                },
+                "author_id": {
+                    "type": "string"
+                },
+                "author_username": {
+                    "type": "string"
+                },
+                "body": {
+                    "description": "Empty once deleted",
+                    "type": "string",
+                    "example": "@caseworker can you check the payslip dates?"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "Deleted comments are listed only while they have replies",
+                    "type": "string"
+                },
+                "edited_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "mentions": {
+                    "description": "Usernames of the users mentioned",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "caseworker"
+                    ]
+                },
+                "parent_id": {
+                    "description": "The comment starting the thread, if this is a reply",
+                    "type": "string"
+                },
+                "replies": {
+                    "description": "Oldest first; set on the comments starting a thread when listed",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Comment"
+                    }
+                }
+            }
+        },
+        "models.CommentEditRequest": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "example": "@caseworker can you check the payslip dates?"
+                }
+            }
+        },
+        "models.CommentRequest": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "example": "@caseworker can you check the payslip dates?"
+                },
+                "parent_id": {
+                    "description": "The comment to reply to",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Consent": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "description": "Whether the consent is in force: given, and neither withdrawn nor expired",
+                    "type": "boolean"
+                },
+                "applicant_id": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "Never, if not set",
+                    "type": "string"
+                },
+                "granted_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "purpose": {
+                    "type": "string",
+                    "enum": [
+                        "data_sharing",
+                        "contact_email",
+                        "contact_phone",
+                        "contact_post",
+                        "income_verification"
+                    ],
+                    "example": "data_sharing"
+                },
+                "recorded_by": {
+                    "type": "string"
+                },
+                "reference": {
+                    "description": "Where the consent was given",
+                    "type": "string",
+                    "example": "Form SW-12 #4471"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "withdrawn_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ConsentRequest": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "description": "Never, if not set",
+                    "type": "string"
+                },
+                "granted_at": {
+                    "description": "Defaults to now",
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string",
+                    "example": "Form SW-12 #4471"
+                }
+            }
+        },
+        "models.Criteria": {
+            "type": "object",
+            "properties": {
+                "any_of": {
+                    "description": "Alternative groups of criteria, at least one of which must also match",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Criteria"
+                    }
+                },
+                "employment_status": {
+                    "type": "string"
+                },
+                "has_children": {
+                    "$ref": "#/definitions/models.ChildCriteria"
+                },
+                "marital_status": {
+                    "type": "string"
+                },
+                "max_age": {
+                    "description": "Applicant's age in whole years, inclusive",
+                    "type": "integer"
+                },
+                "max_household_income": {
+                    "description": "Monthly, applicant and household combined",
+                    "type": "number"
+                },
+                "max_per_capita_income": {
+                    "description": "Monthly household income per person",
+                    "type": "number"
+                },
+                "min_age": {
+                    "description": "Applicant's age in whole years, inclusive",
+                    "type": "integer"
+                },
+                "rules": {
+                    "description": "Custom rules, combined with the fields above using AND",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Rule"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.CustomField": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "key": {
+                    "description": "Lowercase letters, digits and underscores; cannot be changed",
+                    "type": "string",
+                    "example": "housing_type"
+                },
+                "label": {
+                    "type": "string",
+                    "example": "Housing type"
+                },
+                "max": {
+                    "description": "Largest value of number fields",
+                    "type": "number"
+                },
+                "max_length": {
+                    "description": "Longest value of string fields, in characters",
+                    "type": "integer"
+                },
+                "min": {
+                    "description": "Smallest value of number fields",
+                    "type": "number"
+                },
+                "options": {
+                    "description": "Allowed values of enum fields",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "hdb_1_2_room",
+                        "hdb_3_room",
+                        "hdb_4_room",
+                        "private"
+                    ]
+                },
+                "required": {
+                    "description": "Applicants cannot be saved without a value",
+                    "type": "boolean"
+                },
+                "type": {
+                    "description": "Cannot be changed",
+                    "type": "string",
+                    "enum": [
+                        "string",
+                        "number",
+                        "boolean",
+                        "enum"
+                    ],
+                    "example": "enum"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DecisionRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                },
+                "recommended_benefit_amount": {
+                    "description": "Approvals only",
+                    "type": "string",
+                    "example": "500.00"
+                }
+            }
+        },
+        "models.Document": {
+            "type": "object",
+            "properties": {
+                "application_id": {
+                    "type": "string"
+                },
+                "content_type": {
+                    "type": "string",
+                    "example": "application/pdf"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "filename": {
+                    "type": "string",
+                    "example": "payslip-2026-09.pdf"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "sha256": {
+                    "description": "Hex-encoded checksum of the content",
+                    "type": "string"
+                },
+                "size": {
+                    "description": "In bytes",
+                    "type": "integer"
+                },
+                "uploaded_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.EligibleSchemesResponse": {
+            "type": "object",
+            "properties": {
+                "applicant_id": {
+                    "type": "string"
+                },
+                "as_of": {
+                    "type": "string"
+                },
+                "schemes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SchemeResponse"
+                    }
+                }
+            }
+        },
+        "models.FeatureFlag": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "applicants.stream"
+                },
+                "roles": {
+                    "description": "Roles the flag is on for when enabled; every role if empty",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "admin",
+                        "caseworker"
+                    ]
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "description": "ID of the admin who last changed the setting",
+                    "type": "string"
+                }
+            }
+        },
+        "models.FeatureFlagRequest": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "description": "Required",
+                    "type": "boolean"
+                },
+                "roles": {
+                    "description": "Roles the flag is on for when enabled; every role if empty",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "admin",
+                        "caseworker"
+                    ]
+                }
+            }
+        },
+        "models.FeatureFlagState": {
+            "type": "object",
+            "properties": {
+                "default": {
+                    "description": "Whether the flag is on when not overridden, as configured",
+                    "type": "boolean"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Serve GET /api/v1/applicants/stream"
+                },
+                "enabled": {
+                    "description": "Whether the flag is on, for the roles listed if any",
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "applicants.stream"
+                },
+                "override": {
+                    "description": "The admin's setting in effect, if any",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.FeatureFlag"
+                        }
+                    ]
+                },
+                "roles": {
+                    "description": "Roles the flag is on for; every role if empty",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "admin",
+                        "caseworker"
+                    ]
+                }
+            }
+        },
+        "models.FieldChange": {
+            "type": "object",
+            "properties": {
+                "new": {},
+                "old": {}
+            }
+        },
+        "models.GeographicArea": {
+            "type": "object",
+            "properties": {
+                "applicants": {
+                    "description": "Distinct applicants making them",
+                    "type": "integer"
+                },
+                "applications": {
+                    "description": "Applications matching the filters",
+                    "type": "integer"
+                },
+                "approved": {
+                    "description": "Of them, those approved",
+                    "type": "integer"
+                },
+                "by_status": {
+                    "description": "Applications by status",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    },
+                    "example": {
+                        "approved": 5,
+                        "pending": 3,
+                        "rejected": 2
+                    }
+                },
+                "code": {
+                    "description": "Postal district, \"01\" to \"28\", or region code",
+                    "type": "string",
+                    "example": "20"
+                },
+                "latitude": {
+                    "type": "number",
+                    "example": 1.362
+                },
+                "longitude": {
+                    "description": "Approximate centre, for plotting",
+                    "type": "number",
+                    "example": 103.846
+                },
+                "name": {
+                    "description": "General locations of the district, or name of the region",
+                    "type": "string",
+                    "example": "Bishan, Ang Mo Kio"
+                },
+                "region": {
+                    "description": "For a district, the planning region covering most of it",
+                    "type": "string",
+                    "example": "north_east"
+                }
+            }
+        },
+        "models.GeographicReport": {
+            "type": "object",
+            "properties": {
+                "areas": {
+                    "description": "Every district or region in order of code, including those without applications",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.GeographicArea"
+                    }
+                },
+                "group_by": {
+                    "type": "string",
+                    "enum": [
+                        "district",
+                        "region"
+                    ],
+                    "example": "district"
+                },
+                "unlocated": {
+                    "description": "Applications by applicants without a known postal district",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HistoryEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "create",
+                        "update",
+                        "delete",
+                        "restore",
+                        "merge",
+                        "anonymize"
+                    ],
+                    "example": "update"
+                },
+                "actor_id": {
+                    "type": "string"
+                },
+                "actor_username": {
+                    "type": "string"
+                },
+                "audit_id": {
+                    "type": "string"
+                },
+                "changed_at": {
+                    "type": "string"
+                },
+                "changes": {
+                    "description": "Empty for deletions",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.FieldChange"
+                    }
+                }
+            }
+        },
+        "models.Household": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "head_applicant_id": {
+                    "description": "The applicant the members' relations are to",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "memberships": {
+                    "description": "Other applicants, present and past, by start date",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.HouseholdMembership"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.HouseholdHint": {
+            "type": "object",
+            "properties": {
+                "date_of_birth": {
+                    "type": "string",
+                    "format": "date",
+                    "example": "2015-03-12"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "relation": {
+                    "description": "One of Relations",
+                    "type": "string",
+                    "example": "daughter"
+                },
+                "sex": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.HouseholdIssue": {
+            "type": "object",
+            "properties": {
+                "check": {
+                    "description": "One of the HouseholdCheck constants",
+                    "type": "string",
+                    "example": "multiple_spouses"
+                },
+                "member_ids": {
+                    "description": "IDs of the members involved",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "members": {
+                    "description": "Fields of the members involved, e.g. household[1]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "message": {
+                    "description": "Human-readable description",
+                    "type": "string",
+                    "example": "household[0] and household[2] are both a spouse"
+                },
+                "other_applicant_id": {
+                    "description": "For other_household, the applicant whose household or record the member is also in",
+                    "type": "string"
+                }
+            }
+        },
+        "models.HouseholdMember": {
+            "type": "object",
+            "properties": {
+                "applicant_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "date_of_birth": {
+                    "type": "string",
+                    "format": "date",
+                    "example": "1990-07-01"
+                },
+                "employment_status": {
+                    "type": "string"
+                },
+                "household_id": {
+                    "description": "Read-only; set on members of a shared household",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "identity_number": {
+                    "description": "NRIC or FIN, if declared; encrypted at rest",
+                    "type": "string",
+                    "example": "T0512345C"
+                },
+                "member_applicant_id": {
+                    "description": "Read-only; set when the member is another applicant in the shared household",
+                    "type": "string"
+                },
+                "monthly_income": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "relation": {
+                    "description": "One of Relations",
+                    "type": "string",
+                    "example": "daughter"
+                },
+                "school_level": {
+                    "description": "As declared; see SchoolLevels",
+                    "type": "string",
+                    "example": "primary"
+                },
+                "sex": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.HouseholdMembership": {
+            "type": "object",
+            "properties": {
+                "applicant_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "description": "No longer a member from this day, if set",
+                    "type": "string"
+                },
+                "household_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "relation": {
+                    "description": "The applicant's relation to the head; one of Relations",
+                    "type": "string",
+                    "example": "spouse"
+                },
+                "start_date": {
+                    "description": "A member from this day",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.HouseholdMembershipRequest": {
+            "type": "object",
+            "properties": {
+                "applicant_id": {
+                    "description": "Required when adding; cannot be changed",
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "relation": {
+                    "type": "string",
+                    "example": "spouse"
+                },
+                "start_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.HouseholdRequest": {
+            "type": "object",
+            "properties": {
+                "head_applicant_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.HouseholdRule": {
+            "type": "object",
+            "properties": {
+                "max_count": {
+                    "type": "integer"
+                },
+                "min_count": {
+                    "type": "integer"
+                },
+                "where": {
+                    "$ref": "#/definitions/models.Rule"
+                }
+            }
+        },
+        "models.HouseholdValidation": {
+            "type": "object",
+            "properties": {
+                "applicant_id": {
+                    "type": "string"
+                },
+                "issues": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.HouseholdIssue"
+                    }
+                },
+                "valid": {
+                    "description": "Whether no issues were found",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.ImportApplicantsRequest": {
+            "type": "object",
+            "properties": {
+                "applicants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Applicant"
+                    }
+                }
+            }
+        },
+        "models.IncomeRecord": {
+            "type": "object",
+            "properties": {
+                "applicant_id": {
+                    "type": "string"
+                },
+                "assessment_year": {
+                    "description": "The year the income was assessed for, if the source gives one",
+                    "type": "integer",
+                    "example": 2025
+                },
+                "id": {
+                    "type": "string"
+                },
+                "monthly_income": {
+                    "type": "number",
+                    "example": 3500
+                },
+                "previous_monthly_income": {
+                    "description": "The applicant's monthly_income before the retrieval",
+                    "type": "number",
+                    "example": 2800
+                },
+                "reference": {
+                    "type": "string",
+                    "example": "NOA-2025-000123"
+                },
+                "retrieved_at": {
+                    "type": "string"
+                },
+                "retrieved_by": {
+                    "type": "string"
+                },
+                "source": {
+                    "description": "The provider the income came from",
+                    "type": "string",
+                    "example": "iras"
+                }
+            }
+        },
+        "models.Job": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "result": {
+                    "description": "Set once the job has succeeded",
+                    "type": "object"
+                },
+                "run_at": {
+                    "description": "When a queued job is next due",
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "queued",
+                        "running",
+                        "succeeded",
+                        "failed"
+                    ],
+                    "example": "queued"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "applicants.import"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Label": {
+            "type": "object",
+            "properties": {
+                "label": {
+                    "type": "string",
+                    "example": "Approved"
+                },
+                "value": {
+                    "type": "string",
+                    "example": "approved"
+                }
+            }
+        },
+        "models.LoginRequest": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "token_type": {
+                    "type": "string",
+                    "example": "Bearer"
+                },
+                "user": {
+                    "$ref": "#/definitions/models.User"
+                }
+            }
+        },
+        "models.MergeRequest": {
+            "type": "object",
+            "properties": {
+                "policy": {
+                    "description": "How to resolve differing fields; default prefer_target",
+                    "type": "string",
+                    "enum": [
+                        "prefer_target",
+                        "prefer_source"
+                    ]
+                },
+                "source_id": {
+                    "description": "The duplicate, soft-deleted by the merge",
+                    "type": "string"
+                }
+            }
+        },
+        "models.MonthSummary": {
+            "type": "object",
+            "properties": {
+                "by_status": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    },
+                    "example": {
+                        "approved": 5,
+                        "pending": 3,
+                        "rejected": 2
+                    }
+                },
+                "month": {
+                    "type": "string",
+                    "example": "2026-10"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PortalCodeRequest": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "description": "Sent to the applicant's email address or phone",
+                    "type": "string",
+                    "enum": [
+                        "email",
+                        "sms"
+                    ],
+                    "example": "email"
+                },
+                "identity_number": {
+                    "type": "string",
+                    "example": "S1234567D"
+                }
+            }
+        },
+        "models.PortalLoginRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                },
+                "identity_number": {
+                    "type": "string",
+                    "example": "S1234567D"
+                }
+            }
+        },
+        "models.PortalLoginResponse": {
+            "type": "object",
+            "properties": {
+                "applicant_id": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "token_type": {
+                    "type": "string",
+                    "example": "Bearer"
+                }
+            }
+        },
+        "models.Referral": {
+            "type": "object",
+            "properties": {
+                "applicant_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "outcome": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "accepted",
+                        "declined",
+                        "completed"
+                    ],
+                    "example": "pending"
+                },
+                "outcome_at": {
+                    "description": "When the outcome was last set to other than pending",
+                    "type": "string"
+                },
+                "outcome_notes": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Retrenched, looking to retrain"
+                },
+                "referred_by": {
+                    "description": "The agency or officer making the referral",
+                    "type": "string",
+                    "example": "Family Service Centre (Ang Mo Kio)"
+                },
+                "referred_to": {
+                    "description": "The agency referred to",
+                    "type": "string",
+                    "example": "Workforce Singapore"
+                },
+                "scheme": {
+                    "description": "The agency's scheme or service, if any",
+                    "type": "string",
+                    "example": "Career Conversion Programme"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReferralRequest": {
+            "type": "object",
+            "properties": {
+                "outcome": {
+                    "description": "Defaults to pending",
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "accepted",
+                        "declined",
+                        "completed"
+                    ],
+                    "example": "pending"
+                },
+                "outcome_notes": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Retrenched, looking to retrain"
+                },
+                "referred_by": {
+                    "description": "Defaults to the authenticated user's username",
+                    "type": "string",
+                    "example": "Family Service Centre (Ang Mo Kio)"
+                },
+                "referred_to": {
+                    "type": "string",
+                    "example": "Workforce Singapore"
+                },
+                "scheme": {
+                    "type": "string",
+                    "example": "Career Conversion Programme"
+                }
+            }
+        },
+        "models.ReviewFlag": {
+            "type": "object",
+            "properties": {
+                "application_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Applicant no longer meets the criteria of version 2 of the scheme"
+                },
+                "resolved_at": {
+                    "description": "Set once the applicant is found eligible again",
+                    "type": "string"
+                },
+                "scheme_version": {
+                    "description": "The scheme terms assessed against, if any were in effect",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Rule": {
+            "type": "object",
+            "properties": {
+                "all": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Rule"
+                    }
+                },
+                "any": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Rule"
+                    }
+                },
+                "field": {
+                    "type": "string"
+                },
+                "household": {
+                    "$ref": "#/definitions/models.HouseholdRule"
+                },
+                "op": {
+                    "type": "string"
+                },
+                "value": {}
+            }
+        },
+        "models.Scheme": {
+            "type": "object",
+            "properties": {
+                "approved_amount": {
+                    "description": "Set by the server",
+                    "type": "string",
+                    "example": "600.00"
+                },
+                "approved_count": {
+                    "description": "Set by the server",
+                    "type": "integer"
+                },
+                "benefits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Benefit"
+                    }
+                },
+                "budget": {
+                    "type": "string",
+                    "example": "10000.00"
+                },
+                "checklist": {
+                    "description": "Review checklist copied to each new application",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ChecklistItem"
+                    }
+                },
+                "close_date": {
+                    "type": "string"
+                },
+                "cooling_off_days": {
+                    "description": "CoolingOffDays is how long an applicant must wait after a rejection\nbefore applying to the scheme again, counted from the decision. Unset\nor zero lets them reapply straight away.",
+                    "type": "integer",
+                    "example": 90
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "criteria": {
+                    "$ref": "#/definitions/models.Criteria"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "effective_from": {
+                    "description": "EffectiveFrom is when the name, description and criteria sent in a\ncreate or update take effect, defaulting to now. It is not set on reads;\nsee SchemeVersion for the effective dates of stored terms.",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "max_applications": {
+                    "description": "Optional limits on the applications a scheme approves, checked on every\napproval against the running totals of those already approved. An\napproval commits its recommended benefit amount, or the projected value\nof the applicant's entitlement to the scheme's benefits if none is\nrecommended. Amounts are in the currency of the scheme's benefits.",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "open_date": {
+                    "description": "The scheme accepts applications while it is published, active and\nwithin its window; an unset date leaves that end of the window open.\nSee OpenAt. The status is changed by publishing and archiving the\nscheme, not by updates.",
+                    "type": "string"
+                },
+                "remaining_applications": {
+                    "description": "Set by the server if max_applications is",
+                    "type": "integer"
+                },
+                "remaining_budget": {
+                    "description": "Set by the server if budget is",
+                    "type": "string",
+                    "example": "9400.00"
+                },
+                "status": {
+                    "description": "Set by the server",
+                    "type": "string",
+                    "enum": [
+                        "draft",
+                        "published",
+                        "archived"
+                    ],
+                    "example": "draft"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Incremented on every update, for optimistic locking",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SchemeCoverage": {
+            "type": "object",
+            "properties": {
+                "applicants": {
+                    "description": "Registered applicants assessed",
+                    "type": "integer"
+                },
+                "applied_applicants": {
+                    "description": "Applicants with an application for the scheme, whatever its status",
+                    "type": "integer"
+                },
+                "as_of": {
+                    "type": "string"
+                },
+                "eligible_applicants": {
+                    "description": "Applicants meeting the scheme's criteria at as_of",
+                    "type": "integer"
+                },
+                "eligible_applied": {
+                    "description": "Eligible applicants who have applied",
+                    "type": "integer"
+                },
+                "eligible_not_applied": {
+                    "description": "Eligible applicants who have not applied",
+                    "type": "integer"
+                },
+                "scheme_id": {
+                    "type": "string"
+                },
+                "scheme_name": {
+                    "type": "string"
+                },
+                "scheme_open": {
+                    "description": "Whether the scheme accepts applications at as_of; no applicant is eligible for a closed scheme",
+                    "type": "boolean"
+                },
+                "uptake_rate": {
+                    "description": "eligible_applied / eligible_applicants; null if none are eligible",
+                    "type": "number",
+                    "example": 0.42
+                }
+            }
+        },
+        "models.SchemeReport": {
+            "type": "object",
+            "properties": {
+                "average_decision_days": {
+                    "description": "Mean days from application to decision; null if none decided",
+                    "type": "number",
+                    "example": 4.5
+                },
+                "by_month": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MonthSummary"
+                    }
+                },
+                "by_status": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    },
+                    "example": {
+                        "approved": 5,
+                        "pending": 3,
+                        "rejected": 2
+                    }
+                },
+                "decided": {
+                    "description": "Applications with a decision date",
+                    "type": "integer"
+                },
+                "recommended_benefits": {
+                    "description": "Sum of recommended benefit amounts of approved applications",
+                    "type": "string",
+                    "example": "1500.00"
+                },
+                "scheme_benefits": {
+                    "description": "Sum over approved applications of the projected value of their scheme's current benefits",
+                    "type": "string",
+                    "example": "2400.00"
+                },
+                "scheme_id": {
+                    "type": "string"
+                },
+                "scheme_name": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SchemeResponse": {
+            "type": "object",
+            "properties": {
+                "approved_amount": {
+                    "description": "Set by the server",
+                    "type": "string",
+                    "example": "600.00"
+                },
+                "approved_count": {
+                    "description": "Set by the server",
+                    "type": "integer"
+                },
+                "benefits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Benefit"
+                    }
+                },
+                "budget": {
+                    "type": "string",
+                    "example": "10000.00"
+                },
+                "checklist": {
+                    "description": "Review checklist copied to each new application",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ChecklistItem"
+                    }
+                },
+                "close_date": {
+                    "type": "string"
+                },
+                "cooling_off_days": {
+                    "description": "CoolingOffDays is how long an applicant must wait after a rejection\nbefore applying to the scheme again, counted from the decision. Unset\nor zero lets them reapply straight away.",
+                    "type": "integer",
+                    "example": 90
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "criteria": {
+                    "$ref": "#/definitions/models.Criteria"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "effective_from": {
+                    "description": "EffectiveFrom is when the name, description and criteria sent in a\ncreate or update take effect, defaulting to now. It is not set on reads;\nsee SchemeVersion for the effective dates of stored terms.",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "max_applications": {
+                    "description": "Optional limits on the applications a scheme approves, checked on every\napproval against the running totals of those already approved. An\napproval commits its recommended benefit amount, or the projected value\nof the applicant's entitlement to the scheme's benefits if none is\nrecommended. Amounts are in the currency of the scheme's benefits.",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "open_date": {
+                    "description": "The scheme accepts applications while it is published, active and\nwithin its window; an unset date leaves that end of the window open.\nSee OpenAt. The status is changed by publishing and archiving the\nscheme, not by updates.",
+                    "type": "string"
+                },
+                "projected_value": {
+                    "description": "Set when written, from the benefits",
+                    "type": "string",
+                    "example": "7200.00"
+                },
+                "remaining_applications": {
+                    "description": "Set by the server if max_applications is",
+                    "type": "integer"
+                },
+                "remaining_budget": {
+                    "description": "Set by the server if budget is",
+                    "type": "string",
+                    "example": "9400.00"
+                },
+                "status": {
+                    "description": "Set by the server",
+                    "type": "string",
+                    "enum": [
+                        "draft",
+                        "published",
+                        "archived"
+                    ],
+                    "example": "draft"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Incremented on every update, for optimistic locking",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SchemeSummary": {
+            "type": "object",
+            "properties": {
+                "average_decision_days": {
+                    "description": "Mean days from application to decision; null if none decided",
+                    "type": "number",
+                    "example": 4.5
+                },
+                "by_status": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    },
+                    "example": {
+                        "approved": 5,
+                        "pending": 3,
+                        "rejected": 2
+                    }
+                },
+                "decided": {
+                    "description": "Applications with a decision date",
+                    "type": "integer"
+                },
+                "recommended_benefits": {
+                    "description": "Sum of recommended benefit amounts of approved applications",
+                    "type": "string",
+                    "example": "1500.00"
+                },
+                "scheme_benefits": {
+                    "description": "Sum over approved applications of the projected value of their scheme's current benefits",
+                    "type": "string",
+                    "example": "2400.00"
+                },
+                "scheme_id": {
+                    "type": "string"
+                },
+                "scheme_name": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SchemeTranslation": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string",
+                    "enum": [
+                        "zh",
+                        "ms",
+                        "ta"
+                    ],
+                    "example": "zh"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scheme_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SchemeTranslationRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "乐龄援助计划"
+                }
+            }
+        },
+        "models.SchemeVersion": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "criteria": {
+                    "$ref": "#/definitions/models.Criteria"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "effective_from": {
+                    "type": "string"
+                },
+                "effective_to": {
+                    "description": "Unset for the latest version",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scheme_id": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "The scheme's version when the terms were saved",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SearchResult": {
+            "type": "object",
+            "properties": {
+                "applicant_id": {
+                    "description": "For household members and applications",
+                    "type": "string"
+                },
+                "highlights": {
+                    "description": "Matched fields, HTML-escaped with matches in \u003cmark\u003e tags",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
                     }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "scheme_id": {
+                    "description": "For applications",
+                    "type": "string"
+                },
+                "score": {
+                    "description": "Number of search terms matched",
+                    "type": "integer"
+                },
+                "title": {
+                    "description": "The entity's name; for applications, the applicant's",
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "applicant",
+                        "household_member",
+                        "scheme",
+                        "application"
+                    ],
+                    "example": "applicant"
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.Applicant": {
+        },
+        "models.SharedHousehold": {
+            "type": "object",
+            "properties": {
+                "applicant_id": {
+                    "type": "string"
+                },
+                "as_of": {
+                    "type": "string"
+                },
+                "household": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.HouseholdMember"
+                    }
+                },
+                "household_id": {
+                    "description": "Empty when the applicant's own household members are used",
+                    "type": "string"
+                }
+            }
+        },
+        "models.SubmissionAssessment": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SubmissionCheck"
+                    }
+                },
+                "previous_application_id": {
+                    "description": "The applicant's latest rejected application for the scheme, which the application would resubmit",
+                    "type": "string"
+                },
+                "scheme_version": {
+                    "description": "Version of the scheme's terms the application would be assessed under",
+                    "type": "integer"
+                },
+                "valid": {
+                    "description": "Whether every check passed",
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.SubmissionCheck": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "description": "Why the check failed or was skipped",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "enum": [
+                        "applicant",
+                        "scheme",
+                        "scheme_open",
+                        "eligibility",
+                        "duplicate",
+                        "cooling_off"
+                    ],
+                    "example": "eligibility"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "passed",
+                        "failed",
+                        "skipped"
+                    ],
+                    "example": "passed"
+                }
+            }
+        },
+        "models.SwaggerApplicantProfile": {
+            "description": "Everything known about an applicant: household, applications with their schemes and documents, eligible schemes, case notes, referrals and consents",
+            "type": "object",
+            "properties": {
+                "applicant": {
+                    "$ref": "#/definitions/models.ApplicantResponse"
+                },
+                "applications": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SwaggerProfileApplication"
+                    }
+                },
+                "as_of": {
+                    "type": "string"
+                },
+                "case_notes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CaseNote"
+                    }
+                },
+                "consents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Consent"
+                    }
+                },
+                "eligible_schemes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SchemeResponse"
+                    }
+                },
+                "referrals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Referral"
+                    }
+                }
+            }
+        },
+        "models.SwaggerApplication": {
+            "description": "Application for a financial assistance scheme",
             "type": "object",
             "properties": {
+                "applicant": {
+                    "$ref": "#/definitions/models.Applicant"
+                },
+                "applicant_id": {
+                    "type": "string",
+                    "example": "01913b7a-4493-74b2-93f8-e684c4ca935c"
+                },
+                "application_date": {
+                    "description": "The day it was made; created_at has the time",
+                    "type": "string",
+                    "format": "date",
+                    "example": "2026-10-14"
+                },
+                "assigned_at": {
+                    "type": "string"
+                },
+                "assigned_to": {
+                    "type": "string",
+                    "example": "01913b90-1a2b-7c3d-8e4f-5a6b7c8d9e0f"
+                },
                 "created_at": {
                     "type": "string"
                 },
-                "date_of_birth": {
+                "decided_by": {
+                    "type": "string",
+                    "example": "01913b90-1a2b-7c3d-8e4f-5a6b7c8d9e0f"
+                },
+                "decision_date": {
+                    "type": "string"
+                },
+                "decision_reason": {
+                    "type": "string",
+                    "example": "Meets all criteria"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "01913b7a-4493-74b2-93f8-e684c4ca935c"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "previous_application_id": {
+                    "type": "string",
+                    "example": "01913b7a-4493-74b2-93f8-e684c4ca935c"
+                },
+                "recommended_benefit_amount": {
+                    "type": "string",
+                    "example": "500.00"
+                },
+                "scheme": {
+                    "$ref": "#/definitions/models.Scheme"
+                },
+                "scheme_id": {
+                    "type": "string",
+                    "example": "01913b89-9a43-7163-8757-01cc254783f3"
+                },
+                "scheme_version": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "approved",
+                        "rejected",
+                        "withdrawn"
+                    ],
+                    "example": "pending"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "withdrawal_reason": {
+                    "type": "string",
+                    "example": "No longer needed; found employment"
+                },
+                "withdrawn_at": {
+                    "type": "string"
+                },
+                "withdrawn_by": {
+                    "type": "string",
+                    "example": "01913b90-1a2b-7c3d-8e4f-5a6b7c8d9e0f"
+                }
+            }
+        },
+        "models.SwaggerApplicationResponse": {
+            "description": "Response containing an application with applicant and scheme details, which lists only include when expanded",
+            "type": "object",
+            "properties": {
+                "applicant": {
+                    "$ref": "#/definitions/models.ApplicantResponse"
+                },
+                "applicant_id": {
+                    "type": "string",
+                    "example": "01913b7a-4493-74b2-93f8-e684c4ca935c"
+                },
+                "application_date": {
+                    "description": "The day it was made; created_at has the time",
+                    "type": "string",
+                    "format": "date",
+                    "example": "2026-10-14"
+                },
+                "assigned_at": {
+                    "type": "string"
+                },
+                "assigned_to": {
+                    "type": "string",
+                    "example": "01913b90-1a2b-7c3d-8e4f-5a6b7c8d9e0f"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "decided_by": {
+                    "type": "string",
+                    "example": "01913b90-1a2b-7c3d-8e4f-5a6b7c8d9e0f"
+                },
+                "decision_date": {
                     "type": "string"
                 },
-                "employment_status": {
+                "decision_reason": {
+                    "type": "string",
+                    "example": "Meets all criteria"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "01913b7a-4493-74b2-93f8-e684c4ca935c"
+                },
+                "notes": {
                     "type": "string"
                 },
-                "household": {
+                "previous_application_id": {
+                    "type": "string",
+                    "example": "01913b7a-4493-74b2-93f8-e684c4ca935c"
+                },
+                "previous_applications": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.HouseholdMember"
+                        "$ref": "#/definitions/models.SwaggerApplication"
                     }
                 },
-                "id": {
-                    "type": "string"
+                "recommended_benefit_amount": {
+                    "type": "string",
+                    "example": "500.00"
                 },
-                "marital_status": {
-                    "type": "string"
+                "scheme": {
+                    "$ref": "#/definitions/models.SchemeResponse"
                 },
-                "name": {
-                    "type": "string"
+                "scheme_id": {
+                    "type": "string",
+                    "example": "01913b89-9a43-7163-8757-01cc254783f3"
                 },
-                "sex": {
-                    "type": "string"
+                "scheme_version": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "approved",
+                        "rejected",
+                        "withdrawn"
+                    ],
+                    "example": "pending"
                 },
                 "updated_at": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "withdrawal_reason": {
+                    "type": "string",
+                    "example": "No longer needed; found employment"
+                },
+                "withdrawn_at": {
+                    "type": "string"
+                },
+                "withdrawn_by": {
+                    "type": "string",
+                    "example": "01913b90-1a2b-7c3d-8e4f-5a6b7c8d9e0f"
                 }
             }
         },
-        "models.ApplicantResponse": {
+        "models.SwaggerProfileApplication": {
+            "description": "Application in an applicant's profile, with its scheme and documents",
             "type": "object",
             "properties": {
+                "applicant": {
+                    "$ref": "#/definitions/models.Applicant"
+                },
+                "applicant_id": {
+                    "type": "string",
+                    "example": "01913b7a-4493-74b2-93f8-e684c4ca935c"
+                },
+                "application_date": {
+                    "description": "The day it was made; created_at has the time",
+                    "type": "string",
+                    "format": "date",
+                    "example": "2026-10-14"
+                },
+                "assigned_at": {
+                    "type": "string"
+                },
+                "assigned_to": {
+                    "type": "string",
+                    "example": "01913b90-1a2b-7c3d-8e4f-5a6b7c8d9e0f"
+                },
                 "created_at": {
                     "type": "string"
                 },
-                "date_of_birth": {
+                "decided_by": {
+                    "type": "string",
+                    "example": "01913b90-1a2b-7c3d-8e4f-5a6b7c8d9e0f"
+                },
+                "decision_date": {
                     "type": "string"
                 },
-                "employment_status": {
+                "decision_reason": {
+                    "type": "string",
+                    "example": "Meets all criteria"
+                },
+                "deleted_at": {
                     "type": "string"
                 },
-                "household": {
+                "documents": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.HouseholdMember"
+                        "$ref": "#/definitions/models.Document"
                     }
                 },
                 "id": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "01913b7a-4493-74b2-93f8-e684c4ca935c"
                 },
-                "marital_status": {
+                "notes": {
                     "type": "string"
                 },
-                "name": {
-                    "type": "string"
+                "previous_application_id": {
+                    "type": "string",
+                    "example": "01913b7a-4493-74b2-93f8-e684c4ca935c"
                 },
-                "sex": {
-                    "type": "string"
+                "recommended_benefit_amount": {
+                    "type": "string",
+                    "example": "500.00"
+                },
+                "scheme": {
+                    "$ref": "#/definitions/models.SchemeResponse"
+                },
+                "scheme_id": {
+                    "type": "string",
+                    "example": "01913b89-9a43-7163-8757-01cc254783f3"
+                },
+                "scheme_version": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "approved",
+                        "rejected",
+                        "withdrawn"
+                    ],
+                    "example": "pending"
                 },
                 "updated_at": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "withdrawal_reason": {
+                    "type": "string",
+                    "example": "No longer needed; found employment"
+                },
+                "withdrawn_at": {
+                    "type": "string"
+                },
+                "withdrawn_by": {
+                    "type": "string",
+                    "example": "01913b90-1a2b-7c3d-8e4f-5a6b7c8d9e0f"
                 }
             }
         },
-        "models.ApplicationRequest": {
+        "models.Task": {
             "type": "object",
             "properties": {
                 "applicant_id": {
                     "type": "string"
                 },
-                "notes": {
+                "application_id": {
                     "type": "string"
                 },
-                "scheme_id": {
+                "assigned_to": {
+                    "description": "User ID",
+                    "type": "string"
+                },
+                "completed_at": {
+                    "description": "When the task was done or cancelled",
                     "type": "string"
-                }
-            }
-        },
-        "models.Benefit": {
-            "type": "object",
-            "properties": {
-                "amount": {
-                    "type": "number"
                 },
                 "created_at": {
                     "type": "string"
                 },
+                "created_by": {
+                    "type": "string"
+                },
                 "description": {
+                    "type": "string",
+                    "example": "Check the March payslip against the declared income"
+                },
+                "due_date": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "name": {
-                    "type": "string"
+                "overdue": {
+                    "description": "Whether the task is open and past its due date",
+                    "type": "boolean"
                 },
-                "scheme_id": {
+                "reminded_at": {
+                    "description": "When the assignee was emailed a reminder",
                     "type": "string"
                 },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "open",
+                        "done",
+                        "cancelled"
+                    ],
+                    "example": "open"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Verify payslip"
+                },
                 "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "models.ChildCriteria": {
-            "type": "object",
-            "properties": {
-                "school_level": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.Criteria": {
+        "models.TaskRequest": {
             "type": "object",
             "properties": {
-                "employment_status": {
+                "applicant_id": {
                     "type": "string"
                 },
-                "has_children": {
-                    "$ref": "#/definitions/models.ChildCriteria"
+                "application_id": {
+                    "type": "string"
                 },
-                "marital_status": {
+                "assigned_to": {
+                    "description": "User ID of a caseworker or admin",
                     "type": "string"
-                }
-            }
-        },
-        "models.EligibleSchemesResponse": {
-            "type": "object",
-            "properties": {
-                "applicant_id": {
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Check the March payslip against the declared income"
+                },
+                "due_date": {
                     "type": "string"
                 },
-                "schemes": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.SchemeResponse"
-                    }
+                "status": {
+                    "description": "Defaults to open",
+                    "type": "string",
+                    "enum": [
+                        "open",
+                        "done",
+                        "cancelled"
+                    ],
+                    "example": "open"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Verify payslip"
                 }
             }
         },
-        "models.HouseholdMember": {
+        "models.TimelineEntry": {
             "type": "object",
             "properties": {
-                "applicant_id": {
+                "actor_id": {
+                    "description": "The user who did it, where recorded",
                     "type": "string"
                 },
-                "created_at": {
+                "actor_username": {
                     "type": "string"
                 },
-                "date_of_birth": {
-                    "type": "string"
+                "application": {
+                    "$ref": "#/definitions/models.Application"
                 },
-                "employment_status": {
+                "application_id": {
+                    "description": "The application the entry is about, if any",
                     "type": "string"
                 },
-                "id": {
-                    "type": "string"
+                "case_note": {
+                    "$ref": "#/definitions/models.CaseNote"
                 },
-                "name": {
-                    "type": "string"
+                "change": {
+                    "$ref": "#/definitions/models.HistoryEntry"
                 },
-                "relation": {
+                "document": {
+                    "$ref": "#/definitions/models.Document"
+                },
+                "occurred_at": {
                     "type": "string"
                 },
-                "sex": {
+                "referral": {
+                    "$ref": "#/definitions/models.Referral"
+                },
+                "status_change": {
+                    "$ref": "#/definitions/models.ApplicationEvent"
+                },
+                "task": {
+                    "$ref": "#/definitions/models.Task"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "application",
+                        "status_change",
+                        "case_note",
+                        "document",
+                        "task",
+                        "referral",
+                        "change"
+                    ],
+                    "example": "status_change"
+                }
+            }
+        },
+        "models.TrackingToken": {
+            "type": "object",
+            "properties": {
+                "application_id": {
                     "type": "string"
                 },
-                "updated_at": {
+                "token": {
                     "type": "string"
                 }
             }
         },
-        "models.Scheme": {
+        "models.User": {
             "type": "object",
             "properties": {
-                "benefits": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Benefit"
-                    }
-                },
                 "created_at": {
                     "type": "string"
                 },
-                "criteria": {
-                    "$ref": "#/definitions/models.Criteria"
-                },
-                "description": {
+                "email": {
+                    "description": "Where task reminders are sent",
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "name": {
+                "role": {
                     "type": "string"
                 },
                 "updated_at": {
                     "type": "string"
+                },
+                "username": {
+                    "type": "string"
                 }
             }
         },
-        "models.SchemeResponse": {
+        "models.Webhook": {
             "type": "object",
             "properties": {
-                "benefits": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Benefit"
-                    }
+                "active": {
+                    "type": "boolean"
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "criteria": {
-                    "$ref": "#/definitions/models.Criteria"
-                },
-                "description": {
-                    "type": "string"
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "application.created",
+                        "application.approved"
+                    ]
                 },
                 "id": {
                     "type": "string"
                 },
-                "name": {
+                "secret": {
+                    "description": "HMAC signing key, only returned when the webhook is created",
                     "type": "string"
                 },
                 "updated_at": {
                     "type": "string"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://crm.example.com/hooks/ocv"
                 }
             }
         },
-        "models.SwaggerApplicationResponse": {
-            "description": "Response containing an application with applicant and scheme details",
+        "models.WebhookDelivery": {
             "type": "object",
             "properties": {
-                "applicant": {
-                    "$ref": "#/definitions/models.ApplicantResponse"
-                },
-                "applicant_id": {
-                    "type": "string",
-                    "example": "01913b7a-4493-74b2-93f8-e684c4ca935c"
+                "attempts": {
+                    "type": "integer"
                 },
-                "application_date": {
+                "created_at": {
                     "type": "string"
                 },
-                "created_at": {
+                "delivered_at": {
                     "type": "string"
                 },
-                "decision_date": {
+                "event": {
+                    "type": "string",
+                    "example": "application.approved"
+                },
+                "event_id": {
+                    "description": "Shared by the deliveries of one event, for deduplication",
                     "type": "string"
                 },
                 "id": {
-                    "type": "string",
-                    "example": "01913b7a-4493-74b2-93f8-e684c4ca935c"
+                    "type": "string"
                 },
-                "notes": {
+                "last_error": {
                     "type": "string"
                 },
-                "scheme": {
-                    "$ref": "#/definitions/models.SchemeResponse"
+                "next_attempt_at": {
+                    "type": "string"
                 },
-                "scheme_id": {
-                    "type": "string",
-                    "example": "01913b89-9a43-7163-8757-01cc254783f3"
+                "payload": {
+                    "type": "object"
                 },
                 "status": {
                     "type": "string",
                     "enum": [
                         "pending",
-                        "approved",
-                        "rejected"
+                        "delivered",
+                        "failed"
                     ],
                     "example": "pending"
                 },
-                "updated_at": {
+                "webhook_id": {
                     "type": "string"
                 }
             }
+        },
+        "models.WithdrawRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "No longer needed; found employment"
+                }
+            }
+        },
+        "models.WorkloadReport": {
+            "type": "object",
+            "properties": {
+                "caseworkers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CaseworkerWorkload"
+                    }
+                },
+                "unassigned": {
+                    "description": "Pending applications assigned to no one",
+                    "type": "integer"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "description": "Bearer token obtained from /api/v1/auth/login, e.g. \"Bearer {token}\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
@@ -1020,7 +11717,7 @@ const docTemplate = `{
 var SwaggerInfo = &swag.Spec{
 	Version:          "",
 	Host:             "localhost:8080",
-	BasePath:         "/api",
+	BasePath:         "/",
 	Schemes:          []string{"http"},
 	Title:            "",
 	Description:      "",